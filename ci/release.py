@@ -309,6 +309,9 @@ def bump_go_version(new_version):
     for p in [
         "benchmarks/go",
         "integration_tests/idl_tests/go",
+        "go/fory/ext/arrow",
+        "go/fory/ext/decimal",
+        "go/fory/ext/protobuf",
     ]:
         _bump_version(p, "go.mod", new_version, _update_go_mod_version)
     _bump_version("go/fory/cmd/fory", "main.go", new_version, _update_go_cli_version)
@@ -441,6 +441,10 @@ case $1 in
       go test -v ./...
       cd "$ROOT/go/fory"
       go test -v ./...
+      for ext in arrow decimal protobuf; do
+        cd "$ROOT/go/fory/ext/$ext"
+        go test -v ./...
+      done
       echo "Executing fory go tests succeeds"
     ;;
     format)
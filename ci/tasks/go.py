@@ -24,4 +24,26 @@ def run():
     logging.info("Executing fory go tests")
     common.cd_project_subdir("go/fory")
     common.exec_cmd("go test -v")
+    run_codegen_only()
+    run_wasm()
     logging.info("Executing fory go tests succeeds")
+
+
+def run_codegen_only():
+    """Run the Go tests that do not need reflection-based struct serializers."""
+    logging.info("Executing fory go codegen-only tests")
+    common.exec_cmd("go vet -tags fory_codegen_only .")
+    common.exec_cmd("go test -tags fory_codegen_only .")
+    logging.info("Executing fory go codegen-only tests succeeds")
+
+
+def run_wasm():
+    """Run Go tests under js/wasm with Node and build them for wasip1."""
+    logging.info("Executing fory go wasm tests")
+    goroot = common.exec_cmd("go env GOROOT").strip()
+    # wasm_exec moved from misc/wasm to lib/wasm in Go 1.24.
+    path = f"$PATH:{goroot}/lib/wasm:{goroot}/misc/wasm"
+    common.exec_cmd(f'PATH="{path}" GOOS=js GOARCH=wasm go test .')
+    common.exec_cmd("GOOS=wasip1 GOARCH=wasm go build ./...")
+    common.exec_cmd("GOOS=wasip1 GOARCH=wasm go test -c -o /dev/null .")
+    logging.info("Executing fory go wasm tests succeeds")
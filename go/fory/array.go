@@ -369,8 +369,10 @@ func (s byteArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	if ctx.HasError() {
 		return
 	}
-	data := make([]byte, length)
-	buf.Read(data)
+	data := buf.ReadBinary(length, ctx.Err())
+	if ctx.HasError() {
+		return
+	}
 	if value.CanSet() {
 		for i := 0; i < length && i < value.Len(); i++ {
 			value.Index(i).SetUint(uint64(data[i]))
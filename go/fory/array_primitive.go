@@ -20,9 +20,6 @@ package fory
 import (
 	"reflect"
 	"unsafe"
-
-	"github.com/apache/fory/go/fory/bfloat16"
-	"github.com/apache/fory/go/fory/float16"
 )
 
 // ============================================================================
@@ -35,24 +32,9 @@ type boolArraySerializer struct {
 
 func (s boolArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	buf.WriteLength(length)
-	if length > 0 {
-		if value.CanAddr() {
-			// Fast path: direct memory copy - bool is 1 byte in Go
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), length))
-		} else {
-			// Slow path for non-addressable arrays
-			for i := 0; i < length; i++ {
-				if value.Index(i).Bool() {
-					buf.WriteByte(1)
-				} else {
-					buf.WriteByte(0)
-				}
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 1)
 }
 
 func (s boolArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -75,10 +57,8 @@ func (s boolArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		// Direct memory copy - bool is 1 byte in Go
-		ptr := value.Addr().UnsafePointer()
 		raw := buf.ReadBinary(length, err)
-		copy(unsafe.Slice((*byte)(ptr), length), raw)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), length), raw, 1)
 	}
 }
 
@@ -104,20 +84,9 @@ type int8ArraySerializer struct {
 
 func (s int8ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	buf.WriteLength(length)
-	if length > 0 {
-		if value.CanAddr() {
-			// Fast path: direct memory copy - int8 is 1 byte
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), length))
-		} else {
-			// Slow path for non-addressable arrays
-			for i := 0; i < length; i++ {
-				buf.WriteInt8(int8(value.Index(i).Int()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 1)
 }
 
 func (s int8ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -140,10 +109,8 @@ func (s int8ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		// Direct memory copy - int8 is 1 byte
-		ptr := value.Addr().UnsafePointer()
 		raw := buf.ReadBinary(length, err)
-		copy(unsafe.Slice((*byte)(ptr), length), raw)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), length), raw, 1)
 	}
 }
 
@@ -169,21 +136,9 @@ type int16ArraySerializer struct {
 
 func (s int16ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				buf.WriteInt16(int16(value.Index(i).Int()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 2)
 }
 
 func (s int16ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -207,15 +162,8 @@ func (s int16ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetInt(int64(buf.ReadInt16(err)))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 2)
 	}
 }
 
@@ -241,21 +189,9 @@ type int32ArraySerializer struct {
 
 func (s int32ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 4
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				buf.WriteInt32(int32(value.Index(i).Int()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 4)
 }
 
 func (s int32ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -279,15 +215,8 @@ func (s int32ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetInt(int64(buf.ReadInt32(err)))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 4)
 	}
 }
 
@@ -313,21 +242,9 @@ type int64ArraySerializer struct {
 
 func (s int64ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 8
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				buf.WriteInt64(value.Index(i).Int())
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 8)
 }
 
 func (s int64ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -351,15 +268,8 @@ func (s int64ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetInt(buf.ReadInt64(err))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 8)
 	}
 }
 
@@ -385,21 +295,9 @@ type float32ArraySerializer struct {
 
 func (s float32ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 4
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				buf.WriteFloat32(float32(value.Index(i).Float()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 4)
 }
 
 func (s float32ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -423,15 +321,8 @@ func (s float32ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetFloat(float64(buf.ReadFloat32(err)))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 4)
 	}
 }
 
@@ -457,21 +348,9 @@ type float64ArraySerializer struct {
 
 func (s float64ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 8
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				buf.WriteFloat64(value.Index(i).Float())
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 8)
 }
 
 func (s float64ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -495,15 +374,8 @@ func (s float64ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetFloat(buf.ReadFloat64(err))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 8)
 	}
 }
 
@@ -529,20 +401,9 @@ type uint8ArraySerializer struct {
 
 func (s uint8ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	buf.WriteLength(length)
-	if length > 0 {
-		if value.CanAddr() {
-			// Fast path: direct memory copy - uint8 is 1 byte
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), length))
-		} else {
-			// Slow path for non-addressable arrays
-			for i := 0; i < length; i++ {
-				buf.WriteByte(byte(value.Index(i).Uint()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 1)
 }
 
 func (s uint8ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -565,10 +426,8 @@ func (s uint8ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		// Direct memory copy - uint8 is 1 byte
-		ptr := value.Addr().UnsafePointer()
 		raw := buf.ReadBinary(length, err)
-		copy(unsafe.Slice((*byte)(ptr), length), raw)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), length), raw, 1)
 	}
 }
 
@@ -594,22 +453,9 @@ type uint16ArraySerializer struct {
 
 func (s uint16ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			// Fast path: direct memory copy - little-endian only
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Slow path for non-addressable arrays or big-endian
-			for i := 0; i < length; i++ {
-				// Cast to int16 to match INT16_ARRAY wire format (bits are preserved)
-				buf.WriteInt16(int16(value.Index(i).Uint()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 2)
 }
 
 func (s uint16ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -633,15 +479,8 @@ func (s uint16ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetUint(uint64(uint16(buf.ReadInt16(err))))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 2)
 	}
 }
 
@@ -667,20 +506,9 @@ type uint32ArraySerializer struct {
 
 func (s uint32ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 4
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			for i := 0; i < length; i++ {
-				// Cast to int32 (bits preserved)
-				buf.WriteInt32(int32(value.Index(i).Uint()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 4)
 }
 
 func (s uint32ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -704,15 +532,8 @@ func (s uint32ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetUint(uint64(uint32(buf.ReadInt32(err))))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 4)
 	}
 }
 
@@ -738,19 +559,9 @@ type uint64ArraySerializer struct {
 
 func (s uint64ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 8
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			for i := 0; i < length; i++ {
-				buf.WriteInt64(int64(value.Index(i).Uint()))
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 8)
 }
 
 func (s uint64ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -774,15 +585,8 @@ func (s uint64ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).SetUint(uint64(buf.ReadInt64(err)))
-			}
-		}
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 8)
 	}
 }
 
@@ -808,23 +612,9 @@ type float16ArraySerializer struct {
 
 func (s float16ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			for i := 0; i < length; i++ {
-				// We can't easily cast the whole array if not addressable/little-endian
-				// So we iterate.
-				// value.Index(i) is Float16, we cast to uint16
-				val := value.Index(i).Interface().(float16.Float16)
-				buf.WriteUint16(val.Bits())
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 2)
 }
 
 func (s float16ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -847,17 +637,9 @@ func (s float16ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		ctx.SetError(DeserializationErrorf("array length %d does not match type %v", length, value.Type()))
 		return
 	}
-
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, ctxErr)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).Set(reflect.ValueOf(float16.Float16FromBits(buf.ReadUint16(ctxErr))))
-			}
-		}
+		raw := buf.ReadBinary(size, ctxErr)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 2)
 	}
 }
 
@@ -883,22 +665,9 @@ type bfloat16ArraySerializer struct {
 
 func (s bfloat16ArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	buf := ctx.Buffer()
-	length := value.Len()
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		if value.CanAddr() && isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			for i := 0; i < length; i++ {
-				// We can't easily cast the whole array if not addressable/little-endian
-				// So we iterate.
-				val := value.Index(i).Interface().(bfloat16.BFloat16)
-				buf.WriteUint16(val.Bits())
-			}
-		}
-	}
+	data := arrayBytes(value)
+	buf.WriteLength(len(data))
+	writeLittleEndianBytes(buf, data, 2)
 }
 
 func (s bfloat16ArraySerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -921,17 +690,9 @@ func (s bfloat16ArraySerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		ctx.SetError(DeserializationErrorf("array length %d does not match type %v", length, value.Type()))
 		return
 	}
-
 	if length > 0 {
-		if isLittleEndian {
-			ptr := value.Addr().UnsafePointer()
-			raw := buf.ReadBinary(size, ctxErr)
-			copy(unsafe.Slice((*byte)(ptr), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				value.Index(i).Set(reflect.ValueOf(bfloat16.BFloat16FromBits(buf.ReadUint16(ctxErr))))
-			}
-		}
+		raw := buf.ReadBinary(size, ctxErr)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, 2)
 	}
 }
 
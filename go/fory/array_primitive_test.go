@@ -18,6 +18,7 @@
 package fory
 
 import (
+	"reflect"
 	"testing"
 
 	"github.com/apache/fory/go/fory/bfloat16"
@@ -90,6 +91,20 @@ func TestPrimitiveArraySerializer(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Equal(t, arr, result)
 	})
+
+	t.Run("non_addressable", func(t *testing.T) {
+		arr := [3]int16{1, -2, 300}
+		ctx := f.writeCtx
+		ctx.Reset()
+		int16ArraySerializer{}.WriteData(ctx, reflect.ValueOf(arr))
+		require.NoError(t, ctx.CheckError())
+
+		f.readCtx.SetData(ctx.Buffer().GetByteSlice(0, ctx.Buffer().WriterIndex()))
+		var result [3]int16
+		int16ArraySerializer{}.ReadData(f.readCtx, reflect.ValueOf(&result).Elem())
+		require.NoError(t, f.readCtx.CheckError())
+		require.Equal(t, arr, result)
+	})
 }
 
 func TestArraySliceInteroperability(t *testing.T) {
@@ -0,0 +1,137 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"iter"
+	"reflect"
+	"time"
+)
+
+// MarshalBatch serializes values into a single payload: one root header, the
+// value count, then each value in order. The values share one serialization
+// scope, so type metadata such as the type defs written in compatible mode
+// appears once for the whole batch instead of once per value, and with
+// reference tracking a pointer shared by several values is written once.
+// Read the payload back with ReadBatch.
+//
+// As with Marshal, the returned slice is invalidated by the next call on f.
+func (f *Fory) MarshalBatch(values []any) ([]byte, error) {
+	if m := f.config.Metrics; m != nil {
+		start := time.Now()
+		data, err := f.marshalBatch(values)
+		m.Serialized(reflect.TypeOf(values), len(data), time.Since(start), err)
+		return data, err
+	}
+	return f.marshalBatch(values)
+}
+
+func (f *Fory) marshalBatch(values []any) (_ []byte, err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverWrite(&err, f.writeCtx.buffer)
+	}
+	defer f.resetWriteState()
+	writeHeader(f.writeCtx, f.config)
+	f.writeCtx.buffer.WriteVarUint32(uint32(len(values)))
+	for _, value := range values {
+		reflValue := reflect.ValueOf(value)
+		if err := checkRootValue(reflValue); err != nil {
+			return nil, err
+		}
+		f.writeCtx.WriteValue(reflValue, RefModeTracking, true)
+		if f.writeCtx.HasError() {
+			return nil, f.writeCtx.TakeError()
+		}
+	}
+	if f.writeCtx.frameBody {
+		if err := finishBody(f.writeCtx); err != nil {
+			return nil, err
+		}
+	}
+	return f.writeCtx.buffer.GetByteSlice(0, f.writeCtx.buffer.writerIndex), nil
+}
+
+// ReadBatch returns an iterator over the values of a payload written by
+// MarshalBatch, decoding each one as the loop reaches it. Values are decoded
+// as by Unmarshal into an *any. A decoding error is yielded once with a nil
+// value and ends the iteration, and so does a payload whose count exceeds its
+// length.
+//
+// The instance is busy until the loop ends, so the loop body must not use f.
+// data is not copied and must stay unmodified while the loop runs.
+func (f *Fory) ReadBatch(data []byte) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		defer f.resetReadState()
+		var elapsed time.Duration
+		var err error
+		if m := f.config.Metrics; m != nil {
+			// Time spent in the loop body is not part of the decode.
+			defer func() {
+				m.Deserialized(reflect.TypeOf([]any(nil)), len(data), elapsed, err)
+			}()
+		}
+		start := time.Now()
+		count, err := f.readBatchHeader(data)
+		elapsed += time.Since(start)
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		for range count {
+			var value any
+			start = time.Now()
+			err = f.readBatchValue(&value)
+			elapsed += time.Since(start)
+			if err != nil {
+				yield(nil, err)
+				return
+			}
+			if !yield(value, nil) {
+				return
+			}
+		}
+	}
+}
+
+// readBatchHeader reads the root header and value count of a batch.
+func (f *Fory) readBatchHeader(data []byte) (_ int, err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
+	f.readCtx.SetData(data)
+	readHeader(f.readCtx)
+	count := f.readCtx.buffer.ReadVarUint32(f.readCtx.Err())
+	if f.readCtx.HasError() {
+		return 0, f.readCtx.TakeError()
+	}
+	// Every value takes at least its null or ref flag byte.
+	if remaining := f.readCtx.buffer.remaining(); int64(count) > int64(remaining) {
+		return 0, DeserializationErrorf("batch of %d values exceeds the %d remaining bytes", count, remaining)
+	}
+	return int(count), nil
+}
+
+// readBatchValue reads the next batch value into v, keeping the read state
+// so that later values can refer to the metadata and references of earlier
+// ones.
+func (f *Fory) readBatchValue(v *any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
+	return f.readRoot(v)
+}
@@ -0,0 +1,145 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type batchEvent struct {
+	ID     int64
+	Kind   string
+	Labels []string
+}
+
+func newBatchFory(t *testing.T, opts ...Option) *Fory {
+	f := NewFory(opts...)
+	require.NoError(t, f.RegisterStructByName(batchEvent{}, "example.batch_event"))
+	return f
+}
+
+func readAll(t *testing.T, f *Fory, data []byte) []any {
+	var values []any
+	for value, err := range f.ReadBatch(data) {
+		require.NoError(t, err)
+		values = append(values, value)
+	}
+	return values
+}
+
+func TestMarshalBatch(t *testing.T) {
+	values := []any{
+		&batchEvent{ID: 1, Kind: "open", Labels: []string{"a"}},
+		"note",
+		nil,
+		int64(7),
+		&batchEvent{ID: 2, Kind: "close", Labels: []string{}},
+	}
+
+	for _, opts := range [][]Option{
+		{WithXlang(true)},
+		{WithXlang(true), WithCompatible(false)},
+		{WithXlang(false)},
+	} {
+		f := newBatchFory(t, opts...)
+		data, err := f.MarshalBatch(values)
+		require.NoError(t, err)
+		require.Equal(t, values, readAll(t, f, data))
+	}
+
+	t.Run("Empty", func(t *testing.T) {
+		f := newBatchFory(t)
+		data, err := f.MarshalBatch(nil)
+		require.NoError(t, err)
+		require.Empty(t, readAll(t, f, data))
+	})
+
+	t.Run("SharesTypeDefs", func(t *testing.T) {
+		f := newBatchFory(t, WithCompatible(true))
+		events := make([]any, 20)
+		separate := 0
+		for i := range events {
+			events[i] = &batchEvent{ID: int64(i), Kind: "tick", Labels: []string{"x"}}
+			data, err := f.Marshal(events[i])
+			require.NoError(t, err)
+			separate += len(data)
+		}
+		data, err := f.MarshalBatch(events)
+		require.NoError(t, err)
+		require.Less(t, len(data)*3, separate)
+		require.Equal(t, events, readAll(t, f, data))
+	})
+
+	t.Run("SharedReferences", func(t *testing.T) {
+		f := newBatchFory(t, WithTrackRef(true))
+		shared := &batchEvent{ID: 3, Kind: "shared", Labels: []string{}}
+		data, err := f.MarshalBatch([]any{shared, shared})
+		require.NoError(t, err)
+		got := readAll(t, f, data)
+		require.Equal(t, shared, got[0])
+		require.Same(t, got[0], got[1])
+	})
+
+	t.Run("Break", func(t *testing.T) {
+		f := newBatchFory(t)
+		data, err := f.MarshalBatch(values)
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+		for value, err := range f.ReadBatch(data) {
+			require.NoError(t, err)
+			require.Equal(t, values[0], value)
+			break
+		}
+		// The instance is usable again once the loop ends.
+		var s string
+		single, err := f.Marshal("after")
+		require.NoError(t, err)
+		require.NoError(t, f.Unmarshal(single, &s))
+		require.Equal(t, "after", s)
+		require.Equal(t, values, readAll(t, f, data))
+	})
+
+	t.Run("Truncated", func(t *testing.T) {
+		f := newBatchFory(t)
+		data, err := f.MarshalBatch(values)
+		require.NoError(t, err)
+		var errs int
+		for value, err := range f.ReadBatch(data[:len(data)-4]) {
+			if err != nil {
+				require.Nil(t, value)
+				errs++
+			}
+		}
+		require.Equal(t, 1, errs)
+
+		// A count larger than the payload fails before decoding anything.
+		for _, err := range f.ReadBatch([]byte{data[0], 0x7f}) {
+			require.Error(t, err)
+		}
+	})
+
+	t.Run("StructValue", func(t *testing.T) {
+		f := newBatchFory(t)
+		_, err := f.MarshalBatch([]any{batchEvent{ID: 1}})
+		require.Error(t, err)
+	})
+}
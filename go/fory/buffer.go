@@ -31,12 +31,54 @@ type ByteBuffer struct {
 	readerIndex int
 	reader      io.Reader
 	bufferSize  int
+	capacity    int // Preallocated capacity kept across Reset
+	growth      BufferGrowth
+}
+
+// retainedCapacity is the largest backing array Reset keeps without a capacity hint.
+const retainedCapacity = 64 * 1024
+
+// BufferGrowth controls how a ByteBuffer expands when a write does not fit.
+type BufferGrowth struct {
+	// Factor multiplies the required size on reallocation, e.g. 2 or 1.25.
+	// Values <= 1 use the default factor of 2.
+	Factor float64
+	// MaxCapacity bounds speculative over-allocation. A write that needs more
+	// than MaxCapacity still succeeds, but the buffer grows only to the exact
+	// size required. Zero means unbounded.
+	MaxCapacity int
 }
 
 func NewByteBuffer(data []byte) *ByteBuffer {
 	return &ByteBuffer{data: data}
 }
 
+// NewByteBufferWithCapacity creates an empty buffer with n bytes preallocated.
+// The capacity is kept across Reset so large-message producers avoid repeated
+// grow-and-copy cycles.
+func NewByteBufferWithCapacity(n int) *ByteBuffer {
+	if n < 0 {
+		n = 0
+	}
+	return &ByteBuffer{data: make([]byte, n), capacity: n}
+}
+
+// WrapReadOnly points the buffer at data for reading without copying it.
+// Binary values read from the buffer alias data, so the caller must keep it
+// alive and unmodified while they are in use. The buffer never writes into
+// data: its capacity is clamped to len(data), so any later write reallocates.
+func (b *ByteBuffer) WrapReadOnly(data []byte) {
+	b.data = data[:len(data):len(data)]
+	b.readerIndex = 0
+	b.writerIndex = len(data)
+	b.reader = nil
+}
+
+// SetGrowth sets the growth policy used when writes exceed the current capacity.
+func (b *ByteBuffer) SetGrowth(growth BufferGrowth) {
+	b.growth = growth
+}
+
 func NewByteBufferFromReader(r io.Reader, bufferSize int) *ByteBuffer {
 	if bufferSize <= 0 {
 		bufferSize = 4096
@@ -51,9 +93,7 @@ func NewByteBufferFromReader(r io.Reader, bufferSize int) *ByteBuffer {
 //go:noinline
 func (b *ByteBuffer) fill(n int, errOut *Error) bool {
 	if b.reader == nil {
-		if errOut != nil {
-			*errOut = BufferOutOfBoundError(b.readerIndex, n, len(b.data))
-		}
+		errOut.setFirst(BufferOutOfBoundError(b.readerIndex, n, len(b.data)))
 		return false
 	}
 
@@ -96,12 +136,10 @@ func (b *ByteBuffer) fill(n int, errOut *Error) bool {
 			if len(b.data) >= n {
 				return true
 			}
-			if errOut != nil {
-				if err == io.EOF {
-					*errOut = BufferOutOfBoundError(b.readerIndex, n, len(b.data))
-				} else {
-					*errOut = DeserializationError(fmt.Sprintf("stream read error: %v", err))
-				}
+			if err == io.EOF || err == io.ErrUnexpectedEOF {
+				errOut.setFirst(BufferOutOfBoundError(b.readerIndex, n, len(b.data)))
+			} else {
+				errOut.setFirst(DeserializationError(fmt.Sprintf("stream read error: %v", err)))
 			}
 			return false
 		}
@@ -125,13 +163,24 @@ func (b *ByteBuffer) growSlow(n int) {
 	if needed <= cap(b.data) {
 		b.data = b.data[:cap(b.data)]
 	} else {
-		newCap := 2 * needed
-		newBuf := make([]byte, newCap)
+		newBuf := make([]byte, b.grownCapacity(needed))
 		copy(newBuf, b.data[:b.writerIndex])
 		b.data = newBuf
 	}
 }
 
+// grownCapacity returns the capacity to allocate for at least needed bytes.
+func (b *ByteBuffer) grownCapacity(needed int) int {
+	newCap := 2 * needed
+	if b.growth.Factor > 1 {
+		newCap = int(float64(needed) * b.growth.Factor)
+	}
+	if b.growth.MaxCapacity > 0 && newCap > b.growth.MaxCapacity {
+		newCap = max(b.growth.MaxCapacity, needed)
+	}
+	return max(newCap, needed, b.capacity)
+}
+
 func (b *ByteBuffer) WriteBool(value bool) {
 	b.grow(1)
 	// Branchless: directly convert bool to byte via unsafe
@@ -429,7 +478,7 @@ func (b *ByteBuffer) Reset() {
 	b.reader = nil
 	// Keep the underlying buffer if it's reasonable sized to reduce allocations
 	// Only nil it out if we want to release memory
-	if cap(b.data) > 64*1024 {
+	if cap(b.data) > max(retainedCapacity, b.capacity) {
 		b.data = nil
 	}
 }
@@ -460,8 +509,7 @@ func (b *ByteBuffer) Reserve(n int) {
 	if needed <= cap(b.data) {
 		b.data = b.data[:cap(b.data)]
 	} else {
-		newCap := 2 * needed
-		newBuf := make([]byte, newCap, newCap)
+		newBuf := make([]byte, b.grownCapacity(needed))
 		copy(newBuf, b.data)
 		b.data = newBuf
 	}
@@ -979,7 +1027,7 @@ func (b *ByteBuffer) readVaruint36SmallSlow(err *Error) uint64 {
 		}
 		shift += 7
 		if shift >= 36 {
-			*err = DeserializationError("varuint36small overflow")
+			err.setFirst(DeserializationError("varuint36small overflow"))
 			return 0
 		}
 	}
@@ -1290,9 +1338,7 @@ func (b *ByteBuffer) readVarUint32Fast(err *Error) uint32 {
 				if (bulk & 0x80000000) != 0 {
 					fifth := byte(bulk >> 32)
 					if fifth > 0x0F {
-						if err != nil {
-							*err = DeserializationError("VarUint32 overflow")
-						}
+						err.setFirst(DeserializationError("VarUint32 overflow"))
 						return 0
 					}
 					result |= uint32((bulk >> 4) & 0xF0000000)
@@ -1318,9 +1364,7 @@ func (b *ByteBuffer) readVarUint32Slow(err *Error) uint32 {
 		byteVal := b.data[b.readerIndex]
 		b.readerIndex++
 		if shift == 28 && byteVal > 0x0F {
-			if err != nil {
-				*err = DeserializationError("VarUint32 overflow")
-			}
+			err.setFirst(DeserializationError("VarUint32 overflow"))
 			return 0
 		}
 		result |= (uint32(byteVal) & 0x7F) << shift
@@ -1329,7 +1373,7 @@ func (b *ByteBuffer) readVarUint32Slow(err *Error) uint32 {
 		}
 		shift += 7
 		if shift >= 35 {
-			*err = DeserializationError("VarUint32 overflow")
+			err.setFirst(DeserializationError("VarUint32 overflow"))
 			return 0
 		}
 	}
@@ -1506,9 +1550,7 @@ func (b *ByteBuffer) continueReadVarUint32(readIdx int, bulkRead, value uint32,
 		if bulkRead&0x80000000 != 0 {
 			v := b.data[readIdx]
 			if v > 0x0F {
-				if err != nil {
-					*err = DeserializationError("VarUint32 overflow")
-				}
+				err.setFirst(DeserializationError("VarUint32 overflow"))
 				return 0
 			}
 			readIdx++
@@ -1536,7 +1578,7 @@ func (b *ByteBuffer) readVaruint36Slow(err *Error) uint64 {
 		}
 		shift += 7
 		if shift >= 35 {
-			*err = DeserializationError("varuint36 overflow")
+			err.setFirst(DeserializationError("varuint36 overflow"))
 			return 0
 		}
 	}
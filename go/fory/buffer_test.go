@@ -19,6 +19,10 @@ package fory
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
+	"io"
+	"math"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -138,3 +142,169 @@ func TestReadVarUint32Small7StreamRejectsOverflowFifthByte(t *testing.T) {
 	_ = buf.ReadVarUint32Small7(&err)
 	require.True(t, err.HasError())
 }
+
+func TestTruncatedReadsReportUnexpectedEOF(t *testing.T) {
+	reads := map[string]func(b *ByteBuffer, err *Error){
+		"int32":          func(b *ByteBuffer, err *Error) { b.ReadInt32(err) },
+		"float64":        func(b *ByteBuffer, err *Error) { b.ReadFloat64(err) },
+		"varuint32":      func(b *ByteBuffer, err *Error) { b.ReadVarUint32(err) },
+		"varuint32small": func(b *ByteBuffer, err *Error) { b.ReadVarUint32Small7(err) },
+		"varint64":       func(b *ByteBuffer, err *Error) { b.ReadVarint64(err) },
+		"binary":         func(b *ByteBuffer, err *Error) { b.ReadBinary(4, err) },
+	}
+	for name, read := range reads {
+		for _, buf := range []*ByteBuffer{
+			NewByteBuffer([]byte{0x80, 0x80}),
+			NewByteBufferFromReader(bytes.NewReader([]byte{0x80, 0x80}), 1),
+		} {
+			var err Error
+			read(buf, &err)
+			require.Equal(t, ErrKindBufferOutOfBound, err.Kind(), name)
+			require.True(t, errors.Is(err, io.ErrUnexpectedEOF), name)
+		}
+	}
+
+	// Reads after the first failure keep its error.
+	buf := NewByteBuffer([]byte{0x80, 0x80, 0x80, 0x80, 0x10})
+	var err Error
+	buf.ReadInt64(&err)
+	buf.ReadVarUint32(&err)
+	require.Equal(t, ErrKindBufferOutOfBound, err.Kind())
+	require.False(t, errors.Is(DeserializationError("other"), io.ErrUnexpectedEOF))
+}
+
+// TestLittleEndianLayout pins the wire layout of fixed-width writes to
+// encoding/binary's little-endian form, so the native-memory fast paths and the
+// big-endian fallbacks must both produce the same bytes.
+func TestLittleEndianLayout(t *testing.T) {
+	const v16 = uint16(0x0102)
+	const v32 = uint32(0x01020304)
+	const v64 = uint64(0x0102030405060708)
+	f32 := float32(1.5)
+	f64 := float64(-2.25)
+
+	want := binary.LittleEndian.AppendUint16(nil, v16)
+	want = binary.LittleEndian.AppendUint32(want, v32)
+	want = binary.LittleEndian.AppendUint64(want, v64)
+	want = binary.LittleEndian.AppendUint32(want, math.Float32bits(f32))
+	want = binary.LittleEndian.AppendUint64(want, math.Float64bits(f64))
+
+	safe := NewByteBuffer(nil)
+	safe.WriteInt16(int16(v16))
+	safe.WriteInt32(int32(v32))
+	safe.WriteInt64(int64(v64))
+	safe.WriteFloat32(f32)
+	safe.WriteFloat64(f64)
+	require.Equal(t, want, safe.Bytes())
+
+	unsafeBuf := NewByteBuffer(nil)
+	unsafeBuf.Reserve(len(want))
+	unsafeBuf.UnsafeWriteInt16(int16(v16))
+	unsafeBuf.UnsafeWriteInt32(int32(v32))
+	unsafeBuf.UnsafeWriteInt64(int64(v64))
+	unsafeBuf.UnsafeWriteFloat32(f32)
+	unsafeBuf.UnsafeWriteFloat64(f64)
+	require.Equal(t, want, unsafeBuf.Bytes())
+
+	err := &Error{}
+	require.Equal(t, int16(v16), unsafeBuf.ReadInt16(err))
+	require.Equal(t, int32(v32), unsafeBuf.UnsafeReadInt32())
+	require.Equal(t, int64(v64), unsafeBuf.UnsafeReadInt64())
+	require.Equal(t, f32, unsafeBuf.ReadFloat32(err))
+	require.Equal(t, f64, unsafeBuf.ReadFloat64(err))
+	require.False(t, err.HasError())
+
+	tagged := NewByteBuffer(nil)
+	tagged.WriteTaggedInt64(int64(v64))
+	tagged.WriteTaggedUint64(v64)
+	wantTagged := append([]byte{1}, binary.LittleEndian.AppendUint64(nil, v64)...)
+	wantTagged = append(wantTagged, 1)
+	wantTagged = binary.LittleEndian.AppendUint64(wantTagged, v64)
+	require.Equal(t, wantTagged, tagged.Bytes())
+	require.Equal(t, int64(v64), tagged.ReadTaggedInt64(err))
+	require.Equal(t, v64, tagged.ReadTaggedUint64(err))
+	require.False(t, err.HasError())
+
+	slices := NewByteBuffer(nil)
+	WriteInt16Slice(slices, []int16{int16(v16)})
+	WriteInt32Slice(slices, []int32{int32(v32)})
+	WriteInt64Slice(slices, []int64{int64(v64)})
+	WriteFloat32Slice(slices, []float32{f32})
+	WriteFloat64Slice(slices, []float64{f64})
+	wantSlices := append([]byte{2}, want[0:2]...)
+	wantSlices = append(append(wantSlices, 4), want[2:6]...)
+	wantSlices = append(append(wantSlices, 8), want[6:14]...)
+	wantSlices = append(append(wantSlices, 4), want[14:18]...)
+	wantSlices = append(append(wantSlices, 8), want[18:26]...)
+	require.Equal(t, wantSlices, slices.Bytes())
+}
+
+// TestVarintFastPathLayout checks that the bulk-load varint readers agree with
+// the byte-at-a-time encoding, which is independent of host byte order.
+func TestVarintFastPathLayout(t *testing.T) {
+	for _, v := range []uint64{0, 0x7f, 0x80, 0x3fff, 0x4000, 1 << 28, 1<<35 - 1, 1 << 56, ^uint64(0)} {
+		want := binary.AppendUvarint(nil, v)
+		if len(want) > 9 {
+			// Fory caps varuint64 at 9 bytes; the last byte carries the top 8 bits.
+			want = append(want[:8:8], byte(v>>56))
+		}
+		buf := NewByteBuffer(nil)
+		buf.WriteVarUint64(v)
+		require.Equal(t, want, buf.Bytes(), "value %#x", v)
+		// Pad so ReadVarUint64 takes the bulk-load fast path.
+		buf.WriteBinary(make([]byte, 9))
+		err := &Error{}
+		require.Equal(t, v, buf.ReadVarUint64(err))
+		require.False(t, err.HasError())
+
+		if v <= 0xffffffff {
+			buf32 := NewByteBuffer(nil)
+			buf32.WriteVarUint32(uint32(v))
+			require.Equal(t, want, buf32.Bytes(), "value %#x", v)
+			buf32.WriteBinary(make([]byte, 8))
+			require.Equal(t, uint32(v), buf32.ReadVarUint32(err))
+			require.False(t, err.HasError())
+		}
+	}
+}
+
+func TestByteBufferWithCapacity(t *testing.T) {
+	buf := NewByteBufferWithCapacity(1 << 20)
+	data := &buf.GetData()[0]
+	buf.WriteBinary(make([]byte, 1000))
+	require.Same(t, data, &buf.GetData()[0], "preallocated buffer must not reallocate")
+	buf.Reset()
+	require.Equal(t, 1<<20, cap(buf.GetData()), "Reset must keep the preallocated capacity")
+}
+
+func TestByteBufferGrowth(t *testing.T) {
+	buf := NewByteBuffer(nil)
+	buf.SetGrowth(BufferGrowth{Factor: 1.25})
+	buf.WriteBinary(make([]byte, 1000))
+	require.Equal(t, 1250, cap(buf.GetData()))
+
+	buf = NewByteBuffer(nil)
+	buf.SetGrowth(BufferGrowth{MaxCapacity: 1500})
+	buf.WriteBinary(make([]byte, 1000))
+	require.Equal(t, 1500, cap(buf.GetData()))
+	buf.WriteBinary(make([]byte, 1000))
+	require.Equal(t, 2000, cap(buf.GetData()), "writes beyond MaxCapacity grow to the exact size")
+}
+
+func TestByteBufferWrapReadOnly(t *testing.T) {
+	data := make([]byte, 16, 64)
+	binary.LittleEndian.PutUint32(data, 7)
+	copy(data[4:], "payload")
+	buf := NewByteBuffer(nil)
+	buf.WrapReadOnly(data[:11])
+
+	var err Error
+	require.Equal(t, int32(7), buf.ReadInt32(&err))
+	raw := buf.ReadBinary(7, &err)
+	require.NoError(t, err.CheckError())
+	require.Same(t, &data[4], &raw[0], "wrapped data must not be copied")
+
+	buf.WriteInt32(9)
+	require.Equal(t, make([]byte, 4), data[11:15], "writes must not touch the wrapped slice")
+	require.Equal(t, 15, buf.WriterIndex())
+}
@@ -0,0 +1,28 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build tinygo || fory_codegen_only
+
+package fory
+
+// codegenOnly reports whether reflection-based struct serializers are disabled.
+// They are still compiled, but no struct can be registered with them. TinyGo
+// builds select this mode automatically; other builds opt in with the
+// fory_codegen_only tag. Structs must then use serializers generated by
+// forygen, and compatible mode is unavailable because it reads struct fields
+// through reflection.
+const codegenOnly = true
@@ -0,0 +1,114 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package cachecodec stores cache values in the Fory format. A Codec bundles a
+// thread-safe Fory with the types registered for it, so one shared value holds
+// the registration for every cache client in a program.
+//
+// Codec.Marshal and Codec.Unmarshal match the marshal hooks of cache libraries
+// such as github.com/go-redis/cache:
+//
+//	c := cachecodec.New(newFory)
+//	mycache := cache.New(&cache.Options{
+//	    Redis:     rdb,
+//	    Marshal:   c.Marshal,
+//	    Unmarshal: c.Unmarshal,
+//	})
+//
+// Clients that accept encoding.BinaryMarshaler values and scan into
+// encoding.BinaryUnmarshaler targets, such as go-redis, use Value and Dest:
+//
+//	err := rdb.Set(ctx, key, c.Value(user), time.Hour).Err()
+//	err = rdb.Get(ctx, key).Scan(c.Dest(&user))
+//
+// Clients that take raw bytes, such as rueidis and gomemcache, use Marshal
+// and Unmarshal directly.
+package cachecodec
+
+import (
+	"encoding"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+)
+
+// Codec marshals cache values with Fory. It is safe for concurrent use.
+type Codec struct {
+	fory *threadsafe.Fory
+}
+
+// New returns a Codec whose Fory instances are created by factory, which
+// should configure and register every type stored in the cache. A nil factory
+// creates xlang instances with default options.
+func New(factory func() *fory.Fory) *Codec {
+	if factory == nil {
+		factory = func() *fory.Fory { return fory.New(fory.WithXlang(true)) }
+	}
+	return &Codec{fory: threadsafe.NewWithFactory(factory)}
+}
+
+// Marshal returns the Fory encoding of v. Struct values are accepted as well
+// as pointers to structs.
+func (c *Codec) Marshal(v any) ([]byte, error) {
+	// Fory serializes root structs through pointers.
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Struct {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		v = ptr.Interface()
+	}
+	return c.fory.Serialize(v)
+}
+
+// Unmarshal decodes data into v, which must be a non-nil pointer.
+func (c *Codec) Unmarshal(data []byte, v any) error {
+	if value := reflect.ValueOf(v); value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("cachecodec: cannot unmarshal into non-pointer %T", v)
+	}
+	return c.fory.Deserialize(data, v)
+}
+
+// Value wraps v so that clients accepting encoding.BinaryMarshaler store it
+// in the Fory format.
+func (c *Codec) Value(v any) encoding.BinaryMarshaler {
+	return value{codec: c, v: v}
+}
+
+// Dest wraps the pointer v so that clients scanning into
+// encoding.BinaryUnmarshaler decode the stored Fory payload into it.
+func (c *Codec) Dest(v any) encoding.BinaryUnmarshaler {
+	return dest{codec: c, v: v}
+}
+
+type value struct {
+	codec *Codec
+	v     any
+}
+
+func (v value) MarshalBinary() ([]byte, error) {
+	return v.codec.Marshal(v.v)
+}
+
+type dest struct {
+	codec *Codec
+	v     any
+}
+
+func (d dest) UnmarshalBinary(data []byte) error {
+	return d.codec.Unmarshal(data, d.v)
+}
@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package cachecodec
+
+import (
+	"sync"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type session struct {
+	User  string
+	Roles []string
+	TTL   int64
+}
+
+func newFory() *fory.Fory {
+	f := fory.New(fory.WithXlang(true))
+	if err := f.RegisterStructByName(session{}, "example.Session"); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestMarshalUnmarshal(t *testing.T) {
+	c := New(newFory)
+	in := session{User: "ann", Roles: []string{"admin"}, TTL: 60}
+
+	for _, v := range []any{in, &in} {
+		data, err := c.Marshal(v)
+		require.NoError(t, err)
+		var out session
+		require.NoError(t, c.Unmarshal(data, &out))
+		require.Equal(t, in, out)
+	}
+
+	data, err := c.Marshal(map[string]int32{"hits": 3})
+	require.NoError(t, err)
+	var counts map[string]int32
+	require.NoError(t, c.Unmarshal(data, &counts))
+	require.Equal(t, map[string]int32{"hits": 3}, counts)
+
+	var out session
+	require.Error(t, c.Unmarshal(data, out))
+}
+
+func TestValueAndDest(t *testing.T) {
+	c := New(newFory)
+	in := &session{User: "bob", Roles: []string{"guest"}, TTL: 30}
+	data, err := c.Value(in).MarshalBinary()
+	require.NoError(t, err)
+	var out session
+	require.NoError(t, c.Dest(&out).UnmarshalBinary(data))
+	require.Equal(t, *in, out)
+}
+
+func TestConcurrentUse(t *testing.T) {
+	c := New(newFory)
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for j := 0; j < 100; j++ {
+				in := session{User: "ann", Roles: []string{"user"}, TTL: int64(j)}
+				data, err := c.Marshal(&in)
+				assert.NoError(t, err)
+				var out session
+				assert.NoError(t, c.Unmarshal(data, &out))
+				assert.Equal(t, in, out)
+			}
+		}()
+	}
+	wg.Wait()
+}
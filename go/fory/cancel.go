@@ -0,0 +1,98 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import "context"
+
+// cancelCheckInterval is the number of struct values and collection elements
+// encoded or decoded between checks of the context.
+const cancelCheckInterval = 1024
+
+// MarshalContext is Marshal, stopping with an error once ctx is done. The
+// context is checked every 1024 struct values and collection elements, so
+// large values stop soon after cancellation or their deadline; arrays of
+// numbers and strings are copied whole between checks. The error wraps
+// ctx.Err(), so errors.Is reports context.Canceled or
+// context.DeadlineExceeded, and has kind ErrKindCanceled.
+func (f *Fory) MarshalContext(ctx context.Context, v any, opts ...MarshalOption) ([]byte, error) {
+	if ctx.Done() == nil {
+		return f.Marshal(v, opts...)
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, canceledError(err)
+	}
+	f.writeCtx.cancel, f.writeCtx.untilCancelCheck = ctx, cancelCheckInterval
+	defer func() {
+		f.writeCtx.cancel = nil
+	}()
+	return f.Marshal(v, opts...)
+}
+
+// UnmarshalContext is Unmarshal, stopping with an error once ctx is done.
+// The context is checked as MarshalContext checks it. When decoding stops,
+// v may be partly filled.
+func (f *Fory) UnmarshalContext(ctx context.Context, data []byte, v any) error {
+	if ctx.Done() == nil {
+		return f.Unmarshal(data, v)
+	}
+	if err := ctx.Err(); err != nil {
+		return canceledError(err)
+	}
+	f.readCtx.cancel, f.readCtx.untilCancelCheck = ctx, cancelCheckInterval
+	defer func() {
+		f.readCtx.cancel = nil
+	}()
+	return f.Unmarshal(data, v)
+}
+
+// countWork records n units of encoding work and checks the context of a
+// MarshalContext call once enough have been done.
+func (c *WriteContext) countWork(n int) {
+	if c.cancel == nil {
+		return
+	}
+	c.untilCancelCheck -= n
+	if c.untilCancelCheck <= 0 {
+		c.checkCanceled()
+	}
+}
+
+func (c *WriteContext) checkCanceled() {
+	c.untilCancelCheck = cancelCheckInterval
+	if err := c.cancel.Err(); err != nil {
+		c.SetError(canceledError(err))
+	}
+}
+
+// countWork is WriteContext.countWork for UnmarshalContext calls.
+func (c *ReadContext) countWork(n int) {
+	if c.cancel == nil {
+		return
+	}
+	c.untilCancelCheck -= n
+	if c.untilCancelCheck <= 0 {
+		c.checkCanceled()
+	}
+}
+
+func (c *ReadContext) checkCanceled() {
+	c.untilCancelCheck = cancelCheckInterval
+	if err := c.cancel.Err(); err != nil {
+		c.SetError(canceledError(err))
+	}
+}
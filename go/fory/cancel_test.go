@@ -0,0 +1,113 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type cancelItem struct {
+	ID   int64
+	Name string
+}
+
+// checkCountingContext is done from its checks-th call to Err on.
+type checkCountingContext struct {
+	context.Context
+	checks int
+}
+
+func (c *checkCountingContext) Done() <-chan struct{} {
+	return make(chan struct{})
+}
+
+func (c *checkCountingContext) Err() error {
+	if c.checks--; c.checks <= 0 {
+		return context.Canceled
+	}
+	return nil
+}
+
+func TestMarshalContext(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(cancelItem{}, "example.cancelItem"))
+	items := make([]cancelItem, 10000)
+	for i := range items {
+		items[i] = cancelItem{ID: int64(i), Name: "item"}
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	data, err := f.MarshalContext(ctx, items)
+	require.NoError(t, err)
+	var out []cancelItem
+	require.NoError(t, f.UnmarshalContext(ctx, data, &out))
+	require.Equal(t, items, out)
+	data, err = f.MarshalContext(context.Background(), items)
+	require.NoError(t, err)
+	require.NoError(t, f.UnmarshalContext(context.Background(), data, &out))
+	require.Equal(t, items, out)
+
+	// Cancellation partway through.
+	_, err = f.MarshalContext(&checkCountingContext{context.Background(), 3}, items)
+	requireCanceled(t, err, context.Canceled)
+	require.NoError(t, f.Unmarshal(data, &out))
+	err = f.UnmarshalContext(&checkCountingContext{context.Background(), 3}, data, &out)
+	requireCanceled(t, err, context.Canceled)
+	require.NoError(t, f.Unmarshal(data, &out))
+	require.Equal(t, items, out)
+
+	// A done context fails before any work.
+	cancel()
+	_, err = f.MarshalContext(ctx, items)
+	requireCanceled(t, err, context.Canceled)
+	expired, cancelExpired := context.WithTimeout(context.Background(), 0)
+	defer cancelExpired()
+	err = f.UnmarshalContext(expired, data, &out)
+	requireCanceled(t, err, context.DeadlineExceeded)
+}
+
+func TestMarshalContextMap(t *testing.T) {
+	f := NewFory()
+	m := make(map[int64]string, 5000)
+	for i := range int64(5000) {
+		m[i] = "v"
+	}
+	_, err := f.MarshalContext(&checkCountingContext{context.Background(), 1}, m)
+	requireCanceled(t, err, context.Canceled)
+	data, err := f.Marshal(m)
+	require.NoError(t, err)
+	var out map[int64]string
+	err = f.UnmarshalContext(&checkCountingContext{context.Background(), 1}, data, &out)
+	requireCanceled(t, err, context.Canceled)
+}
+
+func requireCanceled(t *testing.T, err error, cause error) {
+	t.Helper()
+	require.Error(t, err)
+	require.True(t, errors.Is(err, cause), "%v", err)
+	var foryErr Error
+	require.True(t, errors.As(err, &foryErr))
+	require.Equal(t, ErrKindCanceled, foryErr.Kind())
+}
@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"hash/crc32"
+)
+
+// checksumFlag is bit 5 of the root header. When set, the header is followed
+// by the body length and its CRC-32C as two little-endian uint32 values.
+const (
+	checksumFlag = 1 << 5
+	checksumSize = 8
+)
+
+var castagnoliTable = crc32.MakeTable(crc32.Castagnoli)
+
+// reserveChecksum leaves room for the body length and checksum after the header.
+func reserveChecksum(ctx *WriteContext) {
+	ctx.checksumAt = ctx.buffer.writerIndex
+	ctx.buffer.WriteUint32(0)
+	ctx.buffer.WriteUint32(0)
+}
+
+// writeChecksum fills in the length and checksum reserved before the body.
+func writeChecksum(ctx *WriteContext) {
+	buf := ctx.buffer
+	body := buf.data[ctx.checksumAt+checksumSize : buf.writerIndex]
+	buf.PutInt32(ctx.checksumAt, int32(len(body)))
+	buf.PutInt32(ctx.checksumAt+4, int32(crc32.Checksum(body, castagnoliTable)))
+}
+
+// verifyChecksum checks the body that follows against its recorded checksum
+// without consuming it.
+func verifyChecksum(ctx *ReadContext) {
+	err := ctx.Err()
+	size := int(ctx.buffer.ReadUint32(err))
+	expected := ctx.buffer.ReadUint32(err)
+	if ctx.HasError() {
+		return
+	}
+	if !ctx.buffer.CheckReadable(size, err) {
+		return
+	}
+	start := ctx.buffer.readerIndex
+	if actual := crc32.Checksum(ctx.buffer.data[start:start+size], castagnoliTable); actual != expected {
+		ctx.SetError(DeserializationErrorf("payload checksum mismatch: expected 0x%08x, got 0x%08x", expected, actual))
+	}
+}
@@ -0,0 +1,101 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestWithChecksum(t *testing.T) {
+	for _, tc := range []struct {
+		writer []Option
+		reader []Option
+	}{
+		{
+			writer: []Option{WithXlang(true), WithChecksum(true)},
+			reader: []Option{WithXlang(true)},
+		},
+		{
+			writer: []Option{WithXlang(false), WithChecksum(true)},
+			reader: []Option{WithXlang(false)},
+		},
+		{
+			writer: []Option{WithXlang(true), WithChecksum(true), WithCompression(flateCodec{})},
+			reader: []Option{WithXlang(true), WithCompression(flateCodec{})},
+		},
+	} {
+		f := NewFory(tc.writer...)
+		require.NoError(t, f.RegisterStruct(Bar{}, 350))
+		value := &Bar{F1: 42, F2: "checked"}
+		data, err := f.Marshal(value)
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+
+		header, err := ParseHeader(data)
+		require.NoError(t, err)
+		require.True(t, header.Checksum)
+		require.Equal(t, f.Header(), header)
+
+		var got Bar
+		require.NoError(t, f.Unmarshal(data, &got))
+		require.Equal(t, *value, got)
+
+		// Readers verify checksums recorded in the header without the option.
+		reader := NewFory(tc.reader...)
+		require.NoError(t, reader.RegisterStruct(Bar{}, 350))
+		got = Bar{}
+		require.NoError(t, reader.Unmarshal(data, &got))
+		require.Equal(t, *value, got)
+
+		corrupted := append([]byte(nil), data...)
+		corrupted[len(corrupted)-1] ^= 0xff
+		err = reader.Unmarshal(corrupted, &got)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "checksum mismatch")
+
+		buf := NewByteBuffer(nil)
+		require.NoError(t, f.SerializeTo(buf, &Bar{F1: 1}))
+		require.NoError(t, f.SerializeTo(buf, &Bar{F1: 2}))
+		stream := NewInputStream(bytes.NewReader(buf.GetByteSlice(0, buf.WriterIndex())))
+		for _, want := range []int32{1, 2} {
+			var next Bar
+			require.NoError(t, f.DeserializeFromStream(stream, &next))
+			require.Equal(t, want, next.F1)
+		}
+	}
+}
+
+func TestWithChecksumHeaderless(t *testing.T) {
+	f := NewFory(WithHeader(false), WithChecksum(true))
+	data, err := f.Marshal("hello")
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+	var got string
+	require.NoError(t, f.Unmarshal(data, &got))
+	require.Equal(t, "hello", got)
+
+	data[len(data)-1] ^= 0xff
+	err = f.Unmarshal(data, &got)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+}
@@ -32,6 +32,9 @@ var (
 	pkgFlag     = flag.String("pkg", ".", "package directory to search for types (legacy mode)")
 	fileFlag    = flag.String("file", "", "source file to generate code for (new mode)")
 	forceFlag   = flag.Bool("force", false, "force regeneration by removing existing generated files first")
+	schemaFlag  = flag.String("schema", "", "exported schema JSON to generate Go types from")
+	outFlag     = flag.String("out", "", "output file for -schema (default <schema>_types.go)")
+	packageFlag = flag.String("package", "", "package name for -schema output (default output directory name)")
 	helpFlag    = flag.Bool("help", false, "show help message")
 	versionFlag = flag.Bool("version", false, "show version information")
 )
@@ -57,6 +60,10 @@ func main() {
 		PackageDir: *pkgFlag,
 		SourceFile: *fileFlag,
 		Force:      *forceFlag,
+
+		SchemaFile:  *schemaFlag,
+		Output:      *outFlag,
+		PackageName: *packageFlag,
 	}
 
 	// Run the code generator with smart error handling
@@ -98,6 +105,12 @@ Options:
         comma-separated list of types to generate code for (optional if using //fory:generate comments)
   -force
         force regeneration by removing existing generated files first
+  -schema string
+        exported schema JSON to generate Go types from
+  -out string
+        output file for -schema (default <schema>_types.go)
+  -package string
+        package name for -schema output (default output directory name)
   -help
         show this help message
   -version
@@ -113,6 +126,9 @@ Examples:
   # Generate for specific types in a directory
   fory -pkg ./models -type "User,Order"
 
+  # Generate Go types and registration code from exported schemas
+  fory -schema schemas.json -out models/types.go
+
 Installation:
   go install github.com/apache/fory/go/fory/cmd/fory
 
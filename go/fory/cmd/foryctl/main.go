@@ -0,0 +1,196 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Command foryctl inspects Fory payloads, for example when debugging data
+// written by services in other languages.
+package main
+
+import (
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/fory/go/fory"
+)
+
+const usage = `foryctl - inspect Fory payloads
+
+Usage:
+  foryctl <command> [options] <file>
+
+Commands:
+  inspect    print the header and the tree of types and values
+  json       convert the payload to JSON
+  validate   check that the payload decodes completely
+
+Use - as the file to read from stdin.
+
+Options:
+  -hex
+        the file holds the payload as hex text, as often found in logs
+
+Examples:
+  foryctl inspect order.bin
+  foryctl json -hex order.hex | jq .
+  foryctl validate order.bin
+`
+
+func main() {
+	if len(os.Args) < 2 || os.Args[1] == "-help" || os.Args[1] == "--help" || os.Args[1] == "help" {
+		fmt.Print(usage)
+		return
+	}
+	if err := run(os.Args[1], os.Args[2:], os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "foryctl: %v\n", err)
+		os.Exit(1)
+	}
+}
+
+func run(command string, args []string, out io.Writer) error {
+	flags := flag.NewFlagSet(command, flag.ContinueOnError)
+	hexInput := flags.Bool("hex", false, "the file holds the payload as hex text")
+	if err := flags.Parse(args); err != nil {
+		return err
+	}
+	if flags.NArg() != 1 {
+		return fmt.Errorf("%s expects one payload file, got %d arguments", command, flags.NArg())
+	}
+	data, err := readPayload(flags.Arg(0), *hexInput)
+	if err != nil {
+		return err
+	}
+	switch command {
+	case "inspect":
+		tree, err := fory.Dump(data)
+		fmt.Fprint(out, tree)
+		return err
+	case "json":
+		value, err := fory.DumpValue(data)
+		if err != nil {
+			return err
+		}
+		converted, err := jsonValue(value, map[uintptr]bool{})
+		if err != nil {
+			return err
+		}
+		encoder := json.NewEncoder(out)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(converted)
+	case "validate":
+		if _, err := fory.DumpValue(data); err != nil {
+			return err
+		}
+		fmt.Fprintf(out, "ok: %d bytes\n", len(data))
+		return nil
+	default:
+		return fmt.Errorf("unknown command %q, run foryctl -help for usage", command)
+	}
+}
+
+func readPayload(path string, hexInput bool) ([]byte, error) {
+	var data []byte
+	var err error
+	if path == "-" {
+		data, err = io.ReadAll(os.Stdin)
+	} else {
+		data, err = os.ReadFile(path)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if hexInput {
+		text := strings.Join(strings.Fields(string(data)), "")
+		if data, err = hex.DecodeString(text); err != nil {
+			return nil, fmt.Errorf("decode hex payload: %w", err)
+		}
+	}
+	return data, nil
+}
+
+// jsonValue converts a value from fory.DumpValue to one encoding/json can
+// encode. visiting holds the maps on the current path to detect cycles.
+func jsonValue(value any, visiting map[uintptr]bool) (any, error) {
+	switch v := value.(type) {
+	case map[string]any:
+		ptr := reflect.ValueOf(v).Pointer()
+		if visiting[ptr] {
+			return nil, fmt.Errorf("payload contains a reference cycle, which JSON cannot represent")
+		}
+		visiting[ptr] = true
+		defer delete(visiting, ptr)
+		result := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted, err := jsonValue(elem, visiting)
+			if err != nil {
+				return nil, err
+			}
+			result[key] = converted
+		}
+		return result, nil
+	case map[any]any:
+		ptr := reflect.ValueOf(v).Pointer()
+		if visiting[ptr] {
+			return nil, fmt.Errorf("payload contains a reference cycle, which JSON cannot represent")
+		}
+		visiting[ptr] = true
+		defer delete(visiting, ptr)
+		result := make(map[string]any, len(v))
+		for key, elem := range v {
+			converted, err := jsonValue(elem, visiting)
+			if err != nil {
+				return nil, err
+			}
+			result[fmt.Sprint(key)] = converted
+		}
+		return result, nil
+	case []any:
+		result := make([]any, len(v))
+		for i, elem := range v {
+			converted, err := jsonValue(elem, visiting)
+			if err != nil {
+				return nil, err
+			}
+			result[i] = converted
+		}
+		return result, nil
+	case float32:
+		return jsonFloat(float64(v)), nil
+	case float64:
+		return jsonFloat(v), nil
+	case fory.Date:
+		return fmt.Sprintf("%04d-%02d-%02d", v.Year, v.Month, v.Day), nil
+	case fory.Decimal, time.Duration:
+		return fmt.Sprint(v), nil
+	default:
+		return v, nil
+	}
+}
+
+// jsonFloat spells out the values JSON numbers cannot hold.
+func jsonFloat(f float64) any {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return fmt.Sprint(f)
+	}
+	return f
+}
@@ -904,13 +904,13 @@ func writeMapReadChunks(buf *bytes.Buffer, mapType *types.Map, fieldAccess strin
 
 	// Parse header flags
 	fmt.Fprintf(buf, "%s\ttrackKeyRef := (kvHeader & 0x1) != 0\n", indent)
-	fmt.Fprintf(buf, "%s\tkeyNotDeclared := (kvHeader & 0x4) != 0\n", indent)
+	fmt.Fprintf(buf, "%s\tkeyDeclared := (kvHeader & 0x4) != 0\n", indent)
 	fmt.Fprintf(buf, "%s\ttrackValueRef := (kvHeader & 0x8) != 0\n", indent)
-	fmt.Fprintf(buf, "%s\tvalueNotDeclared := (kvHeader & 0x20) != 0\n", indent)
+	fmt.Fprintf(buf, "%s\tvalueDeclared := (kvHeader & 0x20) != 0\n", indent)
 	fmt.Fprintf(buf, "%s\t_ = trackKeyRef\n", indent)
-	fmt.Fprintf(buf, "%s\t_ = keyNotDeclared\n", indent)
+	fmt.Fprintf(buf, "%s\t_ = keyDeclared\n", indent)
 	fmt.Fprintf(buf, "%s\t_ = trackValueRef\n", indent)
-	fmt.Fprintf(buf, "%s\t_ = valueNotDeclared\n", indent)
+	fmt.Fprintf(buf, "%s\t_ = valueDeclared\n", indent)
 
 	// ReadData key-value pairs in this chunk
 	fmt.Fprintf(buf, "%s\tfor i := 0; i < chunkSize; i++ {\n", indent)
@@ -642,9 +642,10 @@ func writeMapChunksCode(buf *bytes.Buffer, keyType, valueType types.Type, fieldA
 			fmt.Fprintf(buf, "%s\t\tkvHeader |= 0x1 // track key ref\n", indent)
 			fmt.Fprintf(buf, "%s\t}\n", indent)
 		}
-	} else {
-		// For any keys, always set not declared type flag
-		fmt.Fprintf(buf, "%s\tkvHeader |= 0x4 // key type not declared\n", indent)
+		// Basic keys are written inline without type info
+		if isBasicType(keyType) {
+			fmt.Fprintf(buf, "%s\tkvHeader |= 0x4 // key type declared\n", indent)
+		}
 	}
 
 	if !valueIsInterface {
@@ -654,9 +655,10 @@ func writeMapChunksCode(buf *bytes.Buffer, keyType, valueType types.Type, fieldA
 			fmt.Fprintf(buf, "%s\t\tkvHeader |= 0x8 // track value ref\n", indent)
 			fmt.Fprintf(buf, "%s\t}\n", indent)
 		}
-	} else {
-		// For any values, always set not declared type flag
-		fmt.Fprintf(buf, "%s\tkvHeader |= 0x20 // value type not declared\n", indent)
+		// Basic values are written inline without type info
+		if isBasicType(valueType) {
+			fmt.Fprintf(buf, "%s\tkvHeader |= 0x20 // value type declared\n", indent)
+		}
 	}
 
 	// WriteData map elements in chunks
@@ -737,6 +739,12 @@ func isReferencableType(t types.Type) bool {
 	return false
 }
 
+// isBasicType reports whether map entries of t are written inline
+func isBasicType(t types.Type) bool {
+	_, ok := t.Underlying().(*types.Basic)
+	return ok
+}
+
 // generateMapKeyWrite generates code to write a map key
 func generateMapKeyWrite(buf *bytes.Buffer, keyType types.Type, varName string) error {
 	// For basic types, match reflection's serializer behavior
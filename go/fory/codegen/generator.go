@@ -39,10 +39,18 @@ type GeneratorOptions struct {
 	PackageDir string // package directory to search for types
 	SourceFile string // source file to generate code for (new mode)
 	Force      bool   // force regeneration by removing existing files first
+
+	SchemaFile  string // exported schema JSON to generate Go types from
+	Output      string // output file for schema mode
+	PackageName string // package name for schema mode output
 }
 
 // Run executes the code generator with the given options
 func Run(opts *GeneratorOptions) error {
+	if opts.SchemaFile != "" {
+		return runSchemaMode(opts)
+	}
+
 	// If force flag is set, clean up existing files first
 	if opts.Force {
 		logger.Printf("Force flag detected, cleaning up existing generated files...")
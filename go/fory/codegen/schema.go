@@ -0,0 +1,464 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package codegen
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/apache/fory/go/fory"
+)
+
+// GenerateFromSchemas emits Go declarations for the types in a schema document
+// written by fory.Fory.ExportSchemas, or by another implementation in the same
+// format, together with a RegisterTypes function that registers them. Field
+// types, encodings, tags, nullability and ref tracking follow the schema, so
+// the generated structs read and write the same payloads as the exporter.
+func GenerateFromSchemas(data []byte, pkgName string) ([]byte, error) {
+	var schemas fory.Schemas
+	if err := json.Unmarshal(data, &schemas); err != nil {
+		return nil, fmt.Errorf("parsing schemas: %w", err)
+	}
+	g := &schemaGenerator{
+		names:   map[string]string{},
+		ids:     map[uint32]string{},
+		imports: map[string]bool{},
+	}
+	if err := g.nameTypes(schemas.Types); err != nil {
+		return nil, err
+	}
+	var body bytes.Buffer
+	for _, schema := range schemas.Types {
+		if err := g.writeType(&body, schema); err != nil {
+			return nil, err
+		}
+	}
+	g.writeRegister(&body, schemas.Types)
+
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by forygen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n\n", pkgName)
+	imports := []string{"github.com/apache/fory/go/fory"}
+	for path := range g.imports {
+		imports = append(imports, path)
+	}
+	sort.Strings(imports)
+	fmt.Fprintf(&buf, "import (\n")
+	for _, path := range imports {
+		fmt.Fprintf(&buf, "\t%q\n", path)
+	}
+	fmt.Fprintf(&buf, ")\n\n")
+	buf.Write(body.Bytes())
+	formatted, err := format.Source(buf.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("formatting generated code: %w", err)
+	}
+	return formatted, nil
+}
+
+// runSchemaMode generates Go types from opts.SchemaFile.
+func runSchemaMode(opts *GeneratorOptions) error {
+	data, err := os.ReadFile(opts.SchemaFile)
+	if err != nil {
+		return err
+	}
+	output := opts.Output
+	if output == "" {
+		output = strings.TrimSuffix(opts.SchemaFile, filepath.Ext(opts.SchemaFile)) + "_types.go"
+	}
+	pkgName := opts.PackageName
+	if pkgName == "" {
+		dir, err := filepath.Abs(filepath.Dir(output))
+		if err != nil {
+			return err
+		}
+		pkgName = filepath.Base(dir)
+	}
+	code, err := GenerateFromSchemas(data, pkgName)
+	if err != nil {
+		return err
+	}
+	logger.Printf("Generated %s from %s", output, opts.SchemaFile)
+	return os.WriteFile(output, code, 0644)
+}
+
+type schemaGenerator struct {
+	// names and ids map registered names and user type IDs to Go type names.
+	names   map[string]string
+	ids     map[uint32]string
+	imports map[string]bool
+}
+
+// nameTypes picks a Go name for every type before fields refer to them.
+func (g *schemaGenerator) nameTypes(schemas []fory.TypeSchema) error {
+	owners := map[string]string{}
+	for _, schema := range schemas {
+		if !isGeneratedKind(schema.Kind) {
+			return fmt.Errorf("type %s has kind %s, which needs a hand-written Go type", schemaLabel(schema.Name, schema.ID), schema.Kind)
+		}
+		name := goTypeName(schema)
+		if name == "" {
+			return fmt.Errorf("cannot derive a Go type name for %s", schemaLabel(schema.Name, schema.ID))
+		}
+		label := schemaLabel(schema.Name, schema.ID)
+		if owner, ok := owners[name]; ok {
+			return fmt.Errorf("types %s and %s both map to Go type %s", owner, label, name)
+		}
+		owners[name] = label
+		if schema.Name != "" {
+			g.names[schema.Name] = name
+		} else if schema.ID != nil {
+			g.ids[*schema.ID] = name
+		}
+	}
+	return nil
+}
+
+func (g *schemaGenerator) writeType(buf *bytes.Buffer, schema fory.TypeSchema) error {
+	name := g.lookup(schema.Name, schema.ID)
+	fmt.Fprintf(buf, "// %s is the Go form of %s.\n", name, schemaLabel(schema.Name, schema.ID))
+	if strings.HasSuffix(schema.Kind, "ENUM") {
+		fmt.Fprintf(buf, "type %s int32\n\n", name)
+		return nil
+	}
+	fmt.Fprintf(buf, "type %s struct {\n", name)
+	for _, field := range schema.Fields {
+		goName, err := goFieldName(field)
+		if err != nil {
+			return fmt.Errorf("type %s: %w", name, err)
+		}
+		goType, tags, err := g.fieldDecl(field)
+		if err != nil {
+			return fmt.Errorf("type %s field %s: %w", name, field.Name, err)
+		}
+		if len(tags) > 0 {
+			fmt.Fprintf(buf, "\t%s %s `fory:\"%s\"`\n", goName, goType, strings.Join(tags, ","))
+		} else {
+			fmt.Fprintf(buf, "\t%s %s\n", goName, goType)
+		}
+	}
+	fmt.Fprintf(buf, "}\n\n")
+	return nil
+}
+
+func (g *schemaGenerator) writeRegister(buf *bytes.Buffer, schemas []fory.TypeSchema) {
+	fmt.Fprintf(buf, "// RegisterTypes registers the generated types with f.\n")
+	fmt.Fprintf(buf, "func RegisterTypes(f *fory.Fory) error {\n")
+	for _, schema := range schemas {
+		name := g.lookup(schema.Name, schema.ID)
+		value := name + "{}"
+		method := "Struct"
+		if strings.HasSuffix(schema.Kind, "ENUM") {
+			value = name + "(0)"
+			method = "Enum"
+		}
+		if schema.Name != "" {
+			fmt.Fprintf(buf, "\tif err := f.Register%sByName(%s, %q); err != nil {\n", method, value, schema.Name)
+		} else {
+			fmt.Fprintf(buf, "\tif err := f.Register%s(%s, %d); err != nil {\n", method, value, *schema.ID)
+		}
+		fmt.Fprintf(buf, "\t\treturn err\n\t}\n")
+	}
+	fmt.Fprintf(buf, "\treturn nil\n}\n")
+}
+
+// fieldDecl returns the Go type of a field and the fory tag options needed to
+// reproduce its schema.
+func (g *schemaGenerator) fieldDecl(field fory.FieldSchema) (string, []string, error) {
+	var tags []string
+	if field.Tag != nil {
+		tags = append(tags, fmt.Sprintf("id=%d", *field.Tag))
+	}
+	goType, err := g.goType(field.Type, field.Nullable)
+	if err != nil {
+		return "", nil, err
+	}
+	if encoding := scalarEncoding(field.Type.Kind); encoding != "" {
+		tags = append(tags, "encoding="+encoding)
+	} else if hint, ok := typeHint(field.Type); ok {
+		tags = append(tags, "type="+hint)
+	}
+	// Pointers are nullable by default; other reference-like types are not.
+	if field.Nullable && !strings.HasPrefix(goType, "*") {
+		tags = append(tags, "nullable")
+	}
+	if field.TrackRef {
+		tags = append(tags, "ref")
+	}
+	return goType, tags, nil
+}
+
+// goType maps a field type to Go. Nullable scalars and structs become pointers.
+func (g *schemaGenerator) goType(t fory.FieldType, nullable bool) (string, error) {
+	switch t.Kind {
+	case "LIST", "SET", "MAP":
+	default:
+		if base, ok := scalarGoTypes[t.Kind]; ok {
+			if strings.Contains(base, ".") {
+				g.imports[importPaths[base[:strings.IndexByte(base, '.')]]] = true
+			}
+			if nullable && !strings.HasPrefix(base, "[]") {
+				return "*" + base, nil
+			}
+			return base, nil
+		}
+	}
+	switch t.Kind {
+	case "UNKNOWN":
+		return "any", nil
+	case "LIST", "SET":
+		if t.Element == nil {
+			return "", fmt.Errorf("%s has no element type", t.Kind)
+		}
+		elem, err := g.goType(*t.Element, isStructKind(t.Element.Kind))
+		if err != nil {
+			return "", err
+		}
+		if t.Kind == "SET" {
+			return "fory.Set[" + elem + "]", nil
+		}
+		return "[]" + elem, nil
+	case "MAP":
+		if t.Key == nil || t.Value == nil {
+			return "", fmt.Errorf("MAP has no key or value type")
+		}
+		key, err := g.goType(*t.Key, false)
+		if err != nil {
+			return "", err
+		}
+		value, err := g.goType(*t.Value, isStructKind(t.Value.Kind))
+		if err != nil {
+			return "", err
+		}
+		return "map[" + key + "]" + value, nil
+	case "ENUM", "NAMED_ENUM", "STRUCT", "COMPATIBLE_STRUCT", "NAMED_STRUCT", "NAMED_COMPATIBLE_STRUCT":
+		name := g.lookup(t.Name, t.ID)
+		if name == "" {
+			return "", fmt.Errorf("%s refers to %s, which is not in the schemas", t.Kind, schemaLabel(t.Name, t.ID))
+		}
+		if nullable {
+			return "*" + name, nil
+		}
+		return name, nil
+	default:
+		return "", fmt.Errorf("kind %s is not supported", t.Kind)
+	}
+}
+
+func (g *schemaGenerator) lookup(name string, id *uint32) string {
+	if name != "" {
+		return g.names[name]
+	}
+	if id != nil {
+		return g.ids[*id]
+	}
+	return ""
+}
+
+var scalarGoTypes = map[string]string{
+	"BOOL":           "bool",
+	"INT8":           "int8",
+	"INT16":          "int16",
+	"INT32":          "int32",
+	"VARINT32":       "int32",
+	"INT64":          "int64",
+	"VARINT64":       "int64",
+	"TAGGED_INT64":   "int64",
+	"UINT8":          "uint8",
+	"UINT16":         "uint16",
+	"UINT32":         "uint32",
+	"VAR_UINT32":     "uint32",
+	"UINT64":         "uint64",
+	"VAR_UINT64":     "uint64",
+	"TAGGED_UINT64":  "uint64",
+	"FLOAT16":        "float16.Float16",
+	"BFLOAT16":       "bfloat16.BFloat16",
+	"FLOAT32":        "float32",
+	"FLOAT64":        "float64",
+	"STRING":         "string",
+	"BINARY":         "[]byte",
+	"DATE":           "fory.Date",
+	"TIMESTAMP":      "time.Time",
+	"DURATION":       "time.Duration",
+	"DECIMAL":        "fory.Decimal",
+	"BOOL_ARRAY":     "[]bool",
+	"INT8_ARRAY":     "[]int8",
+	"INT16_ARRAY":    "[]int16",
+	"INT32_ARRAY":    "[]int32",
+	"INT64_ARRAY":    "[]int64",
+	"UINT8_ARRAY":    "[]uint8",
+	"UINT16_ARRAY":   "[]uint16",
+	"UINT32_ARRAY":   "[]uint32",
+	"UINT64_ARRAY":   "[]uint64",
+	"FLOAT16_ARRAY":  "[]float16.Float16",
+	"BFLOAT16_ARRAY": "[]bfloat16.BFloat16",
+	"FLOAT32_ARRAY":  "[]float32",
+	"FLOAT64_ARRAY":  "[]float64",
+}
+
+var importPaths = map[string]string{
+	"[]float16":  "github.com/apache/fory/go/fory/float16",
+	"[]bfloat16": "github.com/apache/fory/go/fory/bfloat16",
+	"float16":    "github.com/apache/fory/go/fory/float16",
+	"bfloat16":   "github.com/apache/fory/go/fory/bfloat16",
+	"fory":       "github.com/apache/fory/go/fory",
+	"time":       "time",
+}
+
+// scalarEncoding returns the encoding tag for integer kinds whose encoding
+// differs from the Go default, which is varint for 32 and 64 bit integers.
+func scalarEncoding(kind string) string {
+	switch kind {
+	case "INT32", "INT64", "UINT32", "UINT64":
+		return "fixed"
+	case "TAGGED_INT64", "TAGGED_UINT64":
+		return "tagged"
+	default:
+		return ""
+	}
+}
+
+// typeHint returns a type= tag value for fields whose declared type Go would
+// not infer from the generated field type: primitive arrays and binary, which
+// Go otherwise writes as lists, and elements with non-default encodings.
+func typeHint(t fory.FieldType) (string, bool) {
+	switch t.Kind {
+	case "LIST", "SET":
+		if t.Element == nil {
+			return "", false
+		}
+		elem, needed := typeHint(*t.Element)
+		return strings.ToLower(t.Kind) + "(element=" + elem + ")", needed
+	case "MAP":
+		if t.Key == nil || t.Value == nil {
+			return "", false
+		}
+		key, keyNeeded := typeHint(*t.Key)
+		value, valueNeeded := typeHint(*t.Value)
+		return "map(key=" + key + ",value=" + value + ")", keyNeeded || valueNeeded
+	case "BINARY":
+		return "bytes", true
+	}
+	if strings.HasSuffix(t.Kind, "_ARRAY") {
+		return "array(element=" + strings.ToLower(strings.TrimSuffix(t.Kind, "_ARRAY")) + ")", true
+	}
+	if base, ok := scalarGoTypes[t.Kind]; ok && !strings.HasPrefix(base, "[]") && !strings.Contains(base, ".") && base != "string" {
+		if encoding := scalarEncoding(t.Kind); encoding != "" {
+			return base + "(encoding=" + encoding + ")", true
+		}
+		return base, false
+	}
+	return "_", false
+}
+
+func isStructKind(kind string) bool {
+	return strings.HasSuffix(kind, "STRUCT")
+}
+
+func isGeneratedKind(kind string) bool {
+	return isStructKind(kind) || strings.HasSuffix(kind, "ENUM")
+}
+
+func schemaLabel(name string, id *uint32) string {
+	if name != "" {
+		return name
+	}
+	if id != nil {
+		return fmt.Sprintf("type id %d", *id)
+	}
+	return "unnamed type"
+}
+
+// goTypeName derives an exported Go name from the last segment of the
+// registered name, the Go type the exporter used, or the user type ID.
+func goTypeName(schema fory.TypeSchema) string {
+	source := schema.Name
+	if source == "" {
+		source = schema.GoType
+	}
+	if source != "" {
+		if i := strings.LastIndexAny(source, "./"); i >= 0 {
+			source = source[i+1:]
+		}
+		return exportedName(source)
+	}
+	if schema.ID != nil {
+		return fmt.Sprintf("Type%d", *schema.ID)
+	}
+	return ""
+}
+
+// exportedName converts a snake_case or camelCase identifier to an exported Go
+// name, dropping characters Go identifiers cannot hold.
+func exportedName(s string) string {
+	var b strings.Builder
+	upper := true
+	for _, r := range s {
+		if !unicode.IsLetter(r) && !unicode.IsDigit(r) {
+			upper = true
+			continue
+		}
+		if upper {
+			r = unicode.ToUpper(r)
+			upper = false
+		}
+		b.WriteRune(r)
+	}
+	name := b.String()
+	if name == "" || !unicode.IsLetter([]rune(name)[0]) {
+		return ""
+	}
+	return name
+}
+
+// goFieldName returns a Go field name whose snake_case form is the schema
+// field name, so the generated struct matches fields by name.
+func goFieldName(field fory.FieldSchema) (string, error) {
+	var b strings.Builder
+	for i, part := range strings.Split(field.Name, "_") {
+		if part == "" || (i > 0 && !unicode.IsLower([]rune(part)[0])) {
+			// Keep underscores that SnakeCase would not produce, as in field_1.
+			b.WriteByte('_')
+		}
+		b.WriteString(capitalize(part))
+	}
+	name := b.String()
+	if fory.SnakeCase(name) != field.Name || !unicode.IsUpper([]rune(name)[0]) {
+		if field.Tag != nil {
+			return exportedName(field.Name), nil
+		}
+		return "", fmt.Errorf("field %q has no Go name that maps back to it; give it a tag ID", field.Name)
+	}
+	return name, nil
+}
+
+func capitalize(s string) string {
+	if s == "" {
+		return s
+	}
+	r := []rune(s)
+	r[0] = unicode.ToUpper(r[0])
+	return string(r)
+}
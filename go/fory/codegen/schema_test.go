@@ -0,0 +1,86 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package codegen
+
+import (
+	"strings"
+	"testing"
+)
+
+const testSchemas = `{
+  "xlang": true,
+  "compatible": true,
+  "types": [
+    {"id": 7, "kind": "ENUM", "go_type": "example.Status"},
+    {
+      "name": "com.example.user_profile",
+      "kind": "NAMED_COMPATIBLE_STRUCT",
+      "fields": [
+        {"name": "user_id", "type": {"kind": "INT64"}, "nullable": false, "track_ref": false},
+        {"name": "nick", "type": {"kind": "STRING"}, "nullable": true, "track_ref": false},
+        {"name": "age", "type": {"kind": "VARINT32"}, "nullable": true, "track_ref": false},
+        {"name": "status", "type": {"kind": "ENUM", "id": 7}, "nullable": false, "track_ref": false},
+        {"name": "scores", "type": {"kind": "INT32_ARRAY"}, "nullable": false, "track_ref": false},
+        {"name": "friends", "tag": 4, "type": {"kind": "LIST", "element": {"kind": "NAMED_COMPATIBLE_STRUCT", "name": "com.example.user_profile"}}, "nullable": false, "track_ref": true},
+        {"name": "attrs", "type": {"kind": "MAP", "key": {"kind": "STRING"}, "value": {"kind": "TAGGED_UINT64"}}, "nullable": true, "track_ref": false}
+      ]
+    }
+  ]
+}`
+
+func TestGenerateFromSchemas(t *testing.T) {
+	code, err := GenerateFromSchemas([]byte(testSchemas), "models")
+	if err != nil {
+		t.Fatalf("GenerateFromSchemas: %v", err)
+	}
+	want := []string{
+		"package models",
+		"type Status int32",
+		"type UserProfile struct {",
+		"UserId  int64 `fory:\"encoding=fixed\"`",
+		"Nick    *string",
+		"Age     *int32",
+		"Status  Status",
+		"Scores  []int32           `fory:\"type=array(element=int32)\"`",
+		"Friends []*UserProfile    `fory:\"id=4,ref\"`",
+		"Attrs   map[string]uint64 `fory:\"type=map(key=_,value=uint64(encoding=tagged)),nullable\"`",
+		"f.RegisterEnum(Status(0), 7)",
+		"f.RegisterStructByName(UserProfile{}, \"com.example.user_profile\")",
+	}
+	for _, line := range want {
+		if !strings.Contains(string(code), line) {
+			t.Errorf("generated code is missing %q:\n%s", line, code)
+		}
+	}
+}
+
+func TestGenerateFromSchemasErrors(t *testing.T) {
+	cases := map[string]string{
+		"missing type": `{"types": [{"name": "a.B", "kind": "NAMED_STRUCT", "fields": [
+			{"name": "c", "type": {"kind": "NAMED_STRUCT", "name": "a.C"}}]}]}`,
+		"duplicate name": `{"types": [{"name": "a.B", "kind": "NAMED_STRUCT"}, {"name": "c.B", "kind": "NAMED_STRUCT"}]}`,
+		"unmappable field": `{"types": [{"name": "a.B", "kind": "NAMED_STRUCT", "fields": [
+			{"name": "userName", "type": {"kind": "STRING"}}]}]}`,
+		"extension": `{"types": [{"name": "a.B", "kind": "NAMED_EXT"}]}`,
+	}
+	for name, input := range cases {
+		if _, err := GenerateFromSchemas([]byte(input), "models"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
@@ -0,0 +1,52 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type codegenOnlyStruct struct {
+	ID int32
+}
+
+func TestCodegenOnlyRejectsReflectStructs(t *testing.T) {
+	f := New()
+	err := f.RegisterStruct(codegenOnlyStruct{}, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "has no generated serializer")
+	require.Error(t, f.RegisterStructByName(codegenOnlyStruct{}, "example.Struct"))
+
+	_, err = New(WithXlang(false)).Marshal(struct{ ID int32 }{1})
+	require.Error(t, err)
+}
+
+func TestCodegenOnlyValues(t *testing.T) {
+	f := New()
+	require.False(t, f.config.Compatible)
+	value := map[string][]int64{"a": {1, 2}}
+	data, err := f.Marshal(value)
+	require.NoError(t, err)
+	var result map[string][]int64
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, value, result)
+}
@@ -0,0 +1,153 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+)
+
+// Codec compresses payload bodies for WithCompression. Implementations
+// typically wrap lz4, zstd or snappy.
+type Codec interface {
+	// ID identifies the codec in the root header. It must be in [1, 7] and
+	// match between writer and reader.
+	ID() uint8
+	// Compress appends the compressed form of src to dst.
+	Compress(dst, src []byte) ([]byte, error)
+	// Decompress appends the decompressed form of src to dst. Once the
+	// decompressed form exceeds maxSize bytes it should stop, either failing
+	// or returning what it has; the reader rejects anything longer.
+	Decompress(dst, src []byte, maxSize int) ([]byte, error)
+}
+
+// Bits 2-4 of the root header hold the ID of the codec that compressed the body.
+const (
+	codecShift = 2
+	codecMask  = 0b111 << codecShift
+	maxCodecID = codecMask >> codecShift
+)
+
+// compressBody replaces the body written after the root header with its
+// compressed form, prefixed by the compressed length so that stream readers
+// know how much to consume.
+func compressBody(ctx *WriteContext) error {
+	if id := ctx.codec.ID(); id == 0 || id > maxCodecID {
+		return fmt.Errorf("codec ID %d is outside [1, %d]", id, maxCodecID)
+	}
+	buf := ctx.buffer
+	compressed, err := ctx.codec.Compress(ctx.compressed[:0], buf.data[ctx.bodyStart:buf.writerIndex])
+	if err != nil {
+		return fmt.Errorf("compress payload: %w", err)
+	}
+	ctx.compressed = compressed
+	buf.writerIndex = ctx.bodyStart
+	buf.WriteVarUint32(uint32(len(compressed)))
+	buf.WriteBinary(compressed)
+	return nil
+}
+
+// inflateBody reads a compressed body from the current buffer and switches the
+// context to its decompressed form until Reset.
+func inflateBody(ctx *ReadContext, codecID uint8) {
+	if ctx.codec == nil || ctx.codec.ID() != codecID {
+		ctx.SetError(DeserializationErrorf("payload is compressed with codec %d, which is not configured", codecID))
+		return
+	}
+	err := ctx.Err()
+	size := int(ctx.buffer.ReadVarUint32(err))
+	if ctx.HasError() {
+		return
+	}
+	if size > ctx.maxBinarySize {
+		ctx.SetError(MaxBinarySizeExceededError(size, ctx.maxBinarySize))
+		return
+	}
+	compressed := ctx.buffer.ReadBinary(size, err)
+	if ctx.HasError() {
+		return
+	}
+	// A small body can inflate to gigabytes, so the output is held to the
+	// same limits as the values decoded from it.
+	limit, budgeted := ctx.maxBinarySize, false
+	if ctx.budgetLeft < limit {
+		limit, budgeted = ctx.budgetLeft, true
+	}
+	// Decoded values may alias the body, so it is not reused across payloads.
+	body, decErr := ctx.codec.Decompress(nil, compressed, limit)
+	if decErr != nil {
+		ctx.SetError(DeserializationErrorf("decompress payload: %v", decErr))
+		return
+	}
+	if len(body) > limit {
+		if budgeted {
+			ctx.SetError(DecodeBudgetExceededError(ctx.decodeBudget))
+		} else {
+			ctx.SetError(MaxBinarySizeExceededError(len(body), ctx.maxBinarySize))
+		}
+		return
+	}
+	ctx.inflated.WrapReadOnly(body)
+	ctx.compressedBuffer = ctx.buffer
+	ctx.buffer = &ctx.inflated
+}
+
+// MetaCompressor compresses the type metadata shared in compatible mode, see
+// WithMetaCompressor. Implementations must be safe for concurrent use.
+type MetaCompressor interface {
+	// Compress appends the compressed form of src to dst.
+	Compress(dst, src []byte) ([]byte, error)
+	// Decompress appends the decompressed form of src to dst, failing once
+	// the decompressed form exceeds maxSize bytes.
+	Decompress(dst, src []byte, maxSize int) ([]byte, error)
+}
+
+// DeflateMetaCompressor compresses type metadata as a zlib stream, the format
+// of Java's DeflaterMetaCompressor.
+type DeflateMetaCompressor struct{}
+
+func (DeflateMetaCompressor) Compress(dst, src []byte) ([]byte, error) {
+	out := bytes.NewBuffer(dst)
+	w := zlib.NewWriter(out)
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (DeflateMetaCompressor) Decompress(dst, src []byte, maxSize int) ([]byte, error) {
+	r, err := zlib.NewReader(bytes.NewReader(src))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Close()
+	out := bytes.NewBuffer(dst)
+	n, err := io.Copy(out, io.LimitReader(r, int64(maxSize)+1))
+	if err != nil {
+		return nil, err
+	}
+	if n > int64(maxSize) {
+		return nil, fmt.Errorf("decompressed type metadata exceeds %d bytes", maxSize)
+	}
+	return out.Bytes(), nil
+}
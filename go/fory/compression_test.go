@@ -0,0 +1,234 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"bytes"
+	"compress/flate"
+	"errors"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type flateCodec struct{}
+
+func (flateCodec) ID() uint8 { return 1 }
+
+func (flateCodec) Compress(dst, src []byte) ([]byte, error) {
+	out := bytes.NewBuffer(dst)
+	w, err := flate.NewWriter(out, flate.BestSpeed)
+	if err != nil {
+		return nil, err
+	}
+	if _, err := w.Write(src); err != nil {
+		return nil, err
+	}
+	if err := w.Close(); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func (flateCodec) Decompress(dst, src []byte, maxSize int) ([]byte, error) {
+	out := bytes.NewBuffer(dst)
+	if _, err := io.Copy(out, io.LimitReader(flate.NewReader(bytes.NewReader(src)), int64(maxSize)+1)); err != nil {
+		return nil, err
+	}
+	return out.Bytes(), nil
+}
+
+func TestWithCompression(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		plain := NewFory(WithXlang(xlang))
+		compressed := NewFory(WithXlang(xlang), WithCompression(flateCodec{}))
+		require.NoError(t, plain.RegisterStruct(Bar{}, 340))
+		require.NoError(t, compressed.RegisterStruct(Bar{}, 340))
+
+		value := make([]string, 1000)
+		for i := range value {
+			value[i] = "repeated payload text"
+		}
+		plainData, err := plain.Marshal(value)
+		require.NoError(t, err)
+		plainLen := len(plainData)
+		data, err := compressed.Marshal(value)
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+		require.Less(t, len(data), plainLen/4)
+
+		header, err := ParseHeader(data)
+		require.NoError(t, err)
+		require.Equal(t, Header{Xlang: xlang, Codec: 1}, header)
+		require.NoError(t, compressed.CheckHeader(header))
+		require.Error(t, plain.CheckHeader(header))
+
+		var got []string
+		require.NoError(t, compressed.Unmarshal(data, &got))
+		require.Equal(t, value, got)
+		require.Error(t, plain.Unmarshal(data, &got), "reader without the codec must reject the payload")
+
+		// Compressing instances still read uncompressed payloads.
+		plainData, err = plain.Marshal(&Bar{F1: 1, F2: "plain"})
+		require.NoError(t, err)
+		var bar Bar
+		require.NoError(t, compressed.Unmarshal(plainData, &bar))
+		require.Equal(t, Bar{F1: 1, F2: "plain"}, bar)
+
+		// Sequential payloads in one buffer and in a stream.
+		buf := NewByteBuffer(nil)
+		require.NoError(t, compressed.SerializeTo(buf, &Bar{F1: 1, F2: "a"}))
+		require.NoError(t, compressed.SerializeTo(buf, &Bar{F1: 2, F2: "b"}))
+		stream := NewInputStream(bytes.NewReader(append([]byte(nil), buf.GetByteSlice(0, buf.WriterIndex())...)))
+		for _, want := range []Bar{{F1: 1, F2: "a"}, {F1: 2, F2: "b"}} {
+			var fromBuf, fromStream Bar
+			require.NoError(t, compressed.DeserializeFrom(buf, &fromBuf))
+			require.Equal(t, want, fromBuf)
+			require.NoError(t, compressed.DeserializeFromStream(stream, &fromStream))
+			require.Equal(t, want, fromStream)
+		}
+
+		generic, err := Serialize(compressed, value)
+		require.NoError(t, err)
+		got = nil
+		require.NoError(t, Deserialize(compressed, generic, &got))
+		require.Equal(t, value, got)
+	}
+}
+
+func TestWithCompressionHeaderless(t *testing.T) {
+	f := NewFory(WithHeader(false), WithCompression(flateCodec{}))
+	data, err := f.Marshal("hello")
+	require.NoError(t, err)
+	var got string
+	require.NoError(t, f.Unmarshal(data, &got))
+	require.Equal(t, "hello", got)
+}
+
+// inflationCodec records the size of the largest body it decompressed.
+type inflationCodec struct {
+	flateCodec
+	largest *int
+}
+
+func (c inflationCodec) Decompress(dst, src []byte, maxSize int) ([]byte, error) {
+	out, err := c.flateCodec.Decompress(dst, src, maxSize)
+	*c.largest = max(*c.largest, len(out))
+	return out, err
+}
+
+func TestWithCompressionBomb(t *testing.T) {
+	writer := NewFory(WithCompression(flateCodec{}))
+	data, err := writer.Marshal(make([]byte, 8<<20))
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+	require.Less(t, len(data), 64<<10)
+
+	var got []byte
+	var largest int
+	codec := inflationCodec{largest: &largest}
+	err = NewFory(WithCompression(codec), WithMaxBinarySize(1<<20)).Unmarshal(data, &got)
+	require.Error(t, err)
+	var foryErr Error
+	require.True(t, errors.As(err, &foryErr))
+	require.Equal(t, ErrKindMaxBinarySizeExceeded, foryErr.Kind())
+	require.LessOrEqual(t, largest, 1<<20+1, "the body must not be inflated past the limit")
+
+	largest = 0
+	err = NewFory(WithCompression(codec), WithDecodeBudget(1<<20)).Unmarshal(data, &got)
+	require.ErrorIs(t, err, ErrBudgetExceeded)
+	require.LessOrEqual(t, largest, 1<<20+1)
+
+	require.NoError(t, NewFory(WithCompression(flateCodec{})).Unmarshal(data, &got))
+	require.Len(t, got, 8<<20)
+}
+
+type metaCompressedRecord struct {
+	CustomerAccountName    string
+	CustomerAccountEmail   string
+	CustomerAccountPhone   string
+	CustomerAccountCountry string
+	CustomerAccountCity    string
+	CustomerAccountStreet  string
+	CustomerAccountZipCode string
+	CustomerAccountBalance int64
+	CustomerAccountCredit  int64
+	CustomerAccountRating  int32
+}
+
+func TestWithMetaCompressor(t *testing.T) {
+	newFory := func(opts ...Option) *Fory {
+		f := New(append([]Option{WithXlang(false), WithCompatible(true)}, opts...)...)
+		require.NoError(t, f.RegisterStructByName(metaCompressedRecord{}, "example.MetaCompressedRecord"))
+		return f
+	}
+	value := &metaCompressedRecord{CustomerAccountName: "a", CustomerAccountBalance: 7}
+
+	plainData, err := newFory().Serialize(value)
+	require.NoError(t, err)
+	compressed := newFory(WithMetaCompressor(DeflateMetaCompressor{}))
+	data, err := compressed.Serialize(value)
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+	require.Less(t, len(data), len(plainData))
+
+	var result metaCompressedRecord
+	require.NoError(t, newFory(WithMetaCompressor(DeflateMetaCompressor{})).Deserialize(data, &result))
+	require.Equal(t, *value, result)
+	require.NoError(t, compressed.Deserialize(plainData, &result))
+
+	err = newFory(WithPanicRecovery(false)).Deserialize(data, &result)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "WithMetaCompressor")
+
+	out, err := Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, out, "customer_account_balance: VARINT64 = 7")
+
+	packed, err := DeflateMetaCompressor{}.Compress(nil, make([]byte, 100))
+	require.NoError(t, err)
+	_, err = DeflateMetaCompressor{}.Decompress(nil, packed, 99)
+	require.Error(t, err)
+}
+
+func TestXlangTypeDefsAreNeverCompressed(t *testing.T) {
+	value := &metaCompressedRecord{CustomerAccountName: "a", CustomerAccountBalance: 7}
+	f := New(WithXlang(true), WithCompatible(true))
+	require.NoError(t, f.RegisterStructByName(metaCompressedRecord{}, "example.MetaCompressedRecord"))
+	typeDef, err := buildTypeDef(f, reflect.ValueOf(*value))
+	require.NoError(t, err)
+	headerErr := &Error{}
+	header := NewByteBuffer(typeDef.encoded).ReadInt64(headerErr)
+	require.NoError(t, headerErr.CheckError())
+	require.Zero(t, header&COMPRESS_META_FLAG)
+
+	compressing := New(WithXlang(true), WithCompatible(true), WithMetaCompressor(DeflateMetaCompressor{}))
+	require.NoError(t, compressing.RegisterStructByName(metaCompressedRecord{}, "example.MetaCompressedRecord"))
+	_, err = compressing.Serialize(value)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "WithXlang(false)")
+
+	frame, compressedHeader := typeDefTestFrame(t, deflateTypeDefTestBody(t, typeDefTestBodyWithoutFields()), true)
+	_, err = decodeTypeDef(compressing, frame, compressedHeader)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "xlang")
+}
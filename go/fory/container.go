@@ -0,0 +1,141 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"container/list"
+	"container/ring"
+	"reflect"
+)
+
+var (
+	listPtrType = reflect.TypeOf((*list.List)(nil))
+	ringPtrType = reflect.TypeOf((*ring.Ring)(nil))
+)
+
+// isContainerType reports whether t is a stdlib container pointer that is
+// serialized as a LIST.
+func isContainerType(t reflect.Type) bool {
+	return t == listPtrType || t == ringPtrType
+}
+
+// containerSerializer writes *list.List and *ring.Ring as a LIST of their
+// values, with the same element encoding as []any, and rebuilds the container
+// when the target has that type.
+type containerSerializer struct {
+	// values returns the elements of a non-nil container in order.
+	values func(value reflect.Value) []any
+	// build returns a new container for n elements and a function that stores
+	// the next element. An empty ring is a nil *ring.Ring.
+	build func(n int) (reflect.Value, func(v any))
+}
+
+var listSerializer = containerSerializer{
+	values: func(value reflect.Value) []any {
+		l := value.Interface().(*list.List)
+		values := make([]any, 0, l.Len())
+		for e := l.Front(); e != nil; e = e.Next() {
+			values = append(values, e.Value)
+		}
+		return values
+	},
+	build: func(n int) (reflect.Value, func(v any)) {
+		l := list.New()
+		return reflect.ValueOf(l), func(v any) { l.PushBack(v) }
+	},
+}
+
+var ringSerializer = containerSerializer{
+	values: func(value reflect.Value) []any {
+		r := value.Interface().(*ring.Ring)
+		values := make([]any, 0, r.Len())
+		r.Do(func(v any) { values = append(values, v) })
+		return values
+	},
+	build: func(n int) (reflect.Value, func(v any)) {
+		r := ring.New(n)
+		next := r
+		return reflect.ValueOf(r), func(v any) {
+			next.Value = v
+			next = next.Next()
+		}
+	},
+}
+
+func (s containerSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	if refMode != RefModeNone {
+		if value.IsNil() {
+			ctx.buffer.WriteInt8(NullFlag)
+			return
+		}
+		refWritten, err := ctx.RefResolver().WriteRefOrNull(ctx.buffer, value)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return
+		}
+		if refWritten {
+			return
+		}
+	}
+	if writeType {
+		ctx.buffer.WriteUint8(uint8(LIST))
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s containerSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	sliceDynSerializer{}.WriteData(ctx, reflect.ValueOf(s.values(value)))
+}
+
+func (s containerSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	done, typeId := readSliceRefAndType(ctx, refMode, readType, value)
+	if done || ctx.HasError() {
+		return
+	}
+	if readType && typeId != uint32(LIST) {
+		ctx.SetError(DeserializationErrorf("%s type mismatch: expected LIST (%d), got %d", value.Type(), LIST, typeId))
+		return
+	}
+	s.ReadData(ctx, value)
+}
+
+func (s containerSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	length := ctx.ReadCollectionLength()
+	if ctx.HasError() || !ctx.chargeElems(length, interfaceSliceType.Elem()) {
+		return
+	}
+	container, add := s.build(length)
+	value.Set(container)
+	ctx.RefResolver().Reference(value)
+	if length == 0 {
+		return
+	}
+	collectFlag := ctx.Buffer().ReadInt8(ctx.Err())
+	elems := reflect.MakeSlice(interfaceSliceType, length, length)
+	sliceDynSerializer{}.readElements(ctx, elems, collectFlag)
+	if ctx.HasError() {
+		return
+	}
+	for _, v := range elems.Interface().([]any) {
+		add(v)
+	}
+}
+
+func (s containerSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	s.Read(ctx, refMode, false, false, value)
+}
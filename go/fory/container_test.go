@@ -0,0 +1,156 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"container/list"
+	"container/ring"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type containerHolder struct {
+	Name  string
+	Queue *list.List
+	Ring  *ring.Ring
+}
+
+func listValues(l *list.List) []any {
+	var values []any
+	for e := l.Front(); e != nil; e = e.Next() {
+		values = append(values, e.Value)
+	}
+	return values
+}
+
+func ringValues(r *ring.Ring) []any {
+	var values []any
+	r.Do(func(v any) { values = append(values, v) })
+	return values
+}
+
+func TestSerializeContainerList(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		for _, trackRef := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithTrackRef(trackRef))
+			l := list.New()
+			l.PushBack("a")
+			l.PushBack(int64(2))
+			l.PushBack(nil)
+			data, err := f.Marshal(l)
+			require.NoError(t, err)
+			var result *list.List
+			require.NoError(t, f.Unmarshal(data, &result), "xlang=%v trackRef=%v", xlang, trackRef)
+			require.Equal(t, []any{"a", int64(2), nil}, listValues(result))
+
+			var dynamic any
+			require.NoError(t, f.Unmarshal(data, &dynamic))
+			require.Equal(t, []any{"a", int64(2), nil}, dynamic)
+
+			data, err = f.Marshal([]string{"x", "y"})
+			require.NoError(t, err)
+			result = nil
+			require.NoError(t, f.Unmarshal(data, &result))
+			require.Equal(t, []any{"x", "y"}, listValues(result))
+
+			data, err = f.Marshal(list.New())
+			require.NoError(t, err)
+			result = nil
+			require.NoError(t, f.Unmarshal(data, &result))
+			require.NotNil(t, result)
+			require.Equal(t, 0, result.Len())
+		}
+	}
+}
+
+func TestSerializeContainerRing(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	r := ring.New(3)
+	for i := range 3 {
+		r.Value = int64(i)
+		r = r.Next()
+	}
+	data, err := f.Marshal(r)
+	require.NoError(t, err)
+	var result *ring.Ring
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, []any{int64(0), int64(1), int64(2)}, ringValues(result))
+
+	data, err = f.Marshal([]string{})
+	require.NoError(t, err)
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Nil(t, result)
+}
+
+func TestSerializeContainerFields(t *testing.T) {
+	for _, compatible := range []bool{true, false} {
+		f := NewFory(WithXlang(true), WithCompatible(compatible), WithTrackRef(true))
+		require.NoError(t, f.RegisterStruct(containerHolder{}, 1))
+		value := containerHolder{Name: "n", Queue: list.New(), Ring: ring.New(2)}
+		value.Queue.PushBack("job")
+		value.Ring.Value = "x"
+		value.Ring.Next().Value = "y"
+		data, err := f.Marshal(&value)
+		require.NoError(t, err)
+		var result containerHolder
+		require.NoError(t, f.Unmarshal(data, &result), "compatible=%v", compatible)
+		require.Equal(t, "n", result.Name)
+		require.Equal(t, []any{"job"}, listValues(result.Queue))
+		require.Equal(t, []any{"x", "y"}, ringValues(result.Ring))
+
+		data, err = f.Marshal(&containerHolder{Name: "empty"})
+		require.NoError(t, err)
+		result = containerHolder{}
+		require.NoError(t, f.Unmarshal(data, &result))
+		require.Nil(t, result.Queue)
+		require.Nil(t, result.Ring)
+	}
+}
+
+func TestSerializeContainerSelfReference(t *testing.T) {
+	f := NewFory(WithXlang(false), WithTrackRef(true))
+	l := list.New()
+	l.PushBack("head")
+	l.PushBack(l)
+	data, err := f.Marshal(l)
+	require.NoError(t, err)
+	var result *list.List
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, 2, result.Len())
+	require.Same(t, result, result.Back().Value)
+}
+
+func TestSerializeContainerFieldFromSlice(t *testing.T) {
+	type sliceHolder struct {
+		Name  string
+		Queue []any
+	}
+	writer := NewFory(WithXlang(true), WithCompatible(true))
+	require.NoError(t, writer.RegisterStruct(sliceHolder{}, 1))
+	reader := NewFory(WithXlang(true), WithCompatible(true))
+	require.NoError(t, reader.RegisterStruct(containerHolder{}, 1))
+
+	data, err := writer.Marshal(&sliceHolder{Name: "n", Queue: []any{"a", int64(2)}})
+	require.NoError(t, err)
+	var result containerHolder
+	require.NoError(t, reader.Unmarshal(data, &result))
+	require.Equal(t, []any{"a", int64(2)}, listValues(result.Queue))
+}
@@ -0,0 +1,235 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"math"
+	"reflect"
+)
+
+// ConvertAssign copies src into the value pointed to by dst, converting
+// dynamically decoded values into dst's concrete type. It is intended for
+// results deserialized into `any`, where slices decode as []any, maps as
+// map[any]any and named structs as pointers.
+//
+// Elements, map keys and values, pointers and arrays are converted
+// recursively. Numeric values convert between numeric kinds only when the
+// value fits in the target type. Interface-typed targets receive the source
+// value unchanged. The returned error names the path of the first value that
+// could not be converted.
+func ConvertAssign(dst, src any) error {
+	dstValue := reflect.ValueOf(dst)
+	if dstValue.Kind() != reflect.Ptr || dstValue.IsNil() {
+		return fmt.Errorf("fory: ConvertAssign destination must be a non-nil pointer, got %T", dst)
+	}
+	out, err := convertValue(reflect.ValueOf(src), dstValue.Elem().Type(), "")
+	if err != nil {
+		return err
+	}
+	dstValue.Elem().Set(out)
+	return nil
+}
+
+func convertValue(src reflect.Value, type_ reflect.Type, path string) (reflect.Value, error) {
+	for src.IsValid() && src.Kind() == reflect.Interface {
+		if src.IsNil() {
+			src = reflect.Value{}
+			break
+		}
+		src = src.Elem()
+	}
+	if !src.IsValid() {
+		switch type_.Kind() {
+		case reflect.Ptr, reflect.Interface, reflect.Slice, reflect.Map:
+			return reflect.Zero(type_), nil
+		}
+		return reflect.Value{}, convertError(path, "nil", type_)
+	}
+	if src.Type() == type_ {
+		return src, nil
+	}
+	switch type_.Kind() {
+	case reflect.Interface:
+		if src.Type().Implements(type_) {
+			out := reflect.New(type_).Elem()
+			out.Set(src)
+			return out, nil
+		}
+	case reflect.Ptr:
+		if src.Kind() == reflect.Ptr {
+			if src.IsNil() {
+				return reflect.Zero(type_), nil
+			}
+			src = src.Elem()
+		}
+		elem, err := convertValue(src, type_.Elem(), path)
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out := reflect.New(type_.Elem())
+		out.Elem().Set(elem)
+		return out, nil
+	case reflect.Slice:
+		if src.Kind() == reflect.Slice && src.IsNil() {
+			return reflect.Zero(type_), nil
+		}
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			out := reflect.MakeSlice(type_, src.Len(), src.Len())
+			if err := convertElements(src, out, path); err != nil {
+				return reflect.Value{}, err
+			}
+			return out, nil
+		}
+	case reflect.Array:
+		if src.Kind() == reflect.Slice || src.Kind() == reflect.Array {
+			if src.Len() != type_.Len() {
+				return reflect.Value{}, fmt.Errorf("fory: cannot convert %s%s: length %d does not match %s",
+					src.Type(), pathSuffix(path), src.Len(), type_)
+			}
+			out := reflect.New(type_).Elem()
+			if err := convertElements(src, out, path); err != nil {
+				return reflect.Value{}, err
+			}
+			return out, nil
+		}
+	case reflect.Map:
+		if src.Kind() == reflect.Map {
+			if src.IsNil() {
+				return reflect.Zero(type_), nil
+			}
+			out := reflect.MakeMapWithSize(type_, src.Len())
+			iter := src.MapRange()
+			for iter.Next() {
+				keyPath := fmt.Sprintf("%s[%v]", path, iter.Key())
+				key, err := convertValue(iter.Key(), type_.Key(), keyPath)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				value, err := convertValue(iter.Value(), type_.Elem(), keyPath)
+				if err != nil {
+					return reflect.Value{}, err
+				}
+				out.SetMapIndex(key, value)
+			}
+			return out, nil
+		}
+	case reflect.Struct:
+		// Named structs decoded into `any` are returned as pointers.
+		if src.Kind() == reflect.Ptr && !src.IsNil() && src.Elem().Type() == type_ {
+			return src.Elem(), nil
+		}
+	default:
+		if out, ok := convertScalar(src, type_); ok {
+			return out, nil
+		}
+	}
+	return reflect.Value{}, convertError(path, src.Type().String(), type_)
+}
+
+func convertElements(src, out reflect.Value, path string) error {
+	elemType := out.Type().Elem()
+	for i := 0; i < src.Len(); i++ {
+		elem, err := convertValue(src.Index(i), elemType, fmt.Sprintf("%s[%d]", path, i))
+		if err != nil {
+			return err
+		}
+		out.Index(i).Set(elem)
+	}
+	return nil
+}
+
+// convertScalar converts between types of the same kind and between numeric
+// kinds when the value is representable in the target type.
+func convertScalar(src reflect.Value, type_ reflect.Type) (reflect.Value, bool) {
+	if src.Kind() == type_.Kind() {
+		if src.Type().ConvertibleTo(type_) {
+			return src.Convert(type_), true
+		}
+		return reflect.Value{}, false
+	}
+	if !isNumberKind(src.Kind()) || !isNumberKind(type_.Kind()) {
+		return reflect.Value{}, false
+	}
+	out := reflect.New(type_).Elem()
+	switch src.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v := src.Int()
+		switch type_.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if out.OverflowInt(v) {
+				return reflect.Value{}, false
+			}
+			out.SetInt(v)
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if v < 0 || out.OverflowUint(uint64(v)) {
+				return reflect.Value{}, false
+			}
+			out.SetUint(uint64(v))
+		default:
+			out.SetFloat(float64(v))
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v := src.Uint()
+		switch type_.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			if int64(v) < 0 || out.OverflowInt(int64(v)) {
+				return reflect.Value{}, false
+			}
+			out.SetInt(int64(v))
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			if out.OverflowUint(v) {
+				return reflect.Value{}, false
+			}
+			out.SetUint(v)
+		default:
+			out.SetFloat(float64(v))
+		}
+	default:
+		v := src.Float()
+		switch type_.Kind() {
+		case reflect.Float32, reflect.Float64:
+			if out.OverflowFloat(v) {
+				return reflect.Value{}, false
+			}
+			out.SetFloat(v)
+		default:
+			// Floats only convert to integers when no precision is lost.
+			if v < math.MinInt64 || v >= math.MaxInt64 || v != math.Trunc(v) {
+				return reflect.Value{}, false
+			}
+			return convertScalar(reflect.ValueOf(int64(v)), type_)
+		}
+	}
+	return out, true
+}
+
+func isNumberKind(kind reflect.Kind) bool {
+	return isNumericKind(kind) || kind == reflect.Float32 || kind == reflect.Float64
+}
+
+func convertError(path, srcType string, type_ reflect.Type) error {
+	return fmt.Errorf("fory: cannot convert %s%s to %s", srcType, pathSuffix(path), type_)
+}
+
+func pathSuffix(path string) string {
+	if path == "" {
+		return ""
+	}
+	return " at " + path
+}
@@ -0,0 +1,117 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type convertItem struct {
+	ID   int32
+	Tags []string
+}
+
+func TestConvertAssign(t *testing.T) {
+	f := NewFory(WithXlang(true), WithRefTracking(true))
+	require.NoError(t, f.RegisterStruct(convertItem{}, 1))
+	type target struct {
+		Ints   [][]int32
+		Counts map[string]int64
+		Arr    [2]int16
+		Item   convertItem
+		Items  []*convertItem
+		Any    any
+	}
+	expected := target{
+		Ints:   [][]int32{{1, 2}, {3}},
+		Counts: map[string]int64{"a": 1},
+		Arr:    [2]int16{4, 5},
+		Item:   convertItem{ID: 6, Tags: []string{"x"}},
+		Items:  []*convertItem{{ID: 7, Tags: []string{"y"}}, nil},
+		Any:    "dynamic",
+	}
+	values := []any{
+		[]any{[]int32{1, 2}, []int32{3}},
+		map[any]any{"a": int64(1)},
+		[]any{int16(4), int16(5)},
+		convertItem{ID: 6, Tags: []string{"x"}},
+		[]any{convertItem{ID: 7, Tags: []string{"y"}}, nil},
+		"dynamic",
+	}
+	var decoded []any
+	for _, value := range values {
+		data, err := f.Marshal(marshalTestValue(value))
+		require.NoError(t, err)
+		var v any
+		require.NoError(t, f.Unmarshal(data, &v))
+		decoded = append(decoded, v)
+	}
+	var result target
+	require.NoError(t, ConvertAssign(&result.Ints, decoded[0]))
+	require.NoError(t, ConvertAssign(&result.Counts, decoded[1]))
+	require.NoError(t, ConvertAssign(&result.Arr, decoded[2]))
+	require.NoError(t, ConvertAssign(&result.Item, decoded[3]))
+	require.NoError(t, ConvertAssign(&result.Items, decoded[4]))
+	require.NoError(t, ConvertAssign(&result.Any, decoded[5]))
+	require.Equal(t, expected, result)
+}
+
+func TestConvertAssignNumbers(t *testing.T) {
+	var i8 int8
+	require.NoError(t, ConvertAssign(&i8, int64(-5)))
+	require.Equal(t, int8(-5), i8)
+	var u16 uint16
+	require.NoError(t, ConvertAssign(&u16, int32(7)))
+	require.Equal(t, uint16(7), u16)
+	var f32 float32
+	require.NoError(t, ConvertAssign(&f32, int64(3)))
+	require.Equal(t, float32(3), f32)
+	var i64 int64
+	require.NoError(t, ConvertAssign(&i64, 2.0))
+	require.Equal(t, int64(2), i64)
+
+	require.Error(t, ConvertAssign(&i8, int64(300)))
+	require.Error(t, ConvertAssign(&u16, int32(-1)))
+	require.Error(t, ConvertAssign(&i64, 2.5))
+}
+
+func TestConvertAssignErrors(t *testing.T) {
+	var ints []int32
+	err := ConvertAssign(&ints, []any{int32(1), "x"})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot convert string at [1] to int32")
+
+	var nested map[string][]int8
+	err = ConvertAssign(&nested, map[any]any{"k": []any{int64(1000)}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "at [k][0]")
+
+	var arr [2]int32
+	err = ConvertAssign(&arr, []any{int32(1)})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "length 1 does not match [2]int32")
+
+	var s string
+	require.Error(t, ConvertAssign(&s, int32(65)))
+	require.Error(t, ConvertAssign(s, "x"))
+	require.Error(t, ConvertAssign(&s, nil))
+}
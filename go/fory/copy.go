@@ -0,0 +1,206 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// DeepCopy returns a deep copy of value without encoding it to bytes.
+//
+// The copy contains what a serialize/deserialize round trip through f would
+// produce: unexported fields and fields tagged `fory:"-"` are left zero, and
+// structs handled by custom, extension or generated serializers are copied
+// through those serializers. Pointers, maps and slices shared within value
+// stay shared in the copy, so cyclic graphs are copied too. Reference
+// tracking does not need to be enabled.
+func DeepCopy[T any](f *Fory, value T) (T, error) {
+	c := copier{fory: f, refs: make(map[copyRef]reflect.Value)}
+	var result T
+	src := reflect.ValueOf(&value).Elem()
+	out, err := c.copy(src)
+	if err != nil {
+		return result, err
+	}
+	reflect.ValueOf(&result).Elem().Set(out)
+	return result, nil
+}
+
+// copyRef identifies a pointer, map or slice already copied. Slices also key
+// on length so that different windows of one array are copied separately.
+type copyRef struct {
+	type_ reflect.Type
+	ptr   uintptr
+	len   int
+}
+
+type copier struct {
+	fory *Fory
+	refs map[copyRef]reflect.Value
+}
+
+func (c *copier) copy(src reflect.Value) (reflect.Value, error) {
+	type_ := src.Type()
+	if isScalarKind(src.Kind()) {
+		return src, nil
+	}
+	switch src.Kind() {
+	case reflect.Interface:
+		out := reflect.New(type_).Elem()
+		if src.IsNil() {
+			return out, nil
+		}
+		elem, err := c.copy(src.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Set(elem)
+		return out, nil
+	case reflect.Ptr:
+		if src.IsNil() {
+			return reflect.Zero(type_), nil
+		}
+		ref := copyRef{type_: type_, ptr: src.Pointer()}
+		if out, ok := c.refs[ref]; ok {
+			return out, nil
+		}
+		out := reflect.New(type_.Elem())
+		c.refs[ref] = out
+		elem, err := c.copy(src.Elem())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		out.Elem().Set(elem)
+		return out, nil
+	case reflect.Slice:
+		if src.IsNil() {
+			return reflect.Zero(type_), nil
+		}
+		ref := copyRef{type_: type_, ptr: src.Pointer(), len: src.Len()}
+		if out, ok := c.refs[ref]; ok {
+			return out, nil
+		}
+		out := reflect.MakeSlice(type_, src.Len(), src.Len())
+		c.refs[ref] = out
+		if isScalarKind(type_.Elem().Kind()) {
+			reflect.Copy(out, src)
+			return out, nil
+		}
+		return out, c.copyElements(src, out)
+	case reflect.Array:
+		out := reflect.New(type_).Elem()
+		if isScalarKind(type_.Elem().Kind()) {
+			out.Set(src)
+			return out, nil
+		}
+		return out, c.copyElements(src, out)
+	case reflect.Map:
+		if src.IsNil() {
+			return reflect.Zero(type_), nil
+		}
+		ref := copyRef{type_: type_, ptr: src.Pointer()}
+		if out, ok := c.refs[ref]; ok {
+			return out, nil
+		}
+		out := reflect.MakeMapWithSize(type_, src.Len())
+		c.refs[ref] = out
+		iter := src.MapRange()
+		for iter.Next() {
+			key, err := c.copy(iter.Key())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			value, err := c.copy(iter.Value())
+			if err != nil {
+				return reflect.Value{}, err
+			}
+			out.SetMapIndex(key, value)
+		}
+		return out, nil
+	case reflect.Struct:
+		return c.copyStruct(src)
+	}
+	return reflect.Value{}, fmt.Errorf("fory: type %s cannot be copied", type_)
+}
+
+func (c *copier) copyElements(src, out reflect.Value) error {
+	for i := 0; i < src.Len(); i++ {
+		elem, err := c.copy(src.Index(i))
+		if err != nil {
+			return err
+		}
+		out.Index(i).Set(elem)
+	}
+	return nil
+}
+
+func (c *copier) copyStruct(src reflect.Value) (reflect.Value, error) {
+	type_ := src.Type()
+	if type_ == timestampType {
+		// time.Time is an immutable value; a round trip would drop its location.
+		return src, nil
+	}
+	serializer, ok := c.fory.typeResolver.typeToSerializers[type_]
+	if ok {
+		if _, isStruct := serializer.(*structSerializer); !isStruct {
+			return c.roundTrip(src)
+		}
+	}
+	out := reflect.New(type_).Elem()
+	for i := 0; i < type_.NumField(); i++ {
+		field := c.fory.typeResolver.structField(type_, i)
+		if !shouldIncludeField(field) {
+			continue
+		}
+		value, err := c.copy(src.Field(i))
+		if err != nil {
+			return reflect.Value{}, fmt.Errorf("fory: copy %s.%s: %w", type_, field.Name, err)
+		}
+		out.Field(i).Set(value)
+	}
+	return out, nil
+}
+
+// roundTrip copies a value handled by a serializer whose data layout is not
+// visible to reflection by encoding and decoding it through f.
+func (c *copier) roundTrip(src reflect.Value) (reflect.Value, error) {
+	// Wrap the value in a slice: root structs must be passed by pointer, while
+	// custom serializers expect the value itself.
+	wrapper := reflect.MakeSlice(reflect.SliceOf(src.Type()), 1, 1)
+	wrapper.Index(0).Set(src)
+	data, err := c.fory.Serialize(wrapper.Interface())
+	if err != nil {
+		return reflect.Value{}, err
+	}
+	out := reflect.New(wrapper.Type())
+	if err := c.fory.Deserialize(data, out.Interface()); err != nil {
+		return reflect.Value{}, err
+	}
+	return out.Elem().Index(0), nil
+}
+
+func isScalarKind(kind reflect.Kind) bool {
+	switch kind {
+	case reflect.Bool, reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr,
+		reflect.Float32, reflect.Float64, reflect.Complex64, reflect.Complex128, reflect.String:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type copyNode struct {
+	Name     string
+	Next     *copyNode
+	Children []*copyNode
+	Attrs    map[string]any
+	Skipped  string `fory:"-"`
+	hidden   int
+}
+
+type copyPoint struct {
+	X, Y int32
+}
+
+type copyPointSerializer struct{}
+
+func (copyPointSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	p := value.Interface().(copyPoint)
+	ctx.Buffer().WriteVarint32(p.X)
+}
+
+func (copyPointSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	x := ctx.Buffer().ReadVarint32(ctx.Err())
+	value.Set(reflect.ValueOf(copyPoint{X: x}))
+}
+
+func TestDeepCopy(t *testing.T) {
+	f := New(WithXlang(false))
+	shared := &copyNode{Name: "shared"}
+	root := &copyNode{
+		Name:     "root",
+		Children: []*copyNode{shared, shared},
+		Attrs:    map[string]any{"n": int64(1), "list": []any{"a", shared}},
+		Skipped:  "skip",
+		hidden:   3,
+	}
+	root.Next = root
+
+	result, err := DeepCopy(f, root)
+	require.NoError(t, err)
+	require.NotSame(t, root, result)
+	require.Same(t, result, result.Next)
+	require.Equal(t, "root", result.Name)
+	require.Empty(t, result.Skipped)
+	require.Zero(t, result.hidden)
+	require.NotSame(t, shared, result.Children[0])
+	require.Same(t, result.Children[0], result.Children[1])
+	require.Same(t, result.Children[0], result.Attrs["list"].([]any)[1])
+	require.Equal(t, int64(1), result.Attrs["n"])
+
+	result.Children[0].Name = "changed"
+	require.Equal(t, "shared", shared.Name)
+}
+
+func TestDeepCopyValues(t *testing.T) {
+	f := New(WithXlang(false))
+	ints := []int32{1, 2, 3}
+	copied, err := DeepCopy(f, ints)
+	require.NoError(t, err)
+	require.Equal(t, ints, copied)
+	copied[0] = 9
+	require.Equal(t, int32(1), ints[0])
+
+	now := time.Now()
+	copiedTime, err := DeepCopy(f, now)
+	require.NoError(t, err)
+	require.True(t, now.Equal(copiedTime))
+	require.Equal(t, now.Location(), copiedTime.Location())
+
+	var nilMap map[string]int
+	copiedMap, err := DeepCopy(f, nilMap)
+	require.NoError(t, err)
+	require.Nil(t, copiedMap)
+
+	_, err = DeepCopy(f, []any{func() {}})
+	require.Error(t, err)
+}
+
+func TestDeepCopyUsesExtensionSerializer(t *testing.T) {
+	f := New(WithXlang(false))
+	require.NoError(t, f.RegisterExtension(copyPoint{}, 1, copyPointSerializer{}))
+	result, err := DeepCopy(f, []copyPoint{{X: 1, Y: 2}})
+	require.NoError(t, err)
+	// The extension serializer only writes X.
+	require.Equal(t, []copyPoint{{X: 1}}, result)
+}
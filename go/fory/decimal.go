@@ -98,6 +98,65 @@ func (s decimalSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, t
 	s.Read(ctx, refMode, false, false, value)
 }
 
+// DecimalConverter converts the decimal type of another package to and from
+// Decimal, so RegisterDecimal can write it as the DECIMAL type.
+type DecimalConverter interface {
+	// ToDecimal returns the exact value held by value.
+	ToDecimal(value reflect.Value) Decimal
+	// FromDecimal stores d in value, which is settable.
+	FromDecimal(d Decimal, value reflect.Value)
+}
+
+// convertedDecimalSerializer writes a type registered with RegisterDecimal in
+// the DECIMAL encoding of Decimal.
+type convertedDecimalSerializer struct {
+	converter DecimalConverter
+}
+
+func (s convertedDecimalSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	if refMode != RefModeNone {
+		ctx.buffer.WriteInt8(NotNullValueFlag)
+	}
+	if writeType {
+		ctx.buffer.WriteUint8(uint8(DECIMAL))
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s convertedDecimalSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	decimal := s.converter.ToDecimal(value)
+	writeDecimalParts(ctx.buffer, decimal.Scale, &decimal.Unscaled)
+}
+
+func (s convertedDecimalSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	err := ctx.Err()
+	if refMode != RefModeNone {
+		if ctx.buffer.ReadInt8(err) == NullFlag {
+			value.Set(reflect.Zero(value.Type()))
+			return
+		}
+	}
+	if readType && !ctx.readExpectedTypeID(DECIMAL) {
+		return
+	}
+	if ctx.HasError() {
+		return
+	}
+	s.ReadData(ctx, value)
+}
+
+func (s convertedDecimalSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	scale, unscaled := readDecimalParts(ctx)
+	if ctx.HasError() {
+		return
+	}
+	s.converter.FromDecimal(Decimal{Unscaled: *unscaled, Scale: scale}, value)
+}
+
+func (s convertedDecimalSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	s.Read(ctx, refMode, false, false, value)
+}
+
 func writeDecimalParts(buffer *ByteBuffer, scale int32, unscaled *big.Int) {
 	if unscaled == nil {
 		unscaled = new(big.Int)
@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"math/big"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// cents holds a decimal with scale 2.
+type cents struct {
+	Units int64
+}
+
+type centsConverter struct{}
+
+func (centsConverter) ToDecimal(value reflect.Value) Decimal {
+	return NewDecimal(big.NewInt(value.Interface().(cents).Units), 2)
+}
+
+func (centsConverter) FromDecimal(d Decimal, value reflect.Value) {
+	units := new(big.Int).Set(&d.Unscaled)
+	for scale := d.Scale; scale < 2; scale++ {
+		units.Mul(units, big.NewInt(10))
+	}
+	value.Set(reflect.ValueOf(cents{Units: units.Int64()}))
+}
+
+type centsPrice struct {
+	Amount cents
+	Prices []cents
+}
+
+type decimalPrice struct {
+	Amount Decimal
+	Prices []Decimal
+}
+
+func TestRegisterDecimal(t *testing.T) {
+	for _, compatible := range []bool{false, true} {
+		f := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, f.RegisterDecimal(cents{}, centsConverter{}))
+		require.NoError(t, f.RegisterStructByName(centsPrice{}, "example.Price"))
+		plain := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, plain.RegisterStructByName(decimalPrice{}, "example.Price"))
+
+		data, err := f.Serialize(&cents{Units: -1234})
+		require.NoError(t, err)
+		buf := NewByteBuffer(data)
+		buf.ReadByte(nil)
+		buf.ReadInt8(nil)
+		require.Equal(t, uint8(DECIMAL), buf.ReadUint8(nil))
+		var decoded Decimal
+		require.NoError(t, plain.Deserialize(data, &decoded))
+		require.True(t, NewDecimal(big.NewInt(-1234), 2).Equal(decoded))
+		var dynamic any
+		require.NoError(t, f.Deserialize(data, &dynamic))
+		require.IsType(t, Decimal{}, dynamic)
+
+		data, err = plain.Serialize(&decimalPrice{
+			Amount: NewDecimal(big.NewInt(5), 0),
+			Prices: []Decimal{NewDecimal(big.NewInt(25), 1)},
+		})
+		require.NoError(t, err)
+		var price centsPrice
+		require.NoError(t, f.Deserialize(data, &price))
+		require.Equal(t, centsPrice{Amount: cents{Units: 500}, Prices: []cents{{Units: 250}}}, price)
+	}
+
+	f := New()
+	require.NoError(t, f.RegisterDecimal(cents{}, centsConverter{}))
+	require.Error(t, f.RegisterDecimal(cents{}, centsConverter{}))
+	require.Error(t, New().RegisterDecimal(cents{}, nil))
+}
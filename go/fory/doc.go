@@ -156,7 +156,7 @@ Available tags:
   - "-": Exclude field from serialization
   - ref: Enable reference tracking for pointer/slice/map fields
   - nullable: Write null flag for pointer fields
-  - encoding=varint|fixed|tagged: Control numeric encoding
+  - encoding=varint|fixed|tagged: Control numeric encoding; the bare names varint, fixed and tagged are shorthand
 
 # Reference Tracking
 
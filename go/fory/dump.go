@@ -0,0 +1,771 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+	"reflect"
+	"strings"
+	"time"
+
+	"github.com/apache/fory/go/fory/bfloat16"
+	"github.com/apache/fory/go/fory/float16"
+)
+
+// maxDumpBinaryBytes bounds how much of a binary value Dump prints.
+const maxDumpBinaryBytes = 64
+
+// Dump renders a payload as an indented tree of its root header, type IDs,
+// type metadata, ref flags and values, for debugging payloads written by other
+// languages without a hex editor. It needs no registrations: struct field names
+// and types come from the TypeDefs that compatible mode writes. Payloads
+// written in schema-consistent mode do not describe struct layouts, so Dump
+// stops at their first struct, and values of extension types cannot be decoded
+// without their serializer. Compressed bodies are not decoded.
+//
+// On malformed payloads Dump returns the tree decoded so far with the error.
+func Dump(data []byte) (string, error) {
+	d, err := dumpData(data)
+	return d.out.String(), err
+}
+
+// DumpValue decodes a payload like Dump, but returns the root value built from
+// plain Go values instead of text: structs become map[string]any keyed by field
+// name, lists, sets and primitive arrays become []any, maps become
+// map[any]any, binary values []byte and enums their uint32 ordinal. Shared
+// structs and maps are shared in the result as well.
+func DumpValue(data []byte) (any, error) {
+	if header, err := ParseHeader(data); err == nil && header.Codec != 0 {
+		return nil, fmt.Errorf("payload is compressed with codec %d, which DumpValue cannot decode", header.Codec)
+	}
+	d, err := dumpData(data)
+	if err != nil {
+		return nil, err
+	}
+	return d.root, nil
+}
+
+func dumpData(data []byte) (*dumper, error) {
+	header, err := ParseHeader(data)
+	if err != nil {
+		return &dumper{}, err
+	}
+	d, err := dumpPayload(data, header, true)
+	if err != nil {
+		// Named types are encoded differently without meta sharing, so the
+		// payload may have been written in schema-consistent mode. Report
+		// whichever reading gets further.
+		if retry, retryErr := dumpPayload(data, header, false); retryErr == nil || retry.out.Len() > d.out.Len() {
+			return retry, retryErr
+		}
+	}
+	return d, err
+}
+
+func dumpPayload(data []byte, header Header, compatible bool) (*dumper, error) {
+	f := New(WithXlang(header.Xlang), WithCompatible(compatible), WithMetaCompressor(DeflateMetaCompressor{}))
+	defer f.resetReadState()
+	d := &dumper{ctx: f.readCtx, pendingRef: -1}
+	d.ctx.SetData(data)
+	d.ctx.buffer.ReadUint8(d.ctx.Err())
+	d.ctx.stringDict = header.StringDictionary
+	d.line("header: xlang=%t out_of_band=%t codec=%d checksum=%t",
+		header.Xlang, header.OutOfBand, header.Codec, header.Checksum)
+	if header.Checksum {
+		verifyChecksum(d.ctx)
+	}
+	if header.Codec != 0 {
+		d.line("body: compressed with codec %d, not decoded", header.Codec)
+	} else if !d.ctx.HasError() {
+		d.root = d.anyValue("root", true)
+	}
+	if d.ctx.HasError() {
+		return d, d.ctx.TakeError()
+	}
+	return d, nil
+}
+
+// dumper walks a payload like the skip functions do, printing every value and
+// building it from plain Go values.
+type dumper struct {
+	ctx    *ReadContext
+	out    strings.Builder
+	indent int
+	root   any
+	// refs holds tracked values by the IDs the ref resolver assigns them, so
+	// back-references can be printed and resolved.
+	refs []any
+	// pendingRef is the ID of the tracked value being read, or -1. Structs and
+	// maps register themselves before their contents to resolve cycles.
+	pendingRef int
+}
+
+// dumpType is the type information written for a value.
+type dumpType struct {
+	id TypeId
+	// name is the qualified name or user type ID of user types.
+	name string
+	// fields is the struct layout from the TypeDef, if the payload has one.
+	fields    []FieldDef
+	hasFields bool
+	// omitZero is set when struct values start with a field presence bitmap.
+	omitZero bool
+}
+
+func (d *dumper) line(format string, args ...any) {
+	d.out.WriteString(strings.Repeat("  ", d.indent))
+	fmt.Fprintf(&d.out, format, args...)
+	d.out.WriteByte('\n')
+}
+
+// readRefFlag reads a ref flag and returns its label. done reports that the
+// flag stands for the whole value, which is then returned.
+func (d *dumper) readRefFlag(label string) (flag string, value any, done bool) {
+	err := d.ctx.Err()
+	refFlag := d.ctx.buffer.ReadInt8(err)
+	if d.ctx.HasError() {
+		return "", nil, true
+	}
+	switch refFlag {
+	case NullFlag:
+		d.line("%s: [null]", label)
+		return "", nil, true
+	case RefFlag:
+		id := d.ctx.buffer.ReadVarUint32(err)
+		if d.ctx.HasError() {
+			return "", nil, true
+		}
+		if int(id) >= len(d.refs) {
+			d.ctx.SetError(DeserializationErrorf("invalid ref id %d", id))
+			return "", nil, true
+		}
+		d.line("%s: [ref #%d]", label, id)
+		return "", d.refs[id], true
+	case RefValueFlag:
+		d.pendingRef = len(d.refs)
+		d.refs = append(d.refs, nil)
+		return fmt.Sprintf("[ref-value #%d] ", d.pendingRef), nil, false
+	case NotNullValueFlag:
+		return "[not-null] ", nil, false
+	default:
+		d.ctx.SetError(DeserializationErrorf("invalid ref flag %d", refFlag))
+		return "", nil, true
+	}
+}
+
+// track records value as the tracked value being read, if there is one.
+func (d *dumper) track(value any) {
+	if d.pendingRef >= 0 {
+		d.refs[d.pendingRef] = value
+		d.pendingRef = -1
+	}
+}
+
+// anyValue dumps a value whose type info is written before its data.
+func (d *dumper) anyValue(label string, readRef bool) any {
+	flag := ""
+	if readRef {
+		var value any
+		var done bool
+		if flag, value, done = d.readRefFlag(label); done {
+			return value
+		}
+	}
+	return d.typedData(label, flag, d.readType())
+}
+
+// value dumps a value declared by spec, such as a struct field.
+func (d *dumper) value(label string, spec *TypeSpec, readRef bool) any {
+	flag := ""
+	if readRef {
+		var value any
+		var done bool
+		if flag, value, done = d.readRefFlag(label); done {
+			return value
+		}
+	}
+	// Polymorphic values and struct-like fields carry their own type info.
+	if spec.TypeId() == UNKNOWN || isStructFieldType(spec) {
+		return d.typedData(label, flag, d.readType())
+	}
+	return d.data(label, flag, dumpType{id: spec.TypeId()}, spec)
+}
+
+// typedData dumps data after type info that was read from the payload.
+func (d *dumper) typedData(label, flag string, t dumpType) any {
+	if d.ctx.HasError() {
+		return nil
+	}
+	return d.data(label, flag, t, nil)
+}
+
+// readType reads a type ID and the metadata that follows it.
+func (d *dumper) readType() dumpType {
+	typeID := d.ctx.buffer.ReadUint8(d.ctx.Err())
+	if d.ctx.HasError() {
+		return dumpType{}
+	}
+	return d.readTypeMeta(typeID)
+}
+
+func (d *dumper) readTypeMeta(typeID TypeId) dumpType {
+	err := d.ctx.Err()
+	resolver := d.ctx.TypeResolver()
+	t := dumpType{id: typeID}
+	switch typeID {
+	case ENUM, STRUCT, EXT, TYPED_UNION:
+		t.name = fmt.Sprintf("id %d", d.ctx.buffer.ReadVarUint32(err))
+	case NAMED_ENUM, NAMED_STRUCT, NAMED_EXT, NAMED_UNION:
+		if resolver.metaShareEnabled() {
+			return d.readSharedType(typeID)
+		}
+		ns, _ := resolver.metaStringResolver.ReadMetaStringBytes(d.ctx.buffer, err)
+		name, _ := resolver.metaStringResolver.ReadMetaStringBytes(d.ctx.buffer, err)
+		if d.ctx.HasError() {
+			return t
+		}
+		t.name = newUnknownTypeWarning(resolver, uint32(typeID), 0, ns, name).typeName()
+	case COMPATIBLE_STRUCT, NAMED_COMPATIBLE_STRUCT:
+		return d.readSharedType(typeID)
+	}
+	return t
+}
+
+// readSharedType reads a meta share index and, on first use, the TypeDef.
+func (d *dumper) readSharedType(typeID TypeId) dumpType {
+	resolver := d.ctx.TypeResolver()
+	info := resolver.readSharedTypeMeta(d.ctx.buffer, d.ctx.Err())
+	if d.ctx.HasError() {
+		return dumpType{}
+	}
+	t := dumpType{id: typeID}
+	if td := info.TypeDef; td != nil {
+		t.name = td.unknownTypeWarning(resolver).typeName()
+		t.fields = td.fieldDefs
+		t.hasFields = true
+		t.omitZero = td.omitZero
+	}
+	return t
+}
+
+// data dumps the data of a value of type t. spec is the declared type, which
+// supplies element types that the payload omits.
+func (d *dumper) data(label, flag string, t dumpType, spec *TypeSpec) any {
+	head := label + ": " + flag + typeIdName(t.id)
+	if t.name != "" {
+		head += " " + t.name
+	}
+	var value any
+	switch t.id {
+	case LIST, SET:
+		value = d.collection(head, spec)
+	case MAP:
+		value = d.mapValue(head, spec)
+	case STRUCT, NAMED_STRUCT, COMPATIBLE_STRUCT, NAMED_COMPATIBLE_STRUCT:
+		value = d.structValue(head, t)
+	case UNION, TYPED_UNION, NAMED_UNION:
+		d.line("%s case %d", head, d.ctx.buffer.ReadVarUint32(d.ctx.Err()))
+		d.nested(func() { value = d.anyValue("value", true) })
+	case NONE:
+		d.line("%s", head)
+	case EXT, NAMED_EXT:
+		d.line("%s", head)
+		d.ctx.SetError(DeserializationErrorf("cannot dump %s: extension values are encoded by their serializer", t.name))
+	default:
+		var text string
+		value, text = d.scalar(t.id)
+		if !d.ctx.HasError() {
+			d.line("%s%s", head, text)
+		} else if text == "" {
+			d.line("%s", head)
+		}
+	}
+	d.track(value)
+	return value
+}
+
+// scalar reads a value that has no nested values and returns it with its
+// printed form.
+func (d *dumper) scalar(typeID TypeId) (any, string) {
+	err := d.ctx.Err()
+	buf := d.ctx.buffer
+	var value any
+	switch typeID {
+	case BOOL:
+		value = buf.ReadBool(err)
+	case INT8:
+		value = buf.ReadInt8(err)
+	case INT16:
+		value = buf.ReadInt16(err)
+	case INT32:
+		value = buf.ReadInt32(err)
+	case VARINT32:
+		value = buf.ReadVarint32(err)
+	case INT64:
+		value = buf.ReadInt64(err)
+	case VARINT64:
+		value = buf.ReadVarint64(err)
+	case TAGGED_INT64:
+		value = buf.ReadTaggedInt64(err)
+	case UINT8:
+		value = buf.ReadUint8(err)
+	case UINT16:
+		value = buf.ReadUint16(err)
+	case UINT32:
+		value = buf.ReadUint32(err)
+	case VAR_UINT32:
+		value = buf.ReadVarUint32(err)
+	case UINT64:
+		value = buf.ReadUint64(err)
+	case VAR_UINT64:
+		value = buf.ReadVarUint64(err)
+	case TAGGED_UINT64:
+		value = buf.ReadTaggedUint64(err)
+	case FLOAT16:
+		value = float16.Float16FromBits(buf.ReadUint16(err)).Float32()
+	case BFLOAT16:
+		value = bfloat16.BFloat16FromBits(buf.ReadUint16(err)).Float32()
+	case FLOAT32:
+		value = buf.ReadFloat32(err)
+	case FLOAT64:
+		value = buf.ReadFloat64(err)
+	case DECIMAL:
+		scale, unscaled := readDecimalParts(d.ctx)
+		if d.ctx.HasError() {
+			return nil, ""
+		}
+		value = NewDecimal(unscaled, scale)
+	case STRING:
+		s := d.ctx.ReadString()
+		return s, fmt.Sprintf(" = %q", s)
+	case BINARY:
+		return d.binary()
+	case BOOL_ARRAY, INT8_ARRAY, INT16_ARRAY, INT32_ARRAY, INT64_ARRAY,
+		UINT8_ARRAY, UINT16_ARRAY, UINT32_ARRAY, UINT64_ARRAY,
+		FLOAT16_ARRAY, BFLOAT16_ARRAY, FLOAT32_ARRAY, FLOAT64_ARRAY:
+		return d.array(typeID)
+	case DATE:
+		var days int64
+		if d.ctx.TypeResolver().IsXlang() {
+			days = buf.ReadVarint64(err)
+		} else {
+			days = int64(buf.ReadInt32(err))
+		}
+		if d.ctx.HasError() {
+			return nil, ""
+		}
+		date, dateErr := DateFromEpochDay(days)
+		if dateErr != nil {
+			d.ctx.SetError(FromError(dateErr))
+			return nil, ""
+		}
+		return date, fmt.Sprintf(" = %04d-%02d-%02d", date.Year, date.Month, date.Day)
+	case TIMESTAMP:
+		seconds := buf.ReadInt64(err)
+		nanos := buf.ReadUint32(err)
+		ts := time.Unix(seconds, int64(nanos)).UTC()
+		return ts, " = " + ts.Format(time.RFC3339Nano)
+	case DURATION:
+		seconds := buf.ReadVarint64(err)
+		nanos := buf.ReadInt32(err)
+		value = time.Duration(seconds)*time.Second + time.Duration(nanos)
+	case ENUM, NAMED_ENUM:
+		ordinal := buf.ReadVarUint32Small7(err)
+		return ordinal, fmt.Sprintf(" = ordinal %d", ordinal)
+	default:
+		d.ctx.SetError(DeserializationErrorf("cannot dump type %d", typeID))
+		return nil, ""
+	}
+	return value, fmt.Sprintf(" = %v", value)
+}
+
+// nested runs fn one level deeper, enforcing the configured max depth.
+func (d *dumper) nested(fn func()) {
+	if d.ctx.HasError() {
+		return
+	}
+	d.indent++
+	if d.indent > d.ctx.maxDepth {
+		d.ctx.SetError(MaxDepthExceededError(d.indent))
+	} else {
+		fn()
+	}
+	d.indent--
+}
+
+func (d *dumper) binary() (any, string) {
+	size := d.ctx.ReadBinaryLength()
+	if d.ctx.HasError() {
+		return nil, ""
+	}
+	data := d.ctx.buffer.ReadBinary(size, d.ctx.Err())
+	if d.ctx.HasError() {
+		return nil, ""
+	}
+	value := append([]byte(nil), data...)
+	if len(data) > maxDumpBinaryBytes {
+		return value, fmt.Sprintf(" len=%d = %x...", size, data[:maxDumpBinaryBytes])
+	}
+	return value, fmt.Sprintf(" len=%d = %x", size, data)
+}
+
+func (d *dumper) array(typeID TypeId) (any, string) {
+	size := d.ctx.ReadBinaryLength()
+	if d.ctx.HasError() {
+		return nil, ""
+	}
+	data := d.ctx.buffer.ReadBinary(size, d.ctx.Err())
+	if d.ctx.HasError() {
+		return nil, ""
+	}
+	var width int
+	switch typeID {
+	case BOOL_ARRAY, INT8_ARRAY, UINT8_ARRAY:
+		width = 1
+	case INT16_ARRAY, UINT16_ARRAY, FLOAT16_ARRAY, BFLOAT16_ARRAY:
+		width = 2
+	case INT32_ARRAY, UINT32_ARRAY, FLOAT32_ARRAY:
+		width = 4
+	default:
+		width = 8
+	}
+	if size%width != 0 {
+		d.ctx.SetError(DeserializationErrorf("%s byte size %d is not a multiple of %d", typeIdName(typeID), size, width))
+		return nil, ""
+	}
+	elems := make([]any, 0, size/width)
+	texts := make([]string, 0, size/width)
+	for i := 0; i < size; i += width {
+		var elem any
+		switch typeID {
+		case BOOL_ARRAY:
+			elem = data[i] != 0
+		case INT8_ARRAY:
+			elem = int8(data[i])
+		case UINT8_ARRAY:
+			elem = data[i]
+		case INT16_ARRAY:
+			elem = int16(binary.LittleEndian.Uint16(data[i:]))
+		case UINT16_ARRAY:
+			elem = binary.LittleEndian.Uint16(data[i:])
+		case FLOAT16_ARRAY:
+			elem = float16.Float16FromBits(binary.LittleEndian.Uint16(data[i:])).Float32()
+		case BFLOAT16_ARRAY:
+			elem = bfloat16.BFloat16FromBits(binary.LittleEndian.Uint16(data[i:])).Float32()
+		case INT32_ARRAY:
+			elem = int32(binary.LittleEndian.Uint32(data[i:]))
+		case UINT32_ARRAY:
+			elem = binary.LittleEndian.Uint32(data[i:])
+		case FLOAT32_ARRAY:
+			elem = math.Float32frombits(binary.LittleEndian.Uint32(data[i:]))
+		case INT64_ARRAY:
+			elem = int64(binary.LittleEndian.Uint64(data[i:]))
+		case UINT64_ARRAY:
+			elem = binary.LittleEndian.Uint64(data[i:])
+		case FLOAT64_ARRAY:
+			elem = math.Float64frombits(binary.LittleEndian.Uint64(data[i:]))
+		}
+		elems = append(elems, elem)
+		texts = append(texts, fmt.Sprint(elem))
+	}
+	return elems, fmt.Sprintf(" len=%d = [%s]", len(elems), strings.Join(texts, " "))
+}
+
+func (d *dumper) collection(head string, spec *TypeSpec) any {
+	length := d.ctx.ReadCollectionLength()
+	if d.ctx.HasError() {
+		return nil
+	}
+	elems := make([]any, 0, length)
+	if length == 0 {
+		d.line("%s len=0", head)
+		return elems
+	}
+	header := d.ctx.buffer.ReadUint8(d.ctx.Err())
+	if d.ctx.HasError() {
+		return nil
+	}
+	d.line("%s len=%d flags=%s", head, length, collectionFlagNames(header))
+	// Elements have ref flags when nulls or references are present.
+	readRef := header&(CollectionTrackingRef|CollectionHasNull) != 0
+	var elemSpec *TypeSpec
+	var elemType *dumpType
+	switch {
+	case header&CollectionIsDeclElementType != 0:
+		if spec == nil || spec.elementType == nil {
+			d.ctx.SetError(DeserializationError("collection declares its element type, but no declared type is known"))
+			return nil
+		}
+		elemSpec = spec.elementType
+	case header&CollectionIsSameType != 0:
+		t := d.readType()
+		if d.ctx.HasError() {
+			return nil
+		}
+		elemType = &t
+	}
+	ref := d.pendingRef
+	d.pendingRef = -1
+	d.nested(func() {
+		for i := 0; i < length && !d.ctx.HasError(); i++ {
+			elems = append(elems, d.element(fmt.Sprintf("[%d]", i), elemSpec, elemType, readRef))
+		}
+	})
+	d.pendingRef = ref
+	return elems
+}
+
+// element dumps a collection element or map entry, whose type comes from the
+// declared spec, a shared type read once, or its own type info.
+func (d *dumper) element(label string, spec *TypeSpec, t *dumpType, readRef bool) any {
+	switch {
+	case spec != nil:
+		return d.value(label, spec, readRef)
+	case t != nil:
+		flag := ""
+		if readRef {
+			var value any
+			var done bool
+			if flag, value, done = d.readRefFlag(label); done {
+				return value
+			}
+		}
+		return d.data(label, flag, *t, nil)
+	default:
+		return d.anyValue(label, readRef)
+	}
+}
+
+func (d *dumper) mapValue(head string, spec *TypeSpec) any {
+	length := d.ctx.ReadCollectionLength()
+	if d.ctx.HasError() {
+		return nil
+	}
+	d.line("%s len=%d", head, length)
+	value := make(map[any]any, length)
+	d.track(value)
+	var keySpec, valueSpec *TypeSpec
+	if spec != nil {
+		keySpec, valueSpec = spec.keyType, spec.valueType
+	}
+	d.nested(func() {
+		for read := 0; read < length && !d.ctx.HasError(); {
+			read += d.mapChunk(value, length-read, keySpec, valueSpec)
+		}
+	})
+	return value
+}
+
+// mapChunk dumps one chunk of map entries into m and returns how many it holds.
+func (d *dumper) mapChunk(m map[any]any, remaining int, keySpec, valueSpec *TypeSpec) int {
+	err := d.ctx.Err()
+	header := d.ctx.buffer.ReadUint8(err)
+	if d.ctx.HasError() {
+		return 0
+	}
+	keyNull := header&KEY_HAS_NULL != 0
+	valueNull := header&VALUE_HAS_NULL != 0
+	size := 1
+	if !keyNull && !valueNull {
+		size = int(d.ctx.buffer.ReadUint8(err))
+		if d.ctx.HasError() {
+			return 0
+		}
+		if size == 0 || size > remaining {
+			d.ctx.SetError(DeserializationErrorf("invalid map chunk size %d for remaining length %d", size, remaining))
+			return 0
+		}
+	}
+	d.line("chunk size=%d flags=%s", size, mapFlagNames(header))
+	var keyType, valueType *dumpType
+	if !keyNull && header&KEY_DECL_TYPE == 0 {
+		t := d.readType()
+		keyType = &t
+	}
+	if !valueNull && header&VALUE_DECL_TYPE == 0 {
+		t := d.readType()
+		valueType = &t
+	}
+	if d.ctx.HasError() {
+		return 0
+	}
+	if header&KEY_DECL_TYPE == 0 {
+		keySpec = nil
+	}
+	if header&VALUE_DECL_TYPE == 0 {
+		valueSpec = nil
+	}
+	d.nested(func() {
+		for i := 0; i < size && !d.ctx.HasError(); i++ {
+			var key, value any
+			if keyNull {
+				d.line("key: [null]")
+			} else {
+				key = d.element("key", keySpec, keyType, header&TRACKING_KEY_REF != 0)
+			}
+			if valueNull {
+				d.line("value: [null]")
+			} else {
+				value = d.element("value", valueSpec, valueType, header&TRACKING_VALUE_REF != 0)
+			}
+			if key != nil && !reflect.TypeOf(key).Comparable() {
+				key = fmt.Sprint(key)
+			}
+			m[key] = value
+		}
+	})
+	return size
+}
+
+func (d *dumper) structValue(head string, t dumpType) any {
+	if !t.hasFields {
+		d.line("%s", head)
+		d.ctx.SetError(DeserializationErrorf("cannot dump struct %s: schema-consistent payloads do not describe struct fields", t.name))
+		return nil
+	}
+	d.line("%s fields=%d", head, len(t.fields))
+	value := make(map[string]any, len(t.fields))
+	d.track(value)
+	var presence []byte
+	if t.omitZero {
+		presence = readFieldPresence(d.ctx, len(t.fields))
+	}
+	d.nested(func() {
+		for i, field := range t.fields {
+			if !fieldPresent(presence, i) {
+				continue
+			}
+			label := field.name
+			if field.tagID >= 0 {
+				label = fmt.Sprintf("#%d", field.tagID)
+			}
+			value[label] = d.value(label, field.typeSpec, field.trackRef || field.nullable)
+			if d.ctx.HasError() {
+				return
+			}
+		}
+	})
+	return value
+}
+
+func collectionFlagNames(header byte) string {
+	return flagNames(header, []flagName{
+		{CollectionTrackingRef, "track_ref"},
+		{CollectionHasNull, "has_null"},
+		{CollectionIsDeclElementType, "decl_type"},
+		{CollectionIsSameType, "same_type"},
+	})
+}
+
+func mapFlagNames(header byte) string {
+	return flagNames(header, []flagName{
+		{TRACKING_KEY_REF, "track_key_ref"},
+		{KEY_HAS_NULL, "key_null"},
+		{KEY_DECL_TYPE, "key_decl_type"},
+		{TRACKING_VALUE_REF, "track_value_ref"},
+		{VALUE_HAS_NULL, "value_null"},
+		{VALUE_DECL_TYPE, "value_decl_type"},
+	})
+}
+
+type flagName struct {
+	bit  byte
+	name string
+}
+
+func flagNames(header byte, names []flagName) string {
+	var set []string
+	for _, n := range names {
+		if header&n.bit != 0 {
+			set = append(set, n.name)
+		}
+	}
+	if len(set) == 0 {
+		return "none"
+	}
+	return strings.Join(set, "|")
+}
+
+var typeIdNames = [...]string{
+	UNKNOWN:                 "UNKNOWN",
+	BOOL:                    "BOOL",
+	INT8:                    "INT8",
+	INT16:                   "INT16",
+	INT32:                   "INT32",
+	VARINT32:                "VARINT32",
+	INT64:                   "INT64",
+	VARINT64:                "VARINT64",
+	TAGGED_INT64:            "TAGGED_INT64",
+	UINT8:                   "UINT8",
+	UINT16:                  "UINT16",
+	UINT32:                  "UINT32",
+	VAR_UINT32:              "VAR_UINT32",
+	UINT64:                  "UINT64",
+	VAR_UINT64:              "VAR_UINT64",
+	TAGGED_UINT64:           "TAGGED_UINT64",
+	FLOAT8:                  "FLOAT8",
+	FLOAT16:                 "FLOAT16",
+	BFLOAT16:                "BFLOAT16",
+	FLOAT32:                 "FLOAT32",
+	FLOAT64:                 "FLOAT64",
+	STRING:                  "STRING",
+	LIST:                    "LIST",
+	SET:                     "SET",
+	MAP:                     "MAP",
+	ENUM:                    "ENUM",
+	NAMED_ENUM:              "NAMED_ENUM",
+	STRUCT:                  "STRUCT",
+	COMPATIBLE_STRUCT:       "COMPATIBLE_STRUCT",
+	NAMED_STRUCT:            "NAMED_STRUCT",
+	NAMED_COMPATIBLE_STRUCT: "NAMED_COMPATIBLE_STRUCT",
+	EXT:                     "EXT",
+	NAMED_EXT:               "NAMED_EXT",
+	UNION:                   "UNION",
+	TYPED_UNION:             "TYPED_UNION",
+	NAMED_UNION:             "NAMED_UNION",
+	NONE:                    "NONE",
+	DURATION:                "DURATION",
+	TIMESTAMP:               "TIMESTAMP",
+	DATE:                    "DATE",
+	DECIMAL:                 "DECIMAL",
+	BINARY:                  "BINARY",
+	ARRAY:                   "ARRAY",
+	BOOL_ARRAY:              "BOOL_ARRAY",
+	INT8_ARRAY:              "INT8_ARRAY",
+	INT16_ARRAY:             "INT16_ARRAY",
+	INT32_ARRAY:             "INT32_ARRAY",
+	INT64_ARRAY:             "INT64_ARRAY",
+	UINT8_ARRAY:             "UINT8_ARRAY",
+	UINT16_ARRAY:            "UINT16_ARRAY",
+	UINT32_ARRAY:            "UINT32_ARRAY",
+	UINT64_ARRAY:            "UINT64_ARRAY",
+	FLOAT8_ARRAY:            "FLOAT8_ARRAY",
+	FLOAT16_ARRAY:           "FLOAT16_ARRAY",
+	BFLOAT16_ARRAY:          "BFLOAT16_ARRAY",
+	FLOAT32_ARRAY:           "FLOAT32_ARRAY",
+	FLOAT64_ARRAY:           "FLOAT64_ARRAY",
+}
+
+func typeIdName(typeID TypeId) string {
+	if int(typeID) < len(typeIdNames) {
+		return typeIdNames[typeID]
+	}
+	return fmt.Sprintf("TYPE_%d", typeID)
+}
@@ -0,0 +1,154 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type dumpItem struct {
+	Name  string
+	Count int32
+}
+
+type dumpOrder struct {
+	ID    int64
+	Items []*dumpItem
+	Tags  map[string]int32
+	Note  *string
+}
+
+func TestDumpStruct(t *testing.T) {
+	f := New(WithXlang(true), WithTrackRef(true))
+	require.NoError(t, f.RegisterStructByName(dumpItem{}, "demo.Item"))
+	require.NoError(t, f.RegisterStructByName(dumpOrder{}, "demo.Order"))
+	item := &dumpItem{Name: "apple", Count: 2}
+	data, err := f.Serialize(&dumpOrder{ID: 7, Items: []*dumpItem{item, item}, Tags: map[string]int32{"x": 1}})
+	require.NoError(t, err)
+
+	// The reader needs no registrations: names come from the TypeDefs.
+	out, err := Dump(data)
+	require.NoError(t, err)
+	require.Equal(t, `header: xlang=true out_of_band=false codec=0 checksum=false
+root: [ref-value #0] NAMED_COMPATIBLE_STRUCT demo.Order fields=4
+  i_d: VARINT64 = 7
+  items: LIST len=2 flags=track_ref|same_type
+    [0]: [ref-value #1] NAMED_COMPATIBLE_STRUCT demo.Item fields=2
+      count: VARINT32 = 2
+      name: STRING = "apple"
+    [1]: [ref #1]
+  note: [null]
+  tags: MAP len=1
+    chunk size=1 flags=key_decl_type|value_decl_type
+      key: STRING = "x"
+      value: VARINT32 = 1
+`, out)
+}
+
+func TestDumpValues(t *testing.T) {
+	f := New(WithXlang(false))
+	tests := []struct {
+		value    any
+		expected string
+	}{
+		{"hello", `root: [not-null] STRING = "hello"`},
+		{int32(-5), `root: [not-null] VARINT32 = -5`},
+		{[]int16{1, -2}, `root: [not-null] INT16_ARRAY len=2 = [1 -2]`},
+		{[]byte{0xca, 0xfe}, `root: [not-null] BINARY len=2 = cafe`},
+		{[]any{int64(1), "a", nil}, "root: [not-null] LIST len=3 flags=has_null\n" +
+			"  [0]: [not-null] VARINT64 = 1\n" +
+			"  [1]: [not-null] STRING = \"a\"\n" +
+			"  [2]: [null]"},
+	}
+	for _, test := range tests {
+		data, err := f.Serialize(test.value)
+		require.NoError(t, err)
+		out, err := Dump(data)
+		require.NoError(t, err)
+		require.Equal(t, "header: xlang=false out_of_band=false codec=0 checksum=false\n"+test.expected+"\n", out)
+	}
+}
+
+func TestDumpChecksumAndCompression(t *testing.T) {
+	data, err := New(WithXlang(true), WithChecksum(true)).Serialize("hi")
+	require.NoError(t, err)
+	out, err := Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, out, "checksum=true\nroot: [not-null] STRING = \"hi\"\n")
+
+	data[len(data)-1] ^= 0xff
+	_, err = Dump(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "checksum mismatch")
+
+	data, err = New(WithXlang(true), WithCompression(flateCodec{})).Serialize("hi")
+	require.NoError(t, err)
+	out, err = Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, out, "codec=1 checksum=false\nbody: compressed with codec 1, not decoded\n")
+}
+
+func TestDumpSchemaConsistentStruct(t *testing.T) {
+	f := New(WithXlang(true), WithCompatible(false))
+	require.NoError(t, f.RegisterStructByName(dumpItem{}, "demo.Item"))
+	data, err := f.Serialize(&dumpItem{Name: "apple"})
+	require.NoError(t, err)
+
+	out, err := Dump(data)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "schema-consistent payloads do not describe struct fields")
+	require.Contains(t, out, "root: [not-null] NAMED_STRUCT demo.Item\n")
+}
+
+func TestDumpTruncated(t *testing.T) {
+	data, err := New(WithXlang(true)).Serialize([]any{"abc", "def"})
+	require.NoError(t, err)
+	out, err := Dump(data[:len(data)-2])
+	require.Error(t, err)
+	require.Contains(t, out, `[0]: STRING = "abc"`)
+}
+
+func TestDumpValue(t *testing.T) {
+	f := New(WithXlang(true), WithTrackRef(true))
+	require.NoError(t, f.RegisterStructByName(dumpItem{}, "demo.Item"))
+	require.NoError(t, f.RegisterStructByName(dumpOrder{}, "demo.Order"))
+	item := &dumpItem{Name: "apple", Count: 2}
+	data, err := f.Serialize(&dumpOrder{ID: 7, Items: []*dumpItem{item, item}, Tags: map[string]int32{"x": 1}})
+	require.NoError(t, err)
+
+	value, err := DumpValue(data)
+	require.NoError(t, err)
+	order := value.(map[string]any)
+	require.Equal(t, int64(7), order["i_d"])
+	require.Nil(t, order["note"])
+	require.Equal(t, map[any]any{"x": int32(1)}, order["tags"])
+	items := order["items"].([]any)
+	require.Equal(t, map[string]any{"name": "apple", "count": int32(2)}, items[0])
+	// Shared values stay shared.
+	items[0].(map[string]any)["name"] = "pear"
+	require.Equal(t, "pear", items[1].(map[string]any)["name"])
+
+	compressed, err := New(WithXlang(true), WithCompression(flateCodec{})).Serialize("hi")
+	require.NoError(t, err)
+	_, err = DumpValue(compressed)
+	require.Error(t, err)
+}
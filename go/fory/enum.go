@@ -26,23 +26,47 @@ import (
 type enumSerializer struct {
 	type_  reflect.Type
 	typeID uint32 // Full type ID including user ID
+	// values and ordinals map ordinals to values and back for enums registered
+	// with RegisterEnumByOrdinal. Values are stored as their integer bits.
+	values   []uint64
+	ordinals map[uint64]uint32
 }
 
 func (s *enumSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	// Convert the enum value to its integer ordinal
-	var ordinal uint32
+	var bits uint64
 	switch value.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		ordinal = uint32(value.Int())
+		bits = uint64(value.Int())
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		ordinal = uint32(value.Uint())
+		bits = value.Uint()
 	default:
 		ctx.SetError(SerializationErrorf("enum serializer: unsupported kind %v", value.Kind()))
 		return
 	}
+	ordinal := uint32(bits)
+	if s.ordinals != nil {
+		var ok bool
+		if ordinal, ok = s.ordinals[bits]; !ok {
+			ctx.SetError(SerializationErrorf("value %v of enum %v has no registered ordinal", value, s.type_))
+			return
+		}
+	}
 	ctx.buffer.WriteVarUint32Small7(ordinal)
 }
 
+// valueBits returns the integer bits of the value with the given ordinal.
+func (s *enumSerializer) valueBits(ctx *ReadContext, ordinal uint32) (uint64, bool) {
+	if s.values == nil {
+		return uint64(ordinal), true
+	}
+	if uint64(ordinal) >= uint64(len(s.values)) {
+		ctx.SetError(DeserializationErrorf("ordinal %d is out of range for enum %v with %d values", ordinal, s.type_, len(s.values)))
+		return 0, false
+	}
+	return s.values[ordinal], true
+}
+
 func (s *enumSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
 	if refMode != RefModeNone {
 		ctx.buffer.WriteInt8(NotNullValueFlag)
@@ -66,12 +90,17 @@ func (s *enumSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 
+	bits, ok := s.valueBits(ctx, ordinal)
+	if !ok {
+		return
+	}
+
 	// Set the value based on the underlying kind
 	switch value.Kind() {
 	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
-		value.SetInt(int64(ordinal))
+		value.SetInt(int64(bits))
 	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
-		value.SetUint(uint64(ordinal))
+		value.SetUint(bits)
 	default:
 		ctx.SetError(DeserializationErrorf("enum serializer: unsupported kind %v", value.Kind()))
 	}
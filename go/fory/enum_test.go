@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -96,3 +98,70 @@ func TestNamedEnumReadConsumesNamedTypeInfo(t *testing.T) {
 	require.Equal(t, namedAuditEnum(3), result)
 	require.Equal(t, buf.WriterIndex(), f.readCtx.Buffer().ReaderIndex())
 }
+
+func TestNamedEnumRootValueWithSharedMeta(t *testing.T) {
+	f := NewFory(WithXlang(true), WithCompatible(true))
+	require.NoError(t, f.RegisterEnumByName(namedAuditEnum(0), "example.NamedAuditEnum"))
+	data, err := f.Serialize(namedAuditEnum(2))
+	require.NoError(t, err)
+	var result namedAuditEnum
+	require.NoError(t, f.Deserialize(data, &result))
+	require.Equal(t, namedAuditEnum(2), result)
+}
+
+type ordinalEnum int64
+
+const (
+	ordinalLow  ordinalEnum = -5
+	ordinalMid  ordinalEnum = 100
+	ordinalHigh ordinalEnum = 1 << 40
+)
+
+type ordinalHolder struct {
+	Level ordinalEnum
+	Ptr   *ordinalEnum
+	List  []ordinalEnum
+}
+
+func TestRegisterEnumByOrdinal(t *testing.T) {
+	for _, compatible := range []bool{false, true} {
+		f := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, f.RegisterEnumByOrdinal(ordinalEnum(0), 110, ordinalLow, ordinalMid, ordinalHigh))
+		require.NoError(t, f.RegisterStruct(ordinalHolder{}, 111))
+
+		data, err := f.Serialize(ordinalHigh)
+		require.NoError(t, err)
+		require.Equal(t, byte(2), data[len(data)-1])
+		var root ordinalEnum
+		require.NoError(t, f.Deserialize(data, &root))
+		require.Equal(t, ordinalHigh, root)
+
+		mid := ordinalMid
+		value := &ordinalHolder{Level: ordinalLow, Ptr: &mid, List: []ordinalEnum{ordinalHigh, ordinalLow}}
+		data, err = f.Serialize(value)
+		require.NoError(t, err)
+		var result ordinalHolder
+		require.NoError(t, f.Deserialize(data, &result))
+		require.Equal(t, *value, result)
+
+		clone := mustClone(t, f)
+		require.NoError(t, clone.Deserialize(data, &result))
+		require.Equal(t, *value, result)
+
+		_, err = f.Serialize(ordinalEnum(7))
+		require.Error(t, err)
+	}
+
+	f := New(WithXlang(true), WithPanicRecovery(false))
+	require.NoError(t, f.RegisterEnum(ordinalEnum(0), 110))
+	data, err := f.Serialize(ordinalEnum(3))
+	require.NoError(t, err)
+	ordinals := New(WithXlang(true), WithPanicRecovery(false))
+	require.NoError(t, ordinals.RegisterEnumByOrdinal(ordinalEnum(0), 110, ordinalLow, ordinalMid, ordinalHigh))
+	var result ordinalEnum
+	require.Error(t, ordinals.Deserialize(data, &result))
+
+	require.Error(t, New().RegisterEnumByOrdinal(ordinalEnum(0), 110))
+	require.Error(t, New().RegisterEnumByOrdinal(ordinalEnum(0), 110, ordinalLow, int64(1)))
+	require.Error(t, New().RegisterEnumByOrdinal(ordinalEnum(0), 110, ordinalLow, ordinalLow))
+}
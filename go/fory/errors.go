@@ -18,7 +18,9 @@
 package fory
 
 import (
+	"errors"
 	"fmt"
+	"io"
 	"os"
 	"strings"
 )
@@ -46,7 +48,7 @@ const (
 	ErrKindNilPointer
 	// ErrKindInvalidRefId indicates an invalid reference ID
 	ErrKindInvalidRefId
-	// ErrKindHashMismatch indicates struct hash mismatch
+	// ErrKindHashMismatch indicates a struct or meta string hash mismatch
 	ErrKindHashMismatch
 	// ErrKindInvalidTag indicates invalid fory struct tag configuration
 	ErrKindInvalidTag
@@ -56,8 +58,16 @@ const (
 	ErrKindMaxCollectionSizeExceeded
 	// ErrKindMaxBinarySizeExceeded indicates max binary size exceeded
 	ErrKindMaxBinarySizeExceeded
+	// ErrKindCanceled indicates the context of the call was done
+	ErrKindCanceled
+	// ErrKindBudgetExceeded indicates the decode budget was exceeded
+	ErrKindBudgetExceeded
 )
 
+// ErrBudgetExceeded matches, with errors.Is, errors returned when a call
+// decodes more than the budget set with WithDecodeBudget.
+var ErrBudgetExceeded = errors.New("fory: decode budget exceeded")
+
 // Error is a lightweight error type optimized for hot path performance.
 // It stores error details without allocating until Error() is called.
 type Error struct {
@@ -74,6 +84,8 @@ type Error struct {
 	actualHash   int32
 	expectedHash int32
 	stack        []string
+	// cause is the error returned by a CheckedSerializer
+	cause error
 }
 
 var panicOnError = parsePanicOnError()
@@ -114,6 +126,24 @@ func (e Error) Kind() ErrorKind {
 	return e.kind
 }
 
+// Is reports out-of-bound reads, which mean the payload is truncated, as
+// io.ErrUnexpectedEOF, so callers can test errors.Is(err, io.ErrUnexpectedEOF),
+// and exceeded decode budgets as ErrBudgetExceeded.
+func (e Error) Is(target error) bool {
+	switch target {
+	case io.ErrUnexpectedEOF:
+		return e.kind == ErrKindBufferOutOfBound
+	case ErrBudgetExceeded:
+		return e.kind == ErrKindBudgetExceeded
+	}
+	return false
+}
+
+// Unwrap returns the error a CheckedSerializer returned, if any.
+func (e Error) Unwrap() error {
+	return e.cause
+}
+
 func (e Error) reverseStackString() string {
 	if len(e.stack) == 0 {
 		return ""
@@ -196,7 +226,50 @@ func HashMismatchError(actual, expected int32, typeName string) Error {
 		kind:         ErrKindHashMismatch,
 		actualHash:   actual,
 		expectedHash: expected,
-		message:      fmt.Sprintf("hash %d is not consistent with %d for type %s", actual, expected, typeName),
+		message: fmt.Sprintf("struct hash mismatch for type %s: payload hash %d, local hash %d",
+			typeName, actual, expected),
+	})
+}
+
+// schemaDigestMismatchError creates an error for a peer whose schema digest
+// differs from the local one.
+//
+//go:noinline
+func schemaDigestMismatchError(peer, local uint64) Error {
+	return panicIfEnabled(Error{
+		kind: ErrKindHashMismatch,
+		message: fmt.Sprintf("schema digest mismatch: peer digest %016x, local digest %016x; "+
+			"compare the output of ExportSchemas on both sides", peer, local),
+	})
+}
+
+// structHashMismatchError creates a struct hash mismatch error that lists the
+// local struct fingerprint and the field explainHashMismatch finds to differ
+// in the writer's.
+//
+//go:noinline
+func structHashMismatchError(actual, expected int32, typeName, fingerprint string) Error {
+	return panicIfEnabled(Error{
+		kind:         ErrKindHashMismatch,
+		actualHash:   actual,
+		expectedHash: expected,
+		message: fmt.Sprintf("struct hash mismatch for type %s: payload hash %d, local hash %d; "+
+			"local fields (id_or_name,type_id,ref,nullable): %s "+
+			"differing fields: %s; "+
+			"compare with the writer's fingerprint (ENABLE_FORY_DEBUG_OUTPUT=1) or use compatible mode",
+			typeName, actual, expected, fingerprint, explainHashMismatch(fingerprint, actual)),
+	})
+}
+
+// metaStringHashMismatchError reports a meta string body that does not match
+// the hash written before it.
+//
+//go:noinline
+func metaStringHashMismatchError(header, body int64) Error {
+	return panicIfEnabled(Error{
+		kind: ErrKindHashMismatch,
+		message: fmt.Sprintf("meta string hash mismatch: header hash %#x, body hash %#x",
+			uint64(header), uint64(body)),
 	})
 }
 
@@ -310,6 +383,14 @@ func MaxCollectionSizeExceededError(size, limit int) Error {
 	})
 }
 
+// canceledError reports that the context of a MarshalContext or
+// UnmarshalContext call is done, wrapping the context's error.
+//
+//go:noinline
+func canceledError(err error) Error {
+	return causeError(err, ErrKindCanceled)
+}
+
 // MaxBinarySizeExceededError creates a max binary size exceeded error
 //
 //go:noinline
@@ -320,6 +401,16 @@ func MaxBinarySizeExceededError(size, limit int) Error {
 	})
 }
 
+// DecodeBudgetExceededError creates a decode budget exceeded error
+//
+//go:noinline
+func DecodeBudgetExceededError(limit int) Error {
+	return panicIfEnabled(Error{
+		kind:    ErrKindBudgetExceeded,
+		message: fmt.Sprintf("decode budget exceeded: limit=%d bytes", limit),
+	})
+}
+
 // WrapError wraps a standard error into a fory Error
 //
 //go:noinline
@@ -351,8 +442,31 @@ func FromError(err error) Error {
 	})
 }
 
+// causeError reports err with kind, keeping it reachable through Unwrap.
+// Fory errors are returned as-is.
+//
+//go:noinline
+func causeError(err error, kind ErrorKind) Error {
+	if e, ok := err.(Error); ok {
+		return e
+	}
+	return panicIfEnabled(Error{
+		kind:    kind,
+		message: err.Error(),
+		cause:   err,
+	})
+}
+
 // Pointer receiver methods for *Error (used for error accumulation)
 
+// setFirst records err unless an error was already recorded, so a read that
+// runs past a truncated payload keeps the original out-of-bound error.
+func (e *Error) setFirst(err Error) {
+	if e != nil && e.kind == ErrKindOK {
+		*e = err
+	}
+}
+
 // SetError sets the error if no error has occurred yet (first-error-wins)
 func (e *Error) SetError(err error) {
 	if e == nil || e.kind != ErrKindOK {
@@ -0,0 +1,121 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package arrow serializes Apache Arrow record batches from
+// github.com/apache/arrow-go.
+//
+// A record batch is written as an extension type registered under TypeName.
+// Its data is the batch in the Arrow IPC streaming format, schema included,
+// as a length-prefixed byte string, so any Arrow implementation can decode
+// it:
+//
+//	if err := arrow.Register(f); err != nil {
+//	    return err
+//	}
+//	data, err := f.Serialize(batch)
+//
+// Batches decoded by Fory are allocated with memory.DefaultAllocator and must
+// be released by the caller like any other batch.
+//
+// The package is a separate module, so the core module does not depend on
+// github.com/apache/arrow-go.
+package arrow
+
+import (
+	"bytes"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/ipc"
+	"github.com/apache/fory/go/fory"
+)
+
+// TypeName is the name record batches are registered under in every
+// language.
+const TypeName = "arrow.RecordBatch"
+
+// Registry is implemented by *fory.Fory and *threadsafe.Fory.
+type Registry interface {
+	RegisterExtensionByName(type_ any, name string, serializer fory.ExtensionSerializer) error
+}
+
+// Register registers the record batch type of array.NewRecordBatch, which
+// the IPC reader also returns, as the record batch extension type. Values
+// and fields of type arrow.RecordBatch holding such batches are serialized
+// with it.
+func Register(r Registry) error {
+	empty := array.NewRecordBatch(arrow.NewSchema(nil, nil), nil, 0)
+	defer empty.Release()
+	return r.RegisterExtensionByName(empty, TypeName, serializer{})
+}
+
+type serializer struct{}
+
+func (serializer) WriteData(ctx *fory.WriteContext, value reflect.Value) {
+	if value.Kind() != reflect.Ptr {
+		value = value.Addr()
+	}
+	batch := value.Interface().(arrow.RecordBatch)
+	var buf bytes.Buffer
+	w := ipc.NewWriter(&buf, ipc.WithSchema(batch.Schema()))
+	err := w.Write(batch)
+	if err == nil {
+		err = w.Close()
+	}
+	if err != nil {
+		ctx.SetError(fory.SerializationErrorf("arrow: %v", err))
+		return
+	}
+	ctx.WriteBinary(buf.Bytes())
+}
+
+func (serializer) ReadData(ctx *fory.ReadContext, value reflect.Value) {
+	data := ctx.ReadBinary()
+	if ctx.HasError() {
+		return
+	}
+	batch, err := readBatch(data)
+	if err != nil {
+		ctx.SetError(fory.DeserializationErrorf("arrow: %v", err))
+		return
+	}
+	switch value.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		value.Set(reflect.ValueOf(batch))
+	default:
+		value.Set(reflect.ValueOf(batch).Elem())
+	}
+}
+
+func readBatch(data []byte) (arrow.RecordBatch, error) {
+	r, err := ipc.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer r.Release()
+	if !r.Next() {
+		if err := r.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("stream holds no record batch")
+	}
+	batch := r.RecordBatch()
+	batch.Retain()
+	return batch, nil
+}
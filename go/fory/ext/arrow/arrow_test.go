@@ -0,0 +1,93 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package arrow
+
+import (
+	"testing"
+
+	"github.com/apache/arrow-go/v18/arrow"
+	"github.com/apache/arrow-go/v18/arrow/array"
+	"github.com/apache/arrow-go/v18/arrow/memory"
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+	"github.com/stretchr/testify/require"
+)
+
+type report struct {
+	Name    string
+	Batch   arrow.RecordBatch
+	Batches []arrow.RecordBatch
+}
+
+func newBatch(t *testing.T) arrow.RecordBatch {
+	schema := arrow.NewSchema([]arrow.Field{
+		{Name: "id", Type: arrow.PrimitiveTypes.Int64},
+		{Name: "name", Type: arrow.BinaryTypes.String, Nullable: true},
+	}, nil)
+	b := array.NewRecordBuilder(memory.DefaultAllocator, schema)
+	defer b.Release()
+	b.Field(0).(*array.Int64Builder).AppendValues([]int64{1, 2, 3}, nil)
+	b.Field(1).(*array.StringBuilder).AppendValues([]string{"a", "", "c"}, []bool{true, false, true})
+	return b.NewRecordBatch()
+}
+
+func requireBatchEqual(t *testing.T, want, got arrow.RecordBatch) {
+	t.Helper()
+	require.NotNil(t, got)
+	require.True(t, array.RecordEqual(want, got), "got %v", got)
+}
+
+func TestRoundTrip(t *testing.T) {
+	batch := newBatch(t)
+	defer batch.Release()
+	for _, xlang := range []bool{true, false} {
+		f := fory.New(fory.WithXlang(xlang))
+		require.NoError(t, Register(f))
+		require.NoError(t, f.RegisterStructByName(report{}, "example.Report"))
+
+		data, err := f.Serialize(batch)
+		require.NoError(t, err)
+		var out arrow.RecordBatch
+		require.NoError(t, f.Deserialize(data, &out))
+		requireBatchEqual(t, batch, out)
+		out.Release()
+
+		in := &report{Name: "daily", Batch: batch, Batches: []arrow.RecordBatch{batch, nil}}
+		data, err = f.Serialize(in)
+		require.NoError(t, err)
+		var r report
+		require.NoError(t, f.Deserialize(data, &r))
+		require.Equal(t, "daily", r.Name)
+		requireBatchEqual(t, batch, r.Batch)
+		require.Len(t, r.Batches, 2)
+		requireBatchEqual(t, batch, r.Batches[0])
+		require.Nil(t, r.Batches[1])
+	}
+}
+
+func TestThreadSafe(t *testing.T) {
+	batch := newBatch(t)
+	defer batch.Release()
+	f := threadsafe.New()
+	require.NoError(t, Register(f))
+	data, err := f.Serialize(batch)
+	require.NoError(t, err)
+	var out arrow.RecordBatch
+	require.NoError(t, f.Deserialize(data, &out))
+	requireBatchEqual(t, batch, out)
+}
@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package decimal serializes github.com/shopspring/decimal.Decimal values as
+// the DECIMAL type of the xlang specification:
+//
+//	if err := decimal.Register(f); err != nil {
+//	    return err
+//	}
+//
+// Values are converted to and from fory.Decimal, so they share its wire
+// format and round-trip with the decimal types of other languages, such as
+// Java's BigDecimal and Python's Decimal. Payloads decoded into an interface
+// yield fory.Decimal.
+//
+// The package is a separate module, so the core module does not depend on
+// github.com/shopspring/decimal.
+package decimal
+
+import (
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/shopspring/decimal"
+)
+
+// Registry is implemented by *fory.Fory and *threadsafe.Fory.
+type Registry interface {
+	RegisterDecimal(type_ any, converter fory.DecimalConverter) error
+}
+
+// Register registers decimal.Decimal as the DECIMAL type.
+func Register(r Registry) error {
+	return r.RegisterDecimal(decimal.Decimal{}, converter{})
+}
+
+type converter struct{}
+
+func (converter) ToDecimal(value reflect.Value) fory.Decimal {
+	d := value.Interface().(decimal.Decimal)
+	return fory.NewDecimal(d.Coefficient(), -d.Exponent())
+}
+
+func (converter) FromDecimal(d fory.Decimal, value reflect.Value) {
+	value.Set(reflect.ValueOf(decimal.NewFromBigInt(&d.Unscaled, -d.Scale)))
+}
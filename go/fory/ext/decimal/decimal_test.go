@@ -0,0 +1,135 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package decimal
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+	"github.com/shopspring/decimal"
+	"github.com/stretchr/testify/require"
+)
+
+type invoice struct {
+	Total    decimal.Decimal
+	Discount *decimal.Decimal
+	Lines    []decimal.Decimal
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, opts := range [][]fory.Option{
+		{fory.WithXlang(true)},
+		{fory.WithXlang(true), fory.WithCompatible(false)},
+		{fory.WithXlang(false)},
+	} {
+		f := fory.New(opts...)
+		require.NoError(t, Register(f))
+		require.NoError(t, f.RegisterStructByName(invoice{}, "example.Invoice"))
+
+		for _, s := range []string{"0", "1", "-1", "128", "-128", "3.14159", "-0.001", "123456789012345678901234567890.5"} {
+			d := decimal.RequireFromString(s)
+			data, err := f.Serialize(&d)
+			require.NoError(t, err)
+			var out decimal.Decimal
+			require.NoError(t, f.Deserialize(data, &out))
+			require.True(t, d.Equal(out), "%s decoded as %s", s, out)
+		}
+
+		discount := decimal.RequireFromString("-2.50")
+		in := &invoice{
+			Total:    decimal.RequireFromString("99.99"),
+			Discount: &discount,
+			Lines:    []decimal.Decimal{decimal.RequireFromString("1e-20"), decimal.Zero},
+		}
+		data, err := f.Serialize(in)
+		require.NoError(t, err)
+		var out invoice
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, in.Total.String(), out.Total.String())
+		require.Equal(t, in.Discount.String(), out.Discount.String())
+		require.Len(t, out.Lines, 2)
+		require.True(t, in.Lines[0].Equal(out.Lines[0]))
+	}
+}
+
+func TestThreadSafe(t *testing.T) {
+	f := threadsafe.New()
+	require.NoError(t, Register(f))
+	d := decimal.RequireFromString("-42.125")
+	data, err := f.Serialize(&d)
+	require.NoError(t, err)
+	var out decimal.Decimal
+	require.NoError(t, f.Deserialize(data, &out))
+	require.True(t, d.Equal(out))
+}
+
+type foryInvoice struct {
+	Total    fory.Decimal
+	Discount *fory.Decimal
+	Lines    []fory.Decimal
+}
+
+func TestForyDecimalPayloads(t *testing.T) {
+	values := []string{"0", "-1", "3.14159", "-0.001", "123456789012345678901234567890.5"}
+	for _, opts := range [][]fory.Option{
+		{fory.WithXlang(true)},
+		{fory.WithXlang(true), fory.WithCompatible(false)},
+	} {
+		plain := fory.New(opts...)
+		require.NoError(t, plain.RegisterStructByName(foryInvoice{}, "example.Invoice"))
+		f := fory.New(opts...)
+		require.NoError(t, Register(f))
+		require.NoError(t, f.RegisterStructByName(invoice{}, "example.Invoice"))
+
+		for _, s := range values {
+			d := decimal.RequireFromString(s)
+			written := fory.NewDecimal(d.Coefficient(), -d.Exponent())
+			data, err := plain.Serialize(&written)
+			require.NoError(t, err)
+			var out decimal.Decimal
+			require.NoError(t, f.Deserialize(data, &out))
+			require.True(t, d.Equal(out), "%s decoded as %s", s, out)
+
+			data, err = f.Serialize(&d)
+			require.NoError(t, err)
+			var back fory.Decimal
+			require.NoError(t, plain.Deserialize(data, &back))
+			require.True(t, written.Equal(back), "%s decoded as %s", s, back)
+			var dynamic any
+			require.NoError(t, f.Deserialize(data, &dynamic))
+			require.IsType(t, fory.Decimal{}, dynamic)
+		}
+
+		discount := fory.NewDecimal(big.NewInt(-250), 2)
+		in := &foryInvoice{
+			Total:    fory.NewDecimal(big.NewInt(9999), 2),
+			Discount: &discount,
+			Lines:    []fory.Decimal{fory.NewDecimal(big.NewInt(1), 20)},
+		}
+		data, err := plain.Serialize(in)
+		require.NoError(t, err)
+		var out invoice
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, "99.99", out.Total.String())
+		require.Equal(t, "-2.5", out.Discount.String())
+		require.Len(t, out.Lines, 1)
+		require.True(t, decimal.RequireFromString("1e-20").Equal(out.Lines[0]))
+	}
+}
@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package protobuf registers protobuf messages as Fory extension types using
+// the google.golang.org/protobuf runtime.
+//
+// Each message is registered under its full protobuf name, such as
+// "example.User", and written as its protobuf encoding in the layout of
+// package protocompat:
+//
+//	if err := protobuf.Register(f, &pb.User{}, &pb.Order{}); err != nil {
+//	    return err
+//	}
+//
+// The package is a separate module, so the core module does not depend on a
+// protobuf runtime. Use protocompat directly to register messages under
+// other names or with another runtime.
+package protobuf
+
+import (
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/protocompat"
+	"google.golang.org/protobuf/proto"
+)
+
+// Registry is implemented by *fory.Fory and *threadsafe.Fory.
+type Registry interface {
+	RegisterExtensionByName(type_ any, name string, serializer fory.ExtensionSerializer) error
+}
+
+// Register registers the type of each message, which must be a pointer to a
+// generated message struct, under the message's full protobuf name.
+func Register(r Registry, msgs ...proto.Message) error {
+	for _, msg := range msgs {
+		s, err := protocompat.NewSerializer(msg, proto.Marshal, proto.Unmarshal)
+		if err != nil {
+			return err
+		}
+		if err := r.RegisterExtensionByName(msg, TypeName(msg), s); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TypeName returns the name Register uses for the type of msg: its full
+// protobuf name.
+func TypeName(msg proto.Message) string {
+	return string(msg.ProtoReflect().Descriptor().FullName())
+}
@@ -0,0 +1,73 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protobuf
+
+import (
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+type audit struct {
+	At     *timestamppb.Timestamp
+	Labels []*wrapperspb.StringValue
+	Note   string
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		f := fory.New(fory.WithXlang(xlang))
+		require.NoError(t, Register(f, &timestamppb.Timestamp{}, &wrapperspb.StringValue{}))
+		require.NoError(t, f.RegisterStructByName(audit{}, "example.Audit"))
+
+		in := &audit{
+			At:     &timestamppb.Timestamp{Seconds: 1700000000, Nanos: 5},
+			Labels: []*wrapperspb.StringValue{wrapperspb.String("a"), nil},
+			Note:   "created",
+		}
+		data, err := f.Serialize(in)
+		require.NoError(t, err)
+		var out audit
+		require.NoError(t, f.Deserialize(data, &out))
+		require.True(t, proto.Equal(in.At, out.At))
+		require.Len(t, out.Labels, 2)
+		require.True(t, proto.Equal(in.Labels[0], out.Labels[0]))
+		require.Nil(t, out.Labels[1])
+		require.Equal(t, in.Note, out.Note)
+	}
+}
+
+func TestThreadSafe(t *testing.T) {
+	f := threadsafe.New()
+	require.NoError(t, Register(f, &timestamppb.Timestamp{}))
+	in := &timestamppb.Timestamp{Seconds: 42}
+	data, err := f.Serialize(in)
+	require.NoError(t, err)
+	var out *timestamppb.Timestamp
+	require.NoError(t, f.Deserialize(data, &out))
+	require.True(t, proto.Equal(in, out))
+}
+
+func TestTypeName(t *testing.T) {
+	require.Equal(t, "google.protobuf.Timestamp", TypeName(&timestamppb.Timestamp{}))
+}
@@ -0,0 +1,100 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package uuid serializes 16-byte UUID types, such as
+// github.com/google/uuid.UUID, so they round-trip with java.util.UUID.
+//
+// The Fory protocol has no fixed-size binary type, so a UUID is written as an
+// extension type registered under TypeName. Its data is 16 bytes with no
+// length prefix: the most significant 64 bits followed by the least
+// significant 64 bits, each as a little-endian int64, which is the layout of
+// Java's UUID serializer:
+//
+//	if err := uuid.Register(f, googleuuid.UUID{}); err != nil {
+//	    return err
+//	}
+//
+// Java registers java.util.UUID under the same name with its UUID serializer:
+//
+//	fory.register(UUID.class, "uuid");
+//	fory.registerSerializer(UUID.class, new Serializers.UUIDSerializer(fory));
+package uuid
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+)
+
+// TypeName is the name UUID types are registered under in every language.
+const TypeName = "uuid"
+
+// Registry is implemented by *fory.Fory and *threadsafe.Fory.
+type Registry interface {
+	RegisterExtensionByName(type_ any, name string, serializer fory.ExtensionSerializer) error
+}
+
+// Register registers the type of value, which must be a [16]byte array type,
+// as the UUID extension type.
+func Register(r Registry, value any) error {
+	type_ := reflect.TypeOf(value)
+	if type_ != nil && type_.Kind() == reflect.Ptr {
+		type_ = type_.Elem()
+	}
+	if type_ == nil || type_.Kind() != reflect.Array || type_.Len() != 16 || type_.Elem().Kind() != reflect.Uint8 {
+		return fmt.Errorf("uuid: type must be a [16]byte array, got %v", type_)
+	}
+	return r.RegisterExtensionByName(reflect.Zero(type_).Interface(), TypeName, serializer{})
+}
+
+type serializer struct{}
+
+func (serializer) WriteData(ctx *fory.WriteContext, value reflect.Value) {
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			value = reflect.Zero(value.Type().Elem())
+		} else {
+			value = value.Elem()
+		}
+	}
+	var b [16]byte
+	reflect.Copy(reflect.ValueOf(b[:]), value)
+	buf := ctx.Buffer()
+	buf.WriteInt64(int64(binary.BigEndian.Uint64(b[:8])))
+	buf.WriteInt64(int64(binary.BigEndian.Uint64(b[8:])))
+}
+
+func (serializer) ReadData(ctx *fory.ReadContext, value reflect.Value) {
+	buf := ctx.Buffer()
+	msb := buf.ReadInt64(ctx.Err())
+	lsb := buf.ReadInt64(ctx.Err())
+	if ctx.HasError() {
+		return
+	}
+	var b [16]byte
+	binary.BigEndian.PutUint64(b[:8], uint64(msb))
+	binary.BigEndian.PutUint64(b[8:], uint64(lsb))
+	if value.Kind() == reflect.Ptr {
+		ptr := reflect.New(value.Type().Elem())
+		reflect.Copy(ptr.Elem(), reflect.ValueOf(b[:]))
+		value.Set(ptr)
+		return
+	}
+	reflect.Copy(value, reflect.ValueOf(b[:]))
+}
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package uuid
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+	"github.com/stretchr/testify/require"
+)
+
+// UUID has the shape of github.com/google/uuid.UUID.
+type UUID [16]byte
+
+var id = UUID{
+	0x12, 0x3e, 0x45, 0x67, 0xe8, 0x9b, 0x12, 0xd3,
+	0xa4, 0x56, 0x42, 0x66, 0x14, 0x17, 0x40, 0x00,
+}
+
+type event struct {
+	ID      UUID
+	Parent  *UUID
+	Related []UUID
+	Name    string
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, opts := range [][]fory.Option{
+		{fory.WithXlang(true)},
+		{fory.WithXlang(true), fory.WithCompatible(false)},
+		{fory.WithXlang(false)},
+	} {
+		f := fory.New(opts...)
+		require.NoError(t, Register(f, UUID{}))
+		require.NoError(t, f.RegisterStructByName(event{}, "example.Event"))
+
+		data, err := f.Serialize(id)
+		require.NoError(t, err)
+		var out UUID
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, id, out)
+
+		parent := UUID{1}
+		in := &event{ID: id, Parent: &parent, Related: []UUID{{}, id}, Name: "created"}
+		data, err = f.Serialize(in)
+		require.NoError(t, err)
+		var ev event
+		require.NoError(t, f.Deserialize(data, &ev))
+		require.Equal(t, in, &ev)
+	}
+}
+
+func TestThreadSafe(t *testing.T) {
+	f := threadsafe.New()
+	require.NoError(t, Register(f, &UUID{}))
+	data, err := f.Serialize(id)
+	require.NoError(t, err)
+	var out UUID
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, id, out)
+}
+
+func TestJavaLayout(t *testing.T) {
+	f := fory.New(fory.WithXlang(true))
+	require.NoError(t, Register(f, UUID{}))
+	data, err := f.Serialize(id)
+	require.NoError(t, err)
+	// java.util.UUID writes getMostSignificantBits then
+	// getLeastSignificantBits, each as a little-endian int64.
+	want := []byte{
+		0xd3, 0x12, 0x9b, 0xe8, 0x67, 0x45, 0x3e, 0x12,
+		0x00, 0x40, 0x17, 0x14, 0x66, 0x42, 0x56, 0xa4,
+	}
+	require.True(t, bytes.HasSuffix(data, want), "data %x", data)
+}
+
+func TestRegisterRejectsOtherTypes(t *testing.T) {
+	f := fory.New()
+	for _, v := range []any{nil, [8]byte{}, []byte{}, [16]int8{}, "uuid"} {
+		require.Error(t, Register(f, v))
+	}
+}
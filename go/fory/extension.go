@@ -69,6 +69,10 @@ func (s *extensionSerializerAdapter) Write(ctx *WriteContext, refMode RefMode, w
 }
 
 func (s *extensionSerializerAdapter) ReadData(ctx *ReadContext, value reflect.Value) {
+	if ctx.bufferCopies != nil {
+		ctx.inlineCopies++
+		defer func() { ctx.inlineCopies-- }()
+	}
 	// Delegate to user's serializer
 	s.userSerial.ReadData(ctx, value)
 }
@@ -97,9 +101,77 @@ func (s *extensionSerializerAdapter) Read(ctx *ReadContext, refMode RefMode, rea
 			return
 		}
 	}
+	if readType {
+		typeInfo := ctx.TypeResolver().ReadTypeInfo(buf, ctxErr)
+		if ctx.HasError() {
+			return
+		}
+		if typeInfo == nil || typeInfo.Type != s.type_ {
+			ctx.SetError(DeserializationErrorf("expected extension type %v in type info", s.type_))
+			return
+		}
+	}
 	s.ReadData(ctx, value)
 }
 
 func (s *extensionSerializerAdapter) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
 	s.Read(ctx, refMode, false, false, value)
 }
+
+// CheckedExtension adapts s to ExtensionSerializer, for use with
+// RegisterExtension, RegisterExtensionByName and ReplaceSerializer. Each call
+// of s counts one level of nesting against the depth limit of the context.
+func CheckedExtension(s CheckedSerializer) ExtensionSerializer {
+	if u, ok := s.(uncheckedSerializer); ok {
+		return u.s
+	}
+	return checkedExtensionSerializer{s}
+}
+
+type checkedExtensionSerializer struct {
+	s CheckedSerializer
+}
+
+func (c checkedExtensionSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	ctx.incDepth()
+	defer ctx.decDepth()
+	if ctx.HasError() {
+		return
+	}
+	if err := c.s.Write(ctx, value); err != nil {
+		ctx.SetError(causeError(err, ErrKindSerializationFailed))
+	}
+}
+
+func (c checkedExtensionSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	ctx.incDepth()
+	defer ctx.decDepth()
+	if ctx.HasError() {
+		return
+	}
+	if err := c.s.Read(ctx, value); err != nil {
+		ctx.SetError(causeError(err, ErrKindDeserializationFailed))
+	}
+}
+
+// Checked adapts s, which records errors on the context like every Serializer
+// and ExtensionSerializer, to CheckedSerializer. The error s records is taken
+// off the context and returned, so a CheckedSerializer can write nested values
+// with built-in or registered serializers and handle their errors.
+func Checked(s ExtensionSerializer) CheckedSerializer {
+	return uncheckedSerializer{s}
+}
+
+type uncheckedSerializer struct {
+	s ExtensionSerializer
+}
+
+func (u uncheckedSerializer) Write(ctx *WriteContext, value reflect.Value) error {
+	u.s.WriteData(ctx, value)
+	return ctx.CheckError()
+}
+
+func (u uncheckedSerializer) Read(ctx *ReadContext, value reflect.Value) error {
+	u.s.ReadData(ctx, value)
+	return ctx.CheckError()
+}
@@ -0,0 +1,241 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type checkedVersion struct {
+	Major uint8
+	Minor uint8
+}
+
+var errUnsupportedVersion = errors.New("unsupported version")
+
+type checkedVersionSerializer struct{}
+
+func (checkedVersionSerializer) Write(ctx *WriteContext, value reflect.Value) error {
+	v := reflect.Indirect(value).Interface().(checkedVersion)
+	if v.Major == 0 {
+		return fmt.Errorf("version %d.%d: %w", v.Major, v.Minor, errUnsupportedVersion)
+	}
+	ctx.Buffer().WriteUint8(v.Major)
+	ctx.Buffer().WriteUint8(v.Minor)
+	return nil
+}
+
+func (checkedVersionSerializer) Read(ctx *ReadContext, value reflect.Value) error {
+	major := ctx.Buffer().ReadUint8(ctx.Err())
+	minor := ctx.Buffer().ReadUint8(ctx.Err())
+	if major > 9 {
+		return errUnsupportedVersion
+	}
+	reflect.Indirect(value).Set(reflect.ValueOf(checkedVersion{Major: major, Minor: minor}))
+	return nil
+}
+
+func TestCheckedExtension(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterExtension(checkedVersion{}, 1, CheckedExtension(checkedVersionSerializer{})))
+
+	data, err := f.Serialize(&checkedVersion{Major: 1, Minor: 2})
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+	var out checkedVersion
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, checkedVersion{Major: 1, Minor: 2}, out)
+
+	_, err = f.Serialize(&checkedVersion{Minor: 2})
+	require.ErrorIs(t, err, errUnsupportedVersion)
+	require.Equal(t, ErrKindSerializationFailed, err.(Error).Kind())
+	require.Contains(t, err.Error(), "version 0.2")
+
+	bad := append([]byte(nil), data...)
+	bad[len(bad)-2] = 10
+	err = f.Deserialize(bad, &out)
+	require.ErrorIs(t, err, errUnsupportedVersion)
+	require.Equal(t, ErrKindDeserializationFailed, err.(Error).Kind())
+
+	// Out-of-bound reads are reported even though Read returns nil.
+	err = f.Deserialize(data[:len(data)-1], &out)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+}
+
+type versioned interface {
+	Version() string
+}
+
+func (v *checkedVersion) Version() string {
+	return fmt.Sprintf("%d.%d", v.Major, v.Minor)
+}
+
+type versionHolder struct {
+	Current versioned
+}
+
+func TestExtensionIntoPointerReceiverInterface(t *testing.T) {
+	f := New(WithXlang(true), WithTrackRef(true))
+	require.NoError(t, f.RegisterExtension(checkedVersion{}, 1, CheckedExtension(checkedVersionSerializer{})))
+	require.NoError(t, f.RegisterStruct(versionHolder{}, 2))
+
+	data, err := f.Serialize(&checkedVersion{Major: 1, Minor: 2})
+	require.NoError(t, err)
+	var out versioned
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, "1.2", out.Version())
+
+	data, err = f.Serialize(&versionHolder{Current: &checkedVersion{Major: 3, Minor: 4}})
+	require.NoError(t, err)
+	var holder versionHolder
+	require.NoError(t, f.Deserialize(data, &holder))
+	require.Equal(t, "3.4", holder.Current.Version())
+}
+
+type checkedRelease struct {
+	Name    string
+	Version checkedVersion
+}
+
+// checkedReleaseSerializer writes its version field with the registered
+// serializer through Checked.
+type checkedReleaseSerializer struct{}
+
+func (checkedReleaseSerializer) Write(ctx *WriteContext, value reflect.Value) error {
+	r := reflect.Indirect(value).Interface().(checkedRelease)
+	ctx.WriteString(r.Name)
+	version := reflect.ValueOf(r.Version)
+	info, err := ctx.TypeResolver().GetTypeInfo(version, true)
+	if err != nil {
+		return err
+	}
+	if err := Checked(info.Serializer).Write(ctx, version); err != nil {
+		return fmt.Errorf("release %s: %w", r.Name, err)
+	}
+	return nil
+}
+
+func (checkedReleaseSerializer) Read(ctx *ReadContext, value reflect.Value) error {
+	r := checkedRelease{Name: ctx.ReadString()}
+	version := reflect.ValueOf(&r.Version).Elem()
+	info, err := ctx.TypeResolver().GetTypeInfo(version, true)
+	if err != nil {
+		return err
+	}
+	if err := Checked(info.Serializer).Read(ctx, version); err != nil {
+		return fmt.Errorf("release %s: %w", r.Name, err)
+	}
+	reflect.Indirect(value).Set(reflect.ValueOf(r))
+	return nil
+}
+
+func TestCheckedAdapter(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterExtension(checkedVersion{}, 1, CheckedExtension(checkedVersionSerializer{})))
+	require.NoError(t, f.RegisterExtension(checkedRelease{}, 2, CheckedExtension(checkedReleaseSerializer{})))
+
+	release := checkedRelease{Name: "stable", Version: checkedVersion{Major: 1, Minor: 2}}
+	data, err := f.Serialize(&release)
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+	var out checkedRelease
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, release, out)
+
+	// Errors of nested serializers are returned to the caller, which can wrap them.
+	_, err = f.Serialize(&checkedRelease{Name: "beta"})
+	require.ErrorIs(t, err, errUnsupportedVersion)
+	require.Contains(t, err.Error(), "release beta")
+	require.Equal(t, ErrKindSerializationFailed, err.(Error).Kind())
+
+	bad := append([]byte(nil), data...)
+	bad[len(bad)-2] = 10
+	err = f.Deserialize(bad, &out)
+	require.ErrorIs(t, err, errUnsupportedVersion)
+	require.Contains(t, err.Error(), "release stable")
+
+	err = f.Deserialize(data[:len(data)-1], &out)
+	require.ErrorIs(t, err, io.ErrUnexpectedEOF)
+	require.Contains(t, err.Error(), "release stable")
+
+	// Adapting back and forth returns the original serializer.
+	require.Equal(t, ExtensionSerializer(stringSerializer{}), CheckedExtension(Checked(stringSerializer{})))
+}
+
+type checkedChain struct {
+	Depth int
+	Next  *checkedChain
+}
+
+type checkedChainSerializer struct{}
+
+func (checkedChainSerializer) Write(ctx *WriteContext, value reflect.Value) error {
+	c := reflect.Indirect(value).Interface().(checkedChain)
+	ctx.Buffer().WriteBool(c.Next != nil)
+	if c.Next == nil {
+		return nil
+	}
+	next := reflect.ValueOf(*c.Next)
+	info, err := ctx.TypeResolver().GetTypeInfo(next, true)
+	if err != nil {
+		return err
+	}
+	return Checked(info.Serializer).Write(ctx, next)
+}
+
+func (checkedChainSerializer) Read(ctx *ReadContext, value reflect.Value) error {
+	c := checkedChain{Depth: ctx.Depth()}
+	if ctx.Buffer().ReadBool(ctx.Err()) {
+		next := reflect.New(value.Type())
+		info, err := ctx.TypeResolver().GetTypeInfo(next.Elem(), true)
+		if err != nil {
+			return err
+		}
+		if err := Checked(info.Serializer).Read(ctx, next.Elem()); err != nil {
+			return err
+		}
+		c.Next = next.Interface().(*checkedChain)
+	}
+	reflect.Indirect(value).Set(reflect.ValueOf(c))
+	return nil
+}
+
+func TestCheckedSerializerDepth(t *testing.T) {
+	chain := checkedChain{Next: &checkedChain{Next: &checkedChain{}}}
+	f := New(WithXlang(true), WithMaxDepth(3))
+	require.NoError(t, f.RegisterExtension(checkedChain{}, 1, CheckedExtension(checkedChainSerializer{})))
+	data, err := f.Serialize(&chain)
+	require.NoError(t, err)
+	var out checkedChain
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, checkedChain{Depth: 1, Next: &checkedChain{Depth: 2, Next: &checkedChain{Depth: 3}}}, out)
+
+	f = New(WithXlang(true), WithMaxDepth(2))
+	require.NoError(t, f.RegisterExtension(checkedChain{}, 1, CheckedExtension(checkedChainSerializer{})))
+	_, err = f.Serialize(&chain)
+	require.Error(t, err)
+	require.Equal(t, ErrKindMaxDepthExceeded, err.(Error).Kind())
+}
@@ -522,6 +522,9 @@ func isStructField(t reflect.Type) bool {
 	if info, ok := getOptionalInfo(t); ok {
 		t = info.valueType
 	}
+	if isContainerType(t) {
+		return false
+	}
 	if t.Kind() == reflect.Ptr {
 		t = t.Elem()
 	}
@@ -529,7 +532,7 @@ func isStructField(t reflect.Type) bool {
 		return false
 	}
 	// Date/Timestamp are built-in types with dedicated encodings, not user structs.
-	if t == dateType || t == timestampType || t == decimalType {
+	if t == dateType || t == timestampType || t == decimalType || isNetAddressType(t) {
 		return false
 	}
 	if t.Kind() == reflect.Struct {
@@ -488,6 +488,13 @@ func parseFieldTag(field reflect.StructField) (parsedFieldTag, error) {
 			value = strings.TrimSpace(part[idx+1:])
 			hasValue = true
 		}
+		switch key {
+		case "varint", "fixed", "tagged":
+			// Bare encodings are shorthand for encoding=<name>.
+			if !hasValue {
+				key, value, hasValue = "encoding", key, true
+			}
+		}
 		if _, ok := seen[key]; ok {
 			return parsedFieldTag{}, InvalidTagErrorf("duplicate fory tag key %q on field %s", key, field.Name)
 		}
@@ -714,6 +721,15 @@ func bindResolvedTypeSpec(resolver *TypeResolver, goType reflect.Type, spec *Typ
 	if baseType.Kind() == reflect.Ptr {
 		baseType = baseType.Elem()
 	}
+	if resolver.isExtensionType(baseType) {
+		// An extension serializer owns the encoding of registered slice, array
+		// and map types, so their inferred collection spec does not apply.
+		spec.Kind = TypeSpecScalar
+		spec.TypeID = TypeId(resolver.typesInfo[baseType].TypeID)
+		spec.Element, spec.Key, spec.Value = nil, nil, nil
+		spec.elementType, spec.keyType, spec.valueType = nil, nil, nil
+		return spec
+	}
 	spec.normalizeChildren()
 	switch baseType.Kind() {
 	case reflect.Slice, reflect.Array:
@@ -786,7 +802,9 @@ func inferFieldTypeSpec(goType reflect.Type, xlang bool, trackRef bool, isRoot b
 	}
 	if baseType.Kind() == reflect.Ptr {
 		nullable = true
-		baseType = baseType.Elem()
+		if !isContainerType(baseType) {
+			baseType = baseType.Elem()
+		}
 	}
 	spec, err := inferBaseTypeSpec(baseType, xlang, trackRef, true)
 	if err != nil {
@@ -812,6 +830,16 @@ func inferBaseTypeSpec(goType reflect.Type, xlang bool, trackRef bool, forceGene
 		spec.GoType = goType
 		return spec, nil
 	}
+	if isContainerType(goType) {
+		elemSpec := NewDynamicTypeSpec(UNKNOWN)
+		elemSpec.Nullable = true
+		elemSpec.TrackRef = trackRef
+		spec := NewCollectionTypeSpec(LIST, elemSpec)
+		spec.Nullable = true
+		spec.TrackRef = inferTrackRef(goType, LIST, trackRef)
+		spec.GoType = goType
+		return spec, nil
+	}
 	if goType.Kind() == reflect.Ptr {
 		spec, err := inferBaseTypeSpec(goType.Elem(), xlang, trackRef, forceGeneralList)
 		if err != nil {
@@ -827,6 +855,11 @@ func inferBaseTypeSpec(goType reflect.Type, xlang bool, trackRef bool, forceGene
 		spec.GoType = goType
 		return spec, nil
 	}
+	if isNetAddressType(goType) {
+		spec := NewSimpleTypeSpec(BINARY)
+		spec.GoType = goType
+		return spec, nil
+	}
 	switch goType.Kind() {
 	case reflect.Interface:
 		spec := NewDynamicTypeSpec(UNKNOWN)
@@ -1795,6 +1828,9 @@ func serializerForTypeSpec(resolver *TypeResolver, goType reflect.Type, spec *Ty
 			return nil, err
 		}
 	}
+	if isContainerType(goType) || isNetAddressType(goType) {
+		return resolver.getSerializerByType(goType, false)
+	}
 	if info, ok := getOptionalInfo(goType); ok {
 		inner, err := serializerForTypeSpec(resolver, info.valueType, spec.Clone())
 		if err != nil {
@@ -1847,6 +1883,11 @@ func serializerForTypeSpec(resolver *TypeResolver, goType reflect.Type, spec *Ty
 			hasGenerics:      true,
 		}, nil
 	case MAP:
+		if resolver.isXlang {
+			if err := checkXlangMapKey(goType.Key()); err != nil {
+				return nil, err
+			}
+		}
 		if spec.Key == nil || spec.Value == nil || spec.Key.TypeID == UNKNOWN || spec.Value.TypeID == UNKNOWN ||
 			goType.Key().Kind() == reflect.Interface || goType.Elem().Kind() == reflect.Interface {
 			return resolver.getSerializerByType(goType, true)
@@ -20,9 +20,12 @@ package fory
 import (
 	"errors"
 	"fmt"
+	"log/slog"
 	"reflect"
+	"slices"
 	"strconv"
 	"strings"
+	"time"
 	"unsafe"
 )
 
@@ -65,13 +68,35 @@ const (
 
 // Config holds configuration options for Fory instances
 type Config struct {
-	TrackRef          bool
-	MaxDepth          int
-	IsXlang           bool
-	Compatible        bool // Schema evolution compatibility mode
-	MaxCollectionSize int
-	MaxBinarySize     int
-	MaxTypeFields     int
+	TrackRef             bool
+	TrackMapKeyRef       bool // Track string and struct map keys as references
+	MaxDepth             int
+	IsXlang              bool
+	Compatible           bool // Schema evolution compatibility mode
+	MaxCollectionSize    int
+	MaxBinarySize        int
+	DecodeBudget         int // Bytes each Deserialize call may decode; 0 is unlimited
+	MaxTypeFields        int
+	BufferCapacity       int            // Preallocated write buffer capacity in bytes
+	BufferGrowth         BufferGrowth   // Write buffer growth policy
+	ReuseObjects         bool           // Decode into existing slices and maps of the target
+	OmitHeader           bool           // Write and expect payloads without the root header
+	Compression          Codec          // Compresses payload bodies when set
+	Checksum             bool           // Prefix payload bodies with a CRC-32C
+	StringInternSize     int            // Slots in the decoded string intern table; 0 disables it
+	CompactStrings       bool           // Write strings in the shortest of Latin-1, UTF-16 and UTF-8
+	ZeroCopyBinary       bool           // Decode []byte values as views of the input
+	Metrics              Metrics        // Receives call measurements when set
+	UnknownStructsAsMaps bool           // Decode unregistered structs as map[string]any
+	TraceLogger          *slog.Logger   // Logs each type ID and ref flag read or written when set
+	RecoverPanics        bool           // Return panics raised while encoding or decoding as errors
+	SkipMetaStringHash   bool           // Trust the hash of cached meta strings instead of checking bodies
+	MetaCompressor       MetaCompressor // Compresses shared type metadata when set
+	OutOfBandThreshold   int            // Smallest buffer offered to the out-of-band callback
+	OutOfBandWorkers     int            // Goroutines copying out-of-band buffers while decoding; 0 or 1 copies inline
+	StringDictionary     bool           // Write repeated strings once per payload
+	FieldNameTag         string         // Struct tag key field names are read from; empty uses Go names
+	OmitZeroFields       bool           // Skip struct fields holding zero values in compatible mode
 }
 
 // defaultConfig returns the default configuration
@@ -83,6 +108,7 @@ func defaultConfig() Config {
 		MaxCollectionSize: 1_000_000,
 		MaxBinarySize:     64 * 1024 * 1024,
 		MaxTypeFields:     10000,
+		RecoverPanics:     !panicOnError,
 	}
 }
 
@@ -101,6 +127,15 @@ func WithRefTracking(enabled bool) Option {
 	return WithTrackRef(enabled)
 }
 
+// WithMapKeyRefTracking writes string and struct map keys as references, so a
+// key equal to one written earlier in the payload costs only a reference id.
+// It requires WithTrackRef(true) and readers that track references.
+func WithMapKeyRefTracking(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.TrackMapKeyRef = enabled
+	}
+}
+
 // WithMaxDepth sets the maximum serialization depth
 func WithMaxDepth(depth int) Option {
 	return func(f *Fory) {
@@ -137,6 +172,19 @@ func WithMaxBinarySize(size int) Option {
 	}
 }
 
+// WithDecodeBudget limits the bytes each deserialize call may decode to
+// bytes, counting binary data, strings, the elements of slices, maps and
+// sets, and values allocated behind pointers. A call that exceeds it fails
+// with an error matching ErrBudgetExceeded. Unlike WithMaxCollectionSize,
+// it bounds the memory a payload can claim however it is shaped, such as
+// many moderate collections or a short list of large structs. Zero, the
+// default, leaves decoding unlimited.
+func WithDecodeBudget(bytes int) Option {
+	return func(f *Fory) {
+		f.config.DecodeBudget = bytes
+	}
+}
+
 // WithMaxTypeFields sets the maximum field count limit for schema definition deserialization
 func WithMaxTypeFields(size int) Option {
 	return func(f *Fory) {
@@ -144,6 +192,214 @@ func WithMaxTypeFields(size int) Option {
 	}
 }
 
+// WithBufferCapacity preallocates the write buffer with n bytes.
+// The capacity is kept between Marshal calls.
+func WithBufferCapacity(n int) Option {
+	return func(f *Fory) {
+		f.config.BufferCapacity = n
+	}
+}
+
+// WithBufferGrowth sets the write buffer growth policy
+func WithBufferGrowth(growth BufferGrowth) Option {
+	return func(f *Fory) {
+		f.config.BufferGrowth = growth
+	}
+}
+
+// WithObjectReuse makes Unmarshal decode into the slices, maps and nested
+// structs already held by the target instead of allocating fresh ones.
+// Slices are overwritten in place when their capacity suffices and maps are
+// cleared and refilled, so the result matches a fresh decode. Values previously
+// read from the target must not be retained across calls.
+func WithObjectReuse(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.ReuseObjects = enabled
+	}
+}
+
+// WithCompactStrings writes each string in whichever of Latin-1, UTF-16 and
+// UTF-8 is shortest, as Java does, instead of always using UTF-8. Text with
+// accented Latin characters or CJK characters shrinks; ASCII is unchanged.
+// All Fory implementations read the three encodings.
+func WithCompactStrings(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.CompactStrings = enabled
+	}
+}
+
+// WithStringDictionary writes each distinct string once per payload and later
+// occurrences as a short index into the strings written before, whether or not
+// reference tracking is enabled. It suits string-heavy collections with many
+// repeated values. Readers detect such payloads from the root header, so any
+// Go instance can read them; other Fory implementations cannot yet.
+func WithStringDictionary(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.StringDictionary = enabled
+	}
+}
+
+// WithFieldNameTag names struct fields from the struct tag key, such as
+// "json" or "msgpack", so types already annotated for another encoding keep
+// the same field names without fory tags. The name is the first
+// comma-separated element of the tag, in snake_case as Go field names are;
+// fields without the tag, or tagged with an empty name or "-", keep the
+// snake_case Go name. Fory options still come from the fory tag, so
+// `fory:"-"` is needed to skip a field. Generated serializers ignore it.
+func WithFieldNameTag(key string) Option {
+	return func(f *Fory) {
+		f.config.FieldNameTag = key
+	}
+}
+
+// WithOmitZeroFields skips struct fields holding their zero value in
+// compatible mode, so sparse structs encode only the fields that are set.
+// Each struct value is preceded by a bitmap with one bit per field recording
+// which fields follow, and the type metadata marks the struct as written this
+// way. Decoders leave absent fields at their zero value. Any Go instance can
+// read such payloads; other Fory implementations cannot yet. It has no effect
+// outside compatible mode, and generated serializers ignore it.
+func WithOmitZeroFields(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.OmitZeroFields = enabled
+	}
+}
+
+// WithStringInterning makes decoding reuse earlier allocations for repeated
+// short strings, such as map keys or enum-like values, through a table of
+// size slots kept across calls. Each slot remembers the last string hashed to
+// it, so memory stays bounded; size 0 disables interning.
+func WithStringInterning(size int) Option {
+	return func(f *Fory) {
+		f.config.StringInternSize = size
+	}
+}
+
+// WithZeroCopyBinary makes decoded []byte values sub-slices of the input
+// instead of copies, saving an allocation per value. The input must then stay
+// alive and unmodified while those values are in use. It takes precedence over
+// WithObjectReuse for []byte values. Primitive arrays read from out-of-band
+// buffers also become views of those buffers when suitably aligned.
+func WithZeroCopyBinary(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.ZeroCopyBinary = enabled
+	}
+}
+
+// WithUnknownStructsAsMaps decodes values of unregistered struct types held
+// in interface-typed targets, such as any fields and []any elements, into
+// map[string]any keyed by field name instead of skipping them. The structs
+// must be written in compatible mode, which carries their field metadata.
+// Use GenericRecord instead to write such values back unchanged.
+func WithUnknownStructsAsMaps(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.UnknownStructsAsMaps = enabled
+	}
+}
+
+// WithMetrics reports the size, duration and outcome of every serialization
+// call, and TypeDef cache lookups, to m. Without it nothing is measured.
+func WithMetrics(m Metrics) Option {
+	return func(f *Fory) {
+		f.config.Metrics = m
+	}
+}
+
+// WithTraceLogger logs each type ID and ref flag written or read, with its
+// buffer offset, to logger at debug level. It is meant for comparing payloads
+// with other implementations and slows serialization down considerably.
+func WithTraceLogger(logger *slog.Logger) Option {
+	return func(f *Fory) {
+		f.config.TraceLogger = logger
+	}
+}
+
+// WithPanicRecovery controls whether a panic raised while encoding or decoding,
+// for example by a malformed payload or a custom serializer, is returned as an
+// error instead of crashing the process. It is enabled by default, unless the
+// FORY_PANIC_ON_ERROR environment variable is set.
+func WithPanicRecovery(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.RecoverPanics = enabled
+	}
+}
+
+// WithMetaStringHashVerification controls whether the bodies of namespaces and
+// type names longer than 16 bytes are checked against the hash written before
+// them. It is enabled by default. When disabled, a body whose hash was already
+// read is skipped without being checked, which saves work on payloads that
+// repeat names but trusts their hashes.
+func WithMetaStringHashVerification(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.SkipMetaStringHash = !enabled
+	}
+}
+
+// WithHeader controls whether payloads start with the root header byte.
+// Disabling it saves that byte when payloads are embedded in another framed
+// protocol that already identifies them; both writer and reader must then be
+// configured with WithHeader(false) and the same xlang mode. Out-of-band
+// buffers are not signalled without a header, so readers must know to pass them.
+func WithHeader(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.OmitHeader = !enabled
+	}
+}
+
+// WithCompression compresses every serialized body with codec and records its
+// ID in the root header. Readers need the same codec configured to decode such
+// payloads; uncompressed payloads are still accepted. Compressed payloads can
+// only be read by implementations that support the codec bits.
+func WithCompression(codec Codec) Option {
+	return func(f *Fory) {
+		f.config.Compression = codec
+	}
+}
+
+// WithMetaCompressor compresses the type metadata that compatible mode shares
+// for each struct, enum and extension type, keeping the compressed form when it
+// is smaller. Readers need a compressor of the same format to decode it.
+// DeflateMetaCompressor matches the format of Java's default compressor.
+//
+// The xlang format does not allow compressed metadata, so this option requires
+// WithXlang(false); in xlang mode serializing a type that shares metadata
+// fails.
+func WithMetaCompressor(compressor MetaCompressor) Option {
+	return func(f *Fory) {
+		f.config.MetaCompressor = compressor
+	}
+}
+
+// WithOutOfBandThreshold writes buffers smaller than bytes in band without
+// offering them to the SerializeWithCallback callback, so the callback only sees
+// buffers worth shipping separately. The default of 0 offers every buffer.
+func WithOutOfBandThreshold(bytes int) Option {
+	return func(f *Fory) {
+		f.config.OutOfBandThreshold = bytes
+	}
+}
+
+// WithOutOfBandWorkers copies large out-of-band buffers into their decoded
+// slices on up to workers goroutines while the rest of the payload is read,
+// so payloads carrying many arrays decode faster. Every copy finishes before
+// DeserializeWithCallbackBuffers or DeserializeWithBufferProvider returns
+// and before AfterDeserialize hooks run. The default of 0 copies each buffer when the payload reaches it.
+func WithOutOfBandWorkers(workers int) Option {
+	return func(f *Fory) {
+		f.config.OutOfBandWorkers = workers
+	}
+}
+
+// WithChecksum prefixes every serialized body with its length and CRC-32C so
+// corrupted payloads fail with a checksum error before decoding starts. The
+// header records the checksum, so any reader verifies it; the option only
+// matters to readers of payloads written with WithHeader(false).
+func WithChecksum(enabled bool) Option {
+	return func(f *Fory) {
+		f.config.Checksum = enabled
+	}
+}
+
 // ============================================================================
 // Fory - Main serialization instance
 // ============================================================================
@@ -162,12 +418,31 @@ type Fory struct {
 	// Resolvers shared between contexts
 	typeResolver *TypeResolver
 	refResolver  *RefResolver
+
+	// appendBuffer wraps the caller's slice in MarshalAppend without allocating
+	appendBuffer ByteBuffer
+	// sizeBuffer is the scratch buffer SizeOf encodes into
+	sizeBuffer ByteBuffer
+	// frameBuffer holds the frame WriteFramed writes and ReadFramed reads
+	frameBuffer []byte
+
+	// options and registrations rebuild the instance in Clone and Unregister
+	options       []Option
+	registrations []registration
+}
+
+// registration is a successful Register call, replayed to rebuild an instance.
+// type_ is the type it registers, or nil for global hooks.
+type registration struct {
+	type_    reflect.Type
+	register func(*Fory) error
 }
 
 // New creates a new Fory instance with the given options
 func New(opts ...Option) *Fory {
 	f := &Fory{
-		config: defaultConfig(),
+		config:  defaultConfig(),
+		options: slices.Clone(opts),
 	}
 
 	// Apply options
@@ -188,17 +463,50 @@ func New(opts ...Option) *Fory {
 	// Initialize resolvers
 	f.typeResolver = newTypeResolver(f)
 	f.refResolver = newRefResolver(f.config.TrackRef)
+	f.typeResolver.trace = f.config.TraceLogger
+	f.refResolver.trace = f.config.TraceLogger
+	f.typeResolver.metaStringResolver.skipHashCheck = f.config.SkipMetaStringHash
 
 	// Initialize reusable contexts with resolvers
 	f.writeCtx = NewWriteContext(f.config.TrackRef, f.config.MaxDepth)
+	if f.config.BufferCapacity > 0 {
+		f.writeCtx.buffer = NewByteBufferWithCapacity(f.config.BufferCapacity)
+	}
+	f.writeCtx.buffer.SetGrowth(f.config.BufferGrowth)
 	f.writeCtx.typeResolver = f.typeResolver
 	f.writeCtx.refResolver = f.refResolver
 	f.writeCtx.compatible = f.config.Compatible
 	f.writeCtx.xlang = f.config.IsXlang
+	f.writeCtx.trackMapKeyRef = f.config.TrackRef && f.config.TrackMapKeyRef
+	f.writeCtx.compactStrings = f.config.CompactStrings
+	if f.config.StringDictionary {
+		f.writeCtx.enableStringDict()
+	}
+	f.writeCtx.minOutOfBand = f.config.OutOfBandThreshold
+	f.writeCtx.codec = f.config.Compression
+	f.writeCtx.checksum = f.config.Checksum
+	f.writeCtx.frameBody = f.config.Compression != nil || f.config.Checksum
 
 	f.readCtx = NewReadContext(f.config.TrackRef)
 	f.readCtx.maxCollectionSize = f.config.MaxCollectionSize
 	f.readCtx.maxBinarySize = f.config.MaxBinarySize
+	if f.config.DecodeBudget > 0 {
+		f.readCtx.decodeBudget = f.config.DecodeBudget
+		f.readCtx.budgetLeft = f.config.DecodeBudget
+	}
+	f.readCtx.reuseObjects = f.config.ReuseObjects
+	f.readCtx.zeroCopyBinary = f.config.ZeroCopyBinary
+	if f.config.OutOfBandWorkers > 1 {
+		f.readCtx.bufferCopies = newBufferCopies(f.config.OutOfBandWorkers)
+	}
+	f.readCtx.unknownStructsAsMaps = f.config.UnknownStructsAsMaps
+	if f.config.StringInternSize > 0 {
+		f.readCtx.strings = newStringTable(f.config.StringInternSize)
+	}
+	f.readCtx.omitHeader = f.config.OmitHeader
+	f.readCtx.expectStringDict = f.config.StringDictionary
+	f.readCtx.codec = f.config.Compression
+	f.readCtx.checksum = f.config.Checksum
 	f.readCtx.typeResolver = f.typeResolver
 	f.readCtx.refResolver = f.refResolver
 	f.readCtx.compatible = f.config.Compatible
@@ -212,7 +520,7 @@ func New(opts ...Option) *Fory {
 
 func (f *Fory) applyCompatibleDefault() {
 	if !f.compatibleSet {
-		f.config.Compatible = true
+		f.config.Compatible = !codegenOnly
 	}
 }
 
@@ -248,10 +556,12 @@ func validateUserTypeID(typeID uint32) error {
 // This is compatible with Java's fory.register(Class, int) method.
 // type_ can be either a reflect.Type or an instance of the type
 // typeID should be the user type ID in the range 0-0xfffffffe (0xffffffff is reserved for "unset").
+// opts may include WithNonReferencable.
 // Note: For enum types, use RegisterEnum instead.
 //
 //go:noinline
-func (f *Fory) RegisterStruct(type_ any, typeID uint32) error {
+func (f *Fory) RegisterStruct(type_ any, typeID uint32, opts ...RegisterOption) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterStruct(type_, typeID, opts...) })
 	if err := validateUserTypeID(typeID); err != nil {
 		return err
 	}
@@ -271,20 +581,37 @@ func (f *Fory) RegisterStruct(type_ any, typeID uint32) error {
 		return fmt.Errorf("RegisterStruct only supports struct types; for enum types use RegisterEnum. Got: %v", t.Kind())
 	}
 
+	o := newRegisterOptions(opts)
+	if o.nestedStructs {
+		return fmt.Errorf("WithNestedStructs requires RegisterStructByName, which provides the namespace for nested types")
+	}
+
 	// Determine the internal type ID based on config
 	var internalTypeID TypeId
 	internalTypeID = f.typeResolver.structTypeID(t, false)
 
-	return f.typeResolver.RegisterStruct(t, internalTypeID, typeID)
+	undo, err := f.setFieldTags(t, o.fieldTags)
+	if err != nil {
+		return err
+	}
+	if err := f.typeResolver.RegisterStruct(t, internalTypeID, typeID); err != nil {
+		undo()
+		return err
+	}
+	f.applyRegisterOptions(t, o)
+	return nil
 }
 
 // RegisterUnion registers a union type with a numeric ID for cross-language serialization.
-// type_ can be either a reflect.Type or an instance of the union type.
+// type_ can be either a reflect.Type or an instance of the union type. Interfaces
+// are passed as a nil pointer, such as (*Shape)(nil), and are written by the
+// case matching the dynamic type of their value.
 // typeID should be the user type ID in the range 0-0xfffffffe (0xffffffff is reserved for "unset").
 // serializer must implement union payload encoding/decoding.
 //
 //go:noinline
-func (f *Fory) RegisterUnion(type_ any, typeID uint32, serializer Serializer) error {
+func (f *Fory) RegisterUnion(type_ any, typeID uint32, serializer Serializer) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterUnion(type_, typeID, serializer) })
 	if serializer == nil {
 		return fmt.Errorf("RegisterUnion requires a non-nil serializer")
 	}
@@ -300,19 +627,21 @@ func (f *Fory) RegisterUnion(type_ any, typeID uint32, serializer Serializer) er
 			t = t.Elem()
 		}
 	}
-	if t.Kind() != reflect.Struct {
-		return fmt.Errorf("RegisterUnion only supports struct types; got: %v", t.Kind())
+	if t.Kind() != reflect.Struct && t.Kind() != reflect.Interface {
+		return fmt.Errorf("RegisterUnion only supports struct and interface types; got: %v", t.Kind())
 	}
 	return f.typeResolver.RegisterUnion(t, typeID, serializer)
 }
 
 // RegisterUnionByName registers a union type by name for cross-language serialization.
 // name can include a namespace prefix separated by "." (e.g., "example.Foo").
-// type_ can be either a reflect.Type or an instance of the union type.
+// type_ can be either a reflect.Type or an instance of the union type, or a nil
+// pointer to an interface as for RegisterUnion.
 // serializer must implement union payload encoding/decoding.
 //
 //go:noinline
-func (f *Fory) RegisterUnionByName(type_ any, name string, serializer Serializer) error {
+func (f *Fory) RegisterUnionByName(type_ any, name string, serializer Serializer) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterUnionByName(type_, name, serializer) })
 	if serializer == nil {
 		return fmt.Errorf("RegisterUnionByName requires a non-nil serializer")
 	}
@@ -325,8 +654,8 @@ func (f *Fory) RegisterUnionByName(type_ any, name string, serializer Serializer
 			t = t.Elem()
 		}
 	}
-	if t.Kind() != reflect.Struct {
-		return fmt.Errorf("RegisterUnionByName only supports struct types; got: %v", t.Kind())
+	if t.Kind() != reflect.Struct && t.Kind() != reflect.Interface {
+		return fmt.Errorf("RegisterUnionByName only supports struct and interface types; got: %v", t.Kind())
 	}
 	namespace, typeName, err := splitRegisteredName(name)
 	if err != nil {
@@ -338,10 +667,12 @@ func (f *Fory) RegisterUnionByName(type_ any, name string, serializer Serializer
 // RegisterStructByName registers a struct type by name for cross-language serialization.
 // type_ can be either a reflect.Type or an instance of the type.
 // name can include a namespace prefix separated by "." (e.g., "example.Foo").
+// opts may include WithNonReferencable.
 // Note: For enum types, use RegisterEnumByName instead.
 //
 //go:noinline
-func (f *Fory) RegisterStructByName(type_ any, name string) error {
+func (f *Fory) RegisterStructByName(type_ any, name string, opts ...RegisterOption) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterStructByName(type_, name, opts...) })
 	var t reflect.Type
 	if rt, ok := type_.(reflect.Type); ok {
 		t = rt
@@ -358,7 +689,233 @@ func (f *Fory) RegisterStructByName(type_ any, name string) error {
 	if err != nil {
 		return err
 	}
-	return f.typeResolver.registerStructByName(t, namespace, typeName)
+	o := newRegisterOptions(opts)
+	var nested []reflect.Type
+	if o.nestedStructs {
+		if nested, err = f.nestedStructs(t, namespace, typeName); err != nil {
+			return err
+		}
+	}
+	undo, err := f.setFieldTags(t, o.fieldTags)
+	if err != nil {
+		return err
+	}
+	if err := f.typeResolver.registerStructByName(t, namespace, typeName); err != nil {
+		undo()
+		return err
+	}
+	f.applyRegisterOptions(t, o)
+	for _, n := range nested {
+		if err := f.typeResolver.registerStructByName(n, namespace, n.Name()); err != nil {
+			// nestedStructs checked every type, but a failure here must not
+			// leave t and the nested types before n registered.
+			if rerr := f.rebuild(f.registrations); rerr != nil {
+				return fmt.Errorf("%w; rolling back failed: %v", err, rerr)
+			}
+			return err
+		}
+		f.applyRegisterOptions(n, o)
+	}
+	return nil
+}
+
+// RegisterStructsByName registers each struct type in types by name, as
+// namespace.TypeName, so a package's DTOs can be registered in one call.
+// Entries can be reflect.Types or instances. All types are checked before any
+// is registered, so a conflict leaves the instance unchanged.
+func (f *Fory) RegisterStructsByName(namespace string, types ...any) error {
+	r := f.typeResolver
+	names := make(map[string]reflect.Type, len(types))
+	for _, type_ := range types {
+		t := registeredType(type_)
+		if t == nil || t.Kind() != reflect.Struct {
+			return fmt.Errorf("RegisterStructsByName only supports struct types, got %v", t)
+		}
+		if t.Name() == "" || strings.ContainsAny(t.Name(), "[]") {
+			return fmt.Errorf("cannot derive a name for type %v; register it explicitly", t)
+		}
+		if _, ok := r.typeToSerializers[t]; ok {
+			return r.duplicateRegistration(t)
+		}
+		tag := joinRegisteredName(namespace, t.Name())
+		if prev, ok := names[tag]; ok {
+			return fmt.Errorf("types %v and %v would both be registered as %s", prev, t, tag)
+		}
+		if prev, ok := r.typeInfoToType["@"+tag]; ok {
+			return fmt.Errorf("type %v would be registered as %s, which is taken by %v", t, tag, prev)
+		}
+		if err := r.validateStructFields(t); err != nil {
+			return err
+		}
+		names[tag] = t
+	}
+	for _, type_ := range types {
+		t := registeredType(type_)
+		if err := f.RegisterStructByName(t, joinRegisteredName(namespace, t.Name())); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// RegisterOption adjusts how a registered struct type is serialized.
+type RegisterOption func(*registerOptions)
+
+type registerOptions struct {
+	nonReferencable bool
+	nestedStructs   bool
+	fieldTags       map[string]string
+}
+
+// WithNonReferencable marks a struct type whose pointers never need reference
+// tracking, such as a small immutable value. Pointers to it are written in
+// full at every occurrence, skipping the reference map even when the instance
+// tracks references. Values of the type must not contain cycles through it.
+// Readers need no matching option.
+func WithNonReferencable() RegisterOption {
+	return func(o *registerOptions) {
+		o.nonReferencable = true
+	}
+}
+
+// WithNestedStructs also registers the unregistered struct types reachable
+// from the fields of the registered type, including through pointers, slices,
+// arrays, maps and optionals. Each is registered by name in the namespace of
+// the registered type, with its Go type name, so example.Order registers its
+// Item field type as example.Item. Other options apply to the nested types
+// too. Only RegisterStructByName accepts it, and enums are not detected. If
+// any of the types cannot be registered, none is.
+func WithNestedStructs() RegisterOption {
+	return func(o *registerOptions) {
+		o.nestedStructs = true
+	}
+}
+
+// WithFieldTag replaces the fory tag of the named field, as if the struct
+// declared `fory:"<tag>"` on it. It maps types that cannot be edited, such as
+// those from other packages, onto an existing schema: for example
+// WithFieldTag("Count", "type=int32") writes an int field as INT32, and
+// WithFieldTag("Done", "-") skips a field. It applies only to the registered
+// type, not to nested types, and generated serializers ignore it.
+func WithFieldTag(field, tag string) RegisterOption {
+	return func(o *registerOptions) {
+		if o.fieldTags == nil {
+			o.fieldTags = make(map[string]string)
+		}
+		o.fieldTags[field] = tag
+	}
+}
+
+func newRegisterOptions(opts []RegisterOption) registerOptions {
+	var o registerOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	return o
+}
+
+func (f *Fory) applyRegisterOptions(t reflect.Type, o registerOptions) {
+	if o.nonReferencable {
+		f.refResolver.skipPointerType(reflect.PointerTo(t))
+	}
+}
+
+// setFieldTags installs the WithFieldTag overrides for t before it is
+// registered. The returned function removes them again if registration fails.
+func (f *Fory) setFieldTags(t reflect.Type, tags map[string]string) (undo func(), err error) {
+	r := f.typeResolver
+	if len(tags) == 0 || r.typeToSerializers[t] != nil {
+		// Registering an already registered type fails without using them.
+		return func() {}, nil
+	}
+	for name := range tags {
+		field, ok := t.FieldByName(name)
+		if !ok || len(field.Index) != 1 || field.PkgPath != "" {
+			return nil, fmt.Errorf("WithFieldTag: struct %v has no exported field %s", t, name)
+		}
+	}
+	if r.fieldTags == nil {
+		r.fieldTags = make(map[reflect.Type]map[string]string)
+	}
+	r.fieldTags[t] = tags
+	return func() { delete(r.fieldTags, t) }, nil
+}
+
+// nestedStructs returns the unregistered named struct types reachable from
+// the fields of t, in the order they are found, after checking that each can
+// be registered in namespace under its Go type name. t itself is being
+// registered as typeName.
+func (f *Fory) nestedStructs(t reflect.Type, namespace, typeName string) ([]reflect.Type, error) {
+	r := f.typeResolver
+	seen := map[reflect.Type]bool{t: true}
+	names := map[string]reflect.Type{joinRegisteredName(namespace, typeName): t}
+	var found []reflect.Type
+	var visit func(reflect.Type) error
+	visitFields := func(t reflect.Type) error {
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if parsed, err := parseFieldTag(field); err != nil || parsed.ignore {
+				continue
+			}
+			if err := visit(field.Type); err != nil {
+				return fmt.Errorf("field %s.%s: %w", t.Name(), field.Name, err)
+			}
+		}
+		return nil
+	}
+	visit = func(t reflect.Type) error {
+		if info, ok := getOptionalInfo(t); ok {
+			return visit(info.valueType)
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			return visit(t.Elem())
+		case reflect.Map:
+			if err := visit(t.Key()); err != nil {
+				return err
+			}
+			return visit(t.Elem())
+		case reflect.Struct:
+		default:
+			return nil
+		}
+		if seen[t] || t.Name() == "" || r.typeToSerializers[t] != nil {
+			return nil
+		}
+		seen[t] = true
+		if strings.ContainsAny(t.Name(), "[]") {
+			return fmt.Errorf("cannot derive a name for generic type %v; register it explicitly", t)
+		}
+		tag := joinRegisteredName(namespace, t.Name())
+		if prev, ok := names[tag]; ok {
+			return fmt.Errorf("nested types %v and %v would both be registered as %s", prev, t, tag)
+		}
+		if prev, ok := r.typeInfoToType["@"+tag]; ok {
+			return fmt.Errorf("nested type %v would be registered as %s, which is taken by %v", t, tag, prev)
+		}
+		// Aliases and other named registrations hold names outside typeInfoToType.
+		if !r.derivedTypeNames[[2]string{namespace, t.Name()}] {
+			if err := r.checkTypeName(t, namespace, t.Name()); err != nil {
+				return fmt.Errorf("nested type %v: %w", t, err)
+			}
+		}
+		if err := r.validateStructFields(t); err != nil {
+			return err
+		}
+		if err := checkReflectStructSerializer(t); err != nil {
+			return err
+		}
+		names[tag] = t
+		found = append(found, t)
+		return visitFields(t)
+	}
+	if err := visitFields(t); err != nil {
+		return nil, err
+	}
+	return found, nil
 }
 
 // RegisterEnum registers an enum type with a numeric ID for cross-language serialization.
@@ -368,7 +925,8 @@ func (f *Fory) RegisterStructByName(type_ any, name string) error {
 // typeID should be the user type ID in the range 0-0xfffffffe (0xffffffff is reserved for "unset").
 //
 //go:noinline
-func (f *Fory) RegisterEnum(type_ any, typeID uint32) error {
+func (f *Fory) RegisterEnum(type_ any, typeID uint32) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterEnum(type_, typeID) })
 	if err := validateUserTypeID(typeID); err != nil {
 		return err
 	}
@@ -394,13 +952,58 @@ func (f *Fory) RegisterEnum(type_ any, typeID uint32) error {
 	return f.typeResolver.RegisterEnum(t, typeID)
 }
 
+// RegisterEnumByOrdinal registers an enum type with a numeric ID and writes each
+// value as its position in values, the way Java writes enum ordinals. Use it when
+// the Go constants are not numbered 0, 1, 2, ... in the order of the peer's enum.
+// values must be distinct values of the enum type; writing any other value fails.
+//
+//go:noinline
+func (f *Fory) RegisterEnumByOrdinal(type_ any, typeID uint32, values ...any) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterEnumByOrdinal(type_, typeID, values...) })
+	t := registeredType(type_)
+	if len(values) == 0 {
+		return fmt.Errorf("RegisterEnumByOrdinal requires at least one value of %v", t)
+	}
+	bits := make([]uint64, len(values))
+	ordinals := make(map[uint64]uint32, len(values))
+	for i, v := range values {
+		rv := reflect.ValueOf(v)
+		if !rv.IsValid() || rv.Type() != t {
+			return fmt.Errorf("enum value %d of %v has type %T", i, t, v)
+		}
+		switch rv.Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			bits[i] = uint64(rv.Int())
+		case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+			bits[i] = rv.Uint()
+		default:
+			return fmt.Errorf("RegisterEnumByOrdinal only supports numeric types (Go enums); got: %v", t.Kind())
+		}
+		if prev, ok := ordinals[bits[i]]; ok {
+			return fmt.Errorf("enum value %v of %v is listed at both %d and %d", v, t, prev, i)
+		}
+		ordinals[bits[i]] = uint32(i)
+	}
+	if err := validateUserTypeID(typeID); err != nil {
+		return err
+	}
+	if err := f.typeResolver.RegisterEnum(t, typeID); err != nil {
+		return err
+	}
+	serializer := f.typeResolver.typesInfo[t].Serializer.(*enumSerializer)
+	serializer.values = bits
+	serializer.ordinals = ordinals
+	return nil
+}
+
 // RegisterEnumByName registers an enum type by name for cross-language serialization.
 // In Go, enums are typically defined as int-based types (e.g., type Color int32).
 // type_ can be either a reflect.Type or an instance of the enum type.
 // name can include a namespace prefix separated by "." (e.g., "example.Color").
 //
 //go:noinline
-func (f *Fory) RegisterEnumByName(type_ any, name string) error {
+func (f *Fory) RegisterEnumByName(type_ any, name string) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterEnumByName(type_, name) })
 	var t reflect.Type
 	if rt, ok := type_.(reflect.Type); ok {
 		t = rt
@@ -432,7 +1035,8 @@ func (f *Fory) RegisterEnumByName(type_ any, name string) error {
 // typeID should be the user type ID in the range 0-0xfffffffe (0xffffffff is reserved for "unset").
 //
 //go:noinline
-func (f *Fory) RegisterExtension(type_ any, typeID uint32, serializer ExtensionSerializer) error {
+func (f *Fory) RegisterExtension(type_ any, typeID uint32, serializer ExtensionSerializer) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterExtension(type_, typeID, serializer) })
 	if err := validateUserTypeID(typeID); err != nil {
 		return err
 	}
@@ -471,7 +1075,8 @@ func (f *Fory) RegisterExtension(type_ any, typeID uint32, serializer ExtensionS
 //	f.RegisterExtensionByName(MyExt{}, "my_ext", &MyExtSerializer{})
 //
 //go:noinline
-func (f *Fory) RegisterExtensionByName(type_ any, name string, serializer ExtensionSerializer) error {
+func (f *Fory) RegisterExtensionByName(type_ any, name string, serializer ExtensionSerializer) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterExtensionByName(type_, name, serializer) })
 	var t reflect.Type
 	if rt, ok := type_.(reflect.Type); ok {
 		t = rt
@@ -488,6 +1093,156 @@ func (f *Fory) RegisterExtensionByName(type_ any, name string, serializer Extens
 	return f.typeResolver.registerExtensionByName(t, namespace, typeName, serializer)
 }
 
+// RegisterDecimal registers the decimal type of another package, such as
+// github.com/shopspring/decimal.Decimal, as the DECIMAL type. Values are
+// converted to and from Decimal by converter, so they share the wire format of
+// Decimal and of the decimal types of other languages. Payloads decoded into
+// an interface still yield Decimal.
+//
+//go:noinline
+func (f *Fory) RegisterDecimal(type_ any, converter DecimalConverter) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterDecimal(type_, converter) })
+	return f.typeResolver.registerDecimal(registeredType(type_), converter)
+}
+
+// RegisterTypeAlias maps an old registered name (e.g. "example.Foo") to a type
+// already registered by name, so payloads written under the old name still
+// decode after the type was renamed or moved. Serialization keeps using the
+// type's current name.
+func (f *Fory) RegisterTypeAlias(oldName string, newType any) (err error) {
+	defer f.recordRegistration(&err, newType, func(c *Fory) error { return c.RegisterTypeAlias(oldName, newType) })
+	var t reflect.Type
+	if rt, ok := newType.(reflect.Type); ok {
+		t = rt
+	} else {
+		t = reflect.TypeOf(newType)
+		if t.Kind() == reflect.Ptr {
+			t = t.Elem()
+		}
+	}
+	namespace, typeName, err := splitRegisteredName(oldName)
+	if err != nil {
+		return err
+	}
+	return f.typeResolver.registerTypeAlias(namespace, typeName, t)
+}
+
+// ReplaceSerializer swaps the serializer of an extension type registered with
+// RegisterExtension or RegisterExtensionByName. The type keeps its ID or name,
+// so payloads stay readable across the swap as long as both serializers use
+// the same encoding. Structs and other values holding the type use the new
+// serializer too.
+//
+// Like registration, it must not be called while f is serializing, for
+// example from inside a serializer. threadsafe.Fory applies it to every
+// operation that starts after it returns.
+//
+//go:noinline
+func (f *Fory) ReplaceSerializer(type_ any, serializer ExtensionSerializer) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.ReplaceSerializer(type_, serializer) })
+	t := registeredType(type_)
+	if serializer == nil {
+		return fmt.Errorf("serializer cannot be nil for extension type %s", t)
+	}
+	adapter, ok := f.typeResolver.typeToSerializers[t].(*extensionSerializerAdapter)
+	if !ok {
+		return fmt.Errorf("type %s is not registered as an extension type; only extension serializers can be replaced", t)
+	}
+	adapter.userSerial = serializer
+	return nil
+}
+
+// Unregister removes the registration of type_ together with the aliases,
+// hooks and serializer replacements registered for it, so the type can be
+// registered again, for example under another ID or with another serializer.
+// Until then, the type is treated like any other unregistered type.
+//
+// f is rebuilt from its remaining registrations, dropping the metadata cached
+// for every type, so avoid unregistering on hot paths. Like registration, it
+// must not be called while f is serializing. threadsafe.Fory applies it to
+// every operation that starts after it returns.
+func (f *Fory) Unregister(type_ any) error {
+	t := registeredType(type_)
+	kept := make([]registration, 0, len(f.registrations))
+	for _, r := range f.registrations {
+		if r.type_ != t {
+			kept = append(kept, r)
+		}
+	}
+	if len(kept) == len(f.registrations) {
+		return fmt.Errorf("type %s is not registered", t)
+	}
+	if err := f.rebuild(kept); err != nil {
+		return fmt.Errorf("cannot unregister type %s: %w", t, err)
+	}
+	return nil
+}
+
+// rebuild replaces the registration state of f with that of a new instance
+// with regs registered. f is unchanged if a registration fails.
+func (f *Fory) rebuild(regs []registration) error {
+	c := New(f.options...)
+	for _, r := range regs {
+		if err := r.register(c); err != nil {
+			return err
+		}
+	}
+	f.typeResolver = c.typeResolver
+	f.typeResolver.fory = f
+	f.writeCtx.typeResolver = f.typeResolver
+	f.readCtx.typeResolver = f.typeResolver
+	f.readCtx.lastTypePtr, f.readCtx.lastTypeInfo = 0, nil
+	// Register options also keep per-type state in the ref resolver.
+	f.refResolver.untrackedTypes = c.refResolver.untrackedTypes
+	f.registrations = c.registrations
+	return nil
+}
+
+// registeredType returns the type a Register call with type_ applies to:
+// type_ itself if it is a reflect.Type, otherwise its dynamic type with one
+// level of pointer removed.
+func registeredType(type_ any) reflect.Type {
+	if t, ok := type_.(reflect.Type); ok {
+		return t
+	}
+	t := reflect.TypeOf(type_)
+	if t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	return t
+}
+
+// recordRegistration keeps register for Clone and Unregister if the
+// registration succeeded.
+func (f *Fory) recordRegistration(err *error, type_ any, register func(*Fory) error) {
+	if *err == nil {
+		f.registrations = append(f.registrations, registration{type_: registeredType(type_), register: register})
+	}
+}
+
+// Clone returns a new instance created with the options of f, with every type,
+// serializer, alias and hook registered on f registered again. The clone has
+// its own buffers, reference state and caches, so a configured template can be
+// replicated per goroutine or per tenant. Serializers passed to registrations
+// are shared and must be safe for concurrent use when clones are. Later
+// registrations on either instance do not affect the other.
+//
+// A registration that depends on global state, such as global hooks or
+// generated serializers, can fail on the clone when that state has changed
+// since it was made on f. Clone then returns the error and no instance.
+func (f *Fory) Clone() (*Fory, error) {
+	c := New(f.options...)
+	for _, r := range f.registrations {
+		if err := r.register(c); err != nil {
+			if r.type_ != nil {
+				return nil, fmt.Errorf("cannot clone registration of type %s: %w", r.type_, err)
+			}
+			return nil, fmt.Errorf("cannot clone registration: %w", err)
+		}
+	}
+	return c, nil
+}
+
 // Reset clears internal state for reuse
 func (f *Fory) Reset() {
 	f.writeCtx.Reset()
@@ -510,31 +1265,70 @@ func (f *Fory) Reset() {
 //
 // For thread-safe usage, use threadsafe.Fory which copies the data internally.
 func (f *Fory) Serialize(value any) ([]byte, error) {
+	if m := f.config.Metrics; m != nil {
+		start := time.Now()
+		data, err := f.serialize(value)
+		m.Serialized(reflect.TypeOf(value), len(data), time.Since(start), err)
+		return data, err
+	}
+	return f.serialize(value)
+}
+
+func (f *Fory) serialize(value any) (_ []byte, err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverWrite(&err, f.writeCtx.buffer)
+	}
 	defer f.resetWriteState()
 	// WriteData protocol header
 	writeHeader(f.writeCtx, f.config)
 
 	// Serialize the value - TypeMeta is written inline using streaming protocol
 	reflValue := reflect.ValueOf(value)
-	// if it's struct kind, must be pointer to struct, otherwise error
-	if reflValue.Kind() == reflect.Struct {
-		reflType := reflValue.Type()
-		if reflType != dateReflectType && reflType != timeReflectType {
-			return nil, fmt.Errorf("Serialize struct %s directly is disallowed, use pointer to struct (*%s) instead",
-				reflValue.Type(), reflValue.Type())
-		}
+	if err := checkRootValue(reflValue); err != nil {
+		return nil, err
 	}
 	f.writeCtx.WriteValue(reflValue, RefModeTracking, true)
 	if f.writeCtx.HasError() {
 		return nil, f.writeCtx.TakeError()
 	}
+	if f.writeCtx.frameBody {
+		if err := finishBody(f.writeCtx); err != nil {
+			return nil, err
+		}
+	}
 
 	return f.writeCtx.buffer.GetByteSlice(0, f.writeCtx.buffer.writerIndex), nil
 }
 
+// checkRootValue rejects struct values written at the root, which must be
+// passed by pointer.
+func checkRootValue(value reflect.Value) error {
+	if value.Kind() == reflect.Struct {
+		reflType := value.Type()
+		if reflType != dateReflectType && reflType != timeReflectType && !isNetAddressType(reflType) {
+			return fmt.Errorf("Serialize struct %s directly is disallowed, use pointer to struct (*%s) instead",
+				reflType, reflType)
+		}
+	}
+	return nil
+}
+
 // Deserialize deserializes data directly into the provided target value.
 // The target must be a pointer to the value to deserialize into.
 func (f *Fory) Deserialize(data []byte, v any) error {
+	if m := f.config.Metrics; m != nil {
+		start := time.Now()
+		err := f.deserialize(data, v)
+		m.Deserialized(targetType(v), len(data), time.Since(start), err)
+		return err
+	}
+	return f.deserialize(data, v)
+}
+
+func (f *Fory) deserialize(data []byte, v any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
 	defer f.resetReadState()
 	f.readCtx.SetData(data)
 
@@ -544,13 +1338,68 @@ func (f *Fory) Deserialize(data []byte, v any) error {
 	}
 
 	// Deserialize the value - TypeMeta is read inline using streaming protocol
-	target := reflect.ValueOf(v).Elem()
-	f.readCtx.ReadValue(target, RefModeTracking, true)
-	if f.readCtx.HasError() {
-		return f.readCtx.TakeError()
+	return f.readRoot(v)
+}
+
+// readRoot reads the root value into v, which must be a non-nil pointer. A
+// null root sets the target to its zero value, which is nil for pointers,
+// interfaces, slices and maps.
+func (f *Fory) readRoot(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Ptr || rv.IsNil() {
+		return DeserializationErrorf("target must be a non-nil pointer, got %T", v)
 	}
+	if f.readCtx.readNullRoot() {
+		rv.Elem().SetZero()
+		return nil
+	}
+	f.readCtx.ReadValue(rv.Elem(), RefModeTracking, true)
+	return f.readCtx.CheckError()
+}
 
-	return nil
+// UnmarshalProjected deserializes data into v, which must point to a struct,
+// decoding only the top-level fields selected by projection. The other fields
+// of v keep their current values. Reference tracking must be disabled.
+func (f *Fory) UnmarshalProjected(data []byte, v any, projection *Projection) error {
+	if f.config.TrackRef {
+		// Skipped fields may define references that later fields point back to.
+		return fmt.Errorf("field projection requires reference tracking to be disabled")
+	}
+	f.readCtx.projection = projection
+	return f.Deserialize(data, v)
+}
+
+// UnmarshalPartial deserializes data into v like Unmarshal, but skips values
+// of types that are not registered with f instead of failing, provided the
+// payload describes them: structs written with compatible metadata and enums.
+// Skipped values are left as nil or zero, dropped from sets and maps, and
+// reported in the returned warnings.
+func (f *Fory) UnmarshalPartial(data []byte, v any) ([]UnknownTypeWarning, error) {
+	f.readCtx.skipUnknownTypes = true
+	err := f.Deserialize(data, v)
+	warnings := f.readCtx.unknownTypes
+	f.readCtx.skipUnknownTypes = false
+	f.readCtx.unknownTypes = nil
+	return warnings, err
+}
+
+// recoverWrite turns a panic raised while writing into *err and restores the
+// write state, including the buffer SerializeTo swaps in.
+func (f *Fory) recoverWrite(err *error, buffer *ByteBuffer) {
+	if r := recover(); r != nil {
+		f.writeCtx.buffer = buffer
+		f.resetWriteState()
+		*err = SerializationErrorf("recovered from panic: %v", r)
+	}
+}
+
+// recoverRead is the read-side counterpart of recoverWrite.
+func (f *Fory) recoverRead(err *error, buffer *ByteBuffer) {
+	if r := recover(); r != nil {
+		f.readCtx.buffer = buffer
+		f.resetReadState()
+		*err = DeserializationErrorf("recovered from panic: %v", r)
+	}
 }
 
 // resetReadState resets read context state without allocation
@@ -573,6 +1422,19 @@ func (f *Fory) resetWriteState() {
 // This is useful when you need to write multiple serialized values to the same buffer.
 // Returns error if serialization fails.
 func (f *Fory) SerializeTo(buf *ByteBuffer, value any) error {
+	if m := f.config.Metrics; m != nil {
+		start, from := time.Now(), buf.writerIndex
+		err := f.serializeTo(buf, value)
+		m.Serialized(reflect.TypeOf(value), buf.writerIndex-from, time.Since(start), err)
+		return err
+	}
+	return f.serializeTo(buf, value)
+}
+
+func (f *Fory) serializeTo(buf *ByteBuffer, value any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverWrite(&err, f.writeCtx.buffer)
+	}
 	defer f.resetWriteState()
 
 	// Temporarily swap buffer
@@ -602,8 +1464,7 @@ func (f *Fory) SerializeTo(buf *ByteBuffer, value any) error {
 				f.writeCtx.buffer = origBuffer
 				return f.writeCtx.TakeError()
 			}
-			f.writeCtx.buffer = origBuffer
-			return nil
+			return f.finishSerializeTo(origBuffer)
 		}
 	}
 
@@ -613,16 +1474,37 @@ func (f *Fory) SerializeTo(buf *ByteBuffer, value any) error {
 		f.writeCtx.buffer = origBuffer
 		return f.writeCtx.TakeError()
 	}
+	return f.finishSerializeTo(origBuffer)
+}
 
-	// Restore original buffer
+// finishSerializeTo frames the body if configured and restores the
+// internal buffer swapped out by SerializeTo.
+func (f *Fory) finishSerializeTo(origBuffer *ByteBuffer) error {
+	var err error
+	if f.writeCtx.frameBody {
+		err = finishBody(f.writeCtx)
+	}
 	f.writeCtx.buffer = origBuffer
-	return nil
+	return err
 }
 
 // DeserializeFrom deserializes data from an existing buffer directly into the provided target value.
 // The buffer's reader index is advanced as data is read.
 // This is useful when reading multiple serialized values from the same buffer.
 func (f *Fory) DeserializeFrom(buf *ByteBuffer, v any) error {
+	if m := f.config.Metrics; m != nil {
+		start, from := time.Now(), buf.readerIndex
+		err := f.deserializeFrom(buf, v)
+		m.Deserialized(targetType(v), buf.readerIndex-from, time.Since(start), err)
+		return err
+	}
+	return f.deserializeFrom(buf, v)
+}
+
+func (f *Fory) deserializeFrom(buf *ByteBuffer, v any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
 	// Reset contexts for each independent serialized object
 	defer f.resetReadState()
 
@@ -637,17 +1519,60 @@ func (f *Fory) DeserializeFrom(buf *ByteBuffer, v any) error {
 	}
 
 	// Deserialize the value - TypeMeta is read inline using streaming protocol
-	target := reflect.ValueOf(v).Elem()
-	f.readCtx.ReadValue(target, RefModeTracking, true)
-	if f.readCtx.HasError() {
-		f.readCtx.buffer = origBuffer
-		return f.readCtx.TakeError()
-	}
+	err = f.readRoot(v)
 
 	// Restore original buffer
 	f.readCtx.buffer = origBuffer
+	return err
+}
 
-	return nil
+// MarshalOption adjusts a single Marshal call without changing the
+// configuration shared by other calls on the same Fory instance.
+type MarshalOption func(*marshalOptions)
+
+type marshalOptions struct {
+	disableRefTracking bool
+	sortMapKeys        bool
+	stringDict         bool
+	appendTo           bool
+	dst                []byte
+}
+
+// WithRefTrackingDisabled writes the value without reference tracking, as if
+// the instance were created with WithTrackRef(false). Shared values are
+// written once per occurrence, so the value must not contain cycles.
+// The output can be read by instances with or without reference tracking.
+func WithRefTrackingDisabled() MarshalOption {
+	return func(o *marshalOptions) {
+		o.disableRefTracking = true
+	}
+}
+
+// WithSortedMaps writes map entries and set elements in ascending key order,
+// so equal values always encode to identical bytes. Keys are compared by
+// value; pointer keys are compared by address. Maps inside structs encoded
+// by generated serializers keep Go's iteration order.
+func WithSortedMaps() MarshalOption {
+	return func(o *marshalOptions) {
+		o.sortMapKeys = true
+	}
+}
+
+// WithDictionaryStrings writes repeated strings once, as if the instance were
+// created with WithStringDictionary(true).
+func WithDictionaryStrings() MarshalOption {
+	return func(o *marshalOptions) {
+		o.stringDict = true
+	}
+}
+
+// WithBuffer appends the encoding to dst, as MarshalAppend does. The result
+// is owned by the caller instead of aliasing the instance's internal buffer.
+func WithBuffer(dst []byte) MarshalOption {
+	return func(o *marshalOptions) {
+		o.appendTo = true
+		o.dst = dst
+	}
 }
 
 // Marshal serializes a value to bytes.
@@ -659,11 +1584,79 @@ func (f *Fory) DeserializeFrom(buf *ByteBuffer, v any) error {
 //	data, _ := f.Marshal(value)
 //	safeCopy := bytes.Clone(data)
 //
+// Options apply to this call only:
+//
+//	data, _ := f.Marshal(value, fory.WithRefTrackingDisabled(), fory.WithSortedMaps())
+//
 // For thread-safe usage, use threadsafe.Fory which copies the data internally.
-func (f *Fory) Marshal(v any) ([]byte, error) {
+func (f *Fory) Marshal(v any, opts ...MarshalOption) ([]byte, error) {
+	if len(opts) == 0 {
+		return f.Serialize(v)
+	}
+	var o marshalOptions
+	for _, opt := range opts {
+		opt(&o)
+	}
+	ctx := f.writeCtx
+	if o.disableRefTracking {
+		trackRef, refWriterEnabled, refTracking := ctx.trackRef, ctx.refWriter.enabled, f.refResolver.refTracking
+		trackMapKeyRef := ctx.trackMapKeyRef
+		ctx.trackRef, ctx.refWriter.enabled, f.refResolver.refTracking = false, false, false
+		ctx.trackMapKeyRef = false
+		defer func() {
+			ctx.trackRef, ctx.refWriter.enabled, f.refResolver.refTracking = trackRef, refWriterEnabled, refTracking
+			ctx.trackMapKeyRef = trackMapKeyRef
+		}()
+	}
+	if o.sortMapKeys {
+		ctx.sortMapKeys = true
+		defer func() {
+			ctx.sortMapKeys = false
+		}()
+	}
+	if o.stringDict && ctx.stringDict == nil {
+		ctx.enableStringDict()
+		defer func() {
+			ctx.stringDict, ctx.dictWriter = nil, nil
+		}()
+	}
+	if o.appendTo {
+		return f.MarshalAppend(o.dst, v)
+	}
 	return f.Serialize(v)
 }
 
+// MarshalAppend appends the encoding of v to dst and returns the extended slice.
+// The result is owned by the caller and stays valid across later calls, so a
+// loop can reuse one buffer with dst[:0] and allocate only when it must grow.
+// On error dst is returned unchanged.
+func (f *Fory) MarshalAppend(dst []byte, v any) ([]byte, error) {
+	buf := &f.appendBuffer
+	buf.data = dst[:cap(dst)]
+	buf.writerIndex = len(dst)
+	buf.readerIndex = 0
+	err := f.SerializeTo(buf, v)
+	out := buf.data[:buf.writerIndex]
+	buf.data = nil
+	if err != nil {
+		return dst, err
+	}
+	return out, nil
+}
+
+// SizeOf returns the exact number of bytes Marshal would produce for v.
+// The size is measured by encoding v into a scratch buffer private to SizeOf,
+// so it costs as much as a Marshal call, but slices previously returned by
+// Marshal stay valid.
+func (f *Fory) SizeOf(v any) (int, error) {
+	buf := &f.sizeBuffer
+	buf.Reset()
+	if err := f.SerializeTo(buf, v); err != nil {
+		return 0, err
+	}
+	return buf.writerIndex, nil
+}
+
 // Unmarshal deserializes bytes into the provided value.
 func (f *Fory) Unmarshal(data []byte, v any) error {
 	return f.Deserialize(data, v)
@@ -673,7 +1666,10 @@ func (f *Fory) Unmarshal(data []byte, v any) error {
 // The third parameter is an optional callback for buffer objects (can be nil).
 // If callback is provided, it will be called for each BufferObject during serialization.
 // Return true from callback to write in-band, false for out-of-band.
-func (f *Fory) SerializeWithCallback(buffer *ByteBuffer, v any, callback func(BufferObject) bool) error {
+func (f *Fory) SerializeWithCallback(buffer *ByteBuffer, v any, callback func(BufferObject) bool) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverWrite(&err, f.writeCtx.buffer)
+	}
 	buf := f.writeCtx.buffer
 	defer func() {
 		// Reset internal state but NOT the buffer - caller manages buffer state
@@ -707,27 +1703,53 @@ func (f *Fory) SerializeWithCallback(buffer *ByteBuffer, v any, callback func(Bu
 	if f.writeCtx.HasError() {
 		return f.writeCtx.TakeError()
 	}
+	if f.writeCtx.frameBody {
+		return finishBody(f.writeCtx)
+	}
 
 	return nil
 }
 
 // DeserializeWithCallbackBuffers deserializes from buffer into the provided value (for streaming/cross-language use).
 // The third parameter is optional external buffers for out-of-band data (can be nil).
-func (f *Fory) DeserializeWithCallbackBuffers(buffer *ByteBuffer, v any, buffers []*ByteBuffer) error {
+func (f *Fory) DeserializeWithCallbackBuffers(buffer *ByteBuffer, v any, buffers []*ByteBuffer) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
+	// Set up out-of-band buffers if provided
+	f.readCtx.outOfBandBuffers = buffers
+	return f.deserializeOutOfBand(buffer, v)
+}
+
+// DeserializeWithBufferProvider is like DeserializeWithCallbackBuffers, but
+// fetches out-of-band buffers as the payload references them, so they can be
+// loaded lazily from remote or blob storage. The provider receives the
+// zero-based index of each buffer in the order SerializeWithCallback offered
+// them; returning nil fails the call.
+func (f *Fory) DeserializeWithBufferProvider(buffer *ByteBuffer, v any, provider func(index int) *ByteBuffer) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
+	f.readCtx.bufferProvider = provider
+	return f.deserializeOutOfBand(buffer, v)
+}
+
+// deserializeOutOfBand reads a payload from buffer into v using the out-of-band
+// source already set on the read context.
+func (f *Fory) deserializeOutOfBand(buffer *ByteBuffer, v any) error {
 	// Reset context and use the provided buffer
 	f.readCtx.buffer = buffer
 	defer func() {
+		if f.readCtx.bufferCopies != nil {
+			// Copies write into the decoded value and may read the input.
+			f.readCtx.bufferCopies.wait()
+		}
 		f.readCtx.Reset()
 		if f.metaContext != nil {
 			f.metaContext.Reset()
 		}
 		f.readCtx.buffer = nil
-		f.readCtx.outOfBandBuffers = nil
 	}()
-	// Set up out-of-band buffers if provided
-	if buffers != nil {
-		f.readCtx.outOfBandBuffers = buffers
-	}
 
 	// ReadData and validate header
 	readHeader(f.readCtx)
@@ -735,25 +1757,8 @@ func (f *Fory) DeserializeWithCallbackBuffers(buffer *ByteBuffer, v any, buffers
 		return f.readCtx.TakeError()
 	}
 
-	// v must be a pointer so we can deserialize into it
-	if v == nil {
-		return fmt.Errorf("v cannot be nil")
-	}
-	rv := reflect.ValueOf(v)
-	if rv.Kind() != reflect.Ptr {
-		return fmt.Errorf("v must be a pointer, got %v", rv.Kind())
-	}
-	if rv.IsNil() {
-		return fmt.Errorf("v must be a non-nil pointer")
-	}
-
 	// Deserialize the value - TypeMeta is read inline using streaming protocol
-	f.readCtx.ReadValue(rv.Elem(), RefModeTracking, true)
-	if f.readCtx.HasError() {
-		return f.readCtx.TakeError()
-	}
-
-	return nil
+	return f.readRoot(v)
 }
 
 // serializeReflectValue serializes a reflect.Value directly, avoiding boxing overhead.
@@ -772,6 +1777,11 @@ func (f *Fory) serializeReflectValue(value reflect.Value) ([]byte, error) {
 	if f.writeCtx.HasError() {
 		return nil, f.writeCtx.TakeError()
 	}
+	if f.writeCtx.frameBody {
+		if err := finishBody(f.writeCtx); err != nil {
+			return nil, err
+		}
+	}
 
 	return f.writeCtx.buffer.GetByteSlice(0, f.writeCtx.buffer.writerIndex), nil
 }
@@ -782,19 +1792,127 @@ func (f *Fory) serializeReflectValue(value reflect.Value) ([]byte, error) {
 
 // writeHeader writes the Fory protocol header
 func writeHeader(ctx *WriteContext, config Config) {
-	var bitmap byte = 0
-	if config.IsXlang {
-		bitmap |= XLangFlag
+	if !config.OmitHeader {
+		var bitmap byte = 0
+		if config.IsXlang {
+			bitmap |= XLangFlag
+		}
+		if ctx.outOfBand {
+			bitmap |= OutOfBandFlag
+		}
+		if ctx.codec != nil {
+			bitmap |= (ctx.codec.ID() << codecShift) & codecMask
+		}
+		if ctx.checksum {
+			bitmap |= checksumFlag
+		}
+		if ctx.stringDict != nil {
+			bitmap |= stringDictFlag
+		}
+		ctx.buffer.WriteByte_(bitmap)
+	}
+	if ctx.checksum {
+		reserveChecksum(ctx)
+	}
+	ctx.bodyStart = ctx.buffer.writerIndex
+}
+
+// finishBody compresses and checksums the body written since writeHeader,
+// as configured.
+func finishBody(ctx *WriteContext) error {
+	if ctx.codec != nil {
+		if err := compressBody(ctx); err != nil {
+			return err
+		}
 	}
-	if ctx.outOfBand {
-		bitmap |= OutOfBandFlag
+	if ctx.checksum {
+		writeChecksum(ctx)
 	}
-	ctx.buffer.WriteByte_(bitmap)
+	return nil
+}
+
+// Header is the root header at the start of every payload. It is a single
+// bitmap byte: the format has no magic number or language tag, and reference
+// tracking is signalled per value rather than in the header.
+type Header struct {
+	// Xlang reports whether the payload uses the xlang format rather than
+	// Go native mode.
+	Xlang bool
+	// OutOfBand reports whether the payload references out-of-band buffers,
+	// which must be supplied through DeserializeWithCallbackBuffers or
+	// DeserializeWithBufferProvider.
+	OutOfBand bool
+	// Codec is the ID of the Codec that compressed the body, or 0.
+	Codec uint8
+	// Checksum reports whether the body is prefixed with its CRC-32C.
+	Checksum bool
+	// StringDictionary reports whether repeated strings are written as
+	// references to their first occurrence.
+	StringDictionary bool
+}
+
+// HeaderSize is the number of bytes ParseHeader needs.
+const HeaderSize = 1
+
+// ParseHeader decodes the root header from a payload prefix without reading
+// the body, so routers can inspect messages before choosing a decoder.
+func ParseHeader(data []byte) (Header, error) {
+	if len(data) < HeaderSize {
+		return Header{}, fmt.Errorf("payload of %d bytes is shorter than the root header", len(data))
+	}
+	bitmap := data[0]
+	if bitmap&^(headerFlagMask|codecMask|checksumFlag|stringDictFlag) != 0 {
+		return Header{}, fmt.Errorf("unsupported root header bitmap 0x%02x", bitmap)
+	}
+	return Header{
+		Xlang:            bitmap&XLangFlag != 0,
+		OutOfBand:        bitmap&OutOfBandFlag != 0,
+		Codec:            (bitmap & codecMask) >> codecShift,
+		Checksum:         bitmap&checksumFlag != 0,
+		StringDictionary: bitmap&stringDictFlag != 0,
+	}, nil
+}
+
+// Header returns the header f writes for in-band payloads. Instances
+// configured with WithHeader(false) write none.
+func (f *Fory) Header() Header {
+	header := Header{Xlang: f.config.IsXlang, Checksum: f.config.Checksum, StringDictionary: f.config.StringDictionary}
+	if f.config.Compression != nil {
+		header.Codec = f.config.Compression.ID()
+	}
+	return header
+}
+
+// CheckHeader reports whether f can decode a payload with header h. Payloads
+// with out-of-band buffers are accepted, but must be read with
+// DeserializeWithCallbackBuffers or DeserializeWithBufferProvider.
+func (f *Fory) CheckHeader(h Header) error {
+	if h.Xlang != f.config.IsXlang {
+		if h.Xlang {
+			return fmt.Errorf("payload uses the xlang format but this instance reads native mode")
+		}
+		return fmt.Errorf("payload uses native mode but this instance reads the xlang format")
+	}
+	if h.Codec != 0 && (f.config.Compression == nil || f.config.Compression.ID() != h.Codec) {
+		return fmt.Errorf("payload is compressed with codec %d, which is not configured", h.Codec)
+	}
+	return nil
 }
 
 // readHeader reads and validates the Fory protocol header
 // Sets error on ctx if header is invalid (use ctx.HasError() to check)
 func readHeader(ctx *ReadContext) {
+	if ctx.omitHeader {
+		ctx.peerOutOfBand = ctx.hasOutOfBandSource()
+		ctx.stringDict = ctx.expectStringDict
+		if ctx.checksum {
+			verifyChecksum(ctx)
+		}
+		if ctx.codec != nil && !ctx.HasError() {
+			inflateBody(ctx, ctx.codec.ID())
+		}
+		return
+	}
 	err := ctx.Err()
 	bitmap := ctx.buffer.ReadByte(err)
 	if ctx.HasError() {
@@ -808,6 +1926,10 @@ func readHeader(ctx *ReadContext) {
 
 //go:noinline
 func readHeaderSlow(ctx *ReadContext, bitmap byte) {
+	codecID := (bitmap & codecMask) >> codecShift
+	hasChecksum := bitmap&checksumFlag != 0
+	ctx.stringDict = bitmap&stringDictFlag != 0
+	bitmap &^= codecMask | checksumFlag | stringDictFlag
 	if bitmap&^headerFlagMask != 0 {
 		ctx.SetError(DeserializationErrorf("unsupported root header bitmap 0x%02x", bitmap))
 		return
@@ -816,10 +1938,20 @@ func readHeaderSlow(ctx *ReadContext, bitmap byte) {
 		ctx.SetError(DeserializationErrorf("header bitmap mismatch at xlang bit"))
 		return
 	}
-	if (bitmap&OutOfBandFlag) != 0 && ctx.outOfBandBuffers == nil {
+	if (bitmap&OutOfBandFlag) != 0 && !ctx.hasOutOfBandSource() {
 		ctx.SetError(DeserializationErrorf("out-of-band buffers are required by root header"))
 		return
 	}
+	ctx.peerOutOfBand = bitmap&OutOfBandFlag != 0
+	if hasChecksum {
+		verifyChecksum(ctx)
+		if ctx.HasError() {
+			return
+		}
+	}
+	if codecID != 0 {
+		inflateBody(ctx, codecID)
+	}
 }
 
 // ============================================================================
@@ -841,13 +1973,25 @@ func readHeaderSlow(ctx *ReadContext, bitmap byte) {
 //
 // For thread-safe usage, use threadsafe.Serialize which copies the data internally.
 func Serialize[T any](f *Fory, value T) ([]byte, error) {
+	if m := f.config.Metrics; m != nil {
+		start := time.Now()
+		data, err := serializeTyped(f, value)
+		m.Serialized(reflect.TypeFor[T](), len(data), time.Since(start), err)
+		return data, err
+	}
+	return serializeTyped(f, value)
+}
+
+func serializeTyped[T any](f *Fory, value T) (_ []byte, err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverWrite(&err, f.writeCtx.buffer)
+	}
 	defer f.resetWriteState()
 	// WriteData protocol header
 	writeHeader(f.writeCtx, f.config)
 
 	// Fast path: type switch for common types (Go compiler can optimize this)
 	v := any(value)
-	var err error
 	switch val := v.(type) {
 	case bool:
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
@@ -898,27 +2042,51 @@ func Serialize[T any](f *Fory, value T) ([]byte, error) {
 			f.writeCtx.buffer.WriteBinary(unsafe.Slice(unsafe.StringData(val), len(val)))
 		}
 	case []byte:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(BINARY)
 		f.writeCtx.buffer.WriteLength(len(val))
 		f.writeCtx.buffer.WriteBinary(val)
 	case []int8:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(INT8_ARRAY)
 		WriteInt8Slice(f.writeCtx.buffer, val)
 	case []int16:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(INT16_ARRAY)
 		WriteInt16Slice(f.writeCtx.buffer, val)
 	case []int32:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(INT32_ARRAY)
 		WriteInt32Slice(f.writeCtx.buffer, val)
 	case []int64:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(INT64_ARRAY)
 		WriteInt64Slice(f.writeCtx.buffer, val)
 	case []int:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		if strconv.IntSize == 64 {
 			f.writeCtx.WriteTypeId(INT64_ARRAY)
@@ -927,53 +2095,101 @@ func Serialize[T any](f *Fory, value T) ([]byte, error) {
 		}
 		WriteIntSlice(f.writeCtx.buffer, val)
 	case []float32:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(FLOAT32_ARRAY)
 		WriteFloat32Slice(f.writeCtx.buffer, val)
 	case []float64:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(FLOAT64_ARRAY)
 		WriteFloat64Slice(f.writeCtx.buffer, val)
 	case []bool:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(BOOL_ARRAY)
 		WriteBoolSlice(f.writeCtx.buffer, val)
 	case map[string]string:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringString(f.writeCtx.buffer, val, false)
+		writeMapStringString(f.writeCtx, val, false)
 	case map[string]int64:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringInt64(f.writeCtx.buffer, val, false)
+		writeMapStringInt64(f.writeCtx, val, false)
 	case map[string]int32:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringInt32(f.writeCtx.buffer, val, false)
+		writeMapStringInt32(f.writeCtx, val, false)
 	case map[string]int:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringInt(f.writeCtx.buffer, val, false)
+		writeMapStringInt(f.writeCtx, val, false)
 	case map[string]float64:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringFloat64(f.writeCtx.buffer, val, false)
+		writeMapStringFloat64(f.writeCtx, val, false)
 	case map[string]bool:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapStringBool(f.writeCtx.buffer, val, false)
+		writeMapStringBool(f.writeCtx, val, false)
 	case map[int32]int32:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapInt32Int32(f.writeCtx.buffer, val, false)
+		writeMapInt32Int32(f.writeCtx, val, false)
 	case map[int64]int64:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapInt64Int64(f.writeCtx.buffer, val, false)
+		writeMapInt64Int64(f.writeCtx, val, false)
 	case map[int]int:
+		if val == nil {
+			f.writeCtx.buffer.WriteInt8(NullFlag)
+			break
+		}
 		f.writeCtx.buffer.WriteInt8(NotNullValueFlag)
 		f.writeCtx.WriteTypeId(MAP)
-		writeMapIntInt(f.writeCtx.buffer, val, false)
+		writeMapIntInt(f.writeCtx, val, false)
 	default:
 		// Fall back to reflection-based serialization
 		return f.serializeReflectValue(reflect.ValueOf(v))
@@ -982,6 +2198,11 @@ func Serialize[T any](f *Fory, value T) ([]byte, error) {
 	if err != nil {
 		return nil, err
 	}
+	if f.writeCtx.frameBody {
+		if err := finishBody(f.writeCtx); err != nil {
+			return nil, err
+		}
+	}
 
 	// Return copy of buffer data
 	return f.writeCtx.buffer.GetByteSlice(0, f.writeCtx.buffer.writerIndex), nil
@@ -994,6 +2215,19 @@ func Serialize[T any](f *Fory, value T) ([]byte, error) {
 // For structs, it reads directly into the struct fields.
 // Note: Fory instance is NOT thread-safe. Use ThreadSafeFory for concurrent use.
 func Deserialize[T any](f *Fory, data []byte, target *T) error {
+	if m := f.config.Metrics; m != nil {
+		start := time.Now()
+		err := deserializeTyped(f, data, target)
+		m.Deserialized(reflect.TypeFor[T](), len(data), time.Since(start), err)
+		return err
+	}
+	return deserializeTyped(f, data, target)
+}
+
+func deserializeTyped[T any](f *Fory, data []byte, target *T) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
 	// Reuse context, reset and set new data
 	f.readCtx.Reset()
 	f.readCtx.SetData(data)
@@ -1004,76 +2238,91 @@ func Deserialize[T any](f *Fory, data []byte, target *T) error {
 		return f.readCtx.TakeError()
 	}
 
+	if target == nil {
+		return DeserializationError("target must be a non-nil pointer")
+	}
+	if f.readCtx.readNullRoot() {
+		var zero T
+		*target = zero
+		return nil
+	}
+
+	// The typed fast paths below allocate fresh slices and maps; object reuse
+	// goes through the serializers, which decode into the existing target.
+	if f.readCtx.reuseObjects {
+		return f.readTarget(reflect.ValueOf(target).Elem())
+	}
+
 	// Fast path: type switch for common types (Go compiler can optimize this)
 	// For primitives, read null flag, skip type ID, then read value from buffer
 	buf := f.readCtx.buffer
-	err := f.readCtx.Err()
+	ctxErr := f.readCtx.Err()
 	switch t := any(target).(type) {
 	case *bool:
-		_ = buf.ReadInt8(err) // null flag
+		_ = buf.ReadInt8(ctxErr) // null flag
 		if !f.readCtx.readExpectedTypeID(BOOL) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadBool(err)
+		*t = buf.ReadBool(ctxErr)
 		return f.readCtx.CheckError()
 	case *int8:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(INT8) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadInt8(err)
+		*t = buf.ReadInt8(ctxErr)
 		return f.readCtx.CheckError()
 	case *int16:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(INT16) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadInt16(err)
+		*t = buf.ReadInt16(ctxErr)
 		return f.readCtx.CheckError()
 	case *int32:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(VARINT32) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadVarint32(err)
+		*t = buf.ReadVarint32(ctxErr)
 		return f.readCtx.CheckError()
 	case *int64:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(VARINT64) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadVarint64(err)
+		*t = buf.ReadVarint64(ctxErr)
 		return f.readCtx.CheckError()
 	case *int:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if strconv.IntSize == 32 {
 			if !f.readCtx.readExpectedTypeID(VARINT32) {
 				return f.readCtx.CheckError()
 			}
-			*t = int(buf.ReadVarint32(err))
+			*t = int(buf.ReadVarint32(ctxErr))
 			return f.readCtx.CheckError()
 		}
 		if !f.readCtx.readExpectedTypeID(VARINT64) {
 			return f.readCtx.CheckError()
 		}
-		*t = int(buf.ReadVarint64(err))
+		*t = int(buf.ReadVarint64(ctxErr))
 		return f.readCtx.CheckError()
 	case *float32:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(FLOAT32) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadFloat32(err)
+		*t = buf.ReadFloat32(ctxErr)
 		return f.readCtx.CheckError()
 	case *float64:
-		_ = buf.ReadInt8(err)
+		_ = buf.ReadInt8(ctxErr)
 		if !f.readCtx.readExpectedTypeID(FLOAT64) {
 			return f.readCtx.CheckError()
 		}
-		*t = buf.ReadFloat64(err)
+		*t = buf.ReadFloat64(ctxErr)
 		return f.readCtx.CheckError()
 	case *string:
-		_ = buf.ReadInt8(err) // null flag
+		_ = buf.ReadInt8(ctxErr) // null flag
 		if !f.readCtx.readExpectedTypeID(STRING) {
 			return f.readCtx.CheckError()
 		}
@@ -1135,17 +2384,21 @@ func Deserialize[T any](f *Fory, data []byte, target *T) error {
 		return f.readCtx.CheckError()
 	default:
 		// Slow path: use serializer-based deserialization
-		targetVal := reflect.ValueOf(target).Elem()
-		targetType := targetVal.Type()
+		return f.readTarget(reflect.ValueOf(target).Elem())
+	}
+}
 
-		// Get serializer for the target type
-		serializer, err := f.typeResolver.getSerializerByType(targetType, false)
-		if err != nil {
-			return fmt.Errorf("failed to get serializer for type %v: %w", targetType, err)
-		}
+// readTarget deserializes the root value into targetVal through its serializer.
+func (f *Fory) readTarget(targetVal reflect.Value) error {
+	targetType := targetVal.Type()
 
-		// Use Read to deserialize directly into target
-		serializer.Read(f.readCtx, RefModeTracking, true, false, targetVal)
-		return f.readCtx.CheckError()
+	// Get serializer for the target type
+	serializer, err := f.typeResolver.getSerializerByType(targetType, false)
+	if err != nil {
+		return fmt.Errorf("failed to get serializer for type %v: %w", targetType, err)
 	}
+
+	// Use Read to deserialize directly into target
+	serializer.Read(f.readCtx, RefModeTracking, true, false, targetVal)
+	return f.readCtx.CheckError()
 }
@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -49,6 +51,24 @@ type ReducedDataClass struct {
 	// Missing 'active' field
 }
 
+type WidenedDataClass struct {
+	Name   string
+	Age    int64 // Widened from int32
+	Active bool
+}
+
+type ReorderedDataClass struct {
+	Active bool
+	Age    int32
+	Name   string
+}
+
+type ReplacedDataClass struct {
+	Age    int32
+	Phones []string // Replaces 'active' and 'email'
+	Name   string
+}
+
 type SliceDataClass struct {
 	Name  string
 	Items []string
@@ -330,6 +350,48 @@ func TestCompatibleSerializationScenarios(t *testing.T) {
 				assert.Equal(t, in.Age, out.Age)
 			},
 		},
+		{
+			name:      "SchemaEvolutionWidenField",
+			tag:       "TestStructWiden",
+			writeType: SimpleDataClass{},
+			readType:  WidenedDataClass{},
+			input:     SimpleDataClass{Name: "test", Age: -25, Active: true},
+			assertFunc: func(t *testing.T, input any, output any) {
+				in := input.(SimpleDataClass)
+				out := output.(WidenedDataClass)
+				assert.Equal(t, in.Name, out.Name)
+				assert.Equal(t, int64(in.Age), out.Age)
+				assert.Equal(t, in.Active, out.Active)
+			},
+		},
+		{
+			name:      "SchemaEvolutionReorderFields",
+			tag:       "TestStructReorder",
+			writeType: SimpleDataClass{},
+			readType:  ReorderedDataClass{},
+			input:     SimpleDataClass{Name: "test", Age: 25, Active: true},
+			assertFunc: func(t *testing.T, input any, output any) {
+				in := input.(SimpleDataClass)
+				out := output.(ReorderedDataClass)
+				assert.Equal(t, in.Name, out.Name)
+				assert.Equal(t, in.Age, out.Age)
+				assert.Equal(t, in.Active, out.Active)
+			},
+		},
+		{
+			name:      "SchemaEvolutionAddAndRemoveFields",
+			tag:       "TestStructAddRemove",
+			writeType: ExtendedDataClass{},
+			readType:  ReplacedDataClass{},
+			input:     ExtendedDataClass{Name: "test", Age: 25, Active: true, Email: "a@b.c"},
+			assertFunc: func(t *testing.T, input any, output any) {
+				in := input.(ExtendedDataClass)
+				out := output.(ReplacedDataClass)
+				assert.Equal(t, in.Name, out.Name)
+				assert.Equal(t, in.Age, out.Age)
+				assert.Nil(t, out.Phones)
+			},
+		},
 		{
 			name:      "SliceFields",
 			tag:       "SliceDataClass",
@@ -678,3 +740,141 @@ func runCompatibilityCase(t *testing.T, tc compatibilityCase) {
 
 	tc.assertFunc(t, tc.input, target.Elem().Interface())
 }
+
+type sparseProfile struct {
+	Rank   int64
+	Name   string
+	Score  float64
+	Active bool
+	Age    int32
+	Tags   []string
+	Limits map[string]int32
+	Parent *SimpleDataClass
+}
+
+type sparseProfileV2 struct {
+	Rank  int64
+	Name  string
+	Extra string
+}
+
+func newSparseFory(t *testing.T, opts ...Option) *Fory {
+	t.Helper()
+	f := New(append([]Option{WithXlang(true), WithCompatible(true)}, opts...)...)
+	assert.NoError(t, f.RegisterStructByName(sparseProfile{}, "example.SparseProfile"))
+	assert.NoError(t, f.RegisterStructByName(SimpleDataClass{}, "example.Simple"))
+	return f
+}
+
+func TestOmitZeroFields(t *testing.T) {
+	writer := newSparseFory(t, WithOmitZeroFields(true))
+	full := &sparseProfile{
+		Rank: 7, Name: "ann", Score: 1.5, Active: true, Age: 30,
+		Tags:   []string{"a"},
+		Limits: map[string]int32{"x": 1},
+		Parent: &SimpleDataClass{Name: "bob", Age: 60},
+	}
+	sparse := &sparseProfile{Rank: 7, Parent: &SimpleDataClass{Active: true}}
+
+	t.Run("Shrinks", func(t *testing.T) {
+		omitted, err := writer.Marshal(sparse)
+		assert.NoError(t, err)
+		written, err := newSparseFory(t).Marshal(sparse)
+		assert.NoError(t, err)
+		assert.Less(t, len(omitted)+8, len(written))
+	})
+
+	t.Run("RoundTrip", func(t *testing.T) {
+		for _, reader := range []*Fory{writer, newSparseFory(t)} {
+			for _, in := range []*sparseProfile{full, sparse, {}} {
+				data, err := writer.Marshal(in)
+				assert.NoError(t, err)
+				var out sparseProfile
+				assert.NoError(t, reader.Unmarshal(data, &out))
+				assert.Equal(t, *in, out)
+			}
+			data, err := writer.Marshal([]sparseProfile{*full, *sparse, {Age: 3}})
+			assert.NoError(t, err)
+			var list []sparseProfile
+			assert.NoError(t, reader.Unmarshal(data, &list))
+			assert.Equal(t, []sparseProfile{*full, *sparse, {Age: 3}}, list)
+		}
+	})
+
+	t.Run("AbsentFieldsAreZeroed", func(t *testing.T) {
+		data, err := writer.Marshal(sparse)
+		assert.NoError(t, err)
+		out := *full
+		assert.NoError(t, writer.Unmarshal(data, &out))
+		assert.Equal(t, *sparse, out)
+	})
+
+	t.Run("Evolved", func(t *testing.T) {
+		reader := New(WithXlang(true), WithCompatible(true))
+		assert.NoError(t, reader.RegisterStructByName(sparseProfileV2{}, "example.SparseProfile"))
+		assert.NoError(t, reader.RegisterStructByName(SimpleDataClass{}, "example.Simple"))
+		data, err := writer.Marshal(&sparseProfile{Name: "ann", Tags: []string{"a"}})
+		assert.NoError(t, err)
+		out := sparseProfileV2{Rank: 1, Extra: "kept"}
+		assert.NoError(t, reader.Unmarshal(data, &out))
+		assert.Equal(t, sparseProfileV2{Name: "ann", Extra: "kept"}, out)
+
+		var projected sparseProfile
+		assert.NoError(t, writer.UnmarshalProjected(data, &projected, NewProjection("Tags")))
+		assert.Equal(t, sparseProfile{Tags: []string{"a"}}, projected)
+	})
+
+	t.Run("GenericRecord", func(t *testing.T) {
+		in := &sparseProfile{Rank: 7, Tags: []string{"a"}, Parent: &SimpleDataClass{Age: 2}}
+		data, err := writer.Marshal(in)
+		assert.NoError(t, err)
+		router := New(WithXlang(true), WithCompatible(true))
+		var rec *GenericRecord
+		assert.NoError(t, router.Unmarshal(data, &rec))
+		name, ok := rec.Get("name")
+		assert.True(t, ok)
+		assert.Equal(t, "", name)
+		forwarded, err := router.Marshal(rec)
+		assert.NoError(t, err)
+		var out sparseProfile
+		assert.NoError(t, writer.Unmarshal(forwarded, &out))
+		assert.Equal(t, *in, out)
+	})
+
+	t.Run("EvolvingOptOut", func(t *testing.T) {
+		writer := New(WithXlang(true), WithCompatible(true), WithOmitZeroFields(true))
+		assert.NoError(t, writer.RegisterStruct(fixedStruct{}, 101))
+		data, err := writer.Marshal(&fixedStruct{})
+		assert.NoError(t, err)
+		reader := New(WithXlang(true), WithCompatible(true))
+		assert.NoError(t, reader.RegisterStruct(fixedStruct{}, 101))
+		out := fixedStruct{ID: 5}
+		assert.NoError(t, reader.Unmarshal(data, &out))
+		assert.Equal(t, fixedStruct{}, out)
+	})
+
+	t.Run("UnknownType", func(t *testing.T) {
+		data, err := writer.Marshal([]any{sparse, "after"})
+		assert.NoError(t, err)
+		reader := New(WithXlang(true), WithCompatible(true), WithUnknownStructsAsMaps(true))
+		var out []any
+		assert.NoError(t, reader.Unmarshal(data, &out))
+		if assert.Len(t, out, 2) {
+			profile := out[0].(map[string]any)
+			assert.Equal(t, int64(7), profile["rank"])
+			assert.Equal(t, "", profile["name"])
+			assert.Nil(t, profile["tags"])
+			assert.Equal(t, map[string]any{"name": "", "age": int32(0), "active": true}, profile["parent"])
+			assert.Equal(t, "after", out[1])
+		}
+
+		out = nil
+		_, err = New(WithXlang(true), WithCompatible(true)).UnmarshalPartial(data, &out)
+		assert.NoError(t, err)
+		assert.Equal(t, []any{nil, "after"}, out)
+
+		dump, err := Dump(data)
+		assert.NoError(t, err)
+		assert.Contains(t, dump, "after")
+	})
+}
@@ -15,11 +15,17 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
+	"bytes"
+	"errors"
 	"fmt"
+	"io"
 	"reflect"
+	"strings"
 	"testing"
 	"unsafe"
 
@@ -162,6 +168,56 @@ func TestSerializePtr(t *testing.T) {
 	}
 }
 
+func TestNullRoot(t *testing.T) {
+	type point struct{ X int32 }
+	for _, xlang := range []bool{false, true} {
+		f := NewFory(WithXlang(xlang), WithPanicRecovery(false))
+		require.NoError(t, f.RegisterStruct(point{}, 301))
+		for _, value := range []any{nil, (*point)(nil), []int32(nil), map[string]string(nil)} {
+			data, err := f.Marshal(value)
+			require.NoError(t, err)
+			require.Equal(t, NullFlag, int8(data[len(data)-1]), "%T", value)
+		}
+		for _, data := range [][]byte{
+			mustSerialize(t, f, []int32(nil)),
+			mustSerialize(t, f, []byte(nil)),
+			mustSerialize(t, f, map[string]int64(nil)),
+		} {
+			require.Equal(t, NullFlag, int8(data[len(data)-1]))
+		}
+
+		null, err := f.Marshal(nil)
+		require.NoError(t, err)
+		null = bytes.Clone(null)
+		ptr := &point{X: 1}
+		require.NoError(t, f.Unmarshal(null, &ptr))
+		require.Nil(t, ptr)
+		var dynamic any = 1
+		require.NoError(t, f.Unmarshal(null, &dynamic))
+		require.Nil(t, dynamic)
+		slice := []int32{1}
+		require.NoError(t, f.Unmarshal(null, &slice))
+		require.Nil(t, slice)
+		value := point{X: 1}
+		require.NoError(t, f.Unmarshal(null, &value))
+		require.Equal(t, point{}, value)
+		n := int32(5)
+		require.NoError(t, Deserialize(f, null, &n))
+		require.Equal(t, int32(0), n)
+
+		err = f.Unmarshal(null, value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "non-nil pointer")
+		require.Error(t, f.Unmarshal(null, nil))
+	}
+}
+
+func mustSerialize[T any](t *testing.T, f *Fory, value T) []byte {
+	data, err := Serialize(f, value)
+	require.NoError(t, err)
+	return bytes.Clone(data)
+}
+
 func TestSerializeSlice(t *testing.T) {
 	for _, referenceTracking := range []bool{false, true} {
 		fory := NewFory(WithXlang(true), WithCompatible(false), WithRefTracking(referenceTracking))
@@ -320,6 +376,417 @@ func TestNamedStructRegistrationCoversPointer(t *testing.T) {
 	require.Equal(t, value, decodedPointer)
 }
 
+type pointerRegistrationItem struct {
+	Name string
+}
+
+type pointerRegistrationHolder struct {
+	Value   pointerRegistrationItem
+	Pointer *pointerRegistrationItem
+	Values  []pointerRegistrationItem
+	Ptrs    []*pointerRegistrationItem
+}
+
+func TestValueAndPointerRegistrationConflicts(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(pointerRegistrationItem{}, 1))
+	// *T resolves to the registration of T, so the same ID is accepted.
+	require.NoError(t, f.RegisterStruct(&pointerRegistrationItem{}, 1))
+
+	err := f.RegisterStruct(&pointerRegistrationItem{}, 2)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already registered with id 1")
+	err = f.RegisterStructByName(&pointerRegistrationItem{}, "example.Item")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already registered with id 1")
+
+	f = NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(&pointerRegistrationItem{}, "example.Item"))
+	err = f.RegisterStruct(pointerRegistrationItem{}, 3)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `is already registered as "example.Item"`)
+}
+
+type nameConflictItem struct {
+	ID int32
+}
+
+type nameConflictCounts map[string]int32
+
+func TestRegisteredNameConflicts(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(pointerRegistrationItem{}, "example.Item"))
+	require.NoError(t, f.RegisterStructByName(stateItem{}, "example.Other"))
+	for name, register := range map[string]func() error{
+		"struct": func() error { return f.RegisterStructByName(&nameConflictItem{}, "example.Item") },
+		"enum":   func() error { return f.RegisterEnumByName(namedAuditEnum(0), "example.Item") },
+		"extension": func() error {
+			return f.RegisterExtensionByName(checkedVersion{}, "example.Item", CheckedExtension(checkedVersionSerializer{}))
+		},
+		"union": func() error { return f.RegisterUnionByName(nameConflictItem{}, "example.Item", NewUnionSerializer()) },
+		"alias": func() error { return f.RegisterTypeAlias("example.Item", &stateItem{}) },
+	} {
+		err := register()
+		require.Error(t, err, name)
+		require.Contains(t, err.Error(), "example.Item is already registered for type fory.pointerRegistrationItem", name)
+	}
+	_, ok := f.typeResolver.typesInfo[reflect.TypeOf(nameConflictItem{})]
+	require.False(t, ok)
+	data, err := f.Serialize(&pointerRegistrationItem{Name: "a"})
+	require.NoError(t, err)
+	var out pointerRegistrationItem
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, "a", out.Name)
+
+	// Unregistered named types are named after their package path.
+	f = NewFory(WithXlang(false))
+	counts := reflect.TypeOf(nameConflictCounts{})
+	require.NoError(t, f.RegisterStructByName(pointerRegistrationItem{}, counts.PkgPath()+"."+counts.Name()))
+	_, err = f.Serialize(nameConflictCounts{"a": 1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is already registered for type fory.pointerRegistrationItem")
+
+	// An explicit registration takes a derived name over.
+	f = NewFory(WithXlang(false))
+	_, err = f.Serialize(nameConflictCounts{"a": 1})
+	require.NoError(t, err)
+	require.NoError(t, f.RegisterStructByName(pointerRegistrationItem{}, counts.PkgPath()+"."+counts.Name()))
+	data, err = f.Serialize(&pointerRegistrationItem{Name: "b"})
+	require.NoError(t, err)
+	var decoded any
+	require.NoError(t, f.Deserialize(data, &decoded))
+	require.Equal(t, &pointerRegistrationItem{Name: "b"}, decoded)
+}
+
+func TestValueAndPointerRoundTrips(t *testing.T) {
+	item := pointerRegistrationItem{Name: "a"}
+	deref := func(v any) any {
+		if p, ok := v.(*pointerRegistrationItem); ok {
+			return *p
+		}
+		return v
+	}
+	for _, registered := range []any{pointerRegistrationItem{}, &pointerRegistrationItem{}} {
+		for _, xlang := range []bool{false, true} {
+			for _, compatible := range []bool{false, true} {
+				name := fmt.Sprintf("%T/xlang=%v/compatible=%v", registered, xlang, compatible)
+				f := NewFory(WithXlang(xlang), WithCompatible(compatible))
+				require.NoError(t, f.RegisterStruct(registered, 1))
+				require.NoError(t, f.RegisterStruct(pointerRegistrationHolder{}, 2))
+
+				data, err := f.Marshal(&item)
+				require.NoError(t, err, name)
+				var value pointerRegistrationItem
+				require.NoError(t, f.Unmarshal(data, &value), name)
+				require.Equal(t, item, value, name)
+				var pointer *pointerRegistrationItem
+				require.NoError(t, f.Unmarshal(data, &pointer), name)
+				require.Equal(t, item, *pointer, name)
+				var dynamic any
+				require.NoError(t, f.Unmarshal(data, &dynamic), name)
+				require.Equal(t, item, deref(dynamic), name)
+
+				for _, written := range []any{
+					[]pointerRegistrationItem{item},
+					[]*pointerRegistrationItem{&item},
+					[]any{item},
+					[]any{&item},
+				} {
+					data, err := f.Marshal(written)
+					require.NoError(t, err, name)
+					var values []pointerRegistrationItem
+					require.NoError(t, f.Unmarshal(data, &values), name)
+					require.Equal(t, []pointerRegistrationItem{item}, values, name)
+					var pointers []*pointerRegistrationItem
+					require.NoError(t, f.Unmarshal(data, &pointers), name)
+					require.Len(t, pointers, 1, name)
+					require.Equal(t, item, *pointers[0], name)
+					var dynamics []any
+					require.NoError(t, f.Unmarshal(data, &dynamics), name)
+					require.Len(t, dynamics, 1, name)
+					require.Equal(t, item, deref(dynamics[0]), name)
+				}
+
+				holder := &pointerRegistrationHolder{
+					Value:   item,
+					Pointer: &item,
+					Values:  []pointerRegistrationItem{item},
+					Ptrs:    []*pointerRegistrationItem{&item},
+				}
+				data, err = f.Marshal(holder)
+				require.NoError(t, err, name)
+				var decoded pointerRegistrationHolder
+				require.NoError(t, f.Unmarshal(data, &decoded), name)
+				require.Equal(t, *holder, decoded, name)
+			}
+		}
+	}
+}
+
+type renamedRegistrationUser struct {
+	Name string
+}
+
+type renamedRegistrationHolder struct {
+	Users []any
+}
+
+func TestRegisterTypeAlias(t *testing.T) {
+	for _, compatible := range []bool{true, false} {
+		writer := NewFory(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, writer.RegisterStructByName(explicitRegistrationUser{}, "example.User"))
+		require.NoError(t, writer.RegisterStructByName(renamedRegistrationHolder{}, "example.Holder"))
+		data, err := writer.Marshal(&renamedRegistrationHolder{
+			Users: []any{&explicitRegistrationUser{Name: "alice"}},
+		})
+		require.NoError(t, err)
+		stored := append([]byte(nil), data...)
+
+		reader := NewFory(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, reader.RegisterStructByName(renamedRegistrationUser{}, "accounts.User"))
+		require.NoError(t, reader.RegisterStructByName(renamedRegistrationHolder{}, "example.Holder"))
+		require.NoError(t, reader.RegisterTypeAlias("example.User", renamedRegistrationUser{}))
+		var holder renamedRegistrationHolder
+		require.NoError(t, reader.Unmarshal(stored, &holder))
+		require.Equal(t, []any{renamedRegistrationUser{Name: "alice"}}, holder.Users)
+
+		// New payloads are written under the current name.
+		data, err = reader.Marshal(&renamedRegistrationUser{Name: "bob"})
+		require.NoError(t, err)
+		current := NewFory(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, current.RegisterStructByName(renamedRegistrationUser{}, "accounts.User"))
+		var decoded any
+		require.NoError(t, current.Unmarshal(data, &decoded))
+		require.Equal(t, &renamedRegistrationUser{Name: "bob"}, decoded)
+	}
+}
+
+type bulkOrder struct {
+	ID       int64
+	Customer *bulkCustomer
+}
+
+type bulkCustomer struct {
+	Name string
+}
+
+func TestRegisterStructsByName(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStructsByName("shop", bulkOrder{}, reflect.TypeOf(bulkCustomer{})))
+	schemas, err := f.ExportSchemas()
+	require.NoError(t, err)
+	require.Contains(t, string(schemas), `"shop.bulkOrder"`)
+	require.Contains(t, string(schemas), `"shop.bulkCustomer"`)
+
+	order := &bulkOrder{ID: 1, Customer: &bulkCustomer{Name: "ann"}}
+	data, err := f.Marshal(order)
+	require.NoError(t, err)
+	var decoded bulkOrder
+	require.NoError(t, f.Unmarshal(data, &decoded))
+	require.Equal(t, *order, decoded)
+	require.NoError(t, f.Unregister(bulkCustomer{}))
+
+	f = NewFory(WithXlang(true))
+	err = f.RegisterStructsByName("shop", bulkOrder{}, &bulkOrder{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "would both be registered as shop.bulkOrder")
+	require.NoError(t, f.RegisterStruct(bulkCustomer{}, 1))
+	err = f.RegisterStructsByName("shop", bulkOrder{}, bulkCustomer{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already registered with id 1")
+	// Nothing was registered by the failed call.
+	require.NoError(t, f.RegisterStructByName(bulkOrder{}, "shop.bulkOrder"))
+	require.Error(t, f.RegisterStructsByName("shop", 1))
+}
+
+type externalMetric struct {
+	Name   string
+	Count  int
+	Events chan int
+}
+
+func TestWithFieldTag(t *testing.T) {
+	f := NewFory(WithXlang(true), WithCompatible(true))
+	require.Error(t, f.RegisterStruct(externalMetric{}, 1))
+	require.NoError(t, f.RegisterStruct(externalMetric{}, 1,
+		WithFieldTag("Count", "type=int32"), WithFieldTag("Events", "-")))
+
+	metric := &externalMetric{Name: "hits", Count: 3, Events: make(chan int)}
+	data, err := f.Marshal(metric)
+	require.NoError(t, err)
+	dump, err := Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, dump, "count: VARINT32 = 3")
+	require.NotContains(t, dump, "events")
+	var decoded externalMetric
+	require.NoError(t, f.Unmarshal(data, &decoded))
+	require.Equal(t, externalMetric{Name: "hits", Count: 3}, decoded)
+
+	clone := mustClone(t, f)
+	data, err = clone.Marshal(metric)
+	require.NoError(t, err)
+	require.NoError(t, f.Unmarshal(data, &decoded))
+
+	f = NewFory(WithXlang(true))
+	err = f.RegisterStructByName(externalMetric{}, "example.Metric", WithFieldTag("Missing", "-"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "no exported field Missing")
+}
+
+func TestRegisterTypeAliasErrors(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	err := f.RegisterTypeAlias("example.User", renamedRegistrationUser{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be registered by name")
+
+	require.NoError(t, f.RegisterStruct(renamedRegistrationHolder{}, 1))
+	err = f.RegisterTypeAlias("example.Holder", renamedRegistrationHolder{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be registered by name")
+
+	require.NoError(t, f.RegisterStructByName(renamedRegistrationUser{}, "accounts.User"))
+	require.NoError(t, f.RegisterStructByName(explicitRegistrationUser{}, "example.User"))
+	err = f.RegisterTypeAlias("example.User", renamedRegistrationUser{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "already registered")
+	require.NoError(t, f.RegisterTypeAlias("accounts.User", renamedRegistrationUser{}))
+}
+
+func TestAnonymousStructNativeMode(t *testing.T) {
+	type record struct {
+		ID    int64
+		Point struct {
+			X, Y int32
+		}
+		Tags []struct {
+			Key   string
+			Value string
+		}
+	}
+	for _, compatible := range []bool{true, false} {
+		writer := NewFory(WithXlang(false), WithCompatible(compatible))
+		require.NoError(t, writer.RegisterStruct(record{}, 1))
+		value := record{ID: 7}
+		value.Point.X, value.Point.Y = 1, 2
+		value.Tags = append(value.Tags, struct {
+			Key   string
+			Value string
+		}{"k", "v"})
+		data, err := writer.Marshal(&value)
+		require.NoError(t, err)
+
+		reader := NewFory(WithXlang(false), WithCompatible(compatible))
+		require.NoError(t, reader.RegisterStruct(record{}, 1))
+		var result record
+		require.NoError(t, reader.Unmarshal(data, &result))
+		require.Equal(t, value, result)
+
+		literal := struct {
+			Name  string
+			Count int32
+		}{"alice", 3}
+		data, err = writer.Marshal(&literal)
+		require.NoError(t, err)
+		var decoded struct {
+			Name  string
+			Count int32
+		}
+		require.NoError(t, NewFory(WithXlang(false), WithCompatible(compatible)).Unmarshal(data, &decoded))
+		require.Equal(t, literal, decoded)
+	}
+
+	_, err := NewFory(WithXlang(true)).Marshal(&struct{ X int32 }{1})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "must be registered explicitly")
+}
+
+type unsupportedChanField struct {
+	ID     int32
+	Events chan int
+}
+
+type unsupportedNestedFuncField struct {
+	Handlers map[string][]func()
+}
+
+type unsupportedInlineField struct {
+	Meta struct {
+		Ptr unsafe.Pointer
+	}
+}
+
+type ignoredChanField struct {
+	ID     int32
+	Events chan int `fory:"-"`
+}
+
+func TestUnsupportedFieldKindsFailAtRegistration(t *testing.T) {
+	for _, tc := range []struct {
+		value any
+		field string
+		typ   string
+	}{
+		{unsupportedChanField{}, "Events", "chan int"},
+		{unsupportedNestedFuncField{}, "Handlers", "func()"},
+		{unsupportedInlineField{}, "Meta", "unsafe.Pointer"},
+	} {
+		f := NewFory(WithXlang(true))
+		err := f.RegisterStruct(tc.value, 1)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field "+tc.field+": type "+tc.typ+" cannot be serialized")
+		err = f.RegisterStructByName(tc.value, "example.Unsupported")
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "field "+tc.field)
+	}
+
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(ignoredChanField{}, 1))
+	data, err := f.Marshal(&ignoredChanField{ID: 1, Events: make(chan int)})
+	require.NoError(t, err)
+	var result ignoredChanField
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, int32(1), result.ID)
+}
+
+type conflictingFieldNames struct {
+	UserId  string
+	User_id string
+}
+
+type duplicateFieldIDs struct {
+	A int32 `fory:"id=1"`
+	B int32 `fory:"id=1"`
+}
+
+type renamedConflictingField struct {
+	UserId  string
+	User_id string `fory:"id=1"`
+}
+
+func TestConflictingFieldsFailAtRegistration(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	err := f.RegisterStruct(conflictingFieldNames{}, 1)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `fields UserId and User_id both serialize as "user_id"`)
+	err = f.RegisterStructByName(duplicateFieldIDs{}, "example.Duplicate")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "duplicate fory tag id=1")
+	require.NoError(t, f.RegisterStruct(renamedConflictingField{}, 2))
+}
+
+func TestUnsupportedDynamicValuesFail(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	for _, value := range []any{
+		make(chan int),
+		[]any{func() {}},
+		map[string]any{"handler": func() {}},
+	} {
+		_, err := f.Marshal(value)
+		require.Error(t, err)
+		require.Contains(t, err.Error(), "cannot be serialized")
+	}
+}
+
 func TestUnregisteredStructSerializationFails(t *testing.T) {
 	value := explicitRegistrationUser{Name: "bob"}
 
@@ -344,6 +811,123 @@ func TestRegisterById(t *testing.T) {
 	serde(t, fory, simple{Field: "value"})
 }
 
+func TestMarshalAppend(t *testing.T) {
+	fory := NewFory(WithXlang(true), WithCompatible(false))
+	type item struct {
+		Name  string
+		Count int32
+	}
+	require.NoError(t, fory.RegisterStructByName(item{}, "example.item"))
+	value := &item{Name: "apple", Count: 3}
+	want, err := fory.Marshal(value)
+	require.NoError(t, err)
+	want = append([]byte(nil), want...)
+
+	dst := make([]byte, 2, 256)
+	dst[0], dst[1] = 0xAB, 0xCD
+	out, err := fory.MarshalAppend(dst, value)
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0xAB, 0xCD}, want...), out)
+	require.Same(t, &dst[0], &out[0], "MarshalAppend must reuse dst when it has room")
+
+	// The result belongs to the caller and survives later calls.
+	_, err = fory.Marshal(&item{Name: "pear"})
+	require.NoError(t, err)
+	var decoded item
+	require.NoError(t, fory.Unmarshal(out[2:], &decoded))
+	require.Equal(t, *value, decoded)
+
+	grown, err := fory.MarshalAppend(nil, value)
+	require.NoError(t, err)
+	require.Equal(t, want, grown)
+}
+
+func TestMarshalOptions(t *testing.T) {
+	fory := NewFory(WithXlang(true), WithCompatible(false), WithRefTracking(true))
+	type node struct {
+		Name  string
+		Left  *node
+		Right *node
+	}
+	require.NoError(t, fory.RegisterStructByName(node{}, "example.node"))
+	shared := &node{Name: "leaf"}
+	root := &node{Name: "root", Left: shared, Right: shared}
+
+	tracked, err := fory.Marshal(root)
+	require.NoError(t, err)
+	tracked = append([]byte(nil), tracked...)
+	untracked, err := fory.Marshal(root, WithRefTrackingDisabled())
+	require.NoError(t, err)
+	require.Greater(t, len(untracked), len(tracked), "shared node must be written twice")
+
+	var decoded *node
+	require.NoError(t, fory.Unmarshal(untracked, &decoded))
+	require.Equal(t, "leaf", decoded.Left.Name)
+	require.NotSame(t, decoded.Left, decoded.Right)
+
+	// The instance configuration is unchanged by the previous call.
+	again, err := fory.Marshal(root)
+	require.NoError(t, err)
+	require.Equal(t, tracked, again)
+
+	dst := []byte{0xAB}
+	out, err := fory.Marshal(root, WithBuffer(dst))
+	require.NoError(t, err)
+	require.Equal(t, append([]byte{0xAB}, tracked...), out)
+}
+
+func TestMarshalSortedMaps(t *testing.T) {
+	fory := NewFory(WithXlang(true))
+	values := []any{
+		map[string]string{"c": "3", "a": "1", "b": "2", "d": "4", "e": "5"},
+		map[int64]int64{5: 1, 3: 2, 9: 3, 1: 4, 7: 5},
+		map[string][]int32{"z": {1}, "y": {2}, "x": {3}, "w": {4}},
+		map[any]any{"b": int32(1), int32(2): "a", "a": nil, true: 1.5},
+		Set[string]{"q": {}, "w": {}, "e": {}, "r": {}, "t": {}, "y": {}},
+	}
+	for _, value := range values {
+		want, err := fory.Marshal(value, WithSortedMaps())
+		require.NoError(t, err)
+		want = append([]byte(nil), want...)
+		for i := 0; i < 20; i++ {
+			// Rebuild the map so its iteration order is drawn afresh.
+			copied := reflect.MakeMap(reflect.TypeOf(value))
+			iter := reflect.ValueOf(value).MapRange()
+			for iter.Next() {
+				copied.SetMapIndex(iter.Key(), iter.Value())
+			}
+			got, err := fory.Marshal(copied.Interface(), WithSortedMaps())
+			require.NoError(t, err)
+			require.Equal(t, want, got, "%T", value)
+		}
+		decoded := reflect.New(reflect.TypeOf(value))
+		require.NoError(t, fory.Unmarshal(want, decoded.Interface()))
+		require.Equal(t, value, decoded.Elem().Interface())
+	}
+}
+
+func TestSizeOf(t *testing.T) {
+	fory := NewFory(WithXlang(true))
+	values := []any{int32(7), "hello", []int64{1, 2, 3}, map[string]int32{"a": 1}}
+	for _, value := range values {
+		size, err := fory.SizeOf(value)
+		require.NoError(t, err)
+		data, err := fory.Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, len(data), size, "size of %T", value)
+	}
+	// SizeOf does not touch the buffer Marshal results point into.
+	data, err := fory.Marshal("hello")
+	require.NoError(t, err)
+	want := append([]byte(nil), data...)
+	_, err = fory.SizeOf([]int64{4, 5, 6, 7})
+	require.NoError(t, err)
+	require.Equal(t, want, data)
+	type unregistered struct{ A int32 }
+	_, err = fory.SizeOf(&unregistered{})
+	require.Error(t, err)
+}
+
 func TestSerializeBeginWithMagicNumber(t *testing.T) {
 	strSlice := []string{"str1", "str1", "", "", "str2"}
 	fory := NewFory(WithXlang(true), WithCompatible(false), WithRefTracking(true))
@@ -354,6 +938,65 @@ func TestSerializeBeginWithMagicNumber(t *testing.T) {
 	require.True(t, len(bytes) >= 1)
 }
 
+func TestParseHeader(t *testing.T) {
+	xlang := NewFory(WithXlang(true))
+	native := NewFory(WithXlang(false))
+	data, err := xlang.Marshal("hello")
+	require.NoError(t, err)
+
+	header, err := ParseHeader(data[:HeaderSize])
+	require.NoError(t, err)
+	require.Equal(t, Header{Xlang: true}, header)
+	require.Equal(t, xlang.Header(), header)
+	require.NoError(t, xlang.CheckHeader(header))
+	require.Error(t, native.CheckHeader(header))
+
+	data, err = native.Marshal("hello")
+	require.NoError(t, err)
+	header, err = ParseHeader(data)
+	require.NoError(t, err)
+	require.Equal(t, Header{}, header)
+	require.NoError(t, native.CheckHeader(header))
+
+	_, err = ParseHeader(nil)
+	require.Error(t, err)
+	_, err = ParseHeader([]byte{0x80})
+	require.Error(t, err)
+
+	buf := NewByteBuffer(nil)
+	require.NoError(t, native.SerializeWithCallback(buf, []byte("oob"), func(BufferObject) bool { return false }))
+	header, err = ParseHeader(buf.GetByteSlice(0, buf.WriterIndex()))
+	require.NoError(t, err)
+	require.True(t, header.OutOfBand)
+}
+
+func TestWithHeaderDisabled(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		framed := NewFory(WithXlang(xlang))
+		raw := NewFory(WithXlang(xlang), WithHeader(false))
+		require.NoError(t, framed.RegisterStruct(Bar{}, 330))
+		require.NoError(t, raw.RegisterStruct(Bar{}, 330))
+		value := &Bar{F1: 7, F2: "raw"}
+
+		withHeader, err := framed.Marshal(value)
+		require.NoError(t, err)
+		withHeader = append([]byte(nil), withHeader...)
+		data, err := raw.Marshal(value)
+		require.NoError(t, err)
+		require.Equal(t, withHeader[HeaderSize:], data)
+
+		var got Bar
+		require.NoError(t, raw.Unmarshal(data, &got))
+		require.Equal(t, *value, got)
+
+		generic, err := Serialize(raw, value)
+		require.NoError(t, err)
+		var genericGot Bar
+		require.NoError(t, Deserialize(raw, generic, &genericGot))
+		require.Equal(t, *value, genericGot)
+	}
+}
+
 type Foo struct {
 	F1 int32
 	F2 string
@@ -755,6 +1398,9 @@ func TestStructWithNestedSlice(t *testing.T) {
 	require.Equal(t, deserialized2, example)
 }
 
+// convertRecursively converts newVal using tmplVal as a template. Unlike
+// ConvertAssign, interface-typed positions are converted to the dynamic type
+// found at the same position in the template.
 func convertRecursively(newVal, tmplVal reflect.Value) (reflect.Value, error) {
 	// Unwrap any any
 	if newVal.Kind() == reflect.Interface && !newVal.IsNil() {
@@ -863,3 +1509,251 @@ func convertRecursively(newVal, tmplVal reflect.Value) (reflect.Value, error) {
 			fmt.Errorf("cannot convert %s to %s", newVal.Type(), tmplVal.Type())
 	}
 }
+
+type panicPoint struct {
+	X int32
+}
+
+type panicPointSerializer struct{}
+
+func (panicPointSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	x := int32(reflect.Indirect(value).Field(0).Int())
+	if x < 0 {
+		panic("negative point")
+	}
+	ctx.Buffer().WriteInt32(x)
+}
+
+func (panicPointSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	x := ctx.Buffer().ReadInt32(ctx.Err())
+	if x < 0 {
+		var m map[string]int
+		m["x"] = int(x) // nil map write, a runtime panic
+	}
+	reflect.Indirect(value).Field(0).SetInt(int64(x))
+}
+
+func TestPanicRecovery(t *testing.T) {
+	newFory := func(opts ...Option) *Fory {
+		f := New(opts...)
+		require.NoError(t, f.RegisterExtension(panicPoint{}, 1, panicPointSerializer{}))
+		return f
+	}
+	f := newFory()
+
+	_, err := f.Serialize(&panicPoint{X: -1})
+	require.Contains(t, err.Error(), "negative point")
+	require.Equal(t, ErrKindSerializationFailed, err.(Error).Kind())
+	_, err = Serialize(f, &panicPoint{X: -1})
+	require.Contains(t, err.Error(), "negative point")
+
+	buf := NewByteBuffer(nil)
+	require.Error(t, f.SerializeTo(buf, &panicPoint{X: -1}))
+	// The instance stays usable and writes to its own buffer again.
+	data, err := f.Serialize(&panicPoint{X: 3})
+	require.NoError(t, err)
+	var p panicPoint
+	require.NoError(t, f.Deserialize(data, &p))
+	require.Equal(t, int32(3), p.X)
+
+	bad := bytes.Clone(data)
+	copy(bad[len(bad)-4:], []byte{0xff, 0xff, 0xff, 0xff})
+	err = f.Deserialize(bad, &p)
+	require.Contains(t, err.Error(), "nil map")
+	require.Equal(t, ErrKindDeserializationFailed, err.(Error).Kind())
+	require.Error(t, Deserialize(f, bad, &p))
+	require.Error(t, f.DeserializeFrom(NewByteBuffer(bad), &p))
+	require.NoError(t, f.Deserialize(data, &p))
+
+	unrecovered := newFory(WithPanicRecovery(false))
+	require.Panics(t, func() { _, _ = unrecovered.Serialize(&panicPoint{X: -1}) })
+}
+
+type cloneUser struct {
+	Name string
+	Tags []string
+}
+
+func TestClone(t *testing.T) {
+	template := New(WithXlang(true), WithRefTracking(true))
+	require.NoError(t, template.RegisterStructByName(cloneUser{}, "example.User", WithNonReferencable()))
+	require.NoError(t, template.RegisterExtension(panicPoint{}, 1, panicPointSerializer{}))
+	require.NoError(t, template.RegisterTypeAlias("example.OldUser", cloneUser{}))
+	var seen []string
+	require.NoError(t, template.RegisterHooks(cloneUser{}, TypeHooks{
+		AfterDeserialize: func(v any) { seen = append(seen, v.(*cloneUser).Name) },
+	}))
+	require.Error(t, template.RegisterStruct(cloneUser{}, 1))
+
+	c := mustClone(t, template)
+	require.Equal(t, template.config.TrackRef, c.config.TrackRef)
+	user := &cloneUser{Name: "ann", Tags: []string{"a"}}
+	value := []any{user, user, &panicPoint{X: 2}}
+	data, err := c.Serialize(value)
+	require.NoError(t, err)
+	data = bytes.Clone(data)
+	want, err := template.Serialize(value)
+	require.NoError(t, err)
+	require.Equal(t, want, data)
+	var out []any
+	require.NoError(t, c.Deserialize(data, &out))
+	require.Equal(t, []any{*user, *user, panicPoint{X: 2}}, out)
+	require.Equal(t, []string{"ann", "ann"}, seen)
+
+	// Registrations after cloning stay with the instance they were made on.
+	require.NoError(t, c.RegisterEnum(schemaColor(0), 3))
+	_, ok := template.typeResolver.userTypeIdToTypeInfo[3]
+	require.False(t, ok)
+	// Clones of clones carry both sets.
+	_, ok = mustClone(t, c).typeResolver.userTypeIdToTypeInfo[3]
+	require.True(t, ok)
+
+	// A registration that no longer replays fails the clone.
+	c.registrations = append(c.registrations, registration{
+		type_:    reflect.TypeOf(cloneUser{}),
+		register: func(*Fory) error { return errors.New("stale global state") },
+	})
+	clone, err := c.Clone()
+	require.Nil(t, clone)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stale global state")
+}
+
+func mustClone(t *testing.T, f *Fory) *Fory {
+	t.Helper()
+	c, err := f.Clone()
+	require.NoError(t, err)
+	return c
+}
+
+// offsetPointSerializer encodes panicPoint differently from panicPointSerializer.
+type offsetPointSerializer struct{}
+
+func (offsetPointSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	ctx.Buffer().WriteInt32(int32(reflect.Indirect(value).Field(0).Int()) + 1000)
+}
+
+func (offsetPointSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	reflect.Indirect(value).Field(0).SetInt(int64(ctx.Buffer().ReadInt32(ctx.Err()) - 1000))
+}
+
+type pointHolder struct {
+	P      panicPoint
+	Points []panicPoint
+}
+
+func TestReplaceSerializer(t *testing.T) {
+	newFory := func() *Fory {
+		f := New(WithXlang(true))
+		require.NoError(t, f.RegisterExtensionByName(panicPoint{}, "example.Point", panicPointSerializer{}))
+		require.NoError(t, f.RegisterStructByName(pointHolder{}, "example.PointHolder"))
+		return f
+	}
+	f := newFory()
+	holder := &pointHolder{P: panicPoint{X: 1}, Points: []panicPoint{{X: 2}}}
+	before, err := f.Serialize(holder)
+	require.NoError(t, err)
+	before = bytes.Clone(before)
+
+	// The holder's serializer was built before the swap and picks it up too.
+	require.NoError(t, f.ReplaceSerializer(panicPoint{}, offsetPointSerializer{}))
+	after, err := f.Serialize(holder)
+	require.NoError(t, err)
+	require.NotEqual(t, before, after)
+	var out pointHolder
+	require.NoError(t, f.Deserialize(after, &out))
+	require.Equal(t, *holder, out)
+
+	var old pointHolder
+	require.NoError(t, newFory().Deserialize(after, &old))
+	require.Equal(t, pointHolder{P: panicPoint{X: 1001}, Points: []panicPoint{{X: 1002}}}, old)
+
+	// Clones replay the replacement.
+	cloned, err := mustClone(t, f).Serialize(holder)
+	require.NoError(t, err)
+	require.Equal(t, after, cloned)
+
+	require.Error(t, f.ReplaceSerializer(pointHolder{}, offsetPointSerializer{}))
+	require.Error(t, f.ReplaceSerializer(cloneUser{}, offsetPointSerializer{}))
+	require.Error(t, f.ReplaceSerializer(panicPoint{}, nil))
+}
+
+func TestUnregister(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterExtension(panicPoint{}, 1, panicPointSerializer{}))
+	require.NoError(t, f.RegisterStructByName(cloneUser{}, "example.User"))
+	require.NoError(t, f.RegisterTypeAlias("example.OldUser", cloneUser{}))
+	var seen int
+	require.NoError(t, f.RegisterHooks(cloneUser{}, TypeHooks{AfterDeserialize: func(any) { seen++ }}))
+	user := &cloneUser{Name: "ann", Tags: []string{"a"}}
+	data, err := f.Serialize([]any{user, &panicPoint{X: 2}})
+	require.NoError(t, err)
+	data = bytes.Clone(data)
+	var out []any
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, 1, seen)
+
+	require.NoError(t, f.Unregister(cloneUser{}))
+	_, err = f.Serialize(user)
+	require.Error(t, err)
+	require.Error(t, f.Unregister(&cloneUser{}))
+	require.Error(t, f.RegisterTypeAlias("example.OldUser", cloneUser{}))
+
+	// Other registrations are kept, and the type can be registered again.
+	point, err := f.Serialize(&panicPoint{X: 3})
+	require.NoError(t, err)
+	var p panicPoint
+	require.NoError(t, f.Deserialize(point, &p))
+	require.Equal(t, panicPoint{X: 3}, p)
+	require.NoError(t, f.RegisterStruct(cloneUser{}, 2))
+	data, err = f.Serialize(user)
+	require.NoError(t, err)
+	var u cloneUser
+	require.NoError(t, mustClone(t, f).Deserialize(data, &u))
+	require.Equal(t, *user, u)
+	require.Equal(t, 1, seen)
+}
+
+type truncatedRecord struct {
+	ID      int64
+	Name    string
+	Long    string
+	Ratio   float64
+	Tags    []string
+	Counts  map[string]int32
+	Any     any
+	Payload []byte
+	Next    *truncatedRecord
+}
+
+func TestTruncatedPayloads(t *testing.T) {
+	value := &truncatedRecord{
+		ID:      1 << 40,
+		Name:    "record",
+		Long:    strings.Repeat("é", 40),
+		Ratio:   0.5,
+		Tags:    []string{"a", "bb"},
+		Counts:  map[string]int32{"x": 1, "y": 300},
+		Any:     []any{int32(7), "seven"},
+		Payload: []byte{1, 2, 3},
+		Next:    &truncatedRecord{Name: "next"},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, compatible := range []bool{true, false} {
+			f := New(WithXlang(xlang), WithCompatible(compatible), WithTrackRef(true), WithPanicRecovery(false))
+			require.NoError(t, f.RegisterStructByName(truncatedRecord{}, "org.example.payloads.TruncatedRecord"))
+			data, err := f.Marshal(value)
+			require.NoError(t, err)
+			data = append([]byte(nil), data...)
+			for n := 0; n < len(data); n++ {
+				var result truncatedRecord
+				err := f.Unmarshal(data[:n], &result)
+				require.True(t, errors.Is(err, io.ErrUnexpectedEOF),
+					"xlang=%v compatible=%v length=%d: %v", xlang, compatible, n, err)
+				err = f.DeserializeFromReader(bytes.NewReader(data[:n]), &result)
+				require.True(t, errors.Is(err, io.ErrUnexpectedEOF),
+					"stream xlang=%v compatible=%v length=%d: %v", xlang, compatible, n, err)
+			}
+		}
+	}
+}
@@ -0,0 +1,433 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package forycheck defines an analyzer that reports Fory registration
+// mistakes at vet time instead of at run time:
+//
+//   - a struct passed to Marshal, Unmarshal or a related call, or reachable
+//     from its fields, that is never registered
+//   - a struct field of channel, function or unsafe.Pointer type that is not
+//     tagged `fory:"-"`
+//
+// A struct counts as registered when a Register call for it appears in the
+// analyzed package or in a package it imports. Packages that register types
+// through values unknown at compile time, such as reflect.Type variables, are
+// not checked for unregistered structs. Structs from the standard library and
+// from the Fory module are built in and never reported.
+//
+// Run it with go vet:
+//
+//	go install github.com/apache/fory/go/fory/cmd/forycheck@latest
+//	go vet -vettool=$(which forycheck) ./...
+package forycheck
+
+import (
+	"go/ast"
+	"go/types"
+	"reflect"
+	"sort"
+	"strings"
+
+	"golang.org/x/tools/go/analysis"
+	"golang.org/x/tools/go/types/typeutil"
+)
+
+const (
+	foryPath       = "github.com/apache/fory/go/fory"
+	threadsafePath = foryPath + "/threadsafe"
+)
+
+// Analyzer reports unregistered structs and unsupported field kinds.
+var Analyzer = &analysis.Analyzer{
+	Name:      "forycheck",
+	Doc:       "report structs serialized with Fory that are never registered, and fields Fory cannot serialize",
+	URL:       "https://pkg.go.dev/github.com/apache/fory/go/fory/forycheck",
+	Run:       run,
+	FactTypes: []analysis.Fact{new(registrations)},
+}
+
+// registrations is the package fact listing the structs a package registers.
+type registrations struct {
+	Types   []string
+	Custom  []string // Registered with their own serializer
+	Dynamic bool     // Some types are registered through values unknown statically
+}
+
+func (*registrations) AFact() {}
+
+func (r *registrations) String() string {
+	return "registrations(" + strings.Join(r.Types, ", ") + ")"
+}
+
+// valueCalls lists the Fory functions and methods that serialize or
+// deserialize a value. The value is their first argument of type any or of
+// a type parameter.
+var valueCalls = map[string]bool{
+	"Serialize":                      true,
+	"SerializeTo":                    true,
+	"SerializeWithCallback":          true,
+	"Marshal":                        true,
+	"MarshalAppend":                  true,
+	"MarshalParallel":                true,
+	"SizeOf":                         true,
+	"WriteFramed":                    true,
+	"Deserialize":                    true,
+	"DeserializeFrom":                true,
+	"DeserializeWithCallbackBuffers": true,
+	"DeserializeWithBufferProvider":  true,
+	"Unmarshal":                      true,
+	"UnmarshalTo":                    true,
+	"UnmarshalPartial":               true,
+	"UnmarshalProjected":             true,
+	"ReadFramed":                     true,
+}
+
+// checker holds what run learns about a package.
+type checker struct {
+	pass       *analysis.Pass
+	registered map[string]bool
+	custom     map[string]bool
+	dynamic    bool
+	badFields  map[*types.Var]bool
+}
+
+func run(pass *analysis.Pass) (any, error) {
+	if path := pass.Pkg.Path(); path == foryPath || strings.HasPrefix(path, foryPath+"/") {
+		// Fory registers its own types through reflect.Type values, which
+		// would disable the registration check for every importer.
+		return nil, nil
+	}
+	c := &checker{pass: pass, registered: map[string]bool{}, custom: map[string]bool{}, badFields: map[*types.Var]bool{}}
+	var uses []ast.Expr
+	for _, file := range pass.Files {
+		ast.Inspect(file, func(n ast.Node) bool {
+			call, ok := n.(*ast.CallExpr)
+			if !ok {
+				return true
+			}
+			fn := foryCallee(pass, call)
+			switch {
+			case fn == nil:
+			case strings.HasPrefix(fn.Name(), "Register"):
+				c.register(fn, call)
+			case valueCalls[fn.Name()]:
+				if arg := valueArg(fn, call); arg != nil {
+					uses = append(uses, arg)
+				}
+			}
+			return true
+		})
+	}
+
+	fact := &registrations{Types: sortedKeys(c.registered), Custom: sortedKeys(c.custom), Dynamic: c.dynamic}
+	pass.ExportPackageFact(fact)
+	for _, imported := range pass.AllPackageFacts() {
+		if r, ok := imported.Fact.(*registrations); ok {
+			c.dynamic = c.dynamic || r.Dynamic
+			for _, key := range r.Types {
+				c.registered[key] = true
+			}
+			for _, key := range r.Custom {
+				c.custom[key] = true
+			}
+		}
+	}
+
+	for _, arg := range uses {
+		w := walker{checker: c, arg: arg, seen: map[types.Type]bool{}}
+		w.walk(pass.TypesInfo.TypeOf(arg), "")
+	}
+	return nil, nil
+}
+
+// register records the types named by the arguments of a Register call and
+// checks the fields of registered structs.
+func (c *checker) register(fn *types.Func, call *ast.CallExpr) {
+	sig := fn.Type().(*types.Signature)
+	custom := strings.HasPrefix(fn.Name(), "RegisterExtension") || strings.HasPrefix(fn.Name(), "RegisterUnion")
+	for i, arg := range call.Args {
+		if p := paramType(sig, i); !isAny(p) && !isReflectType(p) {
+			continue
+		}
+		t, ok := c.namedType(arg)
+		if !ok {
+			c.dynamic = true
+			continue
+		}
+		if t == nil {
+			continue
+		}
+		key := typeKey(t)
+		c.registered[key] = true
+		if custom {
+			c.custom[key] = true
+			continue
+		}
+		if st, ok := t.Underlying().(*types.Struct); ok {
+			w := walker{checker: c, arg: arg, seen: map[types.Type]bool{}, fieldsOnly: true}
+			w.fields(st, typeName(t))
+		}
+	}
+}
+
+// namedType returns the type an argument of a Register call names. It
+// reports false when the argument may name a type that is not known
+// statically, and a nil type for arguments that name no struct or interface.
+func (c *checker) namedType(arg ast.Expr) (types.Type, bool) {
+	if call, ok := ast.Unparen(arg).(*ast.CallExpr); ok {
+		if fn, ok := typeutil.Callee(c.pass.TypesInfo, call).(*types.Func); ok && fn.Pkg() != nil &&
+			fn.Pkg().Path() == "reflect" && fn.Name() == "TypeOf" && len(call.Args) == 1 {
+			return c.namedType(call.Args[0])
+		}
+	}
+	tv := c.pass.TypesInfo.Types[arg]
+	if tv.Type == nil || tv.IsNil() {
+		return nil, true
+	}
+	t := tv.Type
+	if isReflectType(t) {
+		return nil, false
+	}
+	for {
+		ptr, ok := types.Unalias(t).(*types.Pointer)
+		if !ok {
+			break
+		}
+		t = ptr.Elem()
+	}
+	t = types.Unalias(t)
+	switch t.Underlying().(type) {
+	case *types.Struct:
+		return t, true
+	case *types.Interface:
+		if _, named := t.(*types.Named); named && t != tv.Type {
+			return t, true
+		}
+		return nil, false
+	}
+	return nil, true
+}
+
+// foryCallee returns the Fory function or method a call invokes, or nil.
+func foryCallee(pass *analysis.Pass, call *ast.CallExpr) *types.Func {
+	fn, ok := typeutil.Callee(pass.TypesInfo, call).(*types.Func)
+	if !ok || fn.Pkg() == nil {
+		return nil
+	}
+	if path := fn.Pkg().Path(); path != foryPath && path != threadsafePath {
+		return nil
+	}
+	return fn
+}
+
+// valueArg returns the argument holding the value a call serializes or
+// deserializes into.
+func valueArg(fn *types.Func, call *ast.CallExpr) ast.Expr {
+	sig := fn.Origin().Type().(*types.Signature)
+	for i, arg := range call.Args {
+		t := paramType(sig, i)
+		if ptr, ok := t.(*types.Pointer); ok {
+			t = ptr.Elem()
+		}
+		if _, ok := t.(*types.TypeParam); ok || isAny(t) {
+			return arg
+		}
+	}
+	return nil
+}
+
+func paramType(sig *types.Signature, i int) types.Type {
+	params := sig.Params()
+	if sig.Variadic() && i >= params.Len()-1 {
+		return params.At(params.Len() - 1).Type().(*types.Slice).Elem()
+	}
+	if i >= params.Len() {
+		return nil
+	}
+	return params.At(i).Type()
+}
+
+func isAny(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	iface, ok := types.Unalias(t).(*types.Interface)
+	return ok && iface.Empty()
+}
+
+func isReflectType(t types.Type) bool {
+	if t == nil {
+		return false
+	}
+	named, ok := types.Unalias(t).(*types.Named)
+	return ok && named.Obj().Pkg() != nil && named.Obj().Pkg().Path() == "reflect" && named.Obj().Name() == "Type"
+}
+
+func typeKey(t types.Type) string {
+	return types.TypeString(t, nil)
+}
+
+func typeName(t types.Type) string {
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		return named.Obj().Name()
+	}
+	return ""
+}
+
+func sortedKeys(m map[string]bool) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// builtin reports whether a named struct is handled by Fory without
+// registration: standard library types and types of the Fory module.
+func builtin(named *types.Named) bool {
+	pkg := named.Obj().Pkg()
+	if pkg == nil {
+		return true
+	}
+	path := pkg.Path()
+	first, _, _ := strings.Cut(path, "/")
+	return !strings.Contains(first, ".") || path == foryPath || strings.HasPrefix(path, foryPath+"/")
+}
+
+// walker follows the types reachable from a serialized value the way Fory's
+// struct serializers do, reporting at arg.
+type walker struct {
+	*checker
+	arg        ast.Expr
+	seen       map[types.Type]bool
+	fieldsOnly bool // Report unsupported fields but not unregistered structs
+}
+
+func (w *walker) walk(t types.Type, path string) {
+	if t == nil || w.seen[t] {
+		return
+	}
+	w.seen[t] = true
+	switch u := types.Unalias(t).(type) {
+	case *types.Pointer:
+		w.walk(u.Elem(), path)
+		return
+	case *types.TypeParam:
+		return
+	case *types.Named:
+		st, ok := u.Underlying().(*types.Struct)
+		if !ok {
+			w.walk(u.Underlying(), path)
+			return
+		}
+		if builtin(u) {
+			return
+		}
+		key := typeKey(u)
+		if !w.fieldsOnly && !w.dynamic && !w.registered[key] {
+			where := ""
+			if path != "" {
+				where = " (reached through " + path + ")"
+			}
+			w.pass.ReportRangef(w.arg, "fory: struct %s is never registered%s", key, where)
+		}
+		if !w.custom[key] {
+			w.fields(st, u.Obj().Name())
+		}
+		return
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Slice:
+		w.walk(u.Elem(), path)
+	case *types.Array:
+		w.walk(u.Elem(), path)
+	case *types.Map:
+		w.walk(u.Key(), path)
+		w.walk(u.Elem(), path)
+	case *types.Struct:
+		w.fields(u, path)
+	}
+}
+
+// fields walks the fields of a struct that Fory serializes.
+func (w *walker) fields(st *types.Struct, owner string) {
+	for i := 0; i < st.NumFields(); i++ {
+		field := st.Field(i)
+		if !field.Exported() || reflect.StructTag(st.Tag(i)).Get("fory") == "-" {
+			continue
+		}
+		path := field.Name()
+		if owner != "" {
+			path = owner + "." + path
+		}
+		if bad := unsupported(field.Type(), map[types.Type]bool{}); bad != nil {
+			w.reportField(field, path, bad)
+			continue
+		}
+		w.walk(field.Type(), path)
+	}
+}
+
+// reportField reports an unsupported field once, at its declaration when it
+// is in the analyzed package.
+func (w *walker) reportField(field *types.Var, path string, bad types.Type) {
+	if w.badFields[field] {
+		return
+	}
+	w.badFields[field] = true
+	at := w.arg.Pos()
+	if field.Pkg() == w.pass.Pkg {
+		at = field.Pos()
+	}
+	w.pass.Reportf(at, "fory: field %s has type %s, which cannot be serialized; tag it `fory:\"-\"` to skip it",
+		path, types.TypeString(bad, types.RelativeTo(w.pass.Pkg)))
+}
+
+// unsupported returns the channel, function or unsafe.Pointer type reachable
+// from t without passing through a named struct, which is checked on its own.
+func unsupported(t types.Type, seen map[types.Type]bool) types.Type {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	if named, ok := types.Unalias(t).(*types.Named); ok {
+		if _, isStruct := named.Underlying().(*types.Struct); isStruct {
+			return nil
+		}
+	}
+	switch u := t.Underlying().(type) {
+	case *types.Chan, *types.Signature:
+		return t
+	case *types.Basic:
+		if u.Kind() == types.UnsafePointer {
+			return t
+		}
+	case *types.Pointer:
+		return unsupported(u.Elem(), seen)
+	case *types.Slice:
+		return unsupported(u.Elem(), seen)
+	case *types.Array:
+		return unsupported(u.Elem(), seen)
+	case *types.Map:
+		if bad := unsupported(u.Key(), seen); bad != nil {
+			return bad
+		}
+		return unsupported(u.Elem(), seen)
+	}
+	return nil
+}
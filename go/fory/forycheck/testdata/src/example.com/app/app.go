@@ -0,0 +1,95 @@
+// want package:"registrations\\(example.com/app.Ext, example.com/app.Job, example.com/app.Shape, example.com/app.Tag, example.com/app.User\\)"
+
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package app
+
+import (
+	"reflect"
+	"time"
+	"unsafe"
+
+	"example.com/model"
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+)
+
+type User struct {
+	Name    string
+	Home    *model.Address
+	Tags    map[string][]Tag
+	Created time.Time
+	Parent  *User
+	Any     any
+	hidden  chan int
+}
+
+type Tag struct {
+	Label string
+}
+
+type Order struct {
+	ID int64
+}
+
+type Job struct {
+	Name string
+	Done chan struct{} // want `fory: field Job.Done has type chan struct\{\}, which cannot be serialized; tag it .fory:"-". to skip it`
+	Run  func()        `fory:"-"`
+}
+
+type Handle struct {
+	Ptr unsafe.Pointer // want `fory: field Handle.Ptr has type unsafe.Pointer, which cannot be serialized`
+}
+
+type Wrapper struct {
+	Handles []Handle
+}
+
+type Ext struct {
+	Callback func()
+}
+
+type Shape interface{ Area() float64 }
+
+func register(f *fory.Fory) {
+	f.RegisterStruct(User{}, 1)
+	f.RegisterStructByName(reflect.TypeOf(Tag{}), "example.tag")
+	f.RegisterStruct(&Job{}, 3)
+	f.RegisterExtension(Ext{}, 4, nil)
+	f.RegisterUnion((*Shape)(nil), 5, nil)
+}
+
+func use(f *fory.Fory) {
+	f.Marshal(&User{})
+	f.Marshal(Order{})               // want `fory: struct example.com/app.Order is never registered`
+	f.MarshalAppend(nil, []*Order{}) // want `fory: struct example.com/app.Order is never registered`
+	f.Serialize(map[string]model.Address{})
+	f.Serialize(Ext{})
+	f.Serialize(time.Now())
+	f.Serialize(42)
+
+	var order Order
+	f.Unmarshal(nil, &order)         // want `fory: struct example.com/app.Order is never registered`
+	fory.Deserialize(f, nil, &order) // want `fory: struct example.com/app.Order is never registered`
+	fory.Serialize(f, Wrapper{})     // want `fory: struct example.com/app.Wrapper is never registered` `fory: struct example.com/app.Handle is never registered \(reached through Wrapper.Handles\)`
+	threadsafe.Marshal(&model.Address{})
+
+	var v any = Order{}
+	f.Marshal(v)
+}
@@ -0,0 +1,39 @@
+// want package:"registrations\\(\\)"
+
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package dynamic
+
+import (
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+)
+
+type Event struct {
+	Name string
+}
+
+// Types registered from a reflect.Type variable are unknown statically, so
+// unregistered structs are not reported.
+func setup(f *fory.Fory, types []reflect.Type) {
+	for i, t := range types {
+		f.RegisterStruct(t, uint32(i))
+	}
+	f.Marshal(Event{})
+}
@@ -0,0 +1,48 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package fory is a minimal stand-in for the Fory API used by the
+// forycheck tests.
+package fory
+
+type Fory struct{}
+
+type Serializer interface{}
+
+type ExtensionSerializer interface{}
+
+type MarshalOption func()
+
+func New() *Fory { return &Fory{} }
+
+func (f *Fory) RegisterStruct(type_ any, typeID uint32) error              { return nil }
+func (f *Fory) RegisterStructByName(type_ any, name string) error          { return nil }
+func (f *Fory) RegisterStructsByName(namespace string, types ...any) error { return nil }
+func (f *Fory) RegisterEnum(type_ any, typeID uint32) error                { return nil }
+func (f *Fory) RegisterUnion(type_ any, typeID uint32, s Serializer) error { return nil }
+func (f *Fory) RegisterExtension(type_ any, typeID uint32, s ExtensionSerializer) error {
+	return nil
+}
+
+func (f *Fory) Serialize(value any) ([]byte, error)                  { return nil, nil }
+func (f *Fory) Marshal(v any, opts ...MarshalOption) ([]byte, error) { return nil, nil }
+func (f *Fory) MarshalAppend(dst []byte, v any) ([]byte, error)      { return nil, nil }
+func (f *Fory) Deserialize(data []byte, v any) error                 { return nil }
+func (f *Fory) Unmarshal(data []byte, v any) error                   { return nil }
+
+func Serialize[T any](f *Fory, value T) ([]byte, error)        { return nil, nil }
+func Deserialize[T any](f *Fory, data []byte, target *T) error { return nil }
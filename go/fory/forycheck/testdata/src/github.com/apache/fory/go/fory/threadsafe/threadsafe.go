@@ -0,0 +1,25 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//	http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package threadsafe is a minimal stand-in for the thread-safe Fory API used
+// by the forycheck tests.
+package threadsafe
+
+func RegisterStruct(type_ any, typeID uint32) error { return nil }
+
+func Marshal[T any](value *T) ([]byte, error)       { return nil, nil }
+func Unmarshal[T any](data []byte, target *T) error { return nil }
@@ -0,0 +1,209 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package foryhttp reads and writes Fory payloads in HTTP handlers, so web
+// services can accept and emit application/x-fory alongside JSON.
+//
+//	c := foryhttp.New(newFory)
+//	mux.Handle("/users", c.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+//	    var req CreateUser
+//	    if err := c.Read(r, &req); err != nil {
+//	        http.Error(w, err.Error(), http.StatusBadRequest)
+//	        return
+//	    }
+//	    c.Write(w, r, &User{Name: req.Name})
+//	})))
+//
+// Read and Write pick Fory or JSON from the request's Content-Type and Accept
+// headers. ReadFory and WriteFory always use Fory.
+package foryhttp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+)
+
+// ContentType is the media type of Fory payloads.
+const ContentType = "application/x-fory"
+
+const jsonContentType = "application/json"
+
+// Codec encodes and decodes HTTP bodies with a pooled Fory. It is safe for
+// concurrent use.
+type Codec struct {
+	fory *threadsafe.Fory
+}
+
+// New returns a Codec whose Fory instances are created by factory, which
+// should register every type sent over HTTP. A nil factory creates xlang
+// instances with default options.
+func New(factory func() *fory.Fory) *Codec {
+	if factory == nil {
+		factory = func() *fory.Fory { return fory.New(fory.WithXlang(true)) }
+	}
+	return &Codec{fory: threadsafe.NewWithFactory(factory)}
+}
+
+// WriteFory writes v to w as a Fory payload with status 200. Nothing is
+// written if v cannot be serialized.
+func (c *Codec) WriteFory(w http.ResponseWriter, v any) error {
+	// Fory serializes root structs through pointers.
+	if value := reflect.ValueOf(v); value.Kind() == reflect.Struct {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		v = ptr.Interface()
+	}
+	data, err := c.fory.Serialize(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", ContentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(data)))
+	_, err = w.Write(data)
+	return err
+}
+
+// ReadFory decodes the Fory request body of r into v, which must be a non-nil
+// pointer. It fails if the request declares another content type.
+func (c *Codec) ReadFory(r *http.Request, v any) error {
+	if mediaType := requestType(r); mediaType != "" && mediaType != ContentType {
+		return fmt.Errorf("foryhttp: request content type %q is not %s", mediaType, ContentType)
+	}
+	data, err := io.ReadAll(r.Body)
+	if err != nil {
+		return err
+	}
+	return c.fory.Deserialize(data, v)
+}
+
+// Read decodes the request body of r into v as Fory when the request has the
+// Fory content type, and as JSON otherwise.
+func (c *Codec) Read(r *http.Request, v any) error {
+	if requestType(r) == ContentType {
+		return c.ReadFory(r, v)
+	}
+	return json.NewDecoder(r.Body).Decode(v)
+}
+
+// Write writes v to w as Fory when the client prefers it, and as JSON
+// otherwise. The preference comes from Negotiate if it handled r, or from r's
+// Accept header.
+func (c *Codec) Write(w http.ResponseWriter, r *http.Request, v any) error {
+	mediaType, ok := r.Context().Value(responseTypeKey{}).(string)
+	if !ok {
+		mediaType = responseType(r.Header.Get("Accept"))
+	}
+	if mediaType == ContentType {
+		return c.WriteFory(w, v)
+	}
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	w.Header().Set("Content-Type", jsonContentType)
+	_, err = w.Write(data)
+	return err
+}
+
+type responseTypeKey struct{}
+
+// Negotiate wraps next with content negotiation. Requests whose body is
+// neither Fory nor JSON are rejected with 415 Unsupported Media Type, and
+// requests that accept neither with 406 Not Acceptable. The chosen response
+// type is used by Write.
+func (c *Codec) Negotiate(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if mediaType := requestType(r); mediaType != "" && mediaType != ContentType && mediaType != jsonContentType {
+			http.Error(w, fmt.Sprintf("unsupported content type %q", mediaType), http.StatusUnsupportedMediaType)
+			return
+		}
+		mediaType := responseType(r.Header.Get("Accept"))
+		if mediaType == "" {
+			http.Error(w, "no acceptable content type; use "+ContentType+" or "+jsonContentType, http.StatusNotAcceptable)
+			return
+		}
+		next.ServeHTTP(w, r.WithContext(context.WithValue(r.Context(), responseTypeKey{}, mediaType)))
+	})
+}
+
+// requestType returns the media type of r's body, or "" if it has none.
+func requestType(r *http.Request) string {
+	header := r.Header.Get("Content-Type")
+	if header == "" {
+		return ""
+	}
+	mediaType, _, err := mime.ParseMediaType(header)
+	if err != nil {
+		return header
+	}
+	return mediaType
+}
+
+// responseType picks Fory or JSON from an Accept header, or returns "" if the
+// header accepts neither. Explicit media types override wildcards, and JSON
+// wins ties unless Fory is named, so clients that send */* get JSON.
+func responseType(accept string) string {
+	if strings.TrimSpace(accept) == "" {
+		return jsonContentType
+	}
+	foryQ, jsonQ, wildcardQ := -1.0, -1.0, -1.0
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(part)
+		if err != nil {
+			continue
+		}
+		q := 1.0
+		if value, ok := params["q"]; ok {
+			if q, err = strconv.ParseFloat(value, 64); err != nil {
+				continue
+			}
+		}
+		switch mediaType {
+		case ContentType:
+			foryQ = q
+		case jsonContentType:
+			jsonQ = q
+		case "*/*", "application/*":
+			wildcardQ = max(wildcardQ, q)
+		}
+	}
+	foryExplicit := foryQ >= 0
+	if foryQ < 0 {
+		foryQ = wildcardQ
+	}
+	if jsonQ < 0 {
+		jsonQ = wildcardQ
+	}
+	switch {
+	case foryQ > 0 && (foryQ > jsonQ || (foryQ == jsonQ && foryExplicit)):
+		return ContentType
+	case jsonQ > 0:
+		return jsonContentType
+	default:
+		return ""
+	}
+}
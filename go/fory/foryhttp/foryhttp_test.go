@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package foryhttp
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/require"
+)
+
+type user struct {
+	Name string
+	Age  int32
+}
+
+func newFory() *fory.Fory {
+	f := fory.New(fory.WithXlang(true))
+	if err := f.RegisterStructByName(user{}, "example.User"); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func newHandler(c *Codec) http.Handler {
+	return c.Negotiate(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var in user
+		if err := c.Read(r, &in); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		in.Age++
+		if err := c.Write(w, r, &in); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+		}
+	}))
+}
+
+func TestForyRequestAndResponse(t *testing.T) {
+	c := New(newFory)
+	body, err := c.fory.Serialize(&user{Name: "ann", Age: 30})
+	require.NoError(t, err)
+	req := httptest.NewRequest(http.MethodPost, "/users", bytes.NewReader(body))
+	req.Header.Set("Content-Type", ContentType)
+	req.Header.Set("Accept", ContentType)
+	rec := httptest.NewRecorder()
+	newHandler(c).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, ContentType, rec.Header().Get("Content-Type"))
+	var out user
+	require.NoError(t, c.fory.Deserialize(rec.Body.Bytes(), &out))
+	require.Equal(t, user{Name: "ann", Age: 31}, out)
+}
+
+func TestJSONAlongsideFory(t *testing.T) {
+	c := New(newFory)
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader(`{"Name":"bob","Age":20}`))
+	req.Header.Set("Content-Type", "application/json; charset=utf-8")
+	req.Header.Set("Accept", "*/*")
+	rec := httptest.NewRecorder()
+	newHandler(c).ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Equal(t, jsonContentType, rec.Header().Get("Content-Type"))
+	var out user
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &out))
+	require.Equal(t, user{Name: "bob", Age: 21}, out)
+}
+
+func TestNegotiateRejects(t *testing.T) {
+	c := New(newFory)
+	req := httptest.NewRequest(http.MethodPost, "/users", strings.NewReader("<user/>"))
+	req.Header.Set("Content-Type", "application/xml")
+	rec := httptest.NewRecorder()
+	newHandler(c).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusUnsupportedMediaType, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/users", nil)
+	req.Header.Set("Accept", "text/html")
+	rec = httptest.NewRecorder()
+	newHandler(c).ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotAcceptable, rec.Code)
+}
+
+func TestResponseType(t *testing.T) {
+	cases := map[string]string{
+		"":                                      jsonContentType,
+		"*/*":                                   jsonContentType,
+		ContentType:                             ContentType,
+		"application/json, application/x-fory":  ContentType,
+		"application/x-fory;q=0.5, */*":         jsonContentType,
+		"application/json;q=0.2, */*;q=0.8":     ContentType,
+		"application/x-fory;q=0, application/*": jsonContentType,
+		"text/html, application/json;q=0":       "",
+	}
+	for accept, want := range cases {
+		require.Equal(t, want, responseType(accept), accept)
+	}
+}
@@ -0,0 +1,180 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package forykafka implements the topic-aware Serialize and Deserialize shape
+// used by Kafka client serdes, so Go producers and consumers can exchange Fory
+// messages with each other and with Java services using Fory.
+//
+// Message values are xlang Fory payloads by default. With WithSchemaHeader the
+// payload is preceded by the Confluent wire format header: a zero magic byte
+// and a big-endian 4-byte schema ID, for pipelines that route or audit
+// messages by schema ID.
+//
+// Serializers and deserializers are safe for concurrent use.
+package forykafka
+
+import (
+	"encoding/binary"
+	"fmt"
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+)
+
+const (
+	magicByte  = 0
+	headerSize = 5
+)
+
+// Option configures a Serializer or Deserializer.
+type Option func(*config)
+
+type config struct {
+	schemaHeader bool
+	schemaID     func(topic string) uint32
+}
+
+// WithSchemaHeader adds the Confluent wire format header to payloads.
+// Serializers write the schema ID that schemaID returns for the topic.
+// Deserializers check the magic byte and skip the header; they ignore
+// schemaID, which may be nil.
+func WithSchemaHeader(schemaID func(topic string) uint32) Option {
+	return func(c *config) {
+		c.schemaHeader = true
+		c.schemaID = schemaID
+	}
+}
+
+func newConfig(opts []Option) config {
+	var c config
+	for _, opt := range opts {
+		opt(&c)
+	}
+	return c
+}
+
+func newPool(factory func() *fory.Fory) *threadsafe.Fory {
+	if factory == nil {
+		factory = func() *fory.Fory { return fory.New(fory.WithXlang(true)) }
+	}
+	return threadsafe.NewWithFactory(factory)
+}
+
+// Serializer encodes message keys or values.
+type Serializer struct {
+	fory   *threadsafe.Fory
+	config config
+}
+
+// NewSerializer returns a Serializer whose Fory instances are created by
+// factory, which should register every message type. A nil factory creates
+// xlang instances with default options.
+func NewSerializer(factory func() *fory.Fory, opts ...Option) *Serializer {
+	return &Serializer{fory: newPool(factory), config: newConfig(opts)}
+}
+
+// Serialize encodes msg for topic. A nil msg encodes as nil, which Kafka
+// treats as a tombstone.
+func (s *Serializer) Serialize(topic string, msg any) ([]byte, error) {
+	if msg == nil {
+		return nil, nil
+	}
+	// Fory serializes root structs through pointers.
+	if value := reflect.ValueOf(msg); value.Kind() == reflect.Struct {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		msg = ptr.Interface()
+	}
+	if !s.config.schemaHeader {
+		return s.fory.Serialize(msg)
+	}
+	var id uint32
+	if s.config.schemaID != nil {
+		id = s.config.schemaID(topic)
+	}
+	dst := make([]byte, headerSize, 64)
+	dst[0] = magicByte
+	binary.BigEndian.PutUint32(dst[1:], id)
+	return s.fory.MarshalAppend(dst, msg)
+}
+
+// Close releases resources held by the Serializer. It exists to match Kafka
+// serde interfaces and does nothing.
+func (s *Serializer) Close() error {
+	return nil
+}
+
+// Deserializer decodes message keys or values.
+type Deserializer struct {
+	fory   *threadsafe.Fory
+	config config
+}
+
+// NewDeserializer returns a Deserializer whose Fory instances are created by
+// factory, which should register every message type. A nil factory creates
+// xlang instances with default options.
+func NewDeserializer(factory func() *fory.Fory, opts ...Option) *Deserializer {
+	return &Deserializer{fory: newPool(factory), config: newConfig(opts)}
+}
+
+// Deserialize decodes a payload from topic into a value of its registered
+// type; structs are returned as pointers. A nil payload decodes as nil.
+func (d *Deserializer) Deserialize(topic string, payload []byte) (any, error) {
+	if payload == nil {
+		return nil, nil
+	}
+	var msg any
+	if err := d.DeserializeInto(topic, payload, &msg); err != nil {
+		return nil, err
+	}
+	return msg, nil
+}
+
+// DeserializeInto decodes a payload from topic into msg, which must be a
+// non-nil pointer. A nil payload leaves msg unchanged.
+func (d *Deserializer) DeserializeInto(topic string, payload []byte, msg any) error {
+	if payload == nil {
+		return nil
+	}
+	if d.config.schemaHeader {
+		if len(payload) < headerSize || payload[0] != magicByte {
+			return fmt.Errorf("forykafka: message from topic %q has no schema header", topic)
+		}
+		payload = payload[headerSize:]
+	}
+	if err := d.fory.Deserialize(payload, msg); err != nil {
+		return fmt.Errorf("forykafka: message from topic %q: %w", topic, err)
+	}
+	return nil
+}
+
+// Close releases resources held by the Deserializer. It exists to match Kafka
+// serde interfaces and does nothing.
+func (d *Deserializer) Close() error {
+	return nil
+}
+
+// SchemaID returns the schema ID in the Confluent wire format header of a
+// payload written with WithSchemaHeader, and false if payload is too short or
+// has the wrong magic byte.
+func SchemaID(payload []byte) (uint32, bool) {
+	if len(payload) < headerSize || payload[0] != magicByte {
+		return 0, false
+	}
+	return binary.BigEndian.Uint32(payload[1:headerSize]), true
+}
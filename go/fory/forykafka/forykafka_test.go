@@ -0,0 +1,85 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forykafka
+
+import (
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/require"
+)
+
+type order struct {
+	ID    int64
+	Items []string
+}
+
+func newFory() *fory.Fory {
+	f := fory.New(fory.WithXlang(true))
+	if err := f.RegisterStructByName(order{}, "example.Order"); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestSerializeDeserialize(t *testing.T) {
+	s := NewSerializer(newFory)
+	d := NewDeserializer(newFory)
+	in := order{ID: 7, Items: []string{"book"}}
+
+	payload, err := s.Serialize("orders", in)
+	require.NoError(t, err)
+	msg, err := d.Deserialize("orders", payload)
+	require.NoError(t, err)
+	require.Equal(t, &in, msg)
+
+	var out order
+	require.NoError(t, d.DeserializeInto("orders", payload, &out))
+	require.Equal(t, in, out)
+
+	// Tombstones pass through as nil.
+	payload, err = s.Serialize("orders", nil)
+	require.NoError(t, err)
+	require.Nil(t, payload)
+	msg, err = d.Deserialize("orders", nil)
+	require.NoError(t, err)
+	require.Nil(t, msg)
+}
+
+func TestSchemaHeader(t *testing.T) {
+	schemaIDs := map[string]uint32{"orders": 42}
+	s := NewSerializer(newFory, WithSchemaHeader(func(topic string) uint32 { return schemaIDs[topic] }))
+	d := NewDeserializer(newFory, WithSchemaHeader(nil))
+	in := &order{ID: 1, Items: []string{"pen"}}
+
+	payload, err := s.Serialize("orders", in)
+	require.NoError(t, err)
+	id, ok := SchemaID(payload)
+	require.True(t, ok)
+	require.Equal(t, uint32(42), id)
+
+	var out order
+	require.NoError(t, d.DeserializeInto("orders", payload, &out))
+	require.Equal(t, *in, out)
+
+	plain, err := NewSerializer(newFory).Serialize("orders", in)
+	require.NoError(t, err)
+	err = d.DeserializeInto("orders", plain[:3], &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `topic "orders"`)
+}
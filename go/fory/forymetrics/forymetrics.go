@@ -0,0 +1,192 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package forymetrics exports the measurements of fory.WithMetrics through
+// expvar or Prometheus.
+//
+// The Prometheus adapter is written against the method sets of the client
+// library's vectors, so this package does not depend on it:
+//
+//	bytes := prometheus.NewCounterVec(prometheus.CounterOpts{Name: "fory_bytes_total"}, []string{"op"})
+//	latency := prometheus.NewHistogramVec(prometheus.HistogramOpts{Name: "fory_seconds"}, []string{"op"})
+//	prometheus.MustRegister(bytes, latency)
+//	f := fory.New(fory.WithMetrics(&forymetrics.Prometheus[prometheus.Counter, prometheus.Observer]{
+//	    Bytes:   bytes,
+//	    Latency: latency,
+//	}))
+//
+// Both adapters are safe for concurrent use.
+package forymetrics
+
+import (
+	"expvar"
+	"reflect"
+	"time"
+
+	"github.com/apache/fory/go/fory"
+)
+
+// Operation label values.
+const (
+	Serialize   = "serialize"
+	Deserialize = "deserialize"
+)
+
+func typeName(t reflect.Type) string {
+	if t == nil {
+		return "nil"
+	}
+	return t.String()
+}
+
+// Expvar publishes measurements as an expvar.Map with these keys:
+//
+//	serialize_calls, deserialize_calls      calls
+//	serialize_errors, deserialize_errors    failed calls
+//	serialize_bytes, deserialize_bytes      payload bytes
+//	serialize_ns, deserialize_ns            total call time in nanoseconds
+//	serialize_types, deserialize_types      calls per Go type, as a map
+//	typedef_cache_hits, typedef_cache_misses
+type Expvar struct {
+	fory.NopMetrics
+	calls, errors, bytes, nanos [2]*expvar.Int
+	types                       [2]*expvar.Map
+	hits, misses                *expvar.Int
+}
+
+// NewExpvar publishes a new map under name. Like expvar.NewMap, it panics if
+// name is already in use.
+func NewExpvar(name string) *Expvar {
+	m := expvar.NewMap(name)
+	e := &Expvar{hits: new(expvar.Int), misses: new(expvar.Int)}
+	for i, op := range [2]string{Serialize, Deserialize} {
+		e.calls[i], e.errors[i] = new(expvar.Int), new(expvar.Int)
+		e.bytes[i], e.nanos[i] = new(expvar.Int), new(expvar.Int)
+		e.types[i] = new(expvar.Map).Init()
+		m.Set(op+"_calls", e.calls[i])
+		m.Set(op+"_errors", e.errors[i])
+		m.Set(op+"_bytes", e.bytes[i])
+		m.Set(op+"_ns", e.nanos[i])
+		m.Set(op+"_types", e.types[i])
+	}
+	m.Set("typedef_cache_hits", e.hits)
+	m.Set("typedef_cache_misses", e.misses)
+	return e
+}
+
+func (e *Expvar) record(op int, t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	e.calls[op].Add(1)
+	if err != nil {
+		e.errors[op].Add(1)
+	}
+	e.bytes[op].Add(int64(bytes))
+	e.nanos[op].Add(int64(elapsed))
+	e.types[op].Add(typeName(t), 1)
+}
+
+func (e *Expvar) Serialized(t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	e.record(0, t, bytes, elapsed, err)
+}
+
+func (e *Expvar) Deserialized(t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	e.record(1, t, bytes, elapsed, err)
+}
+
+func (e *Expvar) TypeDefLookup(hit bool) {
+	if hit {
+		e.hits.Add(1)
+	} else {
+		e.misses.Add(1)
+	}
+}
+
+// Counter is the method set of prometheus.Counter used by the adapter.
+type Counter interface {
+	Add(float64)
+}
+
+// Observer is the method set of prometheus.Observer used by the adapter.
+type Observer interface {
+	Observe(float64)
+}
+
+// CounterVec is the method set of *prometheus.CounterVec used by the adapter.
+type CounterVec[C Counter] interface {
+	WithLabelValues(lvs ...string) C
+}
+
+// ObserverVec is the method set of *prometheus.HistogramVec and
+// *prometheus.SummaryVec used by the adapter.
+type ObserverVec[O Observer] interface {
+	WithLabelValues(lvs ...string) O
+}
+
+// Prometheus updates Prometheus vectors. The op label is Serialize or
+// Deserialize. Nil vectors are skipped; the caller creates and registers the
+// others with the labels listed.
+type Prometheus[C Counter, O Observer] struct {
+	fory.NopMetrics
+	// Calls counts calls, labelled by op and outcome ("ok" or "error").
+	Calls CounterVec[C]
+	// Bytes counts payload bytes, labelled by op.
+	Bytes CounterVec[C]
+	// Types counts calls, labelled by op and the Go type of the root value.
+	Types CounterVec[C]
+	// Latency observes call durations in seconds, labelled by op.
+	Latency ObserverVec[O]
+	// TypeDefCache counts TypeDef cache lookups, labelled by result ("hit"
+	// or "miss").
+	TypeDefCache CounterVec[C]
+}
+
+func (p *Prometheus[C, O]) record(op string, t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	if p.Calls != nil {
+		outcome := "ok"
+		if err != nil {
+			outcome = "error"
+		}
+		p.Calls.WithLabelValues(op, outcome).Add(1)
+	}
+	if p.Bytes != nil {
+		p.Bytes.WithLabelValues(op).Add(float64(bytes))
+	}
+	if p.Types != nil {
+		p.Types.WithLabelValues(op, typeName(t)).Add(1)
+	}
+	if p.Latency != nil {
+		p.Latency.WithLabelValues(op).Observe(elapsed.Seconds())
+	}
+}
+
+func (p *Prometheus[C, O]) Serialized(t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	p.record(Serialize, t, bytes, elapsed, err)
+}
+
+func (p *Prometheus[C, O]) Deserialized(t reflect.Type, bytes int, elapsed time.Duration, err error) {
+	p.record(Deserialize, t, bytes, elapsed, err)
+}
+
+func (p *Prometheus[C, O]) TypeDefLookup(hit bool) {
+	if p.TypeDefCache == nil {
+		return
+	}
+	if hit {
+		p.TypeDefCache.WithLabelValues("hit").Add(1)
+	} else {
+		p.TypeDefCache.WithLabelValues("miss").Add(1)
+	}
+}
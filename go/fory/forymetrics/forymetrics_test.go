@@ -0,0 +1,143 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package forymetrics
+
+import (
+	"expvar"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/require"
+)
+
+type point struct {
+	X, Y int32
+}
+
+func newFory(t *testing.T, m fory.Metrics) *fory.Fory {
+	f := fory.New(fory.WithMetrics(m))
+	require.NoError(t, f.RegisterStructByName(point{}, "example.Point"))
+	return f
+}
+
+func TestExpvar(t *testing.T) {
+	e := NewExpvar("fory_test")
+	writer := newFory(t, e)
+	data, err := writer.Serialize(&point{X: 1, Y: 2})
+	require.NoError(t, err)
+	size := len(data)
+
+	reader := newFory(t, e)
+	for i := 0; i < 2; i++ {
+		var p point
+		require.NoError(t, reader.Deserialize(data, &p))
+	}
+	var s string
+	require.Error(t, reader.Deserialize(data, &s))
+
+	m := expvar.Get("fory_test").(*expvar.Map)
+	get := func(key string) int64 { return m.Get(key).(*expvar.Int).Value() }
+	require.Equal(t, int64(1), get("serialize_calls"))
+	require.Equal(t, int64(size), get("serialize_bytes"))
+	require.Equal(t, int64(3), get("deserialize_calls"))
+	require.Equal(t, int64(1), get("deserialize_errors"))
+	require.Equal(t, int64(3*size), get("deserialize_bytes"))
+	require.Positive(t, get("serialize_ns"))
+	require.Equal(t, int64(1), get("typedef_cache_misses"))
+	require.Equal(t, int64(1), get("typedef_cache_hits"))
+	types := m.Get("deserialize_types").(*expvar.Map)
+	require.Equal(t, int64(2), types.Get("forymetrics.point").(*expvar.Int).Value())
+	require.Equal(t, "1", m.Get("serialize_types").(*expvar.Map).Get("*forymetrics.point").String())
+}
+
+type counter struct {
+	mu    *sync.Mutex
+	value *float64
+}
+
+func (c counter) Add(v float64) {
+	c.mu.Lock()
+	*c.value += v
+	c.mu.Unlock()
+}
+
+func (c counter) Observe(v float64) { c.Add(v) }
+
+// vec stands in for a Prometheus vector, keyed by the joined label values.
+type vec struct {
+	mu     sync.Mutex
+	values map[string]*float64
+}
+
+func (v *vec) WithLabelValues(lvs ...string) counter {
+	v.mu.Lock()
+	defer v.mu.Unlock()
+	if v.values == nil {
+		v.values = map[string]*float64{}
+	}
+	key := strings.Join(lvs, ",")
+	if v.values[key] == nil {
+		v.values[key] = new(float64)
+	}
+	return counter{mu: &v.mu, value: v.values[key]}
+}
+
+func (v *vec) get(lvs ...string) float64 {
+	if p := v.values[strings.Join(lvs, ",")]; p != nil {
+		return *p
+	}
+	return 0
+}
+
+func TestPrometheus(t *testing.T) {
+	calls, bytes, types, latency, cache := &vec{}, &vec{}, &vec{}, &vec{}, &vec{}
+	p := &Prometheus[counter, counter]{
+		Calls:        calls,
+		Bytes:        bytes,
+		Types:        types,
+		Latency:      latency,
+		TypeDefCache: cache,
+	}
+	f := newFory(t, p)
+	data, err := f.Serialize(&point{X: 1})
+	require.NoError(t, err)
+	size := float64(len(data))
+	var out point
+	require.NoError(t, f.Deserialize(data, &out))
+	require.NoError(t, f.Deserialize(data, &out))
+	var s string
+	require.Error(t, f.Deserialize(data, &s))
+
+	require.Equal(t, 1.0, calls.get(Serialize, "ok"))
+	require.Equal(t, 2.0, calls.get(Deserialize, "ok"))
+	require.Equal(t, 1.0, calls.get(Deserialize, "error"))
+	require.Equal(t, size, bytes.get(Serialize))
+	require.Equal(t, 3*size, bytes.get(Deserialize))
+	require.Equal(t, 1.0, types.get(Serialize, "*forymetrics.point"))
+	require.Equal(t, 2.0, types.get(Deserialize, "forymetrics.point"))
+	require.Equal(t, 1.0, cache.get("miss"))
+	require.Equal(t, 1.0, cache.get("hit"))
+	require.Positive(t, latency.get(Serialize))
+
+	// Vectors left nil are skipped.
+	f = newFory(t, &Prometheus[counter, counter]{})
+	_, err = f.Serialize(&point{})
+	require.NoError(t, err)
+}
@@ -0,0 +1,306 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package foryquick property-tests Fory schemas: it serializes random values
+// of the given types and fails the test when one does not read back equal.
+//
+//	func TestOrderRoundTrip(t *testing.T) {
+//	    foryquick.Check(t, newFory(), Order{})
+//	}
+//
+// Values are generated field by field. Unexported fields, fields tagged
+// `fory:"-"` and interface fields are left zero; types that implement
+// testing/quick's Generator generate themselves, which is how values for
+// interface fields or invariants between fields are supplied. Nil and empty
+// slices and maps compare equal, as do times at the same instant.
+package foryquick
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"strings"
+	"testing"
+	"testing/quick"
+	"time"
+)
+
+// Codec is the part of fory.Fory and threadsafe.Fory that Check uses.
+type Codec interface {
+	Serialize(v any) ([]byte, error)
+	Deserialize(data []byte, v any) error
+}
+
+const (
+	defaultMaxCount = 100
+	maxLen          = 6
+	maxDepth        = 5
+)
+
+var (
+	timeType      = reflect.TypeOf(time.Time{})
+	generatorType = reflect.TypeOf((*quick.Generator)(nil)).Elem()
+)
+
+// Check round-trips 100 random values of the type of each sample, given as
+// an instance or a reflect.Type, through c.
+func Check(t testing.TB, c Codec, samples ...any) {
+	t.Helper()
+	CheckConfig(t, c, nil, samples...)
+}
+
+// CheckConfig is Check with the number of values and the random source taken
+// from cfg's MaxCount, MaxCountScale and Rand. Without a Rand the source is
+// seeded from the clock and the seed is reported with failures.
+func CheckConfig(t testing.TB, c Codec, cfg *quick.Config, samples ...any) {
+	t.Helper()
+	if cfg == nil {
+		cfg = &quick.Config{}
+	}
+	count := defaultMaxCount
+	if cfg.MaxCount > 0 {
+		count = cfg.MaxCount
+	} else if cfg.MaxCountScale > 0 {
+		count = int(cfg.MaxCountScale * defaultMaxCount)
+	}
+	r, seedNote := cfg.Rand, ""
+	if r == nil {
+		seed := time.Now().UnixNano()
+		r = rand.New(rand.NewSource(seed))
+		seedNote = fmt.Sprintf(" (seed %d)", seed)
+	}
+	g := generator{r: r}
+	for _, sample := range samples {
+		type_, ok := sample.(reflect.Type)
+		if !ok {
+			type_ = reflect.TypeOf(sample)
+		}
+		if type_ == nil {
+			t.Errorf("foryquick: cannot generate values for nil")
+			continue
+		}
+		if type_.Kind() == reflect.Ptr {
+			type_ = type_.Elem()
+		}
+		for i := 0; i < count; i++ {
+			value := g.value(type_, 0)
+			if err := roundTrip(c, value); err != nil {
+				t.Errorf("foryquick: %v value %d%s: %v\nvalue: %+v", type_, i, seedNote, err, value.Interface())
+				break
+			}
+		}
+	}
+}
+
+func roundTrip(c Codec, value reflect.Value) error {
+	in := value.Interface()
+	if value.Kind() == reflect.Struct {
+		// Fory serializes root structs through pointers.
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		in = ptr.Interface()
+	}
+	data, err := c.Serialize(in)
+	if err != nil {
+		return fmt.Errorf("serialize: %w", err)
+	}
+	out := reflect.New(value.Type())
+	if err := c.Deserialize(data, out.Interface()); err != nil {
+		return fmt.Errorf("deserialize: %w", err)
+	}
+	if !equal(value, out.Elem()) {
+		return fmt.Errorf("read back %+v", out.Elem().Interface())
+	}
+	return nil
+}
+
+type generator struct {
+	r *rand.Rand
+}
+
+func (g generator) value(t reflect.Type, depth int) reflect.Value {
+	if t.Implements(generatorType) {
+		return reflect.Zero(t).Interface().(quick.Generator).Generate(g.r, maxLen)
+	}
+	if reflect.PointerTo(t).Implements(generatorType) {
+		return reflect.New(t).Interface().(quick.Generator).Generate(g.r, maxLen)
+	}
+	v := reflect.New(t).Elem()
+	switch t.Kind() {
+	case reflect.Bool:
+		v.SetBool(g.r.Intn(2) == 1)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		v.SetInt(int64(g.r.Uint64()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		v.SetUint(g.r.Uint64())
+	case reflect.Float32, reflect.Float64:
+		v.SetFloat(g.r.NormFloat64() * 1e6)
+	case reflect.String:
+		v.SetString(g.string())
+	case reflect.Slice:
+		n := g.length(depth)
+		if n == 0 && g.r.Intn(2) == 0 {
+			break
+		}
+		v.Set(reflect.MakeSlice(t, n, n))
+		for i := 0; i < n; i++ {
+			v.Index(i).Set(g.value(t.Elem(), depth+1))
+		}
+	case reflect.Array:
+		for i := 0; i < t.Len(); i++ {
+			v.Index(i).Set(g.value(t.Elem(), depth+1))
+		}
+	case reflect.Map:
+		n := g.length(depth)
+		if n == 0 && g.r.Intn(2) == 0 {
+			break
+		}
+		v.Set(reflect.MakeMapWithSize(t, n))
+		for i := 0; i < n; i++ {
+			v.SetMapIndex(g.value(t.Key(), depth+1), g.value(t.Elem(), depth+1))
+		}
+	case reflect.Ptr:
+		if depth < maxDepth && g.r.Intn(4) != 0 {
+			v.Set(reflect.New(t.Elem()))
+			v.Elem().Set(g.value(t.Elem(), depth+1))
+		}
+	case reflect.Struct:
+		if t == timeType {
+			v.Set(reflect.ValueOf(time.Unix(g.r.Int63n(1<<34)-1<<33, g.r.Int63n(1e9))))
+			break
+		}
+		for i := 0; i < t.NumField(); i++ {
+			if serialized(t.Field(i)) {
+				v.Field(i).Set(g.value(t.Field(i).Type, depth+1))
+			}
+		}
+	}
+	// Interfaces, channels, functions, complex numbers and unsafe pointers
+	// stay zero.
+	return v
+}
+
+func (g generator) length(depth int) int {
+	if depth >= maxDepth {
+		return 0
+	}
+	return g.r.Intn(maxLen + 1)
+}
+
+// string mixes ASCII, Latin-1, CJK and astral runes so every string
+// encoding is exercised.
+func (g generator) string() string {
+	runes := make([]rune, g.r.Intn(maxLen*4))
+	for i := range runes {
+		switch g.r.Intn(4) {
+		case 0:
+			runes[i] = rune(0x20 + g.r.Intn(0x5f))
+		case 1:
+			runes[i] = rune(0xa0 + g.r.Intn(0x60))
+		case 2:
+			runes[i] = rune(0x4e00 + g.r.Intn(0x5200))
+		default:
+			runes[i] = rune(0x1f600 + g.r.Intn(0x50))
+		}
+	}
+	return string(runes)
+}
+
+// serialized reports whether Fory writes field: it is exported and not
+// tagged to be ignored.
+func serialized(field reflect.StructField) bool {
+	if !field.IsExported() {
+		return false
+	}
+	tag := field.Tag.Get("fory")
+	if tag == "-" {
+		return false
+	}
+	for _, option := range strings.Split(tag, ",") {
+		if option = strings.TrimSpace(option); option == "ignore" || option == "ignore=true" {
+			return false
+		}
+	}
+	return true
+}
+
+func equal(a, b reflect.Value) bool {
+	if a.Type() != b.Type() {
+		return false
+	}
+	switch a.Kind() {
+	case reflect.Slice:
+		if a.Len() != b.Len() {
+			return false
+		}
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if !equal(a.Index(i), b.Index(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Map:
+		if a.Len() != b.Len() {
+			return false
+		}
+		iter := a.MapRange()
+		for iter.Next() {
+			other := b.MapIndex(iter.Key())
+			if !other.IsValid() || !equal(iter.Value(), other) {
+				return false
+			}
+		}
+		return true
+	case reflect.Ptr, reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return a.IsNil() == b.IsNil()
+		}
+		return equal(a.Elem(), b.Elem())
+	case reflect.Struct:
+		if a.Type() == timeType {
+			return a.Interface().(time.Time).Equal(b.Interface().(time.Time))
+		}
+		for i := 0; i < a.NumField(); i++ {
+			if serialized(a.Type().Field(i)) && !equal(a.Field(i), b.Field(i)) {
+				return false
+			}
+		}
+		return true
+	case reflect.Float32, reflect.Float64:
+		x, y := a.Float(), b.Float()
+		return x == y || (x != x && y != y)
+	case reflect.Bool:
+		return a.Bool() == b.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return a.Int() == b.Int()
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return a.Uint() == b.Uint()
+	case reflect.String:
+		return a.String() == b.String()
+	case reflect.Complex64, reflect.Complex128:
+		return a.Complex() == b.Complex()
+	default:
+		return a.IsZero() && b.IsZero()
+	}
+}
@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package foryquick
+
+import (
+	"fmt"
+	"math/rand"
+	"reflect"
+	"testing"
+	"testing/quick"
+	"time"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/apache/fory/go/fory/threadsafe"
+	"github.com/stretchr/testify/require"
+)
+
+type line struct {
+	SKU   string
+	Qty   int32
+	Price float64
+}
+
+type order struct {
+	ID       int64
+	Placed   time.Time
+	Lines    []line
+	Tags     map[string]int64
+	Parent   *order
+	Flags    [3]bool
+	Note     string `fory:"-"`
+	internal int
+}
+
+// even only holds even numbers, so it generates itself.
+type even struct {
+	N int32
+}
+
+func (even) Generate(r *rand.Rand, size int) reflect.Value {
+	return reflect.ValueOf(even{N: int32(r.Intn(1000) * 2)})
+}
+
+func newFory() *fory.Fory {
+	f := fory.New(fory.WithXlang(true))
+	if err := f.RegisterStructsByName("example", order{}, line{}, even{}); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func TestCheck(t *testing.T) {
+	Check(t, newFory(), order{}, reflect.TypeOf(&line{}), even{}, []string{}, map[int32]float32{})
+	Check(t, threadsafe.NewWithFactory(newFory), &order{})
+}
+
+// recorder captures failures instead of failing the test.
+type recorder struct {
+	testing.TB
+	errors []string
+}
+
+func (r *recorder) Helper() {}
+
+func (r *recorder) Errorf(format string, args ...any) {
+	r.errors = append(r.errors, fmt.Sprintf(format, args...))
+}
+
+// lossy drops the lines of every order it reads.
+type lossy struct {
+	*fory.Fory
+}
+
+func (l lossy) Deserialize(data []byte, v any) error {
+	if err := l.Fory.Deserialize(data, v); err != nil {
+		return err
+	}
+	if o, ok := v.(*order); ok && len(o.Lines) > 0 {
+		o.Lines = o.Lines[:len(o.Lines)-1]
+	}
+	return nil
+}
+
+func TestCheckReportsMismatch(t *testing.T) {
+	rec := &recorder{}
+	CheckConfig(rec, lossy{newFory()}, &quick.Config{MaxCount: 200, Rand: rand.New(rand.NewSource(1))}, order{})
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "foryquick: foryquick.order value")
+	require.Contains(t, rec.errors[0], "read back")
+	require.NotContains(t, rec.errors[0], "seed")
+
+	rec = &recorder{}
+	Check(rec, fory.New(fory.WithXlang(true)), line{})
+	require.Len(t, rec.errors, 1)
+	require.Contains(t, rec.errors[0], "serialize")
+	require.Contains(t, rec.errors[0], "seed")
+}
@@ -0,0 +1,250 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package foryrpc implements Fory codecs for net/rpc, so services built on the
+// standard library RPC package can replace gob with Fory payloads that other
+// languages can read.
+//
+// Each request and response is sent as two frames: a header and a body. A
+// frame is the length of a Fory payload as an unsigned varint followed by the
+// payload. The header is a struct registered by name as "fory.rpc.Header" with
+// fields service_method, seq and error; the body is the argument or reply, or
+// null when the response carries an error.
+//
+// Arguments and replies are serialized by Fory instances created with the
+// factory passed to the codec constructors, so register their types there. The
+// factory is called once for the reading side and once for the writing side of
+// a connection; a nil factory creates xlang instances with default options.
+package foryrpc
+
+import (
+	"bufio"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"net/rpc"
+	"reflect"
+	"sync"
+
+	"github.com/apache/fory/go/fory"
+)
+
+// HeaderName is the registered name of the header struct that precedes every
+// request and response body.
+const HeaderName = "fory.rpc.Header"
+
+// maxFrameSize bounds the frame length accepted from a peer before allocating.
+const maxFrameSize = 1 << 30
+
+type header struct {
+	ServiceMethod string
+	Seq           uint64
+	Error         string
+}
+
+// conn reads and writes frames on one connection. Reads and writes each use
+// their own Fory instance, because net/rpc reads and writes concurrently.
+type conn struct {
+	rwc    io.ReadWriteCloser
+	r      *bufio.Reader
+	w      *bufio.Writer
+	reader *fory.Fory
+	writer *fory.Fory
+
+	closeOnce sync.Once
+	closeErr  error
+}
+
+func newConn(rwc io.ReadWriteCloser, factory func() *fory.Fory) (*conn, error) {
+	if factory == nil {
+		factory = func() *fory.Fory { return fory.New(fory.WithXlang(true)) }
+	}
+	c := &conn{rwc: rwc, r: bufio.NewReader(rwc), w: bufio.NewWriter(rwc)}
+	c.reader, c.writer = factory(), factory()
+	for _, f := range []*fory.Fory{c.reader, c.writer} {
+		if err := f.RegisterStructByName(header{}, HeaderName); err != nil {
+			return nil, fmt.Errorf("foryrpc: register header: %w", err)
+		}
+	}
+	return c, nil
+}
+
+// writeFrames writes the header and body frames and flushes them.
+func (c *conn) writeFrames(h *header, body any) error {
+	if err := c.writeFrame(h); err != nil {
+		return err
+	}
+	// Fory serializes root structs through pointers.
+	if value := reflect.ValueOf(body); value.Kind() == reflect.Struct {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		body = ptr.Interface()
+	}
+	if err := c.writeFrame(body); err != nil {
+		return err
+	}
+	return c.w.Flush()
+}
+
+func (c *conn) writeFrame(v any) error {
+	data, err := c.writer.Serialize(v)
+	if err != nil {
+		return err
+	}
+	var length [binary.MaxVarintLen64]byte
+	if _, err := c.w.Write(length[:binary.PutUvarint(length[:], uint64(len(data)))]); err != nil {
+		return err
+	}
+	_, err = c.w.Write(data)
+	return err
+}
+
+// readFrame reads the next frame and decodes it into v. A nil v discards it.
+func (c *conn) readFrame(v any) error {
+	length, err := binary.ReadUvarint(c.r)
+	if err != nil {
+		return err
+	}
+	if length > maxFrameSize {
+		return fmt.Errorf("foryrpc: frame of %d bytes exceeds limit", length)
+	}
+	if v == nil {
+		_, err = c.r.Discard(int(length))
+		return unexpectedEOF(err)
+	}
+	// Decoded values may alias the frame, so it is not reused.
+	frame := make([]byte, length)
+	if _, err := io.ReadFull(c.r, frame); err != nil {
+		return unexpectedEOF(err)
+	}
+	return c.reader.Deserialize(frame, v)
+}
+
+func (c *conn) Close() error {
+	c.closeOnce.Do(func() { c.closeErr = c.rwc.Close() })
+	return c.closeErr
+}
+
+func unexpectedEOF(err error) error {
+	if err == io.EOF {
+		return io.ErrUnexpectedEOF
+	}
+	return err
+}
+
+type serverCodec struct {
+	*conn
+	header header
+}
+
+// NewServerCodec returns an rpc.ServerCodec that uses Fory on rwc.
+func NewServerCodec(rwc io.ReadWriteCloser, factory func() *fory.Fory) (rpc.ServerCodec, error) {
+	c, err := newConn(rwc, factory)
+	if err != nil {
+		return nil, err
+	}
+	return &serverCodec{conn: c}, nil
+}
+
+func (c *serverCodec) ReadRequestHeader(r *rpc.Request) error {
+	c.header = header{}
+	if err := c.readFrame(&c.header); err != nil {
+		return err
+	}
+	r.ServiceMethod = c.header.ServiceMethod
+	r.Seq = c.header.Seq
+	return nil
+}
+
+func (c *serverCodec) ReadRequestBody(body any) error {
+	return c.readFrame(body)
+}
+
+func (c *serverCodec) WriteResponse(r *rpc.Response, body any) error {
+	if r.Error != "" {
+		body = nil
+	}
+	return c.writeFrames(&header{ServiceMethod: r.ServiceMethod, Seq: r.Seq, Error: r.Error}, body)
+}
+
+// ServeConn runs the DefaultServer on a single connection using Fory. It
+// blocks until the client hangs up.
+func ServeConn(rwc io.ReadWriteCloser, factory func() *fory.Fory) error {
+	codec, err := NewServerCodec(rwc, factory)
+	if err != nil {
+		return err
+	}
+	rpc.ServeCodec(codec)
+	return nil
+}
+
+type clientCodec struct {
+	*conn
+	header header
+}
+
+// NewClientCodec returns an rpc.ClientCodec that uses Fory on rwc.
+func NewClientCodec(rwc io.ReadWriteCloser, factory func() *fory.Fory) (rpc.ClientCodec, error) {
+	c, err := newConn(rwc, factory)
+	if err != nil {
+		return nil, err
+	}
+	return &clientCodec{conn: c}, nil
+}
+
+func (c *clientCodec) WriteRequest(r *rpc.Request, body any) error {
+	return c.writeFrames(&header{ServiceMethod: r.ServiceMethod, Seq: r.Seq}, body)
+}
+
+func (c *clientCodec) ReadResponseHeader(r *rpc.Response) error {
+	c.header = header{}
+	if err := c.readFrame(&c.header); err != nil {
+		return err
+	}
+	r.ServiceMethod = c.header.ServiceMethod
+	r.Seq = c.header.Seq
+	r.Error = c.header.Error
+	return nil
+}
+
+func (c *clientCodec) ReadResponseBody(body any) error {
+	return c.readFrame(body)
+}
+
+// NewClient returns an rpc.Client that uses Fory on rwc.
+func NewClient(rwc io.ReadWriteCloser, factory func() *fory.Fory) (*rpc.Client, error) {
+	codec, err := NewClientCodec(rwc, factory)
+	if err != nil {
+		return nil, err
+	}
+	return rpc.NewClientWithCodec(codec), nil
+}
+
+// Dial connects to a Fory RPC server at the specified network address.
+func Dial(network, address string, factory func() *fory.Fory) (*rpc.Client, error) {
+	rwc, err := net.Dial(network, address)
+	if err != nil {
+		return nil, err
+	}
+	client, err := NewClient(rwc, factory)
+	if err != nil {
+		rwc.Close()
+		return nil, err
+	}
+	return client, nil
+}
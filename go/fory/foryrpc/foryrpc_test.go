@@ -0,0 +1,120 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package foryrpc
+
+import (
+	"errors"
+	"net"
+	"net/rpc"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/require"
+)
+
+type Args struct {
+	A, B int32
+}
+
+type Quotient struct {
+	Quo, Rem int32
+}
+
+type Arith struct{}
+
+func (Arith) Divide(args Args, reply *Quotient) error {
+	if args.B == 0 {
+		return errors.New("divide by zero")
+	}
+	reply.Quo = args.A / args.B
+	reply.Rem = args.A % args.B
+	return nil
+}
+
+func (Arith) Concat(args []string, reply *string) error {
+	for _, s := range args {
+		*reply += s
+	}
+	return nil
+}
+
+func newFory() *fory.Fory {
+	f := fory.New(fory.WithXlang(true))
+	if err := f.RegisterStructByName(Args{}, "example.Args"); err != nil {
+		panic(err)
+	}
+	if err := f.RegisterStructByName(Quotient{}, "example.Quotient"); err != nil {
+		panic(err)
+	}
+	return f
+}
+
+func newClient(t *testing.T) *rpc.Client {
+	server := rpc.NewServer()
+	require.NoError(t, server.Register(Arith{}))
+	serverConn, clientConn := net.Pipe()
+	codec, err := NewServerCodec(serverConn, newFory)
+	require.NoError(t, err)
+	go server.ServeCodec(codec)
+	client, err := NewClient(clientConn, newFory)
+	require.NoError(t, err)
+	t.Cleanup(func() { client.Close() })
+	return client
+}
+
+func TestCall(t *testing.T) {
+	client := newClient(t)
+
+	var quo Quotient
+	require.NoError(t, client.Call("Arith.Divide", Args{A: 7, B: 2}, &quo))
+	require.Equal(t, Quotient{Quo: 3, Rem: 1}, quo)
+
+	var s string
+	require.NoError(t, client.Call("Arith.Concat", []string{"fo", "ry"}, &s))
+	require.Equal(t, "fory", s)
+}
+
+func TestCallErrors(t *testing.T) {
+	client := newClient(t)
+
+	var quo Quotient
+	err := client.Call("Arith.Divide", &Args{A: 1}, &quo)
+	require.Error(t, err)
+	require.Equal(t, "divide by zero", err.Error())
+
+	err = client.Call("Arith.Missing", &Args{}, &quo)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "can't find method")
+
+	// The connection stays usable after errors.
+	require.NoError(t, client.Call("Arith.Divide", &Args{A: 9, B: 3}, &quo))
+	require.Equal(t, Quotient{Quo: 3}, quo)
+}
+
+func TestConcurrentCalls(t *testing.T) {
+	client := newClient(t)
+	calls := make([]*rpc.Call, 20)
+	for i := range calls {
+		calls[i] = client.Go("Arith.Divide", &Args{A: int32(i), B: 2}, new(Quotient), nil)
+	}
+	for i, call := range calls {
+		<-call.Done
+		require.NoError(t, call.Error)
+		require.Equal(t, &Quotient{Quo: int32(i) / 2, Rem: int32(i) % 2}, call.Reply)
+	}
+}
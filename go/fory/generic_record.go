@@ -0,0 +1,526 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"iter"
+	"reflect"
+	"strconv"
+)
+
+var (
+	genericRecordPtrType = reflect.TypeOf((*GenericRecord)(nil))
+	structMapType        = reflect.TypeOf((map[string]any)(nil))
+)
+
+// GenericRecord is a struct value decoded without a Go struct type. It holds
+// the fields of the struct's type metadata in their written order, and writes
+// them back with the same metadata, so a record can be inspected, changed and
+// forwarded without registering its type.
+//
+// Decode a record by passing a **GenericRecord to Deserialize:
+//
+//	var rec *fory.GenericRecord
+//	if err := f.Deserialize(data, &rec); err != nil {
+//	    return err
+//	}
+//	rec.Set("status", "routed")
+//	out, err := f.Serialize(rec)
+//
+// Records need the type metadata written in compatible mode. Nested structs
+// whose types are not registered are decoded as *GenericRecord too; nested
+// values of registered types keep their Go types.
+type GenericRecord struct {
+	layout *genericRecordLayout
+	values []any
+}
+
+// genericRecordLayout is the field layout shared by the records of one TypeDef.
+type genericRecordLayout struct {
+	typeDef  *TypeDef
+	name     string
+	fields   []genericRecordField
+	index    map[string]int
+	typeInfo *TypeInfo
+}
+
+type genericRecordField struct {
+	name    string
+	type_   reflect.Type
+	refMode RefMode
+	// dynamic fields carry their own type info and are read into an any.
+	dynamic    bool
+	serializer Serializer
+}
+
+// TypeName returns the namespace-qualified name the struct was registered
+// under, or an empty string for structs registered by ID.
+func (r *GenericRecord) TypeName() string {
+	return r.layout.name
+}
+
+// Len returns the number of fields.
+func (r *GenericRecord) Len() int {
+	return len(r.values)
+}
+
+// FieldNames returns the field names in written order. Fields identified by
+// tag ID are named by the decimal ID.
+func (r *GenericRecord) FieldNames() []string {
+	names := make([]string, len(r.layout.fields))
+	for i := range r.layout.fields {
+		names[i] = r.layout.fields[i].name
+	}
+	return names
+}
+
+// Get returns the value of the named field. Null fields are nil.
+func (r *GenericRecord) Get(name string) (any, bool) {
+	i, ok := r.layout.index[name]
+	if !ok {
+		return nil, false
+	}
+	return r.values[i], true
+}
+
+// Set replaces the value of the named field. The value must have the Go type
+// the field decodes to, except for fields that hold any value, and may only
+// be nil for nullable fields.
+func (r *GenericRecord) Set(name string, value any) error {
+	i, ok := r.layout.index[name]
+	if !ok {
+		return fmt.Errorf("record %s has no field %s", r.layout.name, name)
+	}
+	field := &r.layout.fields[i]
+	if value == nil {
+		if field.refMode == RefModeNone {
+			return fmt.Errorf("field %s is not nullable", name)
+		}
+	} else if !reflect.TypeOf(value).AssignableTo(field.type_) {
+		return fmt.Errorf("field %s holds %s, got %T", name, field.type_, value)
+	}
+	r.values[i] = value
+	return nil
+}
+
+// All returns an iterator over the field names and values in written order.
+func (r *GenericRecord) All() iter.Seq2[string, any] {
+	return func(yield func(string, any) bool) {
+		for i := range r.values {
+			if !yield(r.layout.fields[i].name, r.values[i]) {
+				return
+			}
+		}
+	}
+}
+
+// genericRecordLayout returns the record layout of td, building it on first use.
+func (td *TypeDef) genericRecordLayout(resolver *TypeResolver) (*genericRecordLayout, error) {
+	if td.record != nil {
+		return td.record, nil
+	}
+	layout := &genericRecordLayout{
+		typeDef: td,
+		name:    td.unknownTypeWarning(resolver).Name,
+		fields:  make([]genericRecordField, len(td.fieldDefs)),
+		index:   make(map[string]int, len(td.fieldDefs)),
+	}
+	for i, def := range td.fieldDefs {
+		field := genericRecordField{name: def.name}
+		if def.tagID >= 0 {
+			field.name = strconv.Itoa(def.tagID)
+		}
+		if def.trackRef {
+			field.refMode = RefModeTracking
+		} else if def.nullable {
+			field.refMode = RefModeNullOnly
+		}
+		typeID := def.typeSpec.TypeId()
+		switch {
+		case typeID == UNKNOWN || isStructFieldType(def.typeSpec):
+			field.type_ = interfaceType
+			field.dynamic = true
+		case typeID == ENUM || typeID == NAMED_ENUM:
+			// The field metadata does not name the enum type, so the ordinal is kept.
+			field.type_ = uint32Type
+			field.serializer = &enumSerializer{type_: uint32Type}
+		default:
+			info, err := def.typeSpec.getTypeInfoWithResolver(resolver)
+			if err != nil {
+				return nil, fmt.Errorf("field %s: %w", field.name, err)
+			}
+			if info.Type == nil || info.Serializer == nil || info.Type.Kind() == reflect.Interface {
+				return nil, fmt.Errorf("field %s: type %d cannot be decoded into a record", field.name, typeID)
+			}
+			field.type_ = info.Type
+			field.serializer = info.Serializer
+		}
+		layout.fields[i] = field
+		layout.index[field.name] = i
+	}
+	layout.typeInfo = &TypeInfo{
+		Type:         genericRecordPtrType,
+		TypeID:       td.typeId,
+		UserTypeID:   td.userTypeId,
+		PkgPathBytes: td.nsName,
+		NameBytes:    td.typeName,
+		TypeDef:      td,
+		Serializer:   genericRecordSerializer{},
+		NeedWriteRef: true,
+	}
+	td.record = layout
+	return layout, nil
+}
+
+// genericRecordTypeInfo returns the type info written for the record held by
+// value, which carries the metadata of the type the record was decoded from.
+func genericRecordTypeInfo(value reflect.Value) (*TypeInfo, error) {
+	if value.IsNil() {
+		return nil, fmt.Errorf("nil *GenericRecord has no type info")
+	}
+	return value.Interface().(*GenericRecord).layout.typeInfo, nil
+}
+
+// genericRecordTypeDef returns the TypeDef of the record held by v, or nil
+// if v does not hold a record.
+func genericRecordTypeDef(v reflect.Value) *TypeDef {
+	if v.Type() != genericRecordPtrType || v.IsNil() {
+		return nil
+	}
+	return v.Interface().(*GenericRecord).layout.typeDef
+}
+
+// sameGenericRecordSchema reports whether the records in slice were all
+// decoded from the same type metadata. Records of different structs share
+// one Go type, so they cannot share the element type info of a collection.
+func sameGenericRecordSchema(slice reflect.Value) bool {
+	var typeDef *TypeDef
+	for i := 0; i < slice.Len(); i++ {
+		elem := slice.Index(i)
+		if elem.Kind() == reflect.Interface {
+			elem = elem.Elem()
+		}
+		if !elem.IsValid() {
+			continue
+		}
+		td := genericRecordTypeDef(elem)
+		if td == nil {
+			continue
+		}
+		if typeDef == nil {
+			typeDef = td
+		} else if td != typeDef {
+			return false
+		}
+	}
+	return true
+}
+
+// readGenericRecord reads the fields of a struct described by td into a new
+// record and stores it in value.
+func readGenericRecord(ctx *ReadContext, td *TypeDef, value reflect.Value) {
+	layout, err := td.genericRecordLayout(ctx.TypeResolver())
+	if err != nil {
+		ctx.SetError(DeserializationErrorf("cannot decode %s as a GenericRecord: %v", td.unknownTypeWarning(ctx.TypeResolver()).typeName(), err))
+		return
+	}
+	rec := &GenericRecord{layout: layout, values: make([]any, len(layout.fields))}
+	value.Set(reflect.ValueOf(rec))
+	prev := ctx.genericRecords
+	ctx.genericRecords = true
+	ctx.incDepth()
+	presence := readRecordPresence(ctx, td)
+	for i := range layout.fields {
+		if !fieldPresent(presence, i) {
+			rec.values[i] = layout.fields[i].zero()
+			continue
+		}
+		rec.values[i] = readGenericRecordField(ctx, &layout.fields[i])
+		if ctx.HasError() {
+			break
+		}
+	}
+	ctx.decDepth()
+	ctx.genericRecords = prev
+}
+
+// readStructAsMap reads the fields of a struct described by td into a
+// map[string]any keyed by field name and stores it in value. It backs
+// WithUnknownStructsAsMaps.
+func readStructAsMap(ctx *ReadContext, td *TypeDef, value reflect.Value) {
+	layout, err := td.genericRecordLayout(ctx.TypeResolver())
+	if err != nil {
+		ctx.SetError(DeserializationErrorf("cannot decode %s as a map: %v", td.unknownTypeWarning(ctx.TypeResolver()).typeName(), err))
+		return
+	}
+	fields := make(map[string]any, len(layout.fields))
+	value.Set(reflect.ValueOf(fields))
+	ctx.incDepth()
+	presence := readRecordPresence(ctx, td)
+	for i := range layout.fields {
+		if !fieldPresent(presence, i) {
+			fields[layout.fields[i].name] = layout.fields[i].zero()
+			continue
+		}
+		fields[layout.fields[i].name] = readGenericRecordField(ctx, &layout.fields[i])
+		if ctx.HasError() {
+			break
+		}
+	}
+	ctx.decDepth()
+}
+
+// readRecordPresence reads the presence bitmap of a struct described by td,
+// returning nil when its values are written in full.
+func readRecordPresence(ctx *ReadContext, td *TypeDef) []byte {
+	if !td.omitZero {
+		return nil
+	}
+	return readFieldPresence(ctx, len(td.fieldDefs))
+}
+
+// zero returns the value of a field an omit-zero writer left out: nil for
+// nullable and struct fields, the zero value otherwise.
+func (f *genericRecordField) zero() any {
+	if f.dynamic || f.refMode != RefModeNone {
+		return nil
+	}
+	return reflect.Zero(f.type_).Interface()
+}
+
+// decodesUnknownStructs reports whether values of unregistered struct types
+// are decoded instead of skipped.
+func (c *ReadContext) decodesUnknownStructs() bool {
+	return c.genericRecords || c.unknownStructsAsMaps
+}
+
+func readGenericRecordField(ctx *ReadContext, field *genericRecordField) any {
+	if field.dynamic {
+		var v any
+		ctx.ReadValue(reflect.ValueOf(&v).Elem(), field.refMode, true)
+		return v
+	}
+	buf := ctx.Buffer()
+	refID := int32(NotNullValueFlag)
+	switch field.refMode {
+	case RefModeTracking:
+		var err error
+		refID, err = ctx.RefResolver().TryPreserveRefId(buf)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return nil
+		}
+		if refID == int32(NullFlag) {
+			return nil
+		}
+		if refID < int32(NotNullValueFlag) {
+			if obj := ctx.RefResolver().GetReadObject(refID); obj.IsValid() {
+				return obj.Interface()
+			}
+			return nil
+		}
+	case RefModeNullOnly:
+		if buf.ReadInt8(ctx.Err()) == NullFlag {
+			return nil
+		}
+	}
+	target := reflect.New(field.type_).Elem()
+	field.serializer.ReadData(ctx, target)
+	ctx.RefResolver().SetReadObject(refID, target)
+	return target.Interface()
+}
+
+func writeGenericRecordField(ctx *WriteContext, field *genericRecordField, v any) {
+	if v == nil && field.refMode == RefModeNone {
+		ctx.SetError(SerializationErrorf("field %s is not nullable", field.name))
+		return
+	}
+	if field.dynamic {
+		ctx.WriteValue(reflect.ValueOf(v), field.refMode, true)
+		return
+	}
+	buf := ctx.Buffer()
+	if v == nil {
+		buf.WriteInt8(NullFlag)
+		return
+	}
+	rv := reflect.ValueOf(v)
+	if !rv.Type().AssignableTo(field.type_) {
+		ctx.SetError(SerializationErrorf("field %s holds %s, got %T", field.name, field.type_, v))
+		return
+	}
+	value := reflect.New(field.type_).Elem()
+	value.Set(rv)
+	switch field.refMode {
+	case RefModeTracking:
+		refWritten, err := ctx.RefResolver().WriteRefOrNull(buf, value)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return
+		}
+		if refWritten {
+			return
+		}
+	case RefModeNullOnly:
+		if isNull(value) {
+			buf.WriteInt8(NullFlag)
+			return
+		}
+		buf.WriteInt8(NotNullValueFlag)
+	}
+	field.serializer.WriteData(ctx, value)
+}
+
+// genericRecordSerializer writes a *GenericRecord as the struct it was decoded
+// from and reads any struct written with compatible metadata into one.
+type genericRecordSerializer struct{}
+
+func (s genericRecordSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	if !ctx.Compatible() {
+		ctx.SetError(SerializationError("GenericRecord can only be written in compatible mode"))
+		return
+	}
+	rec := value.Interface().(*GenericRecord)
+	var presence []byte
+	if rec.layout.typeDef.omitZero {
+		presence = make([]byte, (len(rec.values)+7)/8)
+		for i, v := range rec.values {
+			if v != nil && !reflect.ValueOf(v).IsZero() {
+				presence[i>>3] |= 1 << (i & 7)
+			}
+		}
+		ctx.Buffer().WriteBinary(presence)
+	}
+	for i := range rec.layout.fields {
+		if !fieldPresent(presence, i) {
+			continue
+		}
+		writeGenericRecordField(ctx, &rec.layout.fields[i], rec.values[i])
+		if ctx.HasError() {
+			return
+		}
+	}
+}
+
+func (s genericRecordSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	buf := ctx.Buffer()
+	switch refMode {
+	case RefModeTracking:
+		refWritten, err := ctx.RefResolver().WriteRefOrNull(buf, value)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return
+		}
+		if refWritten {
+			return
+		}
+	case RefModeNullOnly:
+		if value.IsNil() {
+			buf.WriteInt8(NullFlag)
+			return
+		}
+		buf.WriteInt8(NotNullValueFlag)
+	default:
+		if value.IsNil() {
+			ctx.SetError(SerializationError("cannot write a nil *GenericRecord without a null flag"))
+			return
+		}
+	}
+	if writeType {
+		ctx.TypeResolver().WriteTypeInfo(buf, value.Interface().(*GenericRecord).layout.typeInfo, ctx.Err())
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s genericRecordSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	ctx.SetError(DeserializationError("GenericRecord requires the struct's type info"))
+}
+
+func (s genericRecordSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	buf := ctx.Buffer()
+	refID := int32(NotNullValueFlag)
+	switch refMode {
+	case RefModeTracking:
+		var err error
+		refID, err = ctx.RefResolver().TryPreserveRefId(buf)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return
+		}
+		if refID == int32(NullFlag) {
+			return
+		}
+		if refID < int32(NotNullValueFlag) {
+			if obj := ctx.RefResolver().GetReadObject(refID); obj.IsValid() {
+				value.Set(obj)
+			}
+			return
+		}
+	case RefModeNullOnly:
+		if buf.ReadInt8(ctx.Err()) == NullFlag {
+			return
+		}
+	}
+	if !readType {
+		s.ReadData(ctx, value)
+		return
+	}
+	typeInfo := ctx.TypeResolver().ReadTypeInfo(buf, ctx.Err())
+	if ctx.HasError() {
+		return
+	}
+	s.readWithTypeDef(ctx, refID, typeInfo, value)
+}
+
+func (s genericRecordSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	buf := ctx.Buffer()
+	refID := int32(NotNullValueFlag)
+	switch refMode {
+	case RefModeTracking:
+		var err error
+		refID, err = ctx.RefResolver().TryPreserveRefId(buf)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return
+		}
+		if refID == int32(NullFlag) {
+			return
+		}
+		if refID < int32(NotNullValueFlag) {
+			if obj := ctx.RefResolver().GetReadObject(refID); obj.IsValid() {
+				value.Set(obj)
+			}
+			return
+		}
+	case RefModeNullOnly:
+		if buf.ReadInt8(ctx.Err()) == NullFlag {
+			return
+		}
+	}
+	s.readWithTypeDef(ctx, refID, typeInfo, value)
+}
+
+func (s genericRecordSerializer) readWithTypeDef(ctx *ReadContext, refID int32, typeInfo *TypeInfo, value reflect.Value) {
+	if typeInfo == nil || typeInfo.TypeDef == nil || !isStructTypeId(TypeId(typeInfo.TypeID)) {
+		ctx.SetError(DeserializationError("GenericRecord requires a struct written with compatible type metadata"))
+		return
+	}
+	readGenericRecord(ctx, typeInfo.TypeDef, value)
+	ctx.RefResolver().SetReadObject(refID, value)
+}
@@ -0,0 +1,228 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type recordAddress struct {
+	City string
+	Zip  int32
+}
+
+type recordOrder struct {
+	Number   int64
+	Customer string
+	Tags     []string
+	Ship     *recordAddress
+	Stops    []*recordAddress
+	Note     *string
+	Total    float64
+}
+
+func recordOptions() map[string][]Option {
+	return map[string][]Option{
+		"xlang":     {WithXlang(true), WithCompatible(true)},
+		"xlang ref": {WithXlang(true), WithCompatible(true), WithRefTracking(true)},
+		"native":    {WithXlang(false), WithCompatible(true)},
+	}
+}
+
+func newRecordWriter(t *testing.T, opts []Option) *Fory {
+	f := New(opts...)
+	require.NoError(t, f.RegisterStructByName(recordAddress{}, "example.Address"))
+	require.NoError(t, f.RegisterStructByName(recordOrder{}, "example.Order"))
+	return f
+}
+
+func TestGenericRecordRoundTrip(t *testing.T) {
+	for name, opts := range recordOptions() {
+		t.Run(name, func(t *testing.T) {
+			writer := newRecordWriter(t, opts)
+			order := &recordOrder{
+				Number:   7,
+				Customer: "ada",
+				Tags:     []string{"a", "b"},
+				Ship:     &recordAddress{City: "Paris", Zip: 75001},
+				Stops:    []*recordAddress{{City: "Lyon", Zip: 69001}, {City: "Nice", Zip: 6000}},
+				Total:    12.5,
+			}
+			data, err := writer.Serialize(order)
+			require.NoError(t, err)
+
+			router := New(opts...)
+			var rec *GenericRecord
+			require.NoError(t, router.Deserialize(data, &rec))
+			require.Equal(t, "example.Order", rec.TypeName())
+			require.Equal(t, 7, rec.Len())
+			require.ElementsMatch(t,
+				[]string{"number", "customer", "tags", "ship", "stops", "note", "total"}, rec.FieldNames())
+
+			id, ok := rec.Get("number")
+			require.True(t, ok)
+			require.Equal(t, int64(7), id)
+			note, _ := rec.Get("note")
+			require.Nil(t, note)
+			ship, _ := rec.Get("ship")
+			shipRec, ok := ship.(*GenericRecord)
+			require.True(t, ok, "ship is %T", ship)
+			require.Equal(t, "example.Address", shipRec.TypeName())
+			city, _ := shipRec.Get("city")
+			require.Equal(t, "Paris", city)
+			stops, _ := rec.Get("stops")
+			require.Len(t, stops, 2)
+
+			fields := map[string]any{}
+			for name, v := range rec.All() {
+				fields[name] = v
+			}
+			require.Len(t, fields, 7)
+
+			require.NoError(t, rec.Set("customer", "grace"))
+			require.NoError(t, rec.Set("note", "fragile"))
+			require.NoError(t, shipRec.Set("zip", int32(75002)))
+
+			out, err := router.Serialize(rec)
+			require.NoError(t, err)
+			var decoded recordOrder
+			require.NoError(t, writer.Deserialize(out, &decoded))
+			fragile := "fragile"
+			order.Customer = "grace"
+			order.Note = &fragile
+			order.Ship.Zip = 75002
+			require.Equal(t, *order, decoded)
+		})
+	}
+}
+
+type recordBatch struct {
+	Items []any
+	ByKey map[string]any
+}
+
+func TestGenericRecordInContainers(t *testing.T) {
+	opts := []Option{WithXlang(true), WithCompatible(true)}
+	writer := newRecordWriter(t, opts)
+	require.NoError(t, writer.RegisterStructByName(recordBatch{}, "example.Batch"))
+	batch := &recordBatch{
+		Items: []any{
+			&recordAddress{City: "Oslo"},
+			&recordOrder{Number: 1, Customer: "x"},
+			&recordAddress{City: "Rome"},
+		},
+		ByKey: map[string]any{
+			"a": &recordAddress{City: "Oslo"},
+			"b": &recordOrder{Number: 2},
+			"c": &recordAddress{City: "Rome"},
+		},
+	}
+	data, err := writer.Serialize(batch)
+	require.NoError(t, err)
+
+	router := New(opts...)
+	var rec *GenericRecord
+	require.NoError(t, router.Deserialize(data, &rec))
+	items, _ := rec.Get("items")
+	require.Len(t, items, 3)
+	require.Equal(t, "example.Order", items.([]any)[1].(*GenericRecord).TypeName())
+
+	out, err := router.Serialize(rec)
+	require.NoError(t, err)
+	var decoded recordBatch
+	require.NoError(t, writer.Deserialize(out, &decoded))
+	require.Len(t, decoded.Items, 3)
+	require.Equal(t, recordAddress{City: "Rome"}, decoded.Items[2])
+	require.Equal(t, "x", decoded.Items[1].(recordOrder).Customer)
+	require.Equal(t, recordAddress{City: "Rome"}, decoded.ByKey["c"])
+	require.Equal(t, int64(2), decoded.ByKey["b"].(recordOrder).Number)
+
+	// Records of different structs in one list keep their own type info.
+	out, err = router.Serialize(items)
+	require.NoError(t, err)
+	var decodedItems []any
+	require.NoError(t, writer.Deserialize(out, &decodedItems))
+	require.Len(t, decodedItems, 3)
+	require.Equal(t, recordAddress{City: "Oslo"}, decodedItems[0])
+	require.Equal(t, int64(1), decodedItems[1].(recordOrder).Number)
+}
+
+func TestGenericRecordSetValidation(t *testing.T) {
+	opts := []Option{WithXlang(true), WithCompatible(true)}
+	data, err := newRecordWriter(t, opts).Serialize(&recordAddress{City: "Oslo"})
+	require.NoError(t, err)
+	var rec *GenericRecord
+	require.NoError(t, New(opts...).Deserialize(data, &rec))
+
+	require.Error(t, rec.Set("country", "NO"))
+	require.Error(t, rec.Set("zip", "0150"))
+	require.Error(t, rec.Set("zip", nil))
+	require.NoError(t, rec.Set("zip", int32(150)))
+	_, ok := rec.Get("country")
+	require.False(t, ok)
+}
+
+func TestGenericRecordRequiresCompatible(t *testing.T) {
+	opts := []Option{WithXlang(true), WithCompatible(false)}
+	data, err := newRecordWriter(t, opts).Serialize(&recordAddress{City: "Oslo"})
+	require.NoError(t, err)
+	var rec *GenericRecord
+	require.Error(t, New(opts...).Deserialize(data, &rec))
+}
+
+type recordEnvelope struct {
+	Payload any
+	Items   []any
+}
+
+func TestUnknownStructsAsMaps(t *testing.T) {
+	for name, opts := range recordOptions() {
+		t.Run(name, func(t *testing.T) {
+			writer := newRecordWriter(t, opts)
+			require.NoError(t, writer.RegisterStructByName(recordEnvelope{}, "example.Envelope"))
+			note := "fragile"
+			data, err := writer.Serialize(&recordEnvelope{
+				Payload: &recordOrder{Number: 3, Ship: &recordAddress{City: "Oslo", Zip: 150}, Note: &note},
+				Items:   []any{&recordAddress{City: "Rome"}, "x"},
+			})
+			require.NoError(t, err)
+
+			reader := New(append(opts, WithUnknownStructsAsMaps(true))...)
+			require.NoError(t, reader.RegisterStructByName(recordEnvelope{}, "example.Envelope"))
+			var env recordEnvelope
+			require.NoError(t, reader.Deserialize(data, &env))
+			payload, ok := env.Payload.(map[string]any)
+			require.True(t, ok, "payload is %T", env.Payload)
+			require.Equal(t, int64(3), payload["number"])
+			require.Equal(t, "fragile", payload["note"])
+			require.Equal(t, map[string]any{"city": "Oslo", "zip": int32(150)}, payload["ship"])
+			require.Equal(t, []any{map[string]any{"city": "Rome", "zip": int32(0)}, "x"}, env.Items)
+
+			skipping := New(opts...)
+			require.NoError(t, skipping.RegisterStructByName(recordEnvelope{}, "example.Envelope"))
+			env = recordEnvelope{}
+			_, err = skipping.UnmarshalPartial(data, &env)
+			require.NoError(t, err)
+			require.Nil(t, env.Payload)
+		})
+	}
+}
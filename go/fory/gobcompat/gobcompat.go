@@ -0,0 +1,213 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package gobcompat offers encoding/gob style encoders and decoders that write
+// the Fory wire format, so gob users can switch formats by changing an import.
+//
+// As with gob, struct types reachable from the encoded or decoded value are
+// handled automatically, while concrete types sent as interface values must be
+// announced with Register or RegisterName on both sides. Payloads use Fory's
+// native mode with compatible structs, so fields may be added or removed
+// between writer and reader.
+package gobcompat
+
+import (
+	"fmt"
+	"io"
+	"reflect"
+	"sync"
+
+	"github.com/apache/fory/go/fory"
+)
+
+var registry = struct {
+	mu    sync.RWMutex
+	names map[reflect.Type]string
+	types map[string]reflect.Type
+}{
+	names: make(map[reflect.Type]string),
+	types: make(map[string]reflect.Type),
+}
+
+// Register records the type of value under its package path and name so that
+// it can be encoded and decoded as an interface value. Like gob.Register it
+// panics if the type or name is already registered differently.
+func Register(value any) {
+	type_ := baseType(reflect.TypeOf(value))
+	RegisterName(typeName(type_), value)
+}
+
+// RegisterName is like Register but uses the provided name.
+func RegisterName(name string, value any) {
+	if name == "" {
+		panic("gobcompat: attempt to register empty name")
+	}
+	type_ := baseType(reflect.TypeOf(value))
+	registry.mu.Lock()
+	defer registry.mu.Unlock()
+	if prev, ok := registry.types[name]; ok && prev != type_ {
+		panic(fmt.Sprintf("gobcompat: registering duplicate types for %q: %s != %s", name, prev, type_))
+	}
+	if prev, ok := registry.names[type_]; ok && prev != name {
+		panic(fmt.Sprintf("gobcompat: registering duplicate names for %s: %q != %q", type_, prev, name))
+	}
+	registry.names[type_] = name
+	registry.types[name] = type_
+}
+
+// Encoder writes values to an io.Writer. It is safe for concurrent use.
+type Encoder struct {
+	mu    sync.Mutex
+	w     io.Writer
+	types typeSet
+}
+
+// NewEncoder returns a new encoder that writes to w.
+func NewEncoder(w io.Writer) *Encoder {
+	return &Encoder{w: w, types: newTypeSet()}
+}
+
+// Encode writes the value e. Each value is written as a self-contained Fory
+// payload.
+func (enc *Encoder) Encode(e any) error {
+	if e == nil {
+		return fmt.Errorf("gobcompat: cannot encode nil value")
+	}
+	enc.mu.Lock()
+	defer enc.mu.Unlock()
+	if err := enc.types.prepare(reflect.TypeOf(e)); err != nil {
+		return err
+	}
+	// Fory serializes root structs through pointers; gob accepts both.
+	if value := reflect.ValueOf(e); value.Kind() == reflect.Struct {
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		e = ptr.Interface()
+	}
+	data, err := enc.types.fory.Serialize(e)
+	if err != nil {
+		return err
+	}
+	_, err = enc.w.Write(data)
+	return err
+}
+
+// Decoder reads values written by an Encoder. It is safe for concurrent use.
+type Decoder struct {
+	mu     sync.Mutex
+	stream *fory.InputStream
+	types  typeSet
+}
+
+// NewDecoder returns a new decoder that reads from r.
+func NewDecoder(r io.Reader) *Decoder {
+	return &Decoder{stream: fory.NewInputStream(r), types: newTypeSet()}
+}
+
+// Decode reads the next value from the input and stores it in e, which must be
+// a non-nil pointer. If e is nil the value is discarded. At the end of the
+// input Decode returns io.EOF.
+func (dec *Decoder) Decode(e any) error {
+	if e == nil {
+		var discard any
+		e = &discard
+	}
+	value := reflect.ValueOf(e)
+	if value.Kind() != reflect.Ptr || value.IsNil() {
+		return fmt.Errorf("gobcompat: attempt to decode into a non-pointer %T", e)
+	}
+	dec.mu.Lock()
+	defer dec.mu.Unlock()
+	if err := dec.types.prepare(value.Type().Elem()); err != nil {
+		return err
+	}
+	return dec.types.fory.DeserializeFromStream(dec.stream, e)
+}
+
+// typeSet registers struct types with a Fory instance the first time they are
+// seen.
+type typeSet struct {
+	fory *fory.Fory
+	seen map[reflect.Type]bool
+}
+
+func newTypeSet() typeSet {
+	return typeSet{
+		fory: fory.New(fory.WithXlang(false), fory.WithCompatible(true), fory.WithTrackRef(true)),
+		seen: make(map[reflect.Type]bool),
+	}
+}
+
+func (s *typeSet) prepare(type_ reflect.Type) error {
+	registry.mu.RLock()
+	defer registry.mu.RUnlock()
+	for registered := range registry.names {
+		if err := s.register(registered); err != nil {
+			return err
+		}
+	}
+	return s.register(type_)
+}
+
+// register walks type_ and registers each named struct reachable from it.
+func (s *typeSet) register(type_ reflect.Type) error {
+	if s.seen[type_] {
+		return nil
+	}
+	s.seen[type_] = true
+	switch type_.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return s.register(type_.Elem())
+	case reflect.Map:
+		if err := s.register(type_.Key()); err != nil {
+			return err
+		}
+		return s.register(type_.Elem())
+	case reflect.Struct:
+		if type_.Name() != "" && type_.PkgPath() != "" {
+			name, ok := registry.names[type_]
+			if !ok {
+				name = typeName(type_)
+			}
+			if err := s.fory.RegisterStructByName(type_, name); err != nil {
+				return fmt.Errorf("gobcompat: register %s: %w", type_, err)
+			}
+		}
+		for i := 0; i < type_.NumField(); i++ {
+			if field := type_.Field(i); field.IsExported() {
+				if err := s.register(field.Type); err != nil {
+					return err
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func baseType(type_ reflect.Type) reflect.Type {
+	for type_.Kind() == reflect.Ptr {
+		type_ = type_.Elem()
+	}
+	return type_
+}
+
+func typeName(type_ reflect.Type) string {
+	if type_.PkgPath() == "" {
+		return type_.String()
+	}
+	return type_.PkgPath() + "." + type_.Name()
+}
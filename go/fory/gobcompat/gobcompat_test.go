@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package gobcompat
+
+import (
+	"bytes"
+	"io"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type address struct {
+	City string
+}
+
+type person struct {
+	Name    string
+	Age     int32
+	Home    *address
+	Tags    []string
+	Friends map[string]address
+}
+
+type shape interface {
+	Area() float64
+}
+
+type square struct {
+	Side float64
+}
+
+func (s square) Area() float64 { return s.Side * s.Side }
+
+type drawing struct {
+	Shapes []shape
+}
+
+func TestEncodeDecodeSequence(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewEncoder(&buf)
+	alice := person{
+		Name:    "alice",
+		Age:     30,
+		Home:    &address{City: "Paris"},
+		Tags:    []string{"a", "b"},
+		Friends: map[string]address{"bob": {City: "Rome"}},
+	}
+	require.NoError(t, enc.Encode(alice))
+	require.NoError(t, enc.Encode(&person{Name: "bob"}))
+	require.NoError(t, enc.Encode(map[string]int64{"n": 1}))
+
+	dec := NewDecoder(&buf)
+	var p1 person
+	require.NoError(t, dec.Decode(&p1))
+	require.Equal(t, alice, p1)
+	var p2 *person
+	require.NoError(t, dec.Decode(&p2))
+	require.Equal(t, "bob", p2.Name)
+	require.NoError(t, dec.Decode(nil))
+	require.Equal(t, io.EOF, dec.Decode(&p1))
+}
+
+func TestInterfaceValuesRequireRegister(t *testing.T) {
+	Register(square{})
+	var buf bytes.Buffer
+	value := drawing{Shapes: []shape{square{Side: 2}}}
+	require.NoError(t, NewEncoder(&buf).Encode(value))
+	var result drawing
+	require.NoError(t, NewDecoder(&buf).Decode(&result))
+	require.Len(t, result.Shapes, 1)
+	require.Equal(t, 4.0, result.Shapes[0].Area())
+}
+
+func TestRegisterNameConflicts(t *testing.T) {
+	RegisterName("gobcompat.test.Address", address{})
+	RegisterName("gobcompat.test.Address", address{})
+	require.Panics(t, func() { RegisterName("gobcompat.test.Address", square{}) })
+	require.Panics(t, func() { RegisterName("gobcompat.test.Other", address{}) })
+	require.Panics(t, func() { RegisterName("", address{}) })
+}
+
+func TestDecodeErrors(t *testing.T) {
+	var buf bytes.Buffer
+	require.NoError(t, NewEncoder(&buf).Encode(int32(1)))
+	dec := NewDecoder(&buf)
+	var n int32
+	require.Error(t, dec.Decode(n))
+	require.Error(t, NewEncoder(&buf).Encode(nil))
+}
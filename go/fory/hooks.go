@@ -0,0 +1,113 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// TypeHooks are callbacks run around the serialization of struct values, for
+// redacting fields, populating caches or recording metrics without writing a
+// custom serializer. Each callback receives a pointer to the struct.
+type TypeHooks struct {
+	// BeforeSerialize runs before a value is written. It receives a pointer to
+	// a shallow copy of the value: assigning its fields affects only the
+	// payload, but changes made through the slices, maps and pointers it
+	// holds also reach the caller's value, so replace those fields instead of
+	// modifying what they refer to.
+	BeforeSerialize func(v any)
+	// AfterDeserialize runs once all fields of a value have been read.
+	AfterDeserialize func(v any)
+}
+
+// RegisterHooks sets the hooks of a struct type, replacing earlier ones.
+// type_ can be either a reflect.Type or an instance of the type. Hooks are
+// bound when a type is first serialized, so register them before that.
+func (f *Fory) RegisterHooks(type_ any, hooks TypeHooks) (err error) {
+	defer f.recordRegistration(&err, type_, func(c *Fory) error { return c.RegisterHooks(type_, hooks) })
+	t := registeredType(type_)
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("RegisterHooks only supports struct types, got %v", t)
+	}
+	r := f.typeResolver
+	if r.typeHooks == nil {
+		r.typeHooks = make(map[reflect.Type]TypeHooks)
+	}
+	r.typeHooks[t] = hooks
+	return nil
+}
+
+// RegisterGlobalHooks sets hooks that run for every struct type, after the
+// hooks registered for the type itself. Like RegisterHooks, they must be
+// registered before the first serialization.
+func (f *Fory) RegisterGlobalHooks(hooks TypeHooks) {
+	f.typeResolver.globalHooks = hooks
+	f.registrations = append(f.registrations, registration{register: func(c *Fory) error {
+		c.RegisterGlobalHooks(hooks)
+		return nil
+	}})
+}
+
+// structHooks are the hooks bound to one struct serializer, in call order.
+type structHooks struct {
+	before []func(v any)
+	after  []func(v any)
+}
+
+func (r *TypeResolver) structHooks(t reflect.Type) *structHooks {
+	var hooks structHooks
+	for _, h := range []TypeHooks{r.typeHooks[t], r.globalHooks} {
+		if h.BeforeSerialize != nil {
+			hooks.before = append(hooks.before, h.BeforeSerialize)
+		}
+		if h.AfterDeserialize != nil {
+			hooks.after = append(hooks.after, h.AfterDeserialize)
+		}
+	}
+	if hooks.before == nil && hooks.after == nil {
+		return nil
+	}
+	return &hooks
+}
+
+// beforeSerialize returns an addressable shallow copy of value after running
+// the BeforeSerialize hooks on it.
+func (h *structHooks) beforeSerialize(value reflect.Value) reflect.Value {
+	copied := reflect.New(value.Type())
+	copied.Elem().Set(value)
+	v := copied.Interface()
+	for _, hook := range h.before {
+		hook(v)
+	}
+	return copied.Elem()
+}
+
+func (h *structHooks) afterDeserialize(ctx *ReadContext, value reflect.Value) {
+	if ctx.HasError() {
+		return
+	}
+	if ctx.bufferCopies != nil {
+		// Hooks may read arrays whose out-of-band copies are still running.
+		ctx.bufferCopies.wait()
+	}
+	v := value.Addr().Interface()
+	for _, hook := range h.after {
+		hook(v)
+	}
+}
@@ -0,0 +1,82 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type hookedUser struct {
+	Name     string
+	Password string
+	loaded   bool
+}
+
+type hookedGroup struct {
+	Owner   hookedUser
+	Members []hookedUser
+	Admin   *hookedUser
+}
+
+func TestTypeHooks(t *testing.T) {
+	for _, compatible := range []bool{true, false} {
+		f := NewFory(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, f.RegisterStruct(hookedUser{}, 360))
+		require.NoError(t, f.RegisterStruct(hookedGroup{}, 361))
+		require.NoError(t, f.RegisterHooks(hookedUser{}, TypeHooks{
+			BeforeSerialize: func(v any) {
+				v.(*hookedUser).Password = ""
+			},
+			AfterDeserialize: func(v any) {
+				v.(*hookedUser).loaded = true
+			},
+		}))
+		var written, read int
+		f.RegisterGlobalHooks(TypeHooks{
+			BeforeSerialize:  func(any) { written++ },
+			AfterDeserialize: func(any) { read++ },
+		})
+
+		group := &hookedGroup{
+			Owner:   hookedUser{Name: "owner", Password: "secret"},
+			Members: []hookedUser{{Name: "member", Password: "secret"}},
+			Admin:   &hookedUser{Name: "admin", Password: "secret"},
+		}
+		data, err := f.Marshal(group)
+		require.NoError(t, err)
+		require.Equal(t, "secret", group.Owner.Password, "hooks must not modify the caller's value")
+		require.Equal(t, 4, written)
+
+		var got hookedGroup
+		require.NoError(t, f.Unmarshal(data, &got))
+		require.Equal(t, 4, read)
+		for _, u := range []hookedUser{got.Owner, got.Members[0], *got.Admin} {
+			require.Empty(t, u.Password)
+			require.True(t, u.loaded)
+		}
+	}
+}
+
+func TestRegisterHooksRejectsNonStruct(t *testing.T) {
+	f := NewFory()
+	require.Error(t, f.RegisterHooks(1, TypeHooks{}))
+}
@@ -0,0 +1,262 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"iter"
+	"math/bits"
+	"reflect"
+)
+
+// iterArity returns 1 for types shaped like iter.Seq[V], 2 for types shaped
+// like iter.Seq2[K, V] and 0 otherwise.
+func iterArity(t reflect.Type) int {
+	if t.Kind() != reflect.Func || t.NumIn() != 1 || t.NumOut() != 0 || t.IsVariadic() {
+		return 0
+	}
+	yield := t.In(0)
+	if yield.Kind() != reflect.Func || yield.NumOut() != 1 || yield.Out(0).Kind() != reflect.Bool || yield.IsVariadic() {
+		return 0
+	}
+	if n := yield.NumIn(); n == 1 || n == 2 {
+		return n
+	}
+	return 0
+}
+
+// newIterTypeInfo returns the write-only type info for an iterator type, or
+// nil if t is not an iterator.
+func (r *TypeResolver) newIterTypeInfo(t reflect.Type) *TypeInfo {
+	switch iterArity(t) {
+	case 1:
+		return &TypeInfo{Type: t, TypeID: LIST, Serializer: seqSerializer{elemType: t.In(0).In(0)}}
+	case 2:
+		yield := t.In(0)
+		return &TypeInfo{Type: t, TypeID: MAP, Serializer: seq2Serializer{entries: mapSerializer{
+			keyReferencable:   isRefType(yield.In(0), r.isXlang),
+			valueReferencable: isRefType(yield.In(1), r.isXlang),
+		}}}
+	}
+	return nil
+}
+
+// writeIterRefAndType writes the ref flag and type ID of an iterator.
+// Iterators have no identity worth tracking, so a non-nil iterator is always
+// written as a fresh value.
+func writeIterRefAndType(ctx *WriteContext, refMode RefMode, writeType bool, value reflect.Value, typeId TypeId) bool {
+	buf := ctx.Buffer()
+	if refMode != RefModeNone {
+		if value.IsNil() {
+			buf.WriteInt8(NullFlag)
+			return true
+		}
+		buf.WriteInt8(NotNullValueFlag)
+	}
+	if writeType {
+		buf.WriteUint8(uint8(typeId))
+	}
+	return false
+}
+
+// putLengthPrefix stores n in the byte reserved at start, shifting everything
+// written after it when n needs a longer varuint32.
+func putLengthPrefix(buf *ByteBuffer, start int, n int) {
+	if n < 0x80 {
+		buf.PutUint8(start, uint8(n))
+		return
+	}
+	size := (bits.Len32(uint32(n)) + 6) / 7
+	end := buf.writerIndex
+	buf.grow(size - 1)
+	copy(buf.data[start+size:end+size-1], buf.data[start+1:end])
+	buf.writerIndex = start
+	buf.WriteVarUint32(uint32(n))
+	buf.writerIndex = end + size - 1
+}
+
+// seqSerializer drains an iter.Seq into a LIST without materializing it.
+// Since elements are only seen once, the header is chosen from the static
+// element type: nullable elements always carry a null flag, and interface
+// elements always carry their own type info.
+type seqSerializer struct {
+	elemType reflect.Type
+}
+
+func (s seqSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	if writeIterRefAndType(ctx, refMode, writeType, value, LIST) || ctx.HasError() {
+		return
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s seqSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	buf := ctx.Buffer()
+	start := buf.writerIndex
+	buf.WriteUint8(0)
+	n := 0
+	var flag byte
+	var elemSerializer Serializer
+	for elem := range value.Seq() {
+		if n == 0 {
+			flag, elemSerializer = s.writeHeader(ctx, elem)
+			if ctx.HasError() {
+				return
+			}
+		}
+		s.writeElem(ctx, elem, flag, elemSerializer)
+		if ctx.HasError() {
+			return
+		}
+		n++
+	}
+	putLengthPrefix(buf, start, n)
+}
+
+func (s seqSerializer) writeHeader(ctx *WriteContext, first reflect.Value) (byte, Serializer) {
+	buf := ctx.Buffer()
+	flag := byte(CollectionDefaultFlag)
+	if isReferencable(s.elemType) {
+		flag |= CollectionHasNull
+	}
+	if s.elemType.Kind() == reflect.Interface {
+		if ctx.TrackRef() {
+			flag |= CollectionTrackingRef
+		}
+		buf.WriteInt8(int8(flag))
+		return flag, nil
+	}
+	typeInfo, err := ctx.TypeResolver().getTypeInfo(first, true)
+	if err != nil {
+		ctx.SetError(FromError(err))
+		return 0, nil
+	}
+	flag |= CollectionIsSameType
+	if ctx.TrackRef() && typeInfo.NeedWriteRef {
+		flag |= CollectionTrackingRef
+	}
+	buf.WriteInt8(int8(flag))
+	ctx.TypeResolver().WriteTypeInfo(buf, typeInfo, ctx.Err())
+	return flag, typeInfo.Serializer
+}
+
+func (s seqSerializer) writeElem(ctx *WriteContext, elem reflect.Value, flag byte, serializer Serializer) {
+	buf := ctx.Buffer()
+	trackRefs := (flag & CollectionTrackingRef) != 0
+	if serializer == nil {
+		refMode := RefModeNullOnly
+		if trackRefs {
+			refMode = RefModeTracking
+		}
+		ctx.WriteValue(elem, refMode, true)
+		return
+	}
+	switch {
+	case trackRefs:
+		serializer.Write(ctx, RefModeTracking, false, false, elem)
+	case (flag & CollectionHasNull) != 0:
+		if isNull(elem) {
+			buf.WriteInt8(NullFlag)
+			return
+		}
+		buf.WriteInt8(NotNullValueFlag)
+		serializer.WriteData(ctx, elem)
+	default:
+		serializer.WriteData(ctx, elem)
+	}
+}
+
+func (s seqSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+func (s seqSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+func (s seqSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+// seq2Serializer drains an iter.Seq2 into a MAP using the same chunk layout
+// as a map with dynamic key and value types.
+type seq2Serializer struct {
+	entries mapSerializer
+}
+
+// seqEntryIter adapts a pulled iter.Seq2 to mapEntryIter and counts the
+// entries it yields.
+type seqEntryIter struct {
+	next       func() (reflect.Value, reflect.Value, bool)
+	key, value reflect.Value
+	count      int
+}
+
+func (it *seqEntryIter) Next() bool {
+	k, v, ok := it.next()
+	if !ok {
+		return false
+	}
+	it.key, it.value = k, v
+	it.count++
+	return true
+}
+
+func (it *seqEntryIter) Key() reflect.Value {
+	return it.key
+}
+
+func (it *seqEntryIter) Value() reflect.Value {
+	return it.value
+}
+
+func (s seq2Serializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	if writeIterRefAndType(ctx, refMode, writeType, value, MAP) || ctx.HasError() {
+		return
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s seq2Serializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	buf := ctx.Buffer()
+	start := buf.writerIndex
+	buf.WriteUint8(0)
+	next, stop := iter.Pull2(value.Seq2())
+	defer stop()
+	entries := &seqEntryIter{next: next}
+	s.entries.writeEntries(ctx, entries)
+	if ctx.HasError() {
+		return
+	}
+	putLengthPrefix(buf, start, entries.count)
+}
+
+func (s seq2Serializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+func (s seq2Serializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+func (s seq2Serializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	setIterReadError(ctx, value)
+}
+
+func setIterReadError(ctx *ReadContext, value reflect.Value) {
+	ctx.SetError(DeserializationErrorf("cannot deserialize into iterator type %s; decode into a slice or map instead", value.Type()))
+}
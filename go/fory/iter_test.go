@@ -0,0 +1,136 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"iter"
+	"maps"
+	"slices"
+	"strconv"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type iterItem struct {
+	ID   int32
+	Name string
+}
+
+func TestSerializeSeq(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		for _, trackRef := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithTrackRef(trackRef))
+			require.NoError(t, f.RegisterStruct(iterItem{}, 1))
+
+			strs := make([]string, 300)
+			for i := range strs {
+				strs[i] = strconv.Itoa(i)
+			}
+			data, err := f.Marshal(slices.Values(strs))
+			require.NoError(t, err)
+			expected, err := f.Marshal(strs)
+			require.NoError(t, err)
+			require.Equal(t, expected, data, "xlang=%v trackRef=%v", xlang, trackRef)
+			var strResult []string
+			require.NoError(t, f.Unmarshal(data, &strResult))
+			require.Equal(t, strs, strResult)
+
+			items := []*iterItem{{ID: 1, Name: "a"}, nil, {ID: 3, Name: "c"}}
+			data, err = f.Marshal(slices.Values(items))
+			require.NoError(t, err)
+			var itemResult []*iterItem
+			require.NoError(t, f.Unmarshal(data, &itemResult), "xlang=%v trackRef=%v", xlang, trackRef)
+			require.Equal(t, items, itemResult)
+
+			dynamic := []any{"a", int64(2), nil, iterItem{ID: 4}}
+			data, err = f.Marshal(slices.Values(dynamic))
+			require.NoError(t, err)
+			var dynResult []any
+			require.NoError(t, f.Unmarshal(data, &dynResult), "xlang=%v trackRef=%v", xlang, trackRef)
+			require.Equal(t, dynamic, dynResult)
+
+			data, err = f.Marshal(slices.Values([]string{}))
+			require.NoError(t, err)
+			strResult = nil
+			require.NoError(t, f.Unmarshal(data, &strResult))
+			require.Empty(t, strResult)
+		}
+	}
+}
+
+func TestSerializeSeq2(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		f := NewFory(WithXlang(xlang))
+		require.NoError(t, f.RegisterStruct(iterItem{}, 1))
+
+		counts := make(map[string]int64, 300)
+		for i := range 300 {
+			counts[strconv.Itoa(i)] = int64(i)
+		}
+		data, err := f.Marshal(maps.All(counts))
+		require.NoError(t, err)
+		var countResult map[string]int64
+		require.NoError(t, f.Unmarshal(data, &countResult), "xlang=%v", xlang)
+		require.Equal(t, counts, countResult)
+
+		items := map[string]any{"a": iterItem{ID: 1}, "b": "str", "c": nil}
+		data, err = f.Marshal(maps.All(items))
+		require.NoError(t, err)
+		var itemResult map[string]any
+		require.NoError(t, f.Unmarshal(data, &itemResult), "xlang=%v", xlang)
+		require.Equal(t, items, itemResult)
+
+		data, err = f.Marshal(maps.All(map[string]int64{}))
+		require.NoError(t, err)
+		countResult = nil
+		require.NoError(t, f.Unmarshal(data, &countResult))
+		require.Empty(t, countResult)
+	}
+}
+
+func TestSerializeSeqStopsEarly(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	produced := 0
+	var seq iter.Seq[iterItem] = func(yield func(iterItem) bool) {
+		for i := range 10 {
+			produced++
+			if !yield(iterItem{ID: int32(i)}) {
+				return
+			}
+		}
+	}
+	_, err := f.Marshal(seq)
+	require.Error(t, err)
+	require.Equal(t, 1, produced)
+}
+
+func TestSerializeSeqInDynamicValue(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	value := []any{slices.Values([]int64{1, 2}), maps.All(map[string]string{"k": "v"})}
+	data, err := f.Marshal(value)
+	require.NoError(t, err)
+	var result []any
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, []any{[]any{int64(1), int64(2)}, map[any]any{"k": "v"}}, result)
+
+	var seq iter.Seq[int64]
+	require.Error(t, f.Unmarshal(data, &seq))
+}
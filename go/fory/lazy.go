@@ -0,0 +1,61 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Lazy decodes the top-level fields of a serialized struct on demand.
+// Each Get decodes only the requested field and skips the bytes of the others,
+// so reading one field of a large record does not pay for the rest. Decoded
+// fields are cached. A Lazy shares its Fory instance and is not safe for
+// concurrent use.
+type Lazy[T any] struct {
+	f       *Fory
+	data    []byte
+	value   T
+	decoded map[string]bool
+}
+
+// NewLazy returns a handle over data, which must hold a serialized T.
+// T must be a struct type. data is not copied and must stay unmodified while
+// the handle is in use. Reference tracking must be disabled on f.
+func NewLazy[T any](f *Fory, data []byte) *Lazy[T] {
+	return &Lazy[T]{f: f, data: data, decoded: make(map[string]bool)}
+}
+
+// Get returns the value of the named top-level field, decoding it on first use.
+func (l *Lazy[T]) Get(name string) (any, error) {
+	value := reflect.ValueOf(&l.value).Elem()
+	if value.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("lazy decoding requires a struct type, got %s", value.Type())
+	}
+	field, ok := value.Type().FieldByName(name)
+	if !ok || len(field.Index) != 1 {
+		return nil, fmt.Errorf("struct %s has no field %s", value.Type(), name)
+	}
+	if !l.decoded[name] {
+		if err := l.f.UnmarshalProjected(l.data, &l.value, NewProjection(name)); err != nil {
+			return nil, err
+		}
+		l.decoded[name] = true
+	}
+	return value.Field(field.Index[0]).Interface(), nil
+}
@@ -0,0 +1,105 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type lazyNested struct {
+	Label string
+}
+
+type lazyRecord struct {
+	F1 int32
+	F2 int64
+	F3 float64
+	F4 string
+	F5 []int32
+	F6 map[string]int64
+	F7 *lazyNested
+	F8 bool
+}
+
+func newLazyRecord() *lazyRecord {
+	return &lazyRecord{
+		F1: 7,
+		F2: -1 << 40,
+		F3: 2.5,
+		F4: "needle",
+		F5: []int32{1, 2, 3},
+		F6: map[string]int64{"a": 1},
+		F7: &lazyNested{Label: "inner"},
+		F8: true,
+	}
+}
+
+func TestLazyGet(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithXlang(true)},
+		{WithXlang(true), WithCompatible(false)},
+		{WithXlang(false), WithCompatible(false)},
+	} {
+		f := NewFory(opts...)
+		require.NoError(t, f.RegisterStruct(lazyNested{}, 310))
+		require.NoError(t, f.RegisterStruct(lazyRecord{}, 311))
+		want := newLazyRecord()
+		data, err := f.Marshal(want)
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+
+		lazy := NewLazy[lazyRecord](f, data)
+		v, err := lazy.Get("F4")
+		require.NoError(t, err)
+		require.Equal(t, "needle", v)
+		require.Equal(t, lazyRecord{F4: "needle"}, lazy.value, "only F4 must be decoded")
+
+		for name, expected := range map[string]any{
+			"F1": want.F1, "F2": want.F2, "F3": want.F3, "F5": want.F5,
+			"F6": want.F6, "F7": want.F7, "F8": want.F8,
+		} {
+			v, err := lazy.Get(name)
+			require.NoError(t, err, name)
+			require.Equal(t, expected, v, name)
+		}
+		require.Equal(t, *want, lazy.value)
+	}
+}
+
+func TestLazyErrors(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(lazyNested{}, 310))
+	require.NoError(t, f.RegisterStruct(lazyRecord{}, 311))
+	data, err := f.Marshal(newLazyRecord())
+	require.NoError(t, err)
+
+	_, err = NewLazy[lazyRecord](f, data).Get("Missing")
+	require.Error(t, err)
+	_, err = NewLazy[int32](f, data).Get("F1")
+	require.Error(t, err)
+
+	tracking := NewFory(WithXlang(true), WithTrackRef(true))
+	require.NoError(t, tracking.RegisterStruct(lazyNested{}, 310))
+	require.NoError(t, tracking.RegisterStruct(lazyRecord{}, 311))
+	_, err = NewLazy[lazyRecord](tracking, data).Get("F1")
+	require.Error(t, err)
+}
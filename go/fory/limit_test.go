@@ -15,11 +15,16 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
-	"github.com/stretchr/testify/require"
+	"errors"
+	"reflect"
 	"testing"
+
+	"github.com/stretchr/testify/require"
 )
 
 func TestMaxCollectionSizeGuardrail(t *testing.T) {
@@ -101,3 +106,80 @@ func TestMaxBinarySizeGuardrail(t *testing.T) {
 		require.Equal(t, str, decoded)
 	})
 }
+
+type budgetRecord struct {
+	ID                     int64
+	A, B, C, D, E, F, G, H []int64
+	Next                   *budgetRecord
+}
+
+func TestDecodeBudget(t *testing.T) {
+	fBase := NewFory(WithXlang(false), WithCompatible(false))
+	require.NoError(t, fBase.RegisterStruct(budgetRecord{}, 1001))
+	newBudgeted := func(budget int) *Fory {
+		f := NewFory(WithXlang(false), WithCompatible(false), WithDecodeBudget(budget))
+		require.NoError(t, f.RegisterStruct(budgetRecord{}, 1001))
+		return f
+	}
+	recordSize := int(reflect.TypeOf(budgetRecord{}).Size())
+	requireBudgetExceeded := func(t *testing.T, err error) {
+		t.Helper()
+		require.Error(t, err)
+		require.True(t, errors.Is(err, ErrBudgetExceeded), "%v", err)
+		var foryErr Error
+		require.True(t, errors.As(err, &foryErr))
+		require.Equal(t, ErrKindBudgetExceeded, foryErr.Kind())
+	}
+
+	t.Run("Slice of structs", func(t *testing.T) {
+		// Each zero record encodes in a few bytes but decodes to hundreds.
+		records := make([]budgetRecord, 1000)
+		data, err := fBase.Serialize(records)
+		require.NoError(t, err)
+		// Half the decoded size is still well above the payload size.
+		budget := 1000 * recordSize / 2
+		require.Less(t, len(data), budget)
+		var decoded []budgetRecord
+		requireBudgetExceeded(t, newBudgeted(budget).Deserialize(data, &decoded))
+		require.NoError(t, newBudgeted(1<<20).Deserialize(data, &decoded))
+		require.Len(t, decoded, 1000)
+	})
+
+	t.Run("Pointer chain", func(t *testing.T) {
+		var head *budgetRecord
+		for i := 0; i < 20; i++ {
+			head = &budgetRecord{ID: int64(i), Next: head}
+		}
+		data, err := fBase.Serialize(head)
+		require.NoError(t, err)
+		var decoded *budgetRecord
+		requireBudgetExceeded(t, newBudgeted(10*recordSize).Deserialize(data, &decoded))
+		require.NoError(t, newBudgeted(30*recordSize).Deserialize(data, &decoded))
+		require.Equal(t, head, decoded)
+	})
+
+	t.Run("Strings, binary and maps", func(t *testing.T) {
+		for _, value := range []any{
+			[]string{"alpha", "beta", "gamma"},
+			make([]byte, 1000),
+			map[string]int64{"a": 1, "b": 2, "c": 3},
+		} {
+			data, err := fBase.Serialize(value)
+			require.NoError(t, err)
+			target := reflect.New(reflect.TypeOf(value))
+			requireBudgetExceeded(t, newBudgeted(16).Deserialize(data, target.Interface()))
+			require.NoError(t, newBudgeted(4096).Deserialize(data, target.Interface()))
+			require.Equal(t, value, target.Elem().Interface())
+		}
+	})
+
+	t.Run("Budget applies per call", func(t *testing.T) {
+		f := newBudgeted(1500)
+		data, err := fBase.Serialize(make([]byte, 1000))
+		require.NoError(t, err)
+		for i := 0; i < 3; i++ {
+			var decoded []byte
+			require.NoError(t, f.Deserialize(data, &decoded))
+		}
+	})
+}
@@ -65,14 +65,32 @@ func (s mapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	value = unwrapInterface(value)
 	length := value.Len()
 	buf.WriteVarUint32(uint32(length))
+	ctx.countWork(length)
 	if length == 0 {
 		return
 	}
 
-	iter := value.MapRange()
+	if p := ctx.parallel; p != nil {
+		ctx.parallel = nil
+		p.writeMap(ctx, value)
+		return
+	}
+
+	var iter mapEntryIter
+	if ctx.sortMapKeys {
+		iter = newSortedMapIter(value)
+	} else {
+		iter = value.MapRange()
+	}
+	s.writeEntries(ctx, iter)
+}
+
+// writeEntries writes the chunks holding every entry left in iter.
+func (s mapSerializer) writeEntries(ctx *WriteContext, iter mapEntryIter) {
 	if !iter.Next() {
 		return
 	}
+	buf := ctx.Buffer()
 
 	typeResolver := ctx.TypeResolver()
 	trackRef := ctx.TrackRef()
@@ -118,11 +136,30 @@ func (s mapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	}
 }
 
+// keyDeclared reports whether chunks may omit key type info. Like list
+// elements, user structs and extension types keep theirs even when declared,
+// so the reader can resolve the remote schema.
+func (s mapSerializer) keyDeclared(resolver *TypeResolver) bool {
+	return s.hasGenerics && s.keySerializer != nil && isMonomorphicType(resolver, s.type_.Key())
+}
+
+// valueDeclared is keyDeclared for map values.
+func (s mapSerializer) valueDeclared(resolver *TypeResolver) bool {
+	return s.hasGenerics && s.valueSerializer != nil && isMonomorphicType(resolver, s.type_.Elem())
+}
+
+// isMonomorphicType reports whether a declared key, value or element type
+// fully determines its type info on the wire.
+func isMonomorphicType(resolver *TypeResolver, t reflect.Type) bool {
+	info, _ := resolver.getTypeInfo(reflect.New(t).Elem(), false)
+	return info == nil || !needsElemTypeInfo(TypeId(info.TypeID))
+}
+
 // writeNullValueEntry writes a single entry where the value is null
 func (s mapSerializer) writeNullValueEntry(ctx *WriteContext, key reflect.Value, resolver *TypeResolver, trackRef bool) {
 	buf := ctx.Buffer()
 
-	if s.hasGenerics && s.keySerializer != nil {
+	if s.keyDeclared(resolver) {
 		if s.keyReferencable && trackRef {
 			buf.WriteInt8(NULL_VALUE_KEY_DECL_TYPE_TRACKING_REF)
 			s.keySerializer.Write(ctx, RefModeTracking, false, true, key)
@@ -159,7 +196,7 @@ func (s mapSerializer) writeNullValueEntry(ctx *WriteContext, key reflect.Value,
 func (s mapSerializer) writeNullKeyEntry(ctx *WriteContext, value reflect.Value, resolver *TypeResolver, trackRef bool) {
 	buf := ctx.Buffer()
 
-	if s.hasGenerics && s.valueSerializer != nil {
+	if s.valueDeclared(resolver) {
 		if s.valueReferencable && trackRef {
 			buf.WriteInt8(NULL_KEY_VALUE_DECL_TYPE_TRACKING_REF)
 			s.valueSerializer.Write(ctx, RefModeTracking, false, true, value)
@@ -193,7 +230,7 @@ func (s mapSerializer) writeNullKeyEntry(ctx *WriteContext, value reflect.Value,
 }
 
 // writeChunk writes a chunk of entries with the same key/value types
-func (s mapSerializer) writeChunk(ctx *WriteContext, iter *reflect.MapIter, entryKey, entryVal *reflect.Value, resolver *TypeResolver, trackRef bool) bool {
+func (s mapSerializer) writeChunk(ctx *WriteContext, iter mapEntryIter, entryKey, entryVal *reflect.Value, resolver *TypeResolver, trackRef bool) bool {
 	buf := ctx.Buffer()
 	keyType := (*entryKey).Type()
 	valueType := (*entryVal).Type()
@@ -208,22 +245,30 @@ func (s mapSerializer) writeChunk(ctx *WriteContext, iter *reflect.MapIter, entr
 	valueWriteRef := s.valueReferencable
 
 	// Determine key serializer and write type info if needed
-	if s.hasGenerics && s.keySerializer != nil {
+	if s.keyDeclared(resolver) {
 		header |= KEY_DECL_TYPE
 		keySer = s.keySerializer
 	} else {
-		keyTypeInfo, _ := getTypeInfoForValue(*entryKey, resolver)
+		keyTypeInfo, err := getTypeInfoForValue(*entryKey, resolver)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return false
+		}
 		resolver.WriteTypeInfo(buf, keyTypeInfo, ctx.Err())
 		keySer = keyTypeInfo.Serializer
 		keyWriteRef = s.keyReferencable && keyTypeInfo.NeedWriteRef
 	}
 
 	// Determine value serializer and write type info if needed
-	if s.hasGenerics && s.valueSerializer != nil {
+	if s.valueDeclared(resolver) {
 		header |= VALUE_DECL_TYPE
 		valSer = s.valueSerializer
 	} else {
-		valueTypeInfo, _ := getTypeInfoForValue(*entryVal, resolver)
+		valueTypeInfo, err := getTypeInfoForValue(*entryVal, resolver)
+		if err != nil {
+			ctx.SetError(FromError(err))
+			return false
+		}
 		resolver.WriteTypeInfo(buf, valueTypeInfo, ctx.Err())
 		valSer = valueTypeInfo.Serializer
 		valueWriteRef = s.valueReferencable && valueTypeInfo.NeedWriteRef
@@ -241,8 +286,18 @@ func (s mapSerializer) writeChunk(ctx *WriteContext, iter *reflect.MapIter, entr
 		valueRefMode = RefModeTracking
 	}
 
+	// Keys without identity are tracked by value (WithMapKeyRefTracking).
+	trackKeyValue := trackRef && ctx.trackMapKeyRef && !keyWriteRef &&
+		(keyType.Kind() == reflect.String || keyType.Kind() == reflect.Struct)
+	if trackKeyValue {
+		header |= TRACKING_KEY_REF
+	}
+
 	buf.PutUint8(headerOffset, uint8(header))
 
+	// Records of different structs share a Go type but not their type info.
+	recordTypeDef := genericRecordTypeDef(*entryVal)
+
 	// Write entries with same type
 	chunkSize := 0
 	for chunkSize < MAX_CHUNK_SIZE {
@@ -253,8 +308,18 @@ func (s mapSerializer) writeChunk(ctx *WriteContext, iter *reflect.MapIter, entr
 		if !k.IsValid() || !v.IsValid() || k.Type() != keyType || v.Type() != valueType {
 			break
 		}
+		if recordTypeDef != nil && genericRecordTypeDef(v) != recordTypeDef {
+			break
+		}
 
-		keySer.Write(ctx, keyRefMode, false, (header&KEY_DECL_TYPE) != 0, k)
+		if !trackKeyValue {
+			keySer.Write(ctx, keyRefMode, false, (header&KEY_DECL_TYPE) != 0, k)
+		} else if !k.CanInterface() {
+			buf.WriteInt8(NotNullValueFlag)
+			keySer.Write(ctx, RefModeNone, false, (header&KEY_DECL_TYPE) != 0, k)
+		} else if !ctx.refResolver.writeValueRef(buf, k.Interface()) {
+			keySer.Write(ctx, RefModeNone, false, (header&KEY_DECL_TYPE) != 0, k)
+		}
 		if ctx.HasError() {
 			return false
 		}
@@ -302,6 +367,8 @@ func (s mapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 			mapType = reflect.MapOf(iface, iface)
 		}
 		value.Set(reflect.MakeMap(mapType))
+	} else if ctx.reuseObjects {
+		value.Clear()
 	}
 	refResolver.Reference(value)
 
@@ -309,15 +376,17 @@ func (s mapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	if size == 0 || ctx.HasError() {
 		return
 	}
+	keyType := type_.Key()
+	valueType := type_.Elem()
+	if !ctx.charge(size * int(keyType.Size()+valueType.Size())) {
+		return
+	}
 
 	chunkHeader := buf.ReadUint8(ctxErr)
 	if ctx.HasError() {
 		return
 	}
 
-	keyType := type_.Key()
-	valueType := type_.Elem()
-
 	for size > 0 {
 		// Phase 1: Handle null entries
 		for {
@@ -414,6 +483,8 @@ func (s mapSerializer) readSingleValue(ctx *ReadContext, buf *ByteBuffer, ctxErr
 			valType = staticType
 		}
 		valType, ser = wrapMapSerializerIfNeeded(staticType, valType, ser)
+		valType, ser = declaredEntryType(staticType, valType, ser, declaredSer, resolver)
+		valType, ser = trackedStructPtr(staticType, valType, ser, true)
 		v := reflect.New(valType).Elem()
 		ser.ReadData(ctx, v)
 		if ctx.HasError() {
@@ -438,6 +509,7 @@ func (s mapSerializer) readSingleValue(ctx *ReadContext, buf *ByteBuffer, ctxErr
 		ser = typeInfo.Serializer
 		valType = typeInfo.Type
 		valType, ser = wrapMapSerializerIfNeeded(staticType, valType, ser)
+		valType, ser = declaredEntryType(staticType, valType, ser, declaredSer, resolver)
 	} else {
 		ser = declaredSer
 		if ser == nil {
@@ -501,6 +573,10 @@ func (s mapSerializer) readChunk(ctx *ReadContext, mapVal reflect.Value, header
 		keySer = keyTypeInfo.Serializer
 		keyType = keyTypeInfo.Type
 		keyType, keySer = wrapMapSerializerIfNeeded(declaredKeyType, keyType, keySer)
+		keyType, keySer = declaredEntryType(declaredKeyType, keyType, keySer, s.keySerializer, resolver)
+		if keyType == nil {
+			keyType = declaredKeyType
+		}
 	} else {
 		keySer = s.keySerializer
 		if keySer == nil {
@@ -516,6 +592,11 @@ func (s mapSerializer) readChunk(ctx *ReadContext, mapVal reflect.Value, header
 		valSer = valueTypeInfo.Serializer
 		valueType = valueTypeInfo.Type
 		valueType, valSer = wrapMapSerializerIfNeeded(declaredValueType, valueType, valSer)
+		valueType, valSer = declaredEntryType(declaredValueType, valueType, valSer, s.valueSerializer, resolver)
+		if valueType == nil {
+			valueType = declaredValueType
+		}
+		valueType, valSer = trackedStructPtr(declaredValueType, valueType, valSer, trackValRef)
 	} else {
 		valSer = s.valueSerializer
 		if valSer == nil {
@@ -532,9 +613,15 @@ func (s mapSerializer) readChunk(ctx *ReadContext, mapVal reflect.Value, header
 		valRefMode = RefModeTracking
 	}
 
+	// String and struct keys written with WithMapKeyRefTracking refer back to
+	// earlier keys by value.
+	keyByValue := trackKeyRef && (keyType.Kind() == reflect.String || keyType.Kind() == reflect.Struct)
+
 	for i := 0; i < chunkSize; i++ {
 		k := reflect.New(keyType).Elem()
-		if keyTypeInfo != nil {
+		if keyByValue {
+			readValueKey(ctx, keySer, keyDeclType, k)
+		} else if keyTypeInfo != nil {
 			keySer.ReadWithTypeInfo(ctx, keyRefMode, keyTypeInfo, k)
 		} else {
 			keySer.Read(ctx, keyRefMode, false, keyDeclType, k)
@@ -553,7 +640,11 @@ func (s mapSerializer) readChunk(ctx *ReadContext, mapVal reflect.Value, header
 			return 0
 		}
 
-		setMapValue(mapVal, unwrapInterface(k), unwrapInterface(v))
+		key, val := unwrapInterface(k), unwrapInterface(v)
+		if key.IsValid() && val.IsValid() {
+			setMapValue(mapVal, key, val)
+		}
+		// Otherwise the key or value has an unregistered type and was skipped.
 		size--
 	}
 
@@ -564,6 +655,37 @@ func (s mapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeI
 	s.Read(ctx, refMode, false, false, value)
 }
 
+// readValueKey reads a key tracked by value into key: either a reference to
+// an equal key read earlier or the key data, which later keys may refer to.
+func readValueKey(ctx *ReadContext, keySer Serializer, declared bool, key reflect.Value) {
+	refResolver := ctx.RefResolver()
+	refID, err := refResolver.TryPreserveRefId(ctx.buffer)
+	if err != nil {
+		ctx.SetError(FromError(err))
+		return
+	}
+	if refID < int32(NotNullValueFlag) {
+		obj := refResolver.GetReadObject(refID)
+		if !obj.IsValid() || obj.Type() != key.Type() {
+			ctx.SetError(DeserializationError("unresolved map key reference; reading tracked map keys requires reference tracking"))
+			return
+		}
+		key.Set(obj)
+		return
+	}
+	keySer.Read(ctx, RefModeNone, false, declared, key)
+	if refID >= 0 {
+		refResolver.SetReadObject(refID, key)
+	}
+}
+
+// readTrackedString is readValueKey for the typed string-keyed map readers.
+func readTrackedString(ctx *ReadContext) string {
+	var key string
+	readValueKey(ctx, globalStringSerializer, true, reflect.ValueOf(&key).Elem())
+	return key
+}
+
 // Helper functions
 
 // writeMapRefAndType handles reference and type writing for maps.
@@ -662,6 +784,90 @@ func wrapMapSerializerIfNeeded(declaredType, actualType reflect.Type, serializer
 	return actualType, serializer
 }
 
+// trackedStructPtr reads a ref-tracked struct held in an interface value as a
+// pointer, like the elements of []any. The pointer is registered before the
+// fields are read, so references to it, including cycles back to an enclosing
+// struct, resolve to the same *T.
+func trackedStructPtr(declaredType, actualType reflect.Type, serializer Serializer, trackRef bool) (reflect.Type, Serializer) {
+	if !trackRef || declaredType.Kind() != reflect.Interface || actualType.Kind() != reflect.Struct {
+		return actualType, serializer
+	}
+	if _, ok := serializer.(*structSerializer); !ok {
+		return actualType, serializer
+	}
+	return reflect.PtrTo(actualType), &ptrToValueSerializer{valueSerializer: serializer}
+}
+
+// declaredEntryType reads a nested container into the declared key or value
+// type when its wire type info resolves to a generic Go type, e.g. MAP to
+// map[any]any for the values of a map[string]map[string]int64.
+func declaredEntryType(declaredType, actualType reflect.Type, serializer, declaredSer Serializer,
+	resolver *TypeResolver) (reflect.Type, Serializer) {
+	if declaredType == nil || actualType == nil || declaredType.Kind() == reflect.Interface ||
+		actualType.Kind() != declaredType.Kind() || actualType.AssignableTo(declaredType) {
+		return actualType, serializer
+	}
+	if declaredSer == nil {
+		var err error
+		if declaredSer, err = resolver.getSerializerByType(declaredType, false); err != nil {
+			return actualType, serializer
+		}
+	}
+	return declaredType, declaredSer
+}
+
+// checkXlangMapKey rejects struct map keys that other languages cannot hash.
+// Go compares struct keys field by field; peers hash the fields of their
+// struct type, and xlang map keys must not hold floating-point or
+// collection-shaped values.
+func checkXlangMapKey(keyType reflect.Type) error {
+	if field, fieldType := unhashableKeyField(keyType, nil); fieldType != nil {
+		return fmt.Errorf("map key type %s cannot be serialized in xlang mode: field %s has type %s, "+
+			"which cannot be part of a map key in other languages; use native mode for Go-only maps",
+			keyType, field, fieldType)
+	}
+	return nil
+}
+
+// unhashableKeyField returns the path and type of the first exported field of
+// the struct key type t that is floating-point or an array, or nil if there is
+// none.
+func unhashableKeyField(t reflect.Type, visited map[reflect.Type]bool) (string, reflect.Type) {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t.Kind() != reflect.Struct || visited[t] {
+		return "", nil
+	}
+	if visited == nil {
+		visited = map[reflect.Type]bool{}
+	}
+	visited[t] = true
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.PkgPath != "" {
+			continue
+		}
+		if parsed, err := parseFieldTag(field); err == nil && parsed.ignore {
+			continue
+		}
+		ft := field.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		switch {
+		case ft.Kind() == reflect.Float32, ft.Kind() == reflect.Float64, ft == float16Type, ft == bfloat16Type,
+			ft.Kind() == reflect.Array:
+			return field.Name, field.Type
+		case ft.Kind() == reflect.Struct:
+			if name, nested := unhashableKeyField(ft, visited); nested != nil {
+				return field.Name + "." + name, nested
+			}
+		}
+	}
+	return "", nil
+}
+
 // UnwrapReflectValue is exported for use by other packages
 func UnwrapReflectValue(v reflect.Value) reflect.Value {
 	return unwrapInterface(v)
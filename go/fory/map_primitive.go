@@ -18,62 +18,123 @@
 package fory
 
 import (
+	"cmp"
 	"reflect"
+	"slices"
+	"unsafe"
 )
 
 // ============================================================================
 // Optimized map serializers for common types
 // ============================================================================
 
+// writePrimitiveMapChunkHeader starts a chunk of size entries. Without
+// generics the key and value type IDs follow for generic readers.
+func writePrimitiveMapChunkHeader(buf *ByteBuffer, size int, hasGenerics bool, keyType, valueType TypeId) {
+	writePrimitiveMapChunkHeaderWithFlags(buf, size, 0, hasGenerics, keyType, valueType)
+}
+
+func writePrimitiveMapChunkHeaderWithFlags(buf *ByteBuffer, size int, flags uint8, hasGenerics bool, keyType, valueType TypeId) {
+	if hasGenerics {
+		buf.WriteUint8(flags | KEY_DECL_TYPE | VALUE_DECL_TYPE)
+		buf.WriteUint8(uint8(size))
+		return
+	}
+	buf.WriteUint8(flags)
+	buf.WriteUint8(uint8(size))
+	buf.WriteUint8(uint8(keyType))
+	buf.WriteUint8(uint8(valueType))
+}
+
+// writeOrderedPrimitiveMap writes the entries of m, in ascending key order
+// for WithSortedMaps, and with string keys written as references for
+// WithMapKeyRefTracking. The map length has already been written.
+func writeOrderedPrimitiveMap[K cmp.Ordered, V any](ctx *WriteContext, m map[K]V, hasGenerics bool, keyType, valueType TypeId,
+	writeKey func(*ByteBuffer, K), writeValue func(*ByteBuffer, V)) {
+	buf := ctx.buffer
+	keys := make([]K, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	if ctx.sortMapKeys {
+		slices.Sort(keys)
+	}
+	var flags uint8
+	trackKeys := ctx.trackMapKeyRef && keyType == STRING
+	if trackKeys {
+		flags = TRACKING_KEY_REF
+	}
+	for len(keys) > 0 {
+		chunk := keys[:min(len(keys), MAX_CHUNK_SIZE)]
+		keys = keys[len(chunk):]
+		writePrimitiveMapChunkHeaderWithFlags(buf, len(chunk), flags, hasGenerics, keyType, valueType)
+		for _, k := range chunk {
+			if !trackKeys || !ctx.refResolver.writeValueRef(buf, k) {
+				writeKey(buf, k)
+			}
+			writeValue(buf, m[k])
+		}
+	}
+}
+
+func writeVarint32(buf *ByteBuffer, v int32) {
+	buf.WriteVarint32(v)
+}
+
+func writeVarintInt(buf *ByteBuffer, v int) {
+	buf.WriteVarint64(int64(v))
+}
+
 // writeMapStringString writes map[string]string using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringString(buf *ByteBuffer, m map[string]string, hasGenerics bool) {
+func writeMapStringString(ctx *WriteContext, m map[string]string, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, STRING, ctx.stringWriter(), ctx.stringWriter())
+		return
+	}
 
-	// WriteData chunks of entries
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			// Element types are known from generics, set DECL_TYPE flags
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			// Write type info for generic reader compatibility
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING)) // key type
-			buf.WriteUint8(uint8(STRING)) // value type
-		}
-
-		// WriteData chunk entries
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			writeString(buf, v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, STRING)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		writeString(buf, v)
+		chunkLeft--
 	}
 }
 
+// reuseMap returns dst cleared for refilling when object reuse is enabled,
+// otherwise a new map sized for n entries.
+func reuseMap[K comparable, V any](ctx *ReadContext, dst map[K]V, n int) map[K]V {
+	var entry struct {
+		key   K
+		value V
+	}
+	if !ctx.charge(n * int(unsafe.Sizeof(entry))) {
+		return dst
+	}
+	if ctx.reuseObjects && dst != nil {
+		clear(dst)
+		return dst
+	}
+	return make(map[K]V, n)
+}
+
 // readMapStringString reads map[string]string using chunk protocol
-func readMapStringString(ctx *ReadContext) map[string]string {
+func readMapStringString(ctx *ReadContext, dst map[string]string) map[string]string {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]string, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
@@ -81,7 +142,7 @@ func readMapStringString(ctx *ReadContext) map[string]string {
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
 
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -106,9 +167,15 @@ func readMapStringString(ctx *ReadContext) map[string]string {
 			}
 		}
 
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
-			v := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
+			v := ctx.ReadString()
 			result[k] = v
 			size--
 		}
@@ -118,56 +185,44 @@ func readMapStringString(ctx *ReadContext) map[string]string {
 
 // writeMapStringInt64 writes map[string]int64 using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringInt64(buf *ByteBuffer, m map[string]int64, hasGenerics bool) {
+func writeMapStringInt64(ctx *WriteContext, m map[string]int64, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, VARINT64, ctx.stringWriter(), (*ByteBuffer).WriteVarint64)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING))   // key type
-			buf.WriteUint8(uint8(VARINT64)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			buf.WriteVarint64(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, VARINT64)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		buf.WriteVarint64(v)
+		chunkLeft--
 	}
 }
 
 // readMapStringInt64 reads map[string]int64 using chunk protocol
-func readMapStringInt64(ctx *ReadContext) map[string]int64 {
+func readMapStringInt64(ctx *ReadContext, dst map[string]int64) map[string]int64 {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]int64, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
 
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -190,8 +245,14 @@ func readMapStringInt64(ctx *ReadContext) map[string]int64 {
 				return result
 			}
 		}
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
 			v := buf.ReadVarint64(err)
 			result[k] = v
 			size--
@@ -202,56 +263,44 @@ func readMapStringInt64(ctx *ReadContext) map[string]int64 {
 
 // writeMapStringInt32 writes map[string]int32 using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringInt32(buf *ByteBuffer, m map[string]int32, hasGenerics bool) {
+func writeMapStringInt32(ctx *WriteContext, m map[string]int32, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, VARINT32, ctx.stringWriter(), writeVarint32)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING))   // key type
-			buf.WriteUint8(uint8(VARINT32)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			buf.WriteVarint32(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, VARINT32)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		buf.WriteVarint32(v)
+		chunkLeft--
 	}
 }
 
 // readMapStringInt32 reads map[string]int32 using chunk protocol
-func readMapStringInt32(ctx *ReadContext) map[string]int32 {
+func readMapStringInt32(ctx *ReadContext, dst map[string]int32) map[string]int32 {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]int32, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
 
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -274,8 +323,14 @@ func readMapStringInt32(ctx *ReadContext) map[string]int32 {
 				return result
 			}
 		}
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
 			v := buf.ReadVarint32(err)
 			result[k] = v
 			size--
@@ -286,56 +341,44 @@ func readMapStringInt32(ctx *ReadContext) map[string]int32 {
 
 // writeMapStringInt writes map[string]int using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringInt(buf *ByteBuffer, m map[string]int, hasGenerics bool) {
+func writeMapStringInt(ctx *WriteContext, m map[string]int, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, VARINT64, ctx.stringWriter(), writeVarintInt)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING))   // key type
-			buf.WriteUint8(uint8(VARINT64)) // value type (int serialized as varint64)
-		}
-
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			buf.WriteVarint64(int64(v))
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, VARINT64)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		buf.WriteVarint64(int64(v))
+		chunkLeft--
 	}
 }
 
 // readMapStringInt reads map[string]int using chunk protocol
-func readMapStringInt(ctx *ReadContext) map[string]int {
+func readMapStringInt(ctx *ReadContext, dst map[string]int) map[string]int {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]int, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
 
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -358,8 +401,14 @@ func readMapStringInt(ctx *ReadContext) map[string]int {
 				return result
 			}
 		}
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
 			v := buf.ReadVarint64(err)
 			result[k] = int(v)
 			size--
@@ -370,56 +419,44 @@ func readMapStringInt(ctx *ReadContext) map[string]int {
 
 // writeMapStringFloat64 writes map[string]float64 using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringFloat64(buf *ByteBuffer, m map[string]float64, hasGenerics bool) {
+func writeMapStringFloat64(ctx *WriteContext, m map[string]float64, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, FLOAT64, ctx.stringWriter(), (*ByteBuffer).WriteFloat64)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING))  // key type
-			buf.WriteUint8(uint8(FLOAT64)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			buf.WriteFloat64(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, FLOAT64)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		buf.WriteFloat64(v)
+		chunkLeft--
 	}
 }
 
 // readMapStringFloat64 reads map[string]float64 using chunk protocol
-func readMapStringFloat64(ctx *ReadContext) map[string]float64 {
+func readMapStringFloat64(ctx *ReadContext, dst map[string]float64) map[string]float64 {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]float64, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
 
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -442,8 +479,14 @@ func readMapStringFloat64(ctx *ReadContext) map[string]float64 {
 				return result
 			}
 		}
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
 			v := buf.ReadFloat64(err)
 			result[k] = v
 			size--
@@ -454,56 +497,44 @@ func readMapStringFloat64(ctx *ReadContext) map[string]float64 {
 
 // writeMapStringBool writes map[string]bool using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapStringBool(buf *ByteBuffer, m map[string]bool, hasGenerics bool) {
+func writeMapStringBool(ctx *WriteContext, m map[string]bool, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys || ctx.trackMapKeyRef || ctx.compactStrings || ctx.stringDict != nil {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, STRING, BOOL, ctx.stringWriter(), (*ByteBuffer).WriteBool)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(STRING)) // key type
-			buf.WriteUint8(uint8(BOOL))   // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			writeString(buf, k)
-			buf.WriteBool(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, STRING, BOOL)
 		}
-		remaining -= chunkSize
+		writeString(buf, k)
+		buf.WriteBool(v)
+		chunkLeft--
 	}
 }
 
 // readMapStringBool reads map[string]bool using chunk protocol
-func readMapStringBool(ctx *ReadContext) map[string]bool {
+func readMapStringBool(ctx *ReadContext, dst map[string]bool) map[string]bool {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[string]bool, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
 
 	for size > 0 {
 		chunkHeader := buf.ReadUint8(err)
-		if chunkHeader&(TRACKING_KEY_REF|KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
+		if chunkHeader&(KEY_HAS_NULL|TRACKING_VALUE_REF|VALUE_HAS_NULL) != 0 {
 			ctx.SetError(DeserializationError("typed map reader does not support ref/null chunks"))
 			return result
 		}
@@ -530,8 +561,14 @@ func readMapStringBool(ctx *ReadContext) map[string]bool {
 			}
 		}
 
+		trackKeys := chunkHeader&TRACKING_KEY_REF != 0
 		for i := 0; i < chunkSize; i++ {
-			k := readString(buf, err)
+			var k string
+			if trackKeys {
+				k = readTrackedString(ctx)
+			} else {
+				k = ctx.ReadString()
+			}
 			v := buf.ReadBool(err)
 			result[k] = v
 			size--
@@ -542,49 +579,37 @@ func readMapStringBool(ctx *ReadContext) map[string]bool {
 
 // writeMapInt32Int32 writes map[int32]int32 using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapInt32Int32(buf *ByteBuffer, m map[int32]int32, hasGenerics bool) {
+func writeMapInt32Int32(ctx *WriteContext, m map[int32]int32, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, VARINT32, VARINT32, writeVarint32, writeVarint32)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(VARINT32)) // key type
-			buf.WriteUint8(uint8(VARINT32)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			buf.WriteVarint32(k)
-			buf.WriteVarint32(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, VARINT32, VARINT32)
 		}
-		remaining -= chunkSize
+		buf.WriteVarint32(k)
+		buf.WriteVarint32(v)
+		chunkLeft--
 	}
 }
 
 // readMapInt32Int32 reads map[int32]int32 using chunk protocol
-func readMapInt32Int32(ctx *ReadContext) map[int32]int32 {
+func readMapInt32Int32(ctx *ReadContext, dst map[int32]int32) map[int32]int32 {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[int32]int32, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
@@ -626,49 +651,37 @@ func readMapInt32Int32(ctx *ReadContext) map[int32]int32 {
 
 // writeMapInt64Int64 writes map[int64]int64 using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapInt64Int64(buf *ByteBuffer, m map[int64]int64, hasGenerics bool) {
+func writeMapInt64Int64(ctx *WriteContext, m map[int64]int64, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, VARINT64, VARINT64, (*ByteBuffer).WriteVarint64, (*ByteBuffer).WriteVarint64)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(VARINT64)) // key type
-			buf.WriteUint8(uint8(VARINT64)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			buf.WriteVarint64(k)
-			buf.WriteVarint64(v)
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, VARINT64, VARINT64)
 		}
-		remaining -= chunkSize
+		buf.WriteVarint64(k)
+		buf.WriteVarint64(v)
+		chunkLeft--
 	}
 }
 
 // readMapInt64Int64 reads map[int64]int64 using chunk protocol
-func readMapInt64Int64(ctx *ReadContext) map[int64]int64 {
+func readMapInt64Int64(ctx *ReadContext, dst map[int64]int64) map[int64]int64 {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[int64]int64, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
@@ -710,49 +723,37 @@ func readMapInt64Int64(ctx *ReadContext) map[int64]int64 {
 
 // writeMapIntInt writes map[int]int using chunk protocol
 // When hasGenerics=true, element types are known so we set DECL_TYPE flags and skip type info
-func writeMapIntInt(buf *ByteBuffer, m map[int]int, hasGenerics bool) {
+func writeMapIntInt(ctx *WriteContext, m map[int]int, hasGenerics bool) {
+	buf := ctx.buffer
 	length := len(m)
 	buf.WriteVarUint32(uint32(length))
 	if length == 0 {
 		return
 	}
+	if ctx.sortMapKeys {
+		writeOrderedPrimitiveMap(ctx, m, hasGenerics, VARINT64, VARINT64, writeVarintInt, writeVarintInt)
+		return
+	}
 
-	remaining := length
-	for remaining > 0 {
-		chunkSize := remaining
-		if chunkSize > MAX_CHUNK_SIZE {
-			chunkSize = MAX_CHUNK_SIZE
-		}
-
-		if hasGenerics {
-			buf.WriteUint8(KEY_DECL_TYPE | VALUE_DECL_TYPE)
-			buf.WriteUint8(uint8(chunkSize))
-		} else {
-			buf.WriteUint8(0)
-			buf.WriteUint8(uint8(chunkSize))
-			buf.WriteUint8(uint8(VARINT64)) // key type (int serialized as varint64)
-			buf.WriteUint8(uint8(VARINT64)) // value type
-		}
-
-		count := 0
-		for k, v := range m {
-			buf.WriteVarint64(int64(k))
-			buf.WriteVarint64(int64(v))
-			count++
-			if count >= chunkSize {
-				break
-			}
+	remaining, chunkLeft := length, 0
+	for k, v := range m {
+		if chunkLeft == 0 {
+			chunkLeft = min(remaining, MAX_CHUNK_SIZE)
+			remaining -= chunkLeft
+			writePrimitiveMapChunkHeader(buf, chunkLeft, hasGenerics, VARINT64, VARINT64)
 		}
-		remaining -= chunkSize
+		buf.WriteVarint64(int64(k))
+		buf.WriteVarint64(int64(v))
+		chunkLeft--
 	}
 }
 
 // readMapIntInt reads map[int]int using chunk protocol
-func readMapIntInt(ctx *ReadContext) map[int]int {
+func readMapIntInt(ctx *ReadContext, dst map[int]int) map[int]int {
 	err := ctx.Err()
 	buf := ctx.Buffer()
 	size := ctx.ReadCollectionLength()
-	result := make(map[int]int, size)
+	result := reuseMap(ctx, dst, size)
 	if size == 0 {
 		return result
 	}
@@ -799,7 +800,7 @@ func readMapIntInt(ctx *ReadContext) map[int]int {
 type stringStringMapSerializer struct{}
 
 func (s stringStringMapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapStringString(ctx.buffer, value.Interface().(map[string]string), false)
+	writeMapStringString(ctx, value.Interface().(map[string]string), false)
 }
 
 func (s stringStringMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -807,7 +808,7 @@ func (s stringStringMapSerializer) Write(ctx *WriteContext, refMode RefMode, wri
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapStringString(ctx.buffer, value.Interface().(map[string]string), hasGenerics)
+	writeMapStringString(ctx, value.Interface().(map[string]string), hasGenerics)
 }
 
 func (s stringStringMapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -815,7 +816,7 @@ func (s stringStringMapSerializer) ReadData(ctx *ReadContext, value reflect.Valu
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapStringString(ctx)
+	result := readMapStringString(ctx, *(*map[string]string)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -834,7 +835,7 @@ func (s stringStringMapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode Re
 type stringInt64MapSerializer struct{}
 
 func (s stringInt64MapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapStringInt64(ctx.buffer, value.Interface().(map[string]int64), false)
+	writeMapStringInt64(ctx, value.Interface().(map[string]int64), false)
 }
 
 func (s stringInt64MapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -842,7 +843,7 @@ func (s stringInt64MapSerializer) Write(ctx *WriteContext, refMode RefMode, writ
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapStringInt64(ctx.buffer, value.Interface().(map[string]int64), hasGenerics)
+	writeMapStringInt64(ctx, value.Interface().(map[string]int64), hasGenerics)
 }
 
 func (s stringInt64MapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -850,7 +851,7 @@ func (s stringInt64MapSerializer) ReadData(ctx *ReadContext, value reflect.Value
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapStringInt64(ctx)
+	result := readMapStringInt64(ctx, *(*map[string]int64)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -869,7 +870,7 @@ func (s stringInt64MapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode Ref
 type stringIntMapSerializer struct{}
 
 func (s stringIntMapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapStringInt(ctx.buffer, value.Interface().(map[string]int), false)
+	writeMapStringInt(ctx, value.Interface().(map[string]int), false)
 }
 
 func (s stringIntMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -877,7 +878,7 @@ func (s stringIntMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeT
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapStringInt(ctx.buffer, value.Interface().(map[string]int), hasGenerics)
+	writeMapStringInt(ctx, value.Interface().(map[string]int), hasGenerics)
 }
 
 func (s stringIntMapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -885,7 +886,7 @@ func (s stringIntMapSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapStringInt(ctx)
+	result := readMapStringInt(ctx, *(*map[string]int)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -904,7 +905,7 @@ func (s stringIntMapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMo
 type stringFloat64MapSerializer struct{}
 
 func (s stringFloat64MapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapStringFloat64(ctx.buffer, value.Interface().(map[string]float64), false)
+	writeMapStringFloat64(ctx, value.Interface().(map[string]float64), false)
 }
 
 func (s stringFloat64MapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -912,7 +913,7 @@ func (s stringFloat64MapSerializer) Write(ctx *WriteContext, refMode RefMode, wr
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapStringFloat64(ctx.buffer, value.Interface().(map[string]float64), hasGenerics)
+	writeMapStringFloat64(ctx, value.Interface().(map[string]float64), hasGenerics)
 }
 
 func (s stringFloat64MapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -920,7 +921,7 @@ func (s stringFloat64MapSerializer) ReadData(ctx *ReadContext, value reflect.Val
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapStringFloat64(ctx)
+	result := readMapStringFloat64(ctx, *(*map[string]float64)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -939,7 +940,7 @@ func (s stringFloat64MapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode R
 type stringBoolMapSerializer struct{}
 
 func (s stringBoolMapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapStringBool(ctx.buffer, value.Interface().(map[string]bool), false)
+	writeMapStringBool(ctx, value.Interface().(map[string]bool), false)
 }
 
 func (s stringBoolMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -947,7 +948,7 @@ func (s stringBoolMapSerializer) Write(ctx *WriteContext, refMode RefMode, write
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapStringBool(ctx.buffer, value.Interface().(map[string]bool), hasGenerics)
+	writeMapStringBool(ctx, value.Interface().(map[string]bool), hasGenerics)
 }
 
 func (s stringBoolMapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -955,7 +956,7 @@ func (s stringBoolMapSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapStringBool(ctx)
+	result := readMapStringBool(ctx, *(*map[string]bool)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -974,7 +975,7 @@ func (s stringBoolMapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefM
 type int32Int32MapSerializer struct{}
 
 func (s int32Int32MapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapInt32Int32(ctx.buffer, value.Interface().(map[int32]int32), false)
+	writeMapInt32Int32(ctx, value.Interface().(map[int32]int32), false)
 }
 
 func (s int32Int32MapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -982,7 +983,7 @@ func (s int32Int32MapSerializer) Write(ctx *WriteContext, refMode RefMode, write
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapInt32Int32(ctx.buffer, value.Interface().(map[int32]int32), hasGenerics)
+	writeMapInt32Int32(ctx, value.Interface().(map[int32]int32), hasGenerics)
 }
 
 func (s int32Int32MapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -990,7 +991,7 @@ func (s int32Int32MapSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapInt32Int32(ctx)
+	result := readMapInt32Int32(ctx, *(*map[int32]int32)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -1009,7 +1010,7 @@ func (s int32Int32MapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefM
 type int64Int64MapSerializer struct{}
 
 func (s int64Int64MapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapInt64Int64(ctx.buffer, value.Interface().(map[int64]int64), false)
+	writeMapInt64Int64(ctx, value.Interface().(map[int64]int64), false)
 }
 
 func (s int64Int64MapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -1017,7 +1018,7 @@ func (s int64Int64MapSerializer) Write(ctx *WriteContext, refMode RefMode, write
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapInt64Int64(ctx.buffer, value.Interface().(map[int64]int64), hasGenerics)
+	writeMapInt64Int64(ctx, value.Interface().(map[int64]int64), hasGenerics)
 }
 
 func (s int64Int64MapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -1025,7 +1026,7 @@ func (s int64Int64MapSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapInt64Int64(ctx)
+	result := readMapInt64Int64(ctx, *(*map[int64]int64)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -1044,7 +1045,7 @@ func (s int64Int64MapSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefM
 type intIntMapSerializer struct{}
 
 func (s intIntMapSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeMapIntInt(ctx.buffer, value.Interface().(map[int]int), false)
+	writeMapIntInt(ctx, value.Interface().(map[int]int), false)
 }
 
 func (s intIntMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -1052,7 +1053,7 @@ func (s intIntMapSerializer) Write(ctx *WriteContext, refMode RefMode, writeType
 	if done || ctx.HasError() {
 		return
 	}
-	writeMapIntInt(ctx.buffer, value.Interface().(map[int]int), hasGenerics)
+	writeMapIntInt(ctx, value.Interface().(map[int]int), hasGenerics)
 }
 
 func (s intIntMapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
@@ -1060,7 +1061,7 @@ func (s intIntMapSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		value.Set(reflect.MakeMap(value.Type()))
 	}
 	ctx.RefResolver().Reference(value)
-	result := readMapIntInt(ctx)
+	result := readMapIntInt(ctx, *(*map[int]int)(value.Addr().UnsafePointer()))
 	value.Set(reflect.ValueOf(result))
 }
 
@@ -18,6 +18,7 @@
 package fory
 
 import (
+	"fmt"
 	"testing"
 
 	"github.com/stretchr/testify/require"
@@ -59,3 +60,31 @@ func TestPrimitiveMapReaderRejectsNullChunks(t *testing.T) {
 	_ = f.readCtx.ReadStringStringMap(RefModeNone, false)
 	require.Error(t, f.readCtx.CheckError())
 }
+
+func TestPrimitiveMapSpanningChunks(t *testing.T) {
+	for _, sorted := range []bool{false, true} {
+		f := NewFory(WithXlang(true))
+		stringInt := make(map[string]int64, 1000)
+		intInt := make(map[int32]int32, 1000)
+		for i := 0; i < 1000; i++ {
+			stringInt[fmt.Sprintf("k%d", i)] = int64(i)
+			intInt[int32(i)] = int32(-i)
+		}
+		var opts []MarshalOption
+		if sorted {
+			opts = append(opts, WithSortedMaps())
+		}
+
+		data, err := f.Marshal(stringInt, opts...)
+		require.NoError(t, err)
+		var stringIntOut map[string]int64
+		require.NoError(t, f.Unmarshal(data, &stringIntOut))
+		require.Equal(t, stringInt, stringIntOut)
+
+		data, err = f.Marshal(intInt, opts...)
+		require.NoError(t, err)
+		var intIntOut map[int32]int32
+		require.NoError(t, f.Unmarshal(data, &intIntOut))
+		require.Equal(t, intInt, intIntOut)
+	}
+}
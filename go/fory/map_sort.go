@@ -0,0 +1,118 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"cmp"
+	"reflect"
+	"slices"
+)
+
+// mapEntryIter is the iteration surface shared by *reflect.MapIter and
+// sortedMapIter.
+type mapEntryIter interface {
+	Next() bool
+	Key() reflect.Value
+	Value() reflect.Value
+}
+
+// sortedMapIter visits map entries in ascending key order so that equal maps
+// produce identical bytes (WithSortedMaps).
+type sortedMapIter struct {
+	m    reflect.Value
+	keys []reflect.Value
+	pos  int
+}
+
+func newSortedMapIter(m reflect.Value) *sortedMapIter {
+	keys := m.MapKeys()
+	slices.SortFunc(keys, compareMapKeys)
+	return &sortedMapIter{m: m, keys: keys, pos: -1}
+}
+
+func (it *sortedMapIter) Next() bool {
+	it.pos++
+	return it.pos < len(it.keys)
+}
+
+func (it *sortedMapIter) Key() reflect.Value {
+	return it.keys[it.pos]
+}
+
+func (it *sortedMapIter) Value() reflect.Value {
+	return it.m.MapIndex(it.keys[it.pos])
+}
+
+// compareMapKeys orders two keys of the same map. Interface keys holding
+// different dynamic types are ordered by type name first; nil sorts first.
+func compareMapKeys(a, b reflect.Value) int {
+	if !a.IsValid() || !b.IsValid() {
+		return cmp.Compare(boolRank(a.IsValid()), boolRank(b.IsValid()))
+	}
+	switch a.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return cmp.Compare(a.Int(), b.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64, reflect.Uintptr:
+		return cmp.Compare(a.Uint(), b.Uint())
+	case reflect.Float32, reflect.Float64:
+		return cmp.Compare(a.Float(), b.Float())
+	case reflect.String:
+		return cmp.Compare(a.String(), b.String())
+	case reflect.Bool:
+		return cmp.Compare(boolRank(a.Bool()), boolRank(b.Bool()))
+	case reflect.Complex64, reflect.Complex128:
+		ac, bc := a.Complex(), b.Complex()
+		if c := cmp.Compare(real(ac), real(bc)); c != 0 {
+			return c
+		}
+		return cmp.Compare(imag(ac), imag(bc))
+	case reflect.Struct:
+		for i := 0; i < a.NumField(); i++ {
+			if c := compareMapKeys(a.Field(i), b.Field(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Array:
+		for i := 0; i < a.Len(); i++ {
+			if c := compareMapKeys(a.Index(i), b.Index(i)); c != 0 {
+				return c
+			}
+		}
+		return 0
+	case reflect.Interface:
+		if a.IsNil() || b.IsNil() {
+			return cmp.Compare(boolRank(!a.IsNil()), boolRank(!b.IsNil()))
+		}
+		ae, be := a.Elem(), b.Elem()
+		if ae.Type() != be.Type() {
+			return cmp.Compare(ae.Type().String(), be.Type().String())
+		}
+		return compareMapKeys(ae, be)
+	case reflect.Pointer, reflect.Chan, reflect.UnsafePointer:
+		return cmp.Compare(a.Pointer(), b.Pointer())
+	}
+	return 0
+}
+
+func boolRank(v bool) int {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,283 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type nestedMapItem struct {
+	ID int32
+}
+
+type nestedMapHolder struct {
+	Counts  map[string]map[string]int64
+	Labels  map[string]map[string]string
+	Series  map[int32]map[string][]int64
+	Items   map[string]map[string]nestedMapItem
+	Flags   map[string]map[int16]map[string]bool
+	Dynamic map[string]map[string]any
+}
+
+func TestNestedMapInStruct(t *testing.T) {
+	value := nestedMapHolder{
+		Counts:  map[string]map[string]int64{"a": {"x": 1, "y": 2}, "b": {}},
+		Labels:  map[string]map[string]string{"a": {"k": "v"}},
+		Series:  map[int32]map[string][]int64{1: {"s": {1, 2, 3}}},
+		Items:   map[string]map[string]nestedMapItem{"a": {"i": {ID: 3}}},
+		Flags:   map[string]map[int16]map[string]bool{"a": {2: {"t": true}}},
+		Dynamic: map[string]map[string]any{"a": {"s": "str", "n": int64(2)}},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, compatible := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithCompatible(compatible))
+			require.NoError(t, f.RegisterStruct(nestedMapItem{}, 1))
+			require.NoError(t, f.RegisterStruct(nestedMapHolder{}, 2))
+			data, err := f.Marshal(&value)
+			require.NoError(t, err, "xlang=%v compatible=%v", xlang, compatible)
+			var result nestedMapHolder
+			require.NoError(t, f.Unmarshal(data, &result), "xlang=%v compatible=%v", xlang, compatible)
+			require.Equal(t, value, result, "xlang=%v compatible=%v", xlang, compatible)
+		}
+	}
+}
+
+func TestNestedMapTopLevel(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	value := map[string]map[string]int64{"a": {"x": 1}, "b": {"y": 2, "z": 3}}
+	data, err := f.Marshal(value)
+	require.NoError(t, err)
+	var result map[string]map[string]int64
+	require.NoError(t, f.Unmarshal(data, &result))
+	require.Equal(t, value, result)
+
+	deep := map[int64]map[string]map[string]string{1: {"a": {"k": "v"}}}
+	data, err = f.Marshal(deep)
+	require.NoError(t, err)
+	var deepResult map[int64]map[string]map[string]string
+	require.NoError(t, f.Unmarshal(data, &deepResult))
+	require.Equal(t, deep, deepResult)
+}
+
+// Struct fields declare their map key/value types, but the same map type at the
+// top level must still write type info for readers that know nothing about it.
+func TestDeclaredMapSerializerDoesNotLeakToTopLevel(t *testing.T) {
+	type holder struct {
+		M map[string]map[string]any
+	}
+	top := map[string]any{"x": "y"}
+	expected, err := NewFory(WithXlang(true)).Marshal(top)
+	require.NoError(t, err)
+	expected = append([]byte(nil), expected...)
+
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(holder{}, 1))
+	_, err = f.Marshal(&holder{M: map[string]map[string]any{"a": {"b": "c"}}})
+	require.NoError(t, err)
+	data, err := f.Marshal(top)
+	require.NoError(t, err)
+	require.Equal(t, expected, data)
+}
+
+type mapKeyPoint struct {
+	X, Y int32
+}
+
+type mapKeyRecord struct {
+	Metrics map[string]int64
+	Tags    map[string]string
+	Cells   map[mapKeyPoint]string
+	Items   map[string]nestedMapItem
+	Dynamic map[any]any
+}
+
+func TestMapKeyRefTracking(t *testing.T) {
+	newFory := func(opts ...Option) *Fory {
+		f := NewFory(append([]Option{WithXlang(true), WithTrackRef(true)}, opts...)...)
+		require.NoError(t, f.RegisterStruct(nestedMapItem{}, 1))
+		require.NoError(t, f.RegisterStruct(mapKeyRecord{}, 2))
+		require.NoError(t, f.RegisterStruct(mapKeyPoint{}, 3))
+		return f
+	}
+	records := make([]*mapKeyRecord, 20)
+	for i := range records {
+		records[i] = &mapKeyRecord{
+			Metrics: map[string]int64{"requests_total": int64(i), "errors_total": 1},
+			Tags:    map[string]string{"datacenter": "us-east-1", "service": "checkout"},
+			Cells:   map[mapKeyPoint]string{{X: 1, Y: 2}: "a", {X: 3, Y: 4}: "b"},
+			Items:   map[string]nestedMapItem{"requests_total": {ID: int32(i)}},
+			Dynamic: map[any]any{"datacenter": int32(i), int32(7): "x"},
+		}
+	}
+
+	plain, err := newFory().Marshal(records)
+	require.NoError(t, err)
+	plain = append([]byte(nil), plain...)
+	tracking := newFory(WithMapKeyRefTracking(true))
+	data, err := tracking.Marshal(records)
+	require.NoError(t, err)
+	require.Less(t, len(data), len(plain)*2/3, "repeated keys must be written as references")
+
+	// Readers need reference tracking but not the option.
+	var decoded []*mapKeyRecord
+	require.NoError(t, newFory().Unmarshal(data, &decoded))
+	require.Equal(t, records, decoded)
+
+	data, err = tracking.Marshal(records[0].Metrics)
+	require.NoError(t, err)
+	var metrics map[string]int64
+	require.NoError(t, tracking.Unmarshal(data, &metrics))
+	require.Equal(t, records[0].Metrics, metrics)
+
+	data, err = tracking.Marshal(records)
+	require.NoError(t, err)
+	err = newFory(WithTrackRef(false)).Unmarshal(data, &decoded)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "requires reference tracking")
+
+	// The option only applies when reference tracking is enabled.
+	untracked := NewFory(WithXlang(true), WithMapKeyRefTracking(true))
+	value := []map[string]string{{"k": "a"}, {"k": "b"}}
+	data, err = untracked.Marshal(value)
+	require.NoError(t, err)
+	var values []map[string]string
+	require.NoError(t, untracked.Unmarshal(data, &values))
+	require.Equal(t, value, values)
+}
+
+type structKeyInner struct {
+	Zone string
+}
+
+type structKey struct {
+	ID    int32
+	Name  string
+	Inner structKeyInner
+}
+
+type structKeyFloat struct {
+	ID    int32
+	Price float64
+}
+
+type structKeyHolder struct {
+	Counts map[structKey]int32
+	Refs   map[*structKey]string
+	Nested map[string]map[structKey]bool
+}
+
+func TestStructMapKeys(t *testing.T) {
+	value := structKeyHolder{
+		Counts: map[structKey]int32{{ID: 1, Name: "a", Inner: structKeyInner{"x"}}: 2, {ID: 3}: 4},
+		Refs:   map[*structKey]string{{ID: 5, Name: "b"}: "c"},
+		Nested: map[string]map[structKey]bool{"n": {{ID: 6}: true}},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, compatible := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithCompatible(compatible), WithRefTracking(true))
+			require.NoError(t, f.RegisterStructByName(structKeyInner{}, "example.StructKeyInner"))
+			require.NoError(t, f.RegisterStructByName(structKey{}, "example.StructKey"))
+			require.NoError(t, f.RegisterStructByName(structKeyHolder{}, "example.StructKeyHolder"))
+			data, err := f.Marshal(&value)
+			require.NoError(t, err, "xlang=%v compatible=%v", xlang, compatible)
+			var result structKeyHolder
+			require.NoError(t, f.Unmarshal(data, &result), "xlang=%v compatible=%v", xlang, compatible)
+			require.Equal(t, value.Counts, result.Counts)
+			require.Equal(t, value.Nested, result.Nested)
+			require.Len(t, result.Refs, 1)
+			for k, v := range result.Refs {
+				require.Equal(t, structKey{ID: 5, Name: "b"}, *k)
+				require.Equal(t, "c", v)
+			}
+
+			data, err = f.Marshal(value.Counts)
+			require.NoError(t, err)
+			var counts map[structKey]int32
+			require.NoError(t, f.Unmarshal(data, &counts))
+			require.Equal(t, value.Counts, counts)
+		}
+	}
+}
+
+func TestStructMapKeysXlangUnhashable(t *testing.T) {
+	type holder struct {
+		Prices map[structKeyFloat]int32
+	}
+	value := map[structKeyFloat]int32{{ID: 1, Price: 1.5}: 2}
+
+	native := NewFory(WithXlang(false))
+	require.NoError(t, native.RegisterStructByName(structKeyFloat{}, "example.StructKeyFloat"))
+	require.NoError(t, native.RegisterStructByName(holder{}, "example.Holder"))
+	data, err := native.Marshal(value)
+	require.NoError(t, err)
+	var result map[structKeyFloat]int32
+	require.NoError(t, native.Unmarshal(data, &result))
+	require.Equal(t, value, result)
+	_, err = native.Marshal(&holder{Prices: value})
+	require.NoError(t, err)
+
+	xlang := NewFory(WithXlang(true))
+	require.NoError(t, xlang.RegisterStructByName(structKeyFloat{}, "example.StructKeyFloat"))
+	require.NoError(t, xlang.RegisterStructByName(holder{}, "example.Holder"))
+	_, err = xlang.Marshal(value)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "field Price has type float64")
+	_, err = xlang.Marshal(&holder{Prices: value})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "cannot be serialized in xlang mode")
+}
+
+type declaredEntryItem struct {
+	ID int32
+}
+
+type declaredEntryHolder struct {
+	Scores map[string]int32
+	Items  map[string]declaredEntryItem
+	List   []declaredEntryItem
+}
+
+func TestMapDeclaredEntryTypes(t *testing.T) {
+	for _, compatible := range []bool{false, true} {
+		f := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, f.RegisterStruct(declaredEntryItem{}, 1))
+		require.NoError(t, f.RegisterStruct(declaredEntryHolder{}, 2))
+		value := declaredEntryHolder{
+			Scores: map[string]int32{"a": 1},
+			Items:  map[string]declaredEntryItem{"b": {ID: 2}},
+			List:   []declaredEntryItem{{ID: 3}},
+		}
+		data, err := f.Serialize(&value)
+		require.NoError(t, err)
+		if compatible {
+			// Struct values keep their type info, like list elements.
+			out, err := Dump(data)
+			require.NoError(t, err)
+			require.Contains(t, out, "items: MAP len=1\n    chunk size=1 flags=key_decl_type\n")
+			require.Contains(t, out, "list: LIST len=1 flags=same_type\n")
+			require.Contains(t, out, "scores: MAP len=1\n    chunk size=1 flags=key_decl_type|value_decl_type\n")
+		}
+		var result declaredEntryHolder
+		require.NoError(t, f.Deserialize(data, &result))
+		require.Equal(t, value, result)
+	}
+}
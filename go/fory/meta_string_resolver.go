@@ -18,6 +18,7 @@
 package fory
 
 import (
+	"bytes"
 	"encoding/binary"
 	"fmt"
 	"github.com/apache/fory/go/fory/meta"
@@ -75,6 +76,7 @@ type MetaStringResolver struct {
 	hashToMetaStrBytes       map[int64]*MetaStringBytes              // Large string lookup
 	smallHashToMetaStrBytes  map[smallMetaStringKey]*MetaStringBytes // Small string lookup
 	metaStrToMetaStrBytes    map[*meta.MetaString]*MetaStringBytes   // Conversion cache
+	skipHashCheck            bool                                    // Trust the hash of cached large strings
 }
 
 var emptyMetaStringBytes = NewMetaStringBytes([]byte{}, 256)
@@ -157,17 +159,20 @@ func (r *MetaStringResolver) ReadMetaStringBytes(buf *ByteBuffer, ctxErr *Error)
 			return emptyMetaStringBytes, nil
 		}
 		encByte := buf.ReadByte(ctxErr)
+		if ctxErr.HasError() {
+			return nil, *ctxErr
+		}
 		var encErr error
 		encoding, encErr = meta.EncodingFromByte(encByte)
 		if encErr != nil {
 			return nil, encErr
 		}
 
-		data = make([]byte, length)
-		_, err := buf.Read(data)
-		if err != nil {
-			return nil, err
+		body := buf.ReadBytes(length, ctxErr)
+		if ctxErr.HasError() {
+			return nil, *ctxErr
 		}
+		data = append([]byte(nil), body...)
 
 		words := smallMetaStringWords(data)
 		key = smallMetaStringKey{
@@ -178,37 +183,46 @@ func (r *MetaStringResolver) ReadMetaStringBytes(buf *ByteBuffer, ctxErr *Error)
 		hashcode = computeSmallMetaStringHash(words, length, encoding)
 	} else {
 		// Large string handling
-		err := binary.Read(buf, binary.LittleEndian, &hashcode)
-		if err != nil {
-			return nil, err
+		hashcode = buf.ReadInt64(ctxErr)
+		if ctxErr.HasError() {
+			return nil, *ctxErr
 		}
 		var encErr error
 		encoding, encErr = meta.EncodingFromByte(byte(hashcode & 0xFF))
 		if encErr != nil {
 			return nil, encErr
 		}
-		data = make([]byte, length)
-		_, err = buf.Read(data)
-		if err != nil {
-			return nil, err
+		// Cached bodies matched their hash when first read, so a hit only
+		// compares bytes, or skips them when hash checks are disabled.
+		m, cached := r.hashToMetaStrBytes[hashcode]
+		cached = cached && int(m.Length) == length
+		if cached && r.skipHashCheck {
+			buf.Skip(length, ctxErr)
+			if ctxErr.HasError() {
+				return nil, *ctxErr
+			}
+			r.dynamicIDToEnumString = append(r.dynamicIDToEnumString, m)
+			return m, nil
+		}
+		body := buf.ReadBytes(length, ctxErr)
+		if ctxErr.HasError() {
+			return nil, *ctxErr
+		}
+		if cached && bytes.Equal(body, m.Data) {
+			r.dynamicIDToEnumString = append(r.dynamicIDToEnumString, m)
+			return m, nil
 		}
-		canonicalHashcode := ComputeMetaStringHash(data, encoding)
-		if canonicalHashcode != hashcode {
-			return nil, fmt.Errorf("meta string body hash mismatch")
+		if bodyHashcode := ComputeMetaStringHash(body, encoding); bodyHashcode != hashcode {
+			return nil, metaStringHashMismatchError(hashcode, bodyHashcode)
 		}
+		data = append([]byte(nil), body...)
 	}
 
-	// Check string caches for existing instance
 	if length <= SmallStringThreshold {
 		if m, ok := r.smallHashToMetaStrBytes[key]; ok {
 			r.dynamicIDToEnumString = append(r.dynamicIDToEnumString, m)
 			return m, nil
 		}
-	} else {
-		if m, ok := r.hashToMetaStrBytes[hashcode]; ok {
-			r.dynamicIDToEnumString = append(r.dynamicIDToEnumString, m)
-			return m, nil
-		}
 	}
 
 	// Cache only after the current body has been parsed and, for large bodies, hash-validated.
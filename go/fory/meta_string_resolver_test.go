@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -190,3 +192,75 @@ func TestMetaStringResolverReadCachesAreCapped(t *testing.T) {
 	require.Len(t, resolver.hashToMetaStrBytes, maxCachedMetaStrings)
 	require.NotContains(t, resolver.hashToMetaStrBytes, largeHash)
 }
+
+func writeLargeMetaString(buffer *ByteBuffer, hashcode int64, data []byte) {
+	buffer.WriteVarUint32Small7(uint32(len(data)) << 1)
+	buffer.WriteInt64(hashcode)
+	buffer.Write(data)
+}
+
+func TestMetaStringResolverHashMismatchError(t *testing.T) {
+	resolver := NewMetaStringResolver()
+	data := []byte("0123456789abcdefg")
+	hashcode := ComputeMetaStringHash(data, meta.UTF_8)
+	buffer := NewByteBuffer(nil)
+	writeLargeMetaString(buffer, hashcode, data)
+	writeLargeMetaString(buffer, hashcode, []byte("0123456789abcdefX"))
+
+	var ctxErr Error
+	m, err := resolver.ReadMetaStringBytes(buffer, &ctxErr)
+	require.NoError(t, err)
+	require.Equal(t, data, m.Data)
+
+	// A cached hash does not vouch for a different body.
+	_, err = resolver.ReadMetaStringBytes(buffer, &ctxErr)
+	require.Error(t, err)
+	require.Equal(t, ErrKindHashMismatch, err.(Error).Kind())
+	require.Contains(t, err.Error(), "meta string hash mismatch")
+	require.Len(t, resolver.hashToMetaStrBytes, 1)
+}
+
+func TestMetaStringResolverSkipsCachedBodies(t *testing.T) {
+	resolver := NewMetaStringResolver()
+	resolver.skipHashCheck = true
+	data := []byte("0123456789abcdefg")
+	hashcode := ComputeMetaStringHash(data, meta.UTF_8)
+	buffer := NewByteBuffer(nil)
+	writeLargeMetaString(buffer, hashcode, []byte("0123456789abcdefX"))
+	writeLargeMetaString(buffer, hashcode, data)
+	writeLargeMetaString(buffer, hashcode, []byte("0123456789abcdefX"))
+	buffer.WriteByte(7)
+
+	// Bodies are still checked before they are cached.
+	var ctxErr Error
+	_, err := resolver.ReadMetaStringBytes(buffer, &ctxErr)
+	require.Error(t, err)
+	require.Empty(t, resolver.hashToMetaStrBytes)
+
+	m, err := resolver.ReadMetaStringBytes(buffer, &ctxErr)
+	require.NoError(t, err)
+	skipped, err := resolver.ReadMetaStringBytes(buffer, &ctxErr)
+	require.NoError(t, err)
+	require.Same(t, m, skipped)
+	require.Equal(t, byte(7), buffer.ReadByte(&ctxErr))
+}
+
+type metaStringHashRecord struct {
+	ID int32
+}
+
+func TestMetaStringHashVerificationOption(t *testing.T) {
+	for _, verify := range []bool{true, false} {
+		f := New(WithXlang(true), WithCompatible(false), WithMetaStringHashVerification(verify))
+		require.Equal(t, !verify, f.typeResolver.metaStringResolver.skipHashCheck)
+		require.NoError(t, f.RegisterStructByName(metaStringHashRecord{}, "org.example.serialization.records.MetaStringHashRecord"))
+		for i := int32(0); i < 3; i++ {
+			data, err := f.Marshal(&metaStringHashRecord{ID: i})
+			require.NoError(t, err)
+			var result metaStringHashRecord
+			require.NoError(t, f.Unmarshal(data, &result))
+			require.Equal(t, i, result.ID)
+		}
+		require.NotEmpty(t, f.typeResolver.metaStringResolver.hashToMetaStrBytes)
+	}
+}
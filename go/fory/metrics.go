@@ -0,0 +1,60 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"reflect"
+	"time"
+)
+
+// Metrics receives measurements of serialization calls, for example to export
+// them to a monitoring system; the forymetrics package adapts them to expvar
+// and Prometheus. A Metrics shared by several Fory instances, such as the
+// pool of a threadsafe.Fory, must be safe for concurrent use.
+//
+// Implementations may embed NopMetrics so they keep compiling when methods
+// are added.
+type Metrics interface {
+	// Serialized reports one serialization call with the type of the root
+	// value, the payload size in bytes, the elapsed time and the error
+	// returned, if any.
+	Serialized(type_ reflect.Type, bytes int, elapsed time.Duration, err error)
+	// Deserialized reports one deserialization call with the type decoded
+	// into, the payload size in bytes, the elapsed time and the error
+	// returned, if any.
+	Deserialized(type_ reflect.Type, bytes int, elapsed time.Duration, err error)
+	// TypeDefLookup reports whether type metadata read from a payload was
+	// found in the cache of parsed TypeDefs. Misses cost a parse.
+	TypeDefLookup(hit bool)
+}
+
+// NopMetrics ignores all measurements.
+type NopMetrics struct{}
+
+func (NopMetrics) Serialized(reflect.Type, int, time.Duration, error)   {}
+func (NopMetrics) Deserialized(reflect.Type, int, time.Duration, error) {}
+func (NopMetrics) TypeDefLookup(bool)                                   {}
+
+// targetType returns the type a Deserialize call decodes into.
+func targetType(v any) reflect.Type {
+	t := reflect.TypeOf(v)
+	if t != nil && t.Kind() == reflect.Ptr {
+		return t.Elem()
+	}
+	return t
+}
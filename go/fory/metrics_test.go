@@ -0,0 +1,87 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type metricsCall struct {
+	deserialize bool
+	type_       reflect.Type
+	bytes       int
+	failed      bool
+}
+
+type recordingMetrics struct {
+	NopMetrics
+	calls  []metricsCall
+	lookup []bool
+}
+
+func (m *recordingMetrics) Serialized(t reflect.Type, bytes int, _ time.Duration, err error) {
+	m.calls = append(m.calls, metricsCall{false, t, bytes, err != nil})
+}
+
+func (m *recordingMetrics) Deserialized(t reflect.Type, bytes int, _ time.Duration, err error) {
+	m.calls = append(m.calls, metricsCall{true, t, bytes, err != nil})
+}
+
+func (m *recordingMetrics) TypeDefLookup(hit bool) {
+	m.lookup = append(m.lookup, hit)
+}
+
+func TestMetrics(t *testing.T) {
+	m := &recordingMetrics{}
+	f := New(WithXlang(true), WithCompatible(true), WithMetrics(m))
+	require.NoError(t, f.RegisterStructByName(recordAddress{}, "example.Address"))
+	addrType := reflect.TypeOf(recordAddress{})
+
+	data, err := f.Serialize(&recordAddress{City: "Oslo"})
+	require.NoError(t, err)
+	var addr recordAddress
+	require.NoError(t, f.Deserialize(data, &addr))
+	require.NoError(t, Deserialize(f, data, &addr))
+	require.Error(t, f.Deserialize(data[:3], &addr))
+
+	buf := NewByteBuffer(nil)
+	buf.WriteInt32(0)
+	require.NoError(t, f.SerializeTo(buf, &addr))
+	buf.ReadInt32(nil)
+	require.NoError(t, f.DeserializeFrom(buf, &addr))
+	intData, err := Serialize(f, int32(5))
+	require.NoError(t, err)
+
+	n := len(data)
+	require.Equal(t, []metricsCall{
+		{false, reflect.PointerTo(addrType), n, false},
+		{true, addrType, n, false},
+		{true, addrType, n, false},
+		{true, addrType, 3, true},
+		{false, reflect.PointerTo(addrType), n, false},
+		{true, addrType, n, false},
+		{false, reflect.TypeOf(int32(0)), len(intData), false},
+	}, m.calls)
+	require.Equal(t, []bool{false, true, true}, m.lookup[:3])
+}
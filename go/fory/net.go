@@ -0,0 +1,186 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"net"
+	"net/netip"
+	"reflect"
+)
+
+var (
+	netIPType       = reflect.TypeFor[net.IP]()
+	netipAddrType   = reflect.TypeFor[netip.Addr]()
+	netipPrefixType = reflect.TypeFor[netip.Prefix]()
+)
+
+// isNetAddressType reports whether t is one of the network address types
+// that are written as BINARY.
+func isNetAddressType(t reflect.Type) bool {
+	return t == netIPType || t == netipAddrType || t == netipPrefixType
+}
+
+// containsNetAddressType reports whether t is a network address type or a
+// pointer, slice, array or map holding one.
+func containsNetAddressType(t reflect.Type) bool {
+	for {
+		if isNetAddressType(t) {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Map:
+			if containsNetAddressType(t.Key()) {
+				return true
+			}
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
+// netIPSerializer writes net.IP as BINARY holding 4 bytes for IPv4 addresses
+// and 16 bytes for IPv6 addresses.
+type netIPSerializer struct{}
+
+func (s netIPSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	ip := net.IP(value.Bytes())
+	if v4 := ip.To4(); v4 != nil {
+		ip = v4
+	}
+	buf := ctx.Buffer()
+	buf.WriteLength(len(ip))
+	if len(ip) > 0 {
+		buf.WriteBinary(ip)
+	}
+}
+
+func (s netIPSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	done := writeSliceRefAndType(ctx, refMode, writeType, value, BINARY)
+	if done || ctx.HasError() {
+		return
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s netIPSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	length := ctx.ReadBinaryLength()
+	if ctx.HasError() {
+		return
+	}
+	if length == 0 {
+		value.SetBytes(nil)
+		return
+	}
+	value.SetBytes(ctx.readBinaryInto(length, nil))
+}
+
+func (s netIPSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	done, typeId := readSliceRefAndType(ctx, refMode, readType, value)
+	if done || ctx.HasError() {
+		return
+	}
+	if readType && typeId != uint32(BINARY) {
+		ctx.SetError(TypeMismatchError(TypeId(typeId), BINARY))
+		return
+	}
+	s.ReadData(ctx, value)
+}
+
+func (s netIPSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	s.Read(ctx, refMode, false, false, value)
+}
+
+// netipSerializer writes netip.Addr and netip.Prefix as BINARY holding their
+// MarshalBinary form: 0, 4 or 16 address bytes followed by the IPv6 zone for
+// addresses, and the address followed by one prefix length byte for prefixes.
+type netipSerializer struct{}
+
+type netipValue interface {
+	AppendBinary(b []byte) ([]byte, error)
+}
+
+func (s netipSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	var scratch [24]byte
+	data, err := value.Interface().(netipValue).AppendBinary(scratch[:0])
+	if err != nil {
+		ctx.SetError(FromError(err))
+		return
+	}
+	buf := ctx.Buffer()
+	buf.WriteLength(len(data))
+	if len(data) > 0 {
+		buf.WriteBinary(data)
+	}
+}
+
+func (s netipSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	if refMode != RefModeNone {
+		ctx.buffer.WriteInt8(NotNullValueFlag)
+	}
+	if writeType {
+		ctx.buffer.WriteUint8(uint8(BINARY))
+	}
+	s.WriteData(ctx, value)
+}
+
+func (s netipSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	length := ctx.ReadBinaryLength()
+	if ctx.HasError() {
+		return
+	}
+	data := ctx.buffer.ReadBinary(length, ctx.Err())
+	if ctx.HasError() {
+		return
+	}
+	var err error
+	switch value.Type() {
+	case netipAddrType:
+		var addr netip.Addr
+		err = addr.UnmarshalBinary(data)
+		value.Set(reflect.ValueOf(addr))
+	default:
+		var prefix netip.Prefix
+		err = prefix.UnmarshalBinary(data)
+		value.Set(reflect.ValueOf(prefix))
+	}
+	if err != nil {
+		ctx.SetError(DeserializationErrorf("invalid %s: %v", value.Type(), err))
+	}
+}
+
+func (s netipSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	if refMode != RefModeNone {
+		if ctx.buffer.ReadInt8(ctx.Err()) == NullFlag {
+			return
+		}
+	}
+	if readType && !ctx.readExpectedTypeID(BINARY) {
+		return
+	}
+	if ctx.HasError() {
+		return
+	}
+	s.ReadData(ctx, value)
+}
+
+func (s netipSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	s.Read(ctx, refMode, false, false, value)
+}
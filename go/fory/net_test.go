@@ -0,0 +1,115 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"net"
+	"net/netip"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type netConfig struct {
+	Name    string
+	Gateway net.IP
+	DNS     []net.IP
+	Listen  netip.Addr
+	Peer    *netip.Addr
+	Subnet  netip.Prefix
+	Routes  []netip.Prefix
+	Hosts   map[string]netip.Addr
+}
+
+func TestSerializeNetIP(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	for _, ip := range []net.IP{net.ParseIP("192.168.1.1"), net.ParseIP("2001:db8::1"), net.IPv4(10, 0, 0, 1).To4()} {
+		data, err := f.Marshal(ip)
+		require.NoError(t, err)
+		var result net.IP
+		require.NoError(t, f.Unmarshal(data, &result))
+		require.True(t, ip.Equal(result), "%s != %s", ip, result)
+
+		var raw []byte
+		require.NoError(t, f.Unmarshal(data, &raw))
+		if ip.To4() != nil {
+			require.Len(t, raw, net.IPv4len)
+		} else {
+			require.Len(t, raw, net.IPv6len)
+		}
+	}
+}
+
+func TestSerializeNetip(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	for _, addr := range []netip.Addr{
+		{},
+		netip.MustParseAddr("10.1.2.3"),
+		netip.MustParseAddr("::ffff:10.1.2.3"),
+		netip.MustParseAddr("fe80::1%eth0"),
+	} {
+		data, err := f.Marshal(addr)
+		require.NoError(t, err)
+		var result netip.Addr
+		require.NoError(t, f.Unmarshal(data, &result))
+		require.Equal(t, addr, result)
+	}
+	for _, prefix := range []netip.Prefix{
+		{},
+		netip.MustParsePrefix("10.0.0.0/8"),
+		netip.MustParsePrefix("2001:db8::/32"),
+	} {
+		data, err := f.Marshal(prefix)
+		require.NoError(t, err)
+		var result netip.Prefix
+		require.NoError(t, f.Unmarshal(data, &result))
+		require.Equal(t, prefix, result)
+	}
+
+	data, err := f.Marshal([]byte{1, 2})
+	require.NoError(t, err)
+	var addr netip.Addr
+	require.Error(t, f.Unmarshal(data, &addr))
+}
+
+func TestSerializeNetFields(t *testing.T) {
+	peer := netip.MustParseAddr("2001:db8::2")
+	value := netConfig{
+		Name:    "eth0",
+		Gateway: net.ParseIP("192.168.1.1").To4(),
+		DNS:     []net.IP{net.ParseIP("8.8.8.8").To4(), net.ParseIP("2001:4860:4860::8888")},
+		Listen:  netip.MustParseAddr("0.0.0.0"),
+		Peer:    &peer,
+		Subnet:  netip.MustParsePrefix("192.168.1.0/24"),
+		Routes:  []netip.Prefix{netip.MustParsePrefix("10.0.0.0/8")},
+		Hosts:   map[string]netip.Addr{"db": netip.MustParseAddr("10.0.0.5")},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, compatible := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithCompatible(compatible))
+			require.NoError(t, f.RegisterStruct(netConfig{}, 1))
+			data, err := f.Marshal(&value)
+			require.NoError(t, err)
+			var result netConfig
+			require.NoError(t, f.Unmarshal(data, &result), "xlang=%v compatible=%v", xlang, compatible)
+			require.Equal(t, value, result, "xlang=%v compatible=%v", xlang, compatible)
+		}
+	}
+}
@@ -0,0 +1,197 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+	"slices"
+	"sync"
+)
+
+// minParallelLength is the smallest top-level slice or map MarshalParallel
+// splits across workers.
+const minParallelLength = 1024
+
+// parallelSeedLength is the number of leading elements the calling instance
+// writes before the workers start, so that type defs for the element types
+// are already in the payload when the workers reference them.
+const parallelSeedLength = 64
+
+// MarshalParallel serializes v like Serialize, but when v is a slice or map
+// with many elements it writes contiguous element ranges concurrently, one
+// range on the calling instance and one on each worker, and joins the
+// results in order. The output decodes like any other payload.
+//
+// Workers must be distinct instances created with the same options and
+// registrations as f, and must not be used by other goroutines during the
+// call. Values other than large lists and maps, and all values when
+// reference tracking or WithStringDictionary is enabled, are written
+// sequentially. A range whose
+// elements need type metadata that the payload does not hold yet is also
+// rewritten sequentially, so heterogeneous collections gain little.
+//
+// As with Marshal, the returned slice is invalidated by the next call on f.
+func (f *Fory) MarshalParallel(v any, workers ...*Fory) ([]byte, error) {
+	for _, w := range workers {
+		if w == nil || w == f {
+			return nil, fmt.Errorf("MarshalParallel workers must be distinct non-nil instances")
+		}
+		if w.config.IsXlang != f.config.IsXlang || w.config.Compatible != f.config.Compatible ||
+			w.config.CompactStrings != f.config.CompactStrings || w.config.TrackMapKeyRef != f.config.TrackMapKeyRef {
+			return nil, fmt.Errorf("MarshalParallel workers must be configured like the calling instance")
+		}
+	}
+	rv := reflect.ValueOf(v)
+	if len(workers) == 0 || f.config.TrackRef || f.writeCtx.stringDict != nil ||
+		(rv.Kind() != reflect.Slice && rv.Kind() != reflect.Map) || rv.Len() < minParallelLength {
+		return f.Serialize(v)
+	}
+	typeInfo, err := f.typeResolver.getTypeInfo(rv, true)
+	if err != nil {
+		return nil, err
+	}
+	switch typeInfo.Serializer.(type) {
+	case *sliceSerializer, *mapSerializer:
+	default:
+		return f.Serialize(v)
+	}
+	f.writeCtx.parallel = &parallelWriter{fory: f, workers: workers}
+	defer func() {
+		f.writeCtx.parallel = nil
+	}()
+	return f.Serialize(v)
+}
+
+// parallelWriter writes the elements of the root list or map for
+// MarshalParallel once the calling instance has written its header.
+type parallelWriter struct {
+	fory    *Fory
+	workers []*Fory
+}
+
+func (p *parallelWriter) writeList(ctx *WriteContext, value reflect.Value, hasNull, declaredGenericDispatch bool) {
+	p.write(ctx, value.Len(), func(c *WriteContext, from, to int) {
+		s, ok := rootSerializer(c, value).(*sliceSerializer)
+		if !ok {
+			c.SetError(SerializationErrorf("no list serializer for %s", value.Type()))
+			return
+		}
+		s.writeElements(c, value, from, to, hasNull, declaredGenericDispatch)
+	})
+}
+
+func (p *parallelWriter) writeMap(ctx *WriteContext, value reflect.Value) {
+	keys := value.MapKeys()
+	if ctx.sortMapKeys {
+		slices.SortFunc(keys, compareMapKeys)
+	}
+	p.write(ctx, len(keys), func(c *WriteContext, from, to int) {
+		s, ok := rootSerializer(c, value).(*mapSerializer)
+		if !ok {
+			c.SetError(SerializationErrorf("no map serializer for %s", value.Type()))
+			return
+		}
+		// Each range starts its own chunks; readers only rely on the total
+		// entry count written before the first chunk.
+		s.writeEntries(c, &sortedMapIter{m: value, keys: keys[from:to], pos: -1})
+	})
+}
+
+// rootSerializer returns the serializer c's instance uses for value. Workers
+// look up their own serializers because serializers are not safe for
+// concurrent use.
+func rootSerializer(c *WriteContext, value reflect.Value) Serializer {
+	typeInfo, err := c.typeResolver.getTypeInfo(value, true)
+	if err != nil {
+		c.SetError(SerializationErrorf("cannot get typeinfo for value %v: %v", value.Type(), err))
+		return nil
+	}
+	return typeInfo.Serializer
+}
+
+// write writes elements [0, n) with writeRange, splitting all but the seed
+// across the calling instance and the workers.
+func (p *parallelWriter) write(ctx *WriteContext, n int, writeRange func(c *WriteContext, from, to int)) {
+	seed := min(n, parallelSeedLength)
+	writeRange(ctx, 0, seed)
+	if ctx.HasError() {
+		return
+	}
+
+	meta := p.fory.metaContext
+	seededTypes := 0
+	if meta != nil {
+		seededTypes = meta.writtenTypes()
+	}
+	parts := len(p.workers) + 1
+	bounds := make([]int, parts+1)
+	for i := range bounds {
+		bounds[i] = seed + (n-seed)*i/parts
+	}
+	for _, w := range p.workers {
+		w.resetWriteState()
+		if meta != nil && w.metaContext != nil {
+			w.metaContext.copyWriteState(meta)
+		}
+		w.writeCtx.depth = ctx.depth
+		w.writeCtx.sortMapKeys = ctx.sortMapKeys
+	}
+
+	var wg sync.WaitGroup
+	for i, w := range p.workers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			writeRange(w.writeCtx, bounds[i+1], bounds[i+2])
+		}()
+	}
+	writeRange(ctx, bounds[0], bounds[1])
+	wg.Wait()
+
+	for i, w := range p.workers {
+		c := w.writeCtx
+		if ctx.HasError() {
+			break
+		}
+		if c.HasError() {
+			ctx.SetError(c.TakeError())
+			break
+		}
+		if w.wroteSharedState(seededTypes) {
+			// The worker numbered type defs or meta strings on its own, so
+			// its bytes cannot be spliced in; write the rest here instead.
+			writeRange(ctx, bounds[i+1], n)
+			break
+		}
+		ctx.buffer.WriteBinary(c.buffer.GetByteSlice(0, c.buffer.writerIndex))
+	}
+	for _, w := range p.workers {
+		w.writeCtx.sortMapKeys = false
+		w.resetWriteState()
+	}
+}
+
+// wroteSharedState reports whether the last write on f added type defs beyond
+// the seeded ones or meta strings, both of which are numbered per payload.
+func (f *Fory) wroteSharedState(seededTypes int) bool {
+	if f.metaContext != nil && f.metaContext.writtenTypes() != seededTypes {
+		return true
+	}
+	return f.typeResolver.dynamicStringId > 0 || f.typeResolver.metaStringResolver.dynamicWriteStringID > 0
+}
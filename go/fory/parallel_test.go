@@ -0,0 +1,128 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type parallelInner struct {
+	Tags  []string
+	Score float64
+}
+
+type parallelItem struct {
+	ID    int64
+	Name  string
+	Inner parallelInner
+	Any   any
+}
+
+type parallelOther struct {
+	Note string
+}
+
+func newParallelFory(t *testing.T, opts ...Option) *Fory {
+	f := NewFory(opts...)
+	require.NoError(t, f.RegisterStruct(parallelInner{}, 360))
+	require.NoError(t, f.RegisterStruct(parallelItem{}, 361))
+	require.NoError(t, f.RegisterStruct(parallelOther{}, 362))
+	return f
+}
+
+func parallelItems(n int) []*parallelItem {
+	items := make([]*parallelItem, n)
+	for i := range items {
+		if i%97 == 5 {
+			continue
+		}
+		items[i] = &parallelItem{
+			ID:    int64(i),
+			Name:  fmt.Sprintf("item-%d", i),
+			Inner: parallelInner{Tags: []string{"a", fmt.Sprint(i)}, Score: float64(i) / 3},
+			Any:   int64(i),
+		}
+	}
+	return items
+}
+
+func TestMarshalParallel(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithXlang(true)},
+		{WithXlang(true), WithCompatible(false)},
+		{WithXlang(false)},
+	} {
+		f := newParallelFory(t, opts...)
+		workers := []*Fory{newParallelFory(t, opts...), newParallelFory(t, opts...), newParallelFory(t, opts...)}
+
+		items := parallelItems(5000)
+		// A type first seen past the seed makes the workers fall back.
+		late := parallelItems(5000)
+		late[4000].Any = &parallelOther{Note: "late"}
+		for _, value := range []any{items, late} {
+			want, err := f.Serialize(value)
+			require.NoError(t, err)
+			want = append([]byte(nil), want...)
+			got, err := f.MarshalParallel(value, workers...)
+			require.NoError(t, err)
+			require.Equal(t, want, got)
+		}
+
+		byName := make(map[string]parallelItem, 3000)
+		for _, item := range items[:3000] {
+			if item != nil {
+				byName[item.Name] = *item
+			}
+		}
+		data, err := f.MarshalParallel(byName, workers...)
+		require.NoError(t, err)
+		var decoded map[string]parallelItem
+		require.NoError(t, f.Unmarshal(data, &decoded))
+		require.Equal(t, byName, decoded)
+
+		// Workers are left ready for sequential use.
+		data, err = workers[0].Marshal(items[:10])
+		require.NoError(t, err)
+		var small []*parallelItem
+		require.NoError(t, workers[0].Unmarshal(data, &small))
+		require.Equal(t, items[:10], small)
+	}
+}
+
+func TestMarshalParallelWorkers(t *testing.T) {
+	f := newParallelFory(t, WithXlang(true))
+	items := parallelItems(2000)
+
+	_, err := f.MarshalParallel(items, f)
+	require.Error(t, err)
+	_, err = f.MarshalParallel(items, newParallelFory(t, WithXlang(false)))
+	require.Error(t, err)
+
+	// Without workers the value is written sequentially.
+	want, err := f.Serialize(items)
+	require.NoError(t, err)
+	want = append([]byte(nil), want...)
+	got, err := f.MarshalParallel(items)
+	require.NoError(t, err)
+	require.Equal(t, want, got)
+}
@@ -140,6 +140,9 @@ func (s *ptrToValueSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	var newVal reflect.Value
 	if value.IsNil() {
 		// Allocate new value
+		if !ctx.chargeElems(1, value.Type().Elem()) {
+			return
+		}
 		newVal = reflect.New(value.Type().Elem())
 		value.Set(newVal)
 	} else {
@@ -191,6 +194,9 @@ func (s *ptrToValueSerializer) Read(ctx *ReadContext, refMode RefMode, readType
 		// Check if this is a struct type that needs type meta reading
 		if IsNamespacedType(internalTypeID) || internalTypeID == COMPATIBLE_STRUCT || internalTypeID == STRUCT {
 			typeInfo := ctx.TypeResolver().readTypeInfoWithTypeID(buf, typeID, ctxErr)
+			if ctx.HasError() {
+				return
+			}
 			// Use the serializer from TypeInfo which has the remote field definitions
 			if structSer, ok := typeInfo.Serializer.(*structSerializer); ok && len(structSer.fieldDefs) > 0 {
 				// Allocate the pointer value if needed
@@ -0,0 +1,134 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Preheat builds and caches the serializers for the types of values, as
+// instances or reflect.Types, so the first call that serializes them does
+// not pay for reflection. Struct field plans are built for every struct
+// reachable through fields, pointers, slices, arrays, maps and optionals; in
+// compatible mode the TypeDefs written for them, and the readers built from
+// those TypeDefs, are cached as well. Interface fields are not followed, so
+// preheat the concrete types they hold separately.
+//
+// Types must be registered first. Preheat reports the error the first
+// serialization would, such as an unregistered struct, without writing
+// anything. Unregister drops the caches, so preheat again afterwards.
+func (f *Fory) Preheat(values ...any) error {
+	seen := make(map[reflect.Type]bool)
+	for _, v := range values {
+		t, ok := v.(reflect.Type)
+		if !ok {
+			t = reflect.TypeOf(v)
+		}
+		if t == nil {
+			return fmt.Errorf("Preheat cannot build a serializer for nil")
+		}
+		if t.Kind() != reflect.Interface {
+			if _, err := f.typeResolver.getTypeInfo(reflect.Zero(t), true); err != nil {
+				return fmt.Errorf("preheat %v: %w", t, err)
+			}
+		}
+		if err := f.typeResolver.preheat(t, seen); err != nil {
+			return fmt.Errorf("preheat %v: %w", t, err)
+		}
+	}
+	return nil
+}
+
+func (r *TypeResolver) preheat(t reflect.Type, seen map[reflect.Type]bool) error {
+	if seen[t] {
+		return nil
+	}
+	seen[t] = true
+	if info, ok := getOptionalInfo(t); ok {
+		return r.preheat(info.valueType, seen)
+	}
+	switch t.Kind() {
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return r.preheat(t.Elem(), seen)
+	case reflect.Map:
+		if err := r.preheat(t.Key(), seen); err != nil {
+			return err
+		}
+		return r.preheat(t.Elem(), seen)
+	case reflect.Struct:
+		return r.preheatStruct(t, seen)
+	}
+	return nil
+}
+
+func (r *TypeResolver) preheatStruct(t reflect.Type, seen map[reflect.Type]bool) error {
+	info, err := r.getTypeInfo(reflect.Zero(t), true)
+	if err != nil {
+		return err
+	}
+	s, ok := info.Serializer.(*structSerializer)
+	if !ok {
+		// Extensions and built-in structs such as time.Time own their layout.
+		return nil
+	}
+	if err := s.initialize(r); err != nil {
+		return err
+	}
+	if _, err := r.getTypeInfo(reflect.Zero(reflect.PointerTo(t)), true); err != nil {
+		return err
+	}
+	if r.Compatible() && isStructTypeId(TypeId(info.TypeID)) {
+		if err := r.preheatTypeDef(t); err != nil {
+			return err
+		}
+	}
+	for _, field := range s.fields {
+		if err := r.preheat(field.Meta.Type, seen); err != nil {
+			return fmt.Errorf("field %s: %w", field.Meta.Name, err)
+		}
+	}
+	return nil
+}
+
+// preheatTypeDef builds the TypeDef written for t and the reader that a peer
+// sending the same TypeDef is read with, as readSharedTypeMeta would.
+func (r *TypeResolver) preheatTypeDef(t reflect.Type) error {
+	typeDef, err := r.getTypeDef(t, true)
+	if err != nil {
+		return err
+	}
+	buffer := NewByteBuffer(typeDef.encoded)
+	bufErr := &Error{}
+	id := buffer.ReadInt64(bufErr)
+	if err := bufErr.CheckError(); err != nil {
+		return err
+	}
+	if _, ok := r.defIdToTypeDef[id]; ok || len(r.defIdToTypeDef) >= maxCachedTypeDefs {
+		return nil
+	}
+	remote, err := decodeTypeDef(r.fory, buffer, id)
+	if err != nil {
+		return err
+	}
+	if _, err := remote.getOrBuildTypeInfo(r); err != nil {
+		return err
+	}
+	r.defIdToTypeDef[id] = remote
+	return nil
+}
@@ -0,0 +1,102 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type preheatLine struct {
+	SKU string
+	Qty int32
+}
+
+type preheatCustomer struct {
+	Name string
+}
+
+type preheatOrder struct {
+	ID       int64
+	Customer *preheatCustomer
+	Lines    []preheatLine
+	ByRegion map[string][]preheatLine
+	Extra    any
+}
+
+type preheatUnregistered struct {
+	Lines []preheatLine
+	Other preheatCustomer
+}
+
+func TestPreheat(t *testing.T) {
+	for _, compatible := range []bool{false, true} {
+		f := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, f.RegisterStruct(preheatOrder{}, 1))
+		require.NoError(t, f.RegisterStruct(preheatLine{}, 2))
+		require.NoError(t, f.RegisterStruct(preheatCustomer{}, 3))
+		require.NoError(t, f.Preheat(&preheatOrder{}))
+
+		r := f.typeResolver
+		for _, v := range []any{preheatOrder{}, preheatLine{}, preheatCustomer{}} {
+			type_ := reflect.TypeOf(v)
+			s, ok := r.typesInfo[type_].Serializer.(*structSerializer)
+			require.True(t, ok)
+			require.True(t, s.initialized, "%v", type_)
+			_, ok = r.typeToTypeDef[type_]
+			require.Equal(t, compatible, ok, "%v", type_)
+		}
+		if compatible {
+			require.Len(t, r.defIdToTypeDef, 3)
+		}
+
+		order := &preheatOrder{
+			ID:       7,
+			Customer: &preheatCustomer{Name: "ada"},
+			Lines:    []preheatLine{{SKU: "a", Qty: 1}},
+			ByRegion: map[string][]preheatLine{"eu": {{SKU: "b", Qty: 2}}},
+		}
+		data, err := f.Serialize(order)
+		require.NoError(t, err)
+		var out preheatOrder
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, *order, out)
+	}
+}
+
+func TestPreheatReflectType(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(preheatLine{}, 2))
+	require.NoError(t, f.Preheat(reflect.TypeOf([]preheatLine{}), map[string]int32{}))
+	s := f.typeResolver.typesInfo[reflect.TypeOf(preheatLine{})].Serializer.(*structSerializer)
+	require.True(t, s.initialized)
+}
+
+func TestPreheatUnregistered(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(preheatUnregistered{}, 1))
+	require.NoError(t, f.RegisterStruct(preheatLine{}, 2))
+	err := f.Preheat(preheatUnregistered{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "preheatCustomer")
+	require.Error(t, f.Preheat(nil))
+}
@@ -0,0 +1,136 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"fmt"
+	"reflect"
+	"sync"
+	"unsafe"
+)
+
+// Projection selects the top-level struct fields UnmarshalProjected decodes.
+// Fields outside the projection are left untouched in the target. When the
+// payload carries field definitions (compatible mode) their bytes are skipped
+// without decoding; otherwise they are decoded into scratch and discarded.
+//
+// Declare a Projection once and reuse it: its resolution against each struct
+// serializer is cached. It is safe for concurrent use.
+type Projection struct {
+	names []string
+	// resolved caches a projectedFields per *structSerializer
+	resolved sync.Map
+}
+
+// projectedFields is a projection resolved against one struct serializer.
+type projectedFields struct {
+	fields    []FieldInfo // wire order, with skip actions for dropped fields
+	indices   []int       // local indices of the kept fields
+	skippable bool        // every dropped field can be skipped on the wire
+}
+
+// NewProjection returns a projection over the named Go struct fields.
+func NewProjection(fields ...string) *Projection {
+	return &Projection{names: fields}
+}
+
+func (p *Projection) resolve(s *structSerializer) (*projectedFields, error) {
+	if cached, ok := p.resolved.Load(s); ok {
+		return cached.(*projectedFields), nil
+	}
+	keep := make(map[int]bool, len(p.names))
+	projected := &projectedFields{skippable: true}
+	for _, name := range p.names {
+		field, ok := s.type_.FieldByName(name)
+		if !ok || len(field.Index) != 1 {
+			return nil, fmt.Errorf("struct %s has no field %s", s.type_, name)
+		}
+		if !keep[field.Index[0]] {
+			keep[field.Index[0]] = true
+			projected.indices = append(projected.indices, field.Index[0])
+		}
+	}
+
+	// Rebuild the wire order: TypeDef order when it differs from the local
+	// layout, otherwise the fixed, varint and remaining groups.
+	var fields []FieldInfo
+	if s.typeDefDiffers {
+		fields = append(fields, s.fields...)
+	} else {
+		group := &s.fieldGroup
+		fields = make([]FieldInfo, 0, len(group.FixedFields)+len(group.VarintFields)+len(group.RemainingFields))
+		for _, field := range group.FixedFields {
+			field.ReadAction = remoteFieldReadExactFixed
+			fields = append(fields, field)
+		}
+		for _, field := range group.VarintFields {
+			field.ReadAction = remoteFieldReadExactVarint
+			fields = append(fields, field)
+		}
+		for _, field := range group.RemainingFields {
+			field.ReadAction = remoteFieldReadExactRemaining
+			fields = append(fields, field)
+		}
+	}
+	for i := range fields {
+		field := &fields[i]
+		if field.ReadAction == remoteFieldReadSkip || keep[field.Meta.FieldIndex] {
+			continue
+		}
+		if field.Meta.FieldDef.typeSpec == nil {
+			projected.skippable = false
+			continue
+		}
+		field.ReadAction = remoteFieldReadSkip
+	}
+	projected.fields = fields
+	actual, _ := p.resolved.LoadOrStore(s, projected)
+	return actual.(*projectedFields), nil
+}
+
+// readProjected reads only the fields selected by ctx.projection into value.
+// The projection is consumed here so nested structs decode in full.
+func (s *structSerializer) readProjected(ctx *ReadContext, value reflect.Value) {
+	projection := ctx.projection
+	ctx.projection = nil
+	projected, err := projection.resolve(s)
+	if err != nil {
+		ctx.SetError(FromError(err))
+		return
+	}
+	read := s.readFieldsInOrder
+	if s.omitZero {
+		read = s.readPresentFields
+	}
+	if projected.skippable {
+		read(ctx, value, projected.fields)
+		return
+	}
+	scratch := reflect.New(s.type_).Elem()
+	read(ctx, scratch, projected.fields)
+	if ctx.HasError() {
+		return
+	}
+	dst := unsafe.Pointer(value.UnsafeAddr())
+	src := unsafe.Pointer(scratch.UnsafeAddr())
+	for _, idx := range projected.indices {
+		field := s.type_.Field(idx)
+		reflect.NewAt(field.Type, unsafe.Add(dst, field.Offset)).Elem().
+			Set(reflect.NewAt(field.Type, unsafe.Add(src, field.Offset)).Elem())
+	}
+}
@@ -0,0 +1,69 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestUnmarshalProjected(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithXlang(true)},
+		{WithXlang(true), WithCompatible(false)},
+		{WithXlang(false)},
+	} {
+		f := NewFory(opts...)
+		require.NoError(t, f.RegisterStruct(lazyNested{}, 310))
+		require.NoError(t, f.RegisterStruct(lazyRecord{}, 311))
+		want := newLazyRecord()
+		data, err := f.Marshal(want)
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+
+		projection := NewProjection("F2", "F5", "F7")
+		for i := 0; i < 2; i++ {
+			got := lazyRecord{F1: 99}
+			require.NoError(t, f.UnmarshalProjected(data, &got, projection))
+			require.Equal(t, lazyRecord{F1: 99, F2: want.F2, F5: want.F5, F7: want.F7}, got)
+		}
+
+		// The projection only applies to the call it is passed to.
+		var full lazyRecord
+		require.NoError(t, f.Unmarshal(data, &full))
+		require.Equal(t, *want, full)
+	}
+}
+
+func TestUnmarshalProjectedErrors(t *testing.T) {
+	f := NewFory(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(lazyNested{}, 310))
+	require.NoError(t, f.RegisterStruct(lazyRecord{}, 311))
+	data, err := f.Marshal(newLazyRecord())
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+
+	var got lazyRecord
+	require.Error(t, f.UnmarshalProjected(data, &got, NewProjection("Missing")))
+
+	refFory := NewFory(WithXlang(true), WithTrackRef(true))
+	require.Error(t, refFory.UnmarshalProjected(data, &got, NewProjection("F1")))
+}
@@ -0,0 +1,112 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package protocompat carries protobuf messages inside Fory payloads, so
+// systems migrating from protobuf can embed existing messages in Fory object
+// graphs before rewriting them as Fory structs.
+//
+// A message is written as a Fory extension type whose data is the message's
+// protobuf encoding, prefixed with its length in the same way as BINARY data.
+// The package does not depend on a protobuf runtime; callers pass the marshal
+// and unmarshal functions of the runtime they use:
+//
+//	s, err := protocompat.NewSerializer(&pb.User{}, proto.Marshal, proto.Unmarshal)
+//	if err != nil {
+//	    return err
+//	}
+//	err = f.RegisterExtensionByName(&pb.User{}, "example.User", s)
+//
+// Other languages read the message with an extension serializer registered
+// under the same name that decodes the bytes with their protobuf runtime.
+package protocompat
+
+import (
+	"fmt"
+	"reflect"
+
+	"github.com/apache/fory/go/fory"
+)
+
+type serializer[M any] struct {
+	type_     reflect.Type
+	marshal   func(M) ([]byte, error)
+	unmarshal func([]byte, M) error
+}
+
+// NewSerializer returns an extension serializer for the message type of msg,
+// which must be a pointer to a struct that implements M. M is usually the
+// runtime's message interface, such as proto.Message, and is inferred from
+// marshal and unmarshal. Register the result with RegisterExtension or
+// RegisterExtensionByName, passing msg as the type.
+func NewSerializer[M any](msg any, marshal func(M) ([]byte, error), unmarshal func([]byte, M) error) (fory.ExtensionSerializer, error) {
+	type_ := reflect.TypeOf(msg)
+	if type_ == nil || type_.Kind() != reflect.Ptr || type_.Elem().Kind() != reflect.Struct {
+		return nil, fmt.Errorf("protocompat: message must be a pointer to a struct, got %v", type_)
+	}
+	if _, ok := msg.(M); !ok {
+		return nil, fmt.Errorf("protocompat: %v does not implement %v", type_, reflect.TypeOf((*M)(nil)).Elem())
+	}
+	if marshal == nil || unmarshal == nil {
+		return nil, fmt.Errorf("protocompat: marshal and unmarshal must not be nil")
+	}
+	return &serializer[M]{type_: type_.Elem(), marshal: marshal, unmarshal: unmarshal}, nil
+}
+
+// WriteData is called with the message struct for fields and elements, and
+// with the message pointer for a root value.
+func (s *serializer[M]) WriteData(ctx *fory.WriteContext, value reflect.Value) {
+	ptr := value
+	if value.Kind() == reflect.Ptr {
+		if value.IsNil() {
+			ptr = reflect.New(s.type_)
+		}
+	} else if value.CanAddr() {
+		ptr = value.Addr()
+	} else {
+		ptr = reflect.New(s.type_)
+		ptr.Elem().Set(value)
+	}
+	data, err := s.marshal(ptr.Interface().(M))
+	if err != nil {
+		ctx.SetError(fory.SerializationErrorf("protocompat: marshal %v: %v", s.type_, err))
+		return
+	}
+	buf := ctx.Buffer()
+	buf.WriteLength(len(data))
+	buf.WriteBinary(data)
+}
+
+func (s *serializer[M]) ReadData(ctx *fory.ReadContext, value reflect.Value) {
+	length := ctx.ReadBinaryLength()
+	if ctx.HasError() {
+		return
+	}
+	data := ctx.Buffer().ReadBinary(length, ctx.Err())
+	if ctx.HasError() {
+		return
+	}
+	ptr := reflect.New(s.type_)
+	if err := s.unmarshal(data, ptr.Interface().(M)); err != nil {
+		ctx.SetError(fory.DeserializationErrorf("protocompat: unmarshal %v: %v", s.type_, err))
+		return
+	}
+	if value.Kind() == reflect.Ptr {
+		value.Set(ptr)
+	} else {
+		value.Set(ptr.Elem())
+	}
+}
@@ -0,0 +1,122 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package protocompat
+
+import (
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/require"
+)
+
+// message mirrors the Marshal/Unmarshal methods of gogo-style generated code.
+type message interface {
+	Marshal() ([]byte, error)
+	Unmarshal([]byte) error
+}
+
+type user struct {
+	Name string
+	Age  int32
+}
+
+func (u *user) Marshal() ([]byte, error) { return json.Marshal(u) }
+
+func (u *user) Unmarshal(data []byte) error { return json.Unmarshal(data, u) }
+
+func marshal(m message) ([]byte, error) { return m.Marshal() }
+
+func unmarshal(data []byte, m message) error { return m.Unmarshal(data) }
+
+type envelope struct {
+	Owner   *user
+	Members []*user
+	Note    string
+}
+
+func TestRoundTrip(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		f := fory.New(fory.WithXlang(xlang))
+		s, err := NewSerializer(&user{}, marshal, unmarshal)
+		require.NoError(t, err)
+		require.NoError(t, f.RegisterExtensionByName(&user{}, "example.User", s))
+		require.NoError(t, f.RegisterStructByName(envelope{}, "example.Envelope"))
+
+		in := &envelope{
+			Owner:   &user{Name: "ann", Age: 30},
+			Members: []*user{{Name: "bob"}, nil},
+			Note:    "team",
+		}
+		data, err := f.Serialize(in)
+		require.NoError(t, err)
+		var out envelope
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, in, &out)
+
+		data, err = f.Serialize(&envelope{Note: "empty"})
+		require.NoError(t, err)
+		out = envelope{}
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Nil(t, out.Owner)
+	}
+}
+
+func TestRootMessage(t *testing.T) {
+	f := fory.New(fory.WithXlang(true))
+	s, err := NewSerializer(&user{}, marshal, unmarshal)
+	require.NoError(t, err)
+	require.NoError(t, f.RegisterExtension(&user{}, 1, s))
+	data, err := f.Serialize(&user{Name: "ann", Age: 30})
+	require.NoError(t, err)
+
+	var out user
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, user{Name: "ann", Age: 30}, out)
+	var ptr *user
+	require.NoError(t, f.Deserialize(data, &ptr))
+	require.Equal(t, &user{Name: "ann", Age: 30}, ptr)
+	var value any
+	require.NoError(t, f.Deserialize(data, &value))
+	require.Equal(t, user{Name: "ann", Age: 30}, value)
+}
+
+func TestUnmarshalError(t *testing.T) {
+	f := fory.New(fory.WithXlang(true))
+	s, err := NewSerializer(&user{}, marshal, func([]byte, message) error {
+		return errors.New("bad message")
+	})
+	require.NoError(t, err)
+	require.NoError(t, f.RegisterExtension(&user{}, 1, s))
+	data, err := f.Serialize(&user{Name: "ann"})
+	require.NoError(t, err)
+	var out user
+	err = f.Deserialize(data, &out)
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "bad message")
+}
+
+func TestNewSerializerValidatesMessage(t *testing.T) {
+	_, err := NewSerializer(user{}, marshal, unmarshal)
+	require.Error(t, err)
+	_, err = NewSerializer(&struct{}{}, marshal, unmarshal)
+	require.Error(t, err)
+	_, err = NewSerializer[message](&user{}, nil, unmarshal)
+	require.Error(t, err)
+}
@@ -18,6 +18,8 @@
 package fory
 
 import (
+	"context"
+	"math"
 	"reflect"
 	"strconv"
 	"unsafe"
@@ -29,23 +31,49 @@ import (
 
 // ReadContext holds all state needed during deserialization.
 type ReadContext struct {
-	buffer            *ByteBuffer
-	refReader         *RefReader
-	trackRef          bool // Cached flag to avoid indirection
-	xlang             bool // Cross-language serialization mode
-	rootHeader        byte
-	compatible        bool          // Schema evolution compatibility mode
-	typeResolver      *TypeResolver // For complex type deserialization
-	refResolver       *RefResolver  // For reference tracking in native-mode paths
-	outOfBandBuffers  []*ByteBuffer // Out-of-band buffers for deserialization
-	outOfBandIndex    int           // Current index into out-of-band buffers
-	depth             int           // Current nesting depth for cycle detection
-	maxDepth          int           // Maximum allowed nesting depth
-	err               Error         // Accumulated error state for deferred checking
-	lastTypePtr       uintptr
-	lastTypeInfo      *TypeInfo
-	maxCollectionSize int // Size guardrail for collection reads
-	maxBinarySize     int // Size guardrail for binary reads
+	buffer               *ByteBuffer
+	refReader            *RefReader
+	trackRef             bool // Cached flag to avoid indirection
+	xlang                bool // Cross-language serialization mode
+	rootHeader           byte
+	compatible           bool          // Schema evolution compatibility mode
+	typeResolver         *TypeResolver // For complex type deserialization
+	refResolver          *RefResolver  // For reference tracking in native-mode paths
+	outOfBandBuffers     []*ByteBuffer // Out-of-band buffers for deserialization
+	outOfBandIndex       int           // Current index into out-of-band buffers
+	peerOutOfBand        bool          // Payload was written with a buffer callback
+	depth                int           // Current nesting depth for cycle detection
+	maxDepth             int           // Maximum allowed nesting depth
+	err                  Error         // Accumulated error state for deferred checking
+	lastTypePtr          uintptr
+	lastTypeInfo         *TypeInfo
+	bufferProvider       func(index int) *ByteBuffer
+	bufferCopies         *bufferCopies // Copies large buffer objects concurrently when set
+	inlineCopies         int           // Extension serializers being run, which may inspect buffers as they are read
+	stringDict           bool
+	dictStrings          []string
+	expectStringDict     bool
+	maxCollectionSize    int // Size guardrail for collection reads
+	maxBinarySize        int // Size guardrail for binary reads
+	decodeBudget         int // Bytes each call may decode; math.MaxInt when unlimited
+	budgetLeft           int
+	reuseObjects         bool
+	strings              *stringTable // Interns decoded strings when set
+	zeroCopyBinary       bool         // Return binary values as views of the input
+	omitHeader           bool
+	codec                Codec
+	checksum             bool        // Expect checksums in header-less payloads
+	inflated             ByteBuffer  // Decompressed body of the current payload
+	compressedBuffer     *ByteBuffer // Buffer the compressed body was read from
+	projection           *Projection // Applies to the next struct read, then cleared
+	skipUnknownTypes     bool        // Set by UnmarshalPartial
+	unknownTypes         []UnknownTypeWarning
+	genericRecords       bool // Decodes unknown structs as GenericRecord
+	unknownStructsAsMaps bool // Decodes unknown structs as map[string]any
+	// cancel is the context of an UnmarshalContext call, checked every
+	// cancelCheckInterval units of work.
+	cancel           context.Context
+	untilCancelCheck int
 }
 
 // IsXlang returns whether cross-language serialization mode is enabled
@@ -56,10 +84,12 @@ func (c *ReadContext) IsXlang() bool {
 // NewReadContext creates a new read context
 func NewReadContext(trackRef bool) *ReadContext {
 	return &ReadContext{
-		buffer:    NewByteBuffer(nil),
-		refReader: NewRefReader(trackRef),
-		trackRef:  trackRef,
-		maxDepth:  128, // Default maximum nesting depth
+		buffer:       NewByteBuffer(nil),
+		refReader:    NewRefReader(trackRef),
+		trackRef:     trackRef,
+		maxDepth:     128, // Default maximum nesting depth
+		decodeBudget: math.MaxInt,
+		budgetLeft:   math.MaxInt,
 	}
 }
 
@@ -67,8 +97,23 @@ func NewReadContext(trackRef bool) *ReadContext {
 func (c *ReadContext) Reset() {
 	c.refReader.Reset()
 	c.outOfBandBuffers = nil
+	c.bufferProvider = nil
 	c.outOfBandIndex = 0
+	c.peerOutOfBand = false
+	c.stringDict = false
+	clear(c.dictStrings)
+	c.dictStrings = c.dictStrings[:0]
 	c.err = Error{} // Clear error state
+	c.budgetLeft = c.decodeBudget
+	c.projection = nil
+	c.inlineCopies = 0
+	if c.compressedBuffer != nil {
+		if c.buffer == &c.inflated {
+			c.buffer = c.compressedBuffer
+		}
+		c.compressedBuffer = nil
+		c.inflated = ByteBuffer{}
+	}
 	if c.refResolver != nil {
 		c.refResolver.resetRead()
 	}
@@ -77,17 +122,21 @@ func (c *ReadContext) Reset() {
 	}
 }
 
+// skippedUnknownType records a skipped value of an unregistered type for
+// UnmarshalPartial.
+func (c *ReadContext) skippedUnknownType(warning UnknownTypeWarning) {
+	if c.skipUnknownTypes {
+		c.unknownTypes = append(c.unknownTypes, warning)
+	}
+}
+
 // SetData sets new input data (for buffer reuse)
 // Reuses existing buffer to avoid allocation
 func (c *ReadContext) SetData(data []byte) {
 	if c.buffer == nil {
-		c.buffer = NewByteBuffer(data)
-	} else {
-		c.buffer.data = data
-		c.buffer.readerIndex = 0
-		c.buffer.writerIndex = len(data)
-		c.buffer.reader = nil
+		c.buffer = &ByteBuffer{}
 	}
+	c.buffer.WrapReadOnly(data)
 }
 
 // Buffer returns the underlying buffer
@@ -115,6 +164,11 @@ func (c *ReadContext) RefResolver() *RefResolver {
 	return c.refResolver
 }
 
+// Depth returns the current nesting depth, counted by checked serializers, generic records and skipped values.
+func (c *ReadContext) Depth() int {
+	return c.depth
+}
+
 // ============================================================================
 // Error State Methods - For deferred error checking pattern
 // ============================================================================
@@ -240,7 +294,7 @@ func (c *ReadContext) readFast(ptr unsafe.Pointer, ct DispatchId) {
 	case PrimitiveFloat16DispatchId:
 		*(*uint16)(ptr) = c.buffer.ReadUint16(err)
 	case StringDispatchId:
-		*(*string)(ptr) = readString(c.buffer, err)
+		*(*string)(ptr) = c.ReadString()
 	}
 }
 
@@ -263,6 +317,7 @@ func (c *ReadContext) ReadCollectionLength() int {
 		c.SetError(MaxCollectionSizeExceededError(length, c.maxCollectionSize))
 		return 0
 	}
+	c.countWork(length)
 	return length
 }
 
@@ -277,9 +332,28 @@ func (c *ReadContext) ReadBinaryLength() int {
 		c.SetError(MaxBinarySizeExceededError(length, c.maxBinarySize))
 		return 0
 	}
+	if !c.charge(length) {
+		return 0
+	}
 	return length
 }
 
+// charge deducts bytes about to be decoded from the budget set with
+// WithDecodeBudget and reports whether decoding may go on.
+func (c *ReadContext) charge(bytes int) bool {
+	c.budgetLeft -= bytes
+	if c.budgetLeft < 0 {
+		c.SetError(DecodeBudgetExceededError(c.decodeBudget))
+		return false
+	}
+	return true
+}
+
+// chargeElems charges n values of type t against the decode budget.
+func (c *ReadContext) chargeElems(n int, t reflect.Type) bool {
+	return c.charge(n * int(t.Size()))
+}
+
 // ============================================================================
 // Typed Read Methods - Fastpath for codegen
 // For primitive numeric types, use ctx.Buffer().ReadXXX()
@@ -289,7 +363,15 @@ func (c *ReadContext) ReadBinaryLength() int {
 
 // ReadString reads a string value (caller handles nullable/type meta)
 func (c *ReadContext) ReadString() string {
-	return readString(c.buffer, c.Err())
+	if c.stringDict {
+		return c.readDictString()
+	}
+	if c.strings != nil {
+		return c.strings.read(c.buffer, c.Err())
+	}
+	s := readString(c.buffer, c.Err())
+	c.charge(len(s))
+	return s
 }
 
 // ReadBoolSlice reads []bool with ref/type info
@@ -307,6 +389,9 @@ func (c *ReadContext) ReadBoolSlice(refMode RefMode, readType bool) []bool {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[bool](c, nil)
+	}
 	return ReadBoolSlice(c.buffer, err)
 }
 
@@ -325,6 +410,9 @@ func (c *ReadContext) ReadInt8Slice(refMode RefMode, readType bool) []int8 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[int8](c, nil)
+	}
 	return ReadInt8Slice(c.buffer, err)
 }
 
@@ -343,6 +431,9 @@ func (c *ReadContext) ReadInt16Slice(refMode RefMode, readType bool) []int16 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[int16](c, nil)
+	}
 	return ReadInt16Slice(c.buffer, err)
 }
 
@@ -361,6 +452,9 @@ func (c *ReadContext) ReadInt32Slice(refMode RefMode, readType bool) []int32 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[int32](c, nil)
+	}
 	return ReadInt32Slice(c.buffer, err)
 }
 
@@ -379,6 +473,9 @@ func (c *ReadContext) ReadInt64Slice(refMode RefMode, readType bool) []int64 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[int64](c, nil)
+	}
 	return ReadInt64Slice(c.buffer, err)
 }
 
@@ -397,6 +494,9 @@ func (c *ReadContext) ReadUint16Slice(refMode RefMode, readType bool) []uint16 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[uint16](c, nil)
+	}
 	return ReadUint16Slice(c.buffer, err)
 }
 
@@ -415,6 +515,9 @@ func (c *ReadContext) ReadUint32Slice(refMode RefMode, readType bool) []uint32 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[uint32](c, nil)
+	}
 	return ReadUint32Slice(c.buffer, err)
 }
 
@@ -433,6 +536,9 @@ func (c *ReadContext) ReadUint64Slice(refMode RefMode, readType bool) []uint64 {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[uint64](c, nil)
+	}
 	return ReadUint64Slice(c.buffer, err)
 }
 
@@ -455,6 +561,9 @@ func (c *ReadContext) ReadIntSlice(refMode RefMode, readType bool) []int {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[int](c, nil)
+	}
 	return ReadIntSlice(c.buffer, err)
 }
 
@@ -477,6 +586,9 @@ func (c *ReadContext) ReadUintSlice(refMode RefMode, readType bool) []uint {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[uint](c, nil)
+	}
 	return ReadUintSlice(c.buffer, err)
 }
 
@@ -495,6 +607,9 @@ func (c *ReadContext) ReadFloat32Slice(refMode RefMode, readType bool) []float32
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[float32](c, nil)
+	}
 	return ReadFloat32Slice(c.buffer, err)
 }
 
@@ -513,6 +628,9 @@ func (c *ReadContext) ReadFloat64Slice(refMode RefMode, readType bool) []float64
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[float64](c, nil)
+	}
 	return ReadFloat64Slice(c.buffer, err)
 }
 
@@ -531,8 +649,27 @@ func (c *ReadContext) ReadByteSlice(refMode RefMode, readType bool) []byte {
 			return nil
 		}
 	}
+	if c.peerOutOfBand {
+		return readArrayBufferObject[byte](c, nil)
+	}
 	size := c.ReadBinaryLength()
-	return c.buffer.ReadBinary(size, err)
+	return c.readBinaryInto(size, nil)
+}
+
+// readBinaryInto reads size bytes of binary data, copying them into dst when
+// it has room. With WithZeroCopyBinary it returns a view of the input instead,
+// capped so appending to it cannot overwrite the rest of the payload.
+func (c *ReadContext) readBinaryInto(size int, dst []byte) []byte {
+	raw := c.buffer.ReadBinary(size, c.Err())
+	if c.HasError() {
+		return nil
+	}
+	if c.zeroCopyBinary {
+		return raw[:size:size]
+	}
+	result := reuseSlice(dst, size)
+	copy(result, raw)
+	return result
 }
 
 // ReadStringSlice reads []string with optional ref/type info using LIST protocol
@@ -546,7 +683,7 @@ func (c *ReadContext) ReadStringSlice(refMode RefMode, readType bool) []string {
 	if readType {
 		_ = c.buffer.ReadUint8(err)
 	}
-	return ReadStringSlice(c.buffer, err)
+	return readStringSliceInto(c.buffer, err, c, nil)
 }
 
 // ReadStringStringMap reads map[string]string with optional ref/type info
@@ -560,7 +697,7 @@ func (c *ReadContext) ReadStringStringMap(refMode RefMode, readType bool) map[st
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringString(c)
+	return readMapStringString(c, nil)
 }
 
 // ReadStringInt64Map reads map[string]int64 with optional ref/type info
@@ -574,7 +711,7 @@ func (c *ReadContext) ReadStringInt64Map(refMode RefMode, readType bool) map[str
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringInt64(c)
+	return readMapStringInt64(c, nil)
 }
 
 // ReadStringInt32Map reads map[string]int32 with optional ref/type info
@@ -588,7 +725,7 @@ func (c *ReadContext) ReadStringInt32Map(refMode RefMode, readType bool) map[str
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringInt32(c)
+	return readMapStringInt32(c, nil)
 }
 
 // ReadStringIntMap reads map[string]int with optional ref/type info
@@ -602,7 +739,7 @@ func (c *ReadContext) ReadStringIntMap(refMode RefMode, readType bool) map[strin
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringInt(c)
+	return readMapStringInt(c, nil)
 }
 
 // ReadStringFloat64Map reads map[string]float64 with optional ref/type info
@@ -616,7 +753,7 @@ func (c *ReadContext) ReadStringFloat64Map(refMode RefMode, readType bool) map[s
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringFloat64(c)
+	return readMapStringFloat64(c, nil)
 }
 
 // ReadStringBoolMap reads map[string]bool with optional ref/type info
@@ -630,7 +767,7 @@ func (c *ReadContext) ReadStringBoolMap(refMode RefMode, readType bool) map[stri
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapStringBool(c)
+	return readMapStringBool(c, nil)
 }
 
 // ReadInt32Int32Map reads map[int32]int32 with optional ref/type info
@@ -644,7 +781,7 @@ func (c *ReadContext) ReadInt32Int32Map(refMode RefMode, readType bool) map[int3
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapInt32Int32(c)
+	return readMapInt32Int32(c, nil)
 }
 
 // ReadInt64Int64Map reads map[int64]int64 with optional ref/type info
@@ -658,7 +795,7 @@ func (c *ReadContext) ReadInt64Int64Map(refMode RefMode, readType bool) map[int6
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapInt64Int64(c)
+	return readMapInt64Int64(c, nil)
 }
 
 // ReadIntIntMap reads map[int]int with optional ref/type info
@@ -672,7 +809,7 @@ func (c *ReadContext) ReadIntIntMap(refMode RefMode, readType bool) map[int]int
 	if readType && !c.readExpectedTypeID(MAP) {
 		return nil
 	}
-	return readMapIntInt(c)
+	return readMapIntInt(c, nil)
 }
 
 // ReadBufferObject reads a buffer object
@@ -681,20 +818,45 @@ func (c *ReadContext) ReadBufferObject() *ByteBuffer {
 	isInBand := c.buffer.ReadBool(err)
 	if isInBand {
 		size := c.ReadBinaryLength()
-		buf := c.buffer.Slice(c.buffer.readerIndex, size)
-		c.buffer.readerIndex += size
-		return buf
+		return NewByteBuffer(c.buffer.ReadBinary(size, err))
+	}
+	// Out-of-band: get the next buffer from the provider or the buffers list
+	var buf *ByteBuffer
+	if c.bufferProvider != nil {
+		buf = c.bufferProvider(c.outOfBandIndex)
+	} else if c.outOfBandIndex < len(c.outOfBandBuffers) {
+		buf = c.outOfBandBuffers[c.outOfBandIndex]
 	}
-	// Out-of-band: get the next buffer from the out-of-band buffers list
-	if c.outOfBandBuffers == nil || c.outOfBandIndex >= len(c.outOfBandBuffers) {
+	if buf == nil {
 		c.SetError(DeserializationErrorf("out-of-band buffer expected but not available at index %d", c.outOfBandIndex))
 		return nil
 	}
-	buf := c.outOfBandBuffers[c.outOfBandIndex]
 	c.outOfBandIndex++
+	if !c.charge(len(buf.GetData())) {
+		return nil
+	}
 	return buf
 }
 
+// readNullRoot consumes the null flag of a null root value and reports
+// whether it was present.
+func (c *ReadContext) readNullRoot() bool {
+	buf := c.buffer
+	if buf.readerIndex+1 > len(buf.data) && !buf.fill(1, c.Err()) {
+		return false
+	}
+	if int8(buf.data[buf.readerIndex]) != NullFlag {
+		return false
+	}
+	buf.readerIndex++
+	return true
+}
+
+// hasOutOfBandSource reports whether the caller supplied out-of-band buffers.
+func (c *ReadContext) hasOutOfBandSource() bool {
+	return c.outOfBandBuffers != nil || c.bufferProvider != nil
+}
+
 // incDepth increments the nesting depth and checks for overflow
 func (c *ReadContext) incDepth() {
 	c.depth++
@@ -719,7 +881,7 @@ func (c *ReadContext) ReadValue(value reflect.Value, refMode RefMode, readType b
 	}
 
 	// Handle array targets (arrays are serialized as slices)
-	if value.Type().Kind() == reflect.Array {
+	if value.Type().Kind() == reflect.Array && !c.typeResolver.isExtensionType(value.Type()) {
 		c.ReadArrayValue(value, refMode, readType)
 		return
 	}
@@ -765,10 +927,14 @@ func (c *ReadContext) ReadValue(value reflect.Value, refMode RefMode, readType b
 		actualType := typeInfo.Type
 		if actualType == nil {
 			// Unknown type - skip the data using the serializer (skipStructSerializer)
+			// Unknown structs are read into value when decoded as records
+			// or maps; otherwise the value is left nil.
 			if typeInfo.Serializer != nil {
-				typeInfo.Serializer.ReadData(c, reflect.Value{})
+				typeInfo.Serializer.ReadData(c, value)
+			}
+			if c.decodesUnknownStructs() && !value.IsNil() {
+				c.RefResolver().SetReadObject(refID, value.Elem())
 			}
-			// Leave interface value as nil for unknown types
 			return
 		}
 
@@ -797,6 +963,11 @@ func (c *ReadContext) ReadValue(value reflect.Value, refMode RefMode, readType b
 		} else {
 			newValue = reflect.New(actualType).Elem()
 			valueToSet = newValue
+			// Extension structs whose methods have pointer receivers only
+			// satisfy the target interface as pointers.
+			if actualType.Kind() == reflect.Struct && !actualType.AssignableTo(value.Type()) {
+				valueToSet = newValue.Addr()
+			}
 		}
 
 		// For named structs, register the pointer BEFORE reading data
@@ -820,7 +991,7 @@ func (c *ReadContext) ReadValue(value reflect.Value, refMode RefMode, readType b
 
 		// Register reference after reading data for non-struct types
 		if !isNamedStruct && refMode == RefModeTracking && refID >= int32(NotNullValueFlag) {
-			c.RefResolver().SetReadObject(refID, newValue)
+			c.RefResolver().SetReadObject(refID, valueToSet)
 		}
 
 		// Set the interface value
@@ -836,7 +1007,7 @@ func (c *ReadContext) ReadValue(value reflect.Value, refMode RefMode, readType b
 	// For struct types, use optimized ReadStruct path when using full ref tracking and type info.
 	// Unions use a custom serializer and must bypass ReadStruct.
 	valueType := value.Type()
-	if refMode == RefModeTracking && readType && !c.typeResolver.IsUnionType(valueType) {
+	if refMode == RefModeTracking && readType && !c.typeResolver.IsUnionType(valueType) && valueType != genericRecordPtrType {
 		if valueType.Kind() == reflect.Struct {
 			c.ReadStruct(value)
 			return
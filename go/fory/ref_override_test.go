@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -124,3 +126,66 @@ func TestCollectionReadFollowsRemoteRefMetadata(t *testing.T) {
 		assert.NotSame(t, output.MapField["k1"], output.MapField["k2"])
 	})
 }
+
+func TestNonReferencableTypeSkipsRefTracking(t *testing.T) {
+	writer := New(WithXlang(true), WithCompatible(false), WithTrackRef(true))
+	require.NoError(t, writer.RegisterStruct(refOverrideTestElement{}, 700, WithNonReferencable()))
+	require.NoError(t, writer.RegisterStruct(refTrackingTestContainer{}, 701))
+	// Readers decode the output without registering the option.
+	reader := newRefOverrideTestFory(t, refTrackingTestContainer{})
+
+	shared := &refOverrideTestElement{ID: 7, Name: "shared_element"}
+	input := &refTrackingTestContainer{
+		ListField: []*refOverrideTestElement{shared, shared, nil},
+		SetField:  Set[*refOverrideTestElement]{shared: {}},
+		MapField:  map[string]*refOverrideTestElement{"k1": shared, "k2": shared},
+	}
+	tracked, err := reader.Serialize(input)
+	require.NoError(t, err)
+	tracked = append([]byte(nil), tracked...)
+	data, err := writer.Serialize(input)
+	require.NoError(t, err)
+	require.Greater(t, len(data), len(tracked), "shared element must be written at every occurrence")
+
+	var output refTrackingTestContainer
+	require.NoError(t, reader.Deserialize(data, &output))
+	require.Len(t, output.ListField, 3)
+	require.Equal(t, *shared, *output.ListField[0])
+	require.Equal(t, *shared, *output.ListField[1])
+	require.NotSame(t, output.ListField[0], output.ListField[1])
+	require.Nil(t, output.ListField[2])
+	require.Equal(t, *shared, *onlyRefOverrideSetElement(t, output.SetField))
+	require.NotSame(t, output.MapField["k1"], output.MapField["k2"])
+	require.Equal(t, *shared, *output.MapField["k2"])
+
+	// Other types keep reference semantics.
+	containers := []any{input, input}
+	data, err = writer.Serialize(containers)
+	require.NoError(t, err)
+	var decoded []any
+	require.NoError(t, reader.Deserialize(data, &decoded))
+	require.Len(t, decoded, 2)
+	require.Same(t, decoded[0], decoded[1])
+}
+
+func TestUnregisterNonReferencableType(t *testing.T) {
+	f := New(WithXlang(true), WithCompatible(false), WithTrackRef(true))
+	require.NoError(t, f.RegisterStruct(refOverrideTestElement{}, 700, WithNonReferencable()))
+	shared := &refOverrideTestElement{ID: 7, Name: "shared_element"}
+	roundTrip := func() []*refOverrideTestElement {
+		data, err := f.Serialize([]*refOverrideTestElement{shared, shared})
+		require.NoError(t, err)
+		var output []*refOverrideTestElement
+		require.NoError(t, f.Deserialize(data, &output))
+		require.Len(t, output, 2)
+		return output
+	}
+	output := roundTrip()
+	require.NotSame(t, output[0], output[1])
+
+	// Registering again without the option restores reference tracking.
+	require.NoError(t, f.Unregister(refOverrideTestElement{}))
+	require.NoError(t, f.RegisterStruct(refOverrideTestElement{}, 700))
+	output = roundTrip()
+	require.Same(t, output[0], output[1])
+}
@@ -19,6 +19,7 @@ package fory
 
 import (
 	"fmt"
+	"log/slog"
 	"reflect"
 	"unsafe"
 )
@@ -65,10 +66,13 @@ const (
 // RefResolver class is used to track objects that have already been read or written.
 type RefResolver struct {
 	refTracking    bool
+	untrackedTypes map[reflect.Type]struct{} // Pointer types registered with WithNonReferencable
 	writtenObjects map[refKey]int32
+	writtenValues  map[any]int32 // Map keys tracked by value (WithMapKeyRefTracking)
 	readObjects    []reflect.Value
 	readRefIds     []int32
 	readObject     reflect.Value // last read object which is not a reference
+	trace          *slog.Logger  // Receives a record per ref flag when set
 }
 
 type refKey struct {
@@ -115,6 +119,15 @@ func isRefType(t reflect.Type, xlang bool) bool {
 	return isReferencable(t)
 }
 
+// skipPointerType makes WriteRefOrNull write values of pointer type t without
+// recording them.
+func (r *RefResolver) skipPointerType(t reflect.Type) {
+	if r.untrackedTypes == nil {
+		r.untrackedTypes = make(map[reflect.Type]struct{})
+	}
+	r.untrackedTypes[t] = struct{}{}
+}
+
 func newRefResolver(refTracking bool) *RefResolver {
 	refResolver := &RefResolver{
 		refTracking:    refTracking,
@@ -129,6 +142,18 @@ func newRefResolver(refTracking bool) *RefResolver {
 // Note that for slice and substring, if the start addr or length are different, we take two objects as
 // different references.
 func (r *RefResolver) WriteRefOrNull(buffer *ByteBuffer, value reflect.Value) (refWritten bool, err error) {
+	if r.trace != nil {
+		offset := buffer.writerIndex
+		refWritten, err = r.writeRefOrNull(buffer, value)
+		if err == nil {
+			traceRefFlag(r.trace, "fory write ref flag", offset, int8(buffer.data[offset]))
+		}
+		return refWritten, err
+	}
+	return r.writeRefOrNull(buffer, value)
+}
+
+func (r *RefResolver) writeRefOrNull(buffer *ByteBuffer, value reflect.Value) (refWritten bool, err error) {
 	if !r.refTracking || value.Kind() == reflect.String {
 		if isNil(value) {
 			buffer.WriteInt8(NullFlag)
@@ -144,6 +169,16 @@ func (r *RefResolver) WriteRefOrNull(buffer *ByteBuffer, value reflect.Value) (r
 	// reference types such as channel/function are not handled here and will be handled by typeResolver.
 	switch kind {
 	case reflect.Ptr:
+		if r.untrackedTypes != nil {
+			if _, ok := r.untrackedTypes[value.Type()]; ok {
+				if value.IsNil() {
+					buffer.WriteInt8(NullFlag)
+					return true, nil
+				}
+				buffer.WriteInt8(NotNullValueFlag)
+				return false, nil
+			}
+		}
 		elemValue := value.Elem()
 		if elemValue.Kind() == reflect.Array {
 			length = elemValue.Len()
@@ -156,7 +191,7 @@ func (r *RefResolver) WriteRefOrNull(buffer *ByteBuffer, value reflect.Value) (r
 		length = value.Len()
 	case reflect.Interface:
 		value = value.Elem()
-		return r.WriteRefOrNull(buffer, value)
+		return r.writeRefOrNull(buffer, value)
 	case reflect.Invalid:
 		isNil = true
 	case reflect.Struct:
@@ -181,7 +216,7 @@ func (r *RefResolver) WriteRefOrNull(buffer *ByteBuffer, value reflect.Value) (r
 			return true, nil
 		} else {
 			// The id should be consistent with `nextReadRefId`
-			newWriteRefId := len(r.writtenObjects)
+			newWriteRefId := len(r.writtenObjects) + len(r.writtenValues)
 			if newWriteRefId >= MaxInt32 {
 				return false, fmt.Errorf("too many objects execced %d to serialize", MaxInt32)
 			}
@@ -197,6 +232,9 @@ func (r *RefResolver) WriteRefOrNull(buffer *ByteBuffer, value reflect.Value) (r
 // null and ref tracking is not enabled or the object is first read.
 func (r *RefResolver) ReadRefOrNull(buffer *ByteBuffer, ctxErr *Error) int8 {
 	refTag := buffer.ReadInt8(ctxErr)
+	if r.trace != nil && !ctxErr.HasError() {
+		traceRefFlag(r.trace, "fory read ref flag", buffer.readerIndex-1, refTag)
+	}
 	if !r.refTracking {
 		return refTag
 	}
@@ -234,6 +272,9 @@ func (r *RefResolver) TryPreserveRefId(buffer *ByteBuffer) (int32, error) {
 	if ctxErr.HasError() {
 		return 0, ctxErr
 	}
+	if r.trace != nil {
+		traceRefFlag(r.trace, "fory read ref flag", buffer.readerIndex-1, headFlag)
+	}
 	if headFlag == RefFlag {
 		// read ref id and get object from ref resolver
 		refId := buffer.ReadVarUint32(&ctxErr)
@@ -322,6 +363,24 @@ func (r *RefResolver) resetRead() {
 func (r *RefResolver) resetWrite() {
 	// Use clear() instead of allocating a new map to reduce allocations
 	clear(r.writtenObjects)
+	clear(r.writtenValues)
+}
+
+// writeValueRef writes a reference to a previously written value equal to v,
+// or the ref-value flag if v is new. Values share the id space of objects
+// tracked by WriteRefOrNull. Returns true if no value data needs to follow.
+func (r *RefResolver) writeValueRef(buffer *ByteBuffer, v any) bool {
+	if id, ok := r.writtenValues[v]; ok {
+		buffer.WriteInt8(RefFlag)
+		buffer.WriteVarUint32(uint32(id))
+		return true
+	}
+	if r.writtenValues == nil {
+		r.writtenValues = make(map[any]int32)
+	}
+	r.writtenValues[v] = int32(len(r.writtenObjects) + len(r.writtenValues))
+	buffer.WriteInt8(RefValueFlag)
+	return false
 }
 
 func nullable(type_ reflect.Type) bool {
@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -218,3 +220,55 @@ func TestRefResolverBoundaryRegression(t *testing.T) {
 	require.Equal(t, val.String(), resolver.GetReadObject(0).String(), "Should resolve index 0")
 	require.Equal(t, val.String(), resolver.GetReadObject(1).String(), "Should resolve index len-1")
 }
+
+type refCycleNode struct {
+	Name     string
+	Parent   *refCycleNode
+	Children []*refCycleNode
+	ByName   map[string]*refCycleNode
+	Any      []any
+	AnyMap   map[string]any
+	Dynamic  map[any]any
+}
+
+// TestRefCyclesThroughContainers checks that pointers back to an ancestor
+// reached through slices and maps decode to the ancestor itself.
+func TestRefCyclesThroughContainers(t *testing.T) {
+	for _, xlang := range []bool{false, true} {
+		for _, compatible := range []bool{false, true} {
+			f := New(WithXlang(xlang), WithCompatible(compatible), WithRefTracking(true))
+			require.NoError(t, f.RegisterStructByName(refCycleNode{}, "example.RefCycleNode"))
+
+			root := &refCycleNode{Name: "root"}
+			child := &refCycleNode{Name: "child", Parent: root}
+			root.Children = []*refCycleNode{child, child}
+			root.ByName = map[string]*refCycleNode{"child": child, "self": root}
+			child.Any = []any{root, child}
+			child.AnyMap = map[string]any{"root": root, "self": child}
+			child.Dynamic = map[any]any{"root": root, int32(1): child}
+			data, err := f.Marshal(root)
+			require.NoError(t, err)
+
+			var decoded *refCycleNode
+			require.NoError(t, f.Unmarshal(data, &decoded), "xlang=%v compatible=%v", xlang, compatible)
+			var dynamic any
+			require.NoError(t, f.Unmarshal(data, &dynamic), "xlang=%v compatible=%v", xlang, compatible)
+			for _, got := range []*refCycleNode{decoded, dynamic.(*refCycleNode)} {
+				require.Equal(t, "root", got.Name)
+				require.Len(t, got.Children, 2)
+				c := got.Children[0]
+				require.Equal(t, "child", c.Name)
+				require.Same(t, c, got.Children[1])
+				require.Same(t, got, c.Parent)
+				require.Same(t, c, got.ByName["child"])
+				require.Same(t, got, got.ByName["self"])
+				require.Same(t, got, c.Any[0])
+				require.Same(t, c, c.Any[1])
+				require.Same(t, got, c.AnyMap["root"])
+				require.Same(t, c, c.AnyMap["self"])
+				require.Same(t, got, c.Dynamic["root"])
+				require.Same(t, c, c.Dynamic[int32(1)])
+			}
+		}
+	}
+}
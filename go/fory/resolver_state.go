@@ -0,0 +1,195 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+)
+
+const resolverStateVersion = 1
+
+// resolverState is the form written by ExportResolverState.
+type resolverState struct {
+	Version       int                 `json:"version"`
+	Registrations []registrationState `json:"registrations"`
+}
+
+// registrationState describes one struct, enum or alias registration.
+type registrationState struct {
+	Kind string `json:"kind"` // "struct", "enum" or "alias"
+	// Type identifies the Go type by package path and name.
+	Type string `json:"type"`
+	// Name is the registered name, or the old name of an alias.
+	Name string  `json:"name,omitempty"`
+	ID   *uint32 `json:"id,omitempty"`
+	// Ordinals lists the values of enums registered with RegisterEnumByOrdinal.
+	Ordinals        []uint64          `json:"ordinals,omitempty"`
+	NonReferencable bool              `json:"non_referencable,omitempty"`
+	FieldTags       map[string]string `json:"field_tags,omitempty"`
+}
+
+// ExportResolverState returns the struct, enum and alias registrations of f,
+// with their IDs, names, field tags and options, as bytes that
+// ImportResolverState replays on another instance. Short-lived workers can
+// restore the registrations of a configured instance from these bytes
+// instead of running the registration code.
+//
+// Extension types, unions, hooks and serializer replacements hold Go values
+// that cannot be written and are left out; register them in code.
+func (f *Fory) ExportResolverState() ([]byte, error) {
+	r := f.typeResolver
+	state := resolverState{Version: resolverStateVersion, Registrations: []registrationState{}}
+	exported := make(map[reflect.Type]string)
+	for type_, info := range r.typesInfo {
+		var kind string
+		switch TypeId(info.TypeID) {
+		case STRUCT, COMPATIBLE_STRUCT, NAMED_STRUCT, NAMED_COMPATIBLE_STRUCT:
+			kind = "struct"
+		case ENUM, NAMED_ENUM:
+			kind = "enum"
+		default:
+			continue
+		}
+		if type_.Kind() == reflect.Ptr {
+			continue
+		}
+		reg := registrationState{Kind: kind, Type: stateTypeName(type_), FieldTags: r.fieldTags[type_]}
+		reg.Name, reg.ID = r.registeredName(info)
+		if _, ok := f.refResolver.untrackedTypes[reflect.PointerTo(type_)]; ok {
+			reg.NonReferencable = true
+		}
+		if s, ok := info.Serializer.(*enumSerializer); ok {
+			reg.Ordinals = s.values
+		}
+		exported[type_] = reg.Name
+		state.Registrations = append(state.Registrations, reg)
+	}
+	for key, info := range r.namedTypeToTypeInfo {
+		type_ := info.Type
+		if type_.Kind() == reflect.Ptr {
+			type_ = type_.Elem()
+		}
+		name, ok := exported[type_]
+		oldName := joinRegisteredName(key[0], key[1])
+		if !ok || oldName == name {
+			continue
+		}
+		state.Registrations = append(state.Registrations,
+			registrationState{Kind: "alias", Type: stateTypeName(type_), Name: oldName})
+	}
+	// Aliases are replayed after the types they name.
+	sort.Slice(state.Registrations, func(i, j int) bool {
+		a, b := state.Registrations[i], state.Registrations[j]
+		if (a.Kind == "alias") != (b.Kind == "alias") {
+			return b.Kind == "alias"
+		}
+		if a.Type != b.Type {
+			return a.Type < b.Type
+		}
+		return a.Name < b.Name
+	})
+	return json.Marshal(state)
+}
+
+// ImportResolverState replays the registrations written by
+// ExportResolverState. Go types cannot be looked up by name, so types lists
+// the types the state registers, as reflect.Types or instances; it may hold
+// more types than the state uses. Types are matched by package path and name.
+//
+// The registrations are recorded like direct ones, so Clone and Unregister
+// handle them. If one fails, the earlier ones stay registered.
+func (f *Fory) ImportResolverState(state []byte, types ...any) error {
+	var s resolverState
+	if err := json.Unmarshal(state, &s); err != nil {
+		return fmt.Errorf("invalid resolver state: %w", err)
+	}
+	if s.Version != resolverStateVersion {
+		return fmt.Errorf("unsupported resolver state version %d", s.Version)
+	}
+	byName := make(map[string]reflect.Type, len(types))
+	for _, type_ := range types {
+		t := registeredType(type_)
+		if t == nil {
+			return fmt.Errorf("ImportResolverState cannot match a nil type")
+		}
+		byName[stateTypeName(t)] = t
+	}
+	for _, reg := range s.Registrations {
+		t, ok := byName[reg.Type]
+		if !ok {
+			return fmt.Errorf("resolver state registers %s, which is not among the given types", reg.Type)
+		}
+		if err := f.importRegistration(t, reg); err != nil {
+			return fmt.Errorf("import registration of %s: %w", reg.Type, err)
+		}
+	}
+	return nil
+}
+
+func (f *Fory) importRegistration(t reflect.Type, reg registrationState) error {
+	switch reg.Kind {
+	case "struct":
+		var opts []RegisterOption
+		if reg.NonReferencable {
+			opts = append(opts, WithNonReferencable())
+		}
+		for field, tag := range reg.FieldTags {
+			opts = append(opts, WithFieldTag(field, tag))
+		}
+		if reg.ID != nil {
+			return f.RegisterStruct(t, *reg.ID, opts...)
+		}
+		return f.RegisterStructByName(t, reg.Name, opts...)
+	case "enum":
+		if reg.ID == nil {
+			return f.RegisterEnumByName(t, reg.Name)
+		}
+		if reg.Ordinals == nil {
+			return f.RegisterEnum(t, *reg.ID)
+		}
+		values := make([]any, len(reg.Ordinals))
+		for i, bits := range reg.Ordinals {
+			v := reflect.New(t).Elem()
+			switch t.Kind() {
+			case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+				v.SetInt(int64(bits))
+			case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+				v.SetUint(bits)
+			default:
+				return fmt.Errorf("enum type %v is not numeric", t)
+			}
+			values[i] = v.Interface()
+		}
+		return f.RegisterEnumByOrdinal(t, *reg.ID, values...)
+	case "alias":
+		return f.RegisterTypeAlias(reg.Name, t)
+	default:
+		return fmt.Errorf("unknown registration kind %q", reg.Kind)
+	}
+}
+
+// stateTypeName identifies a Go type across processes.
+func stateTypeName(t reflect.Type) string {
+	if t.Name() == "" {
+		return t.String()
+	}
+	return t.PkgPath() + "." + t.Name()
+}
@@ -0,0 +1,111 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type stateOrder struct {
+	ID    int64
+	Count int64
+	Level ordinalEnum
+	Audit namedAuditEnum
+}
+
+type stateItem struct {
+	Name string
+}
+
+type stateEvent struct {
+	Order *stateOrder
+	Items []stateItem
+	Note  string
+}
+
+func registerStateTypes(t *testing.T, f *Fory) {
+	require.NoError(t, f.RegisterStruct(stateOrder{}, 1, WithFieldTag("Count", "-"), WithNonReferencable()))
+	require.NoError(t, f.RegisterStructByName(stateEvent{}, "demo.Event", WithNestedStructs()))
+	require.NoError(t, f.RegisterEnumByOrdinal(ordinalEnum(0), 7, ordinalLow, ordinalMid, ordinalHigh))
+	require.NoError(t, f.RegisterEnumByName(namedAuditEnum(0), "demo.Audit"))
+	require.NoError(t, f.RegisterTypeAlias("legacy.Event", stateEvent{}))
+	require.NoError(t, f.RegisterExtension(checkedVersion{}, 9, CheckedExtension(checkedVersionSerializer{})))
+}
+
+func TestResolverState(t *testing.T) {
+	for _, compatible := range []bool{false, true} {
+		f := New(WithXlang(true), WithCompatible(compatible))
+		registerStateTypes(t, f)
+		state, err := f.ExportResolverState()
+		require.NoError(t, err)
+
+		g := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, g.ImportResolverState(state,
+			stateOrder{}, stateItem{}, &stateEvent{}, ordinalEnum(0), namedAuditEnum(0), int64(0)))
+		// Extension types are not part of the state.
+		require.NoError(t, g.RegisterExtension(checkedVersion{}, 9, CheckedExtension(checkedVersionSerializer{})))
+
+		restored, err := g.ExportResolverState()
+		require.NoError(t, err)
+		require.JSONEq(t, string(state), string(restored))
+		want, err := f.ExportSchemas()
+		require.NoError(t, err)
+		got, err := g.ExportSchemas()
+		require.NoError(t, err)
+		require.JSONEq(t, string(want), string(got))
+
+		in := &stateEvent{Order: &stateOrder{ID: 1, Level: ordinalHigh, Audit: 3}, Items: []stateItem{{Name: "a"}}, Note: "n"}
+		data, err := f.Serialize(in)
+		require.NoError(t, err)
+		var out stateEvent
+		require.NoError(t, g.Deserialize(data, &out))
+		require.Equal(t, in, &out)
+		data, err = g.Serialize(in)
+		require.NoError(t, err)
+		out = stateEvent{}
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, in, &out)
+
+		clone := mustClone(t, g)
+		data, err = clone.Serialize(in)
+		require.NoError(t, err)
+		out = stateEvent{}
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, in, &out)
+	}
+}
+
+func TestResolverStateMissingType(t *testing.T) {
+	f := New(WithXlang(true))
+	registerStateTypes(t, f)
+	state, err := f.ExportResolverState()
+	require.NoError(t, err)
+
+	err = New(WithXlang(true)).ImportResolverState(state,
+		stateOrder{}, stateEvent{}, ordinalEnum(0), namedAuditEnum(0))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "fory.stateItem, which is not among the given types")
+
+	err = New(WithXlang(true)).ImportResolverState([]byte("{}"))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "unsupported resolver state version 0")
+}
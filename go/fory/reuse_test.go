@@ -0,0 +1,106 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type reuseInner struct {
+	Values []int64
+}
+
+type reuseRecord struct {
+	Ids    []int32
+	Names  []string
+	Scores map[string]int32
+	Inner  *reuseInner
+	Items  []reuseInner
+}
+
+func newReuseFory(t *testing.T, xlang bool, opts ...Option) *Fory {
+	f := NewFory(append([]Option{WithXlang(xlang), WithCompatible(false)}, opts...)...)
+	require.NoError(t, f.RegisterStruct(reuseInner{}, 301))
+	require.NoError(t, f.RegisterStruct(reuseRecord{}, 302))
+	return f
+}
+
+func TestObjectReuse(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		f := newReuseFory(t, xlang, WithObjectReuse(true))
+		first := &reuseRecord{
+			Ids:    []int32{1, 2, 3, 4},
+			Names:  []string{"a", "b", "c"},
+			Scores: map[string]int32{"x": 1, "y": 2},
+			Inner:  &reuseInner{Values: []int64{7, 8, 9}},
+			Items:  []reuseInner{{Values: []int64{1}}, {Values: []int64{2}}},
+		}
+		second := &reuseRecord{
+			Ids:    []int32{5, 6},
+			Names:  []string{"d"},
+			Scores: map[string]int32{"z": 3},
+			Inner:  &reuseInner{Values: []int64{10}},
+			Items:  []reuseInner{{Values: []int64{3}}},
+		}
+
+		data, err := f.Marshal(first)
+		require.NoError(t, err)
+		var target reuseRecord
+		require.NoError(t, f.Unmarshal(data, &target))
+		require.Equal(t, *first, target)
+
+		ids, names, scores, inner := &target.Ids[0], &target.Names[0], target.Scores, target.Inner
+		items := &target.Items[0]
+		data, err = f.Marshal(second)
+		require.NoError(t, err)
+		require.NoError(t, f.Unmarshal(data, &target))
+		require.Equal(t, *second, target)
+		require.Same(t, ids, &target.Ids[0])
+		require.Same(t, names, &target.Names[0])
+		require.Same(t, inner, target.Inner)
+		require.Same(t, items, &target.Items[0])
+		scores["sentinel"] = 1
+		require.Equal(t, int32(1), target.Scores["sentinel"], "map must be reused, not replaced")
+	}
+}
+
+func TestObjectReuseDisabled(t *testing.T) {
+	f := newReuseFory(t, false)
+	data, err := f.Marshal(&reuseRecord{Ids: []int32{1, 2}})
+	require.NoError(t, err)
+	target := reuseRecord{Ids: []int32{9, 9, 9}}
+	ids := target.Ids
+	require.NoError(t, f.Unmarshal(data, &target))
+	require.Equal(t, []int32{1, 2}, target.Ids)
+	require.Equal(t, []int32{9, 9, 9}, ids, "caller's slice must not be overwritten by default")
+}
+
+func TestObjectReuseGenericDeserialize(t *testing.T) {
+	f := NewFory(WithXlang(true), WithObjectReuse(true))
+	data, err := f.Marshal([]float64{1.5, 2.5})
+	require.NoError(t, err)
+	target := make([]float64, 0, 8)
+	backing := &target[:1][0]
+	require.NoError(t, Deserialize(f, data, &target))
+	require.Equal(t, []float64{1.5, 2.5}, target)
+	require.Same(t, backing, &target[0])
+}
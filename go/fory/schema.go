@@ -0,0 +1,449 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Schemas describes the types registered with a Fory instance, as written by
+// ExportSchemas.
+type Schemas struct {
+	Xlang      bool         `json:"xlang"`
+	Compatible bool         `json:"compatible"`
+	Types      []TypeSchema `json:"types"`
+}
+
+// TypeSchema describes a registered type.
+type TypeSchema struct {
+	// Name is the namespace-qualified name of types registered by name.
+	Name string `json:"name,omitempty"`
+	// ID is the user type ID of types registered by ID.
+	ID *uint32 `json:"id,omitempty"`
+	// Kind is the type ID written for the type, such as NAMED_COMPATIBLE_STRUCT.
+	Kind   string `json:"kind"`
+	GoType string `json:"go_type"`
+	// Fields lists struct fields in the order they are serialized.
+	Fields []FieldSchema `json:"fields,omitempty"`
+}
+
+// FieldSchema describes a struct field as it appears in TypeDefs.
+type FieldSchema struct {
+	// Name is the field name written to TypeDefs, which other languages match.
+	Name string `json:"name"`
+	// Tag is the field's tag ID, if it is identified by tag.
+	Tag      *int      `json:"tag,omitempty"`
+	Type     FieldType `json:"type"`
+	Nullable bool      `json:"nullable"`
+	TrackRef bool      `json:"track_ref"`
+}
+
+// FieldType describes the declared type of a field or of the elements, keys
+// and values of a container field.
+type FieldType struct {
+	// Kind is the type ID, such as VARINT64 or LIST.
+	Kind string `json:"kind"`
+	// Name and ID identify a registered type the field refers to.
+	Name    string     `json:"name,omitempty"`
+	ID      *uint32    `json:"id,omitempty"`
+	Element *FieldType `json:"element,omitempty"`
+	Key     *FieldType `json:"key,omitempty"`
+	Value   *FieldType `json:"value,omitempty"`
+}
+
+// ExportSchemas returns the registered types with their type IDs, names and
+// struct fields as JSON, so that schemas can be compared between services
+// and languages. Types are sorted by name, then by ID.
+func (f *Fory) ExportSchemas() ([]byte, error) {
+	schemas, err := f.typeResolver.exportSchemas()
+	if err != nil {
+		return nil, err
+	}
+	return json.MarshalIndent(schemas, "", "  ")
+}
+
+// SchemaDigest returns a hash of the schemas ExportSchemas describes: the
+// registered types with their names or IDs and struct fields, and the xlang
+// and compatible settings. Go type names are left out, so instances of
+// different programs registering the same schemas have the same digest.
+// Services can exchange digests when they connect and check them with
+// CheckPeerDigest.
+func (f *Fory) SchemaDigest() (uint64, error) {
+	schemas, err := f.typeResolver.exportSchemas()
+	if err != nil {
+		return 0, err
+	}
+	for i := range schemas.Types {
+		schemas.Types[i].GoType = ""
+	}
+	data, err := json.Marshal(schemas)
+	if err != nil {
+		return 0, err
+	}
+	return Murmur3Sum64WithSeed(data, 47), nil
+}
+
+// CheckPeerDigest returns an error of kind ErrKindHashMismatch if digest,
+// the SchemaDigest of a peer, differs from the digest of f.
+func (f *Fory) CheckPeerDigest(digest uint64) error {
+	local, err := f.SchemaDigest()
+	if err != nil {
+		return err
+	}
+	if digest != local {
+		return schemaDigestMismatchError(digest, local)
+	}
+	return nil
+}
+
+func (r *TypeResolver) exportSchemas() (Schemas, error) {
+	schemas := Schemas{
+		Xlang:      r.fory.config.IsXlang,
+		Compatible: r.fory.config.Compatible,
+		Types:      []TypeSchema{},
+	}
+	for type_, info := range r.typesInfo {
+		if type_.Kind() == reflect.Ptr || !isUserTypeKind(TypeId(info.TypeID)) {
+			continue
+		}
+		schema := TypeSchema{Kind: typeIdName(TypeId(info.TypeID)), GoType: type_.String()}
+		schema.Name, schema.ID = r.registeredName(info)
+		if isStructTypeId(TypeId(info.TypeID)) {
+			typeDef, err := r.getTypeDef(type_, true)
+			if err != nil {
+				return Schemas{}, err
+			}
+			for _, fieldDef := range typeDef.fieldDefs {
+				field := FieldSchema{
+					Name:     fieldDef.name,
+					Type:     r.fieldType(fieldDef.typeSpec),
+					Nullable: fieldDef.nullable,
+					TrackRef: fieldDef.trackRef,
+				}
+				if fieldDef.tagID >= 0 {
+					tag := fieldDef.tagID
+					field.Tag = &tag
+				}
+				schema.Fields = append(schema.Fields, field)
+			}
+		}
+		schemas.Types = append(schemas.Types, schema)
+	}
+	sort.Slice(schemas.Types, func(i, j int) bool {
+		a, b := schemas.Types[i], schemas.Types[j]
+		if a.Name != b.Name {
+			return a.Name < b.Name
+		}
+		if a.ID == nil || b.ID == nil {
+			return b.ID != nil
+		}
+		return *a.ID < *b.ID
+	})
+	return schemas, nil
+}
+
+// registeredName returns the name or user type ID a type was registered with.
+func (r *TypeResolver) registeredName(info *TypeInfo) (string, *uint32) {
+	if info.NameBytes != nil {
+		return newUnknownTypeWarning(r, info.TypeID, 0, info.PkgPathBytes, info.NameBytes).Name, nil
+	}
+	if info.UserTypeID != invalidUserTypeID {
+		id := info.UserTypeID
+		return "", &id
+	}
+	return "", nil
+}
+
+func (r *TypeResolver) fieldType(spec *TypeSpec) FieldType {
+	fieldType := FieldType{Kind: typeIdName(spec.TypeId())}
+	if spec.GoType != nil {
+		type_ := spec.GoType
+		if type_.Kind() == reflect.Ptr {
+			type_ = type_.Elem()
+		}
+		if info, ok := r.typesInfo[type_]; ok && isUserTypeKind(TypeId(info.TypeID)) {
+			fieldType.Name, fieldType.ID = r.registeredName(info)
+		}
+	}
+	if spec.elementType != nil {
+		element := r.fieldType(spec.elementType)
+		fieldType.Element = &element
+	}
+	if spec.keyType != nil {
+		key := r.fieldType(spec.keyType)
+		fieldType.Key = &key
+	}
+	if spec.valueType != nil {
+		value := r.fieldType(spec.valueType)
+		fieldType.Value = &value
+	}
+	return fieldType
+}
+
+// isUserTypeKind reports whether typeID is written for user-registered types.
+func isUserTypeKind(typeID TypeId) bool {
+	switch typeID {
+	case ENUM, NAMED_ENUM, STRUCT, COMPATIBLE_STRUCT, NAMED_STRUCT, NAMED_COMPATIBLE_STRUCT,
+		EXT, NAMED_EXT, TYPED_UNION, NAMED_UNION:
+		return true
+	default:
+		return false
+	}
+}
+
+// SchemaChangeKind classifies a difference between two exported schemas.
+type SchemaChangeKind int
+
+const (
+	// SchemaModeChanged is a change of the xlang or compatible setting.
+	SchemaModeChanged SchemaChangeKind = iota
+	// SchemaTypeAdded is a type registered only in the new schema.
+	SchemaTypeAdded
+	// SchemaTypeRemoved is a type registered only in the old schema.
+	SchemaTypeRemoved
+	// SchemaTypeKindChanged is a type written with a different type ID, such
+	// as a struct that became an enum or stopped evolving.
+	SchemaTypeKindChanged
+	// SchemaFieldAdded is a struct field present only in the new schema.
+	SchemaFieldAdded
+	// SchemaFieldRemoved is a struct field present only in the old schema.
+	SchemaFieldRemoved
+	// SchemaFieldRenamed is a field whose name changed. Fields matched by tag
+	// ID keep their data; otherwise a removed and an added field of the same
+	// type are reported as a rename, and the data is not carried over.
+	SchemaFieldRenamed
+	// SchemaFieldTypeChanged is a field whose declared type changed.
+	SchemaFieldTypeChanged
+	// SchemaFieldNullabilityChanged is a field whose nullable or track_ref
+	// flag changed.
+	SchemaFieldNullabilityChanged
+)
+
+func (k SchemaChangeKind) String() string {
+	switch k {
+	case SchemaModeChanged:
+		return "mode changed"
+	case SchemaTypeAdded:
+		return "type added"
+	case SchemaTypeRemoved:
+		return "type removed"
+	case SchemaTypeKindChanged:
+		return "type kind changed"
+	case SchemaFieldAdded:
+		return "field added"
+	case SchemaFieldRemoved:
+		return "field removed"
+	case SchemaFieldRenamed:
+		return "field renamed"
+	case SchemaFieldTypeChanged:
+		return "field type changed"
+	case SchemaFieldNullabilityChanged:
+		return "field nullability changed"
+	default:
+		return fmt.Sprintf("SchemaChangeKind(%d)", int(k))
+	}
+}
+
+// SchemaChange describes one difference found by CheckCompatible.
+type SchemaChange struct {
+	Kind SchemaChangeKind
+	// Type is the registered name of the changed type, or "id N" for types
+	// registered by ID. It is empty for mode changes.
+	Type string
+	// Field is the name of the changed field in the new schema, or in the old
+	// schema for removed fields.
+	Field string
+	// Detail gives the old and new values, such as the two field types.
+	Detail string
+	// Safe reports whether payloads written with either schema decode
+	// correctly with the other.
+	Safe bool
+}
+
+func (c SchemaChange) String() string {
+	var b strings.Builder
+	if c.Type != "" {
+		b.WriteString(c.Type)
+		if c.Field != "" {
+			b.WriteString("." + c.Field)
+		}
+		b.WriteString(": ")
+	}
+	b.WriteString(c.Kind.String())
+	if c.Detail != "" {
+		b.WriteString(" (" + c.Detail + ")")
+	}
+	if !c.Safe {
+		b.WriteString(", unsafe")
+	}
+	return b.String()
+}
+
+// CheckCompatible compares two outputs of ExportSchemas and returns the
+// changes from oldSchema to newSchema, so CI can reject unsafe changes
+// before deploying. Adding types and renaming fields matched by tag ID are
+// safe. Adding and removing struct fields and changing their nullability are
+// safe when both schemas use compatible mode. Field type changes, renames of
+// fields without tag IDs, removed types, kind changes and mode changes are
+// unsafe. Scalar type changes that compatible mode can read are reported as
+// unsafe too, since reading them fails for values the new type cannot hold.
+func CheckCompatible(oldSchema, newSchema []byte) ([]SchemaChange, error) {
+	var before, after Schemas
+	if err := json.Unmarshal(oldSchema, &before); err != nil {
+		return nil, fmt.Errorf("old schema: %w", err)
+	}
+	if err := json.Unmarshal(newSchema, &after); err != nil {
+		return nil, fmt.Errorf("new schema: %w", err)
+	}
+	var changes []SchemaChange
+	if before.Xlang != after.Xlang {
+		changes = append(changes, SchemaChange{Kind: SchemaModeChanged,
+			Detail: fmt.Sprintf("xlang %t to %t", before.Xlang, after.Xlang)})
+	}
+	if before.Compatible != after.Compatible {
+		changes = append(changes, SchemaChange{Kind: SchemaModeChanged,
+			Detail: fmt.Sprintf("compatible %t to %t", before.Compatible, after.Compatible)})
+	}
+	compatible := before.Compatible && after.Compatible
+
+	newTypes := make(map[string]*TypeSchema, len(after.Types))
+	for i := range after.Types {
+		newTypes[schemaTypeKey(&after.Types[i])] = &after.Types[i]
+	}
+	for i := range before.Types {
+		oldType := &before.Types[i]
+		key := schemaTypeKey(oldType)
+		newType, ok := newTypes[key]
+		if !ok {
+			changes = append(changes, SchemaChange{Kind: SchemaTypeRemoved, Type: key})
+			continue
+		}
+		delete(newTypes, key)
+		if oldType.Kind != newType.Kind {
+			changes = append(changes, SchemaChange{Kind: SchemaTypeKindChanged, Type: key,
+				Detail: oldType.Kind + " to " + newType.Kind})
+			continue
+		}
+		changes = append(changes, compareSchemaFields(key, oldType.Fields, newType.Fields, compatible)...)
+	}
+	for i := range after.Types {
+		if key := schemaTypeKey(&after.Types[i]); newTypes[key] != nil {
+			changes = append(changes, SchemaChange{Kind: SchemaTypeAdded, Type: key, Safe: true})
+		}
+	}
+	return changes, nil
+}
+
+// schemaTypeKey returns the name or ID that identifies t on the wire.
+func schemaTypeKey(t *TypeSchema) string {
+	if t.Name != "" || t.ID == nil {
+		return t.Name
+	}
+	return fmt.Sprintf("id %d", *t.ID)
+}
+
+// compareSchemaFields returns the changes between the fields of a struct in
+// two schemas. Fields are matched by tag ID when both have one, else by name.
+func compareSchemaFields(typeKey string, before, after []FieldSchema, compatible bool) []SchemaChange {
+	fieldKey := func(f *FieldSchema) string {
+		if f.Tag != nil {
+			return "#" + strconv.Itoa(*f.Tag)
+		}
+		return f.Name
+	}
+	newFields := make(map[string]*FieldSchema, len(after))
+	for i := range after {
+		newFields[fieldKey(&after[i])] = &after[i]
+	}
+	var changes []SchemaChange
+	var removed []*FieldSchema
+	for i := range before {
+		oldField := &before[i]
+		newField, ok := newFields[fieldKey(oldField)]
+		if !ok {
+			removed = append(removed, oldField)
+			continue
+		}
+		delete(newFields, fieldKey(oldField))
+		if oldField.Name != newField.Name {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldRenamed, Type: typeKey, Field: newField.Name,
+				Detail: "from " + oldField.Name, Safe: true})
+		}
+		if !reflect.DeepEqual(oldField.Type, newField.Type) {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldTypeChanged, Type: typeKey, Field: newField.Name,
+				Detail: oldField.Type.String() + " to " + newField.Type.String()})
+		}
+		if oldField.Nullable != newField.Nullable || oldField.TrackRef != newField.TrackRef {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldNullabilityChanged, Type: typeKey, Field: newField.Name,
+				Detail: fmt.Sprintf("nullable %t, track_ref %t to nullable %t, track_ref %t",
+					oldField.Nullable, oldField.TrackRef, newField.Nullable, newField.TrackRef),
+				Safe: compatible})
+		}
+	}
+	var added []*FieldSchema
+	for i := range after {
+		if newFields[fieldKey(&after[i])] != nil {
+			added = append(added, &after[i])
+		}
+	}
+	for _, oldField := range removed {
+		renamed := false
+		for i, newField := range added {
+			if newField != nil && newField.Tag == nil && oldField.Tag == nil &&
+				reflect.DeepEqual(oldField.Type, newField.Type) {
+				changes = append(changes, SchemaChange{Kind: SchemaFieldRenamed, Type: typeKey, Field: newField.Name,
+					Detail: "from " + oldField.Name})
+				added[i] = nil
+				renamed = true
+				break
+			}
+		}
+		if !renamed {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldRemoved, Type: typeKey, Field: oldField.Name, Safe: compatible})
+		}
+	}
+	for _, newField := range added {
+		if newField != nil {
+			changes = append(changes, SchemaChange{Kind: SchemaFieldAdded, Type: typeKey, Field: newField.Name, Safe: compatible})
+		}
+	}
+	return changes
+}
+
+// String formats t as its kind followed by the registered type it refers to
+// and its element, key and value types, such as LIST<STRING>.
+func (t FieldType) String() string {
+	s := t.Kind
+	if t.Name != "" {
+		s += " " + t.Name
+	} else if t.ID != nil {
+		s += fmt.Sprintf(" id %d", *t.ID)
+	}
+	switch {
+	case t.Key != nil && t.Value != nil:
+		s += "<" + t.Key.String() + ", " + t.Value.String() + ">"
+	case t.Element != nil:
+		s += "<" + t.Element.String() + ">"
+	}
+	return s
+}
@@ -0,0 +1,209 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"encoding/json"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type schemaColor int32
+
+type schemaItem struct {
+	Name  string
+	Count int32 `fory:"id=3"`
+}
+
+type schemaOrder struct {
+	ID    int64
+	Items []*schemaItem
+	Tags  map[string]schemaItem
+	Note  *string
+	Color schemaColor
+}
+
+func TestExportSchemas(t *testing.T) {
+	f := New(WithXlang(true))
+	require.NoError(t, f.RegisterStruct(schemaItem{}, 5))
+	require.NoError(t, f.RegisterStructByName(schemaOrder{}, "demo.Order"))
+	require.NoError(t, f.RegisterEnumByName(schemaColor(0), "demo.Color"))
+
+	data, err := f.ExportSchemas()
+	require.NoError(t, err)
+	var schemas Schemas
+	require.NoError(t, json.Unmarshal(data, &schemas))
+	require.True(t, schemas.Xlang)
+	require.True(t, schemas.Compatible)
+
+	id := uint32(5)
+	tag := 3
+	require.Equal(t, []TypeSchema{
+		{
+			ID:     &id,
+			Kind:   "COMPATIBLE_STRUCT",
+			GoType: "fory.schemaItem",
+			Fields: []FieldSchema{
+				{Name: "count", Tag: &tag, Type: FieldType{Kind: "VARINT32"}},
+				{Name: "name", Type: FieldType{Kind: "STRING"}},
+			},
+		},
+		{Name: "demo.Color", Kind: "NAMED_ENUM", GoType: "fory.schemaColor"},
+		{
+			Name:   "demo.Order",
+			Kind:   "NAMED_COMPATIBLE_STRUCT",
+			GoType: "fory.schemaOrder",
+			Fields: []FieldSchema{
+				{Name: "i_d", Type: FieldType{Kind: "VARINT64"}},
+				{Name: "color", Type: FieldType{Kind: "ENUM", Name: "demo.Color"}},
+				{Name: "items", Type: FieldType{Kind: "LIST", Element: &FieldType{Kind: "COMPATIBLE_STRUCT", ID: &id}}},
+				{Name: "note", Type: FieldType{Kind: "STRING"}, Nullable: true},
+				{Name: "tags", Type: FieldType{
+					Kind:  "MAP",
+					Key:   &FieldType{Kind: "STRING"},
+					Value: &FieldType{Kind: "COMPATIBLE_STRUCT", ID: &id},
+				}},
+			},
+		},
+	}, schemas.Types)
+}
+
+func TestExportSchemasSchemaConsistent(t *testing.T) {
+	f := New(WithXlang(false), WithCompatible(false))
+	require.NoError(t, f.RegisterStructByName(schemaItem{}, "demo.Item"))
+
+	data, err := f.ExportSchemas()
+	require.NoError(t, err)
+	var schemas Schemas
+	require.NoError(t, json.Unmarshal(data, &schemas))
+	require.False(t, schemas.Compatible)
+	require.Len(t, schemas.Types, 1)
+	require.Equal(t, "NAMED_STRUCT", schemas.Types[0].Kind)
+	require.Len(t, schemas.Types[0].Fields, 2)
+}
+
+type schemaItemCopy struct {
+	Name  string
+	Count int32 `fory:"id=3"`
+}
+
+type schemaItemGrown struct {
+	Name  string
+	Count int32 `fory:"id=3"`
+	Price float64
+}
+
+func TestSchemaDigest(t *testing.T) {
+	newInstance := func(item any, opts ...Option) *Fory {
+		f := New(append([]Option{WithXlang(true)}, opts...)...)
+		require.NoError(t, f.RegisterStruct(item, 5))
+		require.NoError(t, f.RegisterEnumByName(schemaColor(0), "demo.Color"))
+		return f
+	}
+	local := newInstance(schemaItem{})
+	digest, err := local.SchemaDigest()
+	require.NoError(t, err)
+
+	again, err := newInstance(schemaItem{}).SchemaDigest()
+	require.NoError(t, err)
+	require.Equal(t, digest, again)
+	require.NoError(t, newInstance(schemaItemCopy{}).CheckPeerDigest(digest))
+
+	for name, peer := range map[string]*Fory{
+		"field added": newInstance(schemaItemGrown{}),
+		"mode":        newInstance(schemaItem{}, WithCompatible(false)),
+	} {
+		peerDigest, err := peer.SchemaDigest()
+		require.NoError(t, err)
+		require.NotEqual(t, digest, peerDigest, name)
+		err = local.CheckPeerDigest(peerDigest)
+		var foryErr Error
+		require.True(t, errors.As(err, &foryErr), name)
+		require.Equal(t, ErrKindHashMismatch, foryErr.Kind(), name)
+	}
+}
+
+type schemaUserV1 struct {
+	Name  string
+	Email string
+	Score int32 `fory:"id=7"`
+	Plan  string
+}
+
+type schemaUserV2 struct {
+	Name    *string
+	Mail    string
+	Points  int32 `fory:"id=7"`
+	Plan    int64
+	Created int64
+}
+
+func TestCheckCompatible(t *testing.T) {
+	export := func(opts []Option, register func(f *Fory)) []byte {
+		f := New(append([]Option{WithXlang(true)}, opts...)...)
+		register(f)
+		data, err := f.ExportSchemas()
+		require.NoError(t, err)
+		return data
+	}
+	before := export(nil, func(f *Fory) {
+		require.NoError(t, f.RegisterStruct(schemaItem{}, 5))
+		require.NoError(t, f.RegisterStructByName(schemaUserV1{}, "demo.User"))
+	})
+	after := export(nil, func(f *Fory) {
+		require.NoError(t, f.RegisterStructByName(schemaUserV2{}, "demo.User"))
+		require.NoError(t, f.RegisterEnumByName(schemaColor(0), "demo.Color"))
+	})
+
+	changes, err := CheckCompatible(before, after)
+	require.NoError(t, err)
+	require.ElementsMatch(t, []SchemaChange{
+		{Kind: SchemaTypeRemoved, Type: "id 5"},
+		{Kind: SchemaFieldNullabilityChanged, Type: "demo.User", Field: "name",
+			Detail: "nullable false, track_ref false to nullable true, track_ref false", Safe: true},
+		{Kind: SchemaFieldRenamed, Type: "demo.User", Field: "points", Detail: "from score", Safe: true},
+		{Kind: SchemaFieldTypeChanged, Type: "demo.User", Field: "plan", Detail: "STRING to VARINT64"},
+		{Kind: SchemaFieldRenamed, Type: "demo.User", Field: "mail", Detail: "from email"},
+		{Kind: SchemaFieldAdded, Type: "demo.User", Field: "created", Safe: true},
+		{Kind: SchemaTypeAdded, Type: "demo.Color", Safe: true},
+	}, changes)
+	require.Equal(t, "demo.User.plan: field type changed (STRING to VARINT64), unsafe",
+		changes[slices.IndexFunc(changes, func(c SchemaChange) bool { return c.Field == "plan" })].String())
+
+	changes, err = CheckCompatible(before, before)
+	require.NoError(t, err)
+	require.Empty(t, changes)
+
+	consistent := export([]Option{WithCompatible(false)}, func(f *Fory) {
+		require.NoError(t, f.RegisterStruct(schemaItem{}, 5))
+		require.NoError(t, f.RegisterStructByName(schemaItemGrown{}, "demo.User"))
+	})
+	changes, err = CheckCompatible(before, consistent)
+	require.NoError(t, err)
+	require.Contains(t, changes, SchemaChange{Kind: SchemaModeChanged, Detail: "compatible true to false"})
+	require.Contains(t, changes, SchemaChange{Kind: SchemaTypeKindChanged, Type: "demo.User",
+		Detail: "NAMED_COMPATIBLE_STRUCT to NAMED_STRUCT"})
+
+	_, err = CheckCompatible([]byte("{"), after)
+	require.Error(t, err)
+}
@@ -142,3 +142,24 @@ type ExtensionSerializer interface {
 	// Errors should be set on the context via ctx.SetError().
 	ReadData(ctx *ReadContext, value reflect.Value)
 }
+
+// CheckedSerializer is the error-returning form of Serializer and
+// ExtensionSerializer: its methods return errors instead of recording them on
+// the context. The context carries the buffer, the nesting depth of checked
+// serializers (Depth), the mode flags (IsXlang, TrackRef, Compatible) and the
+// type and reference resolvers. Wrap it with CheckedExtension to register it,
+// and use Checked to call an existing serializer from it.
+//
+// Errors recorded on the context, such as out-of-bound reads through
+// ctx.Err(), take precedence over the returned error. Returned errors are
+// reported with kind ErrKindSerializationFailed or ErrKindDeserializationFailed
+// and stay reachable through errors.Is and errors.As.
+type CheckedSerializer interface {
+	// Write serializes the value's data to the buffer, like
+	// ExtensionSerializer.WriteData.
+	Write(ctx *WriteContext, value reflect.Value) error
+
+	// Read deserializes the value's data from the buffer into the provided
+	// value, like ExtensionSerializer.ReadData.
+	Read(ctx *ReadContext, value reflect.Value) error
+}
@@ -19,6 +19,7 @@ package fory
 
 import (
 	"reflect"
+	"slices"
 )
 
 // Set is a generic set type using Go generics.
@@ -87,6 +88,9 @@ func (s setSerializer) writeDataWithGenerics(ctx *WriteContext, value reflect.Va
 	// Get all map keys (set elements)
 	keys := value.MapKeys()
 	length := len(keys)
+	if ctx.sortMapKeys {
+		slices.SortFunc(keys, compareMapKeys)
+	}
 
 	// Handle empty set case
 	if length == 0 {
@@ -314,6 +318,9 @@ func (s setSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	type_ := value.Type()
 	// ReadData collection length from buffer
 	length := ctx.ReadCollectionLength()
+	if !ctx.chargeElems(length, type_.Key()) {
+		return
+	}
 	if length == 0 {
 		// Initialize empty set if length is 0
 		value.Set(reflect.MakeMap(type_))
@@ -348,6 +355,8 @@ func (s setSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	// Initialize set if nil
 	if value.IsNil() {
 		value.Set(reflect.MakeMap(type_))
+	} else if ctx.reuseObjects {
+		value.Clear()
 	}
 	// Register reference for tracking (handles circular references)
 	ctx.RefResolver().Reference(value)
@@ -369,8 +378,11 @@ func (s setSerializer) readSameType(ctx *ReadContext, buf *ByteBuffer, value ref
 	serializer := s.elemSerializer
 	keyType := value.Type().Key()
 	elemType := keyType
+	// Elements of an unregistered type are consumed by their serializer and dropped.
+	unknownElems := false
 	if !declaredGenerics && typeInfo != nil && typeInfo.Serializer != nil {
 		serializer = typeInfo.Serializer
+		unknownElems = typeInfo.Type == nil
 		if typeInfo.Type != nil {
 			elemType, serializer = wrapMapSerializerIfNeeded(keyType, typeInfo.Type, serializer)
 		}
@@ -412,6 +424,9 @@ func (s setSerializer) readSameType(ctx *ReadContext, buf *ByteBuffer, value ref
 				return
 			}
 		}
+		if unknownElems {
+			continue
+		}
 		// Add element to set
 		setMapKey(value, elem, keyType)
 	}
@@ -447,6 +462,13 @@ func (s setSerializer) readDifferentTypes(ctx *ReadContext, buf *ByteBuffer, val
 			if ctxErr.HasError() {
 				return
 			}
+			if typeInfo.Type == nil {
+				typeInfo.Serializer.ReadData(ctx, reflect.Value{})
+				if ctx.HasError() {
+					return
+				}
+				continue
+			}
 			// Create new element and deserialize from buffer
 			elem := reflect.New(typeInfo.Type).Elem()
 			typeInfo.Serializer.ReadData(ctx, elem)
@@ -467,6 +489,13 @@ func (s setSerializer) readDifferentTypes(ctx *ReadContext, buf *ByteBuffer, val
 			if ctxErr.HasError() {
 				return
 			}
+			if typeInfo.Type == nil {
+				typeInfo.Serializer.ReadData(ctx, reflect.Value{})
+				if ctx.HasError() {
+					return
+				}
+				continue
+			}
 			elem := reflect.New(typeInfo.Type).Elem()
 			typeInfo.Serializer.ReadData(ctx, elem)
 			if ctx.HasError() {
@@ -479,6 +508,13 @@ func (s setSerializer) readDifferentTypes(ctx *ReadContext, buf *ByteBuffer, val
 			if ctxErr.HasError() {
 				return
 			}
+			if typeInfo.Type == nil {
+				typeInfo.Serializer.ReadData(ctx, reflect.Value{})
+				if ctx.HasError() {
+					return
+				}
+				continue
+			}
 			elem := reflect.New(typeInfo.Type).Elem()
 			typeInfo.Serializer.ReadData(ctx, elem)
 			if ctx.HasError() {
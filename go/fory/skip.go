@@ -497,11 +497,12 @@ func skipStruct(ctx *ReadContext, info *TypeInfo) {
 
 	// Get fieldDefs from the serializer
 	var fieldDefs []FieldDef
+	var omitZero bool
 	if info.Serializer != nil {
 		if ss, ok := info.Serializer.(*structSerializer); ok && ss.fieldDefs != nil {
-			fieldDefs = ss.fieldDefs
+			fieldDefs, omitZero = ss.fieldDefs, ss.omitZero
 		} else if sss, ok := info.Serializer.(*skipStructSerializer); ok && sss.fieldDefs != nil {
-			fieldDefs = sss.fieldDefs
+			fieldDefs, omitZero = sss.fieldDefs, sss.typeDef != nil && sss.typeDef.omitZero
 		}
 	}
 
@@ -512,7 +513,7 @@ func skipStruct(ctx *ReadContext, info *TypeInfo) {
 			ctx.SetError(FromError(fmt.Errorf("cannot skip struct without field definitions: %w", tdErr)))
 			return
 		}
-		fieldDefs = typeDef.fieldDefs
+		fieldDefs, omitZero = typeDef.fieldDefs, typeDef.omitZero
 	}
 
 	ctx.depth++
@@ -522,7 +523,14 @@ func skipStruct(ctx *ReadContext, info *TypeInfo) {
 	}
 	defer ctx.decDepth()
 
-	for _, fieldDef := range fieldDefs {
+	var presence []byte
+	if omitZero {
+		presence = readFieldPresence(ctx, len(fieldDefs))
+	}
+	for i, fieldDef := range fieldDefs {
+		if !fieldPresent(presence, i) {
+			continue
+		}
 		// Use FieldDef's trackRef and nullable to determine if ref flag was written by Java
 		// Java writes ref flag based on its FieldDef, not based on type rules
 		readRefFlag := fieldDef.trackRef || fieldDef.nullable
@@ -629,6 +637,11 @@ func skipValue(ctx *ReadContext, fieldDef FieldDef, readRefFlag bool, isField bo
 
 	// String types
 	case STRING:
+		if ctx.stringDict {
+			// Skipped strings still take a dictionary index
+			_ = ctx.readDictString()
+			return
+		}
 		// String format: VarUint64 header (size << 2 | encoding) + data bytes
 		header := ctx.buffer.ReadVarUint64(err)
 		if ctx.HasError() {
@@ -753,3 +766,106 @@ func skipValue(ctx *ReadContext, fieldDef FieldDef, readRefFlag bool, isField bo
 		ctx.SetError(DeserializationErrorf("unsupported type for skip: %d", typeIDNum))
 	}
 }
+
+// UnknownTypeWarning describes a value that UnmarshalPartial skipped because
+// its type is not registered with the reading Fory instance.
+type UnknownTypeWarning struct {
+	// TypeID is the internal type ID written for the value.
+	TypeID TypeId
+	// UserTypeID is the numeric registration ID; it is unset for types
+	// registered by name.
+	UserTypeID uint32
+	// Name is the namespace-qualified name for types registered by name.
+	Name string
+}
+
+func (w UnknownTypeWarning) String() string {
+	return "skipped value of unregistered type " + w.typeName()
+}
+
+func (w UnknownTypeWarning) typeName() string {
+	if w.Name != "" {
+		return w.Name
+	}
+	return fmt.Sprintf("id %d", w.UserTypeID)
+}
+
+// newUnknownTypeWarning describes an unregistered type from its wire metadata.
+// ns and name are nil for types registered by ID.
+func newUnknownTypeWarning(resolver *TypeResolver, typeID, userTypeID uint32, ns, name *MetaStringBytes) UnknownTypeWarning {
+	warning := UnknownTypeWarning{TypeID: TypeId(typeID), UserTypeID: userTypeID}
+	if resolver != nil && name != nil {
+		typeName, _ := resolver.typeNameDecoder.Decode(name.Data, name.Encoding)
+		if ns != nil {
+			if nsName, _ := resolver.namespaceDecoder.Decode(ns.Data, ns.Encoding); nsName != "" {
+				typeName = nsName + "." + typeName
+			}
+		}
+		warning.Name = typeName
+	}
+	return warning
+}
+
+// readUnknownValue handles the ref header of a value of an unregistered type
+// and skips its payload with s, or reads it into value when s decodes
+// unknown structs.
+func readUnknownValue(ctx *ReadContext, refMode RefMode, s Serializer, value reflect.Value) {
+	buf := ctx.Buffer()
+	refID := int32(NotNullValueFlag)
+	switch refMode {
+	case RefModeTracking:
+		var refErr error
+		refID, refErr = ctx.RefResolver().TryPreserveRefId(buf)
+		if refErr != nil {
+			ctx.SetError(FromError(refErr))
+			return
+		}
+		if refID < int32(NotNullValueFlag) {
+			// Reference found, nothing to skip
+			return
+		}
+	case RefModeNullOnly:
+		if buf.ReadInt8(ctx.Err()) == NullFlag {
+			return
+		}
+	}
+	if ctx.HasError() {
+		return
+	}
+	s.ReadData(ctx, value)
+	if ctx.decodesUnknownStructs() && value.IsValid() && value.Kind() == reflect.Interface && !value.IsNil() {
+		ctx.RefResolver().SetReadObject(refID, value.Elem())
+	}
+}
+
+// unknownEnumSerializer skips enum values of unregistered enum types. Enum
+// payloads are a bare ordinal, so they can be skipped without metadata, but
+// only UnmarshalPartial accepts them; other reads fail as before.
+type unknownEnumSerializer struct {
+	unknown UnknownTypeWarning
+}
+
+func (s *unknownEnumSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	ctx.SetError(SerializationError("unknownEnumSerializer does not support WriteData - unknown enum type"))
+}
+
+func (s *unknownEnumSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
+	ctx.SetError(SerializationError("unknownEnumSerializer does not support Write - unknown enum type"))
+}
+
+func (s *unknownEnumSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	if !ctx.skipUnknownTypes {
+		ctx.SetError(DeserializationErrorf("unknown enum type %s", s.unknown.typeName()))
+		return
+	}
+	ctx.buffer.ReadVarUint32(ctx.Err())
+	ctx.skippedUnknownType(s.unknown)
+}
+
+func (s *unknownEnumSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
+	readUnknownValue(ctx, refMode, s, value)
+}
+
+func (s *unknownEnumSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
+	readUnknownValue(ctx, refMode, s, value)
+}
@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -130,3 +132,87 @@ func TestSkipMapRejectsInvalidChunkSize(t *testing.T) {
 	)
 	require.Error(t, f.readCtx.CheckError())
 }
+
+type partialKnown struct {
+	A int32
+}
+
+type partialColor int32
+
+type partialHolder struct {
+	Any   any
+	List  []any
+	Same  []any
+	Map   map[string]any
+	Color any
+	Tail  string
+}
+
+func TestUnmarshalPartialSkipsUnknownTypes(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		writer := New(WithXlang(xlang))
+		require.NoError(t, writer.RegisterStruct(partialKnown{}, 320))
+		require.NoError(t, writer.RegisterEnum(partialColor(0), 321))
+		require.NoError(t, writer.RegisterStruct(partialHolder{}, 322))
+		data, err := writer.Marshal(&partialHolder{
+			Any:   &partialKnown{A: 1},
+			List:  []any{"a", &partialKnown{A: 2}, "b"},
+			Same:  []any{&partialKnown{A: 3}, &partialKnown{A: 4}},
+			Map:   map[string]any{"known": "v", "unknown": &partialKnown{A: 5}},
+			Color: partialColor(1),
+			Tail:  "tail",
+		})
+		require.NoError(t, err)
+		data = append([]byte(nil), data...)
+
+		reader := New(WithXlang(xlang))
+		require.NoError(t, reader.RegisterStruct(partialHolder{}, 322))
+
+		var strict partialHolder
+		require.Error(t, reader.Unmarshal(data, &strict), "unknown enums fail outside UnmarshalPartial")
+
+		var got partialHolder
+		warnings, err := reader.UnmarshalPartial(data, &got)
+		require.NoError(t, err)
+		require.Equal(t, partialHolder{
+			List: []any{"a", nil, "b"},
+			Same: []any{nil, nil},
+			Map:  map[string]any{"known": "v"},
+			Tail: "tail",
+		}, got)
+		require.Len(t, warnings, 6)
+		require.Contains(t, warnings, UnknownTypeWarning{TypeID: ENUM, UserTypeID: 321})
+
+		// Warnings belong to the call that produced them.
+		var again partialHolder
+		require.Error(t, reader.Unmarshal(data, &again))
+	}
+}
+
+func TestUnmarshalPartialNamedTypes(t *testing.T) {
+	writer := New(WithXlang(true), WithCompatible(false))
+	require.NoError(t, writer.RegisterEnumByName(partialColor(0), "example.Color"))
+	data, err := writer.Marshal([]any{"a", partialColor(2)})
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+
+	reader := New(WithXlang(true), WithCompatible(false))
+	var got []any
+	warnings, err := reader.UnmarshalPartial(data, &got)
+	require.NoError(t, err)
+	require.Equal(t, []any{"a", nil}, got)
+	require.Equal(t, []UnknownTypeWarning{{TypeID: NAMED_ENUM, Name: "example.Color"}}, warnings)
+}
+
+func TestUnmarshalPartialRequiresMetadata(t *testing.T) {
+	// Schema-consistent structs carry no field metadata, so they cannot be skipped.
+	writer := New(WithXlang(true), WithCompatible(false))
+	require.NoError(t, writer.RegisterStruct(partialKnown{}, 320))
+	data, err := writer.Marshal([]any{&partialKnown{A: 1}})
+	require.NoError(t, err)
+	data = append([]byte(nil), data...)
+
+	var got []any
+	_, err = New(WithXlang(true), WithCompatible(false)).UnmarshalPartial(data, &got)
+	require.Error(t, err)
+}
@@ -161,6 +161,7 @@ func (s *sliceSerializer) writeDataWithGenerics(ctx *WriteContext, value reflect
 
 	// WriteData length
 	buf.WriteVarUint32(uint32(length))
+	ctx.countWork(length)
 	if length == 0 {
 		return
 	}
@@ -215,16 +216,38 @@ func (s *sliceSerializer) writeDataWithGenerics(ctx *WriteContext, value reflect
 
 	// Serialize elements with ref tracking or nulls handling
 	declaredGenericDispatch := hasGenerics && serializerNeedsGenericDispatch(s.elemSerializer)
-	if !trackRefs && !hasNull {
+	if !trackRefs {
+		if p := ctx.parallel; p != nil {
+			ctx.parallel = nil
+			p.writeList(ctx, value, hasNull, declaredGenericDispatch)
+			return
+		}
+		s.writeElements(ctx, value, 0, length, hasNull, declaredGenericDispatch)
+		return
+	}
+
+	for i := 0; i < length; i++ {
+		// When tracking refs, the element serializer writes the ref or null flag
+		s.elemSerializer.Write(ctx, elemRefMode, false, declaredGenericDispatch, value.Index(i))
+		if ctx.HasError() {
+			return
+		}
+	}
+}
+
+// writeElements writes elements [from, to) of value without reference
+// tracking. Each element is prefixed with a null flag when hasNull is set.
+func (s *sliceSerializer) writeElements(ctx *WriteContext, value reflect.Value, from, to int, hasNull, declaredGenericDispatch bool) {
+	if !hasNull {
 		if declaredGenericDispatch {
-			for i := 0; i < length; i++ {
+			for i := from; i < to; i++ {
 				s.elemSerializer.Write(ctx, RefModeNone, false, true, value.Index(i))
 				if ctx.HasError() {
 					return
 				}
 			}
 		} else {
-			for i := 0; i < length; i++ {
+			for i := from; i < to; i++ {
 				s.elemSerializer.WriteData(ctx, value.Index(i))
 				if ctx.HasError() {
 					return
@@ -234,39 +257,18 @@ func (s *sliceSerializer) writeDataWithGenerics(ctx *WriteContext, value reflect
 		return
 	}
 
-	for i := 0; i < length; i++ {
+	buf := ctx.Buffer()
+	for i := from; i < to; i++ {
 		elem := value.Index(i)
-
-		// Handle null values (only for pointer element types)
-		if hasNull && elem.IsNil() {
-			if trackRefs {
-				// When tracking refs, the element serializer will write the null flag
-				s.elemSerializer.Write(ctx, elemRefMode, false, false, elem)
-			} else {
-				buf.WriteInt8(NullFlag)
-			}
+		if elem.IsNil() {
+			buf.WriteInt8(NullFlag)
 			continue
 		}
-
-		if trackRefs {
-			// Use Write with ref tracking enabled
-			// The element serializer will handle writing ref flags
-			s.elemSerializer.Write(ctx, elemRefMode, false, declaredGenericDispatch, elem)
-		} else if hasNull {
-			// When hasNull is set but trackRefs is not, write NotNullValueFlag before data
-			buf.WriteInt8(NotNullValueFlag)
-			if declaredGenericDispatch {
-				s.elemSerializer.Write(ctx, RefModeNone, false, true, elem)
-			} else {
-				s.elemSerializer.WriteData(ctx, elem)
-			}
+		buf.WriteInt8(NotNullValueFlag)
+		if declaredGenericDispatch {
+			s.elemSerializer.Write(ctx, RefModeNone, false, true, elem)
 		} else {
-			// No ref tracking and no nulls: directly write data
-			if declaredGenericDispatch {
-				s.elemSerializer.Write(ctx, RefModeNone, false, true, elem)
-			} else {
-				s.elemSerializer.WriteData(ctx, elem)
-			}
+			s.elemSerializer.WriteData(ctx, elem)
 		}
 		if ctx.HasError() {
 			return
@@ -307,7 +309,11 @@ func (s *sliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 
 	if length == 0 {
 		if !isArrayType {
-			value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+			if ctx.reuseObjects && !value.IsNil() {
+				value.Set(value.Slice(0, 0))
+			} else {
+				value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+			}
 		}
 		return
 	}
@@ -354,9 +360,12 @@ func (s *sliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		}
 	} else {
 		// For slices, allocate or resize as needed
+		if !ctx.chargeElems(length, value.Type().Elem()) {
+			return
+		}
 		if value.Cap() < length {
 			value.Set(reflect.MakeSlice(value.Type(), length, length))
-		} else if value.Len() < length {
+		} else if value.Len() != length {
 			value.Set(value.Slice(0, length))
 		}
 	}
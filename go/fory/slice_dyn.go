@@ -130,9 +130,16 @@ func (s sliceDynSerializer) writeHeader(ctx *WriteContext, buf *ByteBuffer, valu
 			}
 		}
 	}
+	if hasSameType && firstType == genericRecordPtrType {
+		hasSameType = sameGenericRecordSchema(value)
+	}
 	// Only get elemTypeInfo if all elements have same type
 	if hasSameType && firstElem.IsValid() {
-		elemTypeInfo, _ = ctx.TypeResolver().getTypeInfo(firstElem, true)
+		var err error
+		if elemTypeInfo, err = ctx.TypeResolver().getTypeInfo(firstElem, true); err != nil {
+			ctx.SetError(FromError(err))
+			return 0, nil
+		}
 	}
 
 	// Set collection flags based on findings
@@ -263,6 +270,9 @@ func (s sliceDynSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	ctxErr := ctx.Err()
 	length := ctx.ReadCollectionLength()
 	sliceType := value.Type()
+	if !ctx.chargeElems(length, sliceType.Elem()) {
+		return
+	}
 	value.Set(reflect.MakeSlice(sliceType, length, length))
 	if length == 0 {
 		return
@@ -270,7 +280,15 @@ func (s sliceDynSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 
 	collectFlag := buf.ReadInt8(ctxErr)
 	ctx.RefResolver().Reference(value)
+	s.readElements(ctx, value, collectFlag)
+}
 
+// readElements fills the already allocated slice value with the elements that
+// follow the collection flag.
+func (s sliceDynSerializer) readElements(ctx *ReadContext, value reflect.Value, collectFlag int8) {
+	buf := ctx.Buffer()
+	ctxErr := ctx.Err()
+	sliceType := value.Type()
 	var elemTypeInfo *TypeInfo
 	var elemType reflect.Type
 	var elemSerializer Serializer
@@ -281,6 +299,11 @@ func (s sliceDynSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		if elemTypeInfo != nil && elemTypeInfo.Serializer != nil {
 			elemType = elemTypeInfo.Type
 			elemSerializer = elemTypeInfo.Serializer
+			if elemType == nil {
+				// Unregistered element type: the serializer skips each element,
+				// leaving it as the zero value of the slice element type.
+				elemType = sliceType.Elem()
+			}
 		} else {
 			// When CollectionIsDeclElementType is set, get serializer from the declared element type
 			elemType = sliceType.Elem()
@@ -398,6 +421,16 @@ func (s sliceDynSerializer) readDifferentTypes(
 			if ctxErr.HasError() {
 				return
 			}
+			if typeInfo.Type == nil {
+				typeInfo.Serializer.ReadData(ctx, value.Index(i))
+				if ctx.HasError() {
+					return
+				}
+				if elem := value.Index(i); elem.Kind() == reflect.Interface && !elem.IsNil() {
+					ctx.RefResolver().SetReadObject(refID, elem.Elem())
+				}
+				continue
+			}
 			elemType, serializer := s.wrapSerializerIfNeeded(typeInfo.Type, typeInfo.Serializer)
 			elem := reflect.New(elemType).Elem()
 			serializer.ReadData(ctx, elem)
@@ -414,6 +447,13 @@ func (s sliceDynSerializer) readDifferentTypes(
 			if ctxErr.HasError() {
 				return
 			}
+			if typeInfo.Type == nil {
+				typeInfo.Serializer.ReadData(ctx, value.Index(i))
+				if ctx.HasError() {
+					return
+				}
+				continue
+			}
 			elemType, serializer := s.wrapSerializerIfNeeded(typeInfo.Type, typeInfo.Serializer)
 			elem := reflect.New(elemType).Elem()
 			serializer.ReadData(ctx, elem)
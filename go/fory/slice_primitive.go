@@ -19,7 +19,9 @@ package fory
 
 import (
 	"reflect"
+	"slices"
 	"strconv"
+	"sync"
 	"unsafe"
 
 	"github.com/apache/fory/go/fory/bfloat16"
@@ -40,6 +42,10 @@ type byteSliceSerializer struct{}
 
 func (s byteSliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	v := value.Interface().([]byte)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
 	buf := ctx.Buffer()
 	buf.WriteLength(len(v))
 	if len(v) > 0 {
@@ -72,18 +78,36 @@ func (s byteSliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode,
 }
 
 func (s byteSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	buf := ctx.Buffer()
-	ctxErr := ctx.Err()
-	length := ctx.ReadBinaryLength()
 	ptr := (*[]byte)(value.Addr().UnsafePointer())
+	dst := reusableSlice(ctx, *ptr)
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, dst)
+		return
+	}
+	length := ctx.ReadBinaryLength()
 	if length == 0 {
-		*ptr = make([]byte, 0)
+		*ptr = reuseSlice(dst, 0)
 		return
 	}
-	result := make([]byte, length)
-	raw := buf.ReadBinary(length, ctxErr)
-	copy(result, raw)
-	*ptr = result
+	*ptr = ctx.readBinaryInto(length, dst)
+}
+
+// reuseSlice returns a slice of n elements backed by dst when it has room.
+// A nil dst always allocates so empty results stay non-nil.
+func reuseSlice[T any](dst []T, n int) []T {
+	if dst != nil && cap(dst) >= n {
+		return dst[:n]
+	}
+	return make([]T, n)
+}
+
+// reusableSlice returns the slice already held by the target when object reuse
+// is enabled, so the decoder can overwrite it instead of allocating.
+func reusableSlice[T any](ctx *ReadContext, existing []T) []T {
+	if ctx.reuseObjects {
+		return existing
+	}
+	return nil
 }
 
 type ByteSliceBufferObject struct {
@@ -102,6 +126,165 @@ func (o *ByteSliceBufferObject) ToBuffer() *ByteBuffer {
 	return NewByteBuffer(o.data)
 }
 
+// writeArrayBufferObject writes a primitive array as a buffer object, so the
+// buffer callback can take it out of band. In-band objects keep the array's
+// little-endian payload after the in-band flag.
+func writeArrayBufferObject[T any](ctx *WriteContext, value []T) {
+	ctx.WriteBufferObject(&ByteSliceBufferObject{data: littleEndianBytes(value)})
+}
+
+// readArrayBufferObject reads a primitive array written by
+// writeArrayBufferObject into dst when it has room. With WithZeroCopyBinary
+// the result views the buffer instead when its alignment allows. With
+// WithOutOfBandWorkers large buffers are copied into the result after it is
+// returned, except inside extension serializers, which may inspect it at once.
+func readArrayBufferObject[T any](ctx *ReadContext, dst []T) []T {
+	buf := ctx.ReadBufferObject()
+	if ctx.HasError() {
+		return nil
+	}
+	data := buf.GetData()
+	var zero T
+	elemSize := int(unsafe.Sizeof(zero))
+	if len(data)%elemSize != 0 {
+		ctx.SetError(DeserializationErrorf("buffer object of %d bytes does not hold %d-byte elements", len(data), elemSize))
+		return nil
+	}
+	length := len(data) / elemSize
+	if length == 0 {
+		return reuseSlice(dst, 0)
+	}
+	if ctx.zeroCopyBinary && isLittleEndian && uintptr(unsafe.Pointer(&data[0]))%unsafe.Alignof(zero) == 0 {
+		return unsafe.Slice((*T)(unsafe.Pointer(&data[0])), length)[:length:length]
+	}
+	result := reuseSlice(dst, length)
+	dstBytes := unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), len(data))
+	if ctx.bufferCopies != nil && len(data) >= minConcurrentCopy && ctx.inlineCopies == 0 && ctx.hasOutOfBandSource() {
+		ctx.bufferCopies.copy(dstBytes, data, elemSize)
+	} else {
+		copyLittleEndian(dstBytes, data, elemSize)
+	}
+	return result
+}
+
+// minConcurrentCopy is the smallest buffer worth handing to another goroutine.
+const minConcurrentCopy = 64 << 10
+
+// bufferCopies runs buffer copies on a bounded number of goroutines. When all
+// of them are busy the caller copies inline, so decoding never waits for a
+// free worker.
+type bufferCopies struct {
+	slots chan struct{}
+	wg    sync.WaitGroup
+}
+
+func newBufferCopies(workers int) *bufferCopies {
+	return &bufferCopies{slots: make(chan struct{}, workers)}
+}
+
+func (b *bufferCopies) copy(dst, src []byte, elemSize int) {
+	select {
+	case b.slots <- struct{}{}:
+		b.wg.Add(1)
+		go func() {
+			defer func() {
+				<-b.slots
+				b.wg.Done()
+			}()
+			copyLittleEndian(dst, src, elemSize)
+		}()
+	default:
+		copyLittleEndian(dst, src, elemSize)
+	}
+}
+
+// wait blocks until every copy started by copy has finished.
+func (b *bufferCopies) wait() {
+	b.wg.Wait()
+}
+
+// littleEndianBytes returns the little-endian bytes of value, viewing its
+// memory on little-endian hosts.
+func littleEndianBytes[T any](value []T) []byte {
+	if len(value) == 0 {
+		return nil
+	}
+	elemSize := int(unsafe.Sizeof(value[0]))
+	data := unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), len(value)*elemSize)
+	if isLittleEndian {
+		return data
+	}
+	swapped := slices.Clone(data)
+	swapElementBytes(swapped, elemSize)
+	return swapped
+}
+
+// writeLittleEndian writes the elements of value as consecutive
+// little-endian values with a single copy.
+func writeLittleEndian[T any](buf *ByteBuffer, value []T) {
+	if len(value) == 0 {
+		return
+	}
+	elemSize := int(unsafe.Sizeof(value[0]))
+	writeLittleEndianBytes(buf, unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), len(value)*elemSize), elemSize)
+}
+
+// writeLittleEndianBytes writes data, which holds elemSize-byte values in
+// native byte order, as little-endian values, swapping bytes in the buffer on
+// big-endian hosts.
+func writeLittleEndianBytes(buf *ByteBuffer, data []byte, elemSize int) {
+	buf.grow(len(data))
+	copyLittleEndian(buf.data[buf.writerIndex:buf.writerIndex+len(data)], data, elemSize)
+	buf.writerIndex += len(data)
+}
+
+// arrayBytes returns the memory of an array value, copying the array first
+// when it is not addressable.
+func arrayBytes(value reflect.Value) []byte {
+	if !value.CanAddr() {
+		addressable := reflect.New(value.Type()).Elem()
+		addressable.Set(value)
+		value = addressable
+	}
+	return unsafe.Slice((*byte)(value.Addr().UnsafePointer()), value.Type().Size())
+}
+
+// readLittleEndian reads size bytes of little-endian values written by
+// writeLittleEndian into dst when it has room.
+func readLittleEndian[T any](buf *ByteBuffer, err *Error, size int, dst []T) []T {
+	raw := buf.ReadBinary(size, err)
+	if err.HasError() {
+		return nil
+	}
+	var zero T
+	elemSize := int(unsafe.Sizeof(zero))
+	length := size / elemSize
+	result := reuseSlice(dst, length)
+	if length > 0 {
+		copyLittleEndian(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), length*elemSize), raw, elemSize)
+	}
+	return result
+}
+
+// copyLittleEndian copies elemSize-byte values from src to dst, converting
+// between native and little-endian byte order. The conversion is its own
+// inverse, so it serves both directions.
+func copyLittleEndian(dst, src []byte, elemSize int) {
+	n := copy(dst, src)
+	if !isLittleEndian {
+		swapElementBytes(dst[:n], elemSize)
+	}
+}
+
+func swapElementBytes(data []byte, elemSize int) {
+	if elemSize == 1 {
+		return
+	}
+	for i := 0; i < len(data); i += elemSize {
+		slices.Reverse(data[i : i+elemSize])
+	}
+}
+
 // ============================================================================
 // boolSliceSerializer - optimized []bool serialization
 // ============================================================================
@@ -109,7 +292,12 @@ func (o *ByteSliceBufferObject) ToBuffer() *ByteBuffer {
 type boolSliceSerializer struct{}
 
 func (s boolSliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteBoolSlice(ctx.Buffer(), value.Interface().([]bool))
+	v := value.Interface().([]bool)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteBoolSlice(ctx.Buffer(), v)
 }
 
 func (s boolSliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -137,7 +325,12 @@ func (s boolSliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode,
 }
 
 func (s boolSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]bool)(value.Addr().UnsafePointer()) = ReadBoolSlice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]bool)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readBoolSliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -147,7 +340,12 @@ func (s boolSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type int8SliceSerializer struct{}
 
 func (s int8SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteInt8Slice(ctx.Buffer(), value.Interface().([]int8))
+	v := value.Interface().([]int8)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteInt8Slice(ctx.Buffer(), v)
 }
 
 func (s int8SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -175,7 +373,12 @@ func (s int8SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode,
 }
 
 func (s int8SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]int8)(value.Addr().UnsafePointer()) = ReadInt8Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]int8)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readInt8SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -185,7 +388,12 @@ func (s int8SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type int16SliceSerializer struct{}
 
 func (s int16SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteInt16Slice(ctx.Buffer(), value.Interface().([]int16))
+	v := value.Interface().([]int16)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteInt16Slice(ctx.Buffer(), v)
 }
 
 func (s int16SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -213,7 +421,12 @@ func (s int16SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode
 }
 
 func (s int16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]int16)(value.Addr().UnsafePointer()) = ReadInt16Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]int16)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readInt16SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -223,7 +436,12 @@ func (s int16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type int32SliceSerializer struct{}
 
 func (s int32SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteInt32Slice(ctx.Buffer(), value.Interface().([]int32))
+	v := value.Interface().([]int32)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteInt32Slice(ctx.Buffer(), v)
 }
 
 func (s int32SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -251,7 +469,12 @@ func (s int32SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode
 }
 
 func (s int32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]int32)(value.Addr().UnsafePointer()) = ReadInt32Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]int32)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readInt32SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -261,7 +484,12 @@ func (s int32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type int64SliceSerializer struct{}
 
 func (s int64SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteInt64Slice(ctx.Buffer(), value.Interface().([]int64))
+	v := value.Interface().([]int64)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteInt64Slice(ctx.Buffer(), v)
 }
 
 func (s int64SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -289,7 +517,12 @@ func (s int64SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode
 }
 
 func (s int64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]int64)(value.Addr().UnsafePointer()) = ReadInt64Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]int64)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readInt64SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -299,7 +532,12 @@ func (s int64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type uint16SliceSerializer struct{}
 
 func (s uint16SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteUint16Slice(ctx.Buffer(), value.Interface().([]uint16))
+	v := value.Interface().([]uint16)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteUint16Slice(ctx.Buffer(), v)
 }
 
 func (s uint16SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -327,7 +565,12 @@ func (s uint16SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMod
 }
 
 func (s uint16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]uint16)(value.Addr().UnsafePointer()) = ReadUint16Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]uint16)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readUint16SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -337,7 +580,12 @@ func (s uint16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type uint32SliceSerializer struct{}
 
 func (s uint32SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteUint32Slice(ctx.Buffer(), value.Interface().([]uint32))
+	v := value.Interface().([]uint32)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteUint32Slice(ctx.Buffer(), v)
 }
 
 func (s uint32SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -365,7 +613,12 @@ func (s uint32SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMod
 }
 
 func (s uint32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]uint32)(value.Addr().UnsafePointer()) = ReadUint32Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]uint32)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readUint32SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -375,7 +628,12 @@ func (s uint32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type uint64SliceSerializer struct{}
 
 func (s uint64SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteUint64Slice(ctx.Buffer(), value.Interface().([]uint64))
+	v := value.Interface().([]uint64)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteUint64Slice(ctx.Buffer(), v)
 }
 
 func (s uint64SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -403,7 +661,12 @@ func (s uint64SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMod
 }
 
 func (s uint64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]uint64)(value.Addr().UnsafePointer()) = ReadUint64Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]uint64)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readUint64SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -413,7 +676,12 @@ func (s uint64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type float32SliceSerializer struct{}
 
 func (s float32SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteFloat32Slice(ctx.Buffer(), value.Interface().([]float32))
+	v := value.Interface().([]float32)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteFloat32Slice(ctx.Buffer(), v)
 }
 
 func (s float32SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -441,7 +709,12 @@ func (s float32SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMo
 }
 
 func (s float32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]float32)(value.Addr().UnsafePointer()) = ReadFloat32Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]float32)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readFloat32SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -451,7 +724,12 @@ func (s float32SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 type float64SliceSerializer struct{}
 
 func (s float64SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteFloat64Slice(ctx.Buffer(), value.Interface().([]float64))
+	v := value.Interface().([]float64)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteFloat64Slice(ctx.Buffer(), v)
 }
 
 func (s float64SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -479,7 +757,12 @@ func (s float64SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMo
 }
 
 func (s float64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]float64)(value.Addr().UnsafePointer()) = ReadFloat64Slice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]float64)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readFloat64SliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -489,7 +772,12 @@ func (s float64SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 type intSliceSerializer struct{}
 
 func (s intSliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteIntSlice(ctx.Buffer(), value.Interface().([]int))
+	v := value.Interface().([]int)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteIntSlice(ctx.Buffer(), v)
 }
 
 func (s intSliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -527,7 +815,12 @@ func (s intSliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode,
 }
 
 func (s intSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]int)(value.Addr().UnsafePointer()) = ReadIntSlice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]int)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readIntSliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -539,7 +832,12 @@ func (s intSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 type uintSliceSerializer struct{}
 
 func (s uintSliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	WriteUintSlice(ctx.Buffer(), value.Interface().([]uint))
+	v := value.Interface().([]uint)
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
+	}
+	WriteUintSlice(ctx.Buffer(), v)
 }
 
 func (s uintSliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -577,7 +875,12 @@ func (s uintSliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode,
 }
 
 func (s uintSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	*(*[]uint)(value.Addr().UnsafePointer()) = ReadUintSlice(ctx.Buffer(), ctx.Err())
+	ptr := (*[]uint)(value.Addr().UnsafePointer())
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
+		return
+	}
+	*ptr = readUintSliceInto(ctx.Buffer(), ctx.Err(), reusableSlice(ctx, *ptr))
 }
 
 // ============================================================================
@@ -607,7 +910,7 @@ func (s stringSliceSerializer) writeDataWithGenerics(ctx *WriteContext, value re
 
 	// Write elements directly (no ref flag for strings)
 	for i := 0; i < length; i++ {
-		writeString(buf, v[i])
+		ctx.WriteString(v[i])
 	}
 }
 
@@ -644,8 +947,12 @@ func (s stringSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 	ctxErr := ctx.Err()
 	length := ctx.ReadCollectionLength()
 	ptr := (*[]string)(value.Addr().UnsafePointer())
+	dst := reusableSlice(ctx, *ptr)
+	if !ctx.chargeElems(length, stringType) {
+		return
+	}
 	if length == 0 {
-		*ptr = make([]string, 0)
+		*ptr = reuseSlice(dst, 0)
 		return
 	}
 
@@ -657,7 +964,7 @@ func (s stringSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		_ = buf.ReadUint8(ctxErr) // Read and discard type ID (we know it's STRING)
 	}
 
-	result := make([]string, length)
+	result := reuseSlice(dst, length)
 
 	// Check if remote sent with ref tracking (handle both cases for compatibility)
 	trackRefs := (collectFlag & CollectionTrackingRef) != 0
@@ -667,10 +974,11 @@ func (s stringSliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		if trackRefs {
 			refFlag := buf.ReadInt8(ctxErr)
 			if refFlag == NullFlag {
-				continue // null string, leave as zero value
+				result[i] = ""
+				continue
 			}
 		}
-		result[i] = readString(buf, ctxErr)
+		result[i] = ctx.ReadString()
 	}
 	*ptr = result
 }
@@ -698,15 +1006,20 @@ func WriteByteSlice(buf *ByteBuffer, value []byte) {
 
 // ReadByteSlice reads []byte from buffer using ARRAY protocol
 func ReadByteSlice(buf *ByteBuffer, err *Error) []byte {
+	return readByteSliceInto(buf, err, nil)
+}
+
+// readByteSliceInto is ReadByteSlice decoding into dst when it has room
+func readByteSliceInto(buf *ByteBuffer, err *Error, dst []byte) []byte {
 	size := buf.ReadLength(err)
 	if size == 0 {
-		return make([]byte, 0)
+		return reuseSlice(dst, 0)
 	}
 	raw := buf.ReadBinary(size, err)
 	if err.HasError() {
 		return nil
 	}
-	result := make([]byte, size)
+	result := reuseSlice(dst, size)
 	copy(result, raw)
 	return result
 }
@@ -722,15 +1035,20 @@ func WriteBoolSlice(buf *ByteBuffer, value []bool) {
 
 // ReadBoolSlice reads []bool from buffer using ARRAY protocol
 func ReadBoolSlice(buf *ByteBuffer, err *Error) []bool {
+	return readBoolSliceInto(buf, err, nil)
+}
+
+// readBoolSliceInto is ReadBoolSlice decoding into dst when it has room
+func readBoolSliceInto(buf *ByteBuffer, err *Error, dst []bool) []bool {
 	size := buf.ReadLength(err)
 	if size == 0 {
-		return make([]bool, 0)
+		return reuseSlice(dst, 0)
 	}
 	raw := buf.ReadBinary(size, err)
 	if err.HasError() {
 		return nil
 	}
-	result := make([]bool, size)
+	result := reuseSlice(dst, size)
 	copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
 	return result
 }
@@ -746,329 +1064,150 @@ func WriteInt8Slice(buf *ByteBuffer, value []int8) {
 
 // ReadInt8Slice reads []int8 from buffer using ARRAY protocol
 func ReadInt8Slice(buf *ByteBuffer, err *Error) []int8 {
+	return readInt8SliceInto(buf, err, nil)
+}
+
+// readInt8SliceInto is ReadInt8Slice decoding into dst when it has room
+func readInt8SliceInto(buf *ByteBuffer, err *Error, dst []int8) []int8 {
 	size := buf.ReadLength(err)
 	if size == 0 {
-		return make([]int8, 0)
+		return reuseSlice(dst, 0)
 	}
 	raw := buf.ReadBinary(size, err)
 	if err.HasError() {
 		return nil
 	}
-	result := make([]int8, size)
+	result := reuseSlice(dst, size)
 	copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
 	return result
 }
 
 // WriteInt16Slice writes []int16 to buffer using ARRAY protocol
 func WriteInt16Slice(buf *ByteBuffer, value []int16) {
-	size := len(value) * 2
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt16(value[i])
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 2)
+	writeLittleEndian(buf, value)
 }
 
 // ReadInt16Slice reads []int16 from buffer using ARRAY protocol
 func ReadInt16Slice(buf *ByteBuffer, err *Error) []int16 {
-	size := buf.ReadLength(err)
-	length := size / 2
-	if length == 0 {
-		return make([]int16, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]int16, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]int16, length)
-		for i := 0; i < length; i++ {
-			result[i] = buf.ReadInt16(err)
-		}
-		return result
-	}
+	return readInt16SliceInto(buf, err, nil)
+}
+
+// readInt16SliceInto is ReadInt16Slice decoding into dst when it has room
+func readInt16SliceInto(buf *ByteBuffer, err *Error, dst []int16) []int16 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteInt32Slice writes []int32 to buffer using ARRAY protocol
 func WriteInt32Slice(buf *ByteBuffer, value []int32) {
-	size := len(value) * 4
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt32(value[i])
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 4)
+	writeLittleEndian(buf, value)
 }
 
 // ReadInt32Slice reads []int32 from buffer using ARRAY protocol
 func ReadInt32Slice(buf *ByteBuffer, err *Error) []int32 {
-	size := buf.ReadLength(err)
-	length := size / 4
-	if length == 0 {
-		return make([]int32, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]int32, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]int32, length)
-		for i := 0; i < length; i++ {
-			result[i] = buf.ReadInt32(err)
-		}
-		return result
-	}
+	return readInt32SliceInto(buf, err, nil)
+}
+
+// readInt32SliceInto is ReadInt32Slice decoding into dst when it has room
+func readInt32SliceInto(buf *ByteBuffer, err *Error, dst []int32) []int32 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteInt64Slice writes []int64 to buffer using ARRAY protocol
 func WriteInt64Slice(buf *ByteBuffer, value []int64) {
-	size := len(value) * 8
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt64(value[i])
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 8)
+	writeLittleEndian(buf, value)
 }
 
 // ReadInt64Slice reads []int64 from buffer using ARRAY protocol
 func ReadInt64Slice(buf *ByteBuffer, err *Error) []int64 {
-	size := buf.ReadLength(err)
-	length := size / 8
-	if length == 0 {
-		return make([]int64, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]int64, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]int64, length)
-		for i := 0; i < length; i++ {
-			result[i] = buf.ReadInt64(err)
-		}
-		return result
-	}
+	return readInt64SliceInto(buf, err, nil)
+}
+
+// readInt64SliceInto is ReadInt64Slice decoding into dst when it has room
+func readInt64SliceInto(buf *ByteBuffer, err *Error, dst []int64) []int64 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteUint16Slice writes []uint16 to buffer using ARRAY protocol
 func WriteUint16Slice(buf *ByteBuffer, value []uint16) {
-	size := len(value) * 2
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt16(int16(value[i]))
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 2)
+	writeLittleEndian(buf, value)
 }
 
 // ReadUint16Slice reads []uint16 from buffer using ARRAY protocol
 func ReadUint16Slice(buf *ByteBuffer, err *Error) []uint16 {
-	size := buf.ReadLength(err)
-	length := size / 2
-	if length == 0 {
-		return make([]uint16, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]uint16, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]uint16, length)
-		for i := 0; i < length; i++ {
-			result[i] = uint16(buf.ReadInt16(err))
-		}
-		return result
-	}
+	return readUint16SliceInto(buf, err, nil)
+}
+
+// readUint16SliceInto is ReadUint16Slice decoding into dst when it has room
+func readUint16SliceInto(buf *ByteBuffer, err *Error, dst []uint16) []uint16 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteUint32Slice writes []uint32 to buffer using ARRAY protocol
 func WriteUint32Slice(buf *ByteBuffer, value []uint32) {
-	size := len(value) * 4
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt32(int32(value[i]))
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 4)
+	writeLittleEndian(buf, value)
 }
 
 // ReadUint32Slice reads []uint32 from buffer using ARRAY protocol
 func ReadUint32Slice(buf *ByteBuffer, err *Error) []uint32 {
-	size := buf.ReadLength(err)
-	length := size / 4
-	if length == 0 {
-		return make([]uint32, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]uint32, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]uint32, length)
-		for i := 0; i < length; i++ {
-			result[i] = uint32(buf.ReadInt32(err))
-		}
-		return result
-	}
+	return readUint32SliceInto(buf, err, nil)
+}
+
+// readUint32SliceInto is ReadUint32Slice decoding into dst when it has room
+func readUint32SliceInto(buf *ByteBuffer, err *Error, dst []uint32) []uint32 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteUint64Slice writes []uint64 to buffer using ARRAY protocol
 func WriteUint64Slice(buf *ByteBuffer, value []uint64) {
-	size := len(value) * 8
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteInt64(int64(value[i]))
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 8)
+	writeLittleEndian(buf, value)
 }
 
 // ReadUint64Slice reads []uint64 from buffer using ARRAY protocol
 func ReadUint64Slice(buf *ByteBuffer, err *Error) []uint64 {
-	size := buf.ReadLength(err)
-	length := size / 8
-	if length == 0 {
-		return make([]uint64, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]uint64, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]uint64, length)
-		for i := 0; i < length; i++ {
-			result[i] = uint64(buf.ReadInt64(err))
-		}
-		return result
-	}
+	return readUint64SliceInto(buf, err, nil)
+}
+
+// readUint64SliceInto is ReadUint64Slice decoding into dst when it has room
+func readUint64SliceInto(buf *ByteBuffer, err *Error, dst []uint64) []uint64 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteFloat32Slice writes []float32 to buffer using ARRAY protocol
 func WriteFloat32Slice(buf *ByteBuffer, value []float32) {
-	size := len(value) * 4
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteFloat32(value[i])
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 4)
+	writeLittleEndian(buf, value)
 }
 
 // ReadFloat32Slice reads []float32 from buffer using ARRAY protocol
 func ReadFloat32Slice(buf *ByteBuffer, err *Error) []float32 {
-	size := buf.ReadLength(err)
-	length := size / 4
-	if length == 0 {
-		return make([]float32, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]float32, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]float32, length)
-		for i := 0; i < length; i++ {
-			result[i] = buf.ReadFloat32(err)
-		}
-		return result
-	}
+	return readFloat32SliceInto(buf, err, nil)
+}
+
+// readFloat32SliceInto is ReadFloat32Slice decoding into dst when it has room
+func readFloat32SliceInto(buf *ByteBuffer, err *Error, dst []float32) []float32 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteFloat64Slice writes []float64 to buffer using ARRAY protocol
 func WriteFloat64Slice(buf *ByteBuffer, value []float64) {
-	size := len(value) * 8
-	buf.WriteLength(size)
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for i := 0; i < len(value); i++ {
-				buf.WriteFloat64(value[i])
-			}
-		}
-	}
+	buf.WriteLength(len(value) * 8)
+	writeLittleEndian(buf, value)
 }
 
 // ReadFloat64Slice reads []float64 from buffer using ARRAY protocol
 func ReadFloat64Slice(buf *ByteBuffer, err *Error) []float64 {
-	size := buf.ReadLength(err)
-	length := size / 8
-	if length == 0 {
-		return make([]float64, 0)
-	}
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, err)
-		if err.HasError() {
-			return nil
-		}
-		result := make([]float64, length)
-		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		return result
-	} else {
-		result := make([]float64, length)
-		for i := 0; i < length; i++ {
-			result[i] = buf.ReadFloat64(err)
-		}
-		return result
-	}
+	return readFloat64SliceInto(buf, err, nil)
+}
+
+// readFloat64SliceInto is ReadFloat64Slice decoding into dst when it has room
+func readFloat64SliceInto(buf *ByteBuffer, err *Error, dst []float64) []float64 {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // ============================================================================
@@ -1078,26 +1217,14 @@ func ReadFloat64Slice(buf *ByteBuffer, err *Error) []float64 {
 type float16SliceSerializer struct{}
 
 func (s float16SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	// Cast to []float16.Float16
 	v := value.Interface().([]float16.Float16)
-	buf := ctx.Buffer()
-	length := len(v)
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		// Float16 is uint16 underneath, so we can cast slice pointer
-		ptr := unsafe.Pointer(&v[0])
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			// Big-endian architectures need explicit byte swapping
-			for i := 0; i < length; i++ {
-				// We can just write as uint16, WriteUint16 handles endianness for us
-				// Float16.Bits() returns uint16
-				buf.WriteUint16(v[i].Bits())
-			}
-		}
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
 	}
+	buf := ctx.Buffer()
+	buf.WriteLength(len(v) * 2)
+	writeLittleEndian(buf, v)
 }
 
 func (s float16SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -1125,191 +1252,59 @@ func (s float16SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMo
 }
 
 func (s float16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	buf := ctx.Buffer()
-	ctxErr := ctx.Err()
-	size := ctx.ReadBinaryLength()
-	length := size / 2
-	if ctx.HasError() {
-		return
-	}
-
-	// Ensure capacity
 	ptr := (*[]float16.Float16)(value.Addr().UnsafePointer())
-	if length == 0 {
-		*ptr = make([]float16.Float16, 0)
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
 		return
 	}
-
-	result := make([]float16.Float16, length)
-
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, ctxErr)
-		// unsafe copy
-		targetPtr := unsafe.Pointer(&result[0])
-		copy(unsafe.Slice((*byte)(targetPtr), size), raw)
-	} else {
-		for i := 0; i < length; i++ {
-			// ReadUint16 handles endianness
-			result[i] = float16.Float16FromBits(buf.ReadUint16(ctxErr))
-		}
+	size := ctx.ReadBinaryLength()
+	if ctx.HasError() {
+		return
 	}
-	*ptr = result
+	*ptr = readLittleEndian(ctx.Buffer(), ctx.Err(), size, reusableSlice(ctx, *ptr))
 }
 
 // WriteIntSlice writes []int to buffer using ARRAY protocol
 func WriteIntSlice(buf *ByteBuffer, value []int) {
-	if strconv.IntSize == 64 {
-		size := len(value) * 8
-		buf.WriteLength(size)
-		if len(value) > 0 {
-			if isLittleEndian {
-				buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-			} else {
-				for i := 0; i < len(value); i++ {
-					buf.WriteInt64(int64(value[i]))
-				}
-			}
-		}
-	} else {
-		size := len(value) * 4
-		buf.WriteLength(size)
-		if len(value) > 0 {
-			if isLittleEndian {
-				buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-			} else {
-				for i := 0; i < len(value); i++ {
-					buf.WriteInt32(int32(value[i]))
-				}
-			}
-		}
-	}
+	buf.WriteLength(len(value) * strconv.IntSize / 8)
+	writeLittleEndian(buf, value)
 }
 
 // ReadIntSlice reads []int from buffer using ARRAY protocol
 func ReadIntSlice(buf *ByteBuffer, err *Error) []int {
-	size := buf.ReadLength(err)
-	if strconv.IntSize == 64 {
-		length := size / 8
-		if length == 0 {
-			return make([]int, 0)
-		}
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			if err.HasError() {
-				return nil
-			}
-			result := make([]int, length)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-			return result
-		} else {
-			result := make([]int, length)
-			for i := 0; i < length; i++ {
-				result[i] = int(buf.ReadInt64(err))
-			}
-			return result
-		}
-	} else {
-		length := size / 4
-		if length == 0 {
-			return make([]int, 0)
-		}
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			if err.HasError() {
-				return nil
-			}
-			result := make([]int, length)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-			return result
-		} else {
-			result := make([]int, length)
-			for i := 0; i < length; i++ {
-				result[i] = int(buf.ReadInt32(err))
-			}
-			return result
-		}
-	}
+	return readIntSliceInto(buf, err, nil)
+}
+
+// readIntSliceInto is ReadIntSlice decoding into dst when it has room
+func readIntSliceInto(buf *ByteBuffer, err *Error, dst []int) []int {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteUintSlice writes []uint to buffer using ARRAY protocol
 func WriteUintSlice(buf *ByteBuffer, value []uint) {
-	if strconv.IntSize == 64 {
-		size := len(value) * 8
-		buf.WriteLength(size)
-		if len(value) > 0 {
-			if isLittleEndian {
-				buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-			} else {
-				for i := 0; i < len(value); i++ {
-					buf.WriteInt64(int64(value[i]))
-				}
-			}
-		}
-	} else {
-		size := len(value) * 4
-		buf.WriteLength(size)
-		if len(value) > 0 {
-			if isLittleEndian {
-				buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-			} else {
-				for i := 0; i < len(value); i++ {
-					buf.WriteInt32(int32(value[i]))
-				}
-			}
-		}
-	}
+	buf.WriteLength(len(value) * strconv.IntSize / 8)
+	writeLittleEndian(buf, value)
 }
 
 // ReadUintSlice reads []uint from buffer using ARRAY protocol
 func ReadUintSlice(buf *ByteBuffer, err *Error) []uint {
-	size := buf.ReadLength(err)
-	if strconv.IntSize == 64 {
-		length := size / 8
-		if length == 0 {
-			return make([]uint, 0)
-		}
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			if err.HasError() {
-				return nil
-			}
-			result := make([]uint, length)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-			return result
-		} else {
-			result := make([]uint, length)
-			for i := 0; i < length; i++ {
-				result[i] = uint(buf.ReadInt64(err))
-			}
-			return result
-		}
-	} else {
-		length := size / 4
-		if length == 0 {
-			return make([]uint, 0)
-		}
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			if err.HasError() {
-				return nil
-			}
-			result := make([]uint, length)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-			return result
-		} else {
-			result := make([]uint, length)
-			for i := 0; i < length; i++ {
-				result[i] = uint(buf.ReadInt32(err))
-			}
-			return result
-		}
-	}
+	return readUintSliceInto(buf, err, nil)
+}
+
+// readUintSliceInto is ReadUintSlice decoding into dst when it has room
+func readUintSliceInto(buf *ByteBuffer, err *Error, dst []uint) []uint {
+	return readLittleEndian(buf, err, buf.ReadLength(err), dst)
 }
 
 // WriteStringSlice writes []string to buffer using LIST protocol.
 // When hasGenerics is true (element type known from TypeDef/generics), uses IS_DECL_ELEMENT_TYPE
 // and doesn't write element type ID. When false, writes element type ID.
 func WriteStringSlice(buf *ByteBuffer, value []string, hasGenerics bool) {
+	writeStringSlice(buf, value, hasGenerics, writeString)
+}
+
+// writeStringSlice is WriteStringSlice encoding elements with write
+func writeStringSlice(buf *ByteBuffer, value []string, hasGenerics bool, write func(*ByteBuffer, string)) {
 	length := len(value)
 	buf.WriteVarUint32(uint32(length))
 	if length > 0 {
@@ -1322,32 +1317,48 @@ func WriteStringSlice(buf *ByteBuffer, value []string, hasGenerics bool) {
 			buf.WriteUint8(uint8(STRING))
 		}
 		for i := 0; i < length; i++ {
-			writeString(buf, value[i])
+			write(buf, value[i])
 		}
 	}
 }
 
 // ReadStringSlice reads []string from buffer using LIST protocol
 func ReadStringSlice(buf *ByteBuffer, err *Error) []string {
+	return readStringSliceInto(buf, err, nil, nil)
+}
+
+// readStringSliceInto is ReadStringSlice decoding into dst when it has room,
+// reading elements through ctx when it is non-nil so they are interned,
+// resolved against the string dictionary and charged to the decode budget
+func readStringSliceInto(buf *ByteBuffer, err *Error, ctx *ReadContext, dst []string) []string {
 	length := buf.ReadLength(err)
+	if ctx != nil && !ctx.chargeElems(length, stringType) {
+		return nil
+	}
 	if length == 0 {
-		return make([]string, 0)
+		return reuseSlice(dst, 0)
 	}
 	collectFlag := buf.ReadInt8(err)
 	if (collectFlag&CollectionIsSameType) != 0 && (collectFlag&CollectionIsDeclElementType) == 0 {
 		_ = buf.ReadUint8(err) // Read and discard element type ID
 	}
-	result := make([]string, length)
+	result := reuseSlice(dst, length)
 	trackRefs := (collectFlag & CollectionTrackingRef) != 0
 	hasNull := (collectFlag & CollectionHasNull) != 0
+	viaCtx := ctx != nil
 	for i := 0; i < length; i++ {
 		if trackRefs || hasNull {
 			rf := buf.ReadInt8(err)
 			if rf == NullFlag {
+				result[i] = ""
 				continue
 			}
 		}
-		result[i] = readString(buf, err)
+		if viaCtx {
+			result[i] = ctx.ReadString()
+		} else {
+			result[i] = readString(buf, err)
+		}
 	}
 	return result
 }
@@ -1359,22 +1370,14 @@ func ReadStringSlice(buf *ByteBuffer, err *Error) []string {
 type bfloat16SliceSerializer struct{}
 
 func (s bfloat16SliceSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	// Cast to []bfloat16.BFloat16
 	v := value.Interface().([]bfloat16.BFloat16)
-	buf := ctx.Buffer()
-	length := len(v)
-	size := length * 2
-	buf.WriteLength(size)
-	if length > 0 {
-		ptr := unsafe.Pointer(&v[0])
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(ptr), size))
-		} else {
-			for i := 0; i < length; i++ {
-				buf.WriteUint16(v[i].Bits())
-			}
-		}
+	if ctx.outOfBand {
+		writeArrayBufferObject(ctx, v)
+		return
 	}
+	buf := ctx.Buffer()
+	buf.WriteLength(len(v) * 2)
+	writeLittleEndian(buf, v)
 }
 
 func (s bfloat16SliceSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -1402,30 +1405,14 @@ func (s bfloat16SliceSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefM
 }
 
 func (s bfloat16SliceSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	buf := ctx.Buffer()
-	ctxErr := ctx.Err()
-	size := ctx.ReadBinaryLength()
-	length := size / 2
-	if ctx.HasError() {
-		return
-	}
-
 	ptr := (*[]bfloat16.BFloat16)(value.Addr().UnsafePointer())
-	if length == 0 {
-		*ptr = make([]bfloat16.BFloat16, 0)
+	if ctx.peerOutOfBand {
+		*ptr = readArrayBufferObject(ctx, reusableSlice(ctx, *ptr))
 		return
 	}
-
-	result := make([]bfloat16.BFloat16, length)
-
-	if isLittleEndian {
-		raw := buf.ReadBinary(size, ctxErr)
-		targetPtr := unsafe.Pointer(&result[0])
-		copy(unsafe.Slice((*byte)(targetPtr), size), raw)
-	} else {
-		for i := 0; i < length; i++ {
-			result[i] = bfloat16.BFloat16FromBits(buf.ReadUint16(ctxErr))
-		}
+	size := ctx.ReadBinaryLength()
+	if ctx.HasError() {
+		return
 	}
-	*ptr = result
+	*ptr = readLittleEndian(ctx.Buffer(), ctx.Err(), size, reusableSlice(ctx, *ptr))
 }
@@ -0,0 +1,141 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+)
+
+const benchSliceLen = 4096
+
+func benchFloat64Slice() []float64 {
+	values := make([]float64, benchSliceLen)
+	for i := range values {
+		values[i] = float64(i) * 1.5
+	}
+	return values
+}
+
+func benchInt16Slice() []int16 {
+	values := make([]int16, benchSliceLen)
+	for i := range values {
+		values[i] = int16(i)
+	}
+	return values
+}
+
+func BenchmarkWriteFloat64SliceBulk(b *testing.B) {
+	buf := NewByteBuffer(make([]byte, 0, benchSliceLen*8+8))
+	values := benchFloat64Slice()
+	b.SetBytes(int64(len(values) * 8))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.writerIndex = 0
+		WriteFloat64Slice(buf, values)
+	}
+}
+
+func BenchmarkWriteFloat64SliceElementwise(b *testing.B) {
+	buf := NewByteBuffer(make([]byte, 0, benchSliceLen*8+8))
+	values := benchFloat64Slice()
+	b.SetBytes(int64(len(values) * 8))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.writerIndex = 0
+		buf.WriteLength(len(values) * 8)
+		for _, v := range values {
+			buf.WriteFloat64(v)
+		}
+	}
+}
+
+func BenchmarkReadFloat64SliceBulk(b *testing.B) {
+	buf := NewByteBuffer(nil)
+	WriteFloat64Slice(buf, benchFloat64Slice())
+	dst := make([]float64, benchSliceLen)
+	var err Error
+	b.SetBytes(benchSliceLen * 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.readerIndex = 0
+		dst = readFloat64SliceInto(buf, &err, dst)
+	}
+}
+
+func BenchmarkReadFloat64SliceElementwise(b *testing.B) {
+	buf := NewByteBuffer(nil)
+	WriteFloat64Slice(buf, benchFloat64Slice())
+	dst := make([]float64, benchSliceLen)
+	var err Error
+	b.SetBytes(benchSliceLen * 8)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.readerIndex = 0
+		length := buf.ReadLength(&err) / 8
+		for j := 0; j < length; j++ {
+			dst[j] = buf.ReadFloat64(&err)
+		}
+	}
+}
+
+func BenchmarkWriteInt16SliceBulk(b *testing.B) {
+	buf := NewByteBuffer(make([]byte, 0, benchSliceLen*2+8))
+	values := benchInt16Slice()
+	b.SetBytes(int64(len(values) * 2))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.writerIndex = 0
+		WriteInt16Slice(buf, values)
+	}
+}
+
+func BenchmarkWriteInt16SliceElementwise(b *testing.B) {
+	buf := NewByteBuffer(make([]byte, 0, benchSliceLen*2+8))
+	values := benchInt16Slice()
+	b.SetBytes(int64(len(values) * 2))
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		buf.writerIndex = 0
+		buf.WriteLength(len(values) * 2)
+		for _, v := range values {
+			buf.WriteInt16(v)
+		}
+	}
+}
+
+func BenchmarkWriteInt16ArrayNonAddressable(b *testing.B) {
+	f := NewFory(WithXlang(false))
+	ctx := f.writeCtx
+	var arr [benchSliceLen]int16
+	copy(arr[:], benchInt16Slice())
+	value := reflect.ValueOf(arr)
+	b.SetBytes(benchSliceLen * 2)
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		ctx.buffer.writerIndex = 0
+		int16ArraySerializer{}.WriteData(ctx, value)
+	}
+}
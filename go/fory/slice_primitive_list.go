@@ -164,8 +164,15 @@ func (s primitiveListSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 	buf := ctx.Buffer()
 	err := ctx.Err()
 	length := ctx.ReadCollectionLength()
+	if !ctx.chargeElems(length, s.type_.Elem()) {
+		return
+	}
 	if length == 0 {
-		value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+		if ctx.reuseObjects && !value.IsNil() {
+			value.Set(value.Slice(0, 0))
+		} else {
+			value.Set(reflect.MakeSlice(value.Type(), 0, 0))
+		}
 		return
 	}
 	collectFlag := buf.ReadInt8(err)
@@ -179,7 +186,7 @@ func (s primitiveListSerializer) ReadData(ctx *ReadContext, value reflect.Value)
 		return
 	}
 	hasNull := (collectFlag & CollectionHasNull) != 0
-	s.readValues(buf, err, value, length, hasNull)
+	s.readValues(ctx, buf, err, value, length, hasNull)
 }
 
 func (s compatiblePrimitiveListToArraySerializer) WriteData(ctx *WriteContext, value reflect.Value) {
@@ -246,7 +253,7 @@ func (s compatiblePrimitiveListToArraySerializer) ReadData(ctx *ReadContext, val
 	}
 	if value.Kind() == reflect.Slice {
 		temp := reflect.New(value.Type()).Elem()
-		s.listReader.readValues(buf, err, temp, length, false)
+		s.listReader.readValues(ctx, buf, err, temp, length, false)
 		if ctx.HasError() {
 			return
 		}
@@ -260,38 +267,61 @@ func (s compatiblePrimitiveListToArraySerializer) ReadWithTypeInfo(ctx *ReadCont
 	s.Read(ctx, refMode, false, false, value)
 }
 
-func (s primitiveListSerializer) readValues(buf *ByteBuffer, err *Error, value reflect.Value, length int, hasNull bool) {
+func (s primitiveListSerializer) readValues(ctx *ReadContext, buf *ByteBuffer, err *Error, value reflect.Value, length int, hasNull bool) {
 	switch s.type_.Elem().Kind() {
 	case reflect.Bool:
-		*(*[]bool)(value.Addr().UnsafePointer()) = readBoolListPayload(buf, err, length, hasNull)
+		ptr := (*[]bool)(value.Addr().UnsafePointer())
+		*ptr = readBoolListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	case reflect.Int8:
-		*(*[]int8)(value.Addr().UnsafePointer()) = readInt8ListPayload(buf, err, length, hasNull)
+		ptr := (*[]int8)(value.Addr().UnsafePointer())
+		*ptr = readInt8ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	case reflect.Uint8:
-		*(*[]byte)(value.Addr().UnsafePointer()) = readUint8ListPayload(buf, err, length, hasNull)
+		ptr := (*[]byte)(value.Addr().UnsafePointer())
+		if hasNull {
+			*ptr = readUint8ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
+		} else {
+			*ptr = ctx.readBinaryInto(length, reusableSlice(ctx, *ptr))
+		}
 	case reflect.Int16:
-		*(*[]int16)(value.Addr().UnsafePointer()) = readInt16ListPayload(buf, err, length, hasNull)
+		ptr := (*[]int16)(value.Addr().UnsafePointer())
+		*ptr = readInt16ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	case reflect.Uint16:
-		*(*[]uint16)(value.Addr().UnsafePointer()) = readUint16ListPayload(buf, err, length, hasNull)
+		ptr := (*[]uint16)(value.Addr().UnsafePointer())
+		*ptr = readUint16ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	case reflect.Int32:
-		*(*[]int32)(value.Addr().UnsafePointer()) = readInt32ListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]int32)(value.Addr().UnsafePointer())
+		*ptr = readInt32ListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Uint32:
-		*(*[]uint32)(value.Addr().UnsafePointer()) = readUint32ListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]uint32)(value.Addr().UnsafePointer())
+		*ptr = readUint32ListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Int64:
-		*(*[]int64)(value.Addr().UnsafePointer()) = readInt64ListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]int64)(value.Addr().UnsafePointer())
+		*ptr = readInt64ListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Uint64:
-		*(*[]uint64)(value.Addr().UnsafePointer()) = readUint64ListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]uint64)(value.Addr().UnsafePointer())
+		*ptr = readUint64ListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Int:
-		*(*[]int)(value.Addr().UnsafePointer()) = readIntListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]int)(value.Addr().UnsafePointer())
+		*ptr = readIntListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Uint:
-		*(*[]uint)(value.Addr().UnsafePointer()) = readUintListPayload(buf, err, length, hasNull, s.elemTypeID)
+		ptr := (*[]uint)(value.Addr().UnsafePointer())
+		*ptr = readUintListPayload(buf, err, length, hasNull, s.elemTypeID, reusableSlice(ctx, *ptr))
 	case reflect.Float32:
-		*(*[]float32)(value.Addr().UnsafePointer()) = readFloat32ListPayload(buf, err, length, hasNull)
+		ptr := (*[]float32)(value.Addr().UnsafePointer())
+		*ptr = readFloat32ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	case reflect.Float64:
-		*(*[]float64)(value.Addr().UnsafePointer()) = readFloat64ListPayload(buf, err, length, hasNull)
+		ptr := (*[]float64)(value.Addr().UnsafePointer())
+		*ptr = readFloat64ListPayload(buf, err, length, hasNull, reusableSlice(ctx, *ptr))
 	}
 }
 
 func (s primitiveListSerializer) readArrayValues(buf *ByteBuffer, err *Error, value reflect.Value, length int) {
+	if elemSize := s.fixedElemSize(); elemSize != 0 && value.CanAddr() && int(value.Type().Elem().Size()) == elemSize {
+		size := length * elemSize
+		raw := buf.ReadBinary(size, err)
+		copyLittleEndian(unsafe.Slice((*byte)(value.Addr().UnsafePointer()), size), raw, elemSize)
+		return
+	}
 	switch s.type_.Elem().Kind() {
 	case reflect.Bool:
 		raw := buf.ReadBinary(length, err)
@@ -389,19 +419,43 @@ func (s primitiveListSerializer) readArrayValues(buf *ByteBuffer, err *Error, va
 	}
 }
 
+// fixedElemSize returns the size of an element when the payload holds each
+// element in its in-memory width in little-endian order, or 0 when elements
+// are variable-length.
+func (s primitiveListSerializer) fixedElemSize() int {
+	elemType := s.type_.Elem()
+	switch elemType.Kind() {
+	case reflect.Bool, reflect.Int8, reflect.Uint8, reflect.Int16, reflect.Uint16, reflect.Float32, reflect.Float64:
+		return int(elemType.Size())
+	case reflect.Int32, reflect.Uint32, reflect.Int64, reflect.Uint64, reflect.Int, reflect.Uint:
+		wireSize := 0
+		switch s.elemTypeID {
+		case INT32, UINT32:
+			wireSize = 4
+		case INT64, UINT64:
+			wireSize = 8
+		}
+		if wireSize == int(elemType.Size()) {
+			return wireSize
+		}
+	}
+	return 0
+}
+
 func writeBoolListPayload(buf *ByteBuffer, value []bool) {
 	if len(value) > 0 {
 		buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), len(value)))
 	}
 }
 
-func readBoolListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []bool {
-	result := make([]bool, length)
+func readBoolListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []bool) []bool {
+	result := reuseSlice(dst, length)
 	if !hasNull {
 		raw := buf.ReadBinary(length, err)
 		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), length), raw)
 		return result
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadBool(err)
@@ -416,13 +470,14 @@ func writeInt8ListPayload(buf *ByteBuffer, value []int8) {
 	}
 }
 
-func readInt8ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []int8 {
-	result := make([]int8, length)
+func readInt8ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []int8) []int8 {
+	result := reuseSlice(dst, length)
 	if !hasNull {
 		raw := buf.ReadBinary(length, err)
 		copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), length), raw)
 		return result
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadInt8(err)
@@ -437,13 +492,14 @@ func writeUint8ListPayload(buf *ByteBuffer, value []byte) {
 	}
 }
 
-func readUint8ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []byte {
-	result := make([]byte, length)
+func readUint8ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []byte) []byte {
+	result := reuseSlice(dst, length)
 	if !hasNull {
 		raw := buf.ReadBinary(length, err)
 		copy(result, raw)
 		return result
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadUint8(err)
@@ -453,32 +509,15 @@ func readUint8ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool)
 }
 
 func writeInt16ListPayload(buf *ByteBuffer, value []int16) {
-	size := len(value) * 2
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt16(v)
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readInt16ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []int16 {
-	result := make([]int16, length)
+func readInt16ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []int16) []int16 {
+	result := reuseSlice(dst, length)
 	if !hasNull {
-		size := length * 2
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = buf.ReadInt16(err)
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*2, result)
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadInt16(err)
@@ -488,32 +527,15 @@ func readInt16ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool)
 }
 
 func writeUint16ListPayload(buf *ByteBuffer, value []uint16) {
-	size := len(value) * 2
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt16(int16(v))
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readUint16ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []uint16 {
-	result := make([]uint16, length)
+func readUint16ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []uint16) []uint16 {
+	result := reuseSlice(dst, length)
 	if !hasNull {
-		size := length * 2
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = uint16(buf.ReadInt16(err))
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*2, result)
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = uint16(buf.ReadInt16(err))
@@ -533,34 +555,17 @@ func writeInt32ListPayload(buf *ByteBuffer, value []int32, typeID TypeId) {
 }
 
 func writeInt32FixedListPayload(buf *ByteBuffer, value []int32) {
-	size := len(value) * 4
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt32(v)
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readInt32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []int32 {
-	result := make([]int32, length)
+func readInt32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []int32) []int32 {
+	result := reuseSlice(dst, length)
 	if !hasNull && typeID == INT32 {
-		size := length * 4
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = buf.ReadInt32(err)
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*4, result)
 	}
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		if typeID == INT32 {
@@ -583,34 +588,17 @@ func writeUint32ListPayload(buf *ByteBuffer, value []uint32, typeID TypeId) {
 }
 
 func writeUint32FixedListPayload(buf *ByteBuffer, value []uint32) {
-	size := len(value) * 4
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt32(int32(v))
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readUint32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []uint32 {
-	result := make([]uint32, length)
+func readUint32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []uint32) []uint32 {
+	result := reuseSlice(dst, length)
 	if !hasNull && typeID == UINT32 {
-		size := length * 4
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = uint32(buf.ReadInt32(err))
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*4, result)
 	}
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		if typeID == UINT32 {
@@ -638,34 +626,17 @@ func writeInt64ListPayload(buf *ByteBuffer, value []int64, typeID TypeId) {
 }
 
 func writeInt64FixedListPayload(buf *ByteBuffer, value []int64) {
-	size := len(value) * 8
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt64(v)
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readInt64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []int64 {
-	result := make([]int64, length)
+func readInt64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []int64) []int64 {
+	result := reuseSlice(dst, length)
 	if !hasNull && typeID == INT64 {
-		size := length * 8
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = buf.ReadInt64(err)
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*8, result)
 	}
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		switch typeID {
@@ -696,34 +667,17 @@ func writeUint64ListPayload(buf *ByteBuffer, value []uint64, typeID TypeId) {
 }
 
 func writeUint64FixedListPayload(buf *ByteBuffer, value []uint64) {
-	size := len(value) * 8
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteInt64(int64(v))
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readUint64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []uint64 {
-	result := make([]uint64, length)
+func readUint64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []uint64) []uint64 {
+	result := reuseSlice(dst, length)
 	if !hasNull && typeID == UINT64 {
-		size := length * 8
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = uint64(buf.ReadInt64(err))
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*8, result)
 	}
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		switch typeID {
@@ -753,15 +707,20 @@ func writeIntListPayload(buf *ByteBuffer, value []int, typeID TypeId) {
 	}
 }
 
-func readIntListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []int {
-	result := make([]int, length)
+func readIntListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []int) []int {
 	if reflect.TypeOf(int(0)).Size() == 8 {
-		values := readInt64ListPayload(buf, err, length, hasNull, typeID)
-		copy(unsafe.Slice((*int64)(unsafe.Pointer(&result[0])), length), values)
-		return result
+		// int and int64 share a layout here, so decode straight into the result
+		var dst64 []int64
+		if dst != nil {
+			dst64 = unsafe.Slice((*int64)(unsafe.Pointer(unsafe.SliceData(dst))), cap(dst))[:len(dst)]
+		}
+		values := readInt64ListPayload(buf, err, length, hasNull, typeID, dst64)
+		return unsafe.Slice((*int)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
 	}
+	result := reuseSlice(dst, length)
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		if typeID == INT32 {
@@ -788,15 +747,20 @@ func writeUintListPayload(buf *ByteBuffer, value []uint, typeID TypeId) {
 	}
 }
 
-func readUintListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId) []uint {
-	result := make([]uint, length)
+func readUintListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, typeID TypeId, dst []uint) []uint {
 	if reflect.TypeOf(uint(0)).Size() == 8 {
-		values := readUint64ListPayload(buf, err, length, hasNull, typeID)
-		copy(unsafe.Slice((*uint64)(unsafe.Pointer(&result[0])), length), values)
-		return result
+		// uint and uint64 share a layout here, so decode straight into the result
+		var dst64 []uint64
+		if dst != nil {
+			dst64 = unsafe.Slice((*uint64)(unsafe.Pointer(unsafe.SliceData(dst))), cap(dst))[:len(dst)]
+		}
+		values := readUint64ListPayload(buf, err, length, hasNull, typeID, dst64)
+		return unsafe.Slice((*uint)(unsafe.Pointer(unsafe.SliceData(values))), len(values))
 	}
+	result := reuseSlice(dst, length)
 	for i := 0; i < length; i++ {
 		if hasNull && buf.ReadInt8(err) == NullFlag {
+			result[i] = 0
 			continue
 		}
 		if typeID == UINT32 {
@@ -809,32 +773,15 @@ func readUintListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool,
 }
 
 func writeFloat32ListPayload(buf *ByteBuffer, value []float32) {
-	size := len(value) * 4
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteFloat32(v)
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readFloat32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []float32 {
-	result := make([]float32, length)
+func readFloat32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []float32) []float32 {
+	result := reuseSlice(dst, length)
 	if !hasNull {
-		size := length * 4
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = buf.ReadFloat32(err)
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*4, result)
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadFloat32(err)
@@ -844,32 +791,15 @@ func readFloat32ListPayload(buf *ByteBuffer, err *Error, length int, hasNull boo
 }
 
 func writeFloat64ListPayload(buf *ByteBuffer, value []float64) {
-	size := len(value) * 8
-	if len(value) > 0 {
-		if isLittleEndian {
-			buf.WriteBinary(unsafe.Slice((*byte)(unsafe.Pointer(&value[0])), size))
-		} else {
-			for _, v := range value {
-				buf.WriteFloat64(v)
-			}
-		}
-	}
+	writeLittleEndian(buf, value)
 }
 
-func readFloat64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool) []float64 {
-	result := make([]float64, length)
+func readFloat64ListPayload(buf *ByteBuffer, err *Error, length int, hasNull bool, dst []float64) []float64 {
+	result := reuseSlice(dst, length)
 	if !hasNull {
-		size := length * 8
-		if isLittleEndian {
-			raw := buf.ReadBinary(size, err)
-			copy(unsafe.Slice((*byte)(unsafe.Pointer(&result[0])), size), raw)
-		} else {
-			for i := 0; i < length; i++ {
-				result[i] = buf.ReadFloat64(err)
-			}
-		}
-		return result
+		return readLittleEndian(buf, err, length*8, result)
 	}
+	clear(result)
 	for i := 0; i < length; i++ {
 		if buf.ReadInt8(err) != NullFlag {
 			result[i] = buf.ReadFloat64(err)
@@ -15,10 +15,14 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
+	"bytes"
 	"math"
+	"reflect"
 	"testing"
 
 	"github.com/apache/fory/go/fory/bfloat16"
@@ -66,6 +70,52 @@ func TestFloat16Slice(t *testing.T) {
 		assert.NoError(t, err)
 		assert.Nil(t, result)
 	})
+
+	t.Run("float16_array_xlang", func(t *testing.T) {
+		xf := NewFory(WithXlang(true))
+		slice := []float16.Float16{
+			float16.Float16FromFloat32(0.25),
+			float16.Inf,
+			float16.NaN,
+			float16.NegZero,
+		}
+		data, err := xf.Serialize(slice)
+		assert.NoError(t, err)
+		// header, not-null ref flag, FLOAT16_ARRAY, byte length, then the packed
+		// little-endian halves
+		expected := []byte{XLangFlag, 0xff, FLOAT16_ARRAY, 8}
+		for _, v := range slice {
+			expected = append(expected, byte(v.Bits()), byte(v.Bits()>>8))
+		}
+		assert.Equal(t, expected, data)
+
+		var result []float16.Float16
+		assert.NoError(t, xf.Deserialize(data, &result))
+		assert.Len(t, result, len(slice))
+		for i := range slice {
+			assert.Equal(t, slice[i].Bits(), result[i].Bits())
+		}
+	})
+
+	t.Run("float16_array_field", func(t *testing.T) {
+		type Embedding struct {
+			ID     int64
+			Vector []float16.Float16 `fory:"type=array(element=float16)"`
+		}
+		xf := NewFory(WithXlang(true), WithObjectReuse(true))
+		assert.NoError(t, xf.RegisterStruct(Embedding{}, 1))
+		value := Embedding{ID: 1, Vector: []float16.Float16{
+			float16.Float16FromFloat32(0.5), float16.Float16FromFloat32(-1.5),
+		}}
+		data, err := xf.Marshal(&value)
+		assert.NoError(t, err)
+
+		target := Embedding{Vector: make([]float16.Float16, 0, 4)}
+		backing := &target.Vector[:1][0]
+		assert.NoError(t, xf.Unmarshal(data, &target))
+		assert.Equal(t, value, target)
+		assert.Same(t, backing, &target.Vector[0])
+	})
 }
 
 func TestBFloat16Slice(t *testing.T) {
@@ -477,3 +527,349 @@ func TestReadBoolSliceWrappedBuffer(t *testing.T) {
 	assert.False(t, err.HasError(), "Expected wrapped buffer reads to use the serialized payload")
 	assert.Equal(t, []bool{true, false}, result)
 }
+
+func TestZeroCopyBinary(t *testing.T) {
+	type blob struct {
+		Data  []byte
+		Items map[string][]byte
+	}
+	value := blob{Data: []byte("payload"), Items: map[string][]byte{"k": []byte("item")}}
+	scribble := func(data []byte) {
+		for i := range data {
+			data[i] = 0
+		}
+	}
+	decode := func(t *testing.T, data []byte, opts ...Option) blob {
+		f := New(opts...)
+		assert.NoError(t, f.RegisterStruct(blob{}, 300))
+		var decoded blob
+		assert.NoError(t, f.Unmarshal(data, &decoded))
+		return decoded
+	}
+
+	for _, xlang := range []bool{true, false} {
+		f := New(WithXlang(xlang))
+		assert.NoError(t, f.RegisterStruct(blob{}, 300))
+		encoded, err := f.Marshal(&value)
+		assert.NoError(t, err)
+
+		data := bytes.Clone(encoded)
+		copied := decode(t, data, WithXlang(xlang))
+		scribble(data)
+		assert.Equal(t, value, copied, "xlang=%v", xlang)
+
+		data = bytes.Clone(encoded)
+		viewed := decode(t, data, WithXlang(xlang), WithZeroCopyBinary(true))
+		assert.Equal(t, value, viewed, "xlang=%v", xlang)
+		assert.Equal(t, len(viewed.Data), cap(viewed.Data))
+		scribble(data)
+		assert.Equal(t, make([]byte, len("payload")), viewed.Data, "xlang=%v", xlang)
+		assert.Equal(t, make([]byte, len("item")), viewed.Items["k"], "xlang=%v", xlang)
+	}
+
+	data, err := New().Marshal([]byte("root"))
+	assert.NoError(t, err)
+	var copied, viewed []byte
+	assert.NoError(t, New().Unmarshal(data, &copied))
+	assert.NoError(t, New(WithZeroCopyBinary(true)).Unmarshal(data, &viewed))
+	scribble(data)
+	assert.Equal(t, []byte("root"), copied)
+	assert.Equal(t, make([]byte, 4), viewed)
+}
+
+func TestPrimitiveArraysOutOfBand(t *testing.T) {
+	type arrays struct {
+		Floats []float64 `fory:"type=array(element=float64)"`
+		Ints   []int64   `fory:"type=array(element=int64)"`
+		Bytes  []byte    `fory:"type=array(element=uint8)"`
+		Small  []int32   `fory:"type=array(element=int32)"`
+		Flags  []bool    `fory:"type=array(element=bool)"`
+		Listed []int64
+	}
+	value := arrays{
+		Floats: make([]float64, 64),
+		Ints:   make([]int64, 64),
+		Bytes:  bytes.Repeat([]byte{7}, 256),
+		Small:  []int32{1, 2, 3},
+		Flags:  []bool{true, false},
+		Listed: make([]int64, 64),
+	}
+	for i := range value.Floats {
+		value.Floats[i] = float64(i) / 3
+		value.Ints[i] = int64(i) << 40
+	}
+	const threshold = 64
+	for _, xlang := range []bool{false, true} {
+		f := New(WithXlang(xlang))
+		assert.NoError(t, f.RegisterStruct(arrays{}, 301))
+
+		var objects []BufferObject
+		buf := NewByteBuffer(nil)
+		assert.NoError(t, f.SerializeWithCallback(buf, &value, func(o BufferObject) bool {
+			if o.TotalBytes() < threshold {
+				return true
+			}
+			objects = append(objects, o)
+			return false
+		}))
+		inBand, err := f.Marshal(&value)
+		assert.NoError(t, err)
+		assert.Less(t, buf.WriterIndex(), len(inBand)-1200)
+
+		buffers := make([]*ByteBuffer, len(objects))
+		for i, o := range objects {
+			buffers[i] = o.ToBuffer()
+		}
+		var decoded arrays
+		assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, buffers))
+		assert.Equal(t, value, decoded, "xlang=%v", xlang)
+		// Listed uses the list encoding, which stays in band.
+		assert.Len(t, objects, 3)
+		viewer := New(WithXlang(xlang), WithZeroCopyBinary(true))
+		assert.NoError(t, viewer.RegisterStruct(arrays{}, 301))
+		var viewed arrays
+		assert.NoError(t, viewer.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &viewed, buffers))
+		assert.Same(t, &value.Floats[0], &viewed.Floats[0])
+		assert.Same(t, &value.Ints[0], &viewed.Ints[0])
+
+		err = f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, nil)
+		assert.Error(t, err)
+	}
+
+	f := New()
+	var objects []BufferObject
+	buf := NewByteBuffer(nil)
+	assert.NoError(t, f.SerializeWithCallback(buf, value.Floats, func(o BufferObject) bool {
+		objects = append(objects, o)
+		return false
+	}))
+	assert.Len(t, objects, 1)
+	assert.Equal(t, len(value.Floats)*8, objects[0].TotalBytes())
+	var floats []float64
+	assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &floats, []*ByteBuffer{objects[0].ToBuffer()}))
+	assert.Equal(t, value.Floats, floats)
+}
+
+type outOfBandFields struct {
+	Floats []float64 `fory:"type=array(element=float64)"`
+	Ints   []int64   `fory:"type=array(element=int64),nullable"`
+	Bytes  []byte    `fory:"type=bytes"`
+	Flags  []bool    `fory:"type=array(element=bool)"`
+	Halves []uint16  `fory:"type=array(element=uint16)"`
+	Listed []int32
+}
+
+func TestOutOfBandStructFields(t *testing.T) {
+	floats := make([]float64, minConcurrentCopy/8)
+	for i := range floats {
+		floats[i] = float64(i) / 5
+	}
+	values := []outOfBandFields{
+		{Floats: floats, Ints: []int64{3, 4}, Bytes: []byte{5}, Flags: []bool{true}, Halves: []uint16{6}, Listed: []int32{7}},
+		{Floats: []float64{1.5}, Bytes: []byte{8}, Flags: []bool{false}, Halves: []uint16{1}, Listed: []int32{2}},
+	}
+	modes := []struct {
+		name string
+		opts []Option
+	}{
+		{"SchemaConsistent", []Option{WithCompatible(false)}},
+		{"Compatible", []Option{WithCompatible(true)}},
+		{"TrackRef", []Option{WithTrackRef(true)}},
+		{"ObjectReuse", []Option{WithObjectReuse(true)}},
+		{"OmitZeroFields", []Option{WithCompatible(true), WithOmitZeroFields(true)}},
+		{"Workers", []Option{WithOutOfBandWorkers(2)}},
+	}
+	for _, mode := range modes {
+		for _, xlang := range []bool{true, false} {
+			f := New(append([]Option{WithXlang(xlang)}, mode.opts...)...)
+			assert.NoError(t, f.RegisterStruct(outOfBandFields{}, 302))
+			for _, value := range values {
+				var objects []BufferObject
+				buf := NewByteBuffer(nil)
+				assert.NoError(t, f.SerializeWithCallback(buf, &value, func(o BufferObject) bool {
+					objects = append(objects, o)
+					return false
+				}))
+				buffers := make([]*ByteBuffer, len(objects))
+				for i, o := range objects {
+					buffers[i] = o.ToBuffer()
+				}
+				// Listed uses the list encoding, and omitted zero fields write nothing.
+				if value.Ints != nil {
+					assert.Len(t, objects, 5, "%s xlang=%v", mode.name, xlang)
+				}
+				var decoded outOfBandFields
+				assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, buffers))
+				assert.Equal(t, value, decoded, "%s xlang=%v", mode.name, xlang)
+			}
+		}
+	}
+}
+
+func TestDeserializeWithBufferProvider(t *testing.T) {
+	f := New(WithXlang(true))
+	value := []any{[]int32{1, 2, 3}, "between", []float64{1.5, 2.5}}
+
+	var objects []BufferObject
+	buf := NewByteBuffer(nil)
+	assert.NoError(t, f.SerializeWithCallback(buf, value, func(o BufferObject) bool {
+		objects = append(objects, o)
+		return false
+	}))
+	assert.Len(t, objects, 2)
+
+	var requested []int
+	var decoded []any
+	assert.NoError(t, f.DeserializeWithBufferProvider(NewByteBuffer(buf.Bytes()), &decoded, func(index int) *ByteBuffer {
+		requested = append(requested, index)
+		return objects[index].ToBuffer()
+	}))
+	assert.Equal(t, []int{0, 1}, requested)
+	assert.Equal(t, value, decoded)
+
+	f = New(WithXlang(true), WithPanicRecovery(false))
+	err := f.DeserializeWithBufferProvider(NewByteBuffer(buf.Bytes()), &decoded, func(index int) *ByteBuffer {
+		return nil
+	})
+	assert.Error(t, err)
+	assert.Contains(t, err.Error(), "index 0")
+}
+
+func TestOutOfBandThreshold(t *testing.T) {
+	type blobs struct {
+		Small []byte `fory:"type=bytes"`
+		Large []byte `fory:"type=bytes"`
+		Items []any
+	}
+	value := blobs{
+		Small: []byte("header"),
+		Large: bytes.Repeat([]byte{1}, 4096),
+		Items: []any{[]byte("tiny"), bytes.Repeat([]byte{2}, 1024)},
+	}
+	for _, xlang := range []bool{false, true} {
+		f := New(WithXlang(xlang), WithOutOfBandThreshold(1024))
+		assert.NoError(t, f.RegisterStruct(blobs{}, 302))
+
+		var objects []BufferObject
+		buf := NewByteBuffer(nil)
+		assert.NoError(t, f.SerializeWithCallback(buf, &value, func(o BufferObject) bool {
+			objects = append(objects, o)
+			return false
+		}))
+		assert.Len(t, objects, 2)
+		assert.Less(t, buf.WriterIndex(), 100)
+
+		buffers := make([]*ByteBuffer, len(objects))
+		for i, o := range objects {
+			buffers[i] = o.ToBuffer()
+		}
+		var decoded blobs
+		assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, buffers))
+		assert.Equal(t, value, decoded, "xlang=%v", xlang)
+	}
+}
+
+func TestOutOfBandWorkers(t *testing.T) {
+	value := make([]any, 8)
+	for i := range value {
+		floats := make([]float64, minConcurrentCopy/8+i)
+		for j := range floats {
+			floats[j] = float64(i*j) / 7
+		}
+		value[i] = floats
+	}
+	value = append(value, []int32{1, 2, 3}, "tail")
+	f := New(WithXlang(true), WithOutOfBandWorkers(3))
+
+	var objects []BufferObject
+	buf := NewByteBuffer(nil)
+	assert.NoError(t, f.SerializeWithCallback(buf, value, func(o BufferObject) bool {
+		objects = append(objects, o)
+		return false
+	}))
+	assert.Len(t, objects, 9)
+	buffers := make([]*ByteBuffer, len(objects))
+	for i, o := range objects {
+		buffers[i] = o.ToBuffer()
+	}
+	for i := 0; i < 2; i++ {
+		var decoded []any
+		assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, buffers))
+		assert.Equal(t, value, decoded)
+		decoded = nil
+		assert.NoError(t, f.DeserializeWithBufferProvider(NewByteBuffer(buf.Bytes()), &decoded, func(index int) *ByteBuffer {
+			return buffers[index]
+		}))
+		assert.Equal(t, value, decoded)
+	}
+}
+
+type oobHooked struct {
+	Data any
+}
+
+// oobSummed is read by oobSumSerializer, which sums Data while reading it.
+type oobSummed struct {
+	Data []float64
+	Sum  float64
+}
+
+type oobSumSerializer struct{}
+
+func (oobSumSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
+	ctx.WriteValue(value.FieldByName("Data"), RefModeNone, true)
+}
+
+func (oobSumSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	var data []float64
+	ctx.ReadValue(reflect.ValueOf(&data).Elem(), RefModeNone, true)
+	summed := oobSummed{Data: data}
+	for _, v := range data {
+		summed.Sum += v
+	}
+	value.Set(reflect.ValueOf(summed))
+}
+
+func TestOutOfBandWorkersUserCode(t *testing.T) {
+	floats := make([]float64, 512<<10/8)
+	var want float64
+	for i := range floats {
+		floats[i] = float64(i%97) + 1
+		want += floats[i]
+	}
+	var sums []float64
+	f := New(WithXlang(true), WithOutOfBandWorkers(4))
+	assert.NoError(t, f.RegisterStruct(oobHooked{}, 350))
+	assert.NoError(t, f.RegisterExtension(oobSummed{}, 351, oobSumSerializer{}))
+	assert.NoError(t, f.RegisterHooks(oobHooked{}, TypeHooks{AfterDeserialize: func(v any) {
+		var sum float64
+		for _, x := range v.(*oobHooked).Data.([]float64) {
+			sum += x
+		}
+		sums = append(sums, sum)
+	}}))
+
+	for _, value := range []any{
+		[]any{&oobHooked{Data: floats}, &oobHooked{Data: floats}},
+		oobSummed{Data: floats},
+	} {
+		var objects []BufferObject
+		buf := NewByteBuffer(nil)
+		assert.NoError(t, f.SerializeWithCallback(buf, value, func(o BufferObject) bool {
+			objects = append(objects, o)
+			return false
+		}))
+		buffers := make([]*ByteBuffer, len(objects))
+		for i, o := range objects {
+			buffers[i] = o.ToBuffer()
+		}
+		sums = nil
+		var decoded any
+		assert.NoError(t, f.DeserializeWithCallbackBuffers(NewByteBuffer(buf.Bytes()), &decoded, buffers))
+		if summed, ok := decoded.(oobSummed); ok {
+			assert.Equal(t, want, summed.Sum)
+		} else {
+			assert.Equal(t, []float64{want, want}, sums)
+		}
+	}
+}
@@ -0,0 +1,78 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type nestedSliceHolder struct {
+	Ints   [][]int32
+	Bytes  [][]byte
+	Names  [][]string
+	Cubes  [][][]int64
+	Values [][]any
+}
+
+func TestNestedSliceTopLevel(t *testing.T) {
+	values := []any{
+		[][]int32{{1, 2}, {}, {3}},
+		[][]byte{{1}, {2, 3}},
+		[][]string{{"a", "b"}, {}},
+		[][][]int64{{{1}, {2, 3}}, {}},
+		[][]any{{int32(1), "x"}},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, trackRef := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithRefTracking(trackRef))
+			for _, value := range values {
+				data, err := f.Marshal(value)
+				require.NoError(t, err, "%T xlang=%v", value, xlang)
+				result := reflect.New(reflect.TypeOf(value))
+				require.NoError(t, f.Unmarshal(data, result.Interface()), "%T xlang=%v", value, xlang)
+				require.Equal(t, value, result.Elem().Interface(), "xlang=%v trackRef=%v", xlang, trackRef)
+			}
+		}
+	}
+}
+
+func TestNestedSliceInStruct(t *testing.T) {
+	value := nestedSliceHolder{
+		Ints:   [][]int32{{1, 2}, {3}},
+		Bytes:  [][]byte{{1}, {2, 3}},
+		Names:  [][]string{{"a"}, {"b", "c"}},
+		Cubes:  [][][]int64{{{1, 2}}, {{3}}},
+		Values: [][]any{{"x", int64(1)}},
+	}
+	for _, xlang := range []bool{true, false} {
+		for _, compatible := range []bool{true, false} {
+			f := NewFory(WithXlang(xlang), WithCompatible(compatible))
+			require.NoError(t, f.RegisterStruct(nestedSliceHolder{}, 1))
+			data, err := f.Marshal(&value)
+			require.NoError(t, err, "xlang=%v compatible=%v", xlang, compatible)
+			var result nestedSliceHolder
+			require.NoError(t, f.Unmarshal(data, &result), "xlang=%v compatible=%v", xlang, compatible)
+			require.Equal(t, value, result, "xlang=%v compatible=%v", xlang, compatible)
+		}
+	}
+}
@@ -18,8 +18,9 @@
 package fory
 
 import (
+	"encoding/binary"
 	"io"
-	"reflect"
+	"math"
 )
 
 // InputStream supports robust sequential deserialization from a stream.
@@ -93,7 +94,11 @@ func (is *InputStream) Shrink() {
 
 // DeserializeFromStream reads the next object from the stream into the provided value.
 // It preserves the stream buffer while clearing root-scoped read metadata between calls.
-func (f *Fory) DeserializeFromStream(is *InputStream, v any) error {
+// It returns io.EOF when the stream ends before the next object starts.
+func (f *Fory) DeserializeFromStream(is *InputStream, v any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
 	origBuffer := f.readCtx.buffer
 	f.readCtx.buffer = is.buffer
 	defer func() {
@@ -101,25 +106,30 @@ func (f *Fory) DeserializeFromStream(is *InputStream, v any) error {
 		f.resetReadState()
 	}()
 
-	readHeader(f.readCtx)
-	if f.readCtx.HasError() {
-		return f.readCtx.TakeError()
+	var fillErr Error
+	if !is.buffer.fill(1, &fillErr) {
+		if fillErr.Kind() == ErrKindBufferOutOfBound && is.buffer.remaining() == 0 {
+			return io.EOF
+		}
+		return fillErr
 	}
 
-	target := reflect.ValueOf(v).Elem()
-	f.readCtx.ReadValue(target, RefModeTracking, true)
+	readHeader(f.readCtx)
 	if f.readCtx.HasError() {
 		return f.readCtx.TakeError()
 	}
 
-	return nil
+	return f.readRoot(v)
 }
 
 // DeserializeFromReader deserializes a single object from a stream.
 // It is strictly stateless: the buffer and all read state are always reset before
 // each call, discarding any prefetched data and type metadata.
 // For sequential multi-object reads on the same stream, use NewInputStream instead.
-func (f *Fory) DeserializeFromReader(r io.Reader, v any) error {
+func (f *Fory) DeserializeFromReader(r io.Reader, v any) (err error) {
+	if f.config.RecoverPanics {
+		defer f.recoverRead(&err, f.readCtx.buffer)
+	}
 	defer f.resetReadState()
 	// Always reset to enforce stateless semantics.
 	f.readCtx.buffer.ResetWithReader(r, 0)
@@ -129,11 +139,76 @@ func (f *Fory) DeserializeFromReader(r io.Reader, v any) error {
 		return f.readCtx.TakeError()
 	}
 
-	target := reflect.ValueOf(v).Elem()
-	f.readCtx.ReadValue(target, RefModeTracking, true)
-	if f.readCtx.HasError() {
-		return f.readCtx.TakeError()
+	return f.readRoot(v)
+}
+
+// WriteFramed serializes v and writes it to w as one frame: the payload length
+// as an unsigned varint followed by the payload. Frames written back to back
+// to a file or socket are read one at a time with ReadFramed.
+// The frame is passed to w in a single Write call.
+func (f *Fory) WriteFramed(w io.Writer, v any) error {
+	data, err := f.Serialize(v)
+	if err != nil {
+		return err
 	}
+	frame := binary.AppendUvarint(f.frameBuffer[:0], uint64(len(data)))
+	frame = append(frame, data...)
+	f.frameBuffer = frame[:0]
+	_, err = w.Write(frame)
+	return err
+}
+
+// ReadFramed reads the next frame written by WriteFramed from r and
+// deserializes its payload into v. It reads exactly one frame, so the next
+// call starts at the following one. It returns io.EOF when r ends before the
+// next frame starts and io.ErrUnexpectedEOF when it ends inside a frame.
+// Frames longer than the configured max binary size are rejected before their
+// payload is read.
+//
+// The length prefix is read a byte at a time unless r implements
+// io.ByteReader, so wrap unbuffered readers such as net.Conn in a bufio.Reader.
+func (f *Fory) ReadFramed(r io.Reader, v any) error {
+	br, ok := r.(io.ByteReader)
+	if !ok {
+		br = &singleByteReader{r: r}
+	}
+	length, err := binary.ReadUvarint(br)
+	if err != nil {
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			return err
+		}
+		return DeserializationErrorf("invalid frame length: %v", err)
+	}
+	if length > uint64(f.config.MaxBinarySize) {
+		return MaxBinarySizeExceededError(int(min(length, math.MaxInt)), f.config.MaxBinarySize)
+	}
+	var payload []byte
+	if f.config.ZeroCopyBinary {
+		// Decoded []byte values view the payload, so it cannot be reused.
+		payload = make([]byte, length)
+	} else {
+		if uint64(cap(f.frameBuffer)) < length {
+			f.frameBuffer = make([]byte, length)
+		}
+		payload = f.frameBuffer[:length]
+	}
+	if _, err := io.ReadFull(r, payload); err != nil {
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		return err
+	}
+	return f.Deserialize(payload, v)
+}
+
+// singleByteReader adapts an io.Reader to io.ByteReader without reading past
+// the byte it returns.
+type singleByteReader struct {
+	r   io.Reader
+	buf [1]byte
+}
 
-	return nil
+func (s *singleByteReader) ReadByte() (byte, error) {
+	_, err := io.ReadFull(s.r, s.buf[:])
+	return s.buf[0], err
 }
@@ -15,12 +15,17 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
 	"bytes"
+	"encoding/binary"
+	"errors"
 	"io"
 	"testing"
+	"testing/iotest"
 )
 
 type StreamTestStruct struct {
@@ -211,6 +216,10 @@ func TestInputStreamSequential(t *testing.T) {
 	if out3.ID != msg3.ID || out3.Name != msg3.Name || !bytes.Equal(out3.Data, msg3.Data) {
 		t.Errorf("Msg 3 mismatch. Got: %+v, Want: %+v", out3, msg3)
 	}
+
+	if err := fDec.DeserializeFromStream(sr, &out3); err != io.EOF {
+		t.Fatalf("Expected io.EOF at end of stream, got %v", err)
+	}
 }
 
 func TestInputStreamShrink(t *testing.T) {
@@ -259,3 +268,62 @@ func TestInputStreamShrink(t *testing.T) {
 		}
 	}
 }
+
+func TestFramedMessages(t *testing.T) {
+	f := New(WithXlang(false), WithCompatible(false))
+	f.RegisterStruct(&StreamTestStruct{}, 100)
+
+	values := []*StreamTestStruct{
+		{ID: 1, Name: "first", Data: []byte{1}},
+		{ID: 2, Name: "second", Data: bytes.Repeat([]byte{2}, 300)},
+		{ID: 3, Name: "third"},
+	}
+	var stream bytes.Buffer
+	for _, v := range values {
+		if err := f.WriteFramed(&stream, v); err != nil {
+			t.Fatalf("WriteFramed failed: %v", err)
+		}
+	}
+	data := stream.Bytes()
+
+	readers := map[string]io.Reader{
+		"ByteReader": bytes.NewReader(data),
+		"OneByte":    iotest.OneByteReader(bytes.NewReader(data)),
+	}
+	for name, r := range readers {
+		t.Run(name, func(t *testing.T) {
+			for i, want := range values {
+				var got StreamTestStruct
+				if err := f.ReadFramed(r, &got); err != nil {
+					t.Fatalf("ReadFramed #%d failed: %v", i, err)
+				}
+				if got.ID != want.ID || got.Name != want.Name || !bytes.Equal(got.Data, want.Data) {
+					t.Fatalf("frame #%d: got %+v, want %+v", i, got, *want)
+				}
+			}
+			var extra StreamTestStruct
+			if err := f.ReadFramed(r, &extra); err != io.EOF {
+				t.Fatalf("expected io.EOF after the last frame, got %v", err)
+			}
+		})
+	}
+
+	var single bytes.Buffer
+	if err := f.WriteFramed(&single, values[1]); err != nil {
+		t.Fatalf("WriteFramed failed: %v", err)
+	}
+	frame := single.Bytes()
+	var decoded StreamTestStruct
+	for _, n := range []int{1, len(frame) - 1} {
+		err := f.ReadFramed(bytes.NewReader(frame[:n]), &decoded)
+		if !errors.Is(err, io.ErrUnexpectedEOF) {
+			t.Fatalf("frame truncated to %d bytes: expected io.ErrUnexpectedEOF, got %v", n, err)
+		}
+	}
+
+	huge := binary.AppendUvarint(nil, 1<<40)
+	err := f.ReadFramed(bytes.NewReader(huge), &decoded)
+	if e, ok := err.(Error); !ok || e.Kind() != ErrKindMaxBinarySizeExceeded {
+		t.Fatalf("expected ErrKindMaxBinarySizeExceeded, got %v", err)
+	}
+}
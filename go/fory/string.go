@@ -19,8 +19,11 @@ package fory
 
 import (
 	"fmt"
+	"hash/maphash"
+	"math/bits"
 	"reflect"
 	"unicode/utf16"
+	"unicode/utf8"
 )
 
 // Encoding type constants
@@ -48,20 +51,64 @@ func writeString(buf *ByteBuffer, value string) {
 	}
 }
 
+// writeCompactString writes value in whichever of Latin-1, UTF-16 and UTF-8 is
+// shortest, as Java does for its compact strings. Ties and invalid UTF-8 keep
+// the UTF-8 encoding of writeString, so ASCII output is unchanged.
+func writeCompactString(buf *ByteBuffer, value string) {
+	if !utf8.ValidString(value) {
+		writeString(buf, value)
+		return
+	}
+	latin1 := true
+	units := 0 // UTF-16 code units, also the Latin-1 length when latin1 holds
+	for _, r := range value {
+		if r > 0xFF {
+			latin1 = false
+		}
+		units += utf16.RuneLen(r)
+	}
+	switch {
+	case latin1 && units < len(value):
+		buf.Reserve(5 + units)
+		buf.WriteVaruint36Small(uint64(units)<<2 | encodingLatin1)
+		for _, r := range value {
+			buf.data[buf.writerIndex] = byte(r)
+			buf.writerIndex++
+		}
+	case !latin1 && 2*units < len(value):
+		buf.Reserve(5 + 2*units)
+		buf.WriteVaruint36Small(uint64(2*units)<<2 | encodingUTF16LE)
+		for _, r := range value {
+			if r >= 0x10000 {
+				r1, r2 := utf16.EncodeRune(r)
+				buf.WriteUint16(uint16(r1))
+				r = r2
+			}
+			buf.WriteUint16(uint16(r))
+		}
+	default:
+		writeString(buf, value)
+	}
+}
+
 // readString reads a string from buffer using xlang encoding
 func readString(buf *ByteBuffer, err *Error) string {
 	header := buf.ReadVaruint36Small(err)
 	size := header >> 2       // Extract byte count
 	encoding := header & 0b11 // Extract encoding type
+	return readStringData(buf, int(size), encoding, err)
+}
 
+// readStringData reads the size bytes following a string header
+func readStringData(buf *ByteBuffer, size int, encoding uint64, err *Error) string {
 	switch encoding {
 	case encodingLatin1:
-		return readLatin1(buf, int(size), err)
+		return readLatin1(buf, size, err)
 	case encodingUTF16LE:
 		// For UTF16LE, size is byte count, need to convert to char count
-		return readUTF16LE(buf, int(size), err)
+		return readUTF16LE(buf, size, err)
 	case encodingUTF8:
-		return readUTF8(buf, int(size), err)
+		return readUTF8(buf, size, err)
 	default:
 		err.SetError(fmt.Errorf("invalid string encoding: %d", encoding))
 		return ""
@@ -74,13 +121,27 @@ func readLatin1(buf *ByteBuffer, size int, err *Error) string {
 	if err.HasError() {
 		return ""
 	}
+	return decodeLatin1(data)
+}
+
+func decodeLatin1(data []byte) string {
 	// Latin1 bytes need to be converted to UTF-8
-	// Each Latin1 byte is a single Unicode code point (0-255)
-	runes := make([]rune, size)
-	for i, b := range data {
-		runes[i] = rune(b)
+	// Each Latin1 byte is a single Unicode code point (0-255), and ASCII bytes
+	// are already valid UTF-8
+	extra := 0
+	for _, b := range data {
+		if b >= 0x80 {
+			extra++
+		}
 	}
-	return string(runes)
+	if extra == 0 {
+		return string(data)
+	}
+	out := make([]byte, 0, len(data)+extra)
+	for _, b := range data {
+		out = utf8.AppendRune(out, rune(b))
+	}
+	return string(out)
 }
 
 func readUTF16LE(buf *ByteBuffer, byteCount int, err *Error) string {
@@ -111,6 +172,115 @@ func readUTF8(buf *ByteBuffer, size int, err *Error) string {
 	return string(data)
 }
 
+// maxInternedStringLen bounds the strings kept in a stringTable; longer strings
+// are rarely repeated and would make the table pin large allocations.
+const maxInternedStringLen = 64
+
+// stringTable is a bounded, direct-mapped intern table for decoded strings.
+// Each slot holds the last string hashed to it, so a repeated string reuses
+// the earlier allocation while memory stays fixed at len(slots) entries.
+type stringTable struct {
+	seed  maphash.Seed
+	slots []string
+	mask  uint64
+}
+
+func newStringTable(size int) *stringTable {
+	n := 1
+	if size > 1 {
+		n = 1 << bits.Len(uint(size-1))
+	}
+	return &stringTable{seed: maphash.MakeSeed(), slots: make([]string, n), mask: uint64(n - 1)}
+}
+
+func (t *stringTable) intern(data []byte) string {
+	slot := &t.slots[maphash.Bytes(t.seed, data)&t.mask]
+	if *slot == string(data) {
+		return *slot
+	}
+	s := string(data)
+	*slot = s
+	return s
+}
+
+// read decodes a string like readString, interning short UTF-8 strings and
+// ASCII Latin-1 strings, whose bytes are the same in both encodings.
+func (t *stringTable) read(buf *ByteBuffer, err *Error) string {
+	header := buf.ReadVaruint36Small(err)
+	size := int(header >> 2)
+	encoding := header & 0b11
+	if size > maxInternedStringLen || (encoding != encodingUTF8 && encoding != encodingLatin1) {
+		return readStringData(buf, size, encoding, err)
+	}
+	data := buf.ReadBinary(size, err)
+	if encoding == encodingLatin1 && !isASCII(data) {
+		return decodeLatin1(data)
+	}
+	return t.intern(data)
+}
+
+func isASCII(data []byte) bool {
+	for _, b := range data {
+		if b >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// stringDictFlag is bit 6 of the root header. When set, every non-empty
+// string is added to a per-payload dictionary the first time it is written,
+// and later occurrences are written as encodingDictRef headers whose size bits
+// hold its dictionary index.
+const (
+	stringDictFlag  = 1 << 6
+	encodingDictRef = 3
+)
+
+// enableStringDict makes c write repeated strings as dictionary references.
+func (c *WriteContext) enableStringDict() {
+	c.stringDict = make(map[string]uint32)
+	c.dictWriter = c.writeDictString
+}
+
+// writeDictString writes value as a reference to its first occurrence in the
+// payload, or in full when it has not been written yet.
+func (c *WriteContext) writeDictString(buf *ByteBuffer, value string) {
+	if index, ok := c.stringDict[value]; ok {
+		buf.WriteVaruint36Small(uint64(index)<<2 | encodingDictRef)
+		return
+	}
+	if value != "" {
+		c.stringDict[value] = uint32(len(c.stringDict))
+	}
+	if c.compactStrings {
+		writeCompactString(buf, value)
+		return
+	}
+	writeString(buf, value)
+}
+
+// readDictString reads a string from a payload written with stringDictFlag,
+// mirroring writeDictString.
+func (c *ReadContext) readDictString() string {
+	err := c.Err()
+	header := c.buffer.ReadVaruint36Small(err)
+	size := header >> 2
+	encoding := header & 0b11
+	if encoding == encodingDictRef {
+		if size >= uint64(len(c.dictStrings)) {
+			c.SetError(DeserializationErrorf("string dictionary index %d out of range [0, %d)", size, len(c.dictStrings)))
+			return ""
+		}
+		return c.dictStrings[size]
+	}
+	s := readStringData(c.buffer, int(size), encoding, err)
+	if s != "" && !c.HasError() {
+		c.dictStrings = append(c.dictStrings, s)
+	}
+	return s
+}
+
 // ============================================================================
 // String Serializers - implement unified Serializer interface
 // ============================================================================
@@ -121,7 +291,7 @@ type stringSerializer struct{}
 var globalStringSerializer = stringSerializer{}
 
 func (s stringSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
-	writeString(ctx.buffer, value.String())
+	ctx.WriteString(value.String())
 }
 
 func (s stringSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bool, hasGenerics bool, value reflect.Value) {
@@ -136,8 +306,7 @@ func (s stringSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bo
 }
 
 func (s stringSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	err := ctx.Err()
-	str := readString(ctx.buffer, err)
+	str := ctx.ReadString()
 	if ctx.HasError() {
 		return
 	}
@@ -186,7 +355,7 @@ func (s ptrToStringSerializer) Write(ctx *WriteContext, refMode RefMode, writeTy
 
 func (s ptrToStringSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	str := value.Interface().(*string)
-	writeString(ctx.buffer, *str)
+	ctx.WriteString(*str)
 }
 
 func (s ptrToStringSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
@@ -206,8 +375,7 @@ func (s ptrToStringSerializer) Read(ctx *ReadContext, refMode RefMode, readType
 }
 
 func (s ptrToStringSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
-	err := ctx.Err()
-	str := readString(ctx.buffer, err)
+	str := ctx.ReadString()
 	if ctx.HasError() {
 		return
 	}
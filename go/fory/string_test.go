@@ -15,10 +15,14 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
+	"strings"
 	"testing"
+	"unsafe"
 
 	"github.com/stretchr/testify/require"
 )
@@ -101,3 +105,190 @@ func TestReadLatin1_OOM_Bug(t *testing.T) {
 	require.True(t, err.HasError(), "Expected an error due to out of bounds buffer")
 	require.Equal(t, "", result, "Expected an empty string due to missing data")
 }
+
+func TestStringInterning(t *testing.T) {
+	long := strings.Repeat("x", maxInternedStringLen+1)
+	values := []string{"status", "status", long, long}
+	data, err := New().Marshal(values)
+	require.NoError(t, err)
+
+	f := New(WithStringInterning(16))
+	var decoded []string
+	require.NoError(t, f.Unmarshal(data, &decoded))
+	require.Equal(t, values, decoded)
+	require.Same(t, unsafe.StringData(decoded[0]), unsafe.StringData(decoded[1]))
+	require.NotSame(t, unsafe.StringData(decoded[2]), unsafe.StringData(decoded[3]))
+
+	// The table is kept across calls.
+	var again []string
+	require.NoError(t, f.Unmarshal(data, &again))
+	require.Same(t, unsafe.StringData(decoded[0]), unsafe.StringData(again[0]))
+
+	m := map[int32]string{1: "shared", 2: "shared"}
+	data, err = New().Marshal(m)
+	require.NoError(t, err)
+	var decodedMap map[int32]string
+	require.NoError(t, f.Unmarshal(data, &decodedMap))
+	require.Equal(t, m, decodedMap)
+	require.Same(t, unsafe.StringData(decodedMap[1]), unsafe.StringData(decodedMap[2]))
+}
+
+func TestStringTableLatin1(t *testing.T) {
+	table := newStringTable(4)
+	buf := NewByteBuffer(nil)
+	for _, s := range []string{"abc", "abc", "\xe9t\xe9"} {
+		buf.WriteVaruint36Small(uint64(len(s))<<2 | encodingLatin1)
+		buf.WriteBinary([]byte(s))
+	}
+	err := &Error{}
+	first := table.read(buf, err)
+	second := table.read(buf, err)
+	third := table.read(buf, err)
+	require.False(t, err.HasError())
+	require.Equal(t, "abc", first)
+	require.Same(t, unsafe.StringData(first), unsafe.StringData(second))
+	require.Equal(t, "été", third)
+}
+
+func TestWriteCompactString(t *testing.T) {
+	cases := []struct {
+		value    string
+		encoding uint64
+		size     int
+	}{
+		{"", encodingUTF8, 0},
+		{"hello", encodingUTF8, 5},
+		{"café crème", encodingLatin1, 10},
+		{"日本語のテキスト", encodingUTF16LE, 16},
+		{"a😀", encodingUTF8, 5},
+		{"日本😀", encodingUTF16LE, 8},
+		{"mostly ascii 日本", encodingUTF8, 19},
+		{"\xff\xfe", encodingUTF8, 2},
+	}
+	for _, c := range cases {
+		buf := NewByteBuffer(nil)
+		writeCompactString(buf, c.value)
+		header := buf.ReadVaruint36Small(&Error{})
+		require.Equal(t, c.encoding, header&0b11, c.value)
+		require.Equal(t, c.size, int(header>>2), c.value)
+
+		buf.SetReaderIndex(0)
+		err := &Error{}
+		require.Equal(t, c.value, readString(buf, err))
+		require.False(t, err.HasError())
+	}
+}
+
+func TestCompactStringsRoundTrip(t *testing.T) {
+	type record struct {
+		Name  string
+		Tags  []string
+		Attrs map[string]string
+	}
+	value := record{
+		Name:  "Zoë Müller",
+		Tags:  []string{"naïve", "東京", "plain"},
+		Attrs: map[string]string{"città": "北京市", "key": "value"},
+	}
+	compact := New(WithCompactStrings(true))
+	plain := New()
+	for _, f := range []*Fory{compact, plain} {
+		require.NoError(t, f.RegisterStruct(record{}, 200))
+	}
+	compactData, err := compact.Marshal(&value)
+	require.NoError(t, err)
+	plainData, err := plain.Marshal(&value)
+	require.NoError(t, err)
+	require.Less(t, len(compactData), len(plainData))
+
+	for _, f := range []*Fory{compact, plain} {
+		var decoded record
+		require.NoError(t, f.Unmarshal(compactData, &decoded))
+		require.Equal(t, value, decoded)
+	}
+}
+
+func TestDecodeLatin1(t *testing.T) {
+	require.Equal(t, "plain ascii", decodeLatin1([]byte("plain ascii")))
+	require.Equal(t, "Zoë ÿ", decodeLatin1([]byte{'Z', 'o', 0xeb, ' ', 0xff}))
+}
+
+func TestStringDictionary(t *testing.T) {
+	type record struct {
+		Status string
+		Tags   []string
+		Attrs  map[string]string
+	}
+	values := make([]record, 50)
+	for i := range values {
+		values[i] = record{
+			Status: "pending-review",
+			Tags:   []string{"priority", "", "customer-facing"},
+			Attrs:  map[string]string{"region": "eu-west-1"},
+		}
+	}
+	dict := New(WithStringDictionary(true), WithCompactStrings(true))
+	plain := New()
+	for _, f := range []*Fory{dict, plain} {
+		require.NoError(t, f.RegisterStruct(record{}, 200))
+	}
+	dictData, err := dict.Marshal(values)
+	require.NoError(t, err)
+	plainData, err := plain.Marshal(values)
+	require.NoError(t, err)
+	require.Less(t, len(dictData)*2, len(plainData))
+	perCall, err := plain.Marshal(values, WithDictionaryStrings())
+	require.NoError(t, err)
+	require.Equal(t, len(dictData), len(perCall))
+
+	header, err := ParseHeader(dictData)
+	require.NoError(t, err)
+	require.True(t, header.StringDictionary)
+
+	// Readers follow the header, and the dictionary starts over per payload.
+	for _, f := range []*Fory{dict, plain, plain} {
+		var decoded []record
+		require.NoError(t, f.Unmarshal(dictData, &decoded))
+		require.Equal(t, values, decoded)
+	}
+	again, err := dict.Marshal(values)
+	require.NoError(t, err)
+	require.Equal(t, dictData, again)
+
+	// Per-call use does not change later calls.
+	later, err := plain.Marshal(values)
+	require.NoError(t, err)
+	require.Equal(t, plainData, later)
+
+	buf := NewByteBuffer(nil)
+	buf.WriteVaruint36Small(5<<2 | encodingDictRef)
+	ctx := plain.readCtx
+	ctx.SetData(buf.GetByteSlice(0, buf.WriterIndex()))
+	ctx.stringDict = true
+	_ = ctx.ReadString()
+	require.True(t, ctx.HasError())
+	plain.resetReadState()
+}
+
+func TestStringDictionarySkippedFields(t *testing.T) {
+	type v1 struct {
+		Dropped string
+		Kept    string
+	}
+	type v2 struct {
+		Kept string
+	}
+	writer := New(WithCompatible(true), WithStringDictionary(true))
+	reader := New(WithCompatible(true))
+	require.NoError(t, writer.RegisterStructByName(v1{}, "record"))
+	require.NoError(t, reader.RegisterStructByName(v2{}, "record"))
+	data, err := writer.Marshal([]v1{{"a", "a"}, {"a", "b"}, {"b", "a"}})
+	require.NoError(t, err)
+
+	var decoded []v2
+	require.NoError(t, reader.Unmarshal(data, &decoded))
+	require.Equal(t, []v2{{"a"}, {"b"}, {"a"}}, decoded)
+	dump, err := Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, dump, `dropped: STRING = "b"`)
+}
@@ -43,12 +43,15 @@ type structSerializer struct {
 	// Mode flags (set at init)
 	isCompatibleMode bool // true when compatible=true
 	typeDefDiffers   bool // true when compatible=true AND remote TypeDef != local (requires ordered read)
+	omitZero         bool // values carry a field presence bitmap and only the non-zero fields
 
 	// Initialization state
 	initialized bool
 
 	// Cached addressable value for non-addressable writes.
 	tempValue *reflect.Value
+
+	hooks *structHooks // nil unless hooks are registered for the type
 }
 
 // newStructSerializerFromTypeDef creates a new structSerializer with the given parameters.
@@ -157,6 +160,7 @@ func (s *structSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 			return
 		}
 	}
+	ctx.countWork(1)
 
 	buf := ctx.Buffer()
 
@@ -168,6 +172,9 @@ func (s *structSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 		}
 		value = value.Elem()
 	}
+	if s.hooks != nil && s.hooks.before != nil {
+		value = s.hooks.beforeSerialize(value)
+	}
 
 	// In compatible mode with meta share, struct hash is not written
 	if !ctx.Compatible() {
@@ -197,6 +204,10 @@ func (s *structSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 		}
 	}
 	ptr := unsafe.Pointer(value.UnsafeAddr())
+	if s.omitZero {
+		s.writePresentFields(ctx, ptr, value)
+		return
+	}
 
 	// ==========================================================================
 	// Phase 1: Fixed-size primitives (bool, int8, int16, float32, float64)
@@ -414,6 +425,135 @@ func (s *structSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 	}
 }
 
+// writePresentFields writes a bitmap with one bit per field in wire order,
+// set for fields that do not hold their zero value, followed by those fields.
+func (s *structSerializer) writePresentFields(ctx *WriteContext, ptr unsafe.Pointer, value reflect.Value) {
+	buf := ctx.Buffer()
+	group := &s.fieldGroup
+	size := (group.FieldCount() + 7) / 8
+	buf.Reserve(size)
+	start := buf.WriterIndex()
+	presence := buf.GetData()[start : start+size]
+	clear(presence)
+	i := markPresentFields(presence, 0, group.FixedFields, value)
+	i = markPresentFields(presence, i, group.VarintFields, value)
+	markPresentFields(presence, i, group.RemainingFields, value)
+	buf.SetWriterIndex(start + size)
+
+	i = 0
+	for j := range group.FixedFields {
+		if fieldPresent(presence, i) {
+			writePrimitiveField(buf, &group.FixedFields[j], ptr)
+		}
+		i++
+	}
+	for j := range group.VarintFields {
+		if fieldPresent(presence, i) {
+			writePrimitiveField(buf, &group.VarintFields[j], ptr)
+		}
+		i++
+	}
+	for j := range group.RemainingFields {
+		if fieldPresent(presence, i) {
+			s.writeRemainingField(ctx, ptr, &group.RemainingFields[j], value)
+		}
+		i++
+	}
+}
+
+// markPresentFields sets the presence bits of the non-zero fields, numbering
+// them from i, and returns the number of the next field.
+func markPresentFields(presence []byte, i int, fields []FieldInfo, value reflect.Value) int {
+	for j := range fields {
+		if !value.Field(fields[j].Meta.FieldIndex).IsZero() {
+			presence[i>>3] |= 1 << (i & 7)
+		}
+		i++
+	}
+	return i
+}
+
+// fieldPresent reports whether field i of a struct value was written. A nil
+// presence bitmap means every field was.
+func fieldPresent(presence []byte, i int) bool {
+	return presence == nil || presence[i>>3]&(1<<(i&7)) != 0
+}
+
+// readFieldPresence reads the presence bitmap of a struct value with
+// fieldCount fields.
+func readFieldPresence(ctx *ReadContext, fieldCount int) []byte {
+	return ctx.buffer.ReadBinary((fieldCount+7)/8, ctx.Err())
+}
+
+// writePrimitiveField writes a non-nullable fixed-size or varint field, one at
+// a time, as the grouped phases of WriteData do in bulk.
+func writePrimitiveField(buf *ByteBuffer, field *FieldInfo, ptr unsafe.Pointer) {
+	fieldPtr := unsafe.Add(ptr, field.Offset)
+	optInfo := optionalInfo{}
+	if field.Kind == FieldKindOptional && field.Meta != nil {
+		optInfo = field.Meta.OptionalInfo
+	}
+	switch field.DispatchId {
+	case PrimitiveBoolDispatchId:
+		v, _ := loadFieldValue[bool](field.Kind, fieldPtr, optInfo)
+		buf.WriteBool(v)
+	case PrimitiveInt8DispatchId:
+		v, _ := loadFieldValue[int8](field.Kind, fieldPtr, optInfo)
+		buf.WriteInt8(v)
+	case PrimitiveUint8DispatchId:
+		v, _ := loadFieldValue[uint8](field.Kind, fieldPtr, optInfo)
+		buf.WriteUint8(v)
+	case PrimitiveInt16DispatchId:
+		v, _ := loadFieldValue[int16](field.Kind, fieldPtr, optInfo)
+		buf.WriteInt16(v)
+	case PrimitiveUint16DispatchId, PrimitiveFloat16DispatchId:
+		v, _ := loadFieldValue[uint16](field.Kind, fieldPtr, optInfo)
+		buf.WriteUint16(v)
+	case PrimitiveInt32DispatchId:
+		v, _ := loadFieldValue[int32](field.Kind, fieldPtr, optInfo)
+		buf.WriteInt32(v)
+	case PrimitiveUint32DispatchId:
+		v, _ := loadFieldValue[uint32](field.Kind, fieldPtr, optInfo)
+		buf.WriteUint32(v)
+	case PrimitiveInt64DispatchId:
+		v, _ := loadFieldValue[int64](field.Kind, fieldPtr, optInfo)
+		buf.WriteInt64(v)
+	case PrimitiveUint64DispatchId:
+		v, _ := loadFieldValue[uint64](field.Kind, fieldPtr, optInfo)
+		buf.WriteUint64(v)
+	case PrimitiveFloat32DispatchId:
+		v, _ := loadFieldValue[float32](field.Kind, fieldPtr, optInfo)
+		buf.WriteFloat32(v)
+	case PrimitiveFloat64DispatchId:
+		v, _ := loadFieldValue[float64](field.Kind, fieldPtr, optInfo)
+		buf.WriteFloat64(v)
+	case PrimitiveVarint32DispatchId:
+		v, _ := loadFieldValue[int32](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarint32(v)
+	case PrimitiveVarint64DispatchId:
+		v, _ := loadFieldValue[int64](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarint64(v)
+	case PrimitiveIntDispatchId:
+		v, _ := loadFieldValue[int](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarint64(int64(v))
+	case PrimitiveVarUint32DispatchId:
+		v, _ := loadFieldValue[uint32](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarUint32(v)
+	case PrimitiveVarUint64DispatchId:
+		v, _ := loadFieldValue[uint64](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarUint64(v)
+	case PrimitiveUintDispatchId:
+		v, _ := loadFieldValue[uint](field.Kind, fieldPtr, optInfo)
+		buf.WriteVarUint64(uint64(v))
+	case PrimitiveTaggedInt64DispatchId:
+		v, _ := loadFieldValue[int64](field.Kind, fieldPtr, optInfo)
+		buf.WriteTaggedInt64(v)
+	case PrimitiveTaggedUint64DispatchId:
+		v, _ := loadFieldValue[uint64](field.Kind, fieldPtr, optInfo)
+		buf.WriteTaggedUint64(v)
+	}
+}
+
 // writeRemainingField writes a non-primitive field (string, slice, map, struct, enum)
 func (s *structSerializer) writeRemainingField(ctx *WriteContext, ptr unsafe.Pointer, field *FieldInfo, value reflect.Value) {
 	buf := ctx.Buffer()
@@ -1385,10 +1525,14 @@ func (s *structSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 			return
 		}
 	}
+	ctx.countWork(1)
 
 	buf := ctx.Buffer()
 	if value.Kind() == reflect.Ptr {
 		if value.IsNil() {
+			if !ctx.chargeElems(1, value.Type().Elem()) {
+				return
+			}
 			value.Set(reflect.New(value.Type().Elem()))
 		}
 		value = value.Elem()
@@ -1399,7 +1543,7 @@ func (s *structSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		err := ctx.Err()
 		structHash := buf.ReadInt32(err)
 		if structHash != s.structHash {
-			ctx.SetError(HashMismatchError(structHash, s.structHash, s.type_.String()))
+			ctx.SetError(structHashMismatchError(structHash, s.structHash, s.type_.String(), s.fingerprint()))
 			return
 		}
 	}
@@ -1410,9 +1554,23 @@ func (s *structSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		return
 	}
 
+	if s.hooks != nil && s.hooks.after != nil {
+		defer s.hooks.afterDeserialize(ctx, value)
+	}
+
+	if ctx.projection != nil {
+		s.readProjected(ctx, value)
+		return
+	}
+
+	if s.omitZero {
+		s.readPresentFields(ctx, value, s.fields)
+		return
+	}
+
 	// Use ordered reading when TypeDef differs from local type (schema evolution)
 	if s.typeDefDiffers {
-		s.readFieldsInOrder(ctx, value)
+		s.readFieldsInOrder(ctx, value, s.fields)
 		return
 	}
 
@@ -1677,91 +1835,91 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			readEnumFieldUnsafe(ctx, field, fieldPtr)
 			return
 		case StringSliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]string)(fieldPtr) = ctx.ReadStringSlice(field.RefMode, false)
 			return
 		case BoolSliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]bool)(fieldPtr) = ctx.ReadBoolSlice(field.RefMode, false)
 			return
 		case Int8SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]int8)(fieldPtr) = ctx.ReadInt8Slice(field.RefMode, false)
 			return
 		case ByteSliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]byte)(fieldPtr) = ctx.ReadByteSlice(field.RefMode, false)
 			return
 		case Int16SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]int16)(fieldPtr) = ctx.ReadInt16Slice(field.RefMode, false)
 			return
 		case Int32SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]int32)(fieldPtr) = ctx.ReadInt32Slice(field.RefMode, false)
 			return
 		case Int64SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]int64)(fieldPtr) = ctx.ReadInt64Slice(field.RefMode, false)
 			return
 		case Uint16SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]uint16)(fieldPtr) = ctx.ReadUint16Slice(field.RefMode, false)
 			return
 		case Uint32SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]uint32)(fieldPtr) = ctx.ReadUint32Slice(field.RefMode, false)
 			return
 		case Uint64SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]uint64)(fieldPtr) = ctx.ReadUint64Slice(field.RefMode, false)
 			return
 		case IntSliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]int)(fieldPtr) = ctx.ReadIntSlice(field.RefMode, false)
 			return
 		case UintSliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]uint)(fieldPtr) = ctx.ReadUintSlice(field.RefMode, false)
 			return
 		case Float32SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]float32)(fieldPtr) = ctx.ReadFloat32Slice(field.RefMode, false)
 			return
 		case Float64SliceDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			*(*[]float64)(fieldPtr) = ctx.ReadFloat64Slice(field.RefMode, false)
 			return
 		case StringStringMapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1771,7 +1929,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			*(*map[string]string)(fieldPtr) = ctx.ReadStringStringMap(field.RefMode, false)
 			return
 		case StringInt64MapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1781,7 +1939,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			*(*map[string]int64)(fieldPtr) = ctx.ReadStringInt64Map(field.RefMode, false)
 			return
 		case StringInt32MapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1791,7 +1949,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			*(*map[string]int32)(fieldPtr) = ctx.ReadStringInt32Map(field.RefMode, false)
 			return
 		case StringIntMapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1801,7 +1959,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			*(*map[string]int)(fieldPtr) = ctx.ReadStringIntMap(field.RefMode, false)
 			return
 		case StringFloat64MapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1813,7 +1971,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 		case StringBoolMapDispatchId:
 			// map[string]bool is a regular map in Go - use MAP format
 			// Note: fory.Set[T] uses struct{} values and has its own setSerializer
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1823,7 +1981,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			*(*map[string]bool)(fieldPtr) = ctx.ReadStringBoolMap(field.RefMode, false)
 			return
 		case IntIntMapDispatchId:
-			if field.RefMode == RefModeTracking {
+			if field.RefMode == RefModeTracking || ctx.reuseObjects {
 				break
 			}
 			if field.Meta.HasGenerics && field.Serializer != nil {
@@ -1995,7 +2153,7 @@ func (s *structSerializer) readRemainingField(ctx *ReadContext, ptr unsafe.Point
 			return
 		}
 	}
-	// Slow path for RefModeTracking cases that break from the switch above
+	// Slow path for RefModeTracking and object-reuse cases that break from the switch above
 	fieldValue := value.Field(field.Meta.FieldIndex)
 	if field.Serializer != nil {
 		field.Serializer.Read(ctx, field.RefMode, field.Meta.WriteType, field.Meta.HasGenerics, fieldValue)
@@ -2307,15 +2465,38 @@ func readOptionFast(ctx *ReadContext, field *FieldInfo, optPtr unsafe.Pointer) b
 	}
 }
 
-// readFieldsInOrder reads fields in the order they appear in s.fields (TypeDef order)
+// readPresentFields reads the presence bitmap of an omit-zero struct value and
+// the fields it marks present, in wire order, and zeroes the local fields it
+// marks absent.
+func (s *structSerializer) readPresentFields(ctx *ReadContext, value reflect.Value, fields []FieldInfo) {
+	presence := readFieldPresence(ctx, len(fields))
+	if ctx.HasError() {
+		return
+	}
+	ptr := unsafe.Pointer(value.UnsafeAddr())
+	for i := range fields {
+		field := &fields[i]
+		if fieldPresent(presence, i) {
+			s.readFieldsInOrder(ctx, value, fields[i:i+1])
+			if ctx.HasError() {
+				return
+			}
+		} else if field.ReadAction != remoteFieldReadSkip && field.Meta.FieldIndex >= 0 {
+			fieldType := s.type_.Field(field.Meta.FieldIndex).Type
+			reflect.NewAt(fieldType, unsafe.Add(ptr, field.Offset)).Elem().SetZero()
+		}
+	}
+}
+
+// readFieldsInOrder reads fields in the order they appear in fields (TypeDef order)
 // This is used in compatible mode where Java writes fields in TypeDef order
 // Precondition: value.CanAddr() must be true (checked by caller)
-func (s *structSerializer) readFieldsInOrder(ctx *ReadContext, value reflect.Value) {
+func (s *structSerializer) readFieldsInOrder(ctx *ReadContext, value reflect.Value, fields []FieldInfo) {
 	buf := ctx.Buffer()
 	ptr := unsafe.Pointer(value.UnsafeAddr())
 	err := ctx.Err()
-	for i := 0; i < len(s.fields); i++ {
-		field := &s.fields[i]
+	for i := 0; i < len(fields); i++ {
+		field := &fields[i]
 		switch field.ReadAction {
 		case remoteFieldReadSkip:
 			s.skipField(ctx, field)
@@ -2324,10 +2505,10 @@ func (s *structSerializer) readFieldsInOrder(ctx *ReadContext, value reflect.Val
 			}
 			continue
 		case remoteFieldReadExactFixed:
-			i = readExactFixedPrimitiveRun(ctx, s.fields, i, ptr) - 1
+			i = readExactFixedPrimitiveRun(ctx, fields, i, ptr) - 1
 			continue
 		case remoteFieldReadExactVarint:
-			i = readExactVarintPrimitiveRun(ctx, s.fields, i, ptr) - 1
+			i = readExactVarintPrimitiveRun(ctx, fields, i, ptr) - 1
 			continue
 		case remoteFieldReadCompatibleScalar:
 			readCompatibleScalarField(ctx, field, unsafe.Add(ptr, field.Offset))
@@ -2695,7 +2876,7 @@ func (s *structSerializer) skipField(ctx *ReadContext, field *FieldInfo) {
 	// No FieldDef available, read into temp value
 	tempValue := reflect.New(field.Meta.Type).Elem()
 	if field.Serializer != nil {
-		readType := ctx.Compatible() && isStructField(field.Meta.Type)
+		readType := ctx.Compatible() && isStructField(field.Meta.Type) && !ctx.typeResolver.containsDecimalType(field.Meta.Type)
 		refMode := RefModeNone
 		if field.Meta.Nullable {
 			refMode = RefModeTracking
@@ -2743,28 +2924,28 @@ func writeEnumField(ctx *WriteContext, field *FieldInfo, fieldValue reflect.Valu
 	}
 }
 
-func setEnumValue(ctx *ReadContext, ptr unsafe.Pointer, kind reflect.Kind, ordinal uint32) bool {
+func setEnumValue(ctx *ReadContext, ptr unsafe.Pointer, kind reflect.Kind, bits uint64) bool {
 	switch kind {
 	case reflect.Int:
-		*(*int)(ptr) = int(ordinal)
+		*(*int)(ptr) = int(bits)
 	case reflect.Int8:
-		*(*int8)(ptr) = int8(ordinal)
+		*(*int8)(ptr) = int8(bits)
 	case reflect.Int16:
-		*(*int16)(ptr) = int16(ordinal)
+		*(*int16)(ptr) = int16(bits)
 	case reflect.Int32:
-		*(*int32)(ptr) = int32(ordinal)
+		*(*int32)(ptr) = int32(bits)
 	case reflect.Int64:
-		*(*int64)(ptr) = int64(ordinal)
+		*(*int64)(ptr) = int64(bits)
 	case reflect.Uint:
-		*(*uint)(ptr) = uint(ordinal)
+		*(*uint)(ptr) = uint(bits)
 	case reflect.Uint8:
-		*(*uint8)(ptr) = uint8(ordinal)
+		*(*uint8)(ptr) = uint8(bits)
 	case reflect.Uint16:
-		*(*uint16)(ptr) = uint16(ordinal)
+		*(*uint16)(ptr) = uint16(bits)
 	case reflect.Uint32:
-		*(*uint32)(ptr) = ordinal
+		*(*uint32)(ptr) = uint32(bits)
 	case reflect.Uint64:
-		*(*uint64)(ptr) = uint64(ordinal)
+		*(*uint64)(ptr) = bits
 	default:
 		ctx.SetError(DeserializationErrorf("enum serializer: unsupported kind %v", kind))
 		return false
@@ -2772,6 +2953,18 @@ func setEnumValue(ctx *ReadContext, ptr unsafe.Pointer, kind reflect.Kind, ordin
 	return true
 }
 
+// fieldEnumSerializer returns the enum serializer of an enum or enum pointer field.
+func fieldEnumSerializer(field *FieldInfo) *enumSerializer {
+	switch s := field.Serializer.(type) {
+	case *enumSerializer:
+		return s
+	case *ptrToValueSerializer:
+		e, _ := s.valueSerializer.(*enumSerializer)
+		return e
+	}
+	return nil
+}
+
 // readEnumFieldUnsafe reads an enum field respecting the field's RefMode.
 // RefMode determines whether null flag is read, regardless of whether the local type is a pointer.
 // This is important for compatible mode where remote TypeDef's nullable flag controls the wire format.
@@ -2797,25 +2990,35 @@ func readEnumFieldUnsafe(ctx *ReadContext, field *FieldInfo, fieldPtr unsafe.Poi
 	if ctx.HasError() {
 		return
 	}
+	bits := uint64(ordinal)
+	if s := fieldEnumSerializer(field); s != nil && s.values != nil {
+		var ok bool
+		if bits, ok = s.valueBits(ctx, ordinal); !ok {
+			return
+		}
+	}
 
 	if isPointer {
 		elemType := field.Meta.Type.Elem()
 		newVal := reflect.New(elemType)
 		elemPtr := unsafe.Pointer(newVal.Pointer())
-		if !setEnumValue(ctx, elemPtr, elemType.Kind(), ordinal) {
+		if !setEnumValue(ctx, elemPtr, elemType.Kind(), bits) {
 			return
 		}
 		*(*unsafe.Pointer)(fieldPtr) = elemPtr
 		return
 	}
 
-	setEnumValue(ctx, fieldPtr, field.Meta.Type.Kind(), ordinal)
+	setEnumValue(ctx, fieldPtr, field.Meta.Type.Kind(), bits)
 }
 
 // skipStructSerializer is a serializer that skips unknown struct data
-// It reads and discards field data based on fieldDefs from remote TypeDef
+// It reads and discards field data based on fieldDefs from remote TypeDef,
+// or decodes it into a GenericRecord or map when configured to
 type skipStructSerializer struct {
+	typeDef   *TypeDef
 	fieldDefs []FieldDef
+	unknown   UnknownTypeWarning
 }
 
 func (s *skipStructSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
@@ -2827,40 +3030,36 @@ func (s *skipStructSerializer) Write(ctx *WriteContext, refMode RefMode, writeTy
 }
 
 func (s *skipStructSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
+	if ctx.decodesUnknownStructs() && s.typeDef != nil && value.IsValid() && value.Kind() == reflect.Interface {
+		if ctx.genericRecords && genericRecordPtrType.AssignableTo(value.Type()) {
+			readGenericRecord(ctx, s.typeDef, value)
+			return
+		}
+		if ctx.unknownStructsAsMaps && structMapType.AssignableTo(value.Type()) {
+			readStructAsMap(ctx, s.typeDef, value)
+			return
+		}
+	}
+	var presence []byte
+	if s.typeDef != nil && s.typeDef.omitZero {
+		presence = readFieldPresence(ctx, len(s.fieldDefs))
+	}
 	// Skip all fields based on fieldDefs from remote TypeDef
-	for _, fieldDef := range s.fieldDefs {
+	for i, fieldDef := range s.fieldDefs {
+		if !fieldPresent(presence, i) {
+			continue
+		}
 		isStructType := isStructFieldType(fieldDef.typeSpec)
-		SkipFieldValueWithTypeFlag(ctx, fieldDef, fieldDef.trackRef, ctx.Compatible() && isStructType)
+		SkipFieldValueWithTypeFlag(ctx, fieldDef, fieldDef.trackRef || fieldDef.nullable, ctx.Compatible() && isStructType)
 		if ctx.HasError() {
 			return
 		}
 	}
+	ctx.skippedUnknownType(s.unknown)
 }
 
 func (s *skipStructSerializer) Read(ctx *ReadContext, refMode RefMode, readType bool, hasGenerics bool, value reflect.Value) {
-	buf := ctx.Buffer()
-	ctxErr := ctx.Err()
-	switch refMode {
-	case RefModeTracking:
-		refID, refErr := ctx.RefResolver().TryPreserveRefId(buf)
-		if refErr != nil {
-			ctx.SetError(FromError(refErr))
-			return
-		}
-		if refID < int32(NotNullValueFlag) {
-			// Reference found, nothing to skip
-			return
-		}
-	case RefModeNullOnly:
-		flag := buf.ReadInt8(ctxErr)
-		if flag == NullFlag {
-			return
-		}
-	}
-	if ctx.HasError() {
-		return
-	}
-	s.ReadData(ctx, value)
+	readUnknownValue(ctx, refMode, s, value)
 }
 
 func (s *skipStructSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, typeInfo *TypeInfo, value reflect.Value) {
@@ -22,6 +22,8 @@ import (
 	"fmt"
 	"reflect"
 	"sort"
+	"strconv"
+	"strings"
 	"unicode"
 	"unicode/utf8"
 )
@@ -62,6 +64,7 @@ func (s *structSerializer) initialize(typeResolver *TypeResolver) error {
 		if err := s.initFields(typeResolver); err != nil {
 			return err
 		}
+		s.omitZero = typeResolver.omitZeroFields(s.type_)
 	}
 	// Compute struct hash
 	s.structHash = s.computeHash()
@@ -69,6 +72,7 @@ func (s *structSerializer) initialize(typeResolver *TypeResolver) error {
 		tmp := reflect.New(s.type_).Elem()
 		s.tempValue = &tmp
 	}
+	s.hooks = typeResolver.structHooks(s.type_)
 	s.initialized = true
 	return nil
 }
@@ -128,7 +132,7 @@ func (s *structSerializer) initFields(typeResolver *TypeResolver) error {
 	var tagIDs []int
 
 	for i := 0; i < type_.NumField(); i++ {
-		field := type_.Field(i)
+		field := typeResolver.structField(type_, i)
 		firstRune, _ := utf8.DecodeRuneInString(field.Name)
 		if unicode.IsLower(firstRune) {
 			continue // skip unexported fields
@@ -138,6 +142,7 @@ func (s *structSerializer) initFields(typeResolver *TypeResolver) error {
 		if err != nil {
 			return err
 		}
+		fieldSpec.Name = typeResolver.fieldName(field)
 		fieldSpec.Type = bindResolvedTypeSpec(typeResolver, field.Type, fieldSpec.Type)
 		if fieldSpec.Ignore {
 			continue // skip ignored fields
@@ -351,7 +356,7 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 	localSpecByIndex := make(map[int]*TypeSpec)
 	fieldTagIDToBinding := make(map[int]localFieldBinding)
 	for i := 0; i < type_.NumField(); i++ {
-		field := type_.Field(i)
+		field := typeResolver.structField(type_, i)
 		if field.PkgPath != "" {
 			continue
 		}
@@ -359,6 +364,7 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 		if err != nil {
 			return err
 		}
+		fieldSpec.Name = typeResolver.fieldName(field)
 		fieldSpec.Type = bindResolvedTypeSpec(typeResolver, field.Type, fieldSpec.Type)
 		if fieldSpec.Ignore {
 			continue
@@ -473,6 +479,13 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 			if isPolymorphicField && localType.Kind() == reflect.Interface {
 				shouldRead = true
 				fieldType = localType
+			} else if defTypeId == LIST && isContainerType(localType) {
+				// Any list can be collected into *list.List or *ring.Ring.
+				shouldRead = true
+				fieldType = localType
+			} else if defTypeId == BINARY && isNetAddressType(localType) {
+				shouldRead = true
+				fieldType = localType
 			} else if typeLookupFailed && isEnumField {
 				localKind := localType.Kind()
 				elemKind := localKind
@@ -489,16 +502,17 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 					fieldSerializer, _ = typeResolver.getSerializerByType(baseType, true)
 				}
 			} else if typeLookupFailed && isStructLikeField {
-				localKind := localType.Kind()
-				if localKind == reflect.Ptr {
-					localKind = localType.Elem().Kind()
+				localBase := localType
+				if localBase.Kind() == reflect.Ptr {
+					localBase = localBase.Elem()
 				}
-				if localKind == reflect.Struct || localKind == reflect.Interface {
+				localKind := localBase.Kind()
+				if localKind == reflect.Struct || localKind == reflect.Interface || typeResolver.isExtensionType(localBase) {
 					shouldRead = true
 					fieldType = localType
 				}
 			} else if typeLookupFailed && (internalDefTypeId == UNION || internalDefTypeId == TYPED_UNION || internalDefTypeId == NAMED_UNION) {
-				if isUnionType(localType) {
+				if isUnionType(localType) || typeResolver.isUnionInterface(localType) {
 					shouldRead = true
 					fieldType = localType
 				}
@@ -588,6 +602,15 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 			} else if !refTrackedScalarSchemaMismatch && !typeLookupFailed && typesCompatible(localType, remoteType) && (!scalarPair || scalarExactSchema) {
 				shouldRead = true
 				fieldType = localType
+			} else if exactSchema && containsNetAddressType(localType) {
+				// Remote BINARY resolves to []byte, which differs from the local carrier.
+				shouldRead = true
+				fieldType = localType
+			} else if exactSchema && typeResolver.containsDecimalType(localType) {
+				// Remote DECIMAL resolves to Decimal, which differs from a type
+				// registered with RegisterDecimal.
+				shouldRead = true
+				fieldType = localType
 			}
 			if !refTrackedScalarSchemaMismatch && !shouldRead && localFieldSpec != nil {
 				if !def.trackRef && !localTrackRefByIndex[fieldIndex] {
@@ -698,7 +721,10 @@ func (s *structSerializer) initFieldsFromTypeDef(typeResolver *TypeResolver) err
 		} else if def.nullable {
 			refMode = RefModeNullOnly
 		}
-		writeType := typeResolver.Compatible() && isStructField(baseType)
+		// The writer decides from its field spec, which also covers extension
+		// types registered on non-struct carriers.
+		writeType := typeResolver.Compatible() &&
+			((isStructField(baseType) && !typeResolver.containsDecimalType(baseType)) || isStructFieldType(def.typeSpec))
 		var cachedTypeInfo *TypeInfo
 		if writeType {
 			cachedType := baseType
@@ -947,6 +973,80 @@ func typeIdEqualForDiff(remoteTypeId TypeId, localTypeId TypeId) bool {
 }
 
 func (s *structSerializer) computeHash() int32 {
+	hashString := s.fingerprint()
+	hash := fingerprintHash(hashString)
+
+	if DebugOutputEnabled {
+		fmt.Printf("[Go][fory-debug] struct %v version fingerprint=\"%s\" version hash=%d\n", s.type_, hashString, hash)
+	}
+
+	if hash == 0 {
+		panic(fmt.Errorf("hash for type %v is 0", s.type_))
+	}
+	return hash
+}
+
+func fingerprintHash(fingerprint string) int32 {
+	h1, _ := Murmur3Sum128WithSeed([]byte(fingerprint), 47)
+	return int32(h1 & 0xFFFFFFFF)
+}
+
+// explainHashMismatch names the field that makes the local fingerprint hash
+// differently from peerHash. The writer's fields are not in the payload, so
+// it tries every change of a single local field the hash could reflect: the
+// field missing from the writer, or declared with another type, ref or
+// nullable flag. Added and renamed fields cannot be recovered.
+func explainHashMismatch(fingerprint string, peerHash int32) string {
+	entries := strings.Split(strings.TrimSuffix(fingerprint, ";"), ";")
+	rebuilt := func(i int, entry string) string {
+		var sb strings.Builder
+		for j, e := range entries {
+			if j == i {
+				e = entry
+			}
+			if e != "" {
+				sb.WriteString(e)
+				sb.WriteString(";")
+			}
+		}
+		return sb.String()
+	}
+	for i, entry := range entries {
+		// entry is key,type_id,ref,nullable followed by nested element types.
+		parts := strings.SplitN(entry, ",", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		key, nested := parts[0], ""
+		nullable := parts[3]
+		if idx := strings.IndexByte(nullable, '['); idx >= 0 {
+			nullable, nested = nullable[:idx], nullable[idx:]
+		}
+		localType, err := strconv.Atoi(parts[1])
+		if err != nil {
+			continue
+		}
+		if fingerprintHash(rebuilt(i, "")) == peerHash {
+			return fmt.Sprintf("field %s is missing from the writer", key)
+		}
+		for typeID := 0; typeID < len(typeIdNames); typeID++ {
+			for _, ref := range []string{"0", "1"} {
+				for _, null := range []string{"0", "1"} {
+					candidate := fmt.Sprintf("%s,%d,%s,%s%s", key, typeID, ref, null, nested)
+					if candidate == entry || fingerprintHash(rebuilt(i, candidate)) != peerHash {
+						continue
+					}
+					return fmt.Sprintf("field %s is %s (ref=%s, nullable=%s) in the writer and %s (ref=%s, nullable=%s) locally",
+						key, typeIdName(TypeId(typeID)), ref, null, typeIdName(TypeId(localType)), parts[2], nullable)
+				}
+			}
+		}
+	}
+	return "no change of a single local field explains the payload hash; the writer may add or rename fields"
+}
+
+// fingerprint returns the xlang struct fingerprint the struct hash is computed from.
+func (s *structSerializer) fingerprint() string {
 	// Build FieldFingerprintInfo for each field
 	fields := make([]FieldFingerprintInfo, 0, len(s.fields))
 	for _, field := range s.fields {
@@ -1070,17 +1170,5 @@ func (s *structSerializer) computeHash() int32 {
 		})
 	}
 
-	hashString := ComputeStructFingerprint(fields)
-	data := []byte(hashString)
-	h1, _ := Murmur3Sum128WithSeed(data, 47)
-	hash := int32(h1 & 0xFFFFFFFF)
-
-	if DebugOutputEnabled {
-		fmt.Printf("[Go][fory-debug] struct %v version fingerprint=\"%s\" version hash=%d\n", s.type_, hashString, hash)
-	}
-
-	if hash == 0 {
-		panic(fmt.Errorf("hash for type %v is 0", s.type_))
-	}
-	return hash
+	return ComputeStructFingerprint(fields)
 }
@@ -15,9 +15,12 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
+	"fmt"
 	"reflect"
 	"testing"
 	"time"
@@ -689,3 +692,156 @@ func TestVarintFastPathTightBuffer(t *testing.T) {
 	require.NoError(t, f.Deserialize(data, &out))
 	require.Equal(t, obj.Value, out.Value)
 }
+
+func TestStructHashMismatchNamesFields(t *testing.T) {
+	type UserV1 struct {
+		Age  int32
+		Name string
+	}
+	type UserV2 struct {
+		Age   int32
+		Email string
+	}
+	writer := NewFory(WithXlang(true), WithCompatible(false))
+	require.NoError(t, writer.RegisterStruct(UserV1{}, 1))
+	data, err := writer.Marshal(&UserV1{Age: 3, Name: "alice"})
+	require.NoError(t, err)
+
+	reader := NewFory(WithXlang(true), WithCompatible(false))
+	require.NoError(t, reader.RegisterStruct(UserV2{}, 1))
+	var user UserV2
+	err = reader.Unmarshal(data, &user)
+	require.Error(t, err)
+	msg := err.Error()
+	require.Contains(t, msg, fmt.Sprintf("payload hash %d", GetStructHash(reflect.TypeOf(UserV1{}), writer.typeResolver)))
+	require.Contains(t, msg, fmt.Sprintf("local hash %d", GetStructHash(reflect.TypeOf(UserV2{}), reader.typeResolver)))
+	require.Contains(t, msg, "age,5,0,0;email,21,0,0;")
+	require.Contains(t, msg, "no change of a single local field explains the payload hash")
+}
+
+func TestStructHashMismatchDiagnosesField(t *testing.T) {
+	type UserV1 struct {
+		Age int32
+	}
+	type UserV2 struct {
+		Age   int32
+		Email string
+	}
+	type UserV3 struct {
+		Age   int64
+		Email string
+	}
+	mismatch := func(t *testing.T, written any, target any) string {
+		writer := NewFory(WithXlang(true), WithCompatible(false))
+		require.NoError(t, writer.RegisterStruct(reflect.TypeOf(written).Elem(), 1))
+		data, err := writer.Marshal(written)
+		require.NoError(t, err)
+		reader := NewFory(WithXlang(true), WithCompatible(false))
+		require.NoError(t, reader.RegisterStruct(reflect.TypeOf(target).Elem(), 1))
+		err = reader.Unmarshal(data, target)
+		require.Error(t, err)
+		return err.Error()
+	}
+
+	t.Run("MissingField", func(t *testing.T) {
+		msg := mismatch(t, &UserV1{Age: 3}, &UserV2{})
+		require.Contains(t, msg, "field email is missing from the writer")
+	})
+	t.Run("ChangedType", func(t *testing.T) {
+		msg := mismatch(t, &UserV2{Age: 3, Email: "a"}, &UserV3{})
+		require.Contains(t, msg, "field age is VARINT32 (ref=0, nullable=0) in the writer and VARINT64 (ref=0, nullable=0) locally")
+	})
+}
+
+type nestedLine struct {
+	SKU   string
+	Price *nestedMoney
+}
+
+type nestedMoney struct {
+	Units int64
+}
+
+type nestedNode struct {
+	Name     string
+	Children []*nestedNode
+}
+
+type nestedOrder struct {
+	Lines    []nestedLine
+	ByRegion map[string]nestedMoney
+	Discount *nestedMoney
+	Tree     *nestedNode
+	Placed   time.Time
+	Skipped  nestedSkipped `fory:"-"`
+}
+
+type nestedSkipped struct {
+	X int32
+}
+
+func TestRegisterNestedStructs(t *testing.T) {
+	for _, xlang := range []bool{true, false} {
+		f := New(WithXlang(xlang))
+		require.NoError(t, f.RegisterStructByName(nestedOrder{}, "example.Order", WithNestedStructs()))
+		for _, name := range []string{"example.nestedLine", "example.nestedMoney", "example.nestedNode"} {
+			require.Contains(t, f.typeResolver.typeInfoToType, "@"+name)
+		}
+		require.NotContains(t, f.typeResolver.typeInfoToType, "@example.nestedSkipped")
+
+		order := &nestedOrder{
+			Lines:    []nestedLine{{SKU: "a", Price: &nestedMoney{Units: 3}}},
+			ByRegion: map[string]nestedMoney{"eu": {Units: 4}},
+			Discount: &nestedMoney{Units: 1},
+			Tree:     &nestedNode{Name: "root", Children: []*nestedNode{{Name: "leaf", Children: []*nestedNode{}}}},
+			Placed:   time.Unix(100, 0),
+		}
+		data, err := f.Serialize(order)
+		require.NoError(t, err)
+		var out nestedOrder
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, *order, out)
+
+		// Clones replay the nested registrations.
+		data, err = mustClone(t, f).Serialize(order)
+		require.NoError(t, err)
+		require.NoError(t, f.Deserialize(data, &out))
+	}
+}
+
+type nestedConflict struct {
+	Line  nestedLine
+	Money nestedMoney
+}
+
+func TestRegisterNestedStructsErrors(t *testing.T) {
+	f := New(WithXlang(true))
+	require.Error(t, f.RegisterStruct(nestedOrder{}, 1, WithNestedStructs()))
+	require.NoError(t, f.RegisterStructByName(nestedSkipped{}, "example.nestedMoney"))
+	// The nested name is taken, so nothing is registered.
+	err := f.RegisterStructByName(nestedConflict{}, "example.Conflict", WithNestedStructs())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "example.nestedMoney")
+	require.NotContains(t, f.typeResolver.typeInfoToType, "@example.Conflict")
+	require.NotContains(t, f.typeResolver.typeInfoToType, "@example.nestedLine")
+	// Registered nested types are kept as they are.
+	require.NoError(t, f.RegisterStructByName(nestedMoney{}, "billing.Money"))
+	require.NoError(t, f.RegisterStructByName(nestedConflict{}, "example.Conflict", WithNestedStructs()))
+	require.Equal(t, reflect.TypeOf(nestedMoney{}), f.typeResolver.typeInfoToType["@billing.Money"])
+
+	// A nested name held by an alias fails before anything is registered.
+	f = New(WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(nestedSkipped{}, "example.Skipped"))
+	require.NoError(t, f.RegisterTypeAlias("example.nestedMoney", nestedSkipped{}))
+	registrations := len(f.registrations)
+	err = f.RegisterStructByName(nestedConflict{}, "example.Conflict", WithNestedStructs())
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "example.nestedMoney")
+	require.NotContains(t, f.typeResolver.typeInfoToType, "@example.Conflict")
+	require.NotContains(t, f.typeResolver.typeInfoToType, "@example.nestedLine")
+	require.Len(t, f.registrations, registrations)
+	require.NoError(t, f.RegisterStructByName(nestedLine{}, "example.nestedLine"))
+	require.NoError(t, f.RegisterStructByName(nestedConflict{}, "example.Conflict"))
+	_, err = f.Clone()
+	require.NoError(t, err)
+}
@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -67,6 +69,23 @@ func TestParseFieldSpecBasic(t *testing.T) {
 	require.EqualValues(t, STRING, defaultSpec.Type.TypeId())
 }
 
+func TestParseFieldSpecEncodingShorthand(t *testing.T) {
+	type Example struct {
+		Fixed32  int32  `fory:",fixed"`
+		Varint64 int64  `fory:"id=1,varint"`
+		Tagged   uint64 `fory:"tagged"`
+		Fixed64  int64  `fory:"encoding=fixed"`
+	}
+
+	typ := reflect.TypeOf(Example{})
+	require.EqualValues(t, INT32, mustParseFieldSpec(t, typ.Field(0)).Type.TypeId())
+	varintSpec := mustParseFieldSpec(t, typ.Field(1))
+	require.Equal(t, 1, varintSpec.TagID)
+	require.EqualValues(t, VARINT64, varintSpec.Type.TypeId())
+	require.EqualValues(t, TAGGED_UINT64, mustParseFieldSpec(t, typ.Field(2)).Type.TypeId())
+	require.EqualValues(t, INT64, mustParseFieldSpec(t, typ.Field(3)).Type.TypeId())
+}
+
 func TestParseFieldSpecNestedTypeHints(t *testing.T) {
 	type Example struct {
 		Size   uint64              `fory:"id=0,encoding=tagged"`
@@ -250,6 +269,12 @@ func TestParseFieldSpecRejectsInvalidTags(t *testing.T) {
 	type Conflict struct {
 		Value int32 `fory:"encoding=fixed,type=int32"`
 	}
+	type ShorthandConflict struct {
+		Value int64 `fory:"fixed,encoding=varint"`
+	}
+	type ShorthandValue struct {
+		Value int64 `fory:"fixed=true"`
+	}
 	type InvalidArrayEncoding struct {
 		Value []int32 `fory:"type=array(element=int32(encoding=varint))"`
 	}
@@ -272,6 +297,8 @@ func TestParseFieldSpecRejectsInvalidTags(t *testing.T) {
 		{name: "bad dsl", typ: reflect.TypeOf(BadDSL{})},
 		{name: "impossible override", typ: reflect.TypeOf(ImpossibleOverride{})},
 		{name: "encoding conflict", typ: reflect.TypeOf(Conflict{})},
+		{name: "shorthand conflict", typ: reflect.TypeOf(ShorthandConflict{})},
+		{name: "shorthand with value", typ: reflect.TypeOf(ShorthandValue{})},
 		{name: "invalid array encoding", typ: reflect.TypeOf(InvalidArrayEncoding{})},
 		{name: "invalid array element", typ: reflect.TypeOf(InvalidArrayElement{})},
 		{name: "invalid array key", typ: reflect.TypeOf(InvalidArrayKey{})},
@@ -323,3 +350,54 @@ func TestShouldIncludeField(t *testing.T) {
 	require.False(t, shouldIncludeField(typ.Field(1)))
 	require.True(t, shouldIncludeField(typ.Field(2)))
 }
+
+type jsonTaggedUser struct {
+	UserID      int64  `json:"user_id"`
+	DisplayName string `json:"name,omitempty"`
+	Email       string `json:"-"`
+	Age         int32  `json:",omitempty"`
+	Password    string `json:"password" fory:"-"`
+}
+
+type plainUser struct {
+	UserId   int64
+	Name     string
+	Email    string
+	Age      int32
+	Password string `fory:"-"`
+}
+
+func TestFieldNameTag(t *testing.T) {
+	for _, compatible := range []bool{true, false} {
+		tagged := New(WithXlang(true), WithCompatible(compatible), WithFieldNameTag("json"))
+		require.NoError(t, tagged.RegisterStructByName(jsonTaggedUser{}, "example.user"))
+		plain := New(WithXlang(true), WithCompatible(compatible))
+		require.NoError(t, plain.RegisterStructByName(plainUser{}, "example.user"))
+
+		data, err := tagged.Serialize(&jsonTaggedUser{UserID: 7, DisplayName: "Ada", Email: "ada@example.com", Age: 36, Password: "secret"})
+		require.NoError(t, err)
+		var out plainUser
+		require.NoError(t, plain.Deserialize(data, &out))
+		require.Equal(t, plainUser{UserId: 7, Name: "Ada", Email: "ada@example.com", Age: 36}, out)
+	}
+
+	type clash struct {
+		Name  string `msgpack:"name"`
+		Title string `msgpack:"name"`
+	}
+	f := New(WithXlang(true), WithFieldNameTag("msgpack"))
+	err := f.RegisterStructByName(clash{}, "example.clash")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), `both serialize as "name"`)
+
+	// WithFieldTag replaces the fory tag but keeps the name.
+	f = New(WithXlang(true), WithCompatible(true), WithFieldNameTag("json"))
+	require.NoError(t, f.RegisterStructByName(jsonTaggedUser{}, "example.user", WithFieldTag("Age", "-")))
+	plain := New(WithXlang(true), WithCompatible(true))
+	require.NoError(t, plain.RegisterStructByName(plainUser{}, "example.user"))
+	data, err := f.Serialize(&jsonTaggedUser{UserID: 7, DisplayName: "Ada", Age: 36})
+	require.NoError(t, err)
+	var out plainUser
+	require.NoError(t, plain.Deserialize(data, &out))
+	require.Equal(t, plainUser{UserId: 7, Name: "Ada"}, out)
+}
@@ -15,6 +15,10 @@
 // specific language governing permissions and limitations
 // under the License.
 
+// These tests compare generated serializers with reflection-based ones, which
+// codegen-only builds do not include.
+//go:build !tinygo && !fory_codegen_only
+
 package fory
 
 import (
@@ -0,0 +1,530 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+// Package golden holds the cross-language golden-file corpus shared with the
+// Java implementation.
+//
+// Each case describes a value, the Fory configuration used to encode it and
+// the type registrations both sides must agree on. Go writes its payloads to
+// testdata/go/<case>.bin; the Java side writes the same cases to a directory
+// of its own, which the Go tests decode when FORY_JAVA_GOLDEN_DIR points at it.
+// The Java registrations use the same numeric IDs and names listed below.
+//
+// Out-of-band cases store their buffers next to the payload in <case>.oob: an
+// int32 buffer count followed by an int32 length and the bytes of each buffer,
+// all little-endian. GoGoldenCorpusTest in fory-core reads the Go fixtures and
+// writes the Java ones.
+package golden
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/apache/fory/go/fory"
+)
+
+// Color is registered as enum type ID 102 on both sides.
+type Color int32
+
+const (
+	Green Color = iota
+	Red
+	Blue
+	White
+)
+
+// Item is registered as type ID 101 on both sides.
+type Item struct {
+	Name  string
+	Count int32
+	Tags  []string
+}
+
+// NamedItem is registered by name as "golden.NamedItem" on both sides.
+type NamedItem struct {
+	Id    int64
+	Label string
+	Score float64
+}
+
+// Palette is registered as type ID 103 and carries an enum field.
+type Palette struct {
+	Primary Color
+	Colors  []Color
+}
+
+// RefPair is registered as type ID 104; First and Second point at the same Item.
+type RefPair struct {
+	First  *Item `fory:"ref"`
+	Second *Item `fory:"ref"`
+}
+
+// Event is registered by name as "golden.Event" by the writer of the schema
+// evolution cases. Readers register one of the Event* variants below under
+// the same name instead, so each case decodes an Event payload into a changed
+// schema. The Java writer uses a class with fields id (long), name (String),
+// count (int) and legacy (String), and the Java reader mirrors the variants
+// except EventWidened: Java does not widen field types, so it reads that case
+// as Event.
+type Event struct {
+	Id     int64
+	Name   string
+	Count  int32
+	Legacy string
+}
+
+// EventAdded appends a field the writer does not know.
+type EventAdded struct {
+	Id     int64
+	Name   string
+	Count  int32
+	Legacy string
+	Source string
+}
+
+// EventRemoved drops the Legacy field.
+type EventRemoved struct {
+	Id    int64
+	Name  string
+	Count int32
+}
+
+// EventWidened reads Count as an int64.
+type EventWidened struct {
+	Id     int64
+	Name   string
+	Count  int64
+	Legacy string
+}
+
+// EventReordered declares the fields in reverse order.
+type EventReordered struct {
+	Legacy string
+	Count  int32
+	Name   string
+	Id     int64
+}
+
+// Samples is registered as type ID 105. Values and Ids are primitive arrays,
+// so the out-of-band cases move them into separate buffers.
+type Samples struct {
+	Name   string
+	Values []float64 `fory:"type=array(element=float64)"`
+	Ids    []int32   `fory:"type=array(element=int32)"`
+}
+
+const (
+	itemTypeID    = 101
+	colorTypeID   = 102
+	paletteTypeID = 103
+	refPairTypeID = 104
+	samplesTypeID = 105
+	namedItemName = "golden.NamedItem"
+	eventName     = "golden.Event"
+)
+
+// Case is one entry of the golden corpus.
+type Case struct {
+	Name    string
+	Options []fory.Option
+	// Value returns the value to serialize.
+	Value func() any
+	// Target returns a pointer the payload is decoded into.
+	Target func() any
+	// Check validates the decoded target against Value.
+	Check func(target any) error
+	// ReaderType, when set, is registered as "golden.Event" for decoding
+	// while the writer registers Event, so the case reads an evolved schema.
+	ReaderType any
+	// OutOfBand writes every buffer object out of band, see <case>.oob.
+	OutOfBand bool
+}
+
+// NewFory returns a Fory instance configured and registered for c.
+func (c Case) NewFory() (*fory.Fory, error) {
+	return c.newFory(Event{})
+}
+
+func (c Case) newFory(event any) (*fory.Fory, error) {
+	f := fory.New(c.Options...)
+	if err := Register(f); err != nil {
+		return nil, err
+	}
+	if err := f.RegisterStructByName(event, eventName); err != nil {
+		return nil, err
+	}
+	return f, nil
+}
+
+// Register registers every corpus type with f using the shared IDs and names.
+func Register(f *fory.Fory) error {
+	if err := f.RegisterStruct(Item{}, itemTypeID); err != nil {
+		return err
+	}
+	if err := f.RegisterEnum(Color(0), colorTypeID); err != nil {
+		return err
+	}
+	if err := f.RegisterStruct(Palette{}, paletteTypeID); err != nil {
+		return err
+	}
+	if err := f.RegisterStruct(RefPair{}, refPairTypeID); err != nil {
+		return err
+	}
+	if err := f.RegisterStruct(Samples{}, samplesTypeID); err != nil {
+		return err
+	}
+	return f.RegisterStructByName(NamedItem{}, namedItemName)
+}
+
+func xlangOptions(compatible, trackRef bool) []fory.Option {
+	return []fory.Option{
+		fory.WithXlang(true),
+		fory.WithCompatible(compatible),
+		fory.WithTrackRef(trackRef),
+	}
+}
+
+func newSamples() *Samples {
+	return &Samples{Name: "probe", Values: []float64{0.25, -1, 1e9}, Ids: []int32{3, 1, 4, 1, 5}}
+}
+
+func newItem() *Item {
+	return &Item{Name: "apple", Count: 42, Tags: []string{"red", "fruit"}}
+}
+
+// Cases returns the corpus in a stable order.
+func Cases() []Case {
+	return []Case{
+		{
+			Name:    "string",
+			Options: xlangOptions(true, false),
+			Value:   func() any { return "hello, 世界" },
+			Target:  func() any { return new(string) },
+			Check: func(target any) error {
+				return expectEqual("hello, 世界", *target.(*string))
+			},
+		},
+		{
+			Name:    "int64_list",
+			Options: xlangOptions(true, false),
+			Value:   func() any { return []int64{-1, 0, 1, 1 << 40} },
+			Target:  func() any { return new([]int64) },
+			Check: func(target any) error {
+				return expectSlice([]int64{-1, 0, 1, 1 << 40}, *target.(*[]int64))
+			},
+		},
+		{
+			Name:    "struct_schema_consistent",
+			Options: xlangOptions(false, false),
+			Value:   func() any { return newItem() },
+			Target:  func() any { return new(Item) },
+			Check:   checkItem,
+		},
+		{
+			Name:    "struct_compatible",
+			Options: xlangOptions(true, false),
+			Value:   func() any { return newItem() },
+			Target:  func() any { return new(Item) },
+			Check:   checkItem,
+		},
+		{
+			Name:    "named_struct_compatible",
+			Options: xlangOptions(true, false),
+			Value: func() any {
+				return &NamedItem{Id: 7, Label: "seven", Score: 0.5}
+			},
+			Target: func() any { return new(NamedItem) },
+			Check: func(target any) error {
+				return expectEqual(NamedItem{Id: 7, Label: "seven", Score: 0.5}, *target.(*NamedItem))
+			},
+		},
+		{
+			Name:    "enum",
+			Options: xlangOptions(true, false),
+			Value:   func() any { return Blue },
+			Target:  func() any { return new(Color) },
+			Check: func(target any) error {
+				return expectEqual(Blue, *target.(*Color))
+			},
+		},
+		{
+			Name:    "enum_struct",
+			Options: xlangOptions(true, false),
+			Value: func() any {
+				return &Palette{Primary: Red, Colors: []Color{Green, White}}
+			},
+			Target: func() any { return new(Palette) },
+			Check: func(target any) error {
+				p := target.(*Palette)
+				if err := expectEqual(Red, p.Primary); err != nil {
+					return err
+				}
+				return expectSlice([]Color{Green, White}, p.Colors)
+			},
+		},
+		{
+			Name:    "shared_ref",
+			Options: xlangOptions(true, true),
+			Value: func() any {
+				item := newItem()
+				return &RefPair{First: item, Second: item}
+			},
+			Target: func() any { return new(RefPair) },
+			Check: func(target any) error {
+				p := target.(*RefPair)
+				if p.First == nil || p.First != p.Second {
+					return fmt.Errorf("expected First and Second to share one *Item, got %p and %p", p.First, p.Second)
+				}
+				return checkItem(p.First)
+			},
+		},
+		{
+			Name:       "evolution_field_added",
+			Options:    xlangOptions(true, false),
+			Value:      func() any { return newEvent() },
+			ReaderType: EventAdded{},
+			Target:     func() any { return new(EventAdded) },
+			Check: func(target any) error {
+				e := target.(*EventAdded)
+				if err := expectEqual("", e.Source); err != nil {
+					return err
+				}
+				return expectEqual(*newEvent(), Event{Id: e.Id, Name: e.Name, Count: e.Count, Legacy: e.Legacy})
+			},
+		},
+		{
+			Name:       "evolution_field_removed",
+			Options:    xlangOptions(true, false),
+			Value:      func() any { return newEvent() },
+			ReaderType: EventRemoved{},
+			Target:     func() any { return new(EventRemoved) },
+			Check: func(target any) error {
+				e := target.(*EventRemoved)
+				want := newEvent()
+				return expectEqual(EventRemoved{Id: want.Id, Name: want.Name, Count: want.Count}, *e)
+			},
+		},
+		{
+			Name:       "evolution_field_widened",
+			Options:    xlangOptions(true, false),
+			Value:      func() any { return newEvent() },
+			ReaderType: EventWidened{},
+			Target:     func() any { return new(EventWidened) },
+			Check: func(target any) error {
+				e := target.(*EventWidened)
+				want := newEvent()
+				return expectEqual(EventWidened{Id: want.Id, Name: want.Name, Count: int64(want.Count), Legacy: want.Legacy}, *e)
+			},
+		},
+		{
+			Name:       "evolution_fields_reordered",
+			Options:    xlangOptions(true, false),
+			Value:      func() any { return newEvent() },
+			ReaderType: EventReordered{},
+			Target:     func() any { return new(EventReordered) },
+			Check: func(target any) error {
+				e := target.(*EventReordered)
+				return expectEqual(*newEvent(), Event{Id: e.Id, Name: e.Name, Count: e.Count, Legacy: e.Legacy})
+			},
+		},
+		{
+			Name:      "oob_float64_array",
+			Options:   xlangOptions(true, false),
+			OutOfBand: true,
+			Value:     func() any { return newSamples().Values },
+			Target:    func() any { return new([]float64) },
+			Check: func(target any) error {
+				return expectSlice(newSamples().Values, *target.(*[]float64))
+			},
+		},
+		{
+			Name:      "oob_struct",
+			Options:   xlangOptions(true, false),
+			OutOfBand: true,
+			Value:     func() any { return newSamples() },
+			Target:    func() any { return new(Samples) },
+			Check: func(target any) error {
+				s := target.(*Samples)
+				want := newSamples()
+				if err := expectEqual(want.Name, s.Name); err != nil {
+					return err
+				}
+				if err := expectSlice(want.Values, s.Values); err != nil {
+					return err
+				}
+				return expectSlice(want.Ids, s.Ids)
+			},
+		},
+	}
+}
+
+// Encode serializes the case value and returns an owned copy of the payload
+// together with the out-of-band buffers, which are nil unless c.OutOfBand.
+func (c Case) Encode() ([]byte, [][]byte, error) {
+	f, err := c.NewFory()
+	if err != nil {
+		return nil, nil, err
+	}
+	if !c.OutOfBand {
+		data, err := f.Serialize(c.Value())
+		if err != nil {
+			return nil, nil, err
+		}
+		return append([]byte(nil), data...), nil, nil
+	}
+	buf := fory.NewByteBuffer(nil)
+	buffers := [][]byte{}
+	err = f.SerializeWithCallback(buf, c.Value(), func(o fory.BufferObject) bool {
+		out := fory.NewByteBuffer(make([]byte, 0, o.TotalBytes()))
+		o.WriteTo(out)
+		buffers = append(buffers, out.Bytes())
+		return false
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return append([]byte(nil), buf.Bytes()...), buffers, nil
+}
+
+// Decode deserializes data and its out-of-band buffers with the case
+// configuration and runs Check.
+func (c Case) Decode(data []byte, buffers [][]byte) error {
+	reader := c.ReaderType
+	if reader == nil {
+		reader = Event{}
+	}
+	f, err := c.newFory(reader)
+	if err != nil {
+		return err
+	}
+	target := c.Target()
+	if c.OutOfBand {
+		views := make([]*fory.ByteBuffer, len(buffers))
+		for i, b := range buffers {
+			views[i] = fory.NewByteBuffer(b)
+		}
+		err = f.DeserializeWithCallbackBuffers(fory.NewByteBuffer(data), target, views)
+	} else {
+		err = f.Deserialize(data, target)
+	}
+	if err != nil {
+		return err
+	}
+	return c.Check(target)
+}
+
+// Generate writes every case payload to dir as <case>.bin, and the buffers of
+// out-of-band cases as <case>.oob.
+func Generate(dir string) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for _, c := range Cases() {
+		data, buffers, err := c.Encode()
+		if err != nil {
+			return fmt.Errorf("encode %s: %w", c.Name, err)
+		}
+		if err := os.WriteFile(FilePath(dir, c.Name), data, 0o644); err != nil {
+			return err
+		}
+		if c.OutOfBand {
+			if err := os.WriteFile(BuffersPath(dir, c.Name), EncodeBuffers(buffers), 0o644); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// FilePath returns the fixture path for a case inside dir.
+func FilePath(dir, name string) string {
+	return filepath.Join(dir, name+".bin")
+}
+
+// BuffersPath returns the out-of-band buffers path for a case inside dir.
+func BuffersPath(dir, name string) string {
+	return filepath.Join(dir, name+".oob")
+}
+
+// EncodeBuffers lays out out-of-band buffers in the <case>.oob format.
+func EncodeBuffers(buffers [][]byte) []byte {
+	out := fory.NewByteBuffer(nil)
+	out.WriteInt32(int32(len(buffers)))
+	for _, b := range buffers {
+		out.WriteInt32(int32(len(b)))
+		out.WriteBinary(b)
+	}
+	return out.Bytes()
+}
+
+// DecodeBuffers splits a <case>.oob file into its buffers.
+func DecodeBuffers(data []byte) ([][]byte, error) {
+	in := fory.NewByteBuffer(data)
+	var err fory.Error
+	n := int(in.ReadInt32(&err))
+	if n < 0 {
+		return nil, fmt.Errorf("negative buffer count %d", n)
+	}
+	buffers := make([][]byte, 0, n)
+	for i := 0; i < n && !err.HasError(); i++ {
+		size := int(in.ReadInt32(&err))
+		if size < 0 {
+			return nil, fmt.Errorf("negative length %d for buffer %d", size, i)
+		}
+		buffers = append(buffers, in.ReadBinary(size, &err))
+	}
+	if err.HasError() {
+		return nil, fmt.Errorf("read out-of-band buffers: %w", err)
+	}
+	return buffers, nil
+}
+
+func checkItem(target any) error {
+	item := target.(*Item)
+	want := newItem()
+	if err := expectEqual(want.Name, item.Name); err != nil {
+		return err
+	}
+	if err := expectEqual(want.Count, item.Count); err != nil {
+		return err
+	}
+	return expectSlice(want.Tags, item.Tags)
+}
+
+func newEvent() *Event {
+	return &Event{Id: 9, Name: "deploy", Count: -3, Legacy: "v1"}
+}
+
+func expectEqual[T comparable](want, got T) error {
+	if want != got {
+		return fmt.Errorf("expected %v, got %v", want, got)
+	}
+	return nil
+}
+
+func expectSlice[T comparable](want, got []T) error {
+	if len(want) != len(got) {
+		return fmt.Errorf("expected %v, got %v", want, got)
+	}
+	for i := range want {
+		if want[i] != got[i] {
+			return fmt.Errorf("expected %v, got %v", want, got)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,83 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package golden
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// Regenerate the Go fixtures with: go test ./tests/golden -update
+var update = flag.Bool("update", false, "rewrite testdata/go golden files")
+
+const goFixtureDir = "testdata/go"
+
+func TestGoFixtures(t *testing.T) {
+	if *update {
+		require.NoError(t, Generate(goFixtureDir))
+	}
+	for _, c := range Cases() {
+		t.Run(c.Name, func(t *testing.T) {
+			want, err := os.ReadFile(FilePath(goFixtureDir, c.Name))
+			require.NoError(t, err, "missing fixture; run go test ./tests/golden -update")
+			got, gotBuffers, err := c.Encode()
+			require.NoError(t, err)
+			require.True(t, bytes.Equal(want, got),
+				"encoding of %s drifted from golden file:\nwant %x\ngot  %x", c.Name, want, got)
+			var buffers [][]byte
+			if c.OutOfBand {
+				data, err := os.ReadFile(BuffersPath(goFixtureDir, c.Name))
+				require.NoError(t, err, "missing buffers; run go test ./tests/golden -update")
+				require.True(t, bytes.Equal(data, EncodeBuffers(gotBuffers)),
+					"out-of-band buffers of %s drifted from golden file", c.Name)
+				buffers, err = DecodeBuffers(data)
+				require.NoError(t, err)
+			}
+			require.NoError(t, c.Decode(want, buffers))
+		})
+	}
+}
+
+// TestJavaFixtures decodes payloads written by the Java implementation.
+// Point FORY_JAVA_GOLDEN_DIR at the directory holding the Java <case>.bin and
+// <case>.oob files; GoGoldenCorpusTest in fory-core writes them and then runs
+// this test.
+func TestJavaFixtures(t *testing.T) {
+	dir := os.Getenv("FORY_JAVA_GOLDEN_DIR")
+	if dir == "" {
+		t.Skip("FORY_JAVA_GOLDEN_DIR not set")
+	}
+	for _, c := range Cases() {
+		t.Run(c.Name, func(t *testing.T) {
+			data, err := os.ReadFile(FilePath(dir, c.Name))
+			require.NoError(t, err, "GoGoldenCorpusTest writes every case")
+			var buffers [][]byte
+			if c.OutOfBand {
+				raw, err := os.ReadFile(BuffersPath(dir, c.Name))
+				require.NoError(t, err)
+				buffers, err = DecodeBuffers(raw)
+				require.NoError(t, err)
+			}
+			require.NoError(t, c.Decode(data, buffers))
+		})
+	}
+}
@@ -326,6 +326,8 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 				kvHeader := uint8(0)
 				isRefTracking := ctx.TrackRef()
 				_ = isRefTracking // Mark as used to avoid warning
+				kvHeader |= 0x4   // key type declared
+				kvHeader |= 0x20  // value type declared
 				chunkSize := 0
 				_ = buf.WriterIndex()         // chunkHeaderOffset
 				buf.WriteInt8(int8(kvHeader)) // KV header
@@ -364,6 +366,8 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 					kvHeader := uint8(0)
 					isRefTracking := ctx.TrackRef()
 					_ = isRefTracking // Mark as used to avoid warning
+					kvHeader |= 0x4   // key type declared
+					kvHeader |= 0x20  // value type declared
 					chunkSize := 0
 					_ = buf.WriterIndex()         // chunkHeaderOffset
 					buf.WriteInt8(int8(kvHeader)) // KV header
@@ -410,6 +414,8 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 				if isRefTracking {
 					kvHeader |= 0x1 // track key ref
 				}
+				kvHeader |= 0x4  // key type declared
+				kvHeader |= 0x20 // value type declared
 				chunkSize := 0
 				_ = buf.WriterIndex()         // chunkHeaderOffset
 				buf.WriteInt8(int8(kvHeader)) // KV header
@@ -451,6 +457,8 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 					if isRefTracking {
 						kvHeader |= 0x1 // track key ref
 					}
+					kvHeader |= 0x4  // key type declared
+					kvHeader |= 0x20 // value type declared
 					chunkSize := 0
 					_ = buf.WriterIndex()         // chunkHeaderOffset
 					buf.WriteInt8(int8(kvHeader)) // KV header
@@ -497,9 +505,11 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 				if isRefTracking {
 					kvHeader |= 0x1 // track key ref
 				}
+				kvHeader |= 0x4 // key type declared
 				if isRefTracking {
 					kvHeader |= 0x8 // track value ref
 				}
+				kvHeader |= 0x20 // value type declared
 				chunkSize := 0
 				_ = buf.WriterIndex()         // chunkHeaderOffset
 				buf.WriteInt8(int8(kvHeader)) // KV header
@@ -541,9 +551,11 @@ func (g *MapDemo_ForyGenSerializer) WriteTyped(ctx *fory.WriteContext, v *MapDem
 					if isRefTracking {
 						kvHeader |= 0x1 // track key ref
 					}
+					kvHeader |= 0x4 // key type declared
 					if isRefTracking {
 						kvHeader |= 0x8 // track value ref
 					}
+					kvHeader |= 0x20 // value type declared
 					chunkSize := 0
 					_ = buf.WriterIndex()         // chunkHeaderOffset
 					buf.WriteInt8(int8(kvHeader)) // KV header
@@ -654,13 +666,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 					kvHeader := buf.ReadByte(err)
 					chunkSize := int(buf.ReadByte(err))
 					trackKeyRef := (kvHeader & 0x1) != 0
-					keyNotDeclared := (kvHeader & 0x4) != 0
+					keyDeclared := (kvHeader & 0x4) != 0
 					trackValueRef := (kvHeader & 0x8) != 0
-					valueNotDeclared := (kvHeader & 0x20) != 0
+					valueDeclared := (kvHeader & 0x20) != 0
 					_ = trackKeyRef
-					_ = keyNotDeclared
+					_ = keyDeclared
 					_ = trackValueRef
-					_ = valueNotDeclared
+					_ = valueDeclared
 					for i := 0; i < chunkSize; i++ {
 						var mapKey int
 						mapKey = int(buf.ReadInt64(err))
@@ -688,13 +700,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 						kvHeader := buf.ReadByte(err)
 						chunkSize := int(buf.ReadByte(err))
 						trackKeyRef := (kvHeader & 0x1) != 0
-						keyNotDeclared := (kvHeader & 0x4) != 0
+						keyDeclared := (kvHeader & 0x4) != 0
 						trackValueRef := (kvHeader & 0x8) != 0
-						valueNotDeclared := (kvHeader & 0x20) != 0
+						valueDeclared := (kvHeader & 0x20) != 0
 						_ = trackKeyRef
-						_ = keyNotDeclared
+						_ = keyDeclared
 						_ = trackValueRef
-						_ = valueNotDeclared
+						_ = valueDeclared
 						for i := 0; i < chunkSize; i++ {
 							var mapKey int
 							mapKey = int(buf.ReadInt64(err))
@@ -724,13 +736,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 					kvHeader := buf.ReadByte(err)
 					chunkSize := int(buf.ReadByte(err))
 					trackKeyRef := (kvHeader & 0x1) != 0
-					keyNotDeclared := (kvHeader & 0x4) != 0
+					keyDeclared := (kvHeader & 0x4) != 0
 					trackValueRef := (kvHeader & 0x8) != 0
-					valueNotDeclared := (kvHeader & 0x20) != 0
+					valueDeclared := (kvHeader & 0x20) != 0
 					_ = trackKeyRef
-					_ = keyNotDeclared
+					_ = keyDeclared
 					_ = trackValueRef
-					_ = valueNotDeclared
+					_ = valueDeclared
 					for i := 0; i < chunkSize; i++ {
 						var mapKey string
 						mapKey = ctx.ReadString()
@@ -758,13 +770,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 						kvHeader := buf.ReadByte(err)
 						chunkSize := int(buf.ReadByte(err))
 						trackKeyRef := (kvHeader & 0x1) != 0
-						keyNotDeclared := (kvHeader & 0x4) != 0
+						keyDeclared := (kvHeader & 0x4) != 0
 						trackValueRef := (kvHeader & 0x8) != 0
-						valueNotDeclared := (kvHeader & 0x20) != 0
+						valueDeclared := (kvHeader & 0x20) != 0
 						_ = trackKeyRef
-						_ = keyNotDeclared
+						_ = keyDeclared
 						_ = trackValueRef
-						_ = valueNotDeclared
+						_ = valueDeclared
 						for i := 0; i < chunkSize; i++ {
 							var mapKey string
 							mapKey = ctx.ReadString()
@@ -794,13 +806,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 					kvHeader := buf.ReadByte(err)
 					chunkSize := int(buf.ReadByte(err))
 					trackKeyRef := (kvHeader & 0x1) != 0
-					keyNotDeclared := (kvHeader & 0x4) != 0
+					keyDeclared := (kvHeader & 0x4) != 0
 					trackValueRef := (kvHeader & 0x8) != 0
-					valueNotDeclared := (kvHeader & 0x20) != 0
+					valueDeclared := (kvHeader & 0x20) != 0
 					_ = trackKeyRef
-					_ = keyNotDeclared
+					_ = keyDeclared
 					_ = trackValueRef
-					_ = valueNotDeclared
+					_ = valueDeclared
 					for i := 0; i < chunkSize; i++ {
 						var mapKey string
 						mapKey = ctx.ReadString()
@@ -828,13 +840,13 @@ func (g *MapDemo_ForyGenSerializer) ReadTyped(ctx *fory.ReadContext, v *MapDemo)
 						kvHeader := buf.ReadByte(err)
 						chunkSize := int(buf.ReadByte(err))
 						trackKeyRef := (kvHeader & 0x1) != 0
-						keyNotDeclared := (kvHeader & 0x4) != 0
+						keyDeclared := (kvHeader & 0x4) != 0
 						trackValueRef := (kvHeader & 0x8) != 0
-						valueNotDeclared := (kvHeader & 0x20) != 0
+						valueDeclared := (kvHeader & 0x20) != 0
 						_ = trackKeyRef
-						_ = keyNotDeclared
+						_ = keyDeclared
 						_ = trackValueRef
-						_ = valueNotDeclared
+						_ = valueDeclared
 						for i := 0; i < chunkSize; i++ {
 							var mapKey string
 							mapKey = ctx.ReadString()
@@ -19,15 +19,41 @@
 package threadsafe
 
 import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"iter"
+	"runtime"
 	"sync"
+	"sync/atomic"
 
 	"github.com/apache/fory/go/fory"
 )
 
 // Fory is a thread-safe wrapper around fory.Fory using sync.Pool.
 // It provides the same API as fory.Fory but is safe for concurrent use.
+// Types may be registered at any time, including while other goroutines
+// serialize; a registration applies to every operation that starts after it
+// returns.
 type Fory struct {
-	pool sync.Pool
+	pool    sync.Pool
+	factory func() *fory.Fory
+
+	// Registrations are validated against registry, which has every earlier
+	// registration applied, then appended to a copy-on-write log that pooled
+	// instances replay when acquired.
+	mu            sync.Mutex
+	registry      *fory.Fory
+	registrations atomic.Pointer[[]registration]
+}
+
+type registration func(*fory.Fory) error
+
+// instance is a pooled fory.Fory with the number of registrations applied to it.
+type instance struct {
+	*fory.Fory
+	applied int
 }
 
 // New creates a new thread-safe Fory instance.
@@ -42,35 +68,74 @@ func NewWithFactory(factory func() *fory.Fory) *Fory {
 	if factory == nil {
 		panic("threadsafe.NewWithFactory requires a non-nil factory")
 	}
-	f := &Fory{}
+	f := &Fory{factory: factory}
 	f.pool = sync.Pool{
 		New: func() any {
-			inner := factory()
-			if inner == nil {
-				panic("threadsafe.NewWithFactory factory returned nil")
-			}
-			return inner
+			return &instance{Fory: f.newInner()}
 		},
 	}
 	return f
 }
 
-func (f *Fory) acquire() *fory.Fory {
-	return f.pool.Get().(*fory.Fory)
+func (f *Fory) newInner() *fory.Fory {
+	inner := f.factory()
+	if inner == nil {
+		panic("threadsafe.NewWithFactory factory returned nil")
+	}
+	return inner
+}
+
+func (f *Fory) acquire() (*instance, error) {
+	inner := f.pool.Get().(*instance)
+	if regs := f.registrations.Load(); regs != nil && inner.applied < len(*regs) {
+		for _, register := range (*regs)[inner.applied:] {
+			// Registrations that succeeded on f.registry can still fail here
+			// when they depend on global state that has changed since. The
+			// instance is then missing one, so it is not returned to the pool.
+			if err := register(inner.Fory); err != nil {
+				return nil, fmt.Errorf("cannot apply registrations to a pooled instance: %w", err)
+			}
+			inner.applied++
+		}
+	}
+	return inner, nil
 }
 
-func (f *Fory) release(inner *fory.Fory) {
+func (f *Fory) release(inner *instance) {
 	inner.Reset()
 	f.pool.Put(inner)
 }
 
+// register applies r to every pooled instance, or returns its error without
+// applying it anywhere.
+func (f *Fory) register(r registration) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if f.registry == nil {
+		f.registry = f.newInner()
+	}
+	if err := r(f.registry); err != nil {
+		return err
+	}
+	var regs []registration
+	if current := f.registrations.Load(); current != nil {
+		regs = *current
+	}
+	next := append(regs[:len(regs):len(regs)], r)
+	f.registrations.Store(&next)
+	return nil
+}
+
 // ============================================================================
 // Non-generic methods
 // ============================================================================
 
 // Serialize serializes a value using a pooled Fory instance
 func (f *Fory) Serialize(v any) ([]byte, error) {
-	inner := f.acquire()
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
 	data, err := inner.Serialize(v)
 	if err != nil {
 		f.release(inner)
@@ -83,18 +148,336 @@ func (f *Fory) Serialize(v any) ([]byte, error) {
 	return result, nil
 }
 
+// Marshal serializes v using a pooled Fory instance with per-call options.
+// The result is always owned by the caller.
+func (f *Fory) Marshal(v any, opts ...fory.MarshalOption) ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	// A later WithBuffer in opts overrides this one.
+	return inner.Marshal(v, append([]fory.MarshalOption{fory.WithBuffer(nil)}, opts...)...)
+}
+
+// MarshalContext is Marshal, stopping with an error once ctx is done. See
+// fory.Fory.MarshalContext. The result is always owned by the caller.
+func (f *Fory) MarshalContext(ctx context.Context, v any, opts ...fory.MarshalOption) ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	return inner.MarshalContext(ctx, v, append([]fory.MarshalOption{fory.WithBuffer(nil)}, opts...)...)
+}
+
+// MarshalParallel serializes v using a pooled Fory instance, splitting a large
+// top-level slice or map across up to GOMAXPROCS pooled instances. See
+// fory.Fory.MarshalParallel. The result is always owned by the caller.
+func (f *Fory) MarshalParallel(v any) ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	workers := make([]*fory.Fory, runtime.GOMAXPROCS(0)-1)
+	for i := range workers {
+		w, err := f.acquire()
+		if err != nil {
+			return nil, err
+		}
+		defer f.release(w)
+		workers[i] = w.Fory
+	}
+	data, err := inner.MarshalParallel(v, workers...)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(data), nil
+}
+
+// MarshalBatch serializes values into one payload using a pooled Fory
+// instance. See fory.Fory.MarshalBatch. The result is always owned by the
+// caller.
+func (f *Fory) MarshalBatch(values []any) ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	data, err := inner.MarshalBatch(values)
+	if err != nil {
+		return nil, err
+	}
+	return bytes.Clone(data), nil
+}
+
+// ReadBatch returns an iterator over the values of a payload written by
+// MarshalBatch. A pooled Fory instance is held while the loop runs. See
+// fory.Fory.ReadBatch.
+func (f *Fory) ReadBatch(data []byte) iter.Seq2[any, error] {
+	return func(yield func(any, error) bool) {
+		inner, err := f.acquire()
+		if err != nil {
+			yield(nil, err)
+			return
+		}
+		defer f.release(inner)
+		inner.ReadBatch(data)(yield)
+	}
+}
+
+// MarshalAppend appends the encoding of v to dst using a pooled Fory instance.
+// No copy is made since the result lives in the caller's slice.
+func (f *Fory) MarshalAppend(dst []byte, v any) ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return dst, err
+	}
+	defer f.release(inner)
+	return inner.MarshalAppend(dst, v)
+}
+
+// SizeOf returns the serialized size of v using a pooled Fory instance.
+func (f *Fory) SizeOf(v any) (int, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer f.release(inner)
+	return inner.SizeOf(v)
+}
+
 // Deserialize deserializes data into the provided value using a pooled Fory instance
 func (f *Fory) Deserialize(data []byte, v any) error {
-	inner := f.acquire()
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
 	defer f.release(inner)
 	return inner.Deserialize(data, v)
 }
 
-// RegisterStructByName registers a struct type by name for cross-language serialization.
-func (f *Fory) RegisterStructByName(type_ any, name string) error {
-	inner := f.acquire()
+// UnmarshalContext deserializes data into v using a pooled Fory instance,
+// stopping with an error once ctx is done. See fory.Fory.UnmarshalContext.
+func (f *Fory) UnmarshalContext(ctx context.Context, data []byte, v any) error {
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
+	defer f.release(inner)
+	return inner.UnmarshalContext(ctx, data, v)
+}
+
+// WriteFramed writes v to w as one length-prefixed frame using a pooled Fory
+// instance. See fory.Fory.WriteFramed.
+func (f *Fory) WriteFramed(w io.Writer, v any) error {
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
 	defer f.release(inner)
-	return inner.RegisterStructByName(type_, name)
+	return inner.WriteFramed(w, v)
+}
+
+// ReadFramed reads the next frame from r into v using a pooled Fory instance.
+// See fory.Fory.ReadFramed.
+func (f *Fory) ReadFramed(r io.Reader, v any) error {
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
+	defer f.release(inner)
+	return inner.ReadFramed(r, v)
+}
+
+// UnmarshalPartial deserializes data into v using a pooled Fory instance,
+// skipping values of unregistered types. See fory.Fory.UnmarshalPartial.
+func (f *Fory) UnmarshalPartial(data []byte, v any) ([]fory.UnknownTypeWarning, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	return inner.UnmarshalPartial(data, v)
+}
+
+// CheckHeader reports whether the pooled instances can decode a payload with header h.
+func (f *Fory) CheckHeader(h fory.Header) error {
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
+	defer f.release(inner)
+	return inner.CheckHeader(h)
+}
+
+// ExportSchemas returns the types registered with the pooled instances as JSON.
+// See fory.Fory.ExportSchemas.
+func (f *Fory) ExportSchemas() ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	return inner.ExportSchemas()
+}
+
+// SchemaDigest returns a hash of the schemas registered with the pooled
+// instances. See fory.Fory.SchemaDigest.
+func (f *Fory) SchemaDigest() (uint64, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return 0, err
+	}
+	defer f.release(inner)
+	return inner.SchemaDigest()
+}
+
+// CheckPeerDigest returns an error if digest differs from SchemaDigest.
+// See fory.Fory.CheckPeerDigest.
+func (f *Fory) CheckPeerDigest(digest uint64) error {
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
+	defer f.release(inner)
+	return inner.CheckPeerDigest(digest)
+}
+
+// ExportResolverState returns the struct, enum and alias registrations of the
+// pooled instances. See fory.Fory.ExportResolverState.
+func (f *Fory) ExportResolverState() ([]byte, error) {
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	defer f.release(inner)
+	return inner.ExportResolverState()
+}
+
+// ImportResolverState replays the registrations written by
+// ExportResolverState. See fory.Fory.ImportResolverState.
+func (f *Fory) ImportResolverState(state []byte, types ...any) error {
+	return f.register(func(inner *fory.Fory) error { return inner.ImportResolverState(state, types...) })
+}
+
+// Preheat builds the serializers for the types of values on a pooled
+// instance now, and on each instance the pool creates later when it is first
+// acquired. See fory.Fory.Preheat.
+func (f *Fory) Preheat(values ...any) error {
+	if err := f.register(func(inner *fory.Fory) error { return inner.Preheat(values...) }); err != nil {
+		return err
+	}
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
+	f.release(inner)
+	return nil
+}
+
+// RegisterStruct registers a struct type with a numeric ID. See fory.Fory.RegisterStruct.
+func (f *Fory) RegisterStruct(type_ any, typeID uint32, opts ...fory.RegisterOption) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterStruct(type_, typeID, opts...) })
+}
+
+// RegisterStructByName registers a struct type by name for cross-language serialization.
+func (f *Fory) RegisterStructByName(type_ any, name string, opts ...fory.RegisterOption) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterStructByName(type_, name, opts...) })
+}
+
+// RegisterStructsByName registers struct types by name under one namespace.
+// See fory.Fory.RegisterStructsByName.
+func (f *Fory) RegisterStructsByName(namespace string, types ...any) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterStructsByName(namespace, types...) })
+}
+
+// RegisterEnum registers an enum type with a numeric ID. See fory.Fory.RegisterEnum.
+func (f *Fory) RegisterEnum(type_ any, typeID uint32) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterEnum(type_, typeID) })
+}
+
+// RegisterEnumByOrdinal registers an enum type written by ordinal. See fory.Fory.RegisterEnumByOrdinal.
+func (f *Fory) RegisterEnumByOrdinal(type_ any, typeID uint32, values ...any) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterEnumByOrdinal(type_, typeID, values...) })
+}
+
+// RegisterEnumByName registers an enum type by name. See fory.Fory.RegisterEnumByName.
+func (f *Fory) RegisterEnumByName(type_ any, name string) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterEnumByName(type_, name) })
+}
+
+// RegisterExtension registers an extension type with a numeric ID. The
+// serializer is shared by all pooled instances and must be safe for concurrent
+// use. See fory.Fory.RegisterExtension.
+func (f *Fory) RegisterExtension(type_ any, typeID uint32, serializer fory.ExtensionSerializer) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterExtension(type_, typeID, serializer) })
+}
+
+// RegisterExtensionByName registers an extension type by name. The serializer
+// is shared by all pooled instances and must be safe for concurrent use. See
+// fory.Fory.RegisterExtensionByName.
+func (f *Fory) RegisterExtensionByName(type_ any, name string, serializer fory.ExtensionSerializer) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterExtensionByName(type_, name, serializer) })
+}
+
+// RegisterDecimal registers the decimal type of another package as the
+// DECIMAL type. The converter is shared by all pooled instances and must be
+// safe for concurrent use. See fory.Fory.RegisterDecimal.
+func (f *Fory) RegisterDecimal(type_ any, converter fory.DecimalConverter) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterDecimal(type_, converter) })
+}
+
+// RegisterUnion registers a union type with a numeric ID. The serializer is
+// shared by all pooled instances and must be safe for concurrent use. See
+// fory.Fory.RegisterUnion.
+func (f *Fory) RegisterUnion(type_ any, typeID uint32, serializer fory.Serializer) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterUnion(type_, typeID, serializer) })
+}
+
+// RegisterUnionByName registers a union type by name. The serializer is
+// shared by all pooled instances and must be safe for concurrent use. See
+// fory.Fory.RegisterUnionByName.
+func (f *Fory) RegisterUnionByName(type_ any, name string, serializer fory.Serializer) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterUnionByName(type_, name, serializer) })
+}
+
+// RegisterTypeAlias maps an old registered name to a type registered by name.
+// See fory.Fory.RegisterTypeAlias.
+func (f *Fory) RegisterTypeAlias(oldName string, newType any) error {
+	return f.register(func(inner *fory.Fory) error { return inner.RegisterTypeAlias(oldName, newType) })
+}
+
+// ReplaceSerializer swaps the serializer of a registered extension type. The
+// serializer is shared by all pooled instances and must be safe for concurrent
+// use. Operations already running keep the old serializer; every operation
+// that starts after ReplaceSerializer returns uses the new one. See
+// fory.Fory.ReplaceSerializer.
+func (f *Fory) ReplaceSerializer(type_ any, serializer fory.ExtensionSerializer) error {
+	return f.register(func(inner *fory.Fory) error { return inner.ReplaceSerializer(type_, serializer) })
+}
+
+// Unregister removes the registration of type_. Operations already running
+// may still use the type; every operation that starts after Unregister returns
+// treats it as unregistered. See fory.Fory.Unregister.
+func (f *Fory) Unregister(type_ any) error {
+	return f.register(func(inner *fory.Fory) error { return inner.Unregister(type_) })
+}
+
+// Clone returns a new thread-safe instance with the same factory and
+// registrations. It returns an error if a registration cannot be replayed on
+// the new instance. See fory.Fory.Clone.
+func (f *Fory) Clone() (*Fory, error) {
+	c := NewWithFactory(f.factory)
+	if regs := f.registrations.Load(); regs != nil {
+		for _, r := range *regs {
+			if err := c.register(r); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return c, nil
 }
 
 // ============================================================================
@@ -104,8 +487,11 @@ func (f *Fory) RegisterStructByName(type_ any, name string) error {
 // Serialize serializes a value with type T inferred, thread-safe.
 // Takes pointer to avoid interface heap allocation and struct copy.
 func Serialize[T any](f *Fory, value *T) ([]byte, error) {
-	inner := f.acquire()
-	data, err := fory.Serialize(inner, value)
+	inner, err := f.acquire()
+	if err != nil {
+		return nil, err
+	}
+	data, err := fory.Serialize(inner.Fory, value)
 	if err != nil {
 		f.release(inner)
 		return nil, err
@@ -120,32 +506,133 @@ func Serialize[T any](f *Fory, value *T) ([]byte, error) {
 // Deserialize deserializes data directly into the provided target, thread-safe.
 // Takes pointer to avoid interface heap allocation and enable direct writes.
 func Deserialize[T any](f *Fory, data []byte, target *T) error {
-	inner := f.acquire()
+	inner, err := f.acquire()
+	if err != nil {
+		return err
+	}
 	defer f.release(inner)
-	return fory.Deserialize(inner, data, target)
+	return fory.Deserialize(inner.Fory, data, target)
 }
 
 // ============================================================================
 // Global convenience functions
 // ============================================================================
 
-// Global thread-safe Fory instance for convenience
-var globalFory = New()
+// defaultFory is the instance used by the package-level functions.
+var defaultFory atomic.Pointer[Fory]
+
+func init() {
+	defaultFory.Store(New())
+}
+
+// Default returns the instance used by the package-level functions.
+func Default() *Fory {
+	return defaultFory.Load()
+}
+
+// SetDefault replaces the instance used by the package-level functions, so
+// they can use a custom configuration. Types registered with the previous
+// default are not carried over. Calls already in progress finish with the
+// previous instance. SetDefault panics if f is nil.
+func SetDefault(f *Fory) {
+	if f == nil {
+		panic("threadsafe.SetDefault requires a non-nil instance")
+	}
+	defaultFory.Store(f)
+}
 
-// Marshal serializes a value using the global thread-safe instance.
+// Marshal serializes a value using the default instance.
 // Takes pointer to avoid interface heap allocation and struct copy.
 func Marshal[T any](value *T) ([]byte, error) {
-	return Serialize(globalFory, value)
+	return Serialize(Default(), value)
 }
 
-// Unmarshal deserializes data into the provided target using the global thread-safe instance.
+// Unmarshal deserializes data into the provided target using the default instance.
 // Takes pointer to avoid interface heap allocation and enable direct writes.
 func Unmarshal[T any](data []byte, target *T) error {
-	return Deserialize(globalFory, data, target)
+	return Deserialize(Default(), data, target)
 }
 
-// UnmarshalTo deserializes data into the provided pointer using the global thread-safe instance.
+// UnmarshalTo deserializes data into the provided pointer using the default instance.
 // This is for non-generic use cases.
 func UnmarshalTo(data []byte, v any) error {
-	return globalFory.Deserialize(data, v)
+	return Default().Deserialize(data, v)
+}
+
+// RegisterStruct registers a struct type with the default instance.
+// See Fory.RegisterStruct.
+func RegisterStruct(type_ any, typeID uint32, opts ...fory.RegisterOption) error {
+	return Default().RegisterStruct(type_, typeID, opts...)
+}
+
+// RegisterStructByName registers a struct type by name with the default
+// instance. See Fory.RegisterStructByName.
+func RegisterStructByName(type_ any, name string, opts ...fory.RegisterOption) error {
+	return Default().RegisterStructByName(type_, name, opts...)
+}
+
+// RegisterStructsByName registers struct types by name with the default
+// instance. See Fory.RegisterStructsByName.
+func RegisterStructsByName(namespace string, types ...any) error {
+	return Default().RegisterStructsByName(namespace, types...)
+}
+
+// RegisterEnum registers an enum type with the default instance.
+// See Fory.RegisterEnum.
+func RegisterEnum(type_ any, typeID uint32) error {
+	return Default().RegisterEnum(type_, typeID)
+}
+
+// RegisterEnumByOrdinal registers an enum type written by ordinal with the
+// default instance. See Fory.RegisterEnumByOrdinal.
+func RegisterEnumByOrdinal(type_ any, typeID uint32, values ...any) error {
+	return Default().RegisterEnumByOrdinal(type_, typeID, values...)
+}
+
+// RegisterEnumByName registers an enum type by name with the default instance.
+// See Fory.RegisterEnumByName.
+func RegisterEnumByName(type_ any, name string) error {
+	return Default().RegisterEnumByName(type_, name)
+}
+
+// RegisterExtension registers an extension type with the default instance.
+// See Fory.RegisterExtension.
+func RegisterExtension(type_ any, typeID uint32, serializer fory.ExtensionSerializer) error {
+	return Default().RegisterExtension(type_, typeID, serializer)
+}
+
+// RegisterExtensionByName registers an extension type by name with the
+// default instance. See Fory.RegisterExtensionByName.
+func RegisterExtensionByName(type_ any, name string, serializer fory.ExtensionSerializer) error {
+	return Default().RegisterExtensionByName(type_, name, serializer)
+}
+
+// RegisterUnion registers a union type with the default instance.
+// See Fory.RegisterUnion.
+func RegisterUnion(type_ any, typeID uint32, serializer fory.Serializer) error {
+	return Default().RegisterUnion(type_, typeID, serializer)
+}
+
+// RegisterUnionByName registers a union type by name with the default
+// instance. See Fory.RegisterUnionByName.
+func RegisterUnionByName(type_ any, name string, serializer fory.Serializer) error {
+	return Default().RegisterUnionByName(type_, name, serializer)
+}
+
+// RegisterTypeAlias maps an old registered name to a type registered by name
+// with the default instance. See Fory.RegisterTypeAlias.
+func RegisterTypeAlias(oldName string, newType any) error {
+	return Default().RegisterTypeAlias(oldName, newType)
+}
+
+// ReplaceSerializer swaps the serializer of an extension type registered with
+// the default instance. See Fory.ReplaceSerializer.
+func ReplaceSerializer(type_ any, serializer fory.ExtensionSerializer) error {
+	return Default().ReplaceSerializer(type_, serializer)
+}
+
+// Unregister removes the registration of type_ from the default instance.
+// See Fory.Unregister.
+func Unregister(type_ any) error {
+	return Default().Unregister(type_)
 }
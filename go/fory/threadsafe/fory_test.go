@@ -18,9 +18,14 @@
 package threadsafe
 
 import (
+	"context"
+	"errors"
+	"reflect"
+	"sync"
 	"testing"
 
 	"github.com/apache/fory/go/fory"
+	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
 
@@ -38,6 +43,99 @@ func TestFory(t *testing.T) {
 		require.Equal(t, int32(42), result)
 	})
 
+	t.Run("MarshalAppend", func(t *testing.T) {
+		data, err := f.MarshalAppend([]byte{0xFF}, int32(42))
+		require.NoError(t, err)
+		require.Equal(t, byte(0xFF), data[0])
+
+		var result int32
+		err = f.Deserialize(data[1:], &result)
+		require.NoError(t, err)
+		require.Equal(t, int32(42), result)
+	})
+
+	t.Run("MarshalOptions", func(t *testing.T) {
+		value := map[string]int32{"b": 2, "a": 1, "c": 3}
+		first, err := f.Marshal(value, fory.WithSortedMaps())
+		require.NoError(t, err)
+		second, err := f.Marshal(value, fory.WithSortedMaps())
+		require.NoError(t, err)
+		require.Equal(t, first, second)
+
+		untracked, err := f.Marshal(value, fory.WithRefTrackingDisabled())
+		require.NoError(t, err)
+		var decoded map[string]int32
+		require.NoError(t, f.Deserialize(untracked, &decoded))
+		require.Equal(t, value, decoded)
+
+		appended, err := f.Marshal(value, fory.WithSortedMaps(), fory.WithBuffer([]byte{0xFF}))
+		require.NoError(t, err)
+		require.Equal(t, append([]byte{0xFF}, first...), appended)
+
+		var result map[string]int32
+		require.NoError(t, f.Deserialize(first, &result))
+		require.Equal(t, value, result)
+	})
+
+	t.Run("MarshalParallel", func(t *testing.T) {
+		f := New(fory.WithXlang(true))
+		require.NoError(t, f.RegisterStruct(lateOrder{}, 30))
+		items := make([]lateOrder, 5000)
+		for i := range items {
+			items[i] = lateOrder{ID: int64(i)}
+		}
+		want, err := f.Serialize(items)
+		require.NoError(t, err)
+		got, err := f.MarshalParallel(items)
+		require.NoError(t, err)
+		require.Equal(t, want, got)
+	})
+
+	t.Run("MarshalBatch", func(t *testing.T) {
+		values := []any{"a", int64(1), "b"}
+		data, err := f.MarshalBatch(values)
+		require.NoError(t, err)
+		var got []any
+		for value, err := range f.ReadBatch(data) {
+			require.NoError(t, err)
+			got = append(got, value)
+		}
+		require.Equal(t, values, got)
+	})
+
+	t.Run("SizeOf", func(t *testing.T) {
+		size, err := f.SizeOf("hello")
+		require.NoError(t, err)
+		data, err := f.Serialize("hello")
+		require.NoError(t, err)
+		require.Equal(t, len(data), size)
+	})
+
+	t.Run("UnmarshalPartial", func(t *testing.T) {
+		data, err := f.Serialize("hello")
+		require.NoError(t, err)
+		var result string
+		warnings, err := f.UnmarshalPartial(data, &result)
+		require.NoError(t, err)
+		require.Empty(t, warnings)
+		require.Equal(t, "hello", result)
+	})
+
+	t.Run("CheckHeader", func(t *testing.T) {
+		data, err := f.Serialize("hello")
+		require.NoError(t, err)
+		header, err := fory.ParseHeader(data)
+		require.NoError(t, err)
+		require.NoError(t, f.CheckHeader(header))
+	})
+
+	t.Run("SchemaDigest", func(t *testing.T) {
+		digest, err := f.SchemaDigest()
+		require.NoError(t, err)
+		require.NoError(t, f.CheckPeerDigest(digest))
+		require.Error(t, f.CheckPeerDigest(digest+1))
+	})
+
 	t.Run("GenericSerialization", func(t *testing.T) {
 		val := "hello world"
 		data, err := Serialize(f, &val)
@@ -171,7 +269,7 @@ func TestGlobalFunctions(t *testing.T) {
 
 	t.Run("UnmarshalTo", func(t *testing.T) {
 		// Use non-generic Serialize for compatibility with non-generic UnmarshalTo
-		data, err := globalFory.Serialize("hello")
+		data, err := Default().Serialize("hello")
 		require.NoError(t, err)
 
 		var result string
@@ -180,3 +278,222 @@ func TestGlobalFunctions(t *testing.T) {
 		require.Equal(t, "hello", result)
 	})
 }
+
+func TestSetDefault(t *testing.T) {
+	previous := Default()
+	t.Cleanup(func() { SetDefault(previous) })
+
+	SetDefault(New(fory.WithXlang(true), fory.WithRefTracking(true)))
+	require.NoError(t, RegisterStructByName(lateUser{}, "example.User"))
+	require.Error(t, RegisterStructByName(lateUser{}, "example.User"))
+	user := &lateUser{Name: "ann"}
+	data, err := Marshal(user)
+	require.NoError(t, err)
+	var one lateUser
+	require.NoError(t, Unmarshal(data, &one))
+	require.Equal(t, *user, one)
+	data, err = Default().Serialize([]*lateUser{user, user})
+	require.NoError(t, err)
+	var out []*lateUser
+	require.NoError(t, UnmarshalTo(data, &out))
+	require.Len(t, out, 2)
+	require.Same(t, out[0], out[1])
+
+	// Registrations stay with the instance they were made on.
+	SetDefault(New(fory.WithXlang(true)))
+	_, err = Marshal(user)
+	require.Error(t, err)
+	require.Panics(t, func() { SetDefault(nil) })
+}
+
+type lateUser struct {
+	Name string
+}
+
+type lateOrder struct {
+	ID int64
+}
+
+type latePoint struct {
+	X int32
+}
+
+type latePointSerializer struct {
+	offset int32
+}
+
+func (s latePointSerializer) WriteData(ctx *fory.WriteContext, value reflect.Value) {
+	ctx.Buffer().WriteInt32(int32(reflect.Indirect(value).Field(0).Int()) + s.offset)
+}
+
+func (s latePointSerializer) ReadData(ctx *fory.ReadContext, value reflect.Value) {
+	reflect.Indirect(value).Field(0).SetInt(int64(ctx.Buffer().ReadInt32(ctx.Err()) - s.offset))
+}
+
+func TestLateRegistration(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(lateUser{}, "example.User"))
+
+	var wg sync.WaitGroup
+	stop := make(chan struct{})
+	for i := 0; i < 4; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for {
+				select {
+				case <-stop:
+					return
+				default:
+				}
+				data, err := f.Serialize(&lateUser{Name: "ann"})
+				assert.NoError(t, err)
+				var out lateUser
+				assert.NoError(t, f.Deserialize(data, &out))
+			}
+		}()
+	}
+	require.NoError(t, f.RegisterStruct(lateOrder{}, 7))
+	// Registering again fails on the shared registry and is not replayed.
+	require.Error(t, f.RegisterStruct(lateOrder{}, 8))
+
+	// Every pooled instance, including ones created after the registration,
+	// sees the new type.
+	for i := 0; i < 100; i++ {
+		data, err := f.Serialize(&lateOrder{ID: int64(i)})
+		require.NoError(t, err)
+		var out lateOrder
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, int64(i), out.ID)
+	}
+	close(stop)
+	wg.Wait()
+}
+
+func TestRegisterOptions(t *testing.T) {
+	f := New(fory.WithXlang(true), fory.WithRefTracking(true))
+	require.NoError(t, f.RegisterStructByName(lateUser{}, "example.User", fory.WithNonReferencable()))
+	shared := &lateUser{Name: "ann"}
+	data, err := f.Serialize([]any{shared, shared})
+	require.NoError(t, err)
+	var out []any
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Len(t, out, 2)
+	require.NotSame(t, out[0], out[1])
+	require.Equal(t, shared, out[1])
+}
+
+func TestUnregisterAndReplace(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	require.NoError(t, f.RegisterStruct(lateOrder{}, 7))
+	require.NoError(t, f.RegisterExtension(latePoint{}, 8, latePointSerializer{}))
+	point, err := f.Serialize(&latePoint{X: 1})
+	require.NoError(t, err)
+
+	require.NoError(t, f.ReplaceSerializer(latePoint{}, latePointSerializer{offset: 100}))
+	require.NoError(t, f.Unregister(lateOrder{}))
+	// The registry rejects what no pooled instance could apply.
+	require.Error(t, f.Unregister(lateOrder{}))
+
+	for i := 0; i < 100; i++ {
+		data, err := f.Serialize(&latePoint{X: 1})
+		require.NoError(t, err)
+		require.NotEqual(t, point, data)
+		var out latePoint
+		require.NoError(t, f.Deserialize(data, &out))
+		require.Equal(t, latePoint{X: 1}, out)
+		_, err = f.Serialize(&lateOrder{ID: 1})
+		require.Error(t, err)
+	}
+	require.NoError(t, f.RegisterStruct(lateOrder{}, 9))
+	c, err := f.Clone()
+	require.NoError(t, err)
+	_, err = c.Serialize(&lateOrder{ID: 1})
+	require.NoError(t, err)
+}
+
+func TestClone(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(lateUser{}, "example.User"))
+	c, err := f.Clone()
+	require.NoError(t, err)
+	require.NoError(t, c.RegisterStruct(lateOrder{}, 7))
+
+	data, err := c.Serialize(&lateUser{Name: "ann"})
+	require.NoError(t, err)
+	var out lateUser
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, "ann", out.Name)
+	_, err = f.Serialize(&lateOrder{ID: 1})
+	require.Error(t, err)
+}
+
+func TestRegistrationReplayErrors(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	// Succeeds on the registry instance only, like a registration whose
+	// global state changed after it was made.
+	var calls int
+	require.NoError(t, f.register(func(*fory.Fory) error {
+		if calls++; calls > 1 {
+			return errors.New("stale global state")
+		}
+		return nil
+	}))
+	_, err := f.Serialize("hello")
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "stale global state")
+	c, err := f.Clone()
+	require.Nil(t, c)
+	require.Error(t, err)
+}
+
+func TestResolverState(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	require.NoError(t, f.RegisterStructByName(lateUser{}, "example.User"))
+	require.NoError(t, f.RegisterStruct(lateOrder{}, 7))
+	state, err := f.ExportResolverState()
+	require.NoError(t, err)
+
+	g := New(fory.WithXlang(true))
+	require.NoError(t, g.ImportResolverState(state, lateUser{}, lateOrder{}))
+	data, err := g.Serialize(&lateUser{Name: "ann"})
+	require.NoError(t, err)
+	var out lateUser
+	require.NoError(t, f.Deserialize(data, &out))
+	require.Equal(t, "ann", out.Name)
+	c, err := g.Clone()
+	require.NoError(t, err)
+	data, err = c.Serialize(&lateOrder{ID: 1})
+	require.NoError(t, err)
+	var order lateOrder
+	require.NoError(t, f.Deserialize(data, &order))
+	require.Equal(t, lateOrder{ID: 1}, order)
+}
+
+func TestPreheat(t *testing.T) {
+	f := New(fory.WithXlang(true))
+	require.NoError(t, f.RegisterStruct(lateOrder{}, 7))
+	require.NoError(t, f.Preheat(&lateOrder{}))
+	require.Error(t, f.Preheat(lateUser{}))
+	data, err := f.Serialize(&lateOrder{ID: 2})
+	require.NoError(t, err)
+	var order lateOrder
+	require.NoError(t, f.Deserialize(data, &order))
+	require.Equal(t, lateOrder{ID: 2}, order)
+}
+
+func TestMarshalContext(t *testing.T) {
+	f := New()
+	values := []int64{1, 2, 3}
+	data, err := f.MarshalContext(context.Background(), values)
+	require.NoError(t, err)
+	var out []int64
+	require.NoError(t, f.UnmarshalContext(context.Background(), data, &out))
+	require.Equal(t, values, out)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = f.MarshalContext(ctx, values)
+	require.ErrorIs(t, err, context.Canceled)
+	require.ErrorIs(t, f.UnmarshalContext(ctx, data, &out), context.Canceled)
+}
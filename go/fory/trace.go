@@ -0,0 +1,64 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+package fory
+
+import (
+	"context"
+	"log/slog"
+	"reflect"
+	"strconv"
+)
+
+// Trace records are emitted at debug level, one per wire step, with the
+// buffer offset the step starts at. With compression or a checksum, offsets
+// are relative to the body rather than the payload.
+
+func traceTypeInfo(logger *slog.Logger, msg string, offset int, typeID uint32, type_ reflect.Type) {
+	attrs := [4]slog.Attr{
+		slog.Int("offset", offset),
+		slog.Uint64("type_id", uint64(typeID)),
+		slog.String("kind", typeIdName(TypeId(typeID))),
+	}
+	n := 3
+	if type_ != nil {
+		attrs[n] = slog.String("go_type", type_.String())
+		n++
+	}
+	logger.LogAttrs(context.Background(), slog.LevelDebug, msg, attrs[:n]...)
+}
+
+func traceRefFlag(logger *slog.Logger, msg string, offset int, flag int8) {
+	logger.LogAttrs(context.Background(), slog.LevelDebug, msg,
+		slog.Int("offset", offset),
+		slog.String("flag", refFlagName(flag)))
+}
+
+func refFlagName(flag int8) string {
+	switch flag {
+	case NullFlag:
+		return "null"
+	case RefFlag:
+		return "ref"
+	case NotNullValueFlag:
+		return "not-null"
+	case RefValueFlag:
+		return "ref-value"
+	default:
+		return strconv.Itoa(int(flag))
+	}
+}
@@ -0,0 +1,94 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type traceRecord struct {
+	Msg    string `json:"msg"`
+	Offset int    `json:"offset"`
+	TypeID *int   `json:"type_id"`
+	Kind   string `json:"kind"`
+	GoType string `json:"go_type"`
+	Flag   string `json:"flag"`
+}
+
+func readTraceRecords(t *testing.T, out *bytes.Buffer) []traceRecord {
+	var records []traceRecord
+	dec := json.NewDecoder(out)
+	for dec.More() {
+		var r traceRecord
+		require.NoError(t, dec.Decode(&r))
+		records = append(records, r)
+	}
+	return records
+}
+
+func TestTraceLogger(t *testing.T) {
+	var out bytes.Buffer
+	logger := slog.New(slog.NewJSONHandler(&out, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	f := New(WithXlang(true), WithRefTracking(true), WithTraceLogger(logger))
+	require.NoError(t, f.RegisterStructByName(recordEnvelope{}, "example.Envelope"))
+	shared := "shared"
+	data, err := f.Serialize(&recordEnvelope{Payload: &shared, Items: []any{int32(1), nil}})
+	require.NoError(t, err)
+	written := readTraceRecords(t, &out)
+
+	var env recordEnvelope
+	require.NoError(t, f.Deserialize(data, &env))
+	read := readTraceRecords(t, &out)
+
+	var kinds []string
+	for _, r := range written {
+		require.Less(t, r.Offset, len(data))
+		if r.Msg == "fory write type" {
+			require.NotNil(t, r.TypeID)
+			require.Equal(t, uint8(*r.TypeID), data[r.Offset], "%+v", r)
+			kinds = append(kinds, r.Kind)
+		} else {
+			require.Equal(t, "fory write ref flag", r.Msg)
+		}
+	}
+	require.Contains(t, kinds, "NAMED_COMPATIBLE_STRUCT")
+	require.Contains(t, kinds, "VARINT32")
+	require.Equal(t, "fory.recordEnvelope", written[1].GoType)
+
+	// Each step read back starts at the offset it was written at.
+	readOffsets := map[int]string{}
+	for _, r := range read {
+		readOffsets[r.Offset] = r.Kind + r.Flag
+	}
+	for _, r := range written {
+		require.Equal(t, r.Kind+r.Flag, readOffsets[r.Offset], "%+v", r)
+	}
+
+	// Records are dropped when the logger ignores debug level.
+	quiet := New(WithXlang(true), WithTraceLogger(slog.New(slog.NewJSONHandler(&out, nil))))
+	_, err = quiet.Serialize(int32(1))
+	require.NoError(t, err)
+	require.Zero(t, out.Len())
+}
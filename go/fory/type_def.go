@@ -26,16 +26,17 @@ import (
 )
 
 const (
-	META_SIZE_MASK     = 0xFF
-	COMPRESS_META_FLAG = 0b1 << 8
-	RESERVED_META_BITS = 0b111 << 9
-	NUM_HASH_BITS      = 52
+	META_SIZE_MASK      = 0xFF
+	COMPRESS_META_FLAG  = 0b1 << 8
+	OMIT_ZERO_META_FLAG = 0b1 << 9
+	RESERVED_META_BITS  = 0b11 << 10
+	NUM_HASH_BITS       = 52
 )
 
 /*
 TypeDef represents a transportable value object containing type information and field definitions.
 typeDef are layout as following:
-  - first 8 bytes: global header (52 bits metadata hash + 3 bits reserved + 1 bit compress flag + 8 bits meta size)
+  - first 8 bytes: global header (52 bits metadata hash + 2 bits reserved + 1 bit omit-zero flag + 1 bit compress flag + 8 bits meta size)
   - next 1 byte: kind header
   - next variable bytes: type id (varint) or ns name + type name
   - next variable bytes: field definitions (see below)
@@ -48,10 +49,13 @@ type TypeDef struct {
 	typeName       *MetaStringBytes
 	compressed     bool
 	registerByName bool
+	// omitZero marks struct values written with a field presence bitmap.
+	omitZero       bool
 	fieldDefs      []FieldDef
 	encoded        []byte
 	type_          reflect.Type
 	cachedTypeInfo *TypeInfo
+	record         *genericRecordLayout
 }
 
 func NewTypeDef(typeId uint32, userTypeId uint32, nsName, typeName *MetaStringBytes, registerByName, compressed bool, fieldDefs []FieldDef) *TypeDef {
@@ -226,6 +230,9 @@ func (td *TypeDef) buildTypeInfoWithResolver(resolver *TypeResolver) (TypeInfo,
 		if serializer == nil && resolver != nil {
 			serializer = resolver.getSerializerByTypeID(td.typeId)
 		}
+		if serializer == nil && type_ == nil && (TypeId(td.typeId) == ENUM || TypeId(td.typeId) == NAMED_ENUM) {
+			serializer = &unknownEnumSerializer{unknown: td.unknownTypeWarning(resolver)}
+		}
 		if serializer == nil {
 			return TypeInfo{}, fmt.Errorf("no serializer registered for TypeDef kind %d", td.typeId)
 		}
@@ -233,12 +240,18 @@ func (td *TypeDef) buildTypeInfoWithResolver(resolver *TypeResolver) (TypeInfo,
 		if type_ == nil {
 			// Unknown struct type - use skipStructSerializer to skip data
 			serializer = &skipStructSerializer{
+				typeDef:   td,
 				fieldDefs: td.fieldDefs,
+				unknown:   td.unknownTypeWarning(resolver),
 			}
 		} else {
 			// Known struct type - use structSerializer with fieldDefs
+			if err := checkReflectStructSerializer(type_); err != nil {
+				return TypeInfo{}, err
+			}
 			structSer := newStructSerializerFromTypeDef(type_, "", td.fieldDefs)
 			structSer.userTypeID = td.userTypeId
+			structSer.omitZero = td.omitZero
 			// Eagerly initialize the struct serializer with pre-computed field metadata
 			if resolver != nil {
 				if err := structSer.initialize(resolver); err != nil {
@@ -262,6 +275,13 @@ func (td *TypeDef) buildTypeInfoWithResolver(resolver *TypeResolver) (TypeInfo,
 	return info, nil
 }
 
+func (td *TypeDef) unknownTypeWarning(resolver *TypeResolver) UnknownTypeWarning {
+	if td.registerByName {
+		return newUnknownTypeWarning(resolver, td.typeId, 0, td.nsName, td.typeName)
+	}
+	return newUnknownTypeWarning(resolver, td.typeId, td.userTypeId, nil, nil)
+}
+
 func (td *TypeDef) getOrBuildTypeInfo(resolver *TypeResolver) (*TypeInfo, error) {
 	if td.cachedTypeInfo != nil {
 		return td.cachedTypeInfo, nil
@@ -396,6 +416,9 @@ func buildTypeDef(fory *Fory, value reflect.Value) (*TypeDef, error) {
 	}
 	registerByName := IsNamespacedType(TypeId(typeId))
 	typeDef := NewTypeDef(typeId, infoPtr.UserTypeID, infoPtr.PkgPathBytes, infoPtr.NameBytes, registerByName, false, fieldDefs)
+	if _, ok := infoPtr.Serializer.(*structSerializer); ok {
+		typeDef.omitZero = fory.typeResolver.omitZeroFields(value.Type())
+	}
 
 	// encoding the typeDef, and save the encoded bytes
 	encoded, err := encodingTypeDef(fory.typeResolver, typeDef)
@@ -460,7 +483,7 @@ func buildFieldDefs(fory *Fory, value reflect.Value) ([]FieldDef, error) {
 
 	type_ := value.Type()
 	for i := 0; i < type_.NumField(); i++ {
-		field := type_.Field(i)
+		field := fory.typeResolver.structField(type_, i)
 
 		// Skip unexported fields
 		if field.PkgPath != "" {
@@ -471,6 +494,7 @@ func buildFieldDefs(fory *Fory, value reflect.Value) ([]FieldDef, error) {
 		if err != nil {
 			return nil, err
 		}
+		fieldSpec.Name = fory.typeResolver.fieldName(field)
 		fieldSpec.Type = bindResolvedTypeSpec(fory.typeResolver, field.Type, fieldSpec.Type)
 		if fieldSpec.Ignore {
 			continue // skip ignored fields
@@ -672,7 +696,7 @@ func getFieldNameEncodingIndex(encoding meta.Encoding) int {
 /*
 encodingTypeDef encodes a TypeDef into binary format according to the specification
 typeDef are layout as following:
-- first 8 bytes: global header (52 bits metadata hash + 3 bits reserved + 1 bit compress flag + 8 bits meta size)
+- first 8 bytes: global header (52 bits metadata hash + 2 bits reserved + 1 bit omit-zero flag + 1 bit compress flag + 8 bits meta size)
 - next 1 byte: kind header
 - next variable bytes: type id (varint) or ns name + type name
 - next variable bytes: field defs (see below)
@@ -796,7 +820,26 @@ func encodingTypeDef(typeResolver *TypeResolver, typeDef *TypeDef) ([]byte, erro
 		return nil, fmt.Errorf("non-struct TypeDef %d cannot carry field metadata", typeDef.typeId)
 	}
 
-	result, err := prependGlobalHeader(buffer, false)
+	compressed := false
+	if c := typeResolver.metaCompressor(); c != nil {
+		// The xlang TypeDef format reserves the compress bit.
+		if typeResolver.isXlang {
+			return nil, fmt.Errorf("WithMetaCompressor requires WithXlang(false)")
+		}
+		body := buffer.GetByteSlice(0, buffer.WriterIndex())
+		packed, err := c.Compress(nil, body)
+		if err != nil {
+			return nil, fmt.Errorf("failed to compress type meta: %w", err)
+		}
+		if len(packed) < len(body) {
+			buffer = NewByteBuffer(packed)
+			buffer.SetWriterIndex(len(packed))
+			compressed = true
+		}
+	}
+	typeDef.compressed = compressed
+
+	result, err := prependGlobalHeader(buffer, compressed, typeDef.omitZero)
 	if err != nil {
 		return nil, fmt.Errorf("failed to write global binary header: %w", err)
 	}
@@ -805,7 +848,7 @@ func encodingTypeDef(typeResolver *TypeResolver, typeDef *TypeDef) ([]byte, erro
 }
 
 // prependGlobalHeader writes the 8-byte global header
-func prependGlobalHeader(buffer *ByteBuffer, isCompressed bool) (*ByteBuffer, error) {
+func prependGlobalHeader(buffer *ByteBuffer, isCompressed, omitZero bool) (*ByteBuffer, error) {
 	metaSize := buffer.WriterIndex()
 	headerLowBits := uint64(metaSize)
 	if metaSize >= META_SIZE_MASK {
@@ -814,6 +857,9 @@ func prependGlobalHeader(buffer *ByteBuffer, isCompressed bool) (*ByteBuffer, er
 	if isCompressed {
 		headerLowBits |= COMPRESS_META_FLAG
 	}
+	if omitZero {
+		headerLowBits |= OMIT_ZERO_META_FLAG
+	}
 	header := typeDefHeaderHash(buffer.GetByteSlice(0, metaSize), headerLowBits) | headerLowBits
 
 	result := NewByteBuffer(make([]byte, metaSize+8))
@@ -985,7 +1031,7 @@ func writeFieldDef(typeResolver *TypeResolver, buffer *ByteBuffer, field FieldDe
 /*
 decodeTypeDef decodes a TypeDef from the buffer
 typeDef are layout as following:
-  - first 8 bytes: global header (52 bits metadata hash + 3 bits reserved + 1 bit compress flag + 8 bits meta size)
+  - first 8 bytes: global header (52 bits metadata hash + 2 bits reserved + 1 bit omit-zero flag + 1 bit compress flag + 8 bits meta size)
   - next 1 byte: kind header
   - next variable bytes: type id (varint) or ns name + type name
   - next variable bytes: field definitions (see below)
@@ -998,8 +1044,11 @@ func decodeTypeDef(fory *Fory, buffer *ByteBuffer, header int64) (*TypeDef, erro
 		return nil, fmt.Errorf("invalid TypeDef global header")
 	}
 	isCompressed := (globalHeader & COMPRESS_META_FLAG) != 0
-	if isCompressed {
-		return nil, fmt.Errorf("compressed xlang TypeDef is not supported")
+	if isCompressed && fory.config.IsXlang {
+		return nil, fmt.Errorf("compressed TypeDef is not allowed in xlang mode")
+	}
+	if isCompressed && fory.config.MetaCompressor == nil {
+		return nil, fmt.Errorf("compressed TypeDef requires WithMetaCompressor")
 	}
 	metaSizeBits := int(globalHeader & META_SIZE_MASK)
 	metaSize := metaSizeBits
@@ -1024,7 +1073,15 @@ func decodeTypeDef(fory *Fory, buffer *ByteBuffer, header int64) (*TypeDef, erro
 	if bufErr.HasError() {
 		return nil, bufErr.TakeError()
 	}
-	metaBuffer := NewByteBuffer(encodedMeta)
+	metaBody := encodedMeta
+	if isCompressed {
+		body, err := fory.config.MetaCompressor.Decompress(nil, encodedMeta, fory.config.MaxBinarySize)
+		if err != nil {
+			return nil, fmt.Errorf("failed to decompress TypeDef: %w", err)
+		}
+		metaBody = body
+	}
+	metaBuffer := NewByteBuffer(metaBody)
 	var metaErr Error
 
 	// ReadData 1-byte meta header
@@ -1223,6 +1280,10 @@ func decodeTypeDef(fory *Fory, buffer *ByteBuffer, header int64) (*TypeDef, erro
 	if !isStruct && len(fieldInfos) != 0 {
 		return nil, fmt.Errorf("non-struct TypeDef cannot carry field metadata")
 	}
+	omitZero := (globalHeader & OMIT_ZERO_META_FLAG) != 0
+	if !isStruct && omitZero {
+		return nil, fmt.Errorf("non-struct TypeDef cannot omit zero fields")
+	}
 	if metaErr.HasError() {
 		return nil, metaErr.TakeError()
 	}
@@ -1239,6 +1300,7 @@ func decodeTypeDef(fory *Fory, buffer *ByteBuffer, header int64) (*TypeDef, erro
 	typeDef := NewTypeDef(typeId, userTypeId, nsBytes, nameBytes, registeredByName, isCompressed, fieldInfos)
 	typeDef.encoded = encoded
 	typeDef.type_ = type_
+	typeDef.omitZero = omitZero
 
 	if DebugOutputEnabled {
 		fmt.Printf("[Go TypeDef DECODED] %s\n", typeDef.String())
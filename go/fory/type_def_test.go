@@ -15,6 +15,8 @@
 // specific language governing permissions and limitations
 // under the License.
 
+//go:build !fory_codegen_only
+
 package fory
 
 import (
@@ -190,7 +192,7 @@ func typeDefTestFrame(t *testing.T, body []byte, compressed bool) (*ByteBuffer,
 	t.Helper()
 	bodyBuffer := NewByteBuffer(nil)
 	bodyBuffer.WriteBinary(body)
-	frame, err := prependGlobalHeader(bodyBuffer, compressed)
+	frame, err := prependGlobalHeader(bodyBuffer, compressed, false)
 	require.NoError(t, err)
 	readErr := &Error{}
 	header := frame.ReadInt64(readErr)
@@ -447,7 +449,7 @@ func TestTypeDefRejectsEncodedMetadataAboveMaxBinarySize(t *testing.T) {
 	require.Contains(t, err.Error(), "max binary size exceeded")
 }
 
-func TestTypeDefRejectsCompressedMetadata(t *testing.T) {
+func TestTypeDefCompressedMetadataNeedsCompressor(t *testing.T) {
 	decoded := typeDefTestBodyWithoutFields()
 	compressed := deflateTypeDefTestBody(t, decoded)
 	fory := NewFory(WithXlang(false), WithMaxBinarySize(4096), WithCompatible(false))
@@ -455,7 +457,13 @@ func TestTypeDefRejectsCompressedMetadata(t *testing.T) {
 
 	_, err := decodeTypeDef(fory, frame, header)
 	require.Error(t, err)
-	require.Contains(t, err.Error(), "compressed xlang TypeDef")
+	require.Contains(t, err.Error(), "compressed TypeDef")
+
+	fory = NewFory(WithXlang(false), WithMaxBinarySize(4096), WithCompatible(false), WithMetaCompressor(DeflateMetaCompressor{}))
+	frame, header = typeDefTestFrame(t, compressed, true)
+	td, err := decodeTypeDef(fory, frame, header)
+	require.NoError(t, err)
+	require.True(t, td.compressed)
 }
 
 func TestReadSharedTypeMetaCapsParsedTypeDefCache(t *testing.T) {
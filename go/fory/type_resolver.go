@@ -21,8 +21,9 @@ import (
 	"errors"
 	"fmt"
 	"hash/fnv"
+	"log/slog"
+	"maps"
 	"reflect"
-	"regexp"
 	"strconv"
 	"strings"
 	"sync"
@@ -57,6 +58,8 @@ const (
 	internalTypeIDLimit            = 0xFF
 	maxCachedTypeDefs              = 8192
 	maxCachedNamedTypeInfos        = 8192
+	// anonymousStructNamespace is the namespace of names derived for anonymous structs.
+	anonymousStructNamespace = "go.anonymous"
 )
 
 var (
@@ -155,7 +158,6 @@ type TypeInfo struct {
 	Serializer   Serializer
 	NeedWriteDef bool
 	NeedWriteRef bool // Whether this type needs reference tracking
-	hashValue    uint64
 	TypeDef      *TypeDef
 }
 type (
@@ -170,15 +172,23 @@ type nsTypeKey struct {
 type TypeResolver struct {
 	typeTagToSerializers map[string]Serializer
 	typeToSerializers    map[reflect.Type]Serializer
-	typeToTypeInfo       map[reflect.Type]string
-	typeToTypeTag        map[reflect.Type]string
-	typeInfoToType       map[string]reflect.Type
-	typeIdToType         map[TypeId]reflect.Type
-	dynamicStringToId    map[string]int16
-	dynamicIdToString    map[int16]string
-	dynamicStringId      int16
+	// declaredMapSerializers holds map serializers for struct fields and nested
+	// map entries, which omit key/value type info the reader already knows.
+	declaredMapSerializers map[reflect.Type]Serializer
+	typeToTypeInfo         map[reflect.Type]string
+	typeToTypeTag          map[reflect.Type]string
+	typeInfoToType         map[string]reflect.Type
+	typeIdToType           map[TypeId]reflect.Type
+	dynamicStringToId      map[string]int16
+	dynamicIdToString      map[int16]string
+	dynamicStringId        int16
+	typeHooks              map[reflect.Type]TypeHooks
+	globalHooks            TypeHooks
+	fieldTags              map[reflect.Type]map[string]string // Set with WithFieldTag
 
 	fory *Fory
+	// trace receives a record per type info read or written when set.
+	trace *slog.Logger
 	//metaStringResolver  MetaStringResolver
 	isXlang             bool
 	metaStringResolver  *MetaStringResolver
@@ -200,6 +210,9 @@ type TypeResolver struct {
 	typesInfo           map[reflect.Type]*TypeInfo
 	nsTypeToTypeInfo    map[nsTypeKey]*TypeInfo
 	namedTypeToTypeInfo map[namedTypeKey]*TypeInfo
+	// derivedTypeNames holds the names taken from the Go package path of
+	// generated and unregistered types, which explicit registrations replace.
+	derivedTypeNames map[namedTypeKey]bool
 
 	// Encoders/Decoders
 	namespaceEncoder *meta.Encoder
@@ -220,14 +233,15 @@ type TypeResolver struct {
 
 func newTypeResolver(fory *Fory) *TypeResolver {
 	r := &TypeResolver{
-		typeTagToSerializers: map[string]Serializer{},
-		typeToSerializers:    map[reflect.Type]Serializer{},
-		typeIdToType:         map[TypeId]reflect.Type{},
-		typeToTypeInfo:       map[reflect.Type]string{},
-		typeInfoToType:       map[string]reflect.Type{},
-		dynamicStringToId:    map[string]int16{},
-		dynamicIdToString:    map[int16]string{},
-		fory:                 fory,
+		typeTagToSerializers:   map[string]Serializer{},
+		typeToSerializers:      map[reflect.Type]Serializer{},
+		declaredMapSerializers: map[reflect.Type]Serializer{},
+		typeIdToType:           map[TypeId]reflect.Type{},
+		typeToTypeInfo:         map[reflect.Type]string{},
+		typeInfoToType:         map[string]reflect.Type{},
+		dynamicStringToId:      map[string]int16{},
+		dynamicIdToString:      map[int16]string{},
+		fory:                   fory,
 
 		isXlang:             fory.config.IsXlang,
 		metaStringResolver:  NewMetaStringResolver(),
@@ -246,6 +260,7 @@ func newTypeResolver(fory *Fory) *TypeResolver {
 		typesInfo:           make(map[reflect.Type]*TypeInfo),
 		nsTypeToTypeInfo:    make(map[nsTypeKey]*TypeInfo),
 		namedTypeToTypeInfo: make(map[namedTypeKey]*TypeInfo),
+		derivedTypeNames:    make(map[namedTypeKey]bool),
 
 		namespaceEncoder: meta.NewEncoder('.', '_'),
 		namespaceDecoder: meta.NewDecoder('.', '_'),
@@ -324,6 +339,7 @@ func newTypeResolver(fory *Fory) *TypeResolver {
 		r.typeToTypeInfo[ptrType] = "*@" + typeTag // *Type -> "*@pkg.Type"
 		r.typeInfoToType["@"+typeTag] = type_      // "@pkg.Type" -> Type
 		r.typeInfoToType["*@"+typeTag] = ptrType   // "*@pkg.Type" -> *Type
+		r.derivedTypeNames[[2]string{pkgPath, typeName}] = true
 	}
 	generatedSerializerFactories.mu.RUnlock()
 
@@ -354,6 +370,9 @@ func (r *TypeResolver) getTypeInfoByType(type_ reflect.Type) *TypeInfo {
 		return cachedInfo
 	}
 	info, ok := r.typesInfo[type_]
+	if !ok && r.registerAnonymousStruct(type_) {
+		info, ok = r.typesInfo[type_]
+	}
 	if !ok {
 		return nil
 	}
@@ -463,6 +482,11 @@ func (r *TypeResolver) initialize() {
 		{durationType, DURATION, durationSerializer{}},
 		{decimalType, DECIMAL, decimalSerializer{}},
 		{genericSetType, SET, setSerializer{}},
+		{listPtrType, LIST, listSerializer},
+		{ringPtrType, LIST, ringSerializer},
+		{netIPType, BINARY, netIPSerializer{}},
+		{netipAddrType, BINARY, netipSerializer{}},
+		{netipPrefixType, BINARY, netipSerializer{}},
 	}
 	for _, elem := range serializers {
 		_, err := r.registerType(elem.Type, uint32(elem.TypeId), invalidUserTypeID, "", "", elem.Serializer, true)
@@ -470,6 +494,7 @@ func (r *TypeResolver) initialize() {
 			panic(fmt.Errorf("init type error: %v", err))
 		}
 	}
+	r.typeToSerializers[genericRecordPtrType] = genericRecordSerializer{}
 
 	// Register additional TypeIds for types that support multiple encodings.
 	// This allows Go to deserialize data from Java that uses different encoding variants.
@@ -500,8 +525,8 @@ func (r *TypeResolver) initialize() {
 }
 
 func (r *TypeResolver) registerSerializer(type_ reflect.Type, typeId TypeId, s Serializer) error {
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 	r.typeToSerializers[type_] = s
 	// Skip type ID registration for namespaced types, collection types, and primitive array types
@@ -518,7 +543,36 @@ func (r *TypeResolver) registerSerializer(type_ reflect.Type, typeId TypeId, s S
 	return nil
 }
 
-func validateOptionalFields(type_ reflect.Type) error {
+// structField returns field i of type_, with its fory tag replaced by the
+// override registered with WithFieldTag, if any.
+func (r *TypeResolver) structField(type_ reflect.Type, i int) reflect.StructField {
+	field := type_.Field(i)
+	if tag, ok := r.fieldTags[type_][field.Name]; ok {
+		// Lookup finds the first fory key, and the other keys still name
+		// the field for WithFieldNameTag.
+		field.Tag = reflect.StructTag("fory:" + strconv.Quote(tag) + " " + string(field.Tag))
+	}
+	return field
+}
+
+// fieldName returns the name field is serialized under: the name in its
+// WithFieldNameTag tag if it has one, or else its Go name, in snake_case.
+func (r *TypeResolver) fieldName(field reflect.StructField) string {
+	if key := r.fory.config.FieldNameTag; key != "" {
+		tag := field.Tag.Get(key)
+		if i := strings.IndexByte(tag, ','); i >= 0 {
+			tag = tag[:i]
+		}
+		if tag != "" && tag != "-" {
+			return SnakeCase(tag)
+		}
+	}
+	return SnakeCase(field.Name)
+}
+
+// validateStructFields rejects field types that can never be serialized when a
+// struct is registered, instead of failing on first use.
+func (r *TypeResolver) validateStructFields(type_ reflect.Type) error {
 	if type_ == nil {
 		return nil
 	}
@@ -528,8 +582,10 @@ func validateOptionalFields(type_ reflect.Type) error {
 	if type_.Kind() != reflect.Struct {
 		return nil
 	}
+	names := make(map[string]string)
+	tagIDs := make(map[int]string)
 	for i := 0; i < type_.NumField(); i++ {
-		field := type_.Field(i)
+		field := r.structField(type_, i)
 		if field.PkgPath != "" {
 			continue
 		}
@@ -540,12 +596,129 @@ func validateOptionalFields(type_ reflect.Type) error {
 		if parsed.ignore {
 			continue
 		}
+		if parsed.idSet {
+			if existing, ok := tagIDs[parsed.tagID]; ok {
+				return InvalidTagErrorf("duplicate fory tag id=%d on fields %s and %s", parsed.tagID, existing, field.Name)
+			}
+			tagIDs[parsed.tagID] = field.Name
+		} else {
+			name := r.fieldName(field)
+			if existing, ok := names[name]; ok {
+				return fmt.Errorf("struct %s fields %s and %s both serialize as %q; tag one with a fory id",
+					type_, existing, field.Name, name)
+			}
+			names[name] = field.Name
+		}
 		optionalInfo, isOptional := getOptionalInfo(field.Type)
 		if isOptional {
 			if err := validateOptionalValueType(optionalInfo.valueType); err != nil {
 				return fmt.Errorf("field %s: %w", field.Name, err)
 			}
 		}
+		if unsupported := unsupportedKindType(field.Type, nil); unsupported != nil {
+			return fmt.Errorf("struct %s field %s: type %s cannot be serialized; tag the field with `fory:\"-\"` to skip it",
+				type_, field.Name, unsupported)
+		}
+	}
+	return nil
+}
+
+// unsupportedKindType returns the channel, function or unsafe.Pointer type
+// reachable from t through pointers, slices, arrays, maps and anonymous
+// structs, or nil if there is none.
+func unsupportedKindType(t reflect.Type, visited map[reflect.Type]bool) reflect.Type {
+	switch t.Kind() {
+	case reflect.Chan, reflect.Func, reflect.UnsafePointer:
+		return t
+	case reflect.Ptr, reflect.Slice, reflect.Array, reflect.Map, reflect.Struct:
+	default:
+		return nil
+	}
+	if t.Kind() == reflect.Struct && t.Name() != "" {
+		return nil // named structs are validated when registered
+	}
+	if visited[t] {
+		return nil
+	}
+	if visited == nil {
+		visited = map[reflect.Type]bool{}
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Map:
+		if unsupported := unsupportedKindType(t.Key(), visited); unsupported != nil {
+			return unsupported
+		}
+		return unsupportedKindType(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			field := t.Field(i)
+			if field.PkgPath != "" {
+				continue
+			}
+			if parsed, err := parseFieldTag(field); err == nil && parsed.ignore {
+				continue
+			}
+			if unsupported := unsupportedKindType(field.Type, visited); unsupported != nil {
+				return unsupported
+			}
+		}
+		return nil
+	default:
+		return unsupportedKindType(t.Elem(), visited)
+	}
+}
+
+// duplicateRegistration reports that type_ is already registered, naming the
+// existing registration. A struct and its pointer share one registration, so
+// registering *T after T, or T after *T, conflicts with the same entry.
+func (r *TypeResolver) duplicateRegistration(type_ reflect.Type) error {
+	existing := "with a serializer"
+	if info, ok := r.typesInfo[type_]; ok {
+		if name, id := r.registeredName(info); name != "" {
+			existing = fmt.Sprintf("as %q", name)
+		} else if id != nil {
+			existing = fmt.Sprintf("with id %d", *id)
+		}
+	}
+	return fmt.Errorf("type %s is already registered %s; unregister it before registering it differently", type_, existing)
+}
+
+// checkTypeName reports that namespace.typeName already names a type other
+// than type_, through a registration, an alias or a name derived from the Go
+// package path. A type and its pointer share their names.
+func (r *TypeResolver) checkTypeName(type_ reflect.Type, namespace, typeName string) error {
+	existing, ok := r.namedTypeToTypeInfo[[2]string{namespace, typeName}]
+	if !ok {
+		return nil
+	}
+	owner := existing.Type
+	if owner.Kind() == reflect.Ptr && type_.Kind() != reflect.Ptr {
+		owner = owner.Elem()
+	} else if owner.Kind() != reflect.Ptr && type_.Kind() == reflect.Ptr {
+		owner = reflect.PtrTo(owner)
+	}
+	if owner == type_ {
+		return nil
+	}
+	return fmt.Errorf("name %s is already registered for type %s",
+		joinRegisteredName(namespace, typeName), existing.Type)
+}
+
+// claimTypeName is checkTypeName for explicit registrations, which take
+// names derived from package paths away from the types they were derived for.
+func (r *TypeResolver) claimTypeName(type_ reflect.Type, namespace, typeName string) error {
+	nameKey := [2]string{namespace, typeName}
+	if !r.derivedTypeNames[nameKey] {
+		return r.checkTypeName(type_, namespace, typeName)
+	}
+	derived := r.namedTypeToTypeInfo[nameKey]
+	delete(r.derivedTypeNames, nameKey)
+	delete(r.namedTypeToTypeInfo, nameKey)
+	for key, info := range r.nsTypeToTypeInfo {
+		if info == derived || info.Type == reflect.PtrTo(derived.Type) {
+			delete(r.nsTypeToTypeInfo, key)
+		}
 	}
 	return nil
 }
@@ -562,12 +735,15 @@ func (r *TypeResolver) RegisterStruct(type_ reflect.Type, typeID TypeId, userTyp
 
 	switch type_.Kind() {
 	case reflect.Struct:
-		if err := validateOptionalFields(type_); err != nil {
+		if err := r.validateStructFields(type_); err != nil {
 			return err
 		}
 		// For struct types, check if serializer already registered
-		if prev, ok := r.typeToSerializers[type_]; ok {
-			return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+		if _, ok := r.typeToSerializers[type_]; ok {
+			return r.duplicateRegistration(type_)
+		}
+		if err := checkReflectStructSerializer(type_); err != nil {
+			return err
 		}
 
 		// Create struct serializer
@@ -617,11 +793,11 @@ func (r *TypeResolver) RegisterUnion(type_ reflect.Type, userTypeID uint32, seri
 	if info, ok := r.userTypeIdToTypeInfo[userTypeID]; ok {
 		return fmt.Errorf("type %s with id %d has been registered", info.Type, userTypeID)
 	}
-	if type_.Kind() != reflect.Struct {
-		return fmt.Errorf("RegisterUnion only supports struct types; got: %v", type_.Kind())
+	if type_.Kind() != reflect.Struct && type_.Kind() != reflect.Interface {
+		return fmt.Errorf("RegisterUnion only supports struct and interface types; got: %v", type_.Kind())
 	}
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 
 	tag := type_.Name()
@@ -629,17 +805,19 @@ func (r *TypeResolver) RegisterUnion(type_ reflect.Type, userTypeID uint32, seri
 	r.typeToTypeInfo[type_] = "@" + tag
 	r.typeInfoToType["@"+tag] = type_
 
+	_, err := r.registerType(type_, uint32(TYPED_UNION), userTypeID, "", "", serializer, false)
+	if err != nil {
+		return fmt.Errorf("failed to register union by ID: %w", err)
+	}
+	if type_.Kind() == reflect.Interface {
+		return nil
+	}
 	ptrType := reflect.PtrTo(type_)
 	ptrSerializer := &ptrToValueSerializer{valueSerializer: serializer}
 	r.typeToSerializers[ptrType] = ptrSerializer
 	r.typeTagToSerializers[tag] = ptrSerializer
 	r.typeToTypeInfo[ptrType] = "*@" + tag
 	r.typeInfoToType["*@"+tag] = ptrType
-
-	_, err := r.registerType(type_, uint32(TYPED_UNION), userTypeID, "", "", serializer, false)
-	if err != nil {
-		return fmt.Errorf("failed to register union by ID: %w", err)
-	}
 	_, err = r.registerType(ptrType, uint32(TYPED_UNION), userTypeID, "", "", ptrSerializer, false)
 	if err != nil {
 		return fmt.Errorf("failed to register pointer union by ID: %w", err)
@@ -679,7 +857,6 @@ func (r *TypeResolver) RegisterEnum(type_ reflect.Type, userTypeID uint32) error
 		Serializer: serializer,
 		IsDynamic:  isDynamicType(type_),
 		DispatchId: GetDispatchId(type_),
-		hashValue:  calcTypeHash(type_),
 	}
 	r.userTypeIdToTypeInfo[userTypeID] = typeInfo
 	r.typesInfo[type_] = typeInfo
@@ -689,12 +866,15 @@ func (r *TypeResolver) RegisterEnum(type_ reflect.Type, userTypeID uint32) error
 
 func (r *TypeResolver) registerEnumByName(type_ reflect.Type, namespace, typeName string) error {
 	// Check if already registered
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 	if typeName == "" {
 		return fmt.Errorf("typeName must be non-empty")
 	}
+	if err := r.claimTypeName(type_, namespace, typeName); err != nil {
+		return err
+	}
 
 	// Verify it's a numeric type
 	switch type_.Kind() {
@@ -727,12 +907,21 @@ func (r *TypeResolver) registerEnumByName(type_ reflect.Type, namespace, typeNam
 }
 
 func (r *TypeResolver) registerStructByName(type_ reflect.Type, namespace, typeName string) error {
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 	if typeName == "" {
 		return fmt.Errorf("typeName must be non-empty")
 	}
+	if err := r.claimTypeName(type_, namespace, typeName); err != nil {
+		return err
+	}
+	if err := r.validateStructFields(type_); err != nil {
+		return err
+	}
+	if err := checkReflectStructSerializer(type_); err != nil {
+		return err
+	}
 	tag := joinRegisteredName(namespace, typeName)
 	serializer := newStructSerializer(type_, tag)
 	r.typeToSerializers[type_] = serializer
@@ -772,32 +961,37 @@ func (r *TypeResolver) registerUnionByName(
 	if serializer == nil {
 		return fmt.Errorf("RegisterUnionByName requires a non-nil serializer")
 	}
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
-	if type_.Kind() != reflect.Struct {
-		return fmt.Errorf("RegisterUnionByName only supports struct types; got: %v", type_.Kind())
+	if type_.Kind() != reflect.Struct && type_.Kind() != reflect.Interface {
+		return fmt.Errorf("RegisterUnionByName only supports struct and interface types; got: %v", type_.Kind())
 	}
 	if typeName == "" {
 		return fmt.Errorf("typeName must be non-empty")
 	}
+	if err := r.claimTypeName(type_, namespace, typeName); err != nil {
+		return err
+	}
 	tag := joinRegisteredName(namespace, typeName)
 	r.typeToSerializers[type_] = serializer
 	r.typeToTypeInfo[type_] = "@" + tag
 	r.typeInfoToType["@"+tag] = type_
 
+	typeId := uint32(NAMED_UNION)
+	_, err := r.registerType(type_, typeId, invalidUserTypeID, namespace, typeName, serializer, false)
+	if err != nil {
+		return fmt.Errorf("failed to register union by name: %w", err)
+	}
+	if type_.Kind() == reflect.Interface {
+		return nil
+	}
 	ptrType := reflect.PtrTo(type_)
 	ptrSerializer := &ptrToValueSerializer{valueSerializer: serializer}
 	r.typeToSerializers[ptrType] = ptrSerializer
 	r.typeTagToSerializers[tag] = ptrSerializer
 	r.typeToTypeInfo[ptrType] = "*@" + tag
 	r.typeInfoToType["*@"+tag] = ptrType
-
-	typeId := uint32(NAMED_UNION)
-	_, err := r.registerType(type_, typeId, invalidUserTypeID, namespace, typeName, serializer, false)
-	if err != nil {
-		return fmt.Errorf("failed to register union by name: %w", err)
-	}
 	_, err = r.registerType(ptrType, typeId, invalidUserTypeID, namespace, typeName, ptrSerializer, false)
 	if err != nil {
 		return fmt.Errorf("failed to register pointer union by name: %w", err)
@@ -805,6 +999,42 @@ func (r *TypeResolver) registerUnionByName(
 	return nil
 }
 
+func (r *TypeResolver) registerTypeAlias(namespace, typeName string, type_ reflect.Type) error {
+	info, ok := r.typesInfo[type_]
+	if !ok || !IsNamespacedType(TypeId(info.TypeID)) {
+		return fmt.Errorf("type %s must be registered by name before adding an alias", type_)
+	}
+	if err := r.claimTypeName(type_, namespace, typeName); err != nil {
+		return err
+	}
+	nameKey := [2]string{namespace, typeName}
+	if _, exists := r.namedTypeToTypeInfo[nameKey]; exists {
+		return nil
+	}
+	r.namedTypeToTypeInfo[nameKey] = info
+	return nil
+}
+
+// isUnionInterface reports whether type_ is an interface registered as a union.
+func (r *TypeResolver) isUnionInterface(type_ reflect.Type) bool {
+	if type_.Kind() != reflect.Interface {
+		return false
+	}
+	info, ok := r.typesInfo[type_]
+	return ok && (info.TypeID == TYPED_UNION || info.TypeID == NAMED_UNION)
+}
+
+// isExtensionType reports whether type_ is registered with an extension
+// serializer. Array types registered this way are not written as slices.
+func (r *TypeResolver) isExtensionType(type_ reflect.Type) bool {
+	info, ok := r.typesInfo[type_]
+	if !ok {
+		return false
+	}
+	typeID := TypeId(info.TypeID)
+	return typeID == EXT || typeID == NAMED_EXT
+}
+
 func (r *TypeResolver) RegisterExt(extId int16, type_ reflect.Type) error {
 	// Registering type is necessary, otherwise we may don't have the symbols of corresponding type when deserializing.
 	panic("not supported")
@@ -819,12 +1049,15 @@ func (r *TypeResolver) registerExtensionByName(
 	if userSerializer == nil {
 		return fmt.Errorf("serializer cannot be nil for extension type %s", type_)
 	}
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 	if typeName == "" {
 		return fmt.Errorf("typeName must be non-empty")
 	}
+	if err := r.claimTypeName(type_, namespace, typeName); err != nil {
+		return err
+	}
 	tag := joinRegisteredName(namespace, typeName)
 
 	// Create adapter wrapping the user's ExtensionSerializer
@@ -867,8 +1100,8 @@ func (r *TypeResolver) RegisterExtension(
 	if userSerializer == nil {
 		return fmt.Errorf("serializer cannot be nil for extension type %s", type_)
 	}
-	if prev, ok := r.typeToSerializers[type_]; ok {
-		return fmt.Errorf("type %s already has a serializer %s registered", type_, prev)
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
 	}
 
 	// Create adapter wrapping the user's ExtensionSerializer
@@ -893,7 +1126,59 @@ func (r *TypeResolver) RegisterExtension(
 	return nil
 }
 
+// registerDecimal registers type_ as the DECIMAL type, converted to and from
+// Decimal by converter.
+func (r *TypeResolver) registerDecimal(type_ reflect.Type, converter DecimalConverter) error {
+	if converter == nil {
+		return fmt.Errorf("converter cannot be nil for decimal type %s", type_)
+	}
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return r.duplicateRegistration(type_)
+	}
+	serializer := convertedDecimalSerializer{converter: converter}
+	r.typeToSerializers[type_] = serializer
+	r.typesInfo[type_] = &TypeInfo{
+		Type:         type_,
+		TypeID:       uint32(DECIMAL),
+		UserTypeID:   invalidUserTypeID,
+		Serializer:   serializer,
+		DispatchId:   GetDispatchId(type_),
+		NeedWriteRef: NeedWriteRef(DECIMAL),
+	}
+	return nil
+}
+
+// containsDecimalType reports whether t is, or holds as a pointer, slice,
+// array or map element, a type registered with RegisterDecimal.
+func (r *TypeResolver) containsDecimalType(t reflect.Type) bool {
+	for {
+		if _, ok := r.typeToSerializers[t].(convertedDecimalSerializer); ok {
+			return true
+		}
+		switch t.Kind() {
+		case reflect.Ptr, reflect.Slice, reflect.Array:
+			t = t.Elem()
+		case reflect.Map:
+			if r.containsDecimalType(t.Key()) {
+				return true
+			}
+			t = t.Elem()
+		default:
+			return false
+		}
+	}
+}
+
 func (r *TypeResolver) getSerializerByType(type_ reflect.Type, mapInStruct bool) (Serializer, error) {
+	if mapInStruct {
+		mapType := type_
+		if mapType.Kind() == reflect.Ptr {
+			mapType = mapType.Elem()
+		}
+		if mapType.Kind() == reflect.Map {
+			return r.getDeclaredMapSerializer(mapType)
+		}
+	}
 	if serializer, ok := r.typeToSerializers[type_]; !ok {
 		if serializer, err := r.createSerializer(type_, mapInStruct); err != nil {
 			return nil, err
@@ -906,6 +1191,28 @@ func (r *TypeResolver) getSerializerByType(type_ reflect.Type, mapInStruct bool)
 	}
 }
 
+// getDeclaredMapSerializer returns the serializer for a map whose key and value
+// types are declared by the enclosing struct field or map. It is cached apart
+// from typeToSerializers so top-level maps keep writing key/value type info.
+func (r *TypeResolver) getDeclaredMapSerializer(type_ reflect.Type) (Serializer, error) {
+	if serializer, ok := r.declaredMapSerializers[type_]; ok {
+		return serializer, nil
+	}
+	serializer, ok := r.typeToSerializers[type_]
+	switch serializer.(type) {
+	case mapSerializer, *mapSerializer:
+		ok = false
+	}
+	if !ok {
+		var err error
+		if serializer, err = r.createSerializer(type_, true); err != nil {
+			return nil, err
+		}
+	}
+	r.declaredMapSerializers[type_] = serializer
+	return serializer, nil
+}
+
 // getTypeIdByType returns the TypeId for a given type, or 0 if not found in typesInfo.
 // This is used to get the type ID without calling Serializer.TypeId().
 func (r *TypeResolver) getTypeIdByType(type_ reflect.Type) TypeId {
@@ -983,11 +1290,18 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 
 	var internal = false
 	type_ := value.Type()
+	if type_ == genericRecordPtrType {
+		// Not cached: each record carries the type info of its own TypeDef.
+		return genericRecordTypeInfo(value)
+	}
+	if r.registerAnonymousStruct(type_) {
+		return r.getTypeInfo(value, create)
+	}
 	// Get package path and type name for registration
 	var typeName string
 	var pkgPath string
-	rawInfo, ok := r.typeToTypeInfo[type_]
-	if !ok {
+	rawInfo, registered := r.typeToTypeInfo[type_]
+	if !registered {
 		// Type not explicitly registered - extract from reflect.Type
 		pkgPath = type_.PkgPath()
 		typeName = type_.Name()
@@ -1037,7 +1351,6 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 				DispatchId:    elemInfo.DispatchId,
 				Serializer:    ptrSerializer,
 				NeedWriteDef:  elemInfo.NeedWriteDef,
-				hashValue:     elemInfo.hashValue,
 			}
 
 			// Cache the pointer type info
@@ -1067,6 +1380,12 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 		}
 
 		return nil, fmt.Errorf("pointer element type %v must be registered", elemType)
+	case iterArity(type_) != 0:
+		info := r.newIterTypeInfo(type_)
+		r.typesInfo[type_] = info
+		return info, nil
+	case type_.Kind() == reflect.Chan || type_.Kind() == reflect.Func || type_.Kind() == reflect.UnsafePointer:
+		return nil, fmt.Errorf("type %s cannot be serialized", type_)
 	case type_.Kind() == reflect.Interface:
 		return nil, fmt.Errorf("interface types must be registered explicitly")
 	case type_.Kind() == reflect.Struct:
@@ -1090,15 +1409,15 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 		// Allow anonymous collection types to use dynamic type ID 0
 		typeID = 0
 	default:
-		panic(fmt.Errorf("type %v must be registered explicitly", type_))
+		return nil, fmt.Errorf("type %v must be registered explicitly", type_)
 	}
 
 	/*
 	   There are still some issues to address when adapting structs:
 	   Named structs need both value and pointer types registered using the negative ID system
 	   to assign the correct typeID.
-	   Multidimensional slices should use typeID = 21 for recursive serialization; on
-	   deserialization, users receive []any and must apply conversion function.
+	   Multidimensional slices use typeID = 21 with a typed element serializer, so
+	   they decode directly into the concrete nested slice type.
 	   Array types aren’t tracked separately in fory-go’s type system; semantically,
 	   arrays reuse their corresponding slice serializer/deserializer. We serialize arrays
 	   via their slice metadata and convert back to arrays by conversion function.
@@ -1196,17 +1515,13 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 		}
 		r.typesInfo[type_] = arrayInfo
 		return arrayInfo, nil
-	} else if isMultiDimensionaSlice(value) {
-		typeID = LIST
-		info := r.typeIDToTypeInfo[typeID]
-		return info, nil
 	} else if value.Kind() == reflect.Slice {
-		// Regular slices are treated as LIST
+		// Regular and nested slices are treated as LIST
 		typeID = LIST
 	}
 
 	// Register the type with full metadata
-	return r.registerType(
+	info, err := r.registerType(
 		type_,
 		typeID,
 		invalidUserTypeID,
@@ -1214,15 +1529,10 @@ func (r *TypeResolver) getTypeInfo(value reflect.Value, create bool) (*TypeInfo,
 		typeName,
 		nil, // serializer will be created during registration
 		internal)
-}
-
-// Check if the slice is multidimensional
-func isMultiDimensionaSlice(v reflect.Value) bool {
-	t := v.Type()
-	if t.Kind() != reflect.Slice {
-		return false
+	if err == nil && !registered && typeName != "" {
+		r.derivedTypeNames[[2]string{pkgPath, typeName}] = true
 	}
-	return t.Elem().Kind() == reflect.Slice
+	return info, err
 }
 
 func (r *TypeResolver) registerType(
@@ -1236,10 +1546,15 @@ func (r *TypeResolver) registerType(
 ) (*TypeInfo, error) {
 	// Input validation
 	if type_ == nil {
-		panic("nil type")
+		return nil, fmt.Errorf("nil type")
 	}
 	if typeName == "" && namespace != "" {
-		panic("namespace provided without typeName")
+		return nil, fmt.Errorf("namespace %q provided without type name", namespace)
+	}
+	if typeName != "" {
+		if err := r.checkTypeName(type_, namespace, typeName); err != nil {
+			return nil, err
+		}
 	}
 	if internal && typeID > internalTypeIDLimit {
 		panic(fmt.Sprintf("internal type id overflow: %d", typeID))
@@ -1256,7 +1571,7 @@ func (r *TypeResolver) registerType(
 		if serializer == nil {
 			// Create new serializer if not found
 			if serializer, err = r.createSerializer(type_, false); err != nil {
-				panic(fmt.Sprintf("failed to create serializer: %v", err))
+				return nil, fmt.Errorf("failed to create serializer for %v: %w", type_, err)
 			}
 		}
 	}
@@ -1274,12 +1589,12 @@ func (r *TypeResolver) registerType(
 
 		nsMeta, _ := r.namespaceEncoder.EncodePackage(namespace)
 		if nsBytes = r.metaStringResolver.GetMetaStrBytes(&nsMeta); nsBytes == nil {
-			panic("failed to encode namespace")
+			return nil, fmt.Errorf("failed to encode namespace %q", namespace)
 		}
 
 		typeMeta, _ := r.typeNameEncoder.EncodeTypeName(typeName)
 		if typeBytes = r.metaStringResolver.GetMetaStrBytes(&typeMeta); typeBytes == nil {
-			panic("failed to encode type name")
+			return nil, fmt.Errorf("failed to encode type name %q", typeName)
 		}
 	}
 
@@ -1293,7 +1608,6 @@ func (r *TypeResolver) registerType(
 		NameBytes:    typeBytes, // Encoded type name bytes
 		IsDynamic:    isDynamicType(type_),
 		DispatchId:   GetDispatchId(type_), // Static type ID for fast path
-		hashValue:    calcTypeHash(type_),  // Precomputed hash for fast lookups
 		NeedWriteRef: NeedWriteRef(TypeId(typeID)),
 	}
 	if structSer, ok := serializer.(*structSerializer); ok {
@@ -1312,8 +1626,12 @@ func (r *TypeResolver) registerType(
 			// If existing is pointer but we're registering value type, prefer value type
 			r.namedTypeToTypeInfo[nameKey] = typeInfo
 		}
-		// Cache by hashed namespace/name bytes
-		r.nsTypeToTypeInfo[nsTypeKey{nsBytes.Hashcode, typeBytes.Hashcode}] = typeInfo
+		// Cache by hashed namespace/name bytes, with the same value type preference
+		nsKey := nsTypeKey{nsBytes.Hashcode, typeBytes.Hashcode}
+		if existing, exists := r.nsTypeToTypeInfo[nsKey]; !exists ||
+			type_.Kind() != reflect.Ptr || existing.Type.Kind() == reflect.Ptr {
+			r.nsTypeToTypeInfo[nsKey] = typeInfo
+		}
 	}
 
 	// Cache by type ID (for cross-language support)
@@ -1349,19 +1667,24 @@ func (r *TypeResolver) registerType(
 	return typeInfo, nil
 }
 
-func calcTypeHash(type_ reflect.Type) uint64 {
-	// Implement proper hash calculation based on type
-	h := fnv.New64a()
-	h.Write([]byte(type_.PkgPath()))
-	h.Write([]byte(type_.Name()))
-	h.Write([]byte(type_.Kind().String()))
-	return h.Sum64()
-}
-
 func (r *TypeResolver) metaShareEnabled() bool {
 	return r.fory != nil && r.fory.metaContext != nil && r.fory.config.Compatible
 }
 
+// omitZeroFields reports whether values of the struct type are written with a
+// field presence bitmap, skipping fields that hold their zero value. Structs
+// opted out of evolution are always written in full.
+func (r *TypeResolver) omitZeroFields(type_ reflect.Type) bool {
+	return r.metaShareEnabled() && r.fory.config.OmitZeroFields && r.structTypeID(type_, false) == COMPATIBLE_STRUCT
+}
+
+func (r *TypeResolver) metaCompressor() MetaCompressor {
+	if r.fory == nil {
+		return nil
+	}
+	return r.fory.config.MetaCompressor
+}
+
 func (r *TypeResolver) structTypeID(type_ reflect.Type, named bool) TypeId {
 	useCompatible := r.metaShareEnabled()
 	if useCompatible {
@@ -1388,6 +1711,9 @@ func (r *TypeResolver) WriteTypeInfo(buffer *ByteBuffer, typeInfo *TypeInfo, err
 		return
 	}
 	typeID := typeInfo.TypeID
+	if r.trace != nil {
+		traceTypeInfo(r.trace, "fory write type", buffer.writerIndex, typeID, typeInfo.Type)
+	}
 	buffer.WriteUint8(uint8(typeID))
 
 	// Handle type meta based on internal type ID (matching Java XtypeResolver.writeTypeInfo)
@@ -1410,35 +1736,33 @@ func (r *TypeResolver) WriteTypeInfo(buffer *ByteBuffer, typeInfo *TypeInfo, err
 	}
 }
 
+// sharedTypeDef returns the TypeDef written for typeInfo under shared meta.
+func (r *TypeResolver) sharedTypeDef(typeInfo *TypeInfo) (*TypeDef, error) {
+	if typeInfo.Type == genericRecordPtrType {
+		return typeInfo.TypeDef, nil
+	}
+	return r.getTypeDef(typeInfo.Type, true)
+}
+
 func (r *TypeResolver) writeSharedTypeMeta(buffer *ByteBuffer, typeInfo *TypeInfo, err *Error) {
 	context := r.fory.MetaContext()
 	key := typePointer(typeInfo.Type)
+	if typeInfo.Type == genericRecordPtrType {
+		// Records share one Go type but carry the TypeDef they were read with.
+		key = uintptr(unsafe.Pointer(typeInfo.TypeDef))
+	}
+	// Enums and extension types have a TypeDef without fields; the reader
+	// expects one after every new-type marker.
 	writeTypeDefInline := func() {
-		// Only build TypeDef for struct types - enums don't have field definitions
-		actualType := typeInfo.Type
-		if actualType.Kind() == reflect.Ptr {
-			actualType = actualType.Elem()
-		}
-		if actualType.Kind() == reflect.Struct {
-			typeDef, typeDefErr := r.getTypeDef(typeInfo.Type, true)
-			if typeDefErr != nil {
-				err.SetError(typeDefErr)
-				return
-			}
-			// Write TypeDef bytes inline
-			typeDef.writeTypeDef(buffer, err)
+		typeDef, typeDefErr := r.sharedTypeDef(typeInfo)
+		if typeDefErr != nil {
+			err.SetError(typeDefErr)
+			return
 		}
+		typeDef.writeTypeDef(buffer, err)
 	}
 	writeTypeDefWithZeroMarker := func() {
-		actualType := typeInfo.Type
-		if actualType.Kind() == reflect.Ptr {
-			actualType = actualType.Elem()
-		}
-		if actualType.Kind() != reflect.Struct {
-			buffer.WriteUint8(0)
-			return
-		}
-		typeDef, typeDefErr := r.getTypeDef(typeInfo.Type, true)
+		typeDef, typeDefErr := r.sharedTypeDef(typeInfo)
 		if typeDefErr != nil {
 			err.SetError(typeDefErr)
 			return
@@ -1559,7 +1883,11 @@ func (r *TypeResolver) readSharedTypeMeta(buffer *ByteBuffer, err *Error) *TypeI
 	}
 
 	var td *TypeDef
-	if existingTd, exists := r.defIdToTypeDef[id]; exists {
+	existingTd, exists := r.defIdToTypeDef[id]
+	if m := r.fory.config.Metrics; m != nil {
+		m.TypeDefLookup(exists)
+	}
+	if exists {
 		// Header-cache hits intentionally skip without rehashing. Entries reach this cache only
 		// after a successful TypeDef parse and 52-bit metadata-hash validation.
 		skipTypeDef(buffer, id, err)
@@ -1757,25 +2085,27 @@ func (r *TypeResolver) createSerializer(type_ reflect.Type, mapInStruct bool) (s
 		if isSetReflectType(type_) {
 			return setSerializer{}, nil
 		}
+		if r.isXlang {
+			if err := checkXlangMapKey(type_.Key()); err != nil {
+				return nil, err
+			}
+		}
 		hasKeySerializer, hasValueSerializer := !isDynamicType(type_.Key()), !isDynamicType(type_.Elem())
 		// Determine key/value referencability using isRefType which handles xlang mode
 		keyReferencable := isRefType(type_.Key(), r.isXlang)
 		valueReferencable := isRefType(type_.Elem(), r.isXlang)
 		if hasKeySerializer || hasValueSerializer {
 			var keySerializer, valueSerializer Serializer
-			/*
-			   Current tests do not cover scenarios where a map’s value is itself a map.
-			   It’s undecided whether to use a type-specific map serializer or a generic one.
-			   mapInStruct is currently set to false.
-			*/
+			// Nested maps inherit mapInStruct: a declared map[string]map[string]int64
+			// declares the inner map's key and value types as well.
 			if hasKeySerializer {
-				keySerializer, err = r.getSerializerByType(type_.Key(), false)
+				keySerializer, err = r.getSerializerByType(type_.Key(), mapInStruct)
 				if err != nil {
 					return nil, err
 				}
 			}
 			if hasValueSerializer {
-				valueSerializer, err = r.getSerializerByType(type_.Elem(), false)
+				valueSerializer, err = r.getSerializerByType(type_.Elem(), mapInStruct)
 				if err != nil {
 					return nil, err
 				}
@@ -1797,6 +2127,9 @@ func (r *TypeResolver) createSerializer(type_ reflect.Type, mapInStruct bool) (s
 		}, nil
 	case reflect.Struct:
 		serializer := r.typeToSerializers[type_]
+		if serializer == nil && r.registerAnonymousStruct(type_) {
+			serializer = r.typeToSerializers[type_]
+		}
 		if serializer == nil {
 			return nil, fmt.Errorf("struct type %s must be registered explicitly", type_.String())
 		}
@@ -1805,6 +2138,35 @@ func (r *TypeResolver) createSerializer(type_ reflect.Type, mapInStruct bool) (s
 	return nil, fmt.Errorf("type %s not supported", type_.String())
 }
 
+// checkReflectStructSerializer fails in codegen-only builds, where structs
+// without a generated serializer cannot be serialized.
+func checkReflectStructSerializer(type_ reflect.Type) error {
+	if codegenOnly {
+		return fmt.Errorf("struct %s has no generated serializer; codegen-only builds require forygen serializers", type_)
+	}
+	return nil
+}
+
+// registerAnonymousStruct registers an unregistered anonymous struct, or a
+// pointer to one, by a name derived from its definition, so Go peers declaring
+// the same struct resolve it without registration. Only native mode does this:
+// other languages cannot resolve the derived name.
+func (r *TypeResolver) registerAnonymousStruct(type_ reflect.Type) bool {
+	if type_.Kind() == reflect.Ptr {
+		type_ = type_.Elem()
+	}
+	if r.isXlang || type_.Kind() != reflect.Struct || type_.Name() != "" {
+		return false
+	}
+	if _, ok := r.typeToSerializers[type_]; ok {
+		return false
+	}
+	h := fnv.New64a()
+	h.Write([]byte(type_.String()))
+	name := fmt.Sprintf("struct_%016x", h.Sum64())
+	return r.registerStructByName(type_, anonymousStructNamespace, name) == nil
+}
+
 // GetSliceSerializer returns the appropriate serializer for a slice type.
 // For primitive element types, it returns the dedicated primitive slice serializer
 // that uses ARRAY protocol.
@@ -2000,21 +2362,21 @@ func (r *TypeResolver) decodeType(typeStr string) (reflect.Type, string, error)
 			return reflect.SliceOf(type_), "[]" + subStr, nil
 		}
 	} else if strings.HasPrefix(typeStr, "[") { // array
-		arrTypeRegex, _ := regexp.Compile(`\[([0-9]+)]`)
-		idx := arrTypeRegex.FindStringSubmatchIndex(typeStr)
-		if idx == nil {
+		end := strings.IndexByte(typeStr, ']')
+		if end < 2 {
 			return nil, "", fmt.Errorf("unparseable type %s", typeStr)
 		}
-		lenStr := typeStr[idx[2]:idx[3]]
-		if length, err := strconv.Atoi(lenStr); err != nil {
+		if length, err := strconv.Atoi(typeStr[1:end]); err != nil {
 			return nil, "", err
+		} else if length < 0 {
+			return nil, "", fmt.Errorf("unparseable type %s", typeStr)
 		} else {
-			subStr := typeStr[idx[1]:]
+			subStr := typeStr[end+1:]
 			type_, elemStr, err := r.decodeType(subStr)
 			if err != nil {
 				return nil, "", err
 			} else {
-				return reflect.ArrayOf(length, type_), typeStr[idx[0]:idx[1]] + elemStr, nil
+				return reflect.ArrayOf(length, type_), typeStr[:end+1] + elemStr, nil
 			}
 		}
 	} else if strings.HasPrefix(typeStr, "map[") {
@@ -2091,14 +2453,31 @@ func (r *TypeResolver) resolveTypeInfoByMetaBytes(nsBytes, typeBytes *MetaString
 	if ns != "" {
 		fullName = ns + "." + typeName
 	}
+	if TypeId(typeID) == NAMED_ENUM {
+		return unknownEnumTypeInfo(UnknownTypeWarning{TypeID: NAMED_ENUM, Name: fullName})
+	}
 	err.SetError(fmt.Errorf("unregistered type: %s (typeID: %d)", fullName, typeID))
 	return nil
 }
 
+// unknownEnumTypeInfo returns type info for an unregistered enum. Its nil Type
+// marks the value as unknown to readers; the serializer skips the ordinal.
+func unknownEnumTypeInfo(unknown UnknownTypeWarning) *TypeInfo {
+	return &TypeInfo{
+		TypeID:     uint32(unknown.TypeID),
+		UserTypeID: unknown.UserTypeID,
+		Serializer: &unknownEnumSerializer{unknown: unknown},
+		IsDynamic:  true,
+	}
+}
+
 // ReadTypeInfo reads type info from buffer and returns it.
 // This is exported for use by generated code.
 func (r *TypeResolver) ReadTypeInfo(buffer *ByteBuffer, err *Error) *TypeInfo {
 	typeID := uint32(buffer.ReadUint8(err))
+	if r.trace != nil && !err.HasError() {
+		traceTypeInfo(r.trace, "fory read type", buffer.readerIndex-1, typeID, nil)
+	}
 	internalTypeID := TypeId(typeID)
 
 	switch internalTypeID {
@@ -2110,6 +2489,9 @@ func (r *TypeResolver) ReadTypeInfo(buffer *ByteBuffer, err *Error) *TypeInfo {
 		if typeInfo, exists := r.userTypeIdToTypeInfo[userTypeID]; exists {
 			return typeInfo
 		}
+		if internalTypeID == ENUM {
+			return unknownEnumTypeInfo(UnknownTypeWarning{TypeID: ENUM, UserTypeID: userTypeID})
+		}
 	case COMPATIBLE_STRUCT, NAMED_COMPATIBLE_STRUCT:
 		return r.readSharedTypeMeta(buffer, err)
 	case NAMED_ENUM, NAMED_STRUCT, NAMED_EXT, NAMED_UNION:
@@ -2152,6 +2534,10 @@ func (r *TypeResolver) ReadTypeInfo(buffer *ByteBuffer, err *Error) *TypeInfo {
 // readTypeInfoWithTypeID reads type info when the typeID has already been read from buffer.
 // This is used by collection serializers that read typeID separately before deciding how to proceed.
 func (r *TypeResolver) readTypeInfoWithTypeID(buffer *ByteBuffer, typeID uint32, err *Error) *TypeInfo {
+	if r.trace != nil {
+		// Callers have just read the one-byte type ID.
+		traceTypeInfo(r.trace, "fory read type", buffer.readerIndex-1, typeID, nil)
+	}
 	internalTypeID := TypeId(typeID)
 
 	switch internalTypeID {
@@ -2163,6 +2549,9 @@ func (r *TypeResolver) readTypeInfoWithTypeID(buffer *ByteBuffer, typeID uint32,
 		if typeInfo, exists := r.userTypeIdToTypeInfo[userTypeID]; exists {
 			return typeInfo
 		}
+		if internalTypeID == ENUM {
+			return unknownEnumTypeInfo(UnknownTypeWarning{TypeID: ENUM, UserTypeID: userTypeID})
+		}
 	case COMPATIBLE_STRUCT, NAMED_COMPATIBLE_STRUCT:
 		return r.readSharedTypeMeta(buffer, err)
 	case NAMED_ENUM, NAMED_STRUCT, NAMED_EXT, NAMED_UNION:
@@ -2208,6 +2597,9 @@ func (r *TypeResolver) readTypeInfoWithTypeID(buffer *ByteBuffer, typeID uint32,
 // For COMPATIBLE_STRUCT/NAMED_COMPATIBLE_STRUCT: Reads type def and creates serializer with passed type
 func (r *TypeResolver) ReadTypeInfoForType(buffer *ByteBuffer, expectedType reflect.Type, err *Error) Serializer {
 	typeID := uint32(buffer.ReadUint8(err))
+	if r.trace != nil && !err.HasError() {
+		traceTypeInfo(r.trace, "fory read type", buffer.readerIndex-1, typeID, expectedType)
+	}
 	internalTypeID := TypeId(typeID)
 	switch internalTypeID {
 	case ENUM, STRUCT, EXT, TYPED_UNION:
@@ -2401,3 +2793,29 @@ func (m *MetaContext) Reset() {
 		m.readTypeInfos = m.readTypeInfos[:0]
 	}
 }
+
+// copyWriteState makes m continue numbering type defs from where src is, so
+// a payload section written with m can reference the type defs src wrote.
+func (m *MetaContext) copyWriteState(src *MetaContext) {
+	m.hasFirstType = src.hasFirstType
+	m.firstTypePtr = src.firstTypePtr
+	m.typeMapActive = src.typeMapActive
+	if src.typeMapActive {
+		if m.typeMap == nil {
+			m.typeMap = make(map[uintptr]uint32, len(src.typeMap))
+		}
+		clear(m.typeMap)
+		maps.Copy(m.typeMap, src.typeMap)
+	}
+}
+
+// writtenTypes returns the number of type defs written since the last Reset.
+func (m *MetaContext) writtenTypes() int {
+	if m.typeMapActive {
+		return len(m.typeMap)
+	}
+	if m.hasFirstType {
+		return 1
+	}
+	return 0
+}
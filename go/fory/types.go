@@ -445,6 +445,9 @@ func GetDispatchId(t reflect.Type) DispatchId {
 		// Check for specific slice types
 		switch t.Elem().Kind() {
 		case reflect.Uint8:
+			if t == netIPType {
+				return UnknownDispatchId // compacted by netIPSerializer
+			}
 			return ByteSliceDispatchId
 		case reflect.Int8:
 			return Int8SliceDispatchId
@@ -57,7 +57,8 @@ type unionCaseInfo struct {
 	declaredValue bool
 }
 
-// UnionSerializer is a generic serializer for generated Go unions.
+// UnionSerializer is a generic serializer for generated Go unions and for
+// interfaces registered as unions, whose case is chosen by the dynamic type.
 // It relies on union case metadata provided at registration time.
 type UnionSerializer struct {
 	cases       []unionCaseInfo
@@ -134,10 +135,16 @@ func (s *UnionSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bo
 	_ = hasGenerics
 	switch refMode {
 	case RefModeTracking:
-		if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		if !value.IsValid() || ((value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil()) {
 			ctx.Buffer().WriteInt8(NullFlag)
 			return
 		}
+		if isSumTypeInterface(value.Type()) {
+			// The case value carries its own ref flag; the interface has no
+			// identity of its own.
+			ctx.Buffer().WriteInt8(NotNullValueFlag)
+			break
+		}
 		refWritten, err := ctx.RefResolver().WriteRefOrNull(ctx.Buffer(), value)
 		if err != nil {
 			ctx.SetError(FromError(err))
@@ -147,7 +154,7 @@ func (s *UnionSerializer) Write(ctx *WriteContext, refMode RefMode, writeType bo
 			return
 		}
 	case RefModeNullOnly:
-		if !value.IsValid() || (value.Kind() == reflect.Ptr && value.IsNil()) {
+		if !value.IsValid() || ((value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) && value.IsNil()) {
 			ctx.Buffer().WriteInt8(NullFlag)
 			return
 		}
@@ -174,7 +181,7 @@ func (s *UnionSerializer) WriteData(ctx *WriteContext, value reflect.Value) {
 		return
 	}
 
-	caseID, caseValue, err := getUnionCaseValue(value)
+	caseID, caseValue, err := s.caseValue(value)
 	if err != nil {
 		ctx.SetError(SerializationErrorf("union value access failed: %v", err))
 		return
@@ -271,6 +278,10 @@ func (s *UnionSerializer) ReadData(ctx *ReadContext, value reflect.Value) {
 		caseValue = v.Interface()
 	}
 
+	if isSumTypeInterface(value.Type()) {
+		value.Set(reflect.ValueOf(caseValue))
+		return
+	}
 	setter, setterErr := getUnionSetter(value)
 	if setterErr != nil {
 		ctx.SetError(DeserializationErrorf("union setter access failed: %v", setterErr))
@@ -284,6 +295,30 @@ func (s *UnionSerializer) ReadWithTypeInfo(ctx *ReadContext, refMode RefMode, ty
 	s.Read(ctx, refMode, false, false, value)
 }
 
+// isSumTypeInterface reports whether t is a Go interface used as a union, whose
+// cases are the concrete types stored in it rather than a carrier's fields.
+func isSumTypeInterface(t reflect.Type) bool {
+	return t.Kind() == reflect.Interface && !t.Implements(unionGetterType)
+}
+
+// caseValue returns the case ID and value of a union carrier, or of the case
+// whose type matches the dynamic type of a sum type value.
+func (s *UnionSerializer) caseValue(value reflect.Value) (uint32, any, error) {
+	if !value.IsValid() || !isSumTypeInterface(value.Type()) {
+		return getUnionCaseValue(value)
+	}
+	if value.IsNil() {
+		return 0, nil, fmt.Errorf("nil interface value")
+	}
+	elem := value.Elem()
+	for i := range s.cases {
+		if s.cases[i].type_ == elem.Type() {
+			return s.cases[i].id, elem.Interface(), nil
+		}
+	}
+	return 0, nil, fmt.Errorf("type %v is not a case of union %v", elem.Type(), value.Type())
+}
+
 func getUnionCaseValue(value reflect.Value) (uint32, any, error) {
 	if !value.IsValid() {
 		return 0, nil, fmt.Errorf("invalid reflect.Value")
@@ -0,0 +1,99 @@
+// Licensed to the Apache Software Foundation (ASF) under one
+// or more contributor license agreements.  See the NOTICE file
+// distributed with this work for additional information
+// regarding copyright ownership.  The ASF licenses this file
+// to you under the Apache License, Version 2.0 (the
+// "License"); you may not use this file except in compliance
+// with the License.  You may obtain a copy of the License at
+//
+//   http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing,
+// software distributed under the License is distributed on an
+// "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+// KIND, either express or implied.  See the License for the
+// specific language governing permissions and limitations
+// under the License.
+
+//go:build !fory_codegen_only
+
+package fory
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+type unionShape interface{ area() float64 }
+
+type unionCircle struct{ R float64 }
+
+type unionSquare struct{ Side int32 }
+
+type unionTriangle struct{ Base float64 }
+
+func (c unionCircle) area() float64   { return c.R * c.R * 3 }
+func (s *unionSquare) area() float64  { return float64(s.Side * s.Side) }
+func (t unionTriangle) area() float64 { return t.Base }
+
+type unionDrawing struct {
+	Main     unionShape
+	Optional unionShape `fory:"nullable"`
+}
+
+func newUnionShapeFory(opts ...Option) (*Fory, error) {
+	f := New(opts...)
+	if err := f.RegisterStruct(unionCircle{}, 1); err != nil {
+		return nil, err
+	}
+	if err := f.RegisterStruct(unionSquare{}, 2); err != nil {
+		return nil, err
+	}
+	if err := f.RegisterStruct(unionTriangle{}, 3); err != nil {
+		return nil, err
+	}
+	err := f.RegisterUnion((*unionShape)(nil), 4, NewUnionSerializer(
+		UnionCase{ID: 0, Type: reflect.TypeOf(unionCircle{})},
+		UnionCase{ID: 1, Type: reflect.TypeOf(&unionSquare{})},
+	))
+	if err != nil {
+		return nil, err
+	}
+	return f, f.RegisterStruct(unionDrawing{}, 5)
+}
+
+func TestInterfaceUnionFields(t *testing.T) {
+	for _, opts := range [][]Option{
+		{WithXlang(true)},
+		{WithXlang(true), WithCompatible(false)},
+		{WithXlang(false)},
+		{WithXlang(true), WithTrackRef(true)},
+	} {
+		f, err := newUnionShapeFory(opts...)
+		require.NoError(t, err)
+		for _, value := range []unionDrawing{
+			{Main: unionCircle{R: 2}},
+			{Main: &unionSquare{Side: 3}, Optional: unionCircle{R: 1}},
+		} {
+			data, err := f.Marshal(&value)
+			require.NoError(t, err)
+			var decoded unionDrawing
+			require.NoError(t, f.Unmarshal(data, &decoded))
+			require.Equal(t, value, decoded)
+		}
+	}
+
+	f, err := newUnionShapeFory()
+	require.NoError(t, err)
+	data, err := f.Marshal(&unionDrawing{Main: &unionSquare{Side: 3}})
+	require.NoError(t, err)
+	dump, err := Dump(data)
+	require.NoError(t, err)
+	require.Contains(t, dump, "main: UNION case 1")
+
+	_, err = f.Marshal(&unionDrawing{Main: unionTriangle{Base: 1}})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "is not a case of union")
+}
@@ -18,6 +18,7 @@
 package fory
 
 import (
+	"context"
 	"reflect"
 	"strconv"
 	"unsafe"
@@ -41,7 +42,24 @@ type WriteContext struct {
 	refResolver    *RefResolver            // For reference tracking in native-mode paths
 	bufferCallback func(BufferObject) bool // Callback for out-of-band buffers
 	outOfBand      bool                    // Whether out-of-band serialization is enabled
+	minOutOfBand   int                     // Smallest buffer offered to bufferCallback
 	err            Error                   // Accumulated error state for deferred checking
+	codec          Codec
+	checksum       bool
+	sortMapKeys    bool            // Write map entries in key order (WithSortedMaps)
+	parallel       *parallelWriter // Splits the root collection across workers (MarshalParallel)
+	trackMapKeyRef bool            // Write string and struct map keys as references
+	compactStrings bool            // Pick the shortest string encoding (WithCompactStrings)
+	stringDict     map[string]uint32
+	dictWriter     func(*ByteBuffer, string)
+	frameBody      bool   // Compression or checksum must run after the body is written
+	checksumAt     int    // Buffer offset reserved for the body length and checksum
+	bodyStart      int    // Buffer offset of the body to compress
+	compressed     []byte // Reused compression output
+	// cancel is the context of a MarshalContext call, checked every
+	// cancelCheckInterval units of work.
+	cancel           context.Context
+	untilCancelCheck int
 }
 
 // IsXlang returns whether cross-language serialization mode is enabled
@@ -65,6 +83,7 @@ func (c *WriteContext) Reset() {
 	c.refWriter.Reset()
 	c.depth = 0
 	c.err = Error{} // Clear error state
+	clear(c.stringDict)
 	if c.refResolver != nil {
 		c.refResolver.resetWrite()
 	}
@@ -80,6 +99,7 @@ func (c *WriteContext) ResetState() {
 	c.depth = 0
 	c.bufferCallback = nil
 	c.outOfBand = false
+	clear(c.stringDict)
 	if c.refResolver != nil {
 		c.refResolver.resetWrite()
 	}
@@ -113,6 +133,24 @@ func (c *WriteContext) RefResolver() *RefResolver {
 	return c.refResolver
 }
 
+// Depth returns the current nesting depth, counted by checked serializers.
+func (c *WriteContext) Depth() int {
+	return c.depth
+}
+
+// incDepth increments the nesting depth and checks for overflow
+func (c *WriteContext) incDepth() {
+	c.depth++
+	if c.depth > c.maxDepth {
+		c.SetError(MaxDepthExceededError(c.maxDepth))
+	}
+}
+
+// decDepth decrements the nesting depth
+func (c *WriteContext) decDepth() {
+	c.depth--
+}
+
 // ============================================================================
 // Error State Methods - For deferred error checking pattern
 // ============================================================================
@@ -206,7 +244,7 @@ func (c *WriteContext) writeFast(ptr unsafe.Pointer, ct DispatchId) {
 		// Float16 is uint16 in Go
 		c.buffer.WriteUint16(*(*uint16)(ptr))
 	case StringDispatchId:
-		writeString(c.buffer, *(*string)(ptr))
+		c.WriteString(*(*string)(ptr))
 	}
 }
 
@@ -228,9 +266,29 @@ func (c *WriteContext) WriteLength(length int) {
 
 // WriteString writes a string value (caller handles nullable/type meta)
 func (c *WriteContext) WriteString(value string) {
+	if c.stringDict != nil {
+		c.writeDictString(c.buffer, value)
+		return
+	}
+	if c.compactStrings {
+		writeCompactString(c.buffer, value)
+		return
+	}
 	writeString(c.buffer, value)
 }
 
+// stringWriter returns the string encoder selected by WithCompactStrings and
+// WithStringDictionary
+func (c *WriteContext) stringWriter() func(*ByteBuffer, string) {
+	if c.stringDict != nil {
+		return c.dictWriter
+	}
+	if c.compactStrings {
+		return writeCompactString
+	}
+	return writeString
+}
+
 // WriteBoolSlice writes []bool with ref/type info
 func (c *WriteContext) WriteBoolSlice(value []bool, refMode RefMode, writeTypeInfo bool) {
 	if refMode != RefModeNone {
@@ -243,6 +301,10 @@ func (c *WriteContext) WriteBoolSlice(value []bool, refMode RefMode, writeTypeIn
 	if writeTypeInfo {
 		c.WriteTypeId(BOOL_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteBoolSlice(c.buffer, value)
 }
 
@@ -258,6 +320,10 @@ func (c *WriteContext) WriteInt8Slice(value []int8, refMode RefMode, writeTypeIn
 	if writeTypeInfo {
 		c.WriteTypeId(INT8_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteInt8Slice(c.buffer, value)
 }
 
@@ -273,6 +339,10 @@ func (c *WriteContext) WriteInt16Slice(value []int16, refMode RefMode, writeType
 	if writeTypeInfo {
 		c.WriteTypeId(INT16_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteInt16Slice(c.buffer, value)
 }
 
@@ -288,6 +358,10 @@ func (c *WriteContext) WriteInt32Slice(value []int32, refMode RefMode, writeType
 	if writeTypeInfo {
 		c.WriteTypeId(INT32_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteInt32Slice(c.buffer, value)
 }
 
@@ -303,6 +377,10 @@ func (c *WriteContext) WriteInt64Slice(value []int64, refMode RefMode, writeType
 	if writeTypeInfo {
 		c.WriteTypeId(INT64_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteInt64Slice(c.buffer, value)
 }
 
@@ -318,6 +396,10 @@ func (c *WriteContext) WriteUint16Slice(value []uint16, refMode RefMode, writeTy
 	if writeTypeInfo {
 		c.WriteTypeId(UINT16_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteUint16Slice(c.buffer, value)
 }
 
@@ -333,6 +415,10 @@ func (c *WriteContext) WriteUint32Slice(value []uint32, refMode RefMode, writeTy
 	if writeTypeInfo {
 		c.WriteTypeId(UINT32_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteUint32Slice(c.buffer, value)
 }
 
@@ -348,6 +434,10 @@ func (c *WriteContext) WriteUint64Slice(value []uint64, refMode RefMode, writeTy
 	if writeTypeInfo {
 		c.WriteTypeId(UINT64_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteUint64Slice(c.buffer, value)
 }
 
@@ -367,6 +457,10 @@ func (c *WriteContext) WriteIntSlice(value []int, refMode RefMode, writeTypeInfo
 			c.WriteTypeId(INT32_ARRAY)
 		}
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteIntSlice(c.buffer, value)
 }
 
@@ -386,6 +480,10 @@ func (c *WriteContext) WriteUintSlice(value []uint, refMode RefMode, writeTypeIn
 			c.WriteTypeId(UINT32_ARRAY)
 		}
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteUintSlice(c.buffer, value)
 }
 
@@ -401,6 +499,10 @@ func (c *WriteContext) WriteFloat32Slice(value []float32, refMode RefMode, write
 	if writeTypeInfo {
 		c.WriteTypeId(FLOAT32_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteFloat32Slice(c.buffer, value)
 }
 
@@ -416,6 +518,10 @@ func (c *WriteContext) WriteFloat64Slice(value []float64, refMode RefMode, write
 	if writeTypeInfo {
 		c.WriteTypeId(FLOAT64_ARRAY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	WriteFloat64Slice(c.buffer, value)
 }
 
@@ -431,6 +537,10 @@ func (c *WriteContext) WriteByteSlice(value []byte, refMode RefMode, writeTypeIn
 	if writeTypeInfo {
 		c.WriteTypeId(BINARY)
 	}
+	if c.outOfBand {
+		writeArrayBufferObject(c, value)
+		return
+	}
 	c.buffer.WriteLength(len(value))
 	c.buffer.WriteBinary(value)
 }
@@ -448,7 +558,7 @@ func (c *WriteContext) WriteStringSlice(value []string, refMode RefMode, writeTy
 	if writeTypeInfo {
 		c.WriteTypeId(LIST)
 	}
-	WriteStringSlice(c.buffer, value, hasGenerics)
+	writeStringSlice(c.buffer, value, hasGenerics, c.stringWriter())
 }
 
 // WriteStringStringMap writes map[string]string with ref/type info
@@ -463,7 +573,7 @@ func (c *WriteContext) WriteStringStringMap(value map[string]string, refMode Ref
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringString(c.buffer, value, false)
+	writeMapStringString(c, value, false)
 }
 
 // WriteStringInt64Map writes map[string]int64 with ref/type info
@@ -478,7 +588,7 @@ func (c *WriteContext) WriteStringInt64Map(value map[string]int64, refMode RefMo
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringInt64(c.buffer, value, false)
+	writeMapStringInt64(c, value, false)
 }
 
 // WriteStringInt32Map writes map[string]int32 with ref/type info
@@ -493,7 +603,7 @@ func (c *WriteContext) WriteStringInt32Map(value map[string]int32, refMode RefMo
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringInt32(c.buffer, value, false)
+	writeMapStringInt32(c, value, false)
 }
 
 // WriteStringIntMap writes map[string]int with ref/type info
@@ -508,7 +618,7 @@ func (c *WriteContext) WriteStringIntMap(value map[string]int, refMode RefMode,
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringInt(c.buffer, value, false)
+	writeMapStringInt(c, value, false)
 }
 
 // WriteStringFloat64Map writes map[string]float64 with ref/type info
@@ -523,7 +633,7 @@ func (c *WriteContext) WriteStringFloat64Map(value map[string]float64, refMode R
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringFloat64(c.buffer, value, false)
+	writeMapStringFloat64(c, value, false)
 }
 
 // WriteStringBoolMap writes map[string]bool with ref/type info
@@ -538,7 +648,7 @@ func (c *WriteContext) WriteStringBoolMap(value map[string]bool, refMode RefMode
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapStringBool(c.buffer, value, false)
+	writeMapStringBool(c, value, false)
 }
 
 // WriteInt32Int32Map writes map[int32]int32 with ref/type info
@@ -553,7 +663,7 @@ func (c *WriteContext) WriteInt32Int32Map(value map[int32]int32, refMode RefMode
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapInt32Int32(c.buffer, value, false)
+	writeMapInt32Int32(c, value, false)
 }
 
 // WriteInt64Int64Map writes map[int64]int64 with ref/type info
@@ -568,7 +678,7 @@ func (c *WriteContext) WriteInt64Int64Map(value map[int64]int64, refMode RefMode
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapInt64Int64(c.buffer, value, false)
+	writeMapInt64Int64(c, value, false)
 }
 
 // WriteIntIntMap writes map[int]int with ref/type info
@@ -583,7 +693,7 @@ func (c *WriteContext) WriteIntIntMap(value map[int]int, refMode RefMode, writeT
 	if writeTypeInfo {
 		c.WriteTypeId(MAP)
 	}
-	writeMapIntInt(c.buffer, value, false)
+	writeMapIntInt(c, value, false)
 }
 
 // WriteBufferObject writes a buffer object
@@ -591,7 +701,7 @@ func (c *WriteContext) WriteIntIntMap(value map[int]int, refMode RefMode, writeT
 func (c *WriteContext) WriteBufferObject(bufferObject BufferObject) {
 	// Check if we should write this buffer out-of-band
 	inBand := true
-	if c.bufferCallback != nil {
+	if c.bufferCallback != nil && bufferObject.TotalBytes() >= c.minOutOfBand {
 		inBand = c.bufferCallback(bufferObject)
 	}
 
@@ -642,7 +752,7 @@ func (c *WriteContext) WriteValue(value reflect.Value, refMode RefMode, writeTyp
 	}
 
 	// For array types, pre-convert the value to slice
-	if value.Kind() == reflect.Array {
+	if value.Kind() == reflect.Array && !c.typeResolver.isExtensionType(value.Type()) {
 		length := value.Len()
 		sliceType := reflect.SliceOf(value.Type().Elem())
 		slice := reflect.MakeSlice(sliceType, length, length)
@@ -0,0 +1,450 @@
+/*
+ * Licensed to the Apache Software Foundation (ASF) under one
+ * or more contributor license agreements.  See the NOTICE file
+ * distributed with this work for additional information
+ * regarding copyright ownership.  The ASF licenses this file
+ * to you under the Apache License, Version 2.0 (the
+ * "License"); you may not use this file except in compliance
+ * with the License.  You may obtain a copy of the License at
+ *
+ *   http://www.apache.org/licenses/LICENSE-2.0
+ *
+ * Unless required by applicable law or agreed to in writing,
+ * software distributed under the License is distributed on an
+ * "AS IS" BASIS, WITHOUT WARRANTIES OR CONDITIONS OF ANY
+ * KIND, either express or implied.  See the License for the
+ * specific language governing permissions and limitations
+ * under the License.
+ */
+
+package org.apache.fory.xlang;
+
+import com.google.common.collect.ImmutableMap;
+import java.io.File;
+import java.io.IOException;
+import java.nio.file.Files;
+import java.nio.file.Path;
+import java.util.ArrayList;
+import java.util.Arrays;
+import java.util.Collections;
+import java.util.List;
+import java.util.function.Consumer;
+import java.util.function.Supplier;
+import lombok.Data;
+import org.apache.fory.Fory;
+import org.apache.fory.annotation.ForyField;
+import org.apache.fory.annotation.ForyStruct;
+import org.apache.fory.annotation.Nullable;
+import org.apache.fory.annotation.Ref;
+import org.apache.fory.memory.MemoryBuffer;
+import org.apache.fory.memory.MemoryUtils;
+import org.apache.fory.serializer.BufferObject;
+import org.apache.fory.test.TestUtils;
+import org.testng.Assert;
+import org.testng.SkipException;
+import org.testng.annotations.BeforeClass;
+import org.testng.annotations.Test;
+
+/**
+ * Checks the golden-file corpus in go/fory/tests/golden against Java: decodes every payload Go
+ * committed under testdata/go, then writes the same cases from Java and has the Go test
+ * TestJavaFixtures decode them. Case names, registrations and the {@code <case>.oob} buffer layout
+ * mirror go/fory/tests/golden/corpus.go.
+ *
+ * <p>The evolution cases are written with {@link Event} and read with one of its variants
+ * registered under the same name. Java does not widen field types across schemas, so it reads
+ * evolution_field_widened with {@link Event}; Go reads the Java payload of that case as int64.
+ */
+@Test
+public class GoGoldenCorpusTest {
+  private static final File GO_MODULE_DIR = new File("../../go/fory");
+  private static final Path GO_FIXTURE_DIR =
+      GO_MODULE_DIR.toPath().resolve("tests/golden/testdata/go");
+
+  enum Color {
+    Green,
+    Red,
+    Blue,
+    White,
+  }
+
+  @Data
+  @ForyStruct
+  static class Item {
+    String name;
+    int count;
+    List<String> tags;
+  }
+
+  @Data
+  @ForyStruct
+  static class NamedItem {
+    long id;
+    String label;
+    double score;
+  }
+
+  @Data
+  @ForyStruct
+  static class Palette {
+    Color primary;
+    List<Color> colors;
+  }
+
+  @Data
+  @ForyStruct
+  static class RefPair {
+    @Nullable
+    @ForyField(dynamic = ForyField.Dynamic.FALSE)
+    @Ref
+    Item first;
+
+    @Nullable
+    @ForyField(dynamic = ForyField.Dynamic.FALSE)
+    @Ref
+    Item second;
+  }
+
+  @Data
+  @ForyStruct
+  static class Samples {
+    String name;
+    double[] values;
+    int[] ids;
+  }
+
+  @Data
+  @ForyStruct
+  static class Event {
+    long id;
+    String name;
+    int count;
+    String legacy;
+  }
+
+  @Data
+  @ForyStruct
+  static class EventAdded {
+    long id;
+    String name;
+    int count;
+    String legacy;
+    String source;
+  }
+
+  @Data
+  @ForyStruct
+  static class EventRemoved {
+    long id;
+    String name;
+    int count;
+  }
+
+  @Data
+  @ForyStruct
+  static class EventReordered {
+    String legacy;
+    int count;
+    String name;
+    long id;
+  }
+
+  /** One corpus entry; see Case in corpus.go. */
+  static class GoldenCase {
+    final String name;
+    final boolean compatible;
+    final boolean trackRef;
+    final boolean outOfBand;
+    final Supplier<Object> value;
+    final Consumer<Object> check;
+    // Registered as "golden.Event" when reading; the writer always registers Event.
+    final Class<?> readerType;
+
+    GoldenCase(
+        String name,
+        boolean compatible,
+        boolean trackRef,
+        boolean outOfBand,
+        Supplier<Object> value,
+        Consumer<Object> check) {
+      this(name, compatible, trackRef, outOfBand, value, check, Event.class);
+    }
+
+    GoldenCase(
+        String name,
+        boolean compatible,
+        boolean trackRef,
+        boolean outOfBand,
+        Supplier<Object> value,
+        Consumer<Object> check,
+        Class<?> readerType) {
+      this.name = name;
+      this.compatible = compatible;
+      this.trackRef = trackRef;
+      this.outOfBand = outOfBand;
+      this.value = value;
+      this.check = check;
+      this.readerType = readerType;
+    }
+
+    Fory newWriter() {
+      return newFory(Event.class);
+    }
+
+    Fory newReader() {
+      return newFory(readerType);
+    }
+
+    private Fory newFory(Class<?> event) {
+      Fory fory =
+          Fory.builder()
+              .withXlang(true)
+              .withCompatible(compatible)
+              .withRefTracking(trackRef)
+              .build();
+      fory.register(Item.class, 101);
+      fory.register(Color.class, 102);
+      fory.register(Palette.class, 103);
+      fory.register(RefPair.class, 104);
+      fory.register(Samples.class, 105);
+      fory.register(NamedItem.class, "golden", "NamedItem");
+      fory.register(event, "golden", "Event");
+      return fory;
+    }
+  }
+
+  private static Item newItem() {
+    Item item = new Item();
+    item.name = "apple";
+    item.count = 42;
+    item.tags = Arrays.asList("red", "fruit");
+    return item;
+  }
+
+  private static Samples newSamples() {
+    Samples samples = new Samples();
+    samples.name = "probe";
+    samples.values = new double[] {0.25, -1, 1e9};
+    samples.ids = new int[] {3, 1, 4, 1, 5};
+    return samples;
+  }
+
+  private static Event newEvent() {
+    Event event = new Event();
+    event.id = 9;
+    event.name = "deploy";
+    event.count = -3;
+    event.legacy = "v1";
+    return event;
+  }
+
+  private static GoldenCase equalCase(
+      String name, boolean compatible, boolean trackRef, Supplier<Object> value) {
+    return new GoldenCase(
+        name, compatible, trackRef, false, value, v -> Assert.assertEquals(v, value.get()));
+  }
+
+  static List<GoldenCase> cases() {
+    List<GoldenCase> cases = new ArrayList<>();
+    cases.add(equalCase("string", true, false, () -> "hello, 世界"));
+    cases.add(
+        new GoldenCase(
+            "int64_list",
+            true,
+            false,
+            false,
+            () -> new long[] {-1, 0, 1, 1L << 40},
+            v -> Assert.assertEquals((long[]) v, new long[] {-1, 0, 1, 1L << 40})));
+    cases.add(equalCase("struct_schema_consistent", false, false, GoGoldenCorpusTest::newItem));
+    cases.add(equalCase("struct_compatible", true, false, GoGoldenCorpusTest::newItem));
+    cases.add(
+        equalCase(
+            "named_struct_compatible",
+            true,
+            false,
+            () -> {
+              NamedItem item = new NamedItem();
+              item.id = 7;
+              item.label = "seven";
+              item.score = 0.5;
+              return item;
+            }));
+    cases.add(equalCase("enum", true, false, () -> Color.Blue));
+    cases.add(
+        equalCase(
+            "enum_struct",
+            true,
+            false,
+            () -> {
+              Palette palette = new Palette();
+              palette.primary = Color.Red;
+              palette.colors = Arrays.asList(Color.Green, Color.White);
+              return palette;
+            }));
+    cases.add(
+        new GoldenCase(
+            "shared_ref",
+            true,
+            true,
+            false,
+            () -> {
+              RefPair pair = new RefPair();
+              pair.first = newItem();
+              pair.second = pair.first;
+              return pair;
+            },
+            v -> {
+              RefPair pair = (RefPair) v;
+              Assert.assertSame(pair.first, pair.second);
+              Assert.assertEquals(pair.first, newItem());
+            }));
+    cases.add(
+        new GoldenCase(
+            "oob_float64_array",
+            true,
+            false,
+            true,
+            () -> newSamples().values,
+            v -> Assert.assertEquals((double[]) v, newSamples().values)));
+    cases.add(
+        new GoldenCase(
+            "oob_struct",
+            true,
+            false,
+            true,
+            GoGoldenCorpusTest::newSamples,
+            v -> Assert.assertEquals(v, newSamples())));
+    cases.add(
+        new GoldenCase(
+            "evolution_field_added",
+            true,
+            false,
+            false,
+            GoGoldenCorpusTest::newEvent,
+            v -> {
+              EventAdded e = (EventAdded) v;
+              Assert.assertNull(e.source);
+              Event want = newEvent();
+              Assert.assertEquals(e.id, want.id);
+              Assert.assertEquals(e.name, want.name);
+              Assert.assertEquals(e.count, want.count);
+              Assert.assertEquals(e.legacy, want.legacy);
+            },
+            EventAdded.class));
+    cases.add(
+        new GoldenCase(
+            "evolution_field_removed",
+            true,
+            false,
+            false,
+            GoGoldenCorpusTest::newEvent,
+            v -> {
+              EventRemoved e = (EventRemoved) v;
+              Event want = newEvent();
+              Assert.assertEquals(e.id, want.id);
+              Assert.assertEquals(e.name, want.name);
+              Assert.assertEquals(e.count, want.count);
+            },
+            EventRemoved.class));
+    cases.add(equalCase("evolution_field_widened", true, false, GoGoldenCorpusTest::newEvent));
+    cases.add(
+        new GoldenCase(
+            "evolution_fields_reordered",
+            true,
+            false,
+            false,
+            GoGoldenCorpusTest::newEvent,
+            v -> {
+              EventReordered e = (EventReordered) v;
+              Event want = newEvent();
+              Assert.assertEquals(e.id, want.id);
+              Assert.assertEquals(e.name, want.name);
+              Assert.assertEquals(e.count, want.count);
+              Assert.assertEquals(e.legacy, want.legacy);
+            },
+            EventReordered.class));
+    return cases;
+  }
+
+  @BeforeClass
+  public void ensureGoReady() {
+    if (!"1".equals(System.getenv("FORY_GO_JAVA_CI"))) {
+      throw new SkipException("Skipping GoGoldenCorpusTest: FORY_GO_JAVA_CI not set to 1");
+    }
+    if (!TestUtils.executeCommand(
+        Arrays.asList("go", "version"), 30, Collections.emptyMap(), GO_MODULE_DIR)) {
+      throw new SkipException("Skipping GoGoldenCorpusTest: go not installed");
+    }
+  }
+
+  @Test
+  public void testReadGoFixtures() throws IOException {
+    for (GoldenCase c : cases()) {
+      byte[] data = Files.readAllBytes(GO_FIXTURE_DIR.resolve(c.name + ".bin"));
+      Object value;
+      if (c.outOfBand) {
+        List<MemoryBuffer> buffers = readBuffers(GO_FIXTURE_DIR.resolve(c.name + ".oob"));
+        value = c.newReader().deserialize(data, buffers);
+      } else {
+        value = c.newReader().deserialize(data);
+      }
+      c.check.accept(value);
+    }
+  }
+
+  @Test
+  public void testGoReadsJavaFixtures() throws IOException {
+    Path dir = Files.createTempDirectory("fory_golden_java");
+    for (GoldenCase c : cases()) {
+      Fory fory = c.newWriter();
+      if (c.outOfBand) {
+        List<BufferObject> bufferObjects = new ArrayList<>();
+        byte[] data =
+            fory.serialize(
+                c.value.get(),
+                o -> {
+                  bufferObjects.add(o);
+                  return false;
+                });
+        Assert.assertFalse(bufferObjects.isEmpty(), c.name);
+        Files.write(dir.resolve(c.name + ".bin"), data);
+        writeBuffers(dir.resolve(c.name + ".oob"), bufferObjects);
+      } else {
+        Files.write(dir.resolve(c.name + ".bin"), fory.serialize(c.value.get()));
+      }
+    }
+    boolean passed =
+        TestUtils.executeCommand(
+            Arrays.asList(
+                "go", "test", "-count=1", "-v", "./tests/golden", "-run", "TestJavaFixtures"),
+            300,
+            ImmutableMap.of("FORY_JAVA_GOLDEN_DIR", dir.toAbsolutePath().toString()),
+            GO_MODULE_DIR);
+    Assert.assertTrue(passed, "Go failed to decode the Java golden files in " + dir);
+  }
+
+  /** Writes buffers as an int32 count followed by an int32 length and the bytes of each. */
+  private static void writeBuffers(Path path, List<BufferObject> bufferObjects)
+      throws IOException {
+    MemoryBuffer out = MemoryBuffer.newHeapBuffer(64);
+    out.writeInt32(bufferObjects.size());
+    for (BufferObject bufferObject : bufferObjects) {
+      out.writeInt32(bufferObject.totalBytes());
+      bufferObject.writeTo(out);
+    }
+    Files.write(path, out.getBytes(0, out.writerIndex()));
+  }
+
+  private static List<MemoryBuffer> readBuffers(Path path) throws IOException {
+    MemoryBuffer in = MemoryUtils.wrap(Files.readAllBytes(path));
+    int numBuffers = in.readInt32();
+    List<MemoryBuffer> buffers = new ArrayList<>(numBuffers);
+    for (int i = 0; i < numBuffers; i++) {
+      int len = in.readInt32();
+      int readerIndex = in.readerIndex();
+      buffers.add(in.slice(readerIndex, len));
+      in.readerIndex(readerIndex + len);
+    }
+    return buffers;
+  }
+}